@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// FollowAction 关注事件的类型
+type FollowAction string
+
+const (
+	FollowActionFollow   FollowAction = "follow"
+	FollowActionUnfollow FollowAction = "unfollow"
+	FollowActionMute     FollowAction = "mute"
+	FollowActionUnmute   FollowAction = "unmute"
+)
+
+// FollowEventRequest 关注事件（社交服务的消息 → 归因、本地关注关系）
+type FollowEventRequest struct {
+	FollowerID  int64        // 发起关注的用户
+	FollowingID int64        // 被关注的用户
+	Action      FollowAction // 事件类型（归因只处理 follow，为空时按 follow 处理）
+	Source      string       // 关注入口（推荐卡片上的关注为 recommendation），可以为空
+	OccurredAt  time.Time    // 零值表示当前时间
+}
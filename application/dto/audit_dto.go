@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// AuditRecord 一次管理操作（管理接口 → 应用层）
+//
+// Before / After 可以是任意可以序列化为 JSON 的值（如权重配置、精选包内容），
+// 应用层统一序列化后保存。
+type AuditRecord struct {
+	Actor     string // 操作人（管理员账号）
+	Action    string // 操作类型（如 scoring_weight.change）
+	Target    string // 操作对象
+	Before    any    // 修改前的值（新建时为 nil）
+	After     any    // 修改后的值（删除时为 nil）
+	RequestID string // 请求ID
+}
+
+// AuditLogQuery 审计日志查询条件（零值字段表示不过滤）
+type AuditLogQuery struct {
+	Actor  string
+	Action string
+	Target string
+	Since  time.Time
+	Until  time.Time
+	Limit  int // 默认 100，最多 1000
+}
+
+// AuditEntryDTO 审计记录DTO
+type AuditEntryDTO struct {
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	Target     string `json:"target"`
+	Before     string `json:"before"` // JSON
+	After      string `json:"after"`  // JSON
+	RequestID  string `json:"request_id"`
+	OccurredAt string `json:"occurred_at"` // RFC3339
+}
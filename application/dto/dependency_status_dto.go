@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// 健康检查结果
+const (
+	HealthOK        = "ok"        // 最近一次检查成功
+	HealthFailing   = "failing"   // 最近一次检查失败（见 LastError）
+	HealthUnchecked = "unchecked" // 没有健康检查（mock、内存实现，或者未使用的可选依赖）
+)
+
+// CircuitBreakerNone 依赖没有配置熔断器
+const CircuitBreakerNone = "none"
+
+// DependencyStatus 一个已装配依赖的状态（诊断接口）
+type DependencyStatus struct {
+	Name           string        // 端口名，如 social_graph
+	Type           string        // 端口类型，如 SocialGraphRepository
+	Implementation string        // 实现，如 mysql、http、mock
+	Target         string        // 目标地址（主机、URL，不含账号密码）
+	Health         string        // HealthOK / HealthFailing / HealthUnchecked
+	LastError      string        // 最近一次检查失败的原因
+	CheckedAt      time.Time     // 最近一次检查的时间（HealthUnchecked 时为零值）
+	Latency        time.Duration // 最近一次检查的耗时
+	CircuitBreaker string        // 熔断器状态（closed、open、half_open），没有熔断器时为 CircuitBreakerNone
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// FeedbackStatsQuery 推荐效果统计查询条件（管理接口 → 应用层）
+type FeedbackStatsQuery struct {
+	From time.Time // 起始时间（包含），零值表示 To 之前 7 天
+	To   time.Time // 结束时间（不包含），零值表示当前时间
+}
+
+// FeedbackStatsDTO 一天、一种策略、一种推荐理由、一个展示场景的推荐效果
+type FeedbackStatsDTO struct {
+	Day            string  `json:"day"` // 2006-01-02
+	Strategy       string  `json:"strategy"`
+	ReasonType     string  `json:"reason_type"`
+	Surface        string  `json:"surface"`
+	Impressions    int64   `json:"impressions"`
+	Conversions    int64   `json:"conversions"`     // 归因窗口内关注了被推荐用户的曝光次数
+	ConversionRate float64 `json:"conversion_rate"` // Conversions / Impressions
+}
+
+// StrategyStatsDTO 一种策略在一段时间内的推荐效果（基于归因转化记录）
+type StrategyStatsDTO struct {
+	Strategy         string  `json:"strategy"`
+	Impressions      int64   `json:"impressions"`
+	Clicks           int64   `json:"clicks"`             // 直接在推荐卡片上关注的次数
+	Conversions      int64   `json:"conversions"`        // 归因到曝光的关注次数（包含 Clicks）
+	ClickThroughRate float64 `json:"click_through_rate"` // Clicks / Impressions
+	ConversionRate   float64 `json:"conversion_rate"`    // Conversions / Impressions
+}
+
+// InterleavingStatsDTO 一个交错实验在一段时间内的结果
+type InterleavingStatsDTO struct {
+	Experiment string  `json:"experiment"`
+	Sessions   int64   `json:"sessions"` // 交错列表数
+	WinsA      int64   `json:"wins_a"`
+	WinsB      int64   `json:"wins_b"`
+	Ties       int64   `json:"ties"`
+	WinRateA   float64 `json:"win_rate_a"` // WinsA / (WinsA + WinsB + Ties)
+	WinRateB   float64 `json:"win_rate_b"` // WinsB / (WinsA + WinsB + Ties)
+}
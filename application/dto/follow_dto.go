@@ -0,0 +1,14 @@
+package dto
+
+// FollowUserRequest 用户主动关注（RPC FollowUser → FollowService.FollowUser）
+type FollowUserRequest struct {
+	FollowerID  int64  // 发起关注的用户
+	FollowingID int64  // 被关注的用户
+	Source      string // 关注入口（推荐卡片上的关注为 recommendation，立即归因到推荐曝光），可以为空
+}
+
+// UnfollowUserRequest 用户主动取关（RPC UnfollowUser → FollowService.UnfollowUser）
+type UnfollowUserRequest struct {
+	FollowerID  int64 // 发起取关的用户
+	FollowingID int64 // 被取关的用户
+}
@@ -0,0 +1,23 @@
+package dto
+
+// GroupRecommendationRequest 小组推荐请求（接口层 → 应用层）
+type GroupRecommendationRequest struct {
+	UserID int64 // 为哪个用户推荐
+	Limit  int   // 返回数量（为 0 时使用默认值）
+}
+
+// GroupRecommendationResponse 小组推荐响应
+type GroupRecommendationResponse struct {
+	Groups []*GroupRecommendationDTO `json:"groups"`
+}
+
+// GroupRecommendationDTO 小组推荐DTO
+//
+// 只有小组ID：小组名称、封面等展示数据由客户端向社区服务获取。
+type GroupRecommendationDTO struct {
+	RecommendationID string `json:"recommendation_id"`
+	GroupID          int64  `json:"group_id"`
+	MemberCount      int    `json:"member_count"` // 成员总数（未知时为 0）
+	Reason           string `json:"reason"`       // "3 位你关注的人是小组成员"
+	Score            int    `json:"score"`        // 推荐分数
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// PostRecommendationRequest 帖子推荐请求（接口层 → 应用层）
+type PostRecommendationRequest struct {
+	UserID  int64   // 为哪个用户推荐
+	Limit   int     // 返回数量（为 0 时使用默认值）
+	Surface Surface // 展示场景（为空时按探索页处理）
+}
+
+// PostRecommendationResponse 帖子推荐响应
+type PostRecommendationResponse struct {
+	Posts []*PostRecommendationDTO `json:"posts"`
+}
+
+// PostRecommendationDTO 帖子推荐DTO
+type PostRecommendationDTO struct {
+	RecommendationID string    `json:"recommendation_id"`
+	PostID           int64     `json:"post_id"`
+	AuthorID         int64     `json:"author_id"`
+	AuthorUsername   string    `json:"author_username"`
+	AuthorAvatar     string    `json:"author_avatar"`
+	Content          string    `json:"content"`
+	CreatedAt        time.Time `json:"created_at"` // 发布时间
+	Masked           bool      `json:"masked"`     // 内容被遮挡（客户端展示为"敏感内容"占位）
+	Reason           string    `json:"reason"`     // "3 位你关注的人也关注了作者"
+	Score            int       `json:"score"`      // 推荐分数
+}
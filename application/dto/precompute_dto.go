@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// PrecomputeReport 一次预计算的执行报告
+type PrecomputeReport struct {
+	RanAt   time.Time                 `json:"ran_at"`
+	Tenants []*PrecomputeTenantReport `json:"tenants"`
+}
+
+// PrecomputeTenantReport 一个租户的执行结果
+type PrecomputeTenantReport struct {
+	Tenant      string        `json:"tenant"`
+	ActiveUsers int           `json:"active_users"` // 活跃窗口内的用户数（不超过每轮上限）
+	Generated   int           `json:"generated"`    // 重新生成并保存了推荐列表的用户数
+	Fresh       int           `json:"fresh"`        // 已有足够新的列表（之前的预计算或者在线请求生成的），跳过
+	Erased      int           `json:"erased"`       // 已删除数据的用户，跳过
+	Failed      int           `json:"failed"`       // 生成或保存失败的用户数（下一轮重试）
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"` // 查询活跃用户失败（这个租户本轮没有执行）
+}
@@ -0,0 +1,20 @@
+package dto
+
+// RecommendationBundleRequest 分栏推荐请求（发现页）
+type RecommendationBundleRequest struct {
+	UserID int64 // 为哪个用户推荐
+}
+
+// RecommendationBundleResponse 分栏推荐响应
+type RecommendationBundleResponse struct {
+	Sections []*RecommendationSectionDTO `json:"sections"` // 按配置的顺序，没有推荐的分栏不返回
+	// Degraded 降级响应：部分推荐因为超时没有返回（客户端可以稍后重新拉取）
+	Degraded bool `json:"degraded"`
+}
+
+// RecommendationSectionDTO 一个分栏（如"你可能认识的人"）
+type RecommendationSectionDTO struct {
+	Key             string                   `json:"key"`   // 分栏标识（如 people_you_may_know），客户端按它埋点
+	Title           string                   `json:"title"` // 分栏标题（来自配置服务）
+	Recommendations []*UserRecommendationDTO `json:"recommendations"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // DTO（数据传输对象 - Data Transfer Object）
 //
 // 什么是 DTO？
@@ -55,9 +57,144 @@ package dto
 // - 对外 API：必须使用，保护内部实现
 // - 内部服务：可以考虑直接用领域对象（如果信任内部调用）
 
+// Surface 推荐展示场景（推荐模块出现在 App 的哪个位置）
+//
+// 不同场景对推荐有不同的要求，例如：
+// - 首页信息流：可以展示敏感内容的遮挡预览
+// - 新用户引导：面向新用户，内容安全要求更严格
+type Surface string
+
+const (
+	SurfaceDefault        Surface = ""                // 未指定场景
+	SurfaceHomeFeed       Surface = "home_feed"       // 首页信息流
+	SurfaceProfileSidebar Surface = "profile_sidebar" // 个人主页侧边栏
+	SurfaceOnboarding     Surface = "onboarding"      // 新用户引导
+	SurfacePush           Surface = "push"            // 推送
+	SurfaceExplore        Surface = "explore"         // 探索页（帖子推荐）
+	SurfaceDiscovery      Surface = "discovery"       // 发现页（分栏展示的推荐）
+)
+
+// RecommendationRequest 推荐请求（接口层 → 应用层）
+//
+// 为什么用请求对象而不是一串参数？
+// 推荐请求的选项会越来越多（场景、时间范围等），
+// 用请求对象可以新增字段而不修改方法签名。
+type RecommendationRequest struct {
+	UserID  int64   // 为哪个用户推荐
+	Limit   int     // 返回数量
+	Surface Surface // 展示场景（可选）
+
+	// KnownVersion 客户端缓存的列表版本（可选）：和当前版本相同时只返回 NotModified
+	KnownVersion string
+
+	// 增量同步（可选，二选一，SinceVersion 优先）：只返回上次同步之后新增、变化的推荐和墓碑
+	SinceVersion   string    // 上次同步拿到的列表版本
+	SinceTimestamp time.Time // 上次同步的时间（客户端没有保存版本时使用）
+
+	// Freshness 对新鲜度的要求（由接口层按用户优先级决定，为空时等同 FreshnessDefault）
+	Freshness Freshness
+
+	// SessionID 客户端会话ID（可选）：同一个会话内不再返回已经返回过的推荐对象
+	SessionID string
+
+	// Days 推荐信号的时间窗口（最近几天，可选）：为 0 时使用展示场景的默认值
+	Days int
+
+	// Filter 调用方指定的过滤条件（可选）
+	Filter *RecommendationFilter
+
+	// 分页（可选）：PageSize 大于 0 或者带 PageToken 时按页返回，PageSize 代替 Limit
+	PageToken string // 上一页响应的 NextCursor，为空时返回第一页
+	PageSize  int    // 每页数量
+
+	// Debug 调试请求（质量评审、数据分析）：每条推荐带上分数明细（ScoreDetails）
+	// 快照中的推荐没有打分特征，调试请求总是重新生成（只接受快照的请求除外，此时没有明细）
+	Debug bool
+}
+
+// RecommendationFilter 请求级别的过滤条件（各条件同时满足才返回）
+//
+// 和自定义时间窗口一样，过滤后的列表和场景的列表不同：不使用快照，也不做增量同步。
+type RecommendationFilter struct {
+	MinScore       int      // 最低分数（0 表示不限制）
+	ReasonTypes    []string // 只返回这些理由类型（如 followed_by_following，为空表示不限制）
+	ExcludeUserIDs []int64  // 不推荐这些用户
+}
+
+// IsEmpty 是否没有任何过滤条件（nil 也是空的）
+func (f *RecommendationFilter) IsEmpty() bool {
+	return f == nil || (f.MinScore <= 0 && len(f.ReasonTypes) == 0 && len(f.ExcludeUserIDs) == 0)
+}
+
+// Freshness 推荐列表的新鲜度要求
+//
+// 生成推荐（召回、排序）是整个请求中最贵的部分；
+// 最近生成过的列表保存在快照中（见 RecommendationSnapshotStore），可以直接拿来用。
+type Freshness string
+
+const (
+	// FreshnessDefault 快照没有超过过期阈值时直接使用，否则重新生成
+	FreshnessDefault Freshness = ""
+	// FreshnessRealtime 总是重新生成（高优先级用户）
+	FreshnessRealtime Freshness = "realtime"
+	// FreshnessCachedOnly 只使用快照（不管是否超过阈值），没有快照时返回 ErrNoPrecomputedRecommendations（过载时的低优先级请求）
+	FreshnessCachedOnly Freshness = "cached_only"
+)
+
+// Paginated 请求是否按页返回
+func (r *RecommendationRequest) Paginated() bool {
+	return r.PageSize > 0 || r.PageToken != ""
+}
+
+// WantsDelta 请求是否要求增量同步
+func (r *RecommendationRequest) WantsDelta() bool {
+	return r.SinceVersion != "" || !r.SinceTimestamp.IsZero()
+}
+
 // RecommendationResponse 推荐响应
 type RecommendationResponse struct {
 	Recommendations []*UserRecommendationDTO `json:"recommendations"`
+	// Degraded 降级响应：部分推荐因为超时没有返回（客户端可以稍后重新拉取）
+	Degraded bool `json:"degraded"`
+	// Metadata 生成过程的元数据（用于评估推荐质量）
+	Metadata *ResponseMetadata `json:"metadata,omitempty"`
+	// Version 列表版本（相当于 HTTP 的 ETag），降级响应为空（不完整的列表不应该被缓存）
+	Version string `json:"version,omitempty"`
+	// NotModified 列表和请求的 KnownVersion 相同：Recommendations 为空，客户端继续使用缓存
+	NotModified bool `json:"not_modified,omitempty"`
+	// Delta 增量响应：Recommendations 只包含新增、变化的推荐，Tombstones 是需要删除的推荐；
+	// 为 false 时 Recommendations 是完整列表（基准快照找不到时也会返回完整列表），客户端整体替换
+	Delta      bool            `json:"delta,omitempty"`
+	Tombstones []*TombstoneDTO `json:"tombstones,omitempty"`
+	// Precomputed 列表来自快照，没有重新生成（可能不包含最新的关注变化）
+	Precomputed bool `json:"precomputed,omitempty"`
+	// 分页响应：NextCursor 是下一页的 PageToken（没有更多时为空），分页响应不带 Version
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// 墓碑原因
+const (
+	TombstoneRemoved = "removed" // 不再被推荐（如已经关注、被过滤、排名下降到这一页之外）
+	TombstoneExpired = "expired" // 推荐已经过期
+)
+
+// TombstoneDTO 墓碑：客户端缓存中需要删除的推荐
+type TombstoneDTO struct {
+	UserID int64  `json:"user_id"`
+	Reason string `json:"reason"` // removed / expired
+}
+
+// ResponseMetadata 推荐生成过程的元数据
+type ResponseMetadata struct {
+	FollowingsSampled bool `json:"followings_sampled"` // 是否对关注列表做了采样
+	FollowingsTotal   int  `json:"followings_total"`   // 关注的人总数（开启采样时才统计）
+	FollowingsUsed    int  `json:"followings_used"`    // 实际用作推荐信号来源的人数
+	// CalibrationVersion 分数校准参数的版本（没有开启校准时为空）
+	CalibrationVersion string `json:"calibration_version,omitempty"`
+	// Experiment、Variant 用户所在的 A/B 实验和分组（不在实验中时为空），客户端埋点时带上
+	Experiment string `json:"experiment,omitempty"`
+	Variant    string `json:"variant,omitempty"`
 }
 
 // UserRecommendationDTO 用户推荐DTO
@@ -67,13 +204,55 @@ type UserRecommendationDTO struct {
 	Avatar      string     `json:"avatar"`
 	Bio         string     `json:"bio"`
 	Reason      string     `json:"reason"`       // "3 位你关注的人也关注了TA"
-	Score       int        `json:"score"`        // 推荐分数
+	ReasonType  string     `json:"reason_type"`  // 推荐理由类型（如 followed_by_following；backfill 表示补足一页的热门用户）
+	Score       int        `json:"score"`        // 推荐分数（原始分数，只在同一个列表内可比较）
 	RecentPosts []*PostDTO `json:"recent_posts"` // 最近的帖子
+	// CalibratedScore 校准后的分数（0~1，不同策略、不同时间之间可比较），没有校准时为 nil
+	CalibratedScore *float64 `json:"calibrated_score,omitempty"`
+	// ProfilePending 资料没有取到，Username、Avatar、Bio 为空（客户端应按 UserID 刷新资料）
+	ProfilePending bool `json:"profile_pending,omitempty"`
+	// Degraded 补全时降级的部分（DegradedProfile、DegradedPosts），为空表示完整
+	Degraded []string `json:"degraded,omitempty"`
+	// ScoreDetails 分数明细（只有调试请求才有）
+	ScoreDetails *ScoreDetailsDTO `json:"score_details,omitempty"`
+}
+
+// ScoreDetailsDTO 一条推荐的分数明细：各因素的贡献之和等于 Score
+type ScoreDetailsDTO struct {
+	Factors              []*ScoreFactorDTO `json:"factors"`
+	RulesVersion         string            `json:"rules_version"`
+	PerRelatedUser       int               `json:"per_related_user"`
+	PerRecentPost        int               `json:"per_recent_post"`
+	PerHundredEngagement int               `json:"per_hundred_engagement"`
+}
+
+// 一条推荐补全时可能降级的部分
+const (
+	DegradedProfile = "profile" // 资料是占位的
+	DegradedPosts   = "posts"   // 帖子没有取到（RecentPosts 为空不代表用户没有发帖）
+)
+
+// ItemStatus 批量接口、补全结果中一项的状态
+type ItemStatus string
+
+const (
+	ItemStatusOK       ItemStatus = "ok"       // 完整
+	ItemStatusDegraded ItemStatus = "degraded" // 可以使用，但有部分数据缺失
+	ItemStatusError    ItemStatus = "error"    // 失败（带错误码，调用方可以只重试这一项）
+)
+
+// Status 一条推荐的补全状态
+func (r *UserRecommendationDTO) Status() ItemStatus {
+	if len(r.Degraded) > 0 {
+		return ItemStatusDegraded
+	}
+	return ItemStatusOK
 }
 
 // PostDTO 帖子DTO
 type PostDTO struct {
-	PostID    int64  `json:"post_id"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"` // 格式化后的时间字符串
+	PostID    int64     `json:"post_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"` // 发布时间（格式化是接口层的事：RPC 协议、客户端时区）
+	Masked    bool      `json:"masked"`     // 内容被遮挡（客户端展示为"敏感内容"占位）
 }
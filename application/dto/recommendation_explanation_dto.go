@@ -0,0 +1,49 @@
+package dto
+
+// ExplainRecommendationRequest 解释一条推荐（质量评审工具 → 应用层）
+type ExplainRecommendationRequest struct {
+	ViewerID     int64   // 看到推荐的用户
+	TargetUserID int64   // 被推荐的用户
+	Surface      Surface // 展示场景（按场景的推荐策略重新生成）
+}
+
+// RecommendationExplanationDTO 一条推荐的打分明细
+//
+// 推荐是重新生成的（和用户下次请求看到的一致），不是当时展示的那一次：
+// 关注关系、帖子、实时互动变化之后，结果可能和当时不同。
+type RecommendationExplanationDTO struct {
+	ViewerID     int64  `json:"viewer_id"`
+	TargetUserID int64  `json:"target_user_id"`
+	Surface      string `json:"surface"`
+
+	// Found 重新生成的候选中有没有这个人（为 false 时下面的打分明细为空：
+	// 没有推荐信号，或者被过滤器、多样性规则排除了）
+	Found      bool              `json:"found"`
+	ReasonType string            `json:"reason_type,omitempty"`
+	Position   int               `json:"position"` // 在排好序的候选中的位置（从 0 开始，曝光配额、重复展示降权之前）；没有找到时为 -1
+	Candidates int               `json:"candidates"`
+	Score      int               `json:"score"`
+	Factors    []*ScoreFactorDTO `json:"factors,omitempty"`
+
+	// 策略权重 bandit：这种推荐理由的权重（没有开启时为 1）
+	StrategyWeight float64 `json:"strategy_weight"`
+	// 分数校准：没有配置校准参数时为空
+	CalibratedScore *float64 `json:"calibrated_score,omitempty"`
+
+	// 版本：打分规则、打分权重、校准参数
+	RulesVersion         string `json:"rules_version"`
+	PerRelatedUser       int    `json:"per_related_user"`
+	PerRecentPost        int    `json:"per_recent_post"`
+	PerHundredEngagement int    `json:"per_hundred_engagement"`
+	CalibrationVersion   string `json:"calibration_version,omitempty"`
+
+	// FiltersPassed 生成推荐时执行的过滤器（找到了就说明都通过了）
+	FiltersPassed []string `json:"filters_passed"`
+}
+
+// ScoreFactorDTO 一个打分因素对分数的贡献
+type ScoreFactorDTO struct {
+	Name         string `json:"name"`
+	Value        int    `json:"value"`
+	Contribution int    `json:"contribution"`
+}
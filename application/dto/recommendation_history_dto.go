@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// RecommendationHistoryRequest 推荐历史查询请求（接口层 → 应用层）
+type RecommendationHistoryRequest struct {
+	UserID    int64
+	From      time.Time // 起始时间（包含），零值表示不限制
+	To        time.Time // 结束时间（不包含），零值表示当前时间
+	PageToken string    // 上一页响应的 NextPageToken，为空时从最新的开始
+	Limit     int       // 默认 20，最多 100
+}
+
+// RecommendationHistoryResponse 推荐历史查询响应
+type RecommendationHistoryResponse struct {
+	Items         []*RecommendationHistoryItemDTO `json:"items"`           // 最近展示的在前
+	NextPageToken string                          `json:"next_page_token"` // 为空表示没有更多
+}
+
+// RecommendationHistoryItemDTO 一次展示过的推荐
+type RecommendationHistoryItemDTO struct {
+	RecommendationID string `json:"recommendation_id"`
+	UserID           int64  `json:"user_id"` // 被推荐的用户
+	Username         string `json:"username"`
+	Avatar           string `json:"avatar"`
+	Surface          string `json:"surface"`  // 展示场景
+	ShownAt          string `json:"shown_at"` // RFC3339
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// RetentionReport 一次数据保留期执行的报告
+type RetentionReport struct {
+	RanAt   time.Time               `json:"ran_at"`
+	Classes []*RetentionClassReport `json:"classes"`
+}
+
+// RetentionClassReport 一类数据的执行结果
+type RetentionClassReport struct {
+	Class        string        `json:"class"`         // 数据类别（如 impressions）
+	MaxAge       time.Duration `json:"max_age"`       // 保留期
+	PurgedRows   int64         `json:"purged_rows"`   // 本次删除的条数
+	OldestRecord time.Time     `json:"oldest_record"` // 删除后最早一条记录的时间（没有记录时为零值）
+	ExceedsLimit bool          `json:"exceeds_limit"` // 删除后仍有超过保留期的数据
+	Unmanaged    bool          `json:"unmanaged"`     // 配置了保留期，但没有对应的数据存储（本服务尚未保存这类数据）
+	Error        string        `json:"error,omitempty"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// StrategyWeightsDTO 一个展示场景上各策略的 bandit 统计和当前权重（管理接口）
+type StrategyWeightsDTO struct {
+	Surface  string                     `json:"surface"`
+	Arms     []*StrategyArmDTO          `json:"arms"`
+	Override *StrategyWeightOverrideDTO `json:"override,omitempty"` // 没有人工覆盖时为空
+}
+
+// StrategyArmDTO 一种策略（推荐理由类型）的累计统计和权重
+type StrategyArmDTO struct {
+	Arm            string  `json:"arm"`
+	Pulls          int64   `json:"pulls"`           // 曝光次数
+	Rewards        int64   `json:"rewards"`         // 归因转化次数
+	ConversionRate float64 `json:"conversion_rate"` // Rewards / Pulls
+	BanditWeight   float64 `json:"bandit_weight"`   // bandit 计算的权重
+	Weight         float64 `json:"weight"`          // 实际生效的权重（有人工覆盖时以覆盖为准）
+}
+
+// StrategyWeightOverrideDTO 人工覆盖
+type StrategyWeightOverrideDTO struct {
+	Disabled  bool               `json:"disabled"`
+	Weights   map[string]float64 `json:"weights,omitempty"`
+	UpdatedBy string             `json:"updated_by"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// StrategyWeightOverrideRequest 设置人工覆盖（管理接口 → 应用层）
+type StrategyWeightOverrideRequest struct {
+	Surface   string
+	Operator  string             // 操作人（认证后的身份，见 Principal.Actor；写入审计日志）
+	RequestID string             // 请求ID（写入审计日志）
+	Clear     bool               // 删除覆盖，恢复 bandit 的权重
+	Disabled  bool               // 关闭 bandit（所有策略权重为 1）
+	Weights   map[string]float64 // 固定权重（推荐理由类型 → 权重）
+}
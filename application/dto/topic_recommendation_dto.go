@@ -0,0 +1,21 @@
+package dto
+
+// TopicRecommendationRequest 话题推荐请求（接口层 → 应用层）
+type TopicRecommendationRequest struct {
+	UserID int64 // 为哪个用户推荐
+	Limit  int   // 返回数量（为 0 时使用默认值）
+}
+
+// TopicRecommendationResponse 话题推荐响应
+type TopicRecommendationResponse struct {
+	Topics []*TopicRecommendationDTO `json:"topics"`
+}
+
+// TopicRecommendationDTO 话题推荐DTO
+type TopicRecommendationDTO struct {
+	RecommendationID string `json:"recommendation_id"`
+	Topic            string `json:"topic"`   // 规范化后的名称（如 golang）
+	Hashtag          string `json:"hashtag"` // 展示用的形式（如 #golang）
+	Reason           string `json:"reason"`  // "3 位你关注的人关注了这个话题"
+	Score            int    `json:"score"`   // 推荐分数
+}
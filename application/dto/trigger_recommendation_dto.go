@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// TriggerType 触发式推荐的触发事件
+type TriggerType string
+
+const (
+	// TriggerMilestone 用户达到了一个里程碑（如粉丝数突破 1 万）
+	TriggerMilestone TriggerType = "milestone"
+	// TriggerReturned 用户很久没有活跃，最近回来了
+	TriggerReturned TriggerType = "returned"
+)
+
+// TriggerEventRequest 触发事件（接口层 → 应用层）
+type TriggerEventRequest struct {
+	Type          TriggerType
+	SubjectUserID int64     // 触发事件的用户（推荐给关注了 TA 的人）
+	Milestone     int       // TriggerMilestone：达到的粉丝数
+	InactiveDays  int       // TriggerReturned：不活跃的天数
+	OccurredAt    time.Time // 事件发生时间（为零值时使用当前时间）
+}
+
+// TriggerRecommendationRequest 推送管道拉取触发式推荐
+type TriggerRecommendationRequest struct {
+	UserID int64 // 给哪个用户推送
+}
+
+// TriggerRecommendationResponse 触发式推荐响应
+type TriggerRecommendationResponse struct {
+	Recommendations []*TriggerRecommendationDTO `json:"recommendations"`
+}
+
+// TriggerRecommendationDTO 一条触发式推荐
+type TriggerRecommendationDTO struct {
+	UserID    int64  `json:"user_id"`    // 被推荐的用户（触发事件的用户）
+	Trigger   string `json:"trigger"`    // milestone / returned
+	Reason    string `json:"reason"`     // "你关注的TA粉丝突破了 10000"
+	Score     int    `json:"score"`      // 推荐分数
+	ExpiresAt int64  `json:"expires_at"` // 过期时间（Unix 秒），过期后不应再推送
+}
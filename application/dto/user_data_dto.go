@@ -0,0 +1,77 @@
+package dto
+
+import "time"
+
+// UserDataExport 用户数据导出（访问权 / right of access）
+//
+// 序列化为 JSON 后交给隐私团队的工具，再由它汇总各服务的导出发给用户。
+type UserDataExport struct {
+	UserID      int64     `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// RecommendationsShown 推荐服务给用户展示过的推荐
+	RecommendationsShown []*ExportedImpression `json:"recommendations_shown"`
+
+	// RecommendationLists 为用户生成并保存的推荐列表（包括推荐理由），按生成时间倒序
+	RecommendationLists []*ExportedRecommendationList `json:"recommendation_lists"`
+
+	// Feedback 用户对推荐做出的反馈（点开、在推荐卡片上关注）
+	Feedback []*ExportedFeedback `json:"feedback"`
+
+	// Conversions 用户看到推荐之后关注了被推荐的人（归因到推荐的关注）
+	Conversions []*ExportedConversion `json:"conversions"`
+
+	// ExperimentAssignments 用户在 A/B 实验中固定的分组，按实验名排序
+	ExperimentAssignments []*ExportedExperimentAssignment `json:"experiment_assignments"`
+
+	// RecommendationsSuppressed 是否已停止为用户生成推荐（用户数据已删除，尚未重新激活）
+	RecommendationsSuppressed bool `json:"recommendations_suppressed"`
+
+	// NotStored 推荐服务不保存的数据类别（说明导出中为什么没有这些数据）
+	NotStored []string `json:"not_stored"`
+}
+
+// ExportedImpression 一次推荐曝光
+type ExportedImpression struct {
+	RecommendationID string    `json:"recommendation_id"`
+	TargetUserID     int64     `json:"target_user_id"` // 被推荐的用户
+	Surface          string    `json:"surface"`        // 展示场景（如 home_feed）
+	ShownAt          time.Time `json:"shown_at"`
+}
+
+// ExportedRecommendationList 一份保存的推荐列表
+type ExportedRecommendationList struct {
+	GeneratedAt     time.Time                 `json:"generated_at"`
+	Recommendations []*ExportedRecommendation `json:"recommendations"`
+}
+
+// ExportedRecommendation 推荐列表中的一条推荐
+type ExportedRecommendation struct {
+	TargetUserID int64     `json:"target_user_id"` // 被推荐的用户
+	ReasonType   string    `json:"reason_type"`    // 推荐理由类型（如 followed_by_following）
+	RelatedCount int       `json:"related_count"`  // 推荐理由涉及的人数（如"你关注的 3 个人也关注了 TA"）
+	Score        int       `json:"score"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// ExportedFeedback 一次推荐反馈
+type ExportedFeedback struct {
+	RecommendationID string    `json:"recommendation_id"`
+	TargetUserID     int64     `json:"target_user_id"` // 被推荐的用户
+	Action           string    `json:"action"`         // 反馈类型（clicked、followed）
+	At               time.Time `json:"at"`
+}
+
+// ExportedConversion 一次归因到推荐的关注
+type ExportedConversion struct {
+	RecommendationID string    `json:"recommendation_id"`
+	TargetUserID     int64     `json:"target_user_id"` // 被推荐、被关注的用户
+	Direct           bool      `json:"direct"`         // 是否直接在推荐卡片上关注
+	ConvertedAt      time.Time `json:"converted_at"`
+}
+
+// ExportedExperimentAssignment 用户在一个 A/B 实验中固定的分组
+type ExportedExperimentAssignment struct {
+	Experiment string `json:"experiment"` // 实验名
+	Variant    string `json:"variant"`    // 分组名（如 control）
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// FollowSourceRecommendation 关注事件的入口：在推荐卡片上点了关注
+const FollowSourceRecommendation = "recommendation"
+
+// AttributionService 应用服务：曝光 → 关注的归因
+//
+// 为什么需要？
+// 曝光记录只说明"推荐展示过"，关注关系只说明"关注了"，
+// 两者连起来才能回答"推荐有没有用、哪种策略更有用"。
+//
+// 规则（最后一次曝光归因）：
+// 1. 关注事件到达时，查找关注前 window 内把被关注的人推荐给关注者的曝光
+// 2. 有多次曝光时归因到最近的一次
+// 3. 写入转化记录（同一次曝光只记录一次）；关注入口是推荐卡片时标记为直接转化
+//
+// 取关事件不撤销转化：统计的是"推荐带来了多少关注"，不是"现在还剩多少"。
+// 开启了策略权重 bandit 时，转化同时作为曝光所属策略的奖励。
+type AttributionService struct {
+	impressionRepo repository.ImpressionRepository
+	conversionRepo repository.ConversionRepository
+	bandit         *StrategyBandit // 可以为 nil
+	window         time.Duration
+	now            func() time.Time
+}
+
+// NewAttributionService 构造函数
+//
+// 参数：
+// - bandit: 策略权重 bandit（可以为 nil）
+// - window: 归因窗口，应该和效果统计（FeedbackAnalyticsService）的 conversionWindow 一致
+func NewAttributionService(
+	impressionRepo repository.ImpressionRepository,
+	conversionRepo repository.ConversionRepository,
+	bandit *StrategyBandit,
+	window time.Duration,
+) *AttributionService {
+	return &AttributionService{
+		impressionRepo: impressionRepo,
+		conversionRepo: conversionRepo,
+		bandit:         bandit,
+		window:         window,
+		now:            time.Now,
+	}
+}
+
+// HandleFollow 用例：处理一个关注事件，返回是否归因到了曝光
+//
+// 返回错误时消费者应该重试（写入是幂等的）。
+func (s *AttributionService) HandleFollow(ctx context.Context, req *dto.FollowEventRequest) (bool, error) {
+	followerID, err := valueobject.NewUserID(req.FollowerID)
+	if err != nil {
+		return false, err
+	}
+	followingID, err := valueobject.NewUserID(req.FollowingID)
+	if err != nil {
+		return false, err
+	}
+	followedAt := req.OccurredAt
+	if followedAt.IsZero() {
+		followedAt = s.now()
+	}
+
+	impression, ok, err := s.impressionRepo.FindLatestImpression(ctx, followerID, followingID, followedAt.Add(-s.window), followedAt)
+	if err != nil || !ok {
+		return false, err
+	}
+	conversion := entity.NewConversion(impression, followedAt, req.Source == FollowSourceRecommendation)
+	if err := s.conversionRepo.RecordConversion(ctx, conversion); err != nil {
+		return false, err
+	}
+	s.bandit.RecordReward(ctx, impression.Surface(), impression.ReasonType())
+	return true, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// fakeConversionRepo 测试用转化记录：按曝光去重
+type fakeConversionRepo struct {
+	conversions map[valueobject.RecommendationID]*entity.Conversion
+}
+
+func (r *fakeConversionRepo) RecordConversion(ctx context.Context, conversion *entity.Conversion) error {
+	if _, ok := r.conversions[conversion.RecommendationID()]; !ok {
+		r.conversions[conversion.RecommendationID()] = conversion
+	}
+	return nil
+}
+
+func (r *fakeConversionRepo) DeleteUserConversions(ctx context.Context, userID valueobject.UserID) error {
+	return nil
+}
+
+func (r *fakeConversionRepo) ListUserConversions(ctx context.Context, viewerID valueobject.UserID) ([]*entity.Conversion, error) {
+	var result []*entity.Conversion
+	for _, conversion := range r.conversions {
+		if conversion.ViewerID() == viewerID {
+			result = append(result, conversion)
+		}
+	}
+	return result, nil
+}
+
+func TestAttributionService_HandleFollow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	viewer, _ := valueobject.NewUserID(1)
+	target, _ := valueobject.NewUserID(2)
+	earlier := entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonFollowedByFollowing, "home_feed", now.Add(-10*time.Hour))
+	latest := entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonMissedPosts, "push", now.Add(-2*time.Hour))
+	impressions := &fakeImpressionRepo{listed: []*entity.Impression{earlier, latest}}
+	conversions := &fakeConversionRepo{conversions: make(map[valueobject.RecommendationID]*entity.Conversion)}
+	s := NewAttributionService(impressions, conversions, nil, 24*time.Hour)
+	ctx := context.Background()
+
+	// 归因到最近的一次曝光；推荐卡片上的关注是直接转化
+	attributed, err := s.HandleFollow(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 2, Source: FollowSourceRecommendation, OccurredAt: now})
+	if err != nil || !attributed {
+		t.Fatalf("attributed = %v, err = %v, want true, nil", attributed, err)
+	}
+	conversion, ok := conversions.conversions[latest.RecommendationID()]
+	if len(conversions.conversions) != 1 || !ok || !conversion.Direct() || conversion.ReasonType() != valueobject.ReasonMissedPosts {
+		t.Fatalf("conversions = %+v, want one direct conversion of the latest impression", conversions.conversions)
+	}
+
+	// 超出归因窗口的关注不归因
+	attributed, err = s.HandleFollow(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 2, OccurredAt: now.Add(25 * time.Hour)})
+	if err != nil || attributed {
+		t.Errorf("outside window: attributed = %v, err = %v, want false, nil", attributed, err)
+	}
+
+	// 没有曝光过的关注不归因
+	attributed, err = s.HandleFollow(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 3, OccurredAt: now})
+	if err != nil || attributed {
+		t.Errorf("not impressed: attributed = %v, err = %v, want false, nil", attributed, err)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/repository"
+)
+
+const (
+	defaultAuditQueryLimit = 100
+	maxAuditQueryLimit     = 1000
+)
+
+// AuditLogService 应用服务：管理操作审计
+//
+// 用例：
+// - Record：记录一次管理操作（修改打分权重、编辑精选包、覆盖推荐屏蔽等）
+// - Query：管理接口查询审计记录
+//
+// 管理操作如何使用？
+// 每个修改类的管理接口在同一个流程里调用 Record：
+//
+//	before := weights.Current()
+//	weights.Update(newWeights)
+//	if err := audit.Record(ctx, &dto.AuditRecord{
+//	    Actor:     principal,
+//	    Action:    string(entity.AuditActionScoringWeightChange),
+//	    Target:    "follow_overlap",
+//	    Before:    before,
+//	    After:     newWeights,
+//	    RequestID: requestID,
+//	}); err != nil {
+//	    return err // 审计写入失败时管理操作必须报错（合规要求：没有审计记录的操作不允许成功）
+//	}
+type AuditLogService struct {
+	repo repository.AuditLogRepository
+}
+
+// NewAuditLogService 构造函数
+func NewAuditLogService(repo repository.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{repo: repo}
+}
+
+// Record 用例：记录一次管理操作
+func (s *AuditLogService) Record(ctx context.Context, record *dto.AuditRecord) error {
+	before, err := marshalAuditValue(record.Before)
+	if err != nil {
+		return fmt.Errorf("marshal audit before value: %w", err)
+	}
+	after, err := marshalAuditValue(record.After)
+	if err != nil {
+		return fmt.Errorf("marshal audit after value: %w", err)
+	}
+
+	entry, err := entity.NewAuditEntry(
+		record.Actor,
+		entity.AuditAction(record.Action),
+		record.Target,
+		before,
+		after,
+		record.RequestID,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	return s.repo.Append(ctx, entry)
+}
+
+// Query 用例：查询审计记录（最新的在前）
+func (s *AuditLogService) Query(ctx context.Context, query *dto.AuditLogQuery) ([]*dto.AuditEntryDTO, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+	limit = min(limit, maxAuditQueryLimit)
+
+	entries, err := s.repo.Query(ctx, repository.AuditLogQuery{
+		Actor:  query.Actor,
+		Action: entity.AuditAction(query.Action),
+		Target: query.Target,
+		Since:  query.Since,
+		Until:  query.Until,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.AuditEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &dto.AuditEntryDTO{
+			Actor:      entry.Actor(),
+			Action:     string(entry.Action()),
+			Target:     entry.Target(),
+			Before:     entry.Before(),
+			After:      entry.After(),
+			RequestID:  entry.RequestID(),
+			OccurredAt: entry.OccurredAt().Format(time.RFC3339),
+		})
+	}
+	return result, nil
+}
+
+// marshalAuditValue 辅助方法：修改前后的值 → JSON（nil 保存为空字符串）
+func marshalAuditValue(value any) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
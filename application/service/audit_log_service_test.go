@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/repository"
+)
+
+// fakeAuditLogRepo 测试用审计日志：按追加顺序保存
+type fakeAuditLogRepo struct {
+	entries []*entity.AuditEntry
+}
+
+func (r *fakeAuditLogRepo) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *fakeAuditLogRepo) Query(ctx context.Context, query repository.AuditLogQuery) ([]*entity.AuditEntry, error) {
+	return r.entries, nil
+}
+
+func TestAuditLogService_Record(t *testing.T) {
+	repo := &fakeAuditLogRepo{}
+	s := NewAuditLogService(repo)
+
+	err := s.Record(context.Background(), &dto.AuditRecord{
+		Actor:     "admin@example.com",
+		Action:    string(entity.AuditActionScoringWeightChange),
+		Target:    "follow_overlap",
+		Before:    map[string]float64{"weight": 1.0},
+		After:     map[string]float64{"weight": 1.5},
+		RequestID: "req-1",
+	})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	entries, err := s.Query(context.Background(), &dto.AuditLogQuery{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+	if entries[0].Before != `{"weight":1}` || entries[0].After != `{"weight":1.5}` {
+		t.Errorf("before/after = %s / %s", entries[0].Before, entries[0].After)
+	}
+	if entries[0].RequestID != "req-1" {
+		t.Errorf("RequestID = %q, want req-1", entries[0].RequestID)
+	}
+}
+
+func TestAuditLogService_RecordRequiresActor(t *testing.T) {
+	s := NewAuditLogService(&fakeAuditLogRepo{})
+
+	err := s.Record(context.Background(), &dto.AuditRecord{
+		Action: string(entity.AuditActionCuratedPackEdit),
+	})
+	if err != entity.ErrAuditActorRequired {
+		t.Errorf("err = %v, want ErrAuditActorRequired", err)
+	}
+}
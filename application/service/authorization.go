@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated: no principal in request")
+	ErrForbidden       = errors.New("forbidden: principal may not access this user's data")
+)
+
+// Principal 发起请求的身份（已经过认证）
+//
+// 两种身份：
+// - 终端用户：UserID > 0（由网关 / BFF 认证用户登录态后透传）
+// - 内部服务：Service 非空（由服务间调用的身份认证得到，如 mTLS 证书中的服务名）
+//
+// BFF 代表终端用户调用时两者都有：Service 是 BFF，UserID 是登录用户。
+type Principal struct {
+	UserID  int64
+	Service string
+}
+
+// IdentityVerifier 身份令牌校验端口（认证用，由接口层调用）
+//
+// 网关 / BFF 认证用户登录态之后签发短期的身份令牌，随请求透传（RPC metainfo、HTTP Authorization 头）；
+// 服务端用同一个签名密钥校验，校验通过后才信任令牌中的用户ID、服务名。
+// 签名不对、过期、格式错误时返回错误，接口层直接拒绝请求（401）。
+type IdentityVerifier interface {
+	Verify(token string) (Principal, error)
+}
+
+// Actor 审计日志中的操作人：服务名，代表已登录用户操作时带上用户ID（如 "ops-dashboard/user:42"）
+func (p Principal) Actor() string {
+	switch {
+	case p.Service != "" && p.UserID > 0:
+		return fmt.Sprintf("%s/user:%d", p.Service, p.UserID)
+	case p.Service != "":
+		return p.Service
+	case p.UserID > 0:
+		return fmt.Sprintf("user:%d", p.UserID)
+	default:
+		return ""
+	}
+}
+
+// principalKey context key（不导出，避免和其他包冲突）
+type principalKey struct{}
+
+// WithPrincipal 把已认证的身份放进 ctx（由接口层在认证之后调用）
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext 从 ctx 取出已认证的身份
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// AuthorizationPolicy 授权策略
+type AuthorizationPolicy struct {
+	// OnBehalfServices 可以查询任意用户推荐的内部服务（如推送管道、BFF）
+	OnBehalfServices []string
+	// PrivacyServices 可以调用用户数据删除等隐私接口的内部服务（如隐私团队的工具）
+	PrivacyServices []string
+	// QualityReviewServices 可以调用推荐解释接口的内部服务（如质量评审工具）
+	QualityReviewServices []string
+	// AdminServices 可以调用管理接口（RecommendationAdminService）的内部服务（如运营后台）
+	AdminServices []string
+	// FollowWriteServices 可以代表任意用户关注、取关的内部服务（默认为空：只有用户自己可以）
+	//
+	// 和 OnBehalfServices 分开配置：代为查询推荐的服务（推送管道、运营后台）只被信任读取，不能改写关注关系。
+	FollowWriteServices []string
+}
+
+// Authorizer 授权检查
+//
+// 为什么需要？
+// 推荐结果包含推荐理由（"你关注的 3 个人也关注了 TA"），是从用户的社交关系推导出来的。
+// 如果任何调用方都能传任意 userID，就能拉取任何人的推荐，间接泄露他的关注关系。
+//
+// 规则：
+// - 终端用户只能查询自己的推荐
+// - 白名单中的内部服务（推送管道、BFF）可以代表任意用户查询
+// - 隐私接口只允许隐私白名单中的内部服务调用
+// - 推荐解释接口只允许质量评审白名单中的内部服务调用
+// - 管理接口只允许管理白名单中的内部服务调用
+// - 关注、取关只能为自己操作，或者由关注写入白名单中的内部服务代为操作（代为查询推荐的白名单不适用）
+//
+// 为什么在应用层？
+// 授权规则（"谁可以看谁的推荐"）是用例的一部分，和协议无关；
+// 接口层只负责认证（识别身份）并把 Principal 放进 ctx。
+type Authorizer struct {
+	onBehalf map[string]bool
+	privacy  map[string]bool
+	review   map[string]bool
+	admin    map[string]bool
+	follow   map[string]bool
+}
+
+// NewAuthorizer 构造函数
+func NewAuthorizer(policy AuthorizationPolicy) *Authorizer {
+	a := &Authorizer{
+		onBehalf: make(map[string]bool, len(policy.OnBehalfServices)),
+		privacy:  make(map[string]bool, len(policy.PrivacyServices)),
+		review:   make(map[string]bool, len(policy.QualityReviewServices)),
+		admin:    make(map[string]bool, len(policy.AdminServices)),
+		follow:   make(map[string]bool, len(policy.FollowWriteServices)),
+	}
+	for _, name := range policy.OnBehalfServices {
+		a.onBehalf[name] = true
+	}
+	for _, name := range policy.PrivacyServices {
+		a.privacy[name] = true
+	}
+	for _, name := range policy.QualityReviewServices {
+		a.review[name] = true
+	}
+	for _, name := range policy.AdminServices {
+		a.admin[name] = true
+	}
+	for _, name := range policy.FollowWriteServices {
+		a.follow[name] = true
+	}
+	return a
+}
+
+// AuthorizeRecommendations 检查 ctx 中的身份能否查询 userID 的推荐
+//
+// 返回：
+// - nil：允许
+// - ErrUnauthenticated：ctx 中没有身份
+// - ErrForbidden：身份不允许查询该用户
+func (a *Authorizer) AuthorizeRecommendations(ctx context.Context, userID int64) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if principal.Service != "" && a.onBehalf[principal.Service] {
+		return nil
+	}
+	if principal.UserID > 0 && principal.UserID == userID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// AuthorizeFollowWrite 检查 ctx 中的身份能否以 followerID 的名义关注、取关
+//
+// 写操作比查询推荐严格：代为查询推荐的服务（OnBehalfServices）不能代为关注、取关，
+// 只有 followerID 本人（包括 BFF 带着登录用户转发）或者关注写入白名单中的服务可以。
+func (a *Authorizer) AuthorizeFollowWrite(ctx context.Context, followerID int64) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if principal.UserID > 0 && principal.UserID == followerID {
+		return nil
+	}
+	if principal.UserID == 0 && principal.Service != "" && a.follow[principal.Service] {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// AuthorizePrivacyOperation 检查 ctx 中的身份能否调用隐私接口（如用户数据删除）
+func (a *Authorizer) AuthorizePrivacyOperation(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if principal.Service != "" && a.privacy[principal.Service] {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// AuthorizeQualityReview 检查 ctx 中的身份能否调用推荐解释接口
+//
+// 解释结果包含打分细节和推荐理由（间接反映用户的社交关系），终端用户不能调用。
+func (a *Authorizer) AuthorizeQualityReview(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if principal.Service != "" && a.review[principal.Service] {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// AuthorizeAdmin 检查 ctx 中的身份能否调用管理接口（审计日志、效果统计、策略权重）
+//
+// 管理接口能看到全局的统计、修改线上的策略权重，终端用户不能调用。
+func (a *Authorizer) AuthorizeAdmin(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if principal.Service != "" && a.admin[principal.Service] {
+		return nil
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAuthorizer_AuthorizeRecommendations(t *testing.T) {
+	a := NewAuthorizer(AuthorizationPolicy{
+		OnBehalfServices:      []string{"push-pipeline"},
+		PrivacyServices:       []string{"privacy-tooling"},
+		QualityReviewServices: []string{"quality-review"},
+		AdminServices:         []string{"ops-dashboard"},
+	})
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		userID    int64
+		want      error
+	}{
+		{"no principal", nil, 1, ErrUnauthenticated},
+		{"own recommendations", &Principal{UserID: 1, Service: "app-gateway"}, 1, nil},
+		{"someone else's recommendations", &Principal{UserID: 2, Service: "app-gateway"}, 1, ErrForbidden},
+		{"allowlisted service on behalf of user", &Principal{Service: "push-pipeline"}, 1, nil},
+		{"unknown service without user", &Principal{Service: "crawler"}, 1, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, *tt.principal)
+			}
+			if err := a.AuthorizeRecommendations(ctx, tt.userID); err != tt.want {
+				t.Errorf("AuthorizeRecommendations() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 可以代查推荐的服务不能调用隐私接口
+	ctx := WithPrincipal(context.Background(), Principal{Service: "push-pipeline"})
+	if err := a.AuthorizePrivacyOperation(ctx); err != ErrForbidden {
+		t.Errorf("AuthorizePrivacyOperation(push-pipeline) = %v, want ErrForbidden", err)
+	}
+	ctx = WithPrincipal(context.Background(), Principal{Service: "privacy-tooling"})
+	if err := a.AuthorizePrivacyOperation(ctx); err != nil {
+		t.Errorf("AuthorizePrivacyOperation(privacy-tooling) = %v, want nil", err)
+	}
+
+	// 推荐解释接口只允许质量评审工具调用
+	if err := a.AuthorizeQualityReview(ctx); err != ErrForbidden {
+		t.Errorf("AuthorizeQualityReview(privacy-tooling) = %v, want ErrForbidden", err)
+	}
+	ctx = WithPrincipal(context.Background(), Principal{Service: "quality-review"})
+	if err := a.AuthorizeQualityReview(ctx); err != nil {
+		t.Errorf("AuthorizeQualityReview(quality-review) = %v, want nil", err)
+	}
+
+	// 管理接口只允许管理白名单中的服务调用，终端用户不能调用
+	if err := a.AuthorizeAdmin(ctx); err != ErrForbidden {
+		t.Errorf("AuthorizeAdmin(quality-review) = %v, want ErrForbidden", err)
+	}
+	if err := a.AuthorizeAdmin(WithPrincipal(context.Background(), Principal{UserID: 1})); err != ErrForbidden {
+		t.Errorf("AuthorizeAdmin(user) = %v, want ErrForbidden", err)
+	}
+	if err := a.AuthorizeAdmin(WithPrincipal(context.Background(), Principal{Service: "ops-dashboard"})); err != nil {
+		t.Errorf("AuthorizeAdmin(ops-dashboard) = %v, want nil", err)
+	}
+	if err := a.AuthorizeAdmin(context.Background()); err != ErrUnauthenticated {
+		t.Errorf("AuthorizeAdmin(no principal) = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestAuthorizer_AuthorizeFollowWrite(t *testing.T) {
+	a := NewAuthorizer(AuthorizationPolicy{
+		OnBehalfServices:    []string{"push-pipeline", "bff"},
+		FollowWriteServices: []string{"onboarding-importer"},
+	})
+
+	tests := []struct {
+		name       string
+		principal  *Principal
+		followerID int64
+		want       error
+	}{
+		{"no principal", nil, 1, ErrUnauthenticated},
+		{"own follow", &Principal{UserID: 1}, 1, nil},
+		{"bff with the logged-in user", &Principal{Service: "bff", UserID: 1}, 1, nil},
+		{"bff with another user", &Principal{Service: "bff", UserID: 2}, 1, ErrForbidden},
+		{"on-behalf read service", &Principal{Service: "push-pipeline"}, 1, ErrForbidden},
+		{"follow write allowlist", &Principal{Service: "onboarding-importer"}, 1, nil},
+		{"allowlisted service carrying another user", &Principal{Service: "onboarding-importer", UserID: 2}, 1, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, *tt.principal)
+			}
+			if err := a.AuthorizeFollowWrite(ctx, tt.followerID); err != tt.want {
+				t.Errorf("AuthorizeFollowWrite() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrincipal_Actor(t *testing.T) {
+	tests := []struct {
+		principal Principal
+		want      string
+	}{
+		{Principal{Service: "ops-dashboard"}, "ops-dashboard"},
+		{Principal{Service: "ops-dashboard", UserID: 42}, "ops-dashboard/user:42"},
+		{Principal{UserID: 42}, "user:42"},
+		{Principal{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.principal.Actor(); got != tt.want {
+			t.Errorf("%+v.Actor() = %q, want %q", tt.principal, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// maxBackfillFetch 补足一页时每份名单（编辑精选、全站热门）最多多取的人数
+//
+// 名单中的人可能已经关注、被屏蔽或者已经在这一页中，多取一些才能凑满一页。
+const maxBackfillFetch = 20
+
+// WithBackfill 开启补足一页：个性化推荐不够 limit 个时，用编辑精选、全站热门的用户补齐
+//
+// 为什么需要？
+// 关注的人少、或者过滤条件、曝光配额、会话去重之后，个性化推荐可能只剩两三个；
+// 客户端按整页布局，短列表会留下一大块空白，翻页也会提前结束。
+//
+// 补上的推荐使用单独的理由类型（ReasonBackfill），排在个性化推荐后面，
+// 客户端可以区分展示，统计时也不会和个性化推荐混在一起。
+//
+// 和冷启动（domain/service.WithColdStart）的区别：
+// 冷启动在生成器中执行，只在一个推荐也没有时使用；补足一页在应用层执行，针对的是最终返回的这一页。
+func WithBackfill(repo repository.PopularUsersRepository) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.backfillRepo = repo
+	}
+}
+
+// backfillPage 辅助方法：这一页不够 limit 个时，用编辑精选、全站热门的用户补齐
+//
+// 跳过的人：自己、已经在这一页中的、会话内已经返回过的、已经关注的、有屏蔽关系的，
+// 以及不满足调用方过滤条件的（如只要某几种理由的请求不会收到补位的推荐）。
+//
+// 容错：名单、关注关系、屏蔽关系查询失败时只记日志，返回原来的一页（补位不影响推荐的可用性）。
+func (s *RecommendationService) backfillPage(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	page []*aggregate.UserRecommendation,
+	limit int,
+	returned map[valueobject.UserID]bool,
+	criteria aggregate.RecommendationCriteria,
+) []*aggregate.UserRecommendation {
+	missing := limit - len(page)
+	if s.backfillRepo == nil || missing <= 0 {
+		return page
+	}
+
+	candidates, err := s.backfillCandidates(ctx, missing+maxBackfillFetch)
+	if err == nil && len(candidates) > 0 {
+		var skip map[valueobject.UserID]bool
+		if skip, err = s.backfillExclusions(ctx, viewerID); err == nil {
+			page = appendBackfill(page, candidates, missing, skip, returned, criteria)
+		}
+	}
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "backfill skipped", map[string]any{
+			FieldUserID: viewerID.Value(),
+			FieldError:  err.Error(),
+		})
+	}
+	return page
+}
+
+// backfillCandidates 辅助方法：补位的候选（编辑精选在前，全站热门在后）
+//
+// 一份名单查询失败时只用另一份；都失败时返回错误。
+func (s *RecommendationService) backfillCandidates(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	curated, curatedErr := s.backfillRepo.GetCuratedUsers(ctx, limit)
+	popular, popularErr := s.backfillRepo.GetPopularUsers(ctx, limit)
+	if curatedErr != nil && popularErr != nil {
+		return nil, popularErr
+	}
+	return append(curated, popular...), nil
+}
+
+// backfillExclusions 辅助方法：不能作为补位推荐的人（自己、已经关注的、有屏蔽关系的）
+func (s *RecommendationService) backfillExclusions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) (map[valueobject.UserID]bool, error) {
+	skip, err := s.blockedUsers(ctx, viewerID.Value())
+	if err != nil {
+		return nil, err
+	}
+	followings, err := s.socialGraphRepo.GetFollowings(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	skip[viewerID] = true
+	for _, userID := range followings {
+		skip[userID] = true
+	}
+	return skip, nil
+}
+
+// appendBackfill 把候选按顺序补到这一页后面，最多补 missing 个
+func appendBackfill(
+	page []*aggregate.UserRecommendation,
+	candidates []valueobject.UserID,
+	missing int,
+	skip, returned map[valueobject.UserID]bool,
+	criteria aggregate.RecommendationCriteria,
+) []*aggregate.UserRecommendation {
+	for _, rec := range page {
+		skip[rec.TargetUserID()] = true
+	}
+	result := make([]*aggregate.UserRecommendation, len(page), len(page)+missing)
+	copy(result, page)
+	for _, userID := range candidates {
+		if missing == 0 {
+			break
+		}
+		if skip[userID] || returned[userID] {
+			continue
+		}
+		skip[userID] = true
+		rec, err := aggregate.NewUserRecommendation(userID, valueobject.NewBackfillReason(), 0)
+		if err != nil || !criteria.IsSatisfiedBy(rec) {
+			continue
+		}
+		result = append(result, rec)
+		missing--
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+// PreviewAction 帖子预览的处理方式
+type PreviewAction int
+
+const (
+	// PreviewShow 正常展示
+	PreviewShow PreviewAction = iota
+	// PreviewMask 遮挡展示：保留帖子位置，内容替换为占位文案，客户端显示为"敏感内容"
+	PreviewMask
+	// PreviewDrop 不展示：从预览中移除
+	PreviewDrop
+)
+
+// maskedPostContent 遮挡后的占位文案
+const maskedPostContent = "该内容可能引起不适，已隐藏"
+
+// ContentSafetyRule 一个场景下的内容安全规则：审核状态 → 处理方式
+//
+// 没有配置的审核状态按 PreviewShow 处理。
+type ContentSafetyRule map[valueobject.ModerationStatus]PreviewAction
+
+// ContentSafetyPolicy 帖子预览的内容安全策略（按展示场景配置）
+//
+// 为什么在应用层？
+// "预览里展示什么"是展示/产品策略，不同场景要求不同，
+// 而帖子的审核状态（领域事实）来自实体 Post 和内容服务。
+// 应用层在组装 DTO 之前，根据场景把两者结合起来。
+//
+// 实际场景：
+//
+//	首页信息流：敏感内容遮挡展示，NSFW 不展示
+//	新用户引导：敏感内容和 NSFW 都不展示（新用户第一印象更重要）
+type ContentSafetyPolicy struct {
+	defaultRule  ContentSafetyRule
+	surfaceRules map[dto.Surface]ContentSafetyRule
+}
+
+// NewContentSafetyPolicy 构造函数
+//
+// 参数：
+// - defaultRule: 没有单独配置的场景使用的规则
+// - surfaceRules: 按场景覆盖的规则（可以为 nil）
+func NewContentSafetyPolicy(
+	defaultRule ContentSafetyRule,
+	surfaceRules map[dto.Surface]ContentSafetyRule,
+) *ContentSafetyPolicy {
+	if surfaceRules == nil {
+		surfaceRules = make(map[dto.Surface]ContentSafetyRule)
+	}
+	return &ContentSafetyPolicy{
+		defaultRule:  defaultRule,
+		surfaceRules: surfaceRules,
+	}
+}
+
+// DefaultContentSafetyPolicy 默认策略
+//
+// - 所有场景：敏感内容遮挡，NSFW 不展示
+// - 新用户引导、推送：敏感内容也不展示
+func DefaultContentSafetyPolicy() *ContentSafetyPolicy {
+	strict := ContentSafetyRule{
+		valueobject.ModerationSensitive: PreviewDrop,
+		valueobject.ModerationNSFW:      PreviewDrop,
+	}
+	return NewContentSafetyPolicy(
+		ContentSafetyRule{
+			valueobject.ModerationSensitive: PreviewMask,
+			valueobject.ModerationNSFW:      PreviewDrop,
+		},
+		map[dto.Surface]ContentSafetyRule{
+			dto.SurfaceOnboarding: strict,
+			dto.SurfacePush:       strict,
+		},
+	)
+}
+
+// ActionFor 查询方法：某个场景下，某种审核状态的帖子应该如何处理
+func (p *ContentSafetyPolicy) ActionFor(surface dto.Surface, status valueobject.ModerationStatus) PreviewAction {
+	rule, ok := p.surfaceRules[surface]
+	if !ok {
+		rule = p.defaultRule
+	}
+	if action, ok := rule[status]; ok {
+		return action
+	}
+	return PreviewShow
+}
+
+// Apply 对一条帖子预览执行策略
+//
+// 返回：处理后的预览；如果帖子不应该展示，返回 nil
+func (p *ContentSafetyPolicy) Apply(
+	surface dto.Surface,
+	post *dto.PostDTO,
+	status valueobject.ModerationStatus,
+) *dto.PostDTO {
+	switch p.ActionFor(surface, status) {
+	case PreviewDrop:
+		return nil
+	case PreviewMask:
+		return &dto.PostDTO{
+			PostID:    post.PostID,
+			Content:   maskedPostContent,
+			CreatedAt: post.CreatedAt,
+			Masked:    true,
+		}
+	default:
+		return post
+	}
+}
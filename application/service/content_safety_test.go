@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+func TestDefaultContentSafetyPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		surface dto.Surface
+		status  string
+		want    PreviewAction
+	}{
+		{"home approved", dto.SurfaceHomeFeed, "approved", PreviewShow},
+		{"home not moderated", dto.SurfaceHomeFeed, "", PreviewShow},
+		{"home sensitive is masked", dto.SurfaceHomeFeed, "sensitive", PreviewMask},
+		{"home nsfw is dropped", dto.SurfaceHomeFeed, "nsfw", PreviewDrop},
+		{"onboarding sensitive is dropped", dto.SurfaceOnboarding, "sensitive", PreviewDrop},
+		{"push sensitive is dropped", dto.SurfacePush, "sensitive", PreviewDrop},
+		{"onboarding approved", dto.SurfaceOnboarding, "approved", PreviewShow},
+	}
+	policy := DefaultContentSafetyPolicy()
+	for _, tt := range tests {
+		if got := policy.ActionFor(tt.surface, valueobject.ParseModerationStatus(tt.status)); got != tt.want {
+			t.Errorf("%s: action = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConvertPostInfosToDTO_ContentSafety(t *testing.T) {
+	metrics := &moderationMetrics{}
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithMetrics(metrics)(s)
+
+	posts := []*PostInfo{
+		{PostID: 1, Content: "hello", ModerationStatus: "approved"},
+		{PostID: 2, Content: "graphic", ModerationStatus: "sensitive"},
+		{PostID: 3, Content: "explicit", ModerationStatus: "nsfw"},
+	}
+
+	home := s.convertPostInfosToDTO(posts, dto.SurfaceHomeFeed)
+	if len(home) != 2 || home[0].Content != "hello" || home[0].Masked {
+		t.Fatalf("home previews = %+v, want the approved post and the masked one", home)
+	}
+	if masked := home[1]; masked.PostID != 2 || !masked.Masked || masked.Content != maskedPostContent {
+		t.Errorf("sensitive preview = %+v, want post 2 masked", masked)
+	}
+
+	onboarding := s.convertPostInfosToDTO(posts, dto.SurfaceOnboarding)
+	if len(onboarding) != 1 || onboarding[0].PostID != 1 {
+		t.Errorf("onboarding previews = %+v, want only the approved post", onboarding)
+	}
+	// 首页遮挡 1 条、移除 1 条，新用户引导移除 2 条
+	if metrics.moderated != 4 {
+		t.Errorf("moderated previews = %d, want 4", metrics.moderated)
+	}
+}
+
+// moderationMetrics 测试用监控：累计被内容安全策略处理的预览数
+type moderationMetrics struct {
+	moderated int
+}
+
+func (m *moderationMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	if name == MetricModeratedPreviews {
+		m.moderated += delta
+	}
+}
+
+func (m *moderationMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ContentSource 帖子预览的一个来源
+//
+// 为什么需要？
+// 帖子预览的"content 服务 → 本地数据库 → 空列表"以前写死在 getRecentPosts 中：
+// 只想用 content 服务、只查本地数据库，或者接入其他来源（如搜索索引、离线快照），都要修改应用服务。
+// 现在来源、顺序、超时在组合根中配置（ContentSourceChain），应用服务只按顺序尝试。
+//
+// ContentServiceClient 本身就是一个 ContentSource；本地数据库通过 NewLocalContentSource 适配。
+type ContentSource interface {
+	// GetRecentPosts 获取用户最近的帖子（返回 nil 表示这个来源没有数据，转到下一个来源）
+	GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error)
+
+	// GetRecentPostsForUsers 批量获取多个用户最近的帖子（没有批量接口时返回 ErrBatchUnavailable）
+	GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error)
+}
+
+// ContentSourceConfig 来源链中的一个来源
+type ContentSourceConfig struct {
+	Name    FallbackSource // 来源名（降级上报的 from / to，如 remote、local）
+	Source  ContentSource
+	Timeout time.Duration // 这个来源的超时（0 表示跟随请求的 context）
+	Healthy func() bool   // 健康信号：返回 false 时不调用，直接转到下一个来源（为 nil 时视为健康）
+}
+
+// ContentSourceChain 帖子预览的来源链：按顺序尝试，返回第一个成功的结果
+//
+// 常见配置：
+//
+//	// 只使用 content 服务
+//	NewContentSourceChain(ContentSourceConfig{Name: SourceRemote, Source: contentClient})
+//
+//	// 只查本地数据库
+//	NewContentSourceChain(ContentSourceConfig{Name: SourceLocal, Source: NewLocalContentSource(contentRepo)})
+//
+//	// content 服务优先（熔断器打开时直接跳过），失败时查本地数据库
+//	NewContentSourceChain(
+//	    ContentSourceConfig{Name: SourceRemote, Source: contentClient, Timeout: 300 * time.Millisecond, Healthy: healthy},
+//	    ContentSourceConfig{Name: SourceLocal, Source: NewLocalContentSource(contentRepo)},
+//	)
+//
+// 每次从一个来源转到下一个来源，都通过 DegradationPolicy 上报（dependency 为 content），
+// 跳过不健康的来源时 reason 为 unhealthy。所有来源都失败时返回空列表，推荐标记为帖子降级。
+type ContentSourceChain struct {
+	sources []ContentSourceConfig
+}
+
+// NewContentSourceChain 构造函数
+//
+// 来源为 nil、名字为空或重复、超时为负数时返回错误；没有来源时帖子预览总是为空。
+func NewContentSourceChain(sources ...ContentSourceConfig) (*ContentSourceChain, error) {
+	seen := make(map[FallbackSource]bool, len(sources))
+	for _, source := range sources {
+		if source.Source == nil {
+			return nil, fmt.Errorf("content source %q: nil source", source.Name)
+		}
+		if source.Name == "" || source.Name == SourceNone || seen[source.Name] {
+			return nil, fmt.Errorf("content source %q: empty, reserved or duplicate name", source.Name)
+		}
+		if source.Timeout < 0 {
+			return nil, fmt.Errorf("content source %q: negative timeout", source.Name)
+		}
+		seen[source.Name] = true
+	}
+	return &ContentSourceChain{sources: sources}, nil
+}
+
+// WithContentSources 使用指定的帖子预览来源链
+//
+// 不配置时按 DegradationPolicy 的 content（顺序、超时）由 contentClient、contentRepo 组成。
+func WithContentSources(chain *ContentSourceChain) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.contentSources = chain
+	}
+}
+
+// defaultContentSources 辅助函数：按降级策略组成来源链（没有注入的客户端、仓储跳过）
+func defaultContentSources(
+	policy DependencyPolicy,
+	contentClient ContentServiceClient,
+	contentRepo repository.ContentRepository,
+) *ContentSourceChain {
+	chain := &ContentSourceChain{}
+	for _, name := range policy.Order {
+		var source ContentSource
+		switch {
+		case name == SourceRemote && contentClient != nil:
+			source = contentClient
+		case name == SourceLocal && contentRepo != nil:
+			source = NewLocalContentSource(contentRepo)
+		default:
+			continue
+		}
+		chain.sources = append(chain.sources, ContentSourceConfig{
+			Name:    name,
+			Source:  source,
+			Timeout: policy.TimeoutFor(name),
+		})
+	}
+	return chain
+}
+
+// loadFromChain 按顺序尝试来源链中的来源，返回第一个成功的结果
+//
+// 每个来源失败（或者不健康被跳过）时记录一次降级（到下一个来源，或者 SourceNone）；
+// 所有来源都失败时返回最后一个错误，由调用方使用兜底结果。
+func loadFromChain[T any](
+	ctx context.Context,
+	chain *ContentSourceChain,
+	p *DegradationPolicy,
+	load func(ctx context.Context, source ContentSource) (T, error),
+) (T, error) {
+	var zero T
+	err := errEmptyResult
+	for i, source := range chain.sources {
+		if source.Healthy != nil && !source.Healthy() {
+			err = errSourceUnhealthy
+		} else {
+			var result T
+			result, err = loadWithTimeout(ctx, source.Timeout, func(ctx context.Context) (T, error) {
+				return load(ctx, source.Source)
+			})
+			if err == nil {
+				return result, nil
+			}
+		}
+		next := SourceNone
+		if i+1 < len(chain.sources) {
+			next = chain.sources[i+1].Name
+		}
+		p.Fallback(ctx, FallbackContent, source.Name, next, err)
+	}
+	return zero, err
+}
+
+// localContentSource 本地数据库（ContentRepository）→ ContentSource
+type localContentSource struct {
+	repo repository.ContentRepository
+}
+
+// NewLocalContentSource 构造函数：从本地数据库读取帖子
+func NewLocalContentSource(repo repository.ContentRepository) ContentSource {
+	return &localContentSource{repo: repo}
+}
+
+// GetRecentPosts 实现 ContentSource
+func (s *localContentSource) GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error) {
+	domainUserID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.repo.GetRecentPosts(ctx, domainUserID, limit)
+	if err != nil || posts == nil {
+		return nil, err
+	}
+	return postInfos(posts), nil
+}
+
+// GetRecentPostsForUsers 实现 ContentSource
+func (s *localContentSource) GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error) {
+	domainUserIDs := make([]valueobject.UserID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		domainUserID, err := valueobject.NewUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		domainUserIDs = append(domainUserIDs, domainUserID)
+	}
+	posts, err := s.repo.GetRecentPostsForUsers(ctx, domainUserIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64][]*PostInfo, len(posts))
+	for userID, userPosts := range posts {
+		result[userID.Value()] = postInfos(userPosts)
+	}
+	return result, nil
+}
+
+// postInfos 辅助函数：帖子实体 → PostInfo（审核状态使用和内容服务相同的标识）
+func postInfos(posts []*entity.Post) []*PostInfo {
+	result := make([]*PostInfo, 0, len(posts))
+	for _, post := range posts {
+		result = append(result, &PostInfo{
+			PostID:           post.ID().Value(),
+			Content:          post.Content(),
+			CreatedAt:        post.CreatedAt(),
+			ModerationStatus: post.ModerationStatus().String(),
+		})
+	}
+	return result
+}
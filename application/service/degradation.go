@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FallbackDependency 有降级策略的依赖（MetricDependencyFallbacks 的 dependency 标签）
+type FallbackDependency string
+
+const (
+	// FallbackContent 帖子预览：content 服务 → 本地数据库 → 空列表
+	FallbackContent FallbackDependency = "content"
+	// FallbackReasonText 推荐理由文案：配置服务 → 领域对象的本地文案
+	FallbackReasonText FallbackDependency = "reason_text"
+	// FallbackRanking 推荐列表：召回排序 → 过期的快照（serve_stale）→ 请求失败
+	FallbackRanking FallbackDependency = "ranking"
+)
+
+// FallbackSource 依赖的数据来源
+type FallbackSource string
+
+const (
+	SourceRemote   FallbackSource = "remote"   // 远程服务（content 服务、配置服务）
+	SourceLocal    FallbackSource = "local"    // 本地数据库、领域对象的本地逻辑
+	SourceGenerate FallbackSource = "generate" // 重新召回排序
+	SourceStale    FallbackSource = "stale"    // 过期的数据（最近的快照，不管是否超过 max_staleness）
+	SourceNone     FallbackSource = "none"     // 没有可用的来源（调用方使用兜底结果：空列表、本地文案、返回错误）
+)
+
+// errEmptyResult 来源没有返回错误，但结果不能使用（如配置服务返回空文案），按失败处理
+var errEmptyResult = errors.New("empty result")
+
+// errSourceUnhealthy 来源报告自己不健康（如熔断器打开），不调用直接跳过
+var errSourceUnhealthy = errors.New("source unhealthy")
+
+// DependencyPolicy 一个依赖的降级策略
+type DependencyPolicy struct {
+	Order          []FallbackSource                 // 依次尝试的来源（没有配置的来源跳过，如没有 content 服务时直接查本地数据库）
+	Timeout        time.Duration                    // 每个来源的超时（0 表示跟随请求的 context）
+	SourceTimeouts map[FallbackSource]time.Duration // 单独设置某个来源的超时（覆盖 Timeout）
+	ServeStale     bool                             // 所有来源都失败时是否使用过期的数据（目前只有 ranking 支持）
+}
+
+// TimeoutFor 来源的超时（SourceTimeouts 中没有时使用 Timeout）
+func (p DependencyPolicy) TimeoutFor(source FallbackSource) time.Duration {
+	if timeout, ok := p.SourceTimeouts[source]; ok {
+		return timeout
+	}
+	return p.Timeout
+}
+
+// defaultDependencyPolicies 没有配置时的降级策略（和引入 DegradationPolicy 之前的行为相同）
+var defaultDependencyPolicies = map[FallbackDependency]DependencyPolicy{
+	FallbackContent:    {Order: []FallbackSource{SourceRemote, SourceLocal}},
+	FallbackReasonText: {Order: []FallbackSource{SourceRemote, SourceLocal}},
+	FallbackRanking:    {Order: []FallbackSource{SourceGenerate}},
+}
+
+// dependencySources 各依赖可以配置的来源
+var dependencySources = map[FallbackDependency][]FallbackSource{
+	FallbackContent:    {SourceRemote, SourceLocal},
+	FallbackReasonText: {SourceRemote, SourceLocal},
+	FallbackRanking:    {SourceGenerate},
+}
+
+// DegradationPolicy 降级策略：集中决定各依赖失败时怎么办，并统一上报
+//
+// 为什么需要？
+// 降级逻辑以前散落在各处：帖子预览在 getRecentPosts 中先查 content 服务再查本地数据库，
+// 推荐理由在 reasonText 中失败时使用本地文案，快照直出在 rankPage 中……
+// 每处的顺序、超时都写死在代码里，降级发生时有的记日志、有的什么都不记，
+// 事故时很难回答"现在有多少请求在走降级"。
+//
+// DegradationPolicy 按依赖配置（config.yaml 的 degradation）：
+// - 来源的尝试顺序（如关闭 content 服务，只查本地数据库）
+// - 每个来源的超时（慢的来源不拖住整个请求）
+// - 所有来源都失败时是否使用过期的数据
+//
+// 每次降级（从一个来源转到下一个来源）都上报 MetricDependencyFallbacks 并记录一条 warn 日志，
+// 标签和字段在所有依赖上都相同：dependency、from、to、reason（error / timeout / empty / unhealthy）。
+//
+// 为 nil 时使用默认策略，不上报（其他应用服务共用的 reasonText 没有注入时就是这样）。
+type DegradationPolicy struct {
+	policies map[FallbackDependency]DependencyPolicy
+	logger   Logger
+	metrics  Metrics
+}
+
+// NewDegradationPolicy 构造函数：没有列出的依赖使用默认策略
+//
+// 来源不是依赖支持的、重复，或者对不支持的依赖开启 ServeStale 时返回错误。
+// logger、metrics 可以为 nil。
+func NewDegradationPolicy(policies map[FallbackDependency]DependencyPolicy, logger Logger, metrics Metrics) (*DegradationPolicy, error) {
+	merged := make(map[FallbackDependency]DependencyPolicy, len(defaultDependencyPolicies))
+	for dependency, policy := range defaultDependencyPolicies {
+		merged[dependency] = policy
+	}
+	for dependency, policy := range policies {
+		allowed, ok := dependencySources[dependency]
+		if !ok {
+			return nil, fmt.Errorf("degradation: unknown dependency %q", dependency)
+		}
+		if policy.Timeout < 0 {
+			return nil, fmt.Errorf("degradation %s: negative timeout", dependency)
+		}
+		for source, timeout := range policy.SourceTimeouts {
+			if timeout < 0 || !containsSource(allowed, source) {
+				return nil, fmt.Errorf("degradation %s: invalid timeout for source %q", dependency, source)
+			}
+		}
+		if policy.ServeStale && dependency != FallbackRanking {
+			return nil, fmt.Errorf("degradation %s: serve_stale is not supported", dependency)
+		}
+		if len(policy.Order) == 0 {
+			policy.Order = defaultDependencyPolicies[dependency].Order
+		}
+		seen := make(map[FallbackSource]bool, len(policy.Order))
+		for _, source := range policy.Order {
+			if seen[source] || !containsSource(allowed, source) {
+				return nil, fmt.Errorf("degradation %s: invalid or duplicate source %q", dependency, source)
+			}
+			seen[source] = true
+		}
+		merged[dependency] = policy
+	}
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	return &DegradationPolicy{policies: merged, logger: logger, metrics: metrics}, nil
+}
+
+// ParseFallbackDependency 解析配置中的依赖名
+func ParseFallbackDependency(raw string) (FallbackDependency, error) {
+	dependency := FallbackDependency(raw)
+	if _, ok := dependencySources[dependency]; !ok {
+		return "", fmt.Errorf("unknown dependency %q", raw)
+	}
+	return dependency, nil
+}
+
+// WithDegradationPolicy 按降级策略处理依赖失败（为 nil 时使用默认策略，不上报）
+func WithDegradationPolicy(policy *DegradationPolicy) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.degradation = policy
+	}
+}
+
+// For 依赖的降级策略（p 为 nil 时返回默认策略）
+func (p *DegradationPolicy) For(dependency FallbackDependency) DependencyPolicy {
+	if p == nil {
+		return defaultDependencyPolicies[dependency]
+	}
+	return p.policies[dependency]
+}
+
+// Fallback 记录一次降级：from 失败，转而使用 to（p 为 nil 时不记录）
+func (p *DegradationPolicy) Fallback(ctx context.Context, dependency FallbackDependency, from, to FallbackSource, cause error) {
+	if p == nil {
+		return
+	}
+	reason := fallbackReason(cause)
+	p.metrics.IncCounter(MetricDependencyFallbacks, 1, map[string]string{
+		"dependency": string(dependency),
+		"from":       string(from),
+		"to":         string(to),
+		"reason":     reason,
+	})
+	fields := map[string]any{
+		"dependency": string(dependency),
+		"from":       string(from),
+		"to":         string(to),
+		"reason":     reason,
+	}
+	if cause != nil {
+		fields[FieldError] = cause.Error()
+	}
+	p.logger.Log(ctx, LogLevelWarn, "dependency fallback", fields)
+}
+
+// degrade 按依赖的降级策略依次尝试各个来源，返回第一个成功的结果
+//
+// loaders 中没有的来源（没有配置对应的客户端、仓储）直接跳过，不算降级。
+// 每个来源失败时记录一次降级（到下一个可用的来源，或者 SourceNone）；
+// 所有来源都失败时返回最后一个错误，由调用方使用兜底结果。
+func degrade[T any](
+	ctx context.Context,
+	p *DegradationPolicy,
+	dependency FallbackDependency,
+	loaders map[FallbackSource]func(ctx context.Context) (T, error),
+) (T, error) {
+	policy := p.For(dependency)
+	available := make([]FallbackSource, 0, len(policy.Order))
+	for _, source := range policy.Order {
+		if loaders[source] != nil {
+			available = append(available, source)
+		}
+	}
+
+	var zero T
+	err := errEmptyResult
+	for i, source := range available {
+		var result T
+		result, err = loadWithTimeout(ctx, policy.TimeoutFor(source), loaders[source])
+		if err == nil {
+			return result, nil
+		}
+		next := SourceNone
+		if i+1 < len(available) {
+			next = available[i+1]
+		}
+		p.Fallback(ctx, dependency, source, next, err)
+	}
+	return zero, err
+}
+
+// loadWithTimeout 辅助函数：在超时内执行一个来源（timeout 为 0 时跟随 ctx）
+func loadWithTimeout[T any](ctx context.Context, timeout time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return load(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return load(ctx)
+}
+
+// fallbackReason 辅助函数：降级原因（MetricDependencyFallbacks 的 reason 标签）
+func fallbackReason(cause error) string {
+	switch {
+	case errors.Is(cause, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(cause, errEmptyResult):
+		return "empty"
+	case errors.Is(cause, errSourceUnhealthy):
+		return "unhealthy"
+	default:
+		return "error"
+	}
+}
+
+// containsSource 辅助函数
+func containsSource(sources []FallbackSource, source FallbackSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fallbackMetrics 测试用监控：记录每次降级的 from→to/reason
+type fallbackMetrics struct {
+	fallbacks []string
+}
+
+func (m *fallbackMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	if name == MetricDependencyFallbacks {
+		m.fallbacks = append(m.fallbacks, labels["dependency"]+":"+labels["from"]+"→"+labels["to"]+"/"+labels["reason"])
+	}
+}
+
+func (m *fallbackMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
+
+func TestDegrade(t *testing.T) {
+	metrics := &fallbackMetrics{}
+	policy, err := NewDegradationPolicy(map[FallbackDependency]DependencyPolicy{
+		FallbackContent: {Order: []FallbackSource{SourceRemote, SourceLocal}, Timeout: 10 * time.Millisecond},
+	}, nil, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	slow := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	local := func(context.Context) (string, error) { return "local", nil }
+
+	// 远程服务超时，降级到本地
+	got, err := degrade(ctx, policy, FallbackContent, map[FallbackSource]func(context.Context) (string, error){
+		SourceRemote: slow,
+		SourceLocal:  local,
+	})
+	if err != nil || got != "local" {
+		t.Fatalf("degrade = %q, %v; want local", got, err)
+	}
+
+	// 没有配置的来源跳过，不算降级
+	if got, err := degrade(ctx, policy, FallbackContent, map[FallbackSource]func(context.Context) (string, error){
+		SourceLocal: local,
+	}); err != nil || got != "local" {
+		t.Fatalf("degrade = %q, %v; want local", got, err)
+	}
+
+	// 所有来源都失败：返回最后一个错误
+	failed := errors.New("db down")
+	_, err = degrade(ctx, policy, FallbackContent, map[FallbackSource]func(context.Context) (string, error){
+		SourceRemote: func(context.Context) (string, error) { return "", errEmptyResult },
+		SourceLocal:  func(context.Context) (string, error) { return "", failed },
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+
+	want := []string{
+		"content:remote→local/timeout",
+		"content:remote→local/empty",
+		"content:local→none/error",
+	}
+	if len(metrics.fallbacks) != len(want) {
+		t.Fatalf("fallbacks = %v, want %v", metrics.fallbacks, want)
+	}
+	for i := range want {
+		if metrics.fallbacks[i] != want[i] {
+			t.Errorf("fallbacks[%d] = %s, want %s", i, metrics.fallbacks[i], want[i])
+		}
+	}
+}
+
+func TestNewDegradationPolicy_Rejects(t *testing.T) {
+	invalid := []map[FallbackDependency]DependencyPolicy{
+		{"cache": {}},
+		{FallbackContent: {Order: []FallbackSource{SourceRemote, SourceRemote}}},
+		{FallbackReasonText: {Order: []FallbackSource{SourceStale}}},
+		{FallbackContent: {ServeStale: true}},
+		{FallbackRanking: {Timeout: -time.Second}},
+	}
+	for _, policies := range invalid {
+		if _, err := NewDegradationPolicy(policies, nil, nil); err == nil {
+			t.Errorf("NewDegradationPolicy(%v) succeeded, want error", policies)
+		}
+	}
+}
+
+// staticContentSource 测试用帖子来源：每个用户返回同一篇帖子（err 不为 nil 时返回错误）
+type staticContentSource struct {
+	postID int64
+	err    error
+}
+
+func (s staticContentSource) GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []*PostInfo{{PostID: s.postID}}, nil
+}
+
+func (s staticContentSource) GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error) {
+	return nil, ErrBatchUnavailable
+}
+
+func TestContentSourceChain(t *testing.T) {
+	metrics := &fallbackMetrics{}
+	policy, err := NewDegradationPolicy(nil, nil, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthy := false
+	chain, err := NewContentSourceChain(
+		ContentSourceConfig{Name: SourceLocal, Source: staticContentSource{err: errors.New("db down")}},
+		ContentSourceConfig{Name: SourceRemote, Source: staticContentSource{postID: 1}, Healthy: func() bool { return healthy }},
+		ContentSourceConfig{Name: "snapshot", Source: staticContentSource{postID: 2}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &RecommendationService{contentSources: chain, degradation: policy, metrics: noopMetrics{}, contentSafety: DefaultContentSafetyPolicy()}
+
+	// 按配置的顺序尝试：本地失败，远程不健康被跳过，使用第三个来源
+	posts, ok := s.getRecentPosts(context.Background(), 7, 3, "")
+	if !ok || len(posts) != 1 || posts[0].PostID != 2 {
+		t.Fatalf("getRecentPosts = %+v, %v; want post 2", posts, ok)
+	}
+	// 远程恢复健康
+	healthy = true
+	if posts, ok := s.getRecentPosts(context.Background(), 7, 3, ""); !ok || len(posts) != 1 || posts[0].PostID != 1 {
+		t.Fatalf("getRecentPosts = %+v, %v; want post 1", posts, ok)
+	}
+
+	want := []string{
+		"content:local→remote/error",
+		"content:remote→snapshot/unhealthy",
+		"content:local→remote/error",
+	}
+	if len(metrics.fallbacks) != len(want) {
+		t.Fatalf("fallbacks = %v, want %v", metrics.fallbacks, want)
+	}
+	for i := range want {
+		if metrics.fallbacks[i] != want[i] {
+			t.Errorf("fallbacks[%d] = %s, want %s", i, metrics.fallbacks[i], want[i])
+		}
+	}
+
+	if _, err := NewContentSourceChain(
+		ContentSourceConfig{Name: SourceRemote, Source: staticContentSource{}},
+		ContentSourceConfig{Name: SourceRemote, Source: staticContentSource{}},
+	); err == nil {
+		t.Error("NewContentSourceChain with duplicate names: want error")
+	}
+}
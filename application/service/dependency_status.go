@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"service/application/dto"
+)
+
+// HealthCheck 依赖的健康检查（如数据库 Ping），返回 nil 表示健康
+type HealthCheck func(ctx context.Context) error
+
+// Dependency 一个已装配的依赖（在组合根中按生效的装配登记）
+type Dependency struct {
+	Name           string
+	Type           string
+	Implementation string
+	Target         string
+
+	// Check 健康检查（可以为 nil：mock、内存实现不需要检查）
+	Check HealthCheck
+
+	// CircuitBreakerState 熔断器状态（可以为 nil：没有熔断器）
+	CircuitBreakerState func() string
+}
+
+// DependencyStatusService 应用服务：报告每个已装配依赖的状态
+//
+// 为什么需要？
+// 一个实例变慢或报错时，值班同学需要马上知道是哪个下游出了问题、
+// 这个实例到底连的是哪个库 / 哪个服务（环境 profile 决定的实现和地址）。
+//
+// 健康检查：
+// 查询时按需执行，距离上次检查不到 minInterval 时直接返回上次的结果，
+// 诊断接口被频繁刷新时不会放大对下游的压力。
+// 所有依赖并发检查，每个检查最多 checkTimeout。
+type DependencyStatusService struct {
+	dependencies []Dependency
+	minInterval  time.Duration
+	checkTimeout time.Duration
+
+	mu      sync.Mutex
+	results []checkResult // 和 dependencies 一一对应
+	checked time.Time
+	now     func() time.Time
+}
+
+// checkResult 一次健康检查的结果
+type checkResult struct {
+	err       error
+	checkedAt time.Time
+	latency   time.Duration
+}
+
+// NewDependencyStatusService 构造函数
+//
+// 参数：
+// - dependencies: 已装配的依赖（按报告中的顺序）
+// - minInterval: 两次健康检查的最小间隔
+// - checkTimeout: 单个健康检查的超时时间
+func NewDependencyStatusService(dependencies []Dependency, minInterval, checkTimeout time.Duration) *DependencyStatusService {
+	return &DependencyStatusService{
+		dependencies: dependencies,
+		minInterval:  minInterval,
+		checkTimeout: checkTimeout,
+		results:      make([]checkResult, len(dependencies)),
+		now:          time.Now,
+	}
+}
+
+// Status 返回每个依赖的状态（必要时先执行一轮健康检查）
+func (s *DependencyStatusService) Status(ctx context.Context) []*dto.DependencyStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.checked.IsZero() || s.now().Sub(s.checked) >= s.minInterval {
+		s.checkAll(ctx)
+	}
+
+	statuses := make([]*dto.DependencyStatus, 0, len(s.dependencies))
+	for i, dependency := range s.dependencies {
+		status := &dto.DependencyStatus{
+			Name:           dependency.Name,
+			Type:           dependency.Type,
+			Implementation: dependency.Implementation,
+			Target:         dependency.Target,
+			Health:         dto.HealthUnchecked,
+			CircuitBreaker: dto.CircuitBreakerNone,
+		}
+		if dependency.Check != nil {
+			result := s.results[i]
+			status.Health = dto.HealthOK
+			status.CheckedAt = result.checkedAt
+			status.Latency = result.latency
+			if result.err != nil {
+				status.Health = dto.HealthFailing
+				status.LastError = result.err.Error()
+			}
+		}
+		if dependency.CircuitBreakerState != nil {
+			status.CircuitBreaker = dependency.CircuitBreakerState()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkAll 辅助方法：并发执行所有健康检查（调用方持有锁）
+func (s *DependencyStatusService) checkAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i, dependency := range s.dependencies {
+		if dependency.Check == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+			defer cancel()
+			start := s.now()
+			err := check(checkCtx)
+			s.results[i] = checkResult{err: err, checkedAt: start, latency: s.now().Sub(start)}
+		}(i, dependency.Check)
+	}
+	wg.Wait()
+	s.checked = s.now()
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/dto"
+)
+
+func TestDependencyStatusService_Status(t *testing.T) {
+	ctx := context.Background()
+	checks := 0
+	dbErr := errors.New("connection refused")
+	svc := NewDependencyStatusService([]Dependency{
+		{Name: "social_graph", Implementation: "mysql", Check: func(context.Context) error {
+			checks++
+			return dbErr
+		}},
+		{Name: "trust", Implementation: "http", Check: func(context.Context) error { return nil },
+			CircuitBreakerState: func() string { return "open" }},
+		{Name: "impressions", Implementation: "memory"},
+	}, 10*time.Second, time.Second)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	statuses := svc.Status(ctx)
+	want := []struct{ health, breaker string }{
+		{dto.HealthFailing, dto.CircuitBreakerNone},
+		{dto.HealthOK, "open"},
+		{dto.HealthUnchecked, dto.CircuitBreakerNone},
+	}
+	for i, w := range want {
+		if statuses[i].Health != w.health || statuses[i].CircuitBreaker != w.breaker {
+			t.Errorf("%s: health=%s breaker=%s, want %s %s",
+				statuses[i].Name, statuses[i].Health, statuses[i].CircuitBreaker, w.health, w.breaker)
+		}
+	}
+	if statuses[0].LastError != dbErr.Error() {
+		t.Errorf("last error = %q, want %q", statuses[0].LastError, dbErr.Error())
+	}
+
+	// 间隔内再次查询返回上次的结果，不重复检查
+	svc.Status(ctx)
+	if checks != 1 {
+		t.Fatalf("checks = %d, want 1 within min interval", checks)
+	}
+	now = now.Add(10 * time.Second)
+	svc.Status(ctx)
+	if checks != 2 {
+		t.Fatalf("checks = %d, want 2 after min interval", checks)
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+// Experiment 一个 A/B 实验：不同分组的用户使用不同的推荐策略、打分权重、理由文案
+//
+// 和交错实验（InterleavingExperiment）的区别：
+// 交错实验让同一个用户同时看到两个排序器的结果，只能比较排序；
+// A/B 实验把用户分到不同的分组，每个分组看到完整的另一套列表，
+// 可以比较召回策略（候选来源、过滤器）和理由文案这些交错实验比较不了的东西。
+//
+// 用户分桶：按"实验名 + 用户"哈希到 0~99，依次落到各分组的比例区间中，
+// 落在所有分组之外的用户不参与实验（和没有实验时完全相同）。
+// Sticky 为 true 时保存第一次分到的分组：之后调整分组比例（如放量），已经参与实验的用户不换组。
+type Experiment struct {
+	Name     string              // 实验名（写入曝光记录、领域事件，最长 32 个字符）
+	Surfaces []dto.Surface       // 参与实验的展示场景，为空时所有场景
+	Variants []ExperimentVariant // 分组（比例之和不超过 100）
+	Sticky   bool                // 保存用户的分组（需要 ExperimentAssignmentRepository）
+}
+
+// ExperimentVariant 实验的一个分组（各项为零值时和不在实验中相同，通常用作对照组）
+type ExperimentVariant struct {
+	Name    string // 分组名（最长 32 个字符）
+	Percent int    // 分到这个分组的用户比例（0~100）
+
+	// Generator 这个分组的推荐策略（候选来源、过滤器），为 nil 时使用场景的生成器
+	Generator *domainService.RecommendationGenerator
+	// Ranker 这个分组的排序器（按分组的打分权重排序），为 nil 时使用场景的排序
+	Ranker domainService.Ranker
+	// ReasonTextVariant 理由文案的版本（配置服务按版本返回文案），为空时使用默认文案
+	ReasonTextVariant string
+}
+
+// covers 实验是否在展示场景上进行
+func (e *Experiment) covers(surface dto.Surface) bool {
+	return len(e.Surfaces) == 0 || slices.Contains(e.Surfaces, surface)
+}
+
+// bucket 用户在这个实验中的桶（0~99，不同实验的分桶互相独立）
+func (e *Experiment) bucket(userID valueobject.UserID) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", e.Name, userID.Value())
+	return int(h.Sum32() % 100)
+}
+
+// variantForBucket 桶落在哪个分组（落在所有分组之外时为 nil）
+func (e *Experiment) variantForBucket(bucket int) *ExperimentVariant {
+	upper := 0
+	for i := range e.Variants {
+		upper += e.Variants[i].Percent
+		if bucket < upper {
+			return &e.Variants[i]
+		}
+	}
+	return nil
+}
+
+// variantNamed 按名称查找分组（保存的分组已经不在实验中时为 nil）
+func (e *Experiment) variantNamed(name string) *ExperimentVariant {
+	for i := range e.Variants {
+		if e.Variants[i].Name == name {
+			return &e.Variants[i]
+		}
+	}
+	return nil
+}
+
+// validate 检查实验配置（名称、分组比例）
+func (e *Experiment) validate() error {
+	if len(e.Variants) == 0 {
+		return fmt.Errorf("experiment %q: no variants", e.Name)
+	}
+	total := 0
+	seen := make(map[string]bool, len(e.Variants))
+	for _, variant := range e.Variants {
+		if _, err := valueobject.NewExperimentAssignment(e.Name, variant.Name); err != nil {
+			return err
+		}
+		if seen[variant.Name] {
+			return fmt.Errorf("experiment %q: duplicate variant %q", e.Name, variant.Name)
+		}
+		seen[variant.Name] = true
+		if variant.Percent < 0 {
+			return fmt.Errorf("experiment %q: variant %q has negative percent", e.Name, variant.Name)
+		}
+		total += variant.Percent
+	}
+	if total > 100 {
+		return fmt.Errorf("experiment %q: variants add up to %d%%", e.Name, total)
+	}
+	return nil
+}
+
+// ExperimentAssigner 为每次请求确定用户所在的 A/B 实验分组
+//
+// 一个展示场景同时最多进行一个实验（构造时检查）：
+// 同一个场景上的两个实验都会改变列表，效果无法区分。
+type ExperimentAssigner struct {
+	experiments []*Experiment
+	repo        repository.ExperimentAssignmentRepository // 保存的分组（为 nil 时只按哈希分组）
+}
+
+// NewExperimentAssigner 构造函数（实验配置错误时返回错误）
+//
+// repo 可以为 nil：此时 Sticky 不生效，始终按哈希分组。
+func NewExperimentAssigner(experiments []*Experiment, repo repository.ExperimentAssignmentRepository) (*ExperimentAssigner, error) {
+	names := make(map[string]bool, len(experiments))
+	for i, experiment := range experiments {
+		if err := experiment.validate(); err != nil {
+			return nil, err
+		}
+		if names[experiment.Name] {
+			return nil, fmt.Errorf("duplicate experiment %q", experiment.Name)
+		}
+		names[experiment.Name] = true
+		for _, other := range experiments[:i] {
+			if overlaps(experiment, other) {
+				return nil, fmt.Errorf("experiments %q and %q run on the same surface", other.Name, experiment.Name)
+			}
+		}
+	}
+	return &ExperimentAssigner{experiments: experiments, repo: repo}, nil
+}
+
+// overlaps 辅助函数：两个实验是否有相同的展示场景
+func overlaps(a, b *Experiment) bool {
+	if len(a.Surfaces) == 0 || len(b.Surfaces) == 0 {
+		return true
+	}
+	for _, surface := range a.Surfaces {
+		if slices.Contains(b.Surfaces, surface) {
+			return true
+		}
+	}
+	return false
+}
+
+// assign 用户在展示场景上的实验分组（不参与任何实验时为 nil）
+//
+// 依次使用：保存的分组（Sticky，并且分组仍在实验中）→ 哈希分组（Sticky 时保存下来）。
+// 分组仓储查询、保存失败时仍然返回哈希分组，同时返回错误（调用方只记日志）。
+func (a *ExperimentAssigner) assign(ctx context.Context, userID valueobject.UserID, surface dto.Surface) (*experimentArm, error) {
+	if a == nil {
+		return nil, nil
+	}
+	idx := slices.IndexFunc(a.experiments, func(e *Experiment) bool { return e.covers(surface) })
+	if idx < 0 {
+		return nil, nil
+	}
+	experiment := a.experiments[idx]
+	sticky := experiment.Sticky && a.repo != nil
+
+	var lookupErr error
+	if sticky {
+		saved, found, err := a.repo.FindAssignment(ctx, experiment.Name, userID)
+		if err == nil && found {
+			if variant := experiment.variantNamed(saved.Variant()); variant != nil {
+				return newExperimentArm(experiment, variant), nil
+			}
+		}
+		lookupErr = err
+	}
+
+	variant := experiment.variantForBucket(experiment.bucket(userID))
+	if variant == nil {
+		return nil, lookupErr
+	}
+	arm := newExperimentArm(experiment, variant)
+	if sticky && lookupErr == nil {
+		// 查询失败时不保存：可能已经有保存的分组，只是这次没有查到
+		return arm, a.repo.SaveAssignment(ctx, userID, arm.assignment)
+	}
+	return arm, lookupErr
+}
+
+// experimentArm 一次请求所在的实验分组
+//
+// 方法在 arm 为 nil（不参与实验）时返回默认行为。
+type experimentArm struct {
+	assignment valueobject.ExperimentAssignment
+	variant    *ExperimentVariant
+}
+
+// newExperimentArm 构造函数（实验配置已经检查过，名称一定有效）
+func newExperimentArm(experiment *Experiment, variant *ExperimentVariant) *experimentArm {
+	assignment, _ := valueobject.NewExperimentAssignment(experiment.Name, variant.Name)
+	return &experimentArm{assignment: assignment, variant: variant}
+}
+
+// listSource 分组使用的生成器和推荐策略名（列表缓存的 key 按分组区分）
+func (a *experimentArm) listSource(
+	generator *domainService.RecommendationGenerator,
+	strategy string,
+) (*domainService.RecommendationGenerator, string) {
+	if a == nil || a.variant.Generator == nil {
+		return generator, strategy
+	}
+	return a.variant.Generator, strategy + "@" + a.assignment.String()
+}
+
+// ranks 分组是否有自己的排序器
+func (a *experimentArm) ranks() bool {
+	return a != nil && a.variant.Ranker != nil
+}
+
+// rank 按分组的排序器排序（没有排序器时原样返回）
+func (a *experimentArm) rank(ranked []*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	if !a.ranks() {
+		return ranked
+	}
+	return a.variant.Ranker.Rank(ranked)
+}
+
+// reasonTextVariant 理由文案的版本（为空时使用默认文案）
+func (a *experimentArm) reasonTextVariant() string {
+	if a == nil {
+		return ""
+	}
+	return a.variant.ReasonTextVariant
+}
+
+// stamp 在响应元数据中写入实验名、分组名
+func (a *experimentArm) stamp(metadata *dto.ResponseMetadata) {
+	if a == nil {
+		return
+	}
+	metadata.Experiment = a.assignment.Experiment()
+	metadata.Variant = a.assignment.Variant()
+}
+
+// experimentArmKey context 中实验分组的 key
+type experimentArmKey struct{}
+
+// withExperimentArm 把实验分组放进 context（arm 为 nil 时原样返回）
+//
+// 和租户一样随 context 传递：排序、理由文案、曝光记录在不同的辅助方法中，
+// 不需要逐层增加参数；没有分组的调用路径（预热、分栏推荐等）自然按不参与实验处理。
+func withExperimentArm(ctx context.Context, arm *experimentArm) context.Context {
+	if arm == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, experimentArmKey{}, arm)
+}
+
+// experimentArmFrom context 中的实验分组（没有时为 nil）
+func experimentArmFrom(ctx context.Context) *experimentArm {
+	arm, _ := ctx.Value(experimentArmKey{}).(*experimentArm)
+	return arm
+}
+
+// WithExperiments 开启 A/B 实验：按用户所在的分组选择推荐策略、打分权重和理由文案
+//
+// 分组写入响应元数据、曝光记录和反馈事件，效果分析按分组对比。
+// 只有普通的推荐请求（GetFollowingBasedRecommendations 的非分页请求）参与实验；
+// 分页请求的列表在第一页生成之后固定，混合推荐、分栏推荐有各自的策略，都不参与。
+func WithExperiments(assigner *ExperimentAssigner) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.experiments = assigner
+	}
+}
+
+// assignExperiment 辅助方法：确定用户的实验分组，放进 context
+//
+// 分组仓储失败只记日志（仍然按哈希分组），不影响推荐。
+func (s *RecommendationService) assignExperiment(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+) context.Context {
+	arm, err := s.experiments.assign(ctx, viewerID, surface)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "experiment assignment store failed", map[string]any{
+			FieldUserID: viewerID.Value(),
+			FieldError:  err.Error(),
+		})
+	}
+	if arm == nil {
+		return ctx
+	}
+	s.metrics.IncCounter(MetricExperimentRequests, 1, map[string]string{
+		"experiment": arm.assignment.Experiment(),
+		"variant":    arm.assignment.Variant(),
+	})
+	return withExperimentArm(ctx, arm)
+}
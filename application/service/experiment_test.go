@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// fakeAssignmentRepo 测试用实验分组：已经有分组时不覆盖
+type fakeAssignmentRepo struct {
+	saved map[string]valueobject.ExperimentAssignment
+}
+
+func (r *fakeAssignmentRepo) key(experiment string, userID valueobject.UserID) string {
+	return experiment + ":" + userID.String()
+}
+
+func (r *fakeAssignmentRepo) FindAssignment(ctx context.Context, experiment string, userID valueobject.UserID) (valueobject.ExperimentAssignment, bool, error) {
+	assignment, ok := r.saved[r.key(experiment, userID)]
+	return assignment, ok, nil
+}
+
+func (r *fakeAssignmentRepo) SaveAssignment(ctx context.Context, userID valueobject.UserID, assignment valueobject.ExperimentAssignment) error {
+	if _, ok := r.saved[r.key(assignment.Experiment(), userID)]; !ok {
+		r.saved[r.key(assignment.Experiment(), userID)] = assignment
+	}
+	return nil
+}
+
+func (r *fakeAssignmentRepo) ListUserAssignments(ctx context.Context, userID valueobject.UserID) ([]valueobject.ExperimentAssignment, error) {
+	var result []valueobject.ExperimentAssignment
+	for key, assignment := range r.saved {
+		if strings.HasSuffix(key, ":"+userID.String()) {
+			result = append(result, assignment)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Experiment() < result[j].Experiment() })
+	return result, nil
+}
+
+func (r *fakeAssignmentRepo) DeleteUserAssignments(ctx context.Context, userID valueobject.UserID) error {
+	for key := range r.saved {
+		if strings.HasSuffix(key, ":"+userID.String()) {
+			delete(r.saved, key)
+		}
+	}
+	return nil
+}
+
+func TestExperimentAssigner(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeAssignmentRepo{saved: make(map[string]valueobject.ExperimentAssignment)}
+	experiment := &Experiment{
+		Name:     "copy",
+		Surfaces: []dto.Surface{dto.SurfaceHomeFeed},
+		Sticky:   true,
+		Variants: []ExperimentVariant{{Name: "control", Percent: 20}, {Name: "short", Percent: 20}},
+	}
+	assigner, err := NewExperimentAssigner([]*Experiment{experiment}, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 分组比例大致符合配置，其余用户不参与；同一个用户每次分到同一个分组
+	counts := make(map[string]int)
+	for id := int64(1); id <= 1000; id++ {
+		userID, _ := valueobject.NewUserID(id)
+		arm, err := assigner.assign(ctx, userID, dto.SurfaceHomeFeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, _ := assigner.assign(ctx, userID, dto.SurfaceHomeFeed)
+		if arm == nil {
+			counts[""]++
+			if again != nil {
+				t.Fatalf("user %d: assigned on second request only", id)
+			}
+			continue
+		}
+		counts[arm.assignment.Variant()]++
+		if again == nil || again.assignment != arm.assignment {
+			t.Fatalf("user %d: assignment changed between requests", id)
+		}
+	}
+	for variant, want := range map[string]int{"control": 200, "short": 200, "": 600} {
+		if got := counts[variant]; got < want-60 || got > want+60 {
+			t.Errorf("variant %q: %d users, want about %d", variant, got, want)
+		}
+	}
+
+	// Sticky：放量之后，保存过分组的用户不换组
+	// （short 的桶是 20~39，按 50/50 重新分组时会落到 control）
+	var userID valueobject.UserID
+	for id := int64(1); ; id++ {
+		userID, _ = valueobject.NewUserID(id)
+		if bucket := experiment.bucket(userID); bucket >= 20 && bucket < 40 {
+			break
+		}
+	}
+	experiment.Variants[0].Percent, experiment.Variants[1].Percent = 50, 50
+	if after, _ := assigner.assign(ctx, userID, dto.SurfaceHomeFeed); after == nil || after.assignment.Variant() != "short" {
+		t.Errorf("sticky assignment changed after reallocation: %+v", after)
+	}
+
+	// 实验之外的场景不参与
+	if arm, _ := assigner.assign(ctx, userID, dto.SurfacePush); arm != nil {
+		t.Errorf("push: assigned to %v, want no experiment", arm.assignment)
+	}
+
+	// 配置错误：比例之和超过 100、同一个场景上有两个实验
+	invalid := [][]*Experiment{
+		{{Name: "a", Variants: []ExperimentVariant{{Name: "x", Percent: 60}, {Name: "y", Percent: 60}}}},
+		{experiment, {Name: "b", Variants: []ExperimentVariant{{Name: "x", Percent: 10}}}},
+	}
+	for i, experiments := range invalid {
+		if _, err := NewExperimentAssigner(experiments, nil); err == nil {
+			t.Errorf("invalid config %d: no error", i)
+		}
+	}
+}
+
+// reverseRanker 测试用排序器：分数升序
+type reverseRanker struct{}
+
+func (reverseRanker) Rank(candidates []*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	ranked := make([]*aggregate.UserRecommendation, 0, len(candidates))
+	for i := len(candidates) - 1; i >= 0; i-- {
+		ranked = append(ranked, candidates[i])
+	}
+	return ranked
+}
+
+// variantReasonClient 测试用配置服务：只有 short 版本的文案
+type variantReasonClient struct{}
+
+func (variantReasonClient) GetReasonText(ctx context.Context, reasonType string, count int, locale string, variant string) (string, error) {
+	if variant == "short" {
+		return "short", nil
+	}
+	return "", nil
+}
+
+func (variantReasonClient) GetReasonTexts(ctx context.Context, requests []ReasonTextRequest) (map[ReasonTextRequest]string, error) {
+	return nil, ErrBatchUnavailable
+}
+
+// recordingImpressionRepo 测试用曝光记录：保存写入的曝光
+type recordingImpressionRepo struct {
+	fakeImpressionRepo
+	recorded []*entity.Impression
+}
+
+func (r *recordingImpressionRepo) RecordImpressions(ctx context.Context, impressions []*entity.Impression) error {
+	r.recorded = append(r.recorded, impressions...)
+	return nil
+}
+
+func TestGetFollowingBasedRecommendations_Experiment(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	s.reasonConfigClient = variantReasonClient{}
+	impressions := &recordingImpressionRepo{}
+	WithImpressionRepository(impressions)(s)
+	assigner, err := NewExperimentAssigner([]*Experiment{{
+		Name:     "copy",
+		Variants: []ExperimentVariant{{Name: "short", Percent: 100, Ranker: reverseRanker{}, ReasonTextVariant: "short"}},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	WithExperiments(assigner)(s)
+
+	resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Metadata.Experiment != "copy" || resp.Metadata.Variant != "short" {
+		t.Errorf("metadata = %+v, want experiment copy / variant short", resp.Metadata)
+	}
+	if len(resp.Recommendations) == 0 || resp.Recommendations[len(resp.Recommendations)-1].UserID != 10 {
+		t.Fatalf("recommendations not ranked by the variant's ranker: %+v", resp.Recommendations)
+	}
+	for _, rec := range resp.Recommendations {
+		if rec.Reason != "short" {
+			t.Errorf("user %d: reason = %q, want the variant's copy", rec.UserID, rec.Reason)
+		}
+	}
+	if len(impressions.recorded) != len(resp.Recommendations) {
+		t.Fatalf("recorded %d impressions, want %d", len(impressions.recorded), len(resp.Recommendations))
+	}
+	for _, impression := range impressions.recorded {
+		if impression.Variant().String() != "copy/short" {
+			t.Errorf("impression variant = %q, want copy/short", impression.Variant())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ExposureQuota 每日曝光配额：每个用户每天最多看到 N 个不同的推荐对象
+//
+// 为什么需要曝光配额？
+// 用户反复刷新推荐模块时，每次都会看到新的推荐，
+// 一天下来可能看到上百个不同的账号，既打扰用户，也稀释了推荐质量。
+//
+// 配额规则（跨所有展示场景统一计算）：
+// - 今天已经看到过的推荐对象：再次展示不消耗配额
+// - 没看到过的推荐对象：每展示一个消耗 1 个配额
+// - 配额用完后：只能展示今天已经看到过的推荐对象
+//
+// 为什么在应用层，而不是领域层？
+// 配额是面向展示的运营策略（防止过度曝光），依赖曝光记录这种"使用数据"，
+// 不影响推荐本身的生成规则，所以由应用层在组装响应前执行。
+//
+// 实际场景：
+//
+//	配额 N = 20，用户今天已经看到 18 个不同的人
+//	本次排序结果：[A(今天看过), B, C, D, ...]
+//	可以展示：A（不消耗）、B、C（消耗 2 个配额，配额用完）
+//	D 以及之后的新面孔：今天不再展示
+type ExposureQuota struct {
+	maxDistinctPerDay int
+	location          *time.Location // "一天"按哪个时区计算
+}
+
+// NewExposureQuota 构造函数
+//
+// 参数：
+// - maxDistinctPerDay: 每天最多曝光的不同推荐对象数
+// - location: 计算自然日使用的时区（nil 表示服务器本地时区）
+func NewExposureQuota(maxDistinctPerDay int, location *time.Location) *ExposureQuota {
+	if location == nil {
+		location = time.Local
+	}
+	return &ExposureQuota{
+		maxDistinctPerDay: maxDistinctPerDay,
+		location:          location,
+	}
+}
+
+// WindowStart 查询方法：当前配额周期的开始时间（当天零点）
+func (q *ExposureQuota) WindowStart(now time.Time) time.Time {
+	local := now.In(q.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.location)
+}
+
+// Select 业务行为：按配额从排好序的推荐中选出可以展示的推荐
+//
+// 参数：
+// - ranked: 按分数降序排列的推荐
+// - shownToday: 今天已经曝光过的推荐对象
+// - limit: 最多返回多少个
+//
+// 返回：
+// - 可以展示的推荐（保持原有顺序）
+// - 因为配额不足被压制的推荐数
+func (q *ExposureQuota) Select(
+	ranked []*aggregate.UserRecommendation,
+	shownToday map[valueobject.UserID]bool,
+	limit int,
+) ([]*aggregate.UserRecommendation, int) {
+	remaining := q.maxDistinctPerDay - len(shownToday)
+
+	selected := make([]*aggregate.UserRecommendation, 0, limit)
+	suppressed := 0
+	for _, rec := range ranked {
+		if len(selected) >= limit {
+			break
+		}
+
+		// 今天看过的：不消耗配额
+		if shownToday[rec.TargetUserID()] {
+			selected = append(selected, rec)
+			continue
+		}
+
+		// 新面孔：消耗配额
+		if remaining > 0 {
+			selected = append(selected, rec)
+			remaining--
+			continue
+		}
+		suppressed++
+	}
+	return selected, suppressed
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// quotaMetrics 测试用监控：累计被配额压制的推荐数
+type quotaMetrics struct {
+	suppressed int
+}
+
+func (m *quotaMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	if name == MetricQuotaSuppressed {
+		m.suppressed += delta
+	}
+}
+
+func (m *quotaMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
+
+// shownTargetsRepo 测试用曝光记录：返回预设的今天已曝光对象（或错误）
+type shownTargetsRepo struct {
+	fakeImpressionRepo
+	targets []valueobject.UserID
+	err     error
+}
+
+func (r *shownTargetsRepo) GetImpressedTargets(ctx context.Context, viewerID valueobject.UserID, since time.Time) ([]valueobject.UserID, error) {
+	return r.targets, r.err
+}
+
+// quotaRecommendations 测试用推荐：每个目标一条，理由相同（分数相同）
+func quotaRecommendations(t *testing.T, targets ...int64) []*aggregate.UserRecommendation {
+	t.Helper()
+	result := make([]*aggregate.UserRecommendation, 0, len(targets))
+	for _, target := range userIDs(targets...) {
+		rec, err := aggregate.NewUserRecommendation(target, valueobject.NewFollowedByFollowingReason(userIDs(2)), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, rec)
+	}
+	return result
+}
+
+func targetValues(recs []*aggregate.UserRecommendation) []int64 {
+	result := make([]int64, 0, len(recs))
+	for _, rec := range recs {
+		result = append(result, rec.TargetUserID().Value())
+	}
+	return result
+}
+
+func TestExposureQuota_Select(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxPerDay      int
+		ranked         []int64
+		shownToday     []int64
+		limit          int
+		want           []int64
+		wantSuppressed int
+	}{
+		{"empty pool", 3, nil, nil, 10, []int64{}, 0},
+		{"under quota", 3, []int64{10, 11}, nil, 10, []int64{10, 11}, 0},
+		{"new faces capped by quota", 2, []int64{10, 11, 12, 13}, nil, 10, []int64{10, 11}, 2},
+		{"shown today do not consume quota", 1, []int64{10, 11, 12}, []int64{10, 12}, 10, []int64{10, 12}, 1},
+		{"quota already used up", 2, []int64{10, 11, 12}, []int64{12, 20}, 10, []int64{12}, 2},
+		{"ties keep the ranked order", 1, []int64{11, 10}, nil, 10, []int64{11}, 1},
+		{"limit caps before quota", 5, []int64{10, 11, 12}, nil, 2, []int64{10, 11}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shownToday := make(map[valueobject.UserID]bool, len(tt.shownToday))
+			for _, id := range userIDs(tt.shownToday...) {
+				shownToday[id] = true
+			}
+			selected, suppressed := NewExposureQuota(tt.maxPerDay, time.UTC).Select(quotaRecommendations(t, tt.ranked...), shownToday, tt.limit)
+			if got := targetValues(selected); !slices.Equal(got, tt.want) || suppressed != tt.wantSuppressed {
+				t.Errorf("Select = %v (suppressed %d), want %v (suppressed %d)", got, suppressed, tt.want, tt.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestExposureQuota_WindowStart(t *testing.T) {
+	shanghai := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC) // 上海已经是 5 月 2 日 04:00
+	got := NewExposureQuota(1, shanghai).WindowStart(now)
+	if want := time.Date(2024, 5, 2, 0, 0, 0, 0, shanghai); !got.Equal(want) {
+		t.Errorf("WindowStart = %v, want %v", got, want)
+	}
+}
+
+func TestApplyExposureQuota(t *testing.T) {
+	viewer := userIDs(1)[0]
+	tests := []struct {
+		name           string
+		repo           *shownTargetsRepo
+		want           []int64
+		wantSuppressed int
+	}{
+		{"quota enforced", &shownTargetsRepo{targets: userIDs(12)}, []int64{10, 12}, 1},
+		{"nothing shown today", &shownTargetsRepo{}, []int64{10, 11}, 1},
+		{"lookup failure skips the quota", &shownTargetsRepo{err: errors.New("db down")}, []int64{10, 11, 12}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &quotaMetrics{}
+			s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+			WithMetrics(metrics)(s)
+			WithImpressionRepository(tt.repo)(s)
+			WithExposureQuota(NewExposureQuota(2, time.UTC))(s)
+
+			got := targetValues(s.applyExposureQuota(context.Background(), viewer, quotaRecommendations(t, 10, 11, 12)))
+			if !slices.Equal(got, tt.want) || metrics.suppressed != tt.wantSuppressed {
+				t.Errorf("applyExposureQuota = %v (suppressed %d), want %v (suppressed %d)", got, metrics.suppressed, tt.want, tt.wantSuppressed)
+			}
+		})
+	}
+}
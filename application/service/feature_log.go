@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// FeatureSnapshot 一个候选在打分时的完整特征（特征快照日志的一条记录）
+//
+// 按推荐ID和曝光记录、转化记录关联：离线训练的样本 = 特征快照 + 是否曝光 + 是否转化。
+type FeatureSnapshot struct {
+	RecommendationID     string    `json:"recommendation_id"`
+	LoggedAt             time.Time `json:"logged_at"`
+	Tenant               string    `json:"tenant"`
+	ViewerID             int64     `json:"viewer_id"`
+	TargetUserID         int64     `json:"target_user_id"`
+	Surface              string    `json:"surface"`
+	Position             int       `json:"position"` // 在排好序的候选中的位置（从 0 开始）
+	ReasonType           string    `json:"reason_type"`
+	ReasonTypes          []string  `json:"reason_types"`    // 全部推荐理由的类型（多种策略发现时有多个）
+	SignalUserIDs        []int64   `json:"signal_user_ids"` // 推荐信号来源（可能只是样本）
+	RelatedCount         int       `json:"related_count"`
+	RecentPostCount      int       `json:"recent_post_count"`
+	RecentLikes          int       `json:"recent_likes"` // 最近一小时的互动（没有拿到时为 0）
+	RecentComments       int       `json:"recent_comments"`
+	CreatorLikes         int       `json:"creator_likes"` // 最近 N 天收到的互动（没有拿到时为 0）
+	CreatorComments      int       `json:"creator_comments"`
+	PerRelatedUser       int       `json:"per_related_user"` // 打分权重
+	PerRecentPost        int       `json:"per_recent_post"`
+	PerHundredEngagement int       `json:"per_hundred_engagement"`
+	Score                int       `json:"score"`
+	PolicyVersion        string    `json:"policy_version"` // 打分规则版本（aggregate.ScoringRulesVersion）
+}
+
+// FeatureLogSink 特征快照的去处（分析平台的 topic、本地文件，实现在基础设施层）
+type FeatureLogSink interface {
+	// Write 写入一条记录（JSON 格式的 FeatureSnapshot）
+	Write(ctx context.Context, record []byte) error
+}
+
+// FeatureLogger 特征快照日志：按比例采样推荐请求，把每个候选打分时的特征写入 FeatureLogSink
+//
+// 为什么需要？
+// 离线训练排序模型时，如果用训练时刻的数据重新计算特征，
+// 关注数、帖子数早就变了，模型学到的和线上排序看到的不一致（training/serving skew）。
+// 在打分的那一刻把特征原样记下来，离线训练直接使用。
+//
+// 记录范围：一次请求排好序的全部候选（不只是返回的那一页），
+// 没有曝光的候选也是训练样本（负样本或位置偏差的校正）。
+//
+// 和流量抓取一样在后台写入，不占用请求时间；缓冲区满时丢弃（特征日志不能影响线上请求）。
+type FeatureLogger struct {
+	sink       FeatureLogSink
+	sampleRate float64
+	random     func() float64
+	queue      chan []FeatureSnapshot
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewFeatureLogger 构造函数（启动后台写入）
+//
+// 参数：
+// - sink: 特征快照的去处
+// - sampleRate: 请求的采样比例（0~1），采中的请求记录全部候选
+// - bufferSize: 等待写入的最大请求数
+func NewFeatureLogger(sink FeatureLogSink, sampleRate float64, bufferSize int) *FeatureLogger {
+	l := &FeatureLogger{
+		sink:       sink,
+		sampleRate: sampleRate,
+		random:     rand.Float64,
+		queue:      make(chan []FeatureSnapshot, max(bufferSize, 1)),
+		done:       make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// WithFeatureLogger 开启特征快照日志（为 nil 时不记录）
+func WithFeatureLogger(logger *FeatureLogger) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.featureLogger = logger
+	}
+}
+
+// Log 采样记录一次排序的全部候选（没有被采样时什么都不做）
+func (l *FeatureLogger) Log(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+	ranked []*aggregate.UserRecommendation,
+) {
+	if l == nil || len(ranked) == 0 || l.random() >= l.sampleRate {
+		return
+	}
+	now := time.Now()
+	tenant := valueobject.TenantFromContext(ctx).String()
+	snapshots := make([]FeatureSnapshot, 0, len(ranked))
+	for position, rec := range ranked {
+		features := rec.Features()
+		signals := make([]int64, 0, len(features.SignalUserIDs))
+		for _, userID := range features.SignalUserIDs {
+			signals = append(signals, userID.Value())
+		}
+		snapshots = append(snapshots, FeatureSnapshot{
+			RecommendationID:     rec.ID().Value(),
+			LoggedAt:             now,
+			Tenant:               tenant,
+			ViewerID:             viewerID.Value(),
+			TargetUserID:         rec.TargetUserID().Value(),
+			Surface:              string(surface),
+			Position:             position,
+			ReasonType:           reasonTypeKey(features.ReasonType),
+			ReasonTypes:          reasonTypeKeys(features.ReasonTypes),
+			SignalUserIDs:        signals,
+			RelatedCount:         features.RelatedCount,
+			RecentPostCount:      features.RecentPostCount,
+			RecentLikes:          features.Engagement.Likes,
+			RecentComments:       features.Engagement.Comments,
+			CreatorLikes:         features.Creator.Likes,
+			CreatorComments:      features.Creator.Comments,
+			PerRelatedUser:       features.Weights.PerRelatedUser,
+			PerRecentPost:        features.Weights.PerRecentPost,
+			PerHundredEngagement: features.Weights.PerHundredEngagement,
+			Score:                features.Score,
+			PolicyVersion:        features.RulesVersion,
+		})
+	}
+	select {
+	case l.queue <- snapshots:
+	default:
+	}
+}
+
+// Close 停止记录，等待缓冲区中的记录写完
+func (l *FeatureLogger) Close() {
+	l.closeOnce.Do(func() { close(l.queue) })
+	<-l.done
+}
+
+// run 后台写入（一个候选一条记录，写入失败的记录直接丢弃）
+func (l *FeatureLogger) run() {
+	defer close(l.done)
+	for snapshots := range l.queue {
+		for _, snapshot := range snapshots {
+			record, err := json.Marshal(snapshot)
+			if err != nil {
+				continue
+			}
+			_ = l.sink.Write(context.Background(), record)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ErrInvalidStatsRange 统计的时间范围无效（结束早于开始，或超过 maxStatsRange）
+var ErrInvalidStatsRange = errors.New("invalid stats time range")
+
+const (
+	defaultStatsRange = 7 * 24 * time.Hour
+	maxStatsRange     = 90 * 24 * time.Hour
+)
+
+// FeedbackAnalyticsService 应用服务：推荐效果统计（运营看板）
+//
+// 为什么需要？
+// 运营看板之前直接连数据库跑 SQL：表结构一改看板就坏，
+// 而且看板的账号能读到所有曝光明细。
+// 改为通过管理接口查询聚合后的结果，看板只能看到按天汇总的数字。
+//
+// 统计维度：天 × 策略 × 推荐理由类型 × 展示场景
+// - 策略由推荐理由类型决定（每种策略产生自己的理由类型，见 reasonStrategy）
+// - 转化：曝光后 conversionWindow 内关注了被推荐的用户
+// - 按策略汇总（QueryStrategies）：基于归因记录，另外统计点击（直接在推荐卡片上关注）
+// - 交错实验（QueryInterleaving）：基于归因记录，比较两个排序器的胜率
+//
+// "不感兴趣"等负反馈目前没有采集，接入反馈之后再增加 dismiss 相关的指标。
+type FeedbackAnalyticsService struct {
+	repo             repository.RecommendationStatsRepository
+	conversionWindow time.Duration
+}
+
+// NewFeedbackAnalyticsService 构造函数（repo 为 nil 时统计为空，如本地开发的内存曝光记录）
+func NewFeedbackAnalyticsService(
+	repo repository.RecommendationStatsRepository,
+	conversionWindow time.Duration,
+) *FeedbackAnalyticsService {
+	return &FeedbackAnalyticsService{repo: repo, conversionWindow: conversionWindow}
+}
+
+// Query 用例：查询一段时间内的推荐效果（按天升序）
+func (s *FeedbackAnalyticsService) Query(ctx context.Context, query *dto.FeedbackStatsQuery) ([]*dto.FeedbackStatsDTO, error) {
+	from, to, err := statsRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.FeedbackStatsDTO, 0)
+	if s.repo == nil {
+		return result, nil
+	}
+	stats, err := s.repo.DailyStats(ctx, from, to, s.conversionWindow)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range stats {
+		item := &dto.FeedbackStatsDTO{
+			Day:         row.Day.Format("2006-01-02"),
+			Strategy:    reasonStrategy(row.ReasonType),
+			ReasonType:  reasonTypeKey(row.ReasonType),
+			Surface:     row.Surface,
+			Impressions: row.Impressions,
+			Conversions: row.Conversions,
+		}
+		if row.Impressions > 0 {
+			item.ConversionRate = float64(row.Conversions) / float64(row.Impressions)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// QueryStrategies 用例：查询一段时间内每种策略的推荐效果（按策略名升序）
+//
+// 和 Query 的区别：转化来自归因记录（AttributionService 写入），
+// 取关不影响已经统计的转化，并且能区分直接在推荐卡片上的关注（点击）。
+func (s *FeedbackAnalyticsService) QueryStrategies(ctx context.Context, query *dto.FeedbackStatsQuery) ([]*dto.StrategyStatsDTO, error) {
+	from, to, err := statsRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.StrategyStatsDTO, 0)
+	if s.repo == nil {
+		return result, nil
+	}
+	stats, err := s.repo.ReasonStats(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byStrategy := make(map[string]*dto.StrategyStatsDTO)
+	for _, row := range stats {
+		strategy := reasonStrategy(row.ReasonType)
+		item, ok := byStrategy[strategy]
+		if !ok {
+			item = &dto.StrategyStatsDTO{Strategy: strategy}
+			byStrategy[strategy] = item
+			result = append(result, item)
+		}
+		item.Impressions += row.Impressions
+		item.Clicks += row.DirectConversions
+		item.Conversions += row.Conversions
+	}
+	for _, item := range result {
+		if item.Impressions > 0 {
+			item.ClickThroughRate = float64(item.Clicks) / float64(item.Impressions)
+			item.ConversionRate = float64(item.Conversions) / float64(item.Impressions)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Strategy < result[j].Strategy
+	})
+	return result, nil
+}
+
+// QueryInterleaving 用例：查询交错实验的胜率（按曝光时间，转化来自归因记录）
+//
+// 每个交错列表是一次比赛：两个排序器各自的推荐带来的关注多的一方获胜。
+// 胜率的分母是有关注的列表数（胜 + 负 + 平），没有任何关注的列表不分胜负。
+func (s *FeedbackAnalyticsService) QueryInterleaving(
+	ctx context.Context,
+	experiment string,
+	query *dto.FeedbackStatsQuery,
+) (*dto.InterleavingStatsDTO, error) {
+	from, to, err := statsRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.InterleavingStatsDTO{Experiment: experiment}
+	if s.repo == nil {
+		return result, nil
+	}
+	outcome, err := s.repo.InterleavingOutcome(ctx, experiment, from, to)
+	if err != nil {
+		return nil, err
+	}
+	result.Sessions = outcome.Sessions
+	result.WinsA = outcome.WinsA
+	result.WinsB = outcome.WinsB
+	result.Ties = outcome.Ties
+	if decided := outcome.WinsA + outcome.WinsB + outcome.Ties; decided > 0 {
+		result.WinRateA = float64(outcome.WinsA) / float64(decided)
+		result.WinRateB = float64(outcome.WinsB) / float64(decided)
+	}
+	return result, nil
+}
+
+// statsRange 辅助函数：查询条件 → 时间范围（默认最近 7 天，最多 90 天）
+func statsRange(query *dto.FeedbackStatsQuery) (from, to time.Time, err error) {
+	to = query.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from = query.From
+	if from.IsZero() {
+		from = to.Add(-defaultStatsRange)
+	}
+	if !from.Before(to) || to.Sub(from) > maxStatsRange {
+		return time.Time{}, time.Time{}, ErrInvalidStatsRange
+	}
+	return from, to, nil
+}
+
+// reasonStrategy 辅助函数：产生这种推荐理由的策略
+func reasonStrategy(reasonType valueobject.ReasonType) string {
+	switch reasonType {
+	case valueobject.ReasonFollowedByFollowing, valueobject.ReasonPopularInNetwork:
+		return "following_based"
+	case valueobject.ReasonTopicFollowedByFollowing:
+		return "topic_based"
+	case valueobject.ReasonFriendsAreMembers:
+		return "group_based"
+	case valueobject.ReasonFollowedUserMilestone, valueobject.ReasonFollowedUserReturned:
+		return "trigger_based"
+	case valueobject.ReasonMissedPosts:
+		return "re_engagement"
+	case valueobject.ReasonManyMutualFollows:
+		return "mutual_based"
+	case valueobject.ReasonPopularOnPlatform, valueobject.ReasonEditorPick:
+		return "cold_start"
+	case valueobject.ReasonBackfill:
+		return "backfill"
+	default:
+		return "unknown"
+	}
+}
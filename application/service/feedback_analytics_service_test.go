@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// fakeStatsRepo 测试用统计：返回预设的结果，记录查询的时间范围
+type fakeStatsRepo struct {
+	stats    []*repository.DailyRecommendationStats
+	reasons  []*repository.ReasonConversionStats
+	outcome  *repository.InterleavingOutcome
+	from, to time.Time
+}
+
+func (r *fakeStatsRepo) DailyStats(ctx context.Context, from, to time.Time, conversionWindow time.Duration) ([]*repository.DailyRecommendationStats, error) {
+	r.from, r.to = from, to
+	return r.stats, nil
+}
+
+func (r *fakeStatsRepo) ReasonStats(ctx context.Context, from, to time.Time) ([]*repository.ReasonConversionStats, error) {
+	r.from, r.to = from, to
+	return r.reasons, nil
+}
+
+func (r *fakeStatsRepo) InterleavingOutcome(ctx context.Context, experiment string, from, to time.Time) (*repository.InterleavingOutcome, error) {
+	r.from, r.to = from, to
+	return r.outcome, nil
+}
+
+func TestFeedbackAnalyticsService_Query(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeStatsRepo{stats: []*repository.DailyRecommendationStats{
+		{Day: day, Surface: "home_feed", ReasonType: valueobject.ReasonFollowedByFollowing, Impressions: 200, Conversions: 5},
+		{Day: day, Surface: "push", ReasonType: valueobject.ReasonPopularInNetwork},
+	}}
+	s := NewFeedbackAnalyticsService(repo, 24*time.Hour)
+
+	stats, err := s.Query(context.Background(), &dto.FeedbackStatsQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.to.Sub(repo.from); got != defaultStatsRange {
+		t.Errorf("default range = %v, want %v", got, defaultStatsRange)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d rows, want 2", len(stats))
+	}
+	if first := stats[0]; first.Day != "2024-05-01" || first.ReasonType != "followed_by_following" ||
+		first.Strategy != "following_based" || first.ConversionRate != 0.025 {
+		t.Errorf("unexpected row: %+v", first)
+	}
+	if stats[1].ConversionRate != 0 {
+		t.Errorf("no impressions: conversion rate = %v, want 0", stats[1].ConversionRate)
+	}
+
+	tooLong := &dto.FeedbackStatsQuery{From: day, To: day.Add(maxStatsRange + time.Hour)}
+	if _, err := s.Query(context.Background(), tooLong); !errors.Is(err, ErrInvalidStatsRange) {
+		t.Errorf("range over max: err = %v", err)
+	}
+}
+
+func TestFeedbackAnalyticsService_QueryStrategies(t *testing.T) {
+	repo := &fakeStatsRepo{reasons: []*repository.ReasonConversionStats{
+		{ReasonType: valueobject.ReasonFollowedByFollowing, Impressions: 300, Conversions: 9, DirectConversions: 6},
+		{ReasonType: valueobject.ReasonPopularInNetwork, Impressions: 100, Conversions: 3, DirectConversions: 2},
+		{ReasonType: valueobject.ReasonTopicFollowedByFollowing, Impressions: 50},
+	}}
+	s := NewFeedbackAnalyticsService(repo, 24*time.Hour)
+
+	stats, err := s.QueryStrategies(context.Background(), &dto.FeedbackStatsQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d strategies, want 2: %+v", len(stats), stats)
+	}
+	// 同一种策略的多种理由类型合并；按策略名排序
+	following := stats[0]
+	if following.Strategy != "following_based" || following.Impressions != 400 ||
+		following.Clicks != 8 || following.Conversions != 12 ||
+		following.ClickThroughRate != 0.02 || following.ConversionRate != 0.03 {
+		t.Errorf("unexpected following_based stats: %+v", following)
+	}
+	if topic := stats[1]; topic.Strategy != "topic_based" || topic.ConversionRate != 0 {
+		t.Errorf("unexpected topic_based stats: %+v", topic)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+// HandleFollowChange 用例：关注关系变化后清理关注者的推荐列表（关注事件的消费者调用）
+//
+// - 所有事件：删除关注者缓存的推荐列表（所有策略），下一次请求重新生成
+// - 关注：从保存的推荐列表（WithRecommendationRepository）中移除刚关注的人
+//
+// 保存的列表在 listMaxAge 内仍然会被使用，不移除的话刚关注的人还会出现在推荐里。
+//
+// 快照（WithSnapshotStore）不修改：快照是增量同步的基准，直出只在 maxStaleness 内使用。
+// 取关、静音不从保存的列表中添加或者移除：列表下一次生成时自然反映。
+//
+// 返回错误时消费者应该重试（删除、移除都是幂等的）。
+func (s *RecommendationService) HandleFollowChange(ctx context.Context, req *dto.FollowEventRequest) error {
+	followerID, err := valueobject.NewUserID(req.FollowerID)
+	if err != nil {
+		return err
+	}
+	followingID, err := valueobject.NewUserID(req.FollowingID)
+	if err != nil {
+		return err
+	}
+
+	if s.listCache != nil {
+		if err := s.listCache.InvalidateUser(ctx, followerID.Value()); err != nil {
+			return err
+		}
+	}
+	if s.listRepo != nil && (req.Action == dto.FollowActionFollow || req.Action == "") {
+		if err := s.listRepo.RemoveTarget(ctx, followerID, followingID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
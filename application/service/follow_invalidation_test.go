@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+func TestHandleFollowChange(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	listCache := memoryListCache{}
+	repo := &memoryListRepo{}
+	WithRecommendationListCache(listCache)(s)
+	WithRecommendationRepository(repo, time.Minute)(s)
+	ctx := context.Background()
+
+	now := time.Now()
+	reason := valueobject.RestoreRecommendationReason(valueobject.ReasonFollowedByFollowing, 1)
+	var recs []*aggregate.UserRecommendation
+	for _, target := range userIDs(2, 3) {
+		recs = append(recs, aggregate.RestoreUserRecommendation(target, reason, 10, 0, now, now.Add(time.Hour)))
+	}
+	repo.latest = aggregate.RestoreRecommendationList(userIDs(1)[0], recs, now, aggregate.FollowingSampling{})
+	targets := func() []int64 {
+		var ids []int64
+		for _, rec := range repo.latest.All() {
+			ids = append(ids, rec.TargetUserID().Value())
+		}
+		return ids
+	}
+
+	// 关注：删除缓存的列表，从保存的列表中移除刚关注的人
+	listCache[ListCacheKey{UserID: 1, Strategy: DefaultListStrategy}] = nil
+	if err := s.HandleFollowChange(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 2, Action: dto.FollowActionFollow}); err != nil {
+		t.Fatal(err)
+	}
+	if len(listCache) != 0 {
+		t.Errorf("list cache not invalidated: %v", listCache)
+	}
+	if got := targets(); len(got) != 1 || got[0] != 3 {
+		t.Errorf("persisted targets = %v, want [3]", got)
+	}
+
+	// 取关：只删除缓存的列表
+	listCache[ListCacheKey{UserID: 1, Strategy: DefaultListStrategy}] = nil
+	if err := s.HandleFollowChange(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 3, Action: dto.FollowActionUnfollow}); err != nil {
+		t.Fatal(err)
+	}
+	if len(listCache) != 0 {
+		t.Errorf("unfollow: list cache not invalidated: %v", listCache)
+	}
+	if got := targets(); len(got) != 1 || got[0] != 3 {
+		t.Errorf("unfollow: persisted targets = %v, want [3]", got)
+	}
+}
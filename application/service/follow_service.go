@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ErrUnknownFollowAction 关注事件的类型不是 follow / unfollow / mute / unmute
+var ErrUnknownFollowAction = errors.New("unknown follow action")
+
+// FollowService 应用服务：根据社交服务的关注事件维护本地的关注关系
+//
+// 为什么需要？
+// 以前 follows 表由社交服务直接写入，推荐服务只读，关注关系只是一行数据（FollowPO）。
+// 推荐服务自己消费关注事件、维护关注关系之后：
+// - 不再依赖社交服务的数据库（可以换成更适合图查询的存储）
+// - 静音、取关、重新关注的规则集中在 entity.Follow 中
+//
+// 两个入口：
+// - HandleFollowEvent：社交服务的关注事件（宽松：重复的事件是空操作）
+// - FollowUser、UnfollowUser：用户通过推荐服务的 RPC 主动关注、取关（严格：重复操作返回错误）
+//
+// 事件是至少一次投递、可能乱序的：
+// 重复的事件是空操作，早于最近一次变化的事件被忽略（见 entity.Follow）。
+// 关注关系还不存在时（如取关事件先于关注事件到达），按事件时间创建一条关注关系再执行操作，
+// 这样之后才到达的旧关注事件不会把已经取关的关系恢复。
+type FollowService struct {
+	socialGraphRepo repository.SocialGraphRepository
+	listCache       RecommendationListCache // 推荐列表缓存（为 nil 时不删除）
+	graphCache      UserCacheInvalidator    // 关注列表缓存（为 nil 时不删除）
+	attribution     *AttributionService     // 推荐卡片上的关注立即归因（为 nil 时只由关注事件归因）
+	transactions    TransactionManager      // 读取、修改、保存关注关系的事务（默认没有事务）
+	now             func() time.Time
+}
+
+// FollowServiceOption 可选配置
+type FollowServiceOption func(*FollowService)
+
+// WithFollowListCache 关注关系变化后删除关注者缓存的推荐列表
+//
+// 用户关注、取关、静音之后，下一次请求就能看到变化（而不是等缓存过期）。
+// 删除失败不影响事件处理（关注关系已经保存），缓存随 TTL 过期。
+func WithFollowListCache(cache RecommendationListCache) FollowServiceOption {
+	return func(s *FollowService) {
+		s.listCache = cache
+	}
+}
+
+// WithFollowGraphCache 关注关系变化、事务提交之后再删除一次关注者的关注列表缓存
+//
+// 带缓存的社交图谱仓储写入时已经删除过缓存，但在事务中写入时还没有提交：
+// 提交之前并发的读请求可能把旧的关注列表重新写回缓存，提交之后再删除一次。
+// 删除失败不影响关注（关注关系已经保存），缓存随 TTL 过期。
+func WithFollowGraphCache(cache UserCacheInvalidator) FollowServiceOption {
+	return func(s *FollowService) {
+		s.graphCache = cache
+	}
+}
+
+// WithFollowAttribution 用户在推荐卡片上关注（FollowUser 的 Source 为 recommendation）时立即归因到推荐曝光
+//
+// 不用等社交服务的关注事件：转化在关注的同一个请求中记录，策略权重 bandit 立即得到奖励。
+// 之后到达的关注事件再归因一次是空操作（同一次曝光只记录一次转化）；
+// 归因失败不影响关注（关注关系已经保存），由关注事件的消费者补上。
+func WithFollowAttribution(attribution *AttributionService) FollowServiceOption {
+	return func(s *FollowService) {
+		s.attribution = attribution
+	}
+}
+
+// WithFollowTransactions 读取、修改、保存关注关系在一个事务中执行
+//
+// 删除缓存、归因在事务提交之后执行：事务回滚时不应该有这些副作用。
+func WithFollowTransactions(transactions TransactionManager) FollowServiceOption {
+	return func(s *FollowService) {
+		if transactions != nil {
+			s.transactions = transactions
+		}
+	}
+}
+
+// NewFollowService 构造函数
+func NewFollowService(socialGraphRepo repository.SocialGraphRepository, opts ...FollowServiceOption) *FollowService {
+	s := &FollowService{
+		socialGraphRepo: socialGraphRepo,
+		transactions:    noopTransactionManager{},
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// HandleFollowEvent 用例：处理一个关注事件，返回关注关系是否发生了变化
+//
+// 返回错误时：
+//   - ErrUnknownFollowAction、entity.ErrInvalidFollowTransition、entity.ErrCannotFollowSelf：
+//     重试也不会成功，应由消费者转入死信队列
+//   - 其他错误（仓储失败）：消费者应该重试（处理是幂等的）
+func (s *FollowService) HandleFollowEvent(ctx context.Context, req *dto.FollowEventRequest) (bool, error) {
+	followerID, err := valueobject.NewUserID(req.FollowerID)
+	if err != nil {
+		return false, err
+	}
+	followingID, err := valueobject.NewUserID(req.FollowingID)
+	if err != nil {
+		return false, err
+	}
+	at := req.OccurredAt
+	if at.IsZero() {
+		at = s.now()
+	}
+
+	var saved bool
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		follow, err := s.socialGraphRepo.FindFollow(ctx, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		created := follow == nil
+		if created {
+			if follow, err = entity.NewFollow(followerID, followingID, at); err != nil {
+				return err
+			}
+		}
+
+		var changed bool
+		switch req.Action {
+		case dto.FollowActionFollow, "":
+			changed, err = follow.Refollow(at)
+		case dto.FollowActionUnfollow:
+			changed, err = follow.Remove(at)
+		case dto.FollowActionMute:
+			changed, err = follow.Mute(at)
+		case dto.FollowActionUnmute:
+			changed, err = follow.Unmute(at)
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownFollowAction, req.Action)
+		}
+		if err != nil || (!created && !changed) {
+			return err
+		}
+		saved = true
+		return s.socialGraphRepo.SaveFollow(ctx, follow)
+	})
+	if err != nil || !saved {
+		return false, err
+	}
+	s.invalidateList(ctx, followerID)
+	return true, nil
+}
+
+// FollowUser 用例：用户主动关注
+//
+// 返回错误时：
+//   - entity.ErrCannotFollowSelf：关注自己
+//   - entity.ErrAlreadyFollowing：已经在关注（包括静音的关注）
+//   - 其他错误（仓储失败）：关注没有保存，客户端可以重试
+//
+// 关注之后删除关注者缓存的推荐列表：刚关注的人下一次请求就不会再被推荐。
+func (s *FollowService) FollowUser(ctx context.Context, req *dto.FollowUserRequest) error {
+	followerID, err := valueobject.NewUserID(req.FollowerID)
+	if err != nil {
+		return err
+	}
+	followingID, err := valueobject.NewUserID(req.FollowingID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		follow, err := s.socialGraphRepo.FindFollow(ctx, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		if follow == nil {
+			follow, err = entity.NewFollow(followerID, followingID, now)
+		} else {
+			err = follow.FollowAgain(now)
+		}
+		if err != nil {
+			return err
+		}
+		return s.socialGraphRepo.SaveFollow(ctx, follow)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateList(ctx, followerID)
+
+	if s.attribution != nil && req.Source == FollowSourceRecommendation {
+		_, _ = s.attribution.HandleFollow(ctx, &dto.FollowEventRequest{
+			FollowerID:  req.FollowerID,
+			FollowingID: req.FollowingID,
+			Action:      dto.FollowActionFollow,
+			Source:      req.Source,
+			OccurredAt:  now,
+		})
+	}
+	return nil
+}
+
+// UnfollowUser 用例：用户主动取关（软删除，见 SocialGraphRepository.RemoveFollow）
+//
+// 返回错误时：
+//   - entity.ErrNotFollowing：没有在关注（从来没有关注过，或者已经取关）
+//   - 其他错误（仓储失败）：取关没有保存，客户端可以重试
+func (s *FollowService) UnfollowUser(ctx context.Context, req *dto.UnfollowUserRequest) error {
+	followerID, err := valueobject.NewUserID(req.FollowerID)
+	if err != nil {
+		return err
+	}
+	followingID, err := valueobject.NewUserID(req.FollowingID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		follow, err := s.socialGraphRepo.FindFollow(ctx, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		if follow == nil {
+			return entity.ErrNotFollowing
+		}
+		if err := follow.Unfollow(now); err != nil {
+			return err
+		}
+		return s.socialGraphRepo.RemoveFollow(ctx, followerID, followingID, now)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateList(ctx, followerID)
+	return nil
+}
+
+// invalidateList 辅助方法：删除关注者的关注列表缓存、缓存的推荐列表（失败不返回错误，缓存随 TTL 过期）
+//
+// 先删除关注列表：推荐列表删除之后的下一次请求要用新的关注列表重新生成。
+func (s *FollowService) invalidateList(ctx context.Context, followerID valueobject.UserID) {
+	if s.graphCache != nil {
+		_ = s.graphCache.InvalidateUser(ctx, followerID.Value())
+	}
+	if s.listCache != nil {
+		_ = s.listCache.InvalidateUser(ctx, followerID.Value())
+	}
+}
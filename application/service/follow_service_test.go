@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// followStoreRepo 测试用关注关系存储：记录保存次数
+type followStoreRepo struct {
+	stubSocialGraphRepo
+	follows map[[2]int64]*entity.Follow
+	saves   int
+}
+
+func (r *followStoreRepo) FindFollow(ctx context.Context, followerID, followingID valueobject.UserID) (*entity.Follow, error) {
+	return r.follows[[2]int64{followerID.Value(), followingID.Value()}], nil
+}
+
+func (r *followStoreRepo) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	r.follows[[2]int64{follow.FollowerID().Value(), follow.FollowingID().Value()}] = follow
+	r.saves++
+	return nil
+}
+
+func (r *followStoreRepo) RemoveFollow(ctx context.Context, followerID, followingID valueobject.UserID, removedAt time.Time) error {
+	if follow := r.follows[[2]int64{followerID.Value(), followingID.Value()}]; follow != nil {
+		_, _ = follow.Remove(removedAt)
+	}
+	return nil
+}
+
+func TestFollowService_HandleFollowEvent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := &followStoreRepo{follows: make(map[[2]int64]*entity.Follow)}
+	s := NewFollowService(repo)
+	ctx := context.Background()
+	event := func(action dto.FollowAction, at time.Time) *dto.FollowEventRequest {
+		return &dto.FollowEventRequest{FollowerID: 1, FollowingID: 2, Action: action, OccurredAt: at}
+	}
+	status := func() entity.FollowStatus {
+		return repo.follows[[2]int64{1, 2}].Status()
+	}
+
+	steps := []struct {
+		name    string
+		req     *dto.FollowEventRequest
+		changed bool
+		status  entity.FollowStatus
+		err     error
+	}{
+		{"follow", event(dto.FollowActionFollow, now), true, entity.FollowActive, nil},
+		{"duplicate follow", event(dto.FollowActionFollow, now), false, entity.FollowActive, nil},
+		{"mute", event(dto.FollowActionMute, now.Add(time.Hour)), true, entity.FollowMuted, nil},
+		{"refollow keeps mute", event(dto.FollowActionFollow, now.Add(2*time.Hour)), false, entity.FollowMuted, nil},
+		{"unfollow", event(dto.FollowActionUnfollow, now.Add(3*time.Hour)), true, entity.FollowRemoved, nil},
+		{"stale unmute", event(dto.FollowActionUnmute, now.Add(2*time.Hour)), false, entity.FollowRemoved, nil},
+		{"mute removed", event(dto.FollowActionMute, now.Add(4*time.Hour)), false, entity.FollowRemoved, entity.ErrInvalidFollowTransition},
+		{"refollow", event(dto.FollowActionFollow, now.Add(5*time.Hour)), true, entity.FollowActive, nil},
+		{"unknown action", event("block", now.Add(6*time.Hour)), false, entity.FollowActive, ErrUnknownFollowAction},
+	}
+	for _, step := range steps {
+		changed, err := s.HandleFollowEvent(ctx, step.req)
+		if !errors.Is(err, step.err) || changed != step.changed || status() != step.status {
+			t.Fatalf("%s: changed=%v err=%v status=%s; want changed=%v err=%v status=%s",
+				step.name, changed, err, status(), step.changed, step.err, step.status)
+		}
+	}
+	if repo.saves != 4 {
+		t.Errorf("saves = %d, want 4 (only changes are saved)", repo.saves)
+	}
+	if got := repo.follows[[2]int64{1, 2}].FollowedAt(); !got.Equal(now.Add(5 * time.Hour)) {
+		t.Errorf("FollowedAt = %v, want refollow time", got)
+	}
+
+	// 取关事件先于关注事件到达：之后到达的旧关注事件不会恢复关注
+	if _, err := s.HandleFollowEvent(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 3, Action: dto.FollowActionUnfollow, OccurredAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.HandleFollowEvent(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 3, Action: dto.FollowActionFollow, OccurredAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.follows[[2]int64{1, 3}].Status(); got != entity.FollowRemoved {
+		t.Errorf("out-of-order follow: status = %s, want removed", got)
+	}
+}
+
+func TestFollowService_FollowUser(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := &followStoreRepo{follows: make(map[[2]int64]*entity.Follow)}
+	listCache := memoryListCache{}
+	viewer, _ := valueobject.NewUserID(1)
+	target, _ := valueobject.NewUserID(2)
+	impressions := &fakeImpressionRepo{listed: []*entity.Impression{
+		entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonFollowedByFollowing, "home_feed", now.Add(-time.Hour)),
+	}}
+	conversions := &fakeConversionRepo{conversions: make(map[valueobject.RecommendationID]*entity.Conversion)}
+	attribution := NewAttributionService(impressions, conversions, nil, 24*time.Hour)
+	attribution.now = func() time.Time { return now }
+	s := NewFollowService(repo, WithFollowListCache(listCache), WithFollowAttribution(attribution))
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	follow := &dto.FollowUserRequest{FollowerID: 1, FollowingID: 2, Source: FollowSourceRecommendation}
+	unfollow := &dto.UnfollowUserRequest{FollowerID: 1, FollowingID: 2}
+
+	// 推荐卡片上的关注：保存关注关系，删除推荐列表缓存，立即归因
+	listCache[ListCacheKey{UserID: 1, Strategy: DefaultListStrategy}] = nil
+	if err := s.FollowUser(ctx, follow); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.follows[[2]int64{1, 2}]; got == nil || !got.IsActive() {
+		t.Fatalf("follow = %+v, want active", got)
+	}
+	if len(listCache) != 0 {
+		t.Errorf("list cache not invalidated: %v", listCache)
+	}
+	if len(conversions.conversions) != 1 {
+		t.Errorf("conversions = %d, want 1", len(conversions.conversions))
+	}
+
+	steps := []struct {
+		name   string
+		run    func() error
+		err    error
+		status entity.FollowStatus
+	}{
+		{"duplicate follow", func() error { return s.FollowUser(ctx, follow) }, entity.ErrAlreadyFollowing, entity.FollowActive},
+		{"unfollow", func() error { return s.UnfollowUser(ctx, unfollow) }, nil, entity.FollowRemoved},
+		{"duplicate unfollow", func() error { return s.UnfollowUser(ctx, unfollow) }, entity.ErrNotFollowing, entity.FollowRemoved},
+		{"follow again", func() error { return s.FollowUser(ctx, follow) }, nil, entity.FollowActive},
+	}
+	for _, step := range steps {
+		now = now.Add(time.Minute)
+		if err := step.run(); !errors.Is(err, step.err) {
+			t.Fatalf("%s: err = %v, want %v", step.name, err, step.err)
+		}
+		if got := repo.follows[[2]int64{1, 2}].Status(); got != step.status {
+			t.Fatalf("%s: status = %s, want %s", step.name, got, step.status)
+		}
+	}
+
+	if err := s.FollowUser(ctx, &dto.FollowUserRequest{FollowerID: 1, FollowingID: 1}); !errors.Is(err, entity.ErrCannotFollowSelf) {
+		t.Errorf("self follow: err = %v, want ErrCannotFollowSelf", err)
+	}
+	if err := s.UnfollowUser(ctx, &dto.UnfollowUserRequest{FollowerID: 1, FollowingID: 3}); !errors.Is(err, entity.ErrNotFollowing) {
+		t.Errorf("never followed: err = %v, want ErrNotFollowing", err)
+	}
+}
+
+// txMarker 测试用事务：fn 收到的 ctx 带上这个标记
+type txMarker struct{}
+
+// fakeTransactions 测试用事务边界：记录提交、回滚（回滚时恢复执行前的关注关系）
+type fakeTransactions struct {
+	repo      *followStoreRepo
+	commits   int
+	rollbacks int
+}
+
+func (m *fakeTransactions) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make(map[[2]int64]*entity.Follow, len(m.repo.follows))
+	for key, follow := range m.repo.follows {
+		copied := *follow
+		snapshot[key] = &copied
+	}
+	if err := fn(context.WithValue(ctx, txMarker{}, true)); err != nil {
+		m.repo.follows = snapshot
+		m.rollbacks++
+		return err
+	}
+	m.commits++
+	return nil
+}
+
+// txFollowStoreRepo 测试用关注关系存储：检查写入在事务中，第 failAt 次保存失败
+type txFollowStoreRepo struct {
+	*followStoreRepo
+	failAt int
+}
+
+func (r *txFollowStoreRepo) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	if ctx.Value(txMarker{}) == nil {
+		return errors.New("save outside a transaction")
+	}
+	if err := r.followStoreRepo.SaveFollow(ctx, follow); err != nil {
+		return err
+	}
+	if r.saves == r.failAt {
+		return errors.New("database unavailable")
+	}
+	return nil
+}
+
+func TestFollowService_Transactions(t *testing.T) {
+	store := &followStoreRepo{follows: make(map[[2]int64]*entity.Follow)}
+	repo := &txFollowStoreRepo{followStoreRepo: store, failAt: 2}
+	transactions := &fakeTransactions{repo: store}
+	listCache := memoryListCache{}
+	s := NewFollowService(repo, WithFollowTransactions(transactions), WithFollowListCache(listCache))
+	ctx := context.Background()
+	key := ListCacheKey{UserID: 1, Strategy: DefaultListStrategy}
+
+	// 关注：在事务中保存，提交之后删除缓存
+	listCache[key] = nil
+	if err := s.FollowUser(ctx, &dto.FollowUserRequest{FollowerID: 1, FollowingID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if transactions.commits != 1 || len(listCache) != 0 {
+		t.Fatalf("commits = %d, cache = %v; want committed and invalidated", transactions.commits, listCache)
+	}
+
+	// 保存失败：回滚，关注关系不变，缓存不删除
+	listCache[key] = nil
+	if _, err := s.HandleFollowEvent(ctx, &dto.FollowEventRequest{FollowerID: 1, FollowingID: 2, Action: dto.FollowActionMute}); err == nil {
+		t.Fatal("mute: no error from a failed save")
+	}
+	if transactions.rollbacks != 1 || store.follows[[2]int64{1, 2}].Status() != entity.FollowActive || len(listCache) != 1 {
+		t.Errorf("rollbacks = %d, status = %s, cache = %v; want rolled back, active, cache kept",
+			transactions.rollbacks, store.follows[[2]int64{1, 2}].Status(), listCache)
+	}
+}
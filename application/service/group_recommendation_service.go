@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+const (
+	defaultGroupLimit = 10
+	maxGroupLimit     = 50
+)
+
+// GroupRecommendationService 应用服务：小组（社区）推荐
+//
+// 和 TopicRecommendationService 一样是 App 中单独的模块，
+// 只共用数据删除、推荐理由文案和租户配置。
+type GroupRecommendationService struct {
+	recommender        *domainService.GroupRecommender
+	erasureRepo        repository.UserErasureRepository // 可以为 nil
+	reasonConfigClient ReasonTextConfigClient           // 可以为 nil（使用默认文案）
+	tenants            *Tenants                         // 可以为 nil（使用默认配置）
+}
+
+// NewGroupRecommendationService 构造函数
+func NewGroupRecommendationService(
+	recommender *domainService.GroupRecommender,
+	erasureRepo repository.UserErasureRepository,
+	reasonConfigClient ReasonTextConfigClient,
+	tenants *Tenants,
+) *GroupRecommendationService {
+	return &GroupRecommendationService{
+		recommender:        recommender,
+		erasureRepo:        erasureRepo,
+		reasonConfigClient: reasonConfigClient,
+		tenants:            tenants,
+	}
+}
+
+// GetGroupRecommendations 用例：为用户推荐小组
+//
+// 1. 已删除数据的用户返回空列表
+// 2. 按租户的打分权重生成小组推荐（我关注的人参与的小组，排除我已加入的）
+// 3. 取分数最高的 limit 个，补全推荐理由文案
+func (s *GroupRecommendationService) GetGroupRecommendations(
+	ctx context.Context,
+	req *dto.GroupRecommendationRequest,
+) (*dto.GroupRecommendationResponse, error) {
+	userID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultGroupLimit
+	}
+	limit = min(limit, maxGroupLimit)
+
+	resp := &dto.GroupRecommendationResponse{Groups: []*dto.GroupRecommendationDTO{}}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	weights := s.tenants.Config(valueobject.TenantFromContext(ctx)).ScoringWeights
+	list, err := s.recommender.RecommendGroups(ctx, userID, weights)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range list.GetTopN(limit) {
+		resp.Groups = append(resp.Groups, &dto.GroupRecommendationDTO{
+			RecommendationID: rec.ID().Value(),
+			GroupID:          rec.GroupID().Value(),
+			MemberCount:      rec.MemberCount(),
+			Reason:           reasonText(ctx, s.reasonConfigClient, s.tenants, nil, rec.Reason(), nil),
+			Score:            rec.Score(),
+		})
+	}
+	return resp, nil
+}
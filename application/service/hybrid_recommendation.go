@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+// 混合推荐的策略名（hybrid.weights 的 key）
+const (
+	HybridStrategyFollowing  = "following"  // 关注推荐（GenerateFollowingBasedRecommendations）
+	HybridStrategyPopularity = "popularity" // 热度推荐（GeneratePopularityBasedRecommendations）
+)
+
+// HybridWeights 混合推荐中各策略的权重：候选的混合分数 = 推荐分数 × 策略权重
+//
+// 权重为 0 的策略不执行。
+type HybridWeights map[string]float64
+
+// DefaultHybridWeights 默认权重：关注推荐 1，热度推荐 0.5（热度是补充，不压过社交信号）
+func DefaultHybridWeights() HybridWeights {
+	return HybridWeights{HybridStrategyFollowing: 1, HybridStrategyPopularity: 0.5}
+}
+
+// ParseHybridWeights 解析配置（没有列出的策略使用默认权重）
+//
+// 策略名写错、权重为负数时返回错误：配置写错应该在启动时发现，而不是悄悄按默认权重混合。
+func ParseHybridWeights(raw map[string]float64) (HybridWeights, error) {
+	weights := DefaultHybridWeights()
+	for strategy, weight := range raw {
+		if _, ok := weights[strategy]; !ok {
+			return nil, fmt.Errorf("unknown hybrid strategy %q", strategy)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("hybrid strategy %q: negative weight %v", strategy, weight)
+		}
+		weights[strategy] = weight
+	}
+	return weights, nil
+}
+
+// 混合推荐的融合方式（hybrid.fusion）
+const (
+	HybridFusionWeighted = "weighted" // 加权合并：混合分数 = 推荐分数 × 策略权重（默认）
+	HybridFusionRRF      = "rrf"      // 倒数排名融合：只看各策略中的名次，不看分数
+)
+
+// ParseHybridFusion 按配置构造融合排序器（为空时加权合并；rrfK <= 0 时使用 DefaultRRFK）
+//
+// 融合方式写错时返回错误（和 ParseHybridWeights 一样在启动时发现）。
+func ParseHybridFusion(name string, weights HybridWeights, rrfK int) (domainService.FusionRanker, error) {
+	switch name {
+	case "", HybridFusionWeighted:
+		return domainService.NewWeightedMergeRanker(weights), nil
+	case HybridFusionRRF:
+		return domainService.NewReciprocalRankFusion(rrfK, weights), nil
+	default:
+		return nil, fmt.Errorf("unknown hybrid fusion %q", name)
+	}
+}
+
+// WithHybridWeights 混合推荐中各策略的权重（为 nil 时使用 DefaultHybridWeights）
+func WithHybridWeights(weights HybridWeights) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.hybridWeights = weights
+	}
+}
+
+// WithHybridFusion 混合推荐的融合排序器（为 nil 时按策略权重加权合并）
+func WithHybridFusion(fusion domainService.FusionRanker) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.hybridFusion = fusion
+	}
+}
+
+// GetHybridRecommendations 用例：混合推荐（关注推荐 + 热度推荐）
+//
+// 流程：
+// 1. 并发执行各策略的生成器（权重为 0 的策略不执行）
+// 2. 融合排序器合并各策略的结果，同一个人只出现一次（见 FusionRanker）
+// 3. 按融合后的顺序执行每日曝光配额，取前 Limit 个
+// 4. 补全展示数据，记录曝光
+//
+// 融合方式：默认加权合并（混合分数 = 推荐分数 × 策略权重，同一个人只保留混合分数最高的一条，
+// 推荐理由、分数都来自那个策略）；各策略分数尺度不同时可以配置为倒数排名融合（hybrid.fusion）。
+//
+// 容错：热度推荐只是补充，失败时只返回关注推荐（记录日志）；关注推荐失败时返回错误。
+//
+// 和 GetFollowingBasedRecommendations 的区别：不使用快照、列表缓存，不计算列表版本，
+// 请求中只使用 UserID、Limit、Surface。返回的分数是原来的推荐分数，不是混合分数。
+func (s *RecommendationService) GetHybridRecommendations(
+	ctx context.Context,
+	req *dto.RecommendationRequest,
+) (*dto.RecommendationResponse, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.GetHybridRecommendations")
+	defer span.End()
+	span.SetAttribute(FieldUserID, req.UserID)
+	span.SetAttribute(FieldSurface, string(req.Surface))
+
+	viewerID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if maxLimit := s.surfaces.For(req.Surface).MaxLimit; maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+
+	resp := &dto.RecommendationResponse{Recommendations: []*dto.UserRecommendationDTO{}}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	lists, err := s.generateHybridLists(ctx, viewerID, req.Surface)
+	if err != nil {
+		return nil, err
+	}
+	ranked := blendHybridLists(lists, s.currentHybridFusion())
+	ranked = s.applyExposureQuota(ctx, viewerID, ranked)
+	if limit >= 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	calibrations := s.currentCalibrations()
+	var sampling aggregate.FollowingSampling
+	if following, ok := lists[HybridStrategyFollowing]; ok {
+		sampling = following.FollowingSampling()
+	}
+	resp.Metadata = convertSamplingToMetadata(sampling)
+	resp.Metadata.CalibrationVersion = calibrations.version()
+	if len(ranked) == 0 {
+		return resp, nil
+	}
+
+	recommendations, shown, degraded, err := s.hydrate(ctx, ranked, nil, req.Surface)
+	if err != nil {
+		return nil, err
+	}
+	calibrations.applyCalibration(recommendations, shown)
+	s.recordImpressions(ctx, viewerID, req.Surface, shown, nil)
+	resp.Recommendations = recommendations
+	resp.Degraded = degraded
+	return resp, nil
+}
+
+// currentHybridWeights 辅助方法：混合推荐使用的策略权重
+func (s *RecommendationService) currentHybridWeights() HybridWeights {
+	if s.hybridWeights == nil {
+		return DefaultHybridWeights()
+	}
+	return s.hybridWeights
+}
+
+// currentHybridFusion 辅助方法：混合推荐使用的融合排序器
+func (s *RecommendationService) currentHybridFusion() domainService.FusionRanker {
+	if s.hybridFusion == nil {
+		return domainService.NewWeightedMergeRanker(s.currentHybridWeights())
+	}
+	return s.hybridFusion
+}
+
+// generateHybridLists 辅助方法：并发执行权重大于 0 的策略
+//
+// 返回策略名 → 推荐列表（失败的热度推荐不在结果中）。
+func (s *RecommendationService) generateHybridLists(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+) (map[string]*aggregate.RecommendationList, error) {
+	generator, _, days := s.listSource(surface, 0)
+	weights := s.currentHybridWeights()
+
+	var following, popularity *aggregate.RecommendationList
+	var followingErr, popularityErr error
+	var wg sync.WaitGroup
+	if weights[HybridStrategyFollowing] > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			following, followingErr = generator.GenerateFollowingBasedRecommendations(ctx, viewerID, days)
+		}()
+	}
+	if weights[HybridStrategyPopularity] > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			popularity, popularityErr = generator.GeneratePopularityBasedRecommendations(ctx, viewerID)
+		}()
+	}
+	wg.Wait()
+
+	if followingErr != nil {
+		return nil, followingErr
+	}
+	lists := make(map[string]*aggregate.RecommendationList, 2)
+	if following != nil {
+		lists[HybridStrategyFollowing] = following
+	}
+	switch {
+	case popularityErr != nil:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		s.logger.Log(ctx, LogLevelWarn, "popularity recommendations failed, using following only", map[string]any{
+			FieldUserID: viewerID.Value(),
+			FieldError:  popularityErr.Error(),
+		})
+	case popularity != nil:
+		lists[HybridStrategyPopularity] = popularity
+	}
+	return lists, nil
+}
+
+// blendHybridLists 辅助函数：用融合排序器合并各策略的推荐列表（每个策略按推荐分数降序作为一路）
+func blendHybridLists(
+	lists map[string]*aggregate.RecommendationList,
+	fusion domainService.FusionRanker,
+) []*aggregate.UserRecommendation {
+	rankings := make(map[string][]*aggregate.UserRecommendation, len(lists))
+	for strategy, list := range lists {
+		rankings[strategy] = list.GetTopN(list.Count())
+	}
+	return fusion.Fuse(rankings)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+func TestBlendHybridLists(t *testing.T) {
+	viewerID, _ := valueobject.NewUserID(1)
+	rec := func(targetID int64, reason valueobject.RecommendationReason, posts int) *aggregate.UserRecommendation {
+		target, _ := valueobject.NewUserID(targetID)
+		r, err := aggregate.NewUserRecommendation(target, reason, posts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	following := aggregate.NewRecommendationList(viewerID)
+	_ = following.AddRecommendation(rec(10, valueobject.NewFollowedByFollowingReason(userIDs(2, 3)), 0)) // 20
+	_ = following.AddRecommendation(rec(11, valueobject.NewFollowedByFollowingReason(userIDs(2)), 0))    // 10
+	popularity := aggregate.NewRecommendationList(viewerID)
+	_ = popularity.AddRecommendation(rec(11, valueobject.NewPopularInNetworkReason(userIDs(2, 3, 4)), 10)) // 25 × 0.5
+	_ = popularity.AddRecommendation(rec(12, valueobject.NewPopularInNetworkReason(userIDs(2, 3, 4)), 0))  // 5 × 0.5
+
+	ranked := blendHybridLists(map[string]*aggregate.RecommendationList{
+		HybridStrategyFollowing:  following,
+		HybridStrategyPopularity: popularity,
+	}, domainService.NewWeightedMergeRanker(DefaultHybridWeights()))
+
+	// 11 只保留混合分数更高的热度推荐（12.5 > 10）
+	want := []struct {
+		userID int64
+		reason valueobject.ReasonType
+	}{
+		{10, valueobject.ReasonFollowedByFollowing},
+		{11, valueobject.ReasonPopularInNetwork},
+		{12, valueobject.ReasonPopularInNetwork},
+	}
+	if len(ranked) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(ranked), len(want))
+	}
+	for i, w := range want {
+		if ranked[i].TargetUserID().Value() != w.userID || ranked[i].Reason().Type() != w.reason {
+			t.Errorf("ranked[%d] = user %d (%v), want user %d (%v)",
+				i, ranked[i].TargetUserID().Value(), ranked[i].Reason().Type(), w.userID, w.reason)
+		}
+	}
+}
+
+func TestParseHybridWeights(t *testing.T) {
+	weights, err := ParseHybridWeights(map[string]float64{HybridStrategyPopularity: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weights[HybridStrategyFollowing] != 1 || weights[HybridStrategyPopularity] != 0 {
+		t.Errorf("weights = %v, want following 1 (default), popularity 0", weights)
+	}
+	if _, err := ParseHybridWeights(map[string]float64{"trending": 1}); err == nil {
+		t.Error("unknown strategy: want error")
+	}
+	if _, err := ParseHybridWeights(map[string]float64{HybridStrategyFollowing: -1}); err == nil {
+		t.Error("negative weight: want error")
+	}
+}
+
+func TestGetHybridRecommendations(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	resp, err := s.GetHybridRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 2 {
+		t.Fatalf("len(Recommendations) = %d, want 2", len(resp.Recommendations))
+	}
+	// 每个人只出现一次；10 被两个人关注，混合分数最高
+	seen := make(map[int64]bool)
+	for _, rec := range resp.Recommendations {
+		if seen[rec.UserID] {
+			t.Errorf("user %d returned twice", rec.UserID)
+		}
+		seen[rec.UserID] = true
+	}
+	if resp.Recommendations[0].UserID != 10 {
+		t.Errorf("Recommendations[0].UserID = %d, want 10", resp.Recommendations[0].UserID)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"service/domain/aggregate"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+// InterleavingExperiment 一个展示场景上的交错实验：比较两个排序器
+//
+// 参与实验的用户看到的是两个排序器结果的交错列表（domainService.TeamDraftInterleave），
+// 每条曝光记下它来自哪个排序器；之后按归因到的关注计算每个排序器的胜率
+// （FeedbackAnalyticsService.QueryInterleaving）。
+//
+// 用户分桶：按"实验名 + 用户"哈希，同一个用户在同一个实验中一直参与或一直不参与，
+// 不同实验的参与用户互相独立。
+//
+// 硬币：按"用户 + 日期"生成随机种子（和关注列表采样一样），
+// 同一天内反复刷新看到的交错顺序不变。
+type InterleavingExperiment struct {
+	Name           string // 实验名（写入曝光记录，最长 32 个字符）
+	TrafficPercent int    // 参与实验的用户比例（0~100）
+	RankerA        domainService.Ranker
+	RankerB        domainService.Ranker
+}
+
+// Includes 用户是否参与实验
+func (e *InterleavingExperiment) Includes(userID valueobject.UserID) bool {
+	if e == nil || e.TrafficPercent <= 0 {
+		return false
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", e.Name, userID.Value())
+	return int(h.Sum32()%100) < e.TrafficPercent
+}
+
+// interleavingAssignment 一次请求的交错结果：实验名 + 每条推荐来自哪个排序器
+type interleavingAssignment struct {
+	experiment string
+	teams      map[valueobject.RecommendationID]domainService.InterleavingTeam
+}
+
+// teamOf 推荐来自哪个排序器（a 为 nil 或推荐不在交错列表中时为空）
+func (a *interleavingAssignment) teamOf(rec *aggregate.UserRecommendation) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	team, ok := a.teams[rec.ID()]
+	return string(team), ok
+}
+
+// interleave 辅助方法：展示场景开启了交错实验、用户参与实验时，把排好序的推荐换成交错列表
+//
+// 不参与实验时原样返回（assignment 为 nil）。
+// 交错在曝光配额之后执行：两个排序器排的是同一批已经执行过配额的候选。
+func (s *RecommendationService) interleave(
+	viewerID valueobject.UserID,
+	experiment *InterleavingExperiment,
+	ranked []*aggregate.UserRecommendation,
+	limit int,
+	now time.Time,
+) ([]*aggregate.UserRecommendation, *interleavingAssignment) {
+	if !experiment.Includes(viewerID) {
+		return ranked, nil
+	}
+	day := now.Year()*1000 + now.YearDay()
+	rng := rand.New(rand.NewPCG(uint64(viewerID.Value()), uint64(day)))
+	interleaved := domainService.TeamDraftInterleave(
+		experiment.RankerA.Rank(ranked),
+		experiment.RankerB.Rank(ranked),
+		limit,
+		func() bool { return rng.IntN(2) == 0 },
+	)
+
+	assignment := &interleavingAssignment{
+		experiment: experiment.Name,
+		teams:      make(map[valueobject.RecommendationID]domainService.InterleavingTeam, len(interleaved)),
+	}
+	page := make([]*aggregate.UserRecommendation, 0, len(interleaved))
+	for _, item := range interleaved {
+		page = append(page, item.Recommendation)
+		assignment.teams[item.Recommendation.ID()] = item.Team
+	}
+	s.metrics.IncCounter(MetricInterleavedResponses, 1, map[string]string{
+		"experiment": experiment.Name,
+	})
+	return page, assignment
+}
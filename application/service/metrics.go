@@ -0,0 +1,107 @@
+package service
+
+// Metrics 监控指标接口
+//
+// 为什么定义在应用层？
+// 和 UserRPCClient 一样，监控是技术细节：
+// 应用层只声明"我要记录什么"，具体上报到哪里（日志、Prometheus 等）
+// 由基础设施层实现。
+//
+// 指标命名约定：
+// - 使用 snake_case，以 recommendation_ 开头
+// - 计数器以 _total 结尾
+// - 直方图带单位后缀（如 _seconds），没有单位时用复数名词（如 _candidates）
+//
+// 使用示例：
+//
+//	s.metrics.IncCounter("recommendation_excluded_total", 3, map[string]string{
+//	    "reason": "high_risk",
+//	})
+type Metrics interface {
+	// IncCounter 计数器累加
+	//
+	// 参数：
+	// - name: 指标名
+	// - delta: 增量
+	// - labels: 指标标签（可以为 nil）
+	IncCounter(name string, delta int, labels map[string]string)
+
+	// ObserveHistogram 直方图记录一个观测值（耗时、数量等分布）
+	//
+	// 参数：
+	// - name: 指标名
+	// - value: 观测值（耗时用秒）
+	// - labels: 指标标签（可以为 nil）
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+// 指标名常量
+const (
+	// MetricExcludedCandidates 生成推荐时被业务规则排除的候选数（标签：reason）
+	MetricExcludedCandidates = "recommendation_excluded_total"
+	// MetricModeratedPreviews 被内容安全策略遮挡或移除的帖子预览数（标签：surface、status）
+	MetricModeratedPreviews = "recommendation_moderated_previews_total"
+	// MetricQuotaSuppressed 因为每日曝光配额用完而没有展示的推荐数
+	MetricQuotaSuppressed = "recommendation_quota_suppressed_total"
+	// MetricPartialResponses 因为超时预算用完而只返回部分推荐的响应数（标签：surface）
+	MetricPartialResponses = "recommendation_partial_responses_total"
+	// MetricNotModifiedResponses 列表版本没有变化、省掉补全的条件请求数（标签：surface）
+	MetricNotModifiedResponses = "recommendation_not_modified_total"
+	// MetricHotCacheRequests 进程内热点缓存的查询次数（标签：cache、result=hit/miss），用于计算命中率
+	MetricHotCacheRequests = "recommendation_hot_cache_requests_total"
+	// MetricHotCacheSets 写入进程内热点缓存的次数（标签：cache、result=offered/rejected/dropped），用于计算准入率
+	MetricHotCacheSets = "recommendation_hot_cache_sets_total"
+	// MetricReadThroughLoads 读穿透缓存的回源次数（标签：cache、trigger=miss/refresh、result=ok/error）
+	MetricReadThroughLoads = "recommendation_read_through_loads_total"
+	// MetricDualReadComparisons 存储迁移双读的比较次数（标签：repository、method、result=match/mismatch/shadow_error/skipped）
+	MetricDualReadComparisons = "recommendation_dual_read_comparisons_total"
+	// MetricLoadShed 因为过载在入口处被拒绝的请求数（标签：reason=queue_full/queue_timeout/no_budget/low_priority）
+	MetricLoadShed = "recommendation_load_shed_total"
+	// MetricRetentionPurgedRows 因为超过数据保留期被删除的数据条数（标签：class）
+	MetricRetentionPurgedRows = "recommendation_retention_purged_rows_total"
+	// MetricRetentionExceeded 执行保留期后仍有超期数据的次数（标签：class）
+	MetricRetentionExceeded = "recommendation_retention_exceeded_total"
+	// MetricAbuseFlagged 发现的异常请求模式次数（标签：pattern=caller_rate/enumeration/target_rate/batch_size）
+	MetricAbuseFlagged = "recommendation_abuse_flagged_total"
+	// MetricDownstreamGoverned 被下游治理器拒绝的调用、重试、对冲次数
+	// （标签：dependency、reason=breaker_open/throttled/breaker_not_closed/unhealthy/budget_exhausted）
+	MetricDownstreamGoverned = "recommendation_downstream_governed_total"
+	// MetricPrecomputedResponses 直接使用快照、没有重新生成的响应数（标签：surface、freshness）
+	MetricPrecomputedResponses = "recommendation_precomputed_responses_total"
+	// MetricInterleavedResponses 返回交错实验列表的响应数（标签：experiment）
+	MetricInterleavedResponses = "recommendation_interleaved_responses_total"
+	// MetricRepeatsDemoted 因为最近展示过而排到后面的推荐数（标签：surface）
+	MetricRepeatsDemoted = "recommendation_repeats_demoted_total"
+	// MetricDependencyFallbacks 依赖降级的次数（标签：dependency、from、to、reason=error/timeout/empty/unhealthy，见 DegradationPolicy）
+	MetricDependencyFallbacks = "recommendation_dependency_fallbacks_total"
+	// MetricProfileMisses user 服务没有返回资料的推荐数（标签：surface、action = drop / backfill / placeholder）
+	MetricProfileMisses = "recommendation_profile_misses_total"
+	// MetricPersistedLists 查找持久化推荐列表的次数（标签：result=reused/stale/missing/error，见 WithRecommendationRepository）
+	MetricPersistedLists = "recommendation_persisted_lists_total"
+	// MetricPrecomputedLists 预计算处理的用户数（标签：result=generated/fresh/erased/failed，见 ListPrecomputer）
+	MetricPrecomputedLists = "recommendation_precomputed_lists_total"
+	// MetricListCacheRequests 查询推荐列表缓存的次数（标签：strategy、result=hit/miss/error，见 WithRecommendationListCache）
+	MetricListCacheRequests = "recommendation_list_cache_requests_total"
+	// MetricDownstreamRequests 下游 HTTP 调用次数（标签：dependency、attempt=primary/retry/hedge、result=success/failure），用于计算错误率
+	MetricDownstreamRequests = "recommendation_downstream_requests_total"
+	// MetricExperimentRequests 参与 A/B 实验的请求数（标签：experiment、variant），用于核对各分组的流量比例
+	MetricExperimentRequests = "recommendation_experiment_requests_total"
+	// MetricConsumedMessages 消费的消息数（标签：consumer、result=processed/retried/dead_letter/dropped），
+	// retried 持续增加说明下游有问题，dead_letter 需要人工处理
+	MetricConsumedMessages = "recommendation_consumed_messages_total"
+)
+
+// 直方图指标名常量
+const (
+	// MetricRPCDuration RPC 请求的处理耗时，秒（标签：method、result=ok/biz_error/error）
+	MetricRPCDuration = "recommendation_rpc_duration_seconds"
+	// MetricGeneratedCandidates 每次调用生成器得到的推荐数（标签：strategy；使用缓存、持久化的列表时不记录）
+	MetricGeneratedCandidates = "recommendation_generated_candidates"
+)
+
+// noopMetrics 空实现：没有注入 Metrics 时使用，避免到处判断 nil
+type noopMetrics struct{}
+
+func (noopMetrics) IncCounter(name string, delta int, labels map[string]string) {}
+
+func (noopMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
@@ -0,0 +1,87 @@
+package service
+
+import "context"
+
+// Logger 日志接口
+//
+// 和 Metrics 一样定义在应用层：
+// 应用层只声明"我要记录什么"，写到哪里（stdout、文件、日志平台）由基础设施层实现。
+//
+// 字段约定（重要）：
+// 用户数据（用户ID、昵称、简介、帖子内容）只能放在 fields 里，并使用下面的 Field* 字段名，
+// 不要拼进 msg。基础设施层的脱敏装饰器按字段名识别并脱敏，拼进 msg 的内容无法识别。
+//
+// 使用示例：
+//
+//	s.logger.Log(ctx, LogLevelWarn, "exposure quota lookup failed", map[string]any{
+//	    FieldUserID: userID.Value(),
+//	    FieldError:  err.Error(),
+//	})
+type Logger interface {
+	// Log 记录一条日志（fields 可以为 nil）
+	Log(ctx context.Context, level LogLevel, msg string, fields map[string]any)
+}
+
+// LogLevel 日志级别
+type LogLevel string
+
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+// Tracer 链路追踪接口
+//
+// 同样只声明"我要追踪什么"，具体接入哪个追踪系统由基础设施层实现。
+// span 属性和日志字段使用同一套字段名，脱敏规则也相同。
+type Tracer interface {
+	// StartSpan 开始一个 span，返回携带该 span 的 ctx
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span 追踪中的一个操作
+type Span interface {
+	// SetAttribute 设置属性（key 使用 Field* 字段名）
+	SetAttribute(key string, value any)
+	// End 结束 span
+	End()
+}
+
+// 日志字段 / span 属性名常量
+const (
+	// 用户标识
+	FieldUserID       = "user_id"
+	FieldViewerID     = "viewer_id"
+	FieldTargetUserID = "target_user_id"
+
+	// 用户内容
+	FieldNickname    = "nickname"
+	FieldBio         = "bio"
+	FieldPostContent = "post_content"
+
+	// 非敏感字段
+	FieldSurface    = "surface"
+	FieldCount      = "count"
+	FieldError      = "error"
+	FieldStep       = "step"
+	FieldDurationMS = "duration_ms"
+)
+
+// noopLogger 空实现：没有注入 Logger 时使用
+type noopLogger struct{}
+
+func (noopLogger) Log(ctx context.Context, level LogLevel, msg string, fields map[string]any) {}
+
+// noopTracer 空实现：没有注入 Tracer 时使用
+type noopTracer struct{}
+
+func (noopTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value any) {}
+func (noopSpan) End()                               {}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+const (
+	defaultSuggestedPostsLimit = 20
+	maxSuggestedPostsLimit     = 50
+	suggestedPostsPerAuthor    = 3 // 每个作者最多取几篇最近的帖子作为候选
+)
+
+// GetSuggestedPosts 用例：探索页的帖子推荐
+//
+// 候选和用户推荐相同（"我关注的人最近关注的人"，过滤器、采样都一样），
+// 推荐的是这些作者最近的帖子，见 RecommendationGenerator.GeneratePostRecommendations。
+//
+// 和用户推荐的区别：
+// - 不使用快照、不计算列表版本：探索页每次打开都重新拉取
+// - 不记录曝光：曝光记录的是"推荐了哪个人"，帖子的曝光由内容服务统计
+// - 同一个作者可能出现多篇帖子（按分数排序，由客户端决定是否合并）
+//
+// 每篇帖子按展示场景执行内容安全策略；作者资料获取失败时只返回作者ID。
+func (s *RecommendationService) GetSuggestedPosts(
+	ctx context.Context,
+	req *dto.PostRecommendationRequest,
+) (*dto.PostRecommendationResponse, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.GetSuggestedPosts")
+	defer span.End()
+	span.SetAttribute(FieldUserID, req.UserID)
+
+	viewerID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	surface := req.Surface
+	if surface == dto.SurfaceDefault {
+		surface = dto.SurfaceExplore
+	}
+	span.SetAttribute(FieldSurface, string(surface))
+
+	policy := s.surfaces.For(surface)
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSuggestedPostsLimit
+	}
+	limit = min(limit, maxSuggestedPostsLimit)
+	if policy.MaxLimit > 0 {
+		limit = min(limit, policy.MaxLimit)
+	}
+
+	resp := &dto.PostRecommendationResponse{Posts: []*dto.PostRecommendationDTO{}}
+	// 用户已删除数据：不生成推荐
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	generator := s.generator
+	if policy.Generator != nil {
+		generator = policy.Generator
+	}
+	recommendations, err := generator.GeneratePostRecommendations(ctx, viewerID, policy.recencyDays(), suggestedPostsPerAuthor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 推荐理由文案批量获取（只调用一次配置服务）
+	reasons := make([]valueobject.RecommendationReason, 0, len(recommendations))
+	for _, rec := range recommendations {
+		reasons = append(reasons, rec.Reason())
+	}
+	reasonTexts := s.prefetchReasonTexts(ctx, reasons)
+
+	// 先执行内容安全策略再截断：被移除的帖子不占用这一页的位置
+	for _, rec := range recommendations {
+		if len(resp.Posts) == limit {
+			break
+		}
+		post := rec.Post()
+		postDTO := s.applyContentSafety(surface, &dto.PostDTO{
+			PostID:    post.ID().Value(),
+			Content:   post.Content(),
+			CreatedAt: post.CreatedAt(),
+		}, post.ModerationStatus())
+		if postDTO == nil {
+			continue
+		}
+		resp.Posts = append(resp.Posts, &dto.PostRecommendationDTO{
+			RecommendationID: rec.ID().Value(),
+			PostID:           postDTO.PostID,
+			AuthorID:         rec.AuthorID().Value(),
+			Content:          postDTO.Content,
+			CreatedAt:        postDTO.CreatedAt,
+			Masked:           postDTO.Masked,
+			Reason:           s.getReasonText(ctx, rec.Reason(), reasonTexts),
+			Score:            rec.Score(),
+		})
+	}
+	if len(resp.Posts) == 0 {
+		return resp, nil
+	}
+
+	authorIDs := make([]int64, 0, len(resp.Posts))
+	for _, post := range resp.Posts {
+		authorIDs = append(authorIDs, post.AuthorID)
+	}
+	authors, err := s.getUserInfoMap(ctx, authorIDs)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "get author info for suggested posts failed", map[string]any{
+			FieldUserID: req.UserID,
+			FieldError:  err.Error(),
+		})
+	}
+	for _, post := range resp.Posts {
+		if info, ok := authors[post.AuthorID]; ok {
+			post.AuthorUsername, post.AuthorAvatar = info.Username, info.Avatar
+		}
+	}
+	return resp, nil
+}
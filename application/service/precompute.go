@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ErrPrecomputeUnavailable 推荐服务没有推荐列表仓储（recommendations 端口为 none），预计算的结果没有地方保存
+var ErrPrecomputeUnavailable = errors.New("precompute requires a recommendation repository")
+
+// 一个用户的预计算结果（MetricPrecomputedLists 的 result 标签）
+const (
+	precomputeGenerated = "generated"
+	precomputeFresh     = "fresh"
+	precomputeErased    = "erased"
+	precomputeFailed    = "failed"
+)
+
+// WithActiveUsersRepository 记录请求推荐的用户（预计算只为最近活跃的用户生成推荐列表）
+//
+// 每次请求写入一次最近活跃时间；写入失败只记日志，不影响请求。
+func WithActiveUsersRepository(repo repository.ActiveUsersRepository) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.activeUsers = repo
+	}
+}
+
+// touchActiveUser 辅助方法：记录用户在请求推荐（没有注入 ActiveUsersRepository 时什么都不做）
+func (s *RecommendationService) touchActiveUser(ctx context.Context, userID valueobject.UserID) {
+	if s.activeUsers == nil {
+		return
+	}
+	if err := s.activeUsers.TouchUser(ctx, userID, time.Now()); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "record active user failed", map[string]any{
+			FieldUserID: userID.Value(),
+			FieldError:  err.Error(),
+		})
+	}
+}
+
+// PrecomputeConfig 预计算参数
+type PrecomputeConfig struct {
+	// ActiveWindow 多久以内请求过推荐的用户算作活跃，默认 24 小时
+	ActiveWindow time.Duration
+	// MaxUsers 每个租户每轮最多处理多少个用户（最近活跃的优先），默认 10000
+	MaxUsers int
+	// RefreshAfter 列表生成超过这个时间才重新生成（应小于在线请求使用持久化列表的 listMaxAge）
+	RefreshAfter time.Duration
+	// Concurrency 同时生成的用户数，默认 4
+	Concurrency int
+}
+
+// ListPrecomputer 预计算：为最近活跃的用户提前生成并保存推荐列表
+//
+// 为什么需要预计算？
+// 召回、打分是请求中最贵的部分，而活跃用户的请求集中在少数人身上。
+// 提前为他们生成好列表，在线请求在 listMaxAge 内直接使用持久化的列表（见 WithRecommendationRepository），
+// 只有没有预计算过的用户（不活跃、新用户）才回退到实时生成。
+//
+// 和在线生成保存的是同一份列表（默认的生成器、默认时间窗口），
+// 在线请求刚生成过的、还足够新的列表不重复生成。
+//
+// 多实例部署时：
+// 列表保存在共享的仓储中，只需要一个实例（或者单独的 worker 部署）开启预计算；
+// 多个实例同时开启也不影响正确性，只是重复生成。
+type ListPrecomputer struct {
+	recommendations *RecommendationService
+	activeUsers     repository.ActiveUsersRepository
+	tenants         *Tenants
+	cfg             PrecomputeConfig
+}
+
+// NewListPrecomputer 构造函数（没有设置的参数使用默认值）
+//
+// 推荐服务没有推荐列表仓储时返回 ErrPrecomputeUnavailable。
+func NewListPrecomputer(
+	recommendations *RecommendationService,
+	activeUsers repository.ActiveUsersRepository,
+	tenants *Tenants,
+	cfg PrecomputeConfig,
+) (*ListPrecomputer, error) {
+	if recommendations.listRepo == nil {
+		return nil, ErrPrecomputeUnavailable
+	}
+	if cfg.ActiveWindow <= 0 {
+		cfg.ActiveWindow = 24 * time.Hour
+	}
+	if cfg.MaxUsers <= 0 {
+		cfg.MaxUsers = 10000
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+	return &ListPrecomputer{
+		recommendations: recommendations,
+		activeUsers:     activeUsers,
+		tenants:         tenants,
+		cfg:             cfg,
+	}, nil
+}
+
+// RunOnce 为每个租户最近活跃的用户执行一轮预计算
+//
+// 单个用户失败只计入报告（下一轮重试），不影响其他用户；ctx 取消时停止，报告中是已经处理的部分。
+func (p *ListPrecomputer) RunOnce(ctx context.Context) *dto.PrecomputeReport {
+	report := &dto.PrecomputeReport{RanAt: time.Now()}
+	for _, tenant := range p.tenants.IDs() {
+		if ctx.Err() != nil {
+			break
+		}
+		report.Tenants = append(report.Tenants, p.runTenant(valueobject.WithTenant(ctx, tenant), tenant))
+	}
+	return report
+}
+
+// runTenant 辅助方法：一个租户的一轮预计算
+func (p *ListPrecomputer) runTenant(ctx context.Context, tenant valueobject.TenantID) *dto.PrecomputeTenantReport {
+	start := time.Now()
+	report := &dto.PrecomputeTenantReport{Tenant: tenant.String()}
+	users, err := p.activeUsers.ListActiveUsers(ctx, start.Add(-p.cfg.ActiveWindow), p.cfg.MaxUsers)
+	if err != nil {
+		report.Error = fmt.Sprintf("list active users: %v", err)
+		report.Duration = time.Since(start)
+		return report
+	}
+	report.ActiveUsers = len(users)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.cfg.Concurrency)
+	for _, userID := range users {
+		if ctx.Err() != nil {
+			break
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(userID valueobject.UserID) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			result := p.recommendations.precomputeList(ctx, userID, p.cfg.RefreshAfter)
+			mu.Lock()
+			defer mu.Unlock()
+			switch result {
+			case precomputeGenerated:
+				report.Generated++
+			case precomputeFresh:
+				report.Fresh++
+			case precomputeErased:
+				report.Erased++
+			default:
+				report.Failed++
+			}
+		}(userID)
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+	return report
+}
+
+// precomputeList 辅助方法：为一个用户生成并保存推荐列表（已有足够新的列表时跳过）
+//
+// 和 generateList 的第 3 步相同（默认的生成器、默认时间窗口，保存后写入缓存），
+// 区别是保存失败算作失败：预计算的结果只有保存下来才有用。
+func (s *RecommendationService) precomputeList(ctx context.Context, userID valueobject.UserID, refreshAfter time.Duration) string {
+	result, err := s.doPrecomputeList(ctx, userID, refreshAfter)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "precompute recommendation list failed", map[string]any{
+			FieldUserID: userID.Value(),
+			FieldError:  err.Error(),
+		})
+	}
+	s.metrics.IncCounter(MetricPrecomputedLists, 1, map[string]string{"result": result})
+	return result
+}
+
+// doPrecomputeList 辅助方法：precomputeList 的实现（失败时 result 为 failed）
+func (s *RecommendationService) doPrecomputeList(ctx context.Context, userID valueobject.UserID, refreshAfter time.Duration) (string, error) {
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, userID)
+		if err != nil {
+			return precomputeFailed, err
+		}
+		if erased {
+			return precomputeErased, nil
+		}
+	}
+	latest, err := s.listRepo.GetLatestListForUser(ctx, userID)
+	if err != nil {
+		return precomputeFailed, err
+	}
+	if latest != nil && time.Since(latest.GeneratedAt()) < refreshAfter {
+		return precomputeFresh, nil
+	}
+
+	list, err := s.generator.GenerateFollowingBasedRecommendations(ctx, userID, defaultRecencyDays)
+	if err != nil {
+		return precomputeFailed, err
+	}
+	s.metrics.ObserveHistogram(MetricGeneratedCandidates, float64(list.Count()), map[string]string{"strategy": DefaultListStrategy})
+	if err := s.listRepo.SaveList(ctx, list); err != nil {
+		return precomputeFailed, err
+	}
+	s.cacheList(ctx, listCacheKey(ctx, DefaultListStrategy, userID, defaultRecencyDays), list)
+	return precomputeGenerated, nil
+}
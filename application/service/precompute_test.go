@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// userListRepo 测试用推荐列表仓储：每个用户一份（预计算并发写入）
+type userListRepo struct {
+	memoryListRepo
+	mu    sync.Mutex
+	lists map[valueobject.UserID]*aggregate.RecommendationList
+}
+
+func (r *userListRepo) SaveList(ctx context.Context, list *aggregate.RecommendationList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists[list.ForUserID()] = list
+	return nil
+}
+
+func (r *userListRepo) GetLatestListForUser(ctx context.Context, userID valueobject.UserID) (*aggregate.RecommendationList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lists[userID], nil
+}
+
+// fakeActiveUsers 测试用活跃用户：按写入顺序返回
+type fakeActiveUsers struct {
+	mu      sync.Mutex
+	touched []valueobject.UserID
+}
+
+func (r *fakeActiveUsers) TouchUser(ctx context.Context, userID valueobject.UserID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touched = append(r.touched, userID)
+	return nil
+}
+
+func (r *fakeActiveUsers) ListActiveUsers(ctx context.Context, since time.Time, limit int) ([]valueobject.UserID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.touched) > limit {
+		return r.touched[:limit], nil
+	}
+	return r.touched, nil
+}
+
+func (r *fakeActiveUsers) DeleteUser(ctx context.Context, userID valueobject.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.touched[:0]
+	for _, touched := range r.touched {
+		if touched != userID {
+			kept = append(kept, touched)
+		}
+	}
+	r.touched = kept
+	return nil
+}
+
+func TestListPrecomputer_RunOnce(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	if _, err := NewListPrecomputer(s, &fakeActiveUsers{}, nil, PrecomputeConfig{}); !errors.Is(err, ErrPrecomputeUnavailable) {
+		t.Fatalf("without a list repository: err = %v, want ErrPrecomputeUnavailable", err)
+	}
+
+	repo := &userListRepo{lists: make(map[valueobject.UserID]*aggregate.RecommendationList)}
+	listCache := memoryListCache{}
+	WithRecommendationRepository(repo, 10*time.Minute)(s)
+	WithRecommendationListCache(listCache)(s)
+	WithUserErasureRepository(&fakeErasureRepo{erased: map[valueobject.UserID]bool{userIDs(3)[0]: true}})(s)
+	activeUsers := &fakeActiveUsers{}
+	WithActiveUsersRepository(activeUsers)(s)
+	ctx := context.Background()
+
+	// 请求推荐的用户记录为活跃
+	if _, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if len(activeUsers.touched) != 1 || activeUsers.touched[0].Value() != 1 {
+		t.Fatalf("touched = %v, want [1]", activeUsers.touched)
+	}
+
+	// 1 刚刚由在线请求生成过；2 的列表已经旧了；3 已删除数据；4 没有列表；5 超过每轮上限
+	now := time.Now()
+	repo.lists[userIDs(2)[0]] = aggregate.RestoreRecommendationList(userIDs(2)[0], nil, now.Add(-8*time.Minute), aggregate.FollowingSampling{})
+	activeUsers.touched = append(activeUsers.touched, userIDs(2, 3, 4, 5)...)
+	clear(listCache)
+
+	precomputer, err := NewListPrecomputer(s, activeUsers, nil, PrecomputeConfig{MaxUsers: 4, RefreshAfter: 5 * time.Minute, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := precomputer.RunOnce(ctx)
+	if len(report.Tenants) != 1 {
+		t.Fatalf("tenants = %+v, want the default tenant only", report.Tenants)
+	}
+	got := report.Tenants[0]
+	if got.ActiveUsers != 4 || got.Generated != 2 || got.Fresh != 1 || got.Erased != 1 || got.Failed != 0 || got.Error != "" {
+		t.Errorf("report = %+v, want 4 active users: 2 generated, 1 fresh, 1 erased", got)
+	}
+	for _, id := range []int64{2, 4} {
+		list := repo.lists[userIDs(id)[0]]
+		if list == nil || time.Since(list.GeneratedAt()) > time.Minute {
+			t.Errorf("user %d: list not regenerated", id)
+		}
+		if _, ok := listCache[ListCacheKey{Tenant: valueobject.DefaultTenantID, UserID: id, Strategy: DefaultListStrategy, Days: defaultRecencyDays}]; !ok {
+			t.Errorf("user %d: precomputed list not cached", id)
+		}
+	}
+	if repo.lists[userIDs(3)[0]] != nil || repo.lists[userIDs(5)[0]] != nil {
+		t.Errorf("lists generated for erased or over-limit users")
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+// PriorityTier 用户优先级
+//
+// 为什么需要？
+// 生成推荐的成本对所有用户都一样，但价值不一样：
+// 付费会员、头部创作者看到"刚关注的人马上出现在推荐里"，
+// 和普通用户晚几分钟看到，对业务的影响完全不同。
+// 按优先级区分新鲜度，把生成能力留给最需要的用户：
+// - high：总是重新生成（不使用快照），过载时也不降级为快照
+// - standard：快照没有超过过期阈值时直接使用；过载时只使用快照
+// - low：同 standard，但负载保护先拒绝它（不排队，在途请求较多时直接拒绝）
+type PriorityTier string
+
+const (
+	PriorityHigh     PriorityTier = "high"
+	PriorityStandard PriorityTier = "standard"
+	PriorityLow      PriorityTier = "low"
+)
+
+// ParsePriorityTier 解析优先级（配置、外部信号）
+func ParsePriorityTier(raw string) (PriorityTier, error) {
+	switch tier := PriorityTier(raw); tier {
+	case PriorityHigh, PriorityStandard, PriorityLow:
+		return tier, nil
+	}
+	return "", fmt.Errorf("unknown priority tier %q", raw)
+}
+
+// Freshness 这一档用户对推荐新鲜度的要求（没有过载时）
+func (t PriorityTier) Freshness() dto.Freshness {
+	if t == PriorityHigh {
+		return dto.FreshnessRealtime
+	}
+	return dto.FreshnessDefault
+}
+
+// PriorityTierSource 外部的优先级信号（如会员系统、商业化标签）
+//
+// 在请求路径上调用，实现应该自己缓存结果。
+type PriorityTierSource interface {
+	// UserTier 用户的优先级，没有信号时返回空字符串
+	UserTier(ctx context.Context, userID int64) (PriorityTier, error)
+}
+
+// PriorityTiers 用户优先级表
+//
+// 按顺序确定一个用户的优先级：
+// 1. 配置中列出的用户（按租户，运维可以临时指定）
+// 2. 外部信号（可以为 nil）
+// 3. 默认优先级
+//
+// 外部信号查询失败时使用默认优先级：优先级只影响新鲜度，不应该让请求失败。
+type PriorityTiers struct {
+	users       map[valueobject.TenantID]map[int64]PriorityTier
+	source      PriorityTierSource
+	defaultTier PriorityTier
+}
+
+// NewPriorityTiers 构造函数
+//
+// 参数：
+// - users: 租户 → 用户 → 优先级（可以为 nil）
+// - source: 外部信号（可以为 nil）
+// - defaultTier: 默认优先级（为空时 standard）
+func NewPriorityTiers(
+	users map[valueobject.TenantID]map[int64]PriorityTier,
+	source PriorityTierSource,
+	defaultTier PriorityTier,
+) *PriorityTiers {
+	if defaultTier == "" {
+		defaultTier = PriorityStandard
+	}
+	return &PriorityTiers{users: users, source: source, defaultTier: defaultTier}
+}
+
+// Resolve 用户的优先级（租户从 ctx 中读取）
+func (p *PriorityTiers) Resolve(ctx context.Context, userID int64) PriorityTier {
+	if tier, ok := p.users[valueobject.TenantFromContext(ctx)][userID]; ok {
+		return tier
+	}
+	if p.source != nil {
+		if tier, err := p.source.UserTier(ctx, userID); err == nil && tier != "" {
+			return tier
+		}
+	}
+	return p.defaultTier
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+)
+
+// ProfileMissPolicy user 服务没有返回推荐对象的资料时怎么办
+type ProfileMissPolicy string
+
+const (
+	// ProfileMissDrop 跳过这条推荐（默认，返回的列表可能少于请求的数量）
+	ProfileMissDrop ProfileMissPolicy = "drop"
+	// ProfileMissBackfill 用排在这一页后面的候选补位（补位的候选同样要有资料）
+	ProfileMissBackfill ProfileMissPolicy = "backfill"
+	// ProfileMissPlaceholder 返回只有用户ID的占位资料，标记 ProfilePending，由客户端稍后刷新
+	ProfileMissPlaceholder ProfileMissPolicy = "placeholder"
+)
+
+// maxProfileBackfill 补位时最多多取几个候选（user 服务大面积失败时补位也没有意义）
+const maxProfileBackfill = 10
+
+// ParseProfileMissPolicy 解析配置（为空时使用 ProfileMissDrop）
+func ParseProfileMissPolicy(raw string) (ProfileMissPolicy, error) {
+	switch policy := ProfileMissPolicy(raw); policy {
+	case "":
+		return ProfileMissDrop, nil
+	case ProfileMissDrop, ProfileMissBackfill, ProfileMissPlaceholder:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown profile miss policy %q", raw)
+}
+
+// WithProfileMissPolicy 推荐对象的资料缺失时的处理方式
+//
+// 为什么需要？
+// user 服务偶尔漏掉个别用户（刚注销、复制延迟、批量接口部分失败），
+// 以前这些推荐被直接跳过，客户端要 10 条只拿到 8 条，列表看起来"没填满"。
+// - backfill：用下一名候选补齐，列表长度不变，但排序不再是原来的前 N 名
+// - placeholder：位置不变，资料由客户端按用户ID再取一次
+//
+// 无论哪种方式，缺失的资料都计入 MetricProfileMisses（标签 action 区分处理结果）。
+func WithProfileMissPolicy(policy ProfileMissPolicy) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.profileMiss = policy
+	}
+}
+
+// profileReserve 辅助方法：补位用的候选（排好序的列表中这一页后面的几个）
+//
+// 不是 backfill 策略时返回 nil，不多取资料。
+func (s *RecommendationService) profileReserve(ranked []*aggregate.UserRecommendation, limit int) []*aggregate.UserRecommendation {
+	if s.profileMiss != ProfileMissBackfill || limit < 0 || len(ranked) <= limit {
+		return nil
+	}
+	reserve := ranked[limit:]
+	if len(reserve) > maxProfileBackfill {
+		reserve = reserve[:maxProfileBackfill]
+	}
+	return reserve
+}
+
+// resolveProfileMisses 辅助方法：按策略处理资料缺失的推荐
+//
+// 返回要补全的推荐（保持排序：补位的候选排在这一页原有的推荐后面）。
+// placeholder 策略下，缺失的资料以占位资料写入 userInfos，并记录在 pending 中。
+func (s *RecommendationService) resolveProfileMisses(
+	page []*aggregate.UserRecommendation,
+	reserve []*aggregate.UserRecommendation,
+	userInfos map[int64]*UserInfo,
+	surface dto.Surface,
+) (resolved []*aggregate.UserRecommendation, pending map[int64]bool) {
+	resolved = make([]*aggregate.UserRecommendation, 0, len(page))
+	misses := 0
+	for _, rec := range page {
+		userID := rec.TargetUserID().Value()
+		if _, ok := userInfos[userID]; ok {
+			resolved = append(resolved, rec)
+			continue
+		}
+		misses++
+		if s.profileMiss == ProfileMissPlaceholder {
+			if pending == nil {
+				pending = make(map[int64]bool)
+			}
+			pending[userID] = true
+			userInfos[userID] = &UserInfo{UserID: userID}
+			resolved = append(resolved, rec)
+		}
+	}
+	if misses == 0 {
+		return resolved, nil
+	}
+
+	backfilled := 0
+	for _, rec := range reserve {
+		if len(resolved) == len(page) {
+			break
+		}
+		if _, ok := userInfos[rec.TargetUserID().Value()]; ok {
+			resolved = append(resolved, rec)
+			backfilled++
+		}
+	}
+
+	action := string(ProfileMissDrop)
+	switch {
+	case pending != nil:
+		action = string(ProfileMissPlaceholder)
+	case backfilled > 0:
+		s.metrics.IncCounter(MetricProfileMisses, backfilled, map[string]string{
+			"surface": string(surface),
+			"action":  string(ProfileMissBackfill),
+		})
+		misses -= backfilled
+	}
+	if misses > 0 {
+		s.metrics.IncCounter(MetricProfileMisses, misses, map[string]string{
+			"surface": string(surface),
+			"action":  action,
+		})
+	}
+	return resolved, pending
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// SectionLayout 发现页的一个分栏
+type SectionLayout struct {
+	Key         string   // 分栏标识（如 people_you_may_know）
+	Title       string   // 分栏标题
+	ReasonTypes []string // 放进这个分栏的推荐理由类型（配置服务的类型标识，如 followed_by_following）
+	Limit       int      // 分栏最多展示多少条
+}
+
+// SectionLayoutClient 分栏配置客户端（由推荐理由配置服务提供）
+type SectionLayoutClient interface {
+	// GetSectionLayout 某个展示场景的分栏（按展示顺序），locale 为空时使用默认语言
+	GetSectionLayout(ctx context.Context, surface string, locale string) ([]SectionLayout, error)
+}
+
+// WithSectionLayoutClient 从配置服务获取发现页的分栏（顺序、标题、放哪些推荐）
+func WithSectionLayoutClient(client SectionLayoutClient) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.sectionLayouts = client
+	}
+}
+
+// DefaultSectionLayout 默认的分栏（配置服务不可用或没有配置时使用）
+//
+// "平台新人"需要按注册时间召回的候选来源，接入之前这个分栏总是为空、不会返回。
+func DefaultSectionLayout() []SectionLayout {
+	return []SectionLayout{
+		{Key: "people_you_may_know", Title: "你可能认识的人", ReasonTypes: []string{"followed_by_following"}, Limit: 10},
+		{Key: "popular_creators", Title: "热门创作者", ReasonTypes: []string{"popular_in_network"}, Limit: 10},
+		{Key: "new_on_platform", Title: "平台新人", ReasonTypes: []string{"new_on_platform"}, Limit: 10},
+	}
+}
+
+// GetRecommendationBundle 用例：发现页的分栏推荐
+//
+// 和 GetFollowingBasedRecommendations 共用召回、排序和补全（discovery 场景的推荐行为），
+// 再按推荐理由类型把推荐分到各个分栏：
+// 1. 召回并排序候选（rankCandidates）
+// 2. 获取分栏配置（配置服务失败时使用默认分栏）
+// 3. 按分数从高到低，把每个推荐放进第一个接受它的理由类型、还没有放满的分栏
+// 4. 一次补全所有分栏的推荐，记录曝光
+//
+// 不使用快照、不计算列表版本：发现页每次打开都重新拉取。
+func (s *RecommendationService) GetRecommendationBundle(
+	ctx context.Context,
+	req *dto.RecommendationBundleRequest,
+) (*dto.RecommendationBundleResponse, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.GetRecommendationBundle")
+	defer span.End()
+	span.SetAttribute(FieldUserID, req.UserID)
+
+	viewerID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.RecommendationBundleResponse{Sections: []*dto.RecommendationSectionDTO{}}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	ranked, _, err := s.rankCandidates(ctx, viewerID, dto.SurfaceDiscovery, 0, dto.FreshnessDefault, aggregate.RecommendationCriteria{})
+	if err != nil {
+		return nil, err
+	}
+	layout := s.sectionLayout(ctx)
+	sections := assignSections(layout, ranked)
+
+	// 所有分栏一起补全（一次批量获取用户信息），保持分栏的顺序
+	var page []*aggregate.UserRecommendation
+	for _, section := range sections {
+		page = append(page, section...)
+	}
+	recommendations, shown, degraded, err := s.hydrate(ctx, page, nil, dto.SurfaceDiscovery)
+	if err != nil {
+		return nil, err
+	}
+	s.recordImpressions(ctx, viewerID, dto.SurfaceDiscovery, shown, nil)
+
+	hydrated := make(map[int64]*dto.UserRecommendationDTO, len(recommendations))
+	for _, rec := range recommendations {
+		hydrated[rec.UserID] = rec
+	}
+	for i, section := range sections {
+		items := make([]*dto.UserRecommendationDTO, 0, len(section))
+		for _, rec := range section {
+			if item, ok := hydrated[rec.TargetUserID().Value()]; ok {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		resp.Sections = append(resp.Sections, &dto.RecommendationSectionDTO{
+			Key:             layout[i].Key,
+			Title:           layout[i].Title,
+			Recommendations: items,
+		})
+	}
+	resp.Degraded = degraded
+	return resp, nil
+}
+
+// sectionLayout 辅助方法：当前请求语言的分栏配置
+//
+// 配置服务失败或返回空配置时使用 DefaultSectionLayout（只记日志）。
+func (s *RecommendationService) sectionLayout(ctx context.Context) []SectionLayout {
+	if s.sectionLayouts == nil {
+		return DefaultSectionLayout()
+	}
+	locale := s.tenants.Locale(ctx).String()
+	layout, err := s.sectionLayouts.GetSectionLayout(ctx, string(dto.SurfaceDiscovery), locale)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "get section layout failed, using default", map[string]any{
+			FieldError: err.Error(),
+		})
+		return DefaultSectionLayout()
+	}
+	if len(layout) == 0 {
+		return DefaultSectionLayout()
+	}
+	return layout
+}
+
+// assignSections 辅助函数：按分数顺序把推荐分到分栏（每个推荐最多出现在一个分栏）
+//
+// 返回和 layout 一一对应的推荐（没有推荐的分栏为空）。
+func assignSections(layout []SectionLayout, ranked []*aggregate.UserRecommendation) [][]*aggregate.UserRecommendation {
+	sections := make([][]*aggregate.UserRecommendation, len(layout))
+	for _, rec := range ranked {
+		key := reasonTypeKey(rec.Reason().Type())
+		for i, section := range layout {
+			if len(sections[i]) < section.Limit && slices.Contains(section.ReasonTypes, key) {
+				sections[i] = append(sections[i], rec)
+				break
+			}
+		}
+	}
+	return sections
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ExplainRecommendation 用例：解释为什么把 target 推荐给 viewer（质量评审工具）
+//
+// 为什么需要？
+// 评审人员看到一条奇怪的推荐时，以前只能找开发者加日志复现；
+// 现在按和线上相同的策略重新生成一次，返回这条推荐的每个打分因素贡献了多少分、
+// 排在第几、用的是哪一版打分规则和校准参数、通过了哪些过滤器。
+//
+// 和 GetFollowingBasedRecommendations 的区别：
+// - 只召回、排序（按场景的推荐策略、多样性规则、策略权重），不补全展示数据
+// - 不执行曝光配额、重复展示降权、会话去重（这些取决于用户最近看过什么，不是打分的一部分）
+// - 不记录曝光、特征快照，不保存快照（解释不能影响线上数据）
+func (s *RecommendationService) ExplainRecommendation(
+	ctx context.Context,
+	req *dto.ExplainRecommendationRequest,
+) (*dto.RecommendationExplanationDTO, error) {
+	viewerID, err := valueobject.NewUserID(req.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	targetUserID, err := valueobject.NewUserID(req.TargetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.surfaces.For(req.Surface)
+	generator := s.generator
+	if policy.Generator != nil {
+		generator = policy.Generator
+	}
+	recommendationList, err := generator.GenerateFollowingBasedRecommendations(ctx, viewerID, policy.recencyDays())
+	if err != nil {
+		return nil, err
+	}
+	recommendationList.ApplyDiversity(policy.MaxPerReasonType)
+	recommendationList.ApplyReferrerDiversity(policy.MaxPerReferrer)
+	ranked := s.blendStrategies(ctx, req.Surface, recommendationList.GetTopN(recommendationList.Count()))
+
+	calibrations := s.currentCalibrations()
+	explanation := &dto.RecommendationExplanationDTO{
+		ViewerID:           req.ViewerID,
+		TargetUserID:       req.TargetUserID,
+		Surface:            string(req.Surface),
+		Position:           -1,
+		Candidates:         len(ranked),
+		StrategyWeight:     1,
+		RulesVersion:       aggregate.ScoringRulesVersion,
+		CalibrationVersion: calibrations.version(),
+		FiltersPassed:      generator.FilterNames(),
+	}
+	for i, rec := range ranked {
+		if !rec.TargetUserID().Equals(targetUserID) {
+			continue
+		}
+		breakdown := rec.ScoreBreakdown()
+		reasonType := reasonTypeKey(rec.Reason().Type())
+		explanation.Found = true
+		explanation.ReasonType = reasonType
+		explanation.Position = i
+		explanation.Score = breakdown.Score
+		explanation.RulesVersion = breakdown.RulesVersion
+		explanation.PerRelatedUser = breakdown.Weights.PerRelatedUser
+		explanation.PerRecentPost = breakdown.Weights.PerRecentPost
+		explanation.PerHundredEngagement = breakdown.Weights.PerHundredEngagement
+		explanation.Factors = convertScoreFactors(breakdown.Factors)
+		explanation.StrategyWeight = weightOf(s.bandit.Weights(ctx, string(req.Surface)), reasonType)
+		if calibrated, ok := calibrations.Calibrate(rec); ok {
+			explanation.CalibratedScore = &calibrated
+		}
+		break
+	}
+	return explanation, nil
+}
+
+// applyScoreDetails 辅助函数：调试请求为补全好的推荐填写分数明细（items 和 shown 一一对应）
+//
+// shown 必须是这次生成的推荐：从快照恢复的推荐没有特征，拆出来的明细没有意义。
+func applyScoreDetails(items []*dto.UserRecommendationDTO, shown []*aggregate.UserRecommendation) {
+	for i, item := range items {
+		breakdown := shown[i].ScoreBreakdown()
+		item.ScoreDetails = &dto.ScoreDetailsDTO{
+			Factors:              convertScoreFactors(breakdown.Factors),
+			RulesVersion:         breakdown.RulesVersion,
+			PerRelatedUser:       breakdown.Weights.PerRelatedUser,
+			PerRecentPost:        breakdown.Weights.PerRecentPost,
+			PerHundredEngagement: breakdown.Weights.PerHundredEngagement,
+		}
+	}
+}
+
+// convertScoreFactors 辅助函数：打分因素 -> DTO 转换
+func convertScoreFactors(factors []aggregate.ScoreFactor) []*dto.ScoreFactorDTO {
+	result := make([]*dto.ScoreFactorDTO, 0, len(factors))
+	for _, factor := range factors {
+		result = append(result, &dto.ScoreFactorDTO{
+			Name:         factor.Name,
+			Value:        factor.Value,
+			Contribution: factor.Contribution,
+		})
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/event"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+var (
+	// ErrInvalidRecommendationID 推荐ID不是响应中返回的格式
+	ErrInvalidRecommendationID = errors.New("invalid recommendation id")
+	// ErrRecommendationNotShown 推荐ID没有展示给这个用户（或者曝光记录已经超过保留期）
+	ErrRecommendationNotShown = errors.New("recommendation not shown to user")
+)
+
+// WithFeedback 开启推荐反馈的记录：客户端上报点开、关注，写入反馈记录并发布领域事件
+//
+// publisher 为 nil 时只写入反馈记录，不发布事件。
+// 依赖曝光记录（WithImpressionRepository）：反馈只能针对展示过的推荐。
+func WithFeedback(repo repository.FeedbackRepository, publisher event.Publisher) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.feedbackRepo = repo
+		s.eventPublisher = publisher
+	}
+}
+
+// RecordRecommendationFeedback 用例：记录用户对一条推荐的反馈（点开、在卡片上关注）
+//
+// 为什么需要？
+// 曝光和归因转化只能回答"展示了之后有没有关注"，回答不了"用户有没有点开看"；
+// 排序团队要按推荐理由类型、场景计算点击率和卡片上的关注率，并把它们用作打分信号。
+//
+// 步骤：
+// 1. 校验反馈类型、推荐ID
+// 2. 查找展示给这个用户的曝光（不是展示给他的推荐返回 ErrRecommendationNotShown）
+// 3. 写入反馈记录（同一条推荐的同一种反馈只记录一次）
+// 4. 新的反馈发布 RecommendationFeedbackRecorded 事件
+//
+// 步骤 2、3 在一个事务中（WithTransactionManager），事件在事务提交之后发布：
+// 事务回滚时不会发布一条没有保存的反馈。
+//
+// 事件发布失败只记日志：反馈记录已经保存，离线任务可以从反馈表补齐。
+// 没有开启反馈记录（WithFeedback）或者没有曝光记录时不记录，返回 nil。
+func (s *RecommendationService) RecordRecommendationFeedback(
+	ctx context.Context,
+	userID int64,
+	recommendationID string,
+	action string,
+) error {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.RecordRecommendationFeedback")
+	defer span.End()
+	span.SetAttribute(FieldUserID, userID)
+
+	viewerID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return err
+	}
+	feedbackAction, err := valueobject.ParseFeedbackAction(action)
+	if err != nil {
+		return err
+	}
+	id, err := valueobject.RecommendationIDFromString(recommendationID)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidRecommendationID, recommendationID)
+	}
+	if s.feedbackRepo == nil || s.impressionRepo == nil {
+		return nil
+	}
+
+	var feedback *entity.RecommendationFeedback
+	var recorded bool
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		impression, ok, err := s.impressionRepo.FindImpression(ctx, viewerID, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrRecommendationNotShown, recommendationID)
+		}
+		feedback = entity.NewRecommendationFeedback(impression, feedbackAction, time.Now())
+		recorded, err = s.feedbackRepo.RecordFeedback(ctx, feedback)
+		return err
+	})
+	if err != nil || !recorded {
+		return err
+	}
+	if s.eventPublisher == nil {
+		return nil
+	}
+	recordedEvent := feedback.RecordedEvent(valueobject.TenantFromContext(ctx))
+	if err := s.eventPublisher.Publish(ctx, recordedEvent); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "publish feedback event failed", map[string]any{
+			FieldUserID: userID,
+			FieldError:  err.Error(),
+		})
+	}
+	return nil
+}
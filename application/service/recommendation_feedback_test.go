@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/event"
+	"service/domain/valueobject"
+)
+
+// fakeFeedbackRepo 测试用反馈记录：同一条推荐的同一种反馈只记录一次
+type fakeFeedbackRepo struct {
+	recorded map[string]bool
+	listed   []*entity.RecommendationFeedback
+}
+
+func (r *fakeFeedbackRepo) RecordFeedback(ctx context.Context, feedback *entity.RecommendationFeedback) (bool, error) {
+	key := feedback.RecommendationID().String() + "/" + feedback.Action().String()
+	if r.recorded[key] {
+		return false, nil
+	}
+	r.recorded[key] = true
+	return true, nil
+}
+
+func (r *fakeFeedbackRepo) DeleteUserFeedback(ctx context.Context, userID valueobject.UserID) error {
+	return nil
+}
+
+func (r *fakeFeedbackRepo) ListUserFeedback(ctx context.Context, viewerID valueobject.UserID) ([]*entity.RecommendationFeedback, error) {
+	return r.listed, nil
+}
+
+func (r *fakeFeedbackRepo) DeleteFeedbackBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeFeedbackRepo) OldestFeedbackTime(ctx context.Context) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+// fakePublisher 测试用事件发布：记录发布过的事件
+type fakePublisher struct {
+	published []event.Event
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, e event.Event) error {
+	p.published = append(p.published, e)
+	return nil
+}
+
+func TestRecordRecommendationFeedback(t *testing.T) {
+	viewer, _ := valueobject.NewUserID(1)
+	target, _ := valueobject.NewUserID(2)
+	shown := entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonFollowedByFollowing, "home_feed", time.Now())
+	publisher := &fakePublisher{}
+	s := newTestRecommendationService(&batchContentClient{})
+	WithImpressionRepository(&fakeImpressionRepo{listed: []*entity.Impression{shown}})(s)
+	WithFeedback(&fakeFeedbackRepo{recorded: make(map[string]bool)}, publisher)(s)
+	ctx := context.Background()
+	id := shown.RecommendationID().String()
+
+	// 重复上报只记录一次、只发布一次事件
+	for i := 0; i < 2; i++ {
+		if err := s.RecordRecommendationFeedback(ctx, 1, id, "clicked"); err != nil {
+			t.Fatalf("RecordRecommendationFeedback() error = %v", err)
+		}
+	}
+	if err := s.RecordRecommendationFeedback(ctx, 1, id, "followed"); err != nil {
+		t.Fatalf("RecordRecommendationFeedback() error = %v", err)
+	}
+	if len(publisher.published) != 2 {
+		t.Fatalf("published %d events, want 2", len(publisher.published))
+	}
+	recorded, ok := publisher.published[0].(event.RecommendationFeedbackRecorded)
+	if !ok || recorded.TargetUserID != 2 || recorded.Action != "clicked" {
+		t.Errorf("first event = %+v, want clicked on user 2", publisher.published[0])
+	}
+
+	// 没有展示给这个用户的推荐、未知的反馈类型、格式不对的推荐ID
+	if err := s.RecordRecommendationFeedback(ctx, 3, id, "clicked"); !errors.Is(err, ErrRecommendationNotShown) {
+		t.Errorf("other viewer: err = %v, want ErrRecommendationNotShown", err)
+	}
+	if err := s.RecordRecommendationFeedback(ctx, 1, id, "liked"); !errors.Is(err, valueobject.ErrUnknownFeedbackAction) {
+		t.Errorf("unknown action: err = %v, want ErrUnknownFeedbackAction", err)
+	}
+	if err := s.RecordRecommendationFeedback(ctx, 1, "not-an-id", "clicked"); !errors.Is(err, ErrInvalidRecommendationID) {
+		t.Errorf("bad id: err = %v, want ErrInvalidRecommendationID", err)
+	}
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/service"
+	"service/domain/valueobject"
+)
+
+// ErrNoPrecomputedRecommendations 请求只接受快照（FreshnessCachedOnly），但没有可用的快照
+var ErrNoPrecomputedRecommendations = errors.New("no precomputed recommendations")
+
+// WithPrecomputedServing 开启快照直出：快照没有超过 maxStaleness 时直接使用，不重新生成
+//
+// 为什么可以直接使用快照？
+// 推荐列表的输入（关注关系、最近关注）变化得很慢，几分钟前生成的列表和现在生成的几乎一样，
+// 而召回、排序是整个请求中最贵的部分。
+// 高优先级用户（FreshnessRealtime）不受影响，总是重新生成。
+//
+// 依赖快照存储（WithSnapshotStore），没有快照存储时不生效。
+func WithPrecomputedServing(maxStaleness time.Duration) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.maxStaleness = maxStaleness
+	}
+}
+
+// WithRecommendationRepository 保存生成的推荐列表，maxAge 内的请求直接使用，不重新生成
+//
+// 和快照直出（WithPrecomputedServing）的区别：
+// 快照是下发过的那一页，只能原样返回；持久化的是生成器的完整结果，
+// 之后的场景策略、过滤条件、曝光配额、会话去重都照常执行，
+// 所以翻页、换场景、带过滤条件的请求都可以使用，省掉的只是召回和打分。
+//
+// 只有默认的生成器、默认时间窗口生成的列表会被保存和使用（列表按用户保存，不区分生成方式）。
+// maxAge 为 0 时只保存、不使用（先写入一段时间，观察存储的压力）。
+func WithRecommendationRepository(repo repository.RecommendationRepository, maxAge time.Duration) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.listRepo = repo
+		s.listMaxAge = maxAge
+	}
+}
+
+// rankPage 阶段一：确定本次要返回的一页推荐
+//
+// 新鲜度要求允许时使用最近的快照（precomputed = true），否则召回并排序候选。
+// 只接受快照而没有可用的快照时，返回 ErrNoPrecomputedRecommendations。
+//
+// 重新生成时，用户参与了展示场景的交错实验则返回交错列表，
+// assignment 记录每条推荐来自哪个排序器（写入曝光记录）；快照不参与实验。
+//
+// returned 是会话内已经返回过的推荐对象（可以为 nil），不会出现在这一页中；
+// criteria 是调用方的过滤条件（不为空时总是重新生成）。
+//
+// reserve 是排在这一页后面的几个候选（资料缺失时补位用，见 WithProfileMissPolicy）；快照没有多余的候选。
+// 重新生成的一页不够 limit 个时用热门、精选用户补齐（见 WithBackfill）。
+//
+// 召回排序的超时、失败后是否使用过期的快照由降级策略（ranking）决定，见 stalePage。
+func (s *RecommendationService) rankPage(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	key SnapshotKey,
+	req *dto.RecommendationRequest,
+	returned map[valueobject.UserID]bool,
+	criteria aggregate.RecommendationCriteria,
+	now time.Time,
+) (page, reserve []*aggregate.UserRecommendation, assignment *interleavingAssignment, sampling aggregate.FollowingSampling, precomputed bool, err error) {
+	if page, ok := s.precomputedPage(ctx, key, req, returned, now); ok {
+		s.metrics.IncCounter(MetricPrecomputedResponses, 1, map[string]string{
+			"surface":   string(req.Surface),
+			"freshness": string(req.Freshness),
+		})
+		// 快照不记录采样情况
+		return page, nil, nil, aggregate.FollowingSampling{}, true, nil
+	}
+	if req.Freshness == dto.FreshnessCachedOnly {
+		return nil, nil, nil, aggregate.FollowingSampling{}, false, ErrNoPrecomputedRecommendations
+	}
+
+	ranked, sampling, err := s.rankWithPolicy(ctx, viewerID, req, criteria)
+	if err != nil {
+		if page, ok := s.stalePage(ctx, key, req, returned, err, now); ok {
+			return page, nil, nil, aggregate.FollowingSampling{}, true, nil
+		}
+		return nil, nil, nil, aggregate.FollowingSampling{}, false, err
+	}
+	ranked = excludeReturned(ranked, returned)
+	ranked, assignment = s.interleave(viewerID, s.surfaces.For(req.Surface).Interleaving, ranked, req.Limit, now)
+	reserve = s.profileReserve(ranked, req.Limit)
+	if req.Limit >= 0 && len(ranked) > req.Limit {
+		ranked = ranked[:req.Limit]
+	}
+	ranked = s.backfillPage(ctx, viewerID, ranked, req.Limit, returned, criteria)
+	return ranked, reserve, assignment, sampling, false, nil
+}
+
+// rankWithPolicy 辅助方法：在降级策略的超时内召回并排序候选
+func (s *RecommendationService) rankWithPolicy(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	req *dto.RecommendationRequest,
+	criteria aggregate.RecommendationCriteria,
+) ([]*aggregate.UserRecommendation, aggregate.FollowingSampling, error) {
+	if timeout := s.degradation.For(FallbackRanking).Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.rankCandidates(ctx, viewerID, req.Surface, req.Days, req.Freshness, criteria)
+}
+
+// stalePage 辅助方法：召回排序失败时，按降级策略使用最近的快照（不管是否过期）
+//
+// 和 FreshnessCachedOnly 一样使用快照：指定了时间窗口、过滤条件的请求没有对应的快照，不降级。
+// 响应和快照直出一样标记为 precomputed；无论是否降级都记录一次 ranking 的降级。
+func (s *RecommendationService) stalePage(
+	ctx context.Context,
+	key SnapshotKey,
+	req *dto.RecommendationRequest,
+	returned map[valueobject.UserID]bool,
+	cause error,
+	now time.Time,
+) ([]*aggregate.UserRecommendation, bool) {
+	if !s.degradation.For(FallbackRanking).ServeStale {
+		s.degradation.Fallback(ctx, FallbackRanking, SourceGenerate, SourceNone, cause)
+		return nil, false
+	}
+	staleReq := *req
+	staleReq.Freshness = dto.FreshnessCachedOnly
+	page, ok := s.precomputedPage(ctx, key, &staleReq, returned, now)
+	if !ok {
+		s.degradation.Fallback(ctx, FallbackRanking, SourceGenerate, SourceNone, cause)
+		return nil, false
+	}
+	s.degradation.Fallback(ctx, FallbackRanking, SourceGenerate, SourceStale, cause)
+	return page, true
+}
+
+// precomputedPage 辅助方法：按新鲜度要求从最近的快照中恢复这一页
+//
+// 可以使用快照的条件：
+// - FreshnessRealtime：从不使用
+// - FreshnessDefault：开启了快照直出，快照没有超过 maxStaleness，并且快照不少于一页
+// - FreshnessCachedOnly：有快照就使用（过载时旧一点的列表比没有列表好）
+// - 请求指定了和场景默认值不同的时间窗口、或者指定了过滤条件时从不使用
+//
+// 快照中已经过期的、会话内已经返回过的、有屏蔽关系的推荐跳过（快照可能早于屏蔽）。
+// 查询失败只记日志，按没有快照处理（屏蔽关系查询失败时重新生成，由生成器决定是否失败）。
+func (s *RecommendationService) precomputedPage(
+	ctx context.Context,
+	key SnapshotKey,
+	req *dto.RecommendationRequest,
+	returned map[valueobject.UserID]bool,
+	now time.Time,
+) ([]*aggregate.UserRecommendation, bool) {
+	// 快照是按场景默认的时间窗口、没有过滤条件生成的：指定了其他时间窗口、过滤条件时重新生成
+	if s.snapshotStore == nil || req.Freshness == dto.FreshnessRealtime || req.Days != 0 || !req.Filter.IsEmpty() {
+		return nil, false
+	}
+	cachedOnly := req.Freshness == dto.FreshnessCachedOnly
+	if !cachedOnly && (s.maxStaleness <= 0 || req.Limit < 0) {
+		return nil, false
+	}
+
+	snapshot, err := s.snapshotStore.FindLatestBefore(ctx, key, now)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "find recommendation snapshot failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+		return nil, false
+	}
+	if snapshot == nil {
+		return nil, false
+	}
+	// 快照可能是更小的一页（上次请求的 limit 更小）：不能凑成这一页时重新生成
+	if !cachedOnly && (now.Sub(snapshot.CreatedAt) > s.maxStaleness || len(snapshot.Entries) < req.Limit) {
+		return nil, false
+	}
+
+	blocked, err := s.blockedUsers(ctx, key.UserID)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "load blocked users failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+		return nil, false
+	}
+
+	page := make([]*aggregate.UserRecommendation, 0, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		if req.Limit >= 0 && len(page) == req.Limit {
+			break
+		}
+		if !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+			continue
+		}
+		targetUserID, err := valueobject.NewUserID(entry.UserID)
+		if err != nil || returned[targetUserID] || blocked[targetUserID] {
+			continue
+		}
+		reason := valueobject.RestoreRecommendationReason(valueobject.ReasonType(entry.ReasonType), entry.RelatedCount)
+		page = append(page, aggregate.RestoreUserRecommendation(targetUserID, reason, entry.Score, 0, snapshot.CreatedAt, entry.ExpiresAt))
+	}
+	return page, true
+}
+
+// unchangedSnapshotVersion 辅助方法：条件请求在召回排序之前判断列表是否变化
+//
+// 客户端带来的版本（known_version / If-None-Match，或增量同步的 since_version）
+// 就是最近一份快照的版本，并且这份快照现在可以原样直接使用（见 precomputedPage）时，
+// 这次请求返回的还是这份列表：直接返回这个版本，调用方回复 not_modified。
+//
+// 只比较快照中保存的版本，不恢复列表、不重新计算版本；只有版本相同时才查询屏蔽关系
+// （快照之后新增的屏蔽会让列表变化）。
+// 快照不能直接使用时（高优先级请求、没有开启快照直出、快照过期、会话去重等）返回 false，
+// 由调用方召回排序之后再比较版本。
+func (s *RecommendationService) unchangedSnapshotVersion(
+	ctx context.Context,
+	key SnapshotKey,
+	req *dto.RecommendationRequest,
+	returned map[valueobject.UserID]bool,
+	calibrationVersion string,
+	now time.Time,
+) (string, bool) {
+	if req.KnownVersion == "" && req.SinceVersion == "" {
+		return "", false
+	}
+	if s.snapshotStore == nil || req.Freshness == dto.FreshnessRealtime || req.Days != 0 || !req.Filter.IsEmpty() || returned != nil {
+		return "", false
+	}
+	if req.Freshness != dto.FreshnessCachedOnly && (s.maxStaleness <= 0 || req.Limit < 0) {
+		return "", false
+	}
+
+	snapshot, err := s.snapshotStore.FindLatestBefore(ctx, key, now)
+	if err != nil || snapshot == nil {
+		return "", false // 查询失败由 rankPage 记录日志
+	}
+	if snapshot.Version != req.KnownVersion && snapshot.Version != req.SinceVersion {
+		return "", false
+	}
+	// 快照恢复出的一页必须和快照完全相同：数量一致、校准参数一致、没有过期的推荐
+	if snapshot.CalibrationVersion != calibrationVersion || len(snapshot.Entries) != req.Limit {
+		return "", false
+	}
+	if req.Freshness != dto.FreshnessCachedOnly && now.Sub(snapshot.CreatedAt) > s.maxStaleness {
+		return "", false
+	}
+	for _, entry := range snapshot.Entries {
+		if !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+			return "", false
+		}
+	}
+
+	blocked, err := s.blockedUsers(ctx, key.UserID)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range snapshot.Entries {
+		if userID, err := valueobject.NewUserID(entry.UserID); err != nil || blocked[userID] {
+			return "", false
+		}
+	}
+	return snapshot.Version, true
+}
+
+// blockedUsers 辅助方法：和用户有屏蔽关系的人（生成器没有配置屏蔽关系仓储时为空）
+func (s *RecommendationService) blockedUsers(ctx context.Context, userID int64) (map[valueobject.UserID]bool, error) {
+	viewerID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.generator.BlockedUsers(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[valueobject.UserID]bool, len(users))
+	for _, user := range users {
+		blocked[user] = true
+	}
+	return blocked, nil
+}
+
+// generateList 辅助方法：调用生成器生成推荐列表，或者使用缓存、持久化的推荐列表
+//
+// 依次尝试（FreshnessRealtime 跳过前两步，总是重新生成）：
+//  1. 推荐列表缓存（WithRecommendationListCache），按租户 + 用户 + 策略 + 时间窗口
+//  2. 持久化的推荐列表（WithRecommendationRepository）：只有默认的生成器、默认时间窗口，没有超过 listMaxAge
+//  3. 调用生成器；默认的生成器、默认时间窗口生成的列表保存到仓储
+//
+// 后两步得到的列表都写入缓存；第 3 步上报生成的推荐数（MetricGeneratedCandidates）。
+// 使用缓存、持久化的列表时去掉已经过期的推荐，并重新登记屏蔽关系（列表可能早于屏蔽）。
+// 查询失败只记日志，按没有列表处理；保存、缓存失败同样只记日志。
+func (s *RecommendationService) generateList(
+	ctx context.Context,
+	generator *service.RecommendationGenerator,
+	strategy string,
+	viewerID valueobject.UserID,
+	days int,
+	freshness dto.Freshness,
+) (*aggregate.RecommendationList, error) {
+	if freshness != dto.FreshnessRealtime {
+		if list, ok := s.reusedList(ctx, generator, strategy, viewerID, days); ok {
+			return list, nil
+		}
+	}
+
+	list, err := generator.GenerateFollowingBasedRecommendations(ctx, viewerID, days)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.ObserveHistogram(MetricGeneratedCandidates, float64(list.Count()), map[string]string{"strategy": strategy})
+	if s.persistable(generator, days) {
+		if err := s.listRepo.SaveList(ctx, list); err != nil {
+			s.logger.Log(ctx, LogLevelWarn, "save recommendation list failed", map[string]any{
+				FieldUserID: viewerID.Value(),
+				FieldError:  err.Error(),
+			})
+		}
+	}
+	s.cacheList(ctx, listCacheKey(ctx, strategy, viewerID, days), list)
+	return list, nil
+}
+
+// reusedList 辅助方法：generateList 的前两步，缓存、持久化的推荐列表（都没有时返回 false，不生成）
+//
+// 持久化的列表也写入缓存。
+func (s *RecommendationService) reusedList(
+	ctx context.Context,
+	generator *service.RecommendationGenerator,
+	strategy string,
+	viewerID valueobject.UserID,
+	days int,
+) (*aggregate.RecommendationList, bool) {
+	key := listCacheKey(ctx, strategy, viewerID, days)
+	if list, ok := s.cachedList(ctx, key); ok && s.refreshReusedList(ctx, list) {
+		return list, true
+	}
+	if s.persistable(generator, days) && s.listMaxAge > 0 {
+		if list, ok := s.persistedList(ctx, viewerID); ok {
+			s.cacheList(ctx, key, list)
+			return list, true
+		}
+	}
+	return nil, false
+}
+
+// persistable 辅助方法：生成器的结果是否保存到推荐列表仓储（只有默认的生成器、默认时间窗口）
+func (s *RecommendationService) persistable(generator *service.RecommendationGenerator, days int) bool {
+	return s.listRepo != nil && generator == s.generator && days == defaultRecencyDays
+}
+
+// listCacheKey 辅助函数：推荐列表缓存的 key（按请求的租户隔离）
+func listCacheKey(ctx context.Context, strategy string, viewerID valueobject.UserID, days int) ListCacheKey {
+	return ListCacheKey{
+		Tenant:   valueobject.TenantFromContext(ctx),
+		UserID:   viewerID.Value(),
+		Strategy: strategy,
+		Days:     days,
+	}
+}
+
+// refreshReusedList 辅助方法：重新使用一份之前生成的列表之前，去掉过期的推荐、重新登记屏蔽关系
+//
+// 屏蔽关系查询失败时返回 false（重新生成，由生成器决定是否失败）。
+func (s *RecommendationService) refreshReusedList(ctx context.Context, list *aggregate.RecommendationList) bool {
+	blocked, err := s.generator.BlockedUsers(ctx, list.ForUserID())
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "load blocked users failed", map[string]any{
+			FieldUserID: list.ForUserID().Value(),
+			FieldError:  err.Error(),
+		})
+		return false
+	}
+	list.RemoveExpired()
+	list.ExcludeBlocked(blocked)
+	return true
+}
+
+// persistedList 辅助方法：最新的一份没有超过 listMaxAge 的推荐列表
+func (s *RecommendationService) persistedList(ctx context.Context, viewerID valueobject.UserID) (*aggregate.RecommendationList, bool) {
+	list, err := s.listRepo.GetLatestListForUser(ctx, viewerID)
+	switch {
+	case err == nil && list != nil && time.Since(list.GeneratedAt()) <= s.listMaxAge:
+		if !s.refreshReusedList(ctx, list) {
+			s.reportPersistedList("error")
+			return nil, false
+		}
+		s.reportPersistedList("reused")
+		return list, true
+	case err != nil:
+		s.logger.Log(ctx, LogLevelWarn, "load recommendation list failed", map[string]any{
+			FieldUserID: viewerID.Value(),
+			FieldError:  err.Error(),
+		})
+		s.reportPersistedList("error")
+	case list == nil:
+		s.reportPersistedList("missing")
+	default:
+		s.reportPersistedList("stale")
+	}
+	return nil, false
+}
+
+// reportPersistedList 辅助方法：上报一次持久化推荐列表的查找结果
+func (s *RecommendationService) reportPersistedList(result string) {
+	s.metrics.IncCounter(MetricPersistedLists, 1, map[string]string{"result": result})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ErrInvalidPageToken 分页参数不是上一页响应返回的 next_page_token
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// GetRecommendationHistory 用例：查询一段时间内展示给用户的推荐（按展示时间倒序，分页）
+//
+// 用途：
+// - 客户端"之前推荐过的人"
+// - 客服排查"推荐服务给这个用户推荐过什么、什么时候、在哪个场景"
+//
+// 数据来源：曝光记录（ImpressionRepository）。
+// 列表快照只是缓存、会过期，曝光记录才是"给谁展示过什么"的持久化记录，
+// 保留期和用户数据删除都已经覆盖（见 RetentionEnforcer、UserDataService）。
+// 同一个人被推荐过多次时每次展示都是一条（客户端按需去重）。
+//
+// 被推荐用户的资料批量获取；获取失败时只返回用户ID，不让整个查询失败。
+// 没有注入曝光记录仓储时返回空列表。
+func (s *RecommendationService) GetRecommendationHistory(
+	ctx context.Context,
+	req *dto.RecommendationHistoryRequest,
+) (*dto.RecommendationHistoryResponse, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.GetRecommendationHistory")
+	defer span.End()
+	span.SetAttribute(FieldUserID, req.UserID)
+
+	viewerID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.RecommendationHistoryResponse{Items: []*dto.RecommendationHistoryItemDTO{}}
+	if s.impressionRepo == nil {
+		return resp, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	limit = min(limit, maxHistoryLimit)
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	// 多取一条判断是否还有下一页
+	impressions, err := s.impressionRepo.ListImpressionHistory(ctx, viewerID, req.From, to, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(impressions) > limit {
+		impressions = impressions[:limit]
+		resp.NextPageToken = encodePageToken(repository.CursorOf(impressions[limit-1]))
+	}
+	if len(impressions) == 0 {
+		return resp, nil
+	}
+
+	targetIDs := make([]int64, 0, len(impressions))
+	for _, impression := range impressions {
+		targetIDs = append(targetIDs, impression.TargetUserID().Value())
+	}
+	userInfos, err := s.getUserInfoMap(ctx, targetIDs)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "get user info for recommendation history failed", map[string]any{
+			FieldUserID: req.UserID,
+			FieldError:  err.Error(),
+		})
+	}
+
+	for _, impression := range impressions {
+		item := &dto.RecommendationHistoryItemDTO{
+			RecommendationID: impression.RecommendationID().Value(),
+			UserID:           impression.TargetUserID().Value(),
+			Surface:          impression.Surface(),
+			ShownAt:          impression.ShownAt().Format(time.RFC3339),
+		}
+		if info, ok := userInfos[item.UserID]; ok {
+			item.Username, item.Avatar = info.Username, info.Avatar
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+// encodePageToken 辅助函数：分页位置 -> 不透明的 page token
+//
+// 调用方只原样带回，不应该解析它（编码方式可以随时调整）。
+func encodePageToken(cursor *repository.ImpressionCursor) string {
+	raw := strconv.FormatInt(cursor.ShownAt.UnixNano(), 10) + ":" + cursor.RecommendationID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePageToken 辅助函数：page token -> 分页位置（为空时返回 nil）
+func decodePageToken(token string) (*repository.ImpressionCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	nanos, recommendationID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &repository.ImpressionCursor{ShownAt: time.Unix(0, n), RecommendationID: recommendationID}, nil
+}
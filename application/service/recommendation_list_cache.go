@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ListCacheKey 缓存的推荐列表属于谁、由哪个推荐策略生成
+//
+// 同一个用户在不同展示场景可能使用不同的生成器（场景单独配置了候选来源、过滤器），
+// 不同的时间窗口生成的列表也不同，都是 key 的一部分。
+type ListCacheKey struct {
+	Tenant   valueobject.TenantID
+	UserID   int64
+	Strategy string // 推荐策略：默认生成器为 DefaultListStrategy，场景单独配置的生成器为场景名
+	Days     int    // 推荐信号的时间窗口
+}
+
+// DefaultListStrategy 默认生成器的推荐策略名
+const DefaultListStrategy = "default"
+
+// RecommendationListCache 推荐列表缓存（生成器的结果）
+//
+// 和推荐列表仓储（WithRecommendationRepository）的区别：
+// 仓储只保存默认生成器的结果，存在数据库里，用于保留和分析；
+// 缓存按策略保存所有生成器的结果，有 TTL，丢失只是多一次生成。
+//
+// 缓存的列表可能早于关注关系、屏蔽关系的变化：
+// 屏蔽关系在使用时重新登记；关注关系变化时由调用方删除（见 InvalidateRecommendations），否则随 TTL 过期。
+type RecommendationListCache interface {
+	// Get 读取缓存的推荐列表（没有或者已经过期时返回 nil, nil），每次返回一份独立的列表
+	Get(ctx context.Context, key ListCacheKey) (*aggregate.RecommendationList, error)
+	// Set 缓存一份推荐列表
+	Set(ctx context.Context, key ListCacheKey, list *aggregate.RecommendationList) error
+	// InvalidateUser 删除用户所有策略的推荐列表（幂等，同时实现 UserCacheInvalidator）
+	InvalidateUser(ctx context.Context, userID int64) error
+}
+
+// WithRecommendationListCache 生成推荐之前先查缓存，命中时不重新生成（高优先级用户除外）
+func WithRecommendationListCache(cache RecommendationListCache) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.listCache = cache
+	}
+}
+
+// InvalidateRecommendations 用例：删除用户缓存的推荐列表，下一次请求重新生成
+//
+// 推荐信号变化、希望用户马上看到新的推荐时调用（如用户刚关注了一批人）。
+// 没有开启缓存时什么都不做。
+// 持久化的推荐列表（WithRecommendationRepository）不删除，在 listMaxAge 内仍然会被使用：
+// 所以 listMaxAge 应该比缓存的 TTL 短得多。
+func (s *RecommendationService) InvalidateRecommendations(ctx context.Context, userID int64) error {
+	if s.listCache == nil {
+		return nil
+	}
+	if _, err := valueobject.NewUserID(userID); err != nil {
+		return err
+	}
+	return s.listCache.InvalidateUser(ctx, userID)
+}
+
+// cachedList 辅助方法：读取缓存的推荐列表（查询失败只记日志，按未命中处理）
+func (s *RecommendationService) cachedList(ctx context.Context, key ListCacheKey) (*aggregate.RecommendationList, bool) {
+	if s.listCache == nil {
+		return nil, false
+	}
+	list, err := s.listCache.Get(ctx, key)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "get cached recommendation list failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+		s.reportListCache(key.Strategy, "error")
+		return nil, false
+	}
+	if list == nil {
+		s.reportListCache(key.Strategy, "miss")
+		return nil, false
+	}
+	s.reportListCache(key.Strategy, "hit")
+	return list, true
+}
+
+// cacheList 辅助方法：缓存推荐列表（写入失败只记日志）
+func (s *RecommendationService) cacheList(ctx context.Context, key ListCacheKey, list *aggregate.RecommendationList) {
+	if s.listCache == nil {
+		return
+	}
+	if err := s.listCache.Set(ctx, key, list); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "cache recommendation list failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+	}
+}
+
+// reportListCache 辅助方法：上报一次推荐列表缓存的查询结果
+func (s *RecommendationService) reportListCache(strategy, result string) {
+	s.metrics.IncCounter(MetricListCacheRequests, 1, map[string]string{"strategy": strategy, "result": result})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ErrPageTokenExpired 分页位置对应的推荐列表已经不在了（重新生成了、缓存过期），客户端从第一页重新开始
+//
+// 属于 ErrInvalidPageToken（errors.Is 成立），接口层按参数错误处理。
+var ErrPageTokenExpired = fmt.Errorf("%w: recommendation list has changed", ErrInvalidPageToken)
+
+// defaultPageSize 分页请求既没有 PageSize 也没有 Limit 时的每页数量
+const defaultPageSize = 10
+
+// recommendationPage 用例 GetFollowingBasedRecommendations 的分页模式：按页返回同一份推荐列表
+//
+// 为什么要固定在同一份列表上？
+// 每次重新生成，列表都可能变化（新的关注、新的帖子），
+// 按偏移量翻页会重复或漏掉推荐。第一页使用的列表写入推荐列表缓存（或推荐列表仓储），
+// 后面的页从缓存中取回同一份列表（按生成时间核对），取不回时返回 ErrPageTokenExpired。
+// 所以分页依赖推荐列表缓存（WithRecommendationListCache）；
+// 只有推荐列表仓储时，持久化的列表超过 listMaxAge 后翻页同样返回 ErrPageTokenExpired。
+//
+// 分页位置（listCursor）不是偏移量，而是上一页最后一条推荐的排序键：
+// 列表取回时会去掉过期的、新屏蔽的推荐，用偏移量的话后面的推荐会整体前移，被跳过。
+//
+// 和不分页的请求相比：
+//   - 按分数排序（分数相同时按用户ID），不执行策略权重、降权最近展示过的、交错实验：
+//     这些步骤依赖曝光记录，每次请求的顺序都可能不同，翻页会重复或漏掉推荐
+//   - 曝光配额照常执行（只去掉推荐，不改变顺序）
+//   - 不使用快照、会话去重，不做增量同步，响应不带 Version
+//   - 没有补位：资料缺失、超时没有补全的推荐直接跳过，不会在后面的页中出现
+func (s *RecommendationService) recommendationPage(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	req *dto.RecommendationRequest,
+	criteria aggregate.RecommendationCriteria,
+) (*dto.RecommendationResponse, error) {
+	after, err := decodeListCursor(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	policy := s.surfaces.For(req.Surface)
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = req.Limit
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if policy.MaxLimit > 0 {
+		pageSize = min(pageSize, policy.MaxLimit)
+	}
+
+	list, err := s.pageList(ctx, viewerID, req, after)
+	if err != nil {
+		return nil, err
+	}
+	list.ApplyCriteria(criteria)
+	list.ApplyDiversity(policy.MaxPerReasonType)
+	list.ApplyReferrerDiversity(policy.MaxPerReferrer)
+	s.reportExclusions(list)
+
+	ranked := afterCursor(pagingOrder(list.All()), after)
+	ranked = s.applyExposureQuota(ctx, viewerID, ranked)
+	hasMore := len(ranked) > pageSize
+	page := ranked[:min(pageSize, len(ranked))]
+	if policy.Expiry > 0 {
+		for _, rec := range page {
+			rec.ExpireIn(policy.Expiry)
+		}
+	}
+
+	calibrations := s.currentCalibrations()
+	metadata := convertSamplingToMetadata(list.FollowingSampling())
+	metadata.CalibrationVersion = calibrations.version()
+	resp := &dto.RecommendationResponse{
+		Recommendations: []*dto.UserRecommendationDTO{},
+		Metadata:        metadata,
+		HasMore:         hasMore,
+	}
+	if hasMore {
+		resp.NextCursor = encodeListCursor(list, page[len(page)-1])
+	}
+	if len(page) == 0 {
+		return resp, nil
+	}
+
+	recommendations, shown, degraded, err := s.hydrate(ctx, page, nil, req.Surface)
+	if err != nil {
+		return nil, err
+	}
+	calibrations.applyCalibration(recommendations, shown)
+	if req.Debug && after == nil && req.Freshness == dto.FreshnessRealtime {
+		applyScoreDetails(recommendations, shown) // 后面的页使用已有的列表，可能是从持久化恢复的
+	}
+	s.recordImpressions(ctx, viewerID, req.Surface, shown, nil)
+	resp.Recommendations = recommendations
+	resp.Degraded = degraded
+	return resp, nil
+}
+
+// pageList 辅助方法：分页使用的推荐列表
+//
+// 第一页和不分页的请求一样生成（或者使用缓存、持久化的列表），只接受快照的请求只使用已有的列表；
+// 后面的页只使用已有的列表，并且必须是分页位置记录的那一份。
+func (s *RecommendationService) pageList(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	req *dto.RecommendationRequest,
+	after *listCursor,
+) (*aggregate.RecommendationList, error) {
+	generator, strategy, days := s.listSource(req.Surface, req.Days)
+	if after != nil {
+		list, ok := s.reusedList(ctx, generator, strategy, viewerID, days)
+		if !ok || list.GeneratedAt().UnixMilli() != after.GeneratedAt {
+			return nil, ErrPageTokenExpired
+		}
+		return list, nil
+	}
+	if req.Freshness == dto.FreshnessCachedOnly {
+		list, ok := s.reusedList(ctx, generator, strategy, viewerID, days)
+		if !ok {
+			return nil, ErrNoPrecomputedRecommendations
+		}
+		return list, nil
+	}
+
+	if timeout := s.degradation.For(FallbackRanking).Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.generateList(ctx, generator, strategy, viewerID, days, req.Freshness)
+}
+
+// pagingOrder 辅助函数：分页使用的顺序（分数降序，分数相同时按用户ID升序）
+//
+// 和 GetTopN 不同，顺序只取决于推荐本身，不取决于加入列表的顺序：分页位置可以用排序键表示。
+func pagingOrder(recommendations []*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	sorted := make([]*aggregate.UserRecommendation, len(recommendations))
+	copy(sorted, recommendations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return listCursorOf(sorted[i]).precedes(sorted[j])
+	})
+	return sorted
+}
+
+// afterCursor 辅助函数：排在分页位置之后的推荐（after 为 nil 时返回全部）
+func afterCursor(sorted []*aggregate.UserRecommendation, after *listCursor) []*aggregate.UserRecommendation {
+	if after == nil {
+		return sorted
+	}
+	i := sort.Search(len(sorted), func(i int) bool { return after.precedes(sorted[i]) })
+	return sorted[i:]
+}
+
+// listCursor 推荐列表的分页位置：哪一份列表 + 上一页最后一条推荐的排序键
+type listCursor struct {
+	GeneratedAt int64 // 列表的生成时间（Unix 毫秒：MySQL 只保存到毫秒）
+	Score       int
+	UserID      int64
+}
+
+// listCursorOf 一条推荐的排序键（不含列表的生成时间）
+func listCursorOf(rec *aggregate.UserRecommendation) *listCursor {
+	return &listCursor{Score: rec.Score(), UserID: rec.TargetUserID().Value()}
+}
+
+// precedes 分页位置是否排在推荐之前
+func (c *listCursor) precedes(rec *aggregate.UserRecommendation) bool {
+	if c.Score != rec.Score() {
+		return c.Score > rec.Score()
+	}
+	return c.UserID < rec.TargetUserID().Value()
+}
+
+// encodeListCursor 辅助函数：分页位置 -> 不透明的 cursor（和 encodePageToken 一样，调用方只原样带回）
+func encodeListCursor(list *aggregate.RecommendationList, last *aggregate.UserRecommendation) string {
+	raw := strconv.FormatInt(list.GeneratedAt().UnixMilli(), 10) + ":" +
+		strconv.Itoa(last.Score()) + ":" +
+		strconv.FormatInt(last.TargetUserID().Value(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeListCursor 辅助函数：cursor -> 分页位置（为空时返回 nil）
+func decodeListCursor(token string) (*listCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	parts := strings.Split(string(data), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidPageToken
+	}
+	generatedAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	score, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	userID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &listCursor{GeneratedAt: generatedAt, Score: score, UserID: userID}, nil
+}
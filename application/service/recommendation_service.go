@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
 
 	"service/application/dto"
 	"service/domain/repository"
 	"service/domain/service"
 
+	"service/domain/aggregate"
 	"service/domain/entity"
+	"service/domain/event"
 	"service/domain/valueobject"
 )
 
@@ -63,10 +69,130 @@ import (
 type RecommendationService struct {
 	generator          *service.RecommendationGenerator
 	socialGraphRepo    repository.SocialGraphRepository
-	contentRepo        repository.ContentRepository // 本地数据库查询（可选）
-	contentClient      ContentServiceClient         // 远程服务调用（可选）
-	userRPCClient      UserRPCClient                // 调用 user 服务获取用户信息
-	reasonConfigClient ReasonTextConfigClient       // 调用配置服务获取推荐理由文案（可选）
+	userRPCClient      UserRPCClient          // 调用 user 服务获取用户信息
+	reasonConfigClient ReasonTextConfigClient // 调用配置服务获取推荐理由文案（可选）
+
+	// 可选依赖（通过 RecommendationServiceOption 注入）
+	metrics        Metrics                             // 监控指标（默认空实现）
+	logger         Logger                              // 日志（默认空实现）
+	tracer         Tracer                              // 链路追踪（默认空实现）
+	contentSafety  *ContentSafetyPolicy                // 帖子预览内容安全策略（默认 DefaultContentSafetyPolicy）
+	impressionRepo repository.ImpressionRepository     // 曝光记录（为 nil 时不记录曝光）
+	exposureQuota  *ExposureQuota                      // 每日曝光配额（为 nil 时不限制）
+	erasureRepo    repository.UserErasureRepository    // 用户数据删除记录（为 nil 时不检查）
+	tenants        *Tenants                            // 租户配置（为 nil 时不区分租户的文案语言）
+	snapshotStore  RecommendationSnapshotStore         // 列表快照（为 nil 时不支持增量同步）
+	maxStaleness   time.Duration                       // 快照直出的过期阈值（为 0 时只在过载时使用快照）
+	surfaces       *SurfacePolicies                    // 各展示场景的推荐行为（为 nil 时所有场景相同）
+	sectionLayouts SectionLayoutClient                 // 发现页的分栏配置（为 nil 时使用 DefaultSectionLayout）
+	featureLogger  *FeatureLogger                      // 特征快照日志（为 nil 时不记录）
+	calibration    ScoreCalibrationSource              // 分数校准参数（为 nil 时不校准）
+	bandit         *StrategyBandit                     // 策略权重 bandit（为 nil 时不调整）
+	sessions       SessionStore                        // 会话内去重（为 nil 时不去重）
+	profileMiss    ProfileMissPolicy                   // 推荐对象的资料缺失时的处理方式（默认跳过）
+	degradation    *DegradationPolicy                  // 依赖失败时的降级策略（为 nil 时使用默认策略，不上报）
+	listRepo       repository.RecommendationRepository // 生成过的推荐列表（为 nil 时每次都重新生成）
+	listMaxAge     time.Duration                       // 持久化的推荐列表在这个时间内直接使用
+	listCache      RecommendationListCache             // 推荐列表缓存（为 nil 时不缓存）
+	contentSources *ContentSourceChain                 // 帖子预览的来源链（为 nil 时由 contentClient、contentRepo 组成）
+	hybridWeights  HybridWeights                       // 混合推荐的策略权重（为 nil 时使用 DefaultHybridWeights）
+	hybridFusion   service.FusionRanker                // 混合推荐的融合排序器（为 nil 时按策略权重加权合并）
+	backfillRepo   repository.PopularUsersRepository   // 补足一页的热门、精选用户（为 nil 时不补位）
+	feedbackRepo   repository.FeedbackRepository       // 推荐反馈记录（为 nil 时不记录反馈）
+	eventPublisher event.Publisher                     // 领域事件的发布者（为 nil 时不发布）
+	experiments    *ExperimentAssigner                 // A/B 实验（为 nil 时不做实验）
+	activeUsers    repository.ActiveUsersRepository    // 最近活跃的用户（为 nil 时不记录，见 ListPrecomputer）
+	transactions   TransactionManager                  // 多个仓储一起写入的事务（默认没有事务）
+}
+
+// RecommendationServiceOption 可选配置：为应用服务注入可选依赖
+//
+// 与领域层的 GeneratorOption 一样：
+// 必需依赖放在构造函数参数里，可选能力通过 Option 开启。
+type RecommendationServiceOption func(*RecommendationService)
+
+// WithMetrics 注入监控指标实现
+func WithMetrics(metrics Metrics) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		if metrics != nil {
+			s.metrics = metrics
+		}
+	}
+}
+
+// WithLogger 注入日志实现（生产环境应注入带脱敏的实现）
+func WithLogger(logger Logger) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// WithTracer 注入链路追踪实现（生产环境应注入带脱敏的实现）
+func WithTracer(tracer Tracer) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		if tracer != nil {
+			s.tracer = tracer
+		}
+	}
+}
+
+// WithTransactionManager 注入事务边界：一个用例中对多个仓储的写入一起提交或者一起回滚
+//
+// 没有注入时每个仓储的写入各自提交（内存仓储、没有数据库的环境）。
+func WithTransactionManager(transactions TransactionManager) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		if transactions != nil {
+			s.transactions = transactions
+		}
+	}
+}
+
+// WithContentSafetyPolicy 注入帖子预览的内容安全策略
+func WithContentSafetyPolicy(policy *ContentSafetyPolicy) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		if policy != nil {
+			s.contentSafety = policy
+		}
+	}
+}
+
+// WithImpressionRepository 注入曝光记录仓储
+//
+// 注入后，每次返回的推荐都会记录为曝光。
+func WithImpressionRepository(repo repository.ImpressionRepository) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.impressionRepo = repo
+	}
+}
+
+// WithExposureQuota 开启每日曝光配额
+//
+// 依赖曝光记录（WithImpressionRepository），没有曝光记录时配额不生效。
+func WithExposureQuota(quota *ExposureQuota) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.exposureQuota = quota
+	}
+}
+
+// WithUserErasureRepository 注入用户数据删除记录
+//
+// 注入后，已删除数据（且还没有重新完成引导）的用户不再生成推荐，
+// 直接返回空列表，也不会产生新的曝光记录。
+func WithUserErasureRepository(repo repository.UserErasureRepository) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.erasureRepo = repo
+	}
+}
+
+// WithTenants 注入租户配置
+//
+// 注入后，推荐理由文案按 ctx 中租户配置的语言获取。
+func WithTenants(tenants *Tenants) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.tenants = tenants
+	}
 }
 
 // UserRPCClient 用户服务RPC客户端接口
@@ -95,16 +221,43 @@ type UserRPCClient interface {
 type ContentServiceClient interface {
 	// GetRecentPosts 获取用户最近的帖子（从远程服务）
 	GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error)
+
+	// GetRecentPostsForUsers 批量获取多个用户最近的帖子（每人最多 limit 篇）
+	//
+	// 返回：用户ID → 帖子（没有帖子的用户可以不出现在结果中）
+	// 内容服务没有批量接口时返回 ErrBatchUnavailable，调用方逐个调用 GetRecentPosts。
+	GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error)
 }
 
+// ErrBatchUnavailable 远程服务没有提供批量接口（如旧版本的内容服务、配置服务）
+//
+// 不是故障：调用方改为逐个调用单个用户的接口，不算降级。
+var ErrBatchUnavailable = errors.New("batch api unavailable")
+
 // ReasonTextConfigClient 推荐理由文案配置服务客户端接口
 // 用于从配置服务获取推荐理由的展示文案
 type ReasonTextConfigClient interface {
 	// GetReasonText 获取推荐理由的展示文案
 	// reasonType: 推荐理由类型（如 "followed_by_following"）
 	// count: 相关用户数量（用于生成文案，如 "3 位你关注的人"）
+	// locale: 文案语言（如 "zh-CN"，来自请求或租户配置，见 Tenants.Locale；为空时由配置服务使用默认语言）
+	// variant: 文案版本（A/B 实验的分组配置，见 ExperimentVariant.ReasonTextVariant；为空时使用默认文案）
 	// 返回配置的文案，如果配置服务异常或没有配置，返回空字符串（会降级到本地逻辑）
-	GetReasonText(ctx context.Context, reasonType string, count int) (string, error)
+	GetReasonText(ctx context.Context, reasonType string, count int, locale string, variant string) (string, error)
+
+	// GetReasonTexts 批量获取推荐理由的展示文案（组装一页推荐只调用一次配置服务）
+	//
+	// 返回：请求 → 文案（没有配置文案的请求可以不出现在结果中，会降级到本地逻辑）
+	// 配置服务没有批量接口时返回 ErrBatchUnavailable，调用方逐个调用 GetReasonText。
+	GetReasonTexts(ctx context.Context, requests []ReasonTextRequest) (map[ReasonTextRequest]string, error)
+}
+
+// ReasonTextRequest 批量获取推荐理由文案的一项（同时作为结果的 key）
+type ReasonTextRequest struct {
+	ReasonType string // 推荐理由类型（如 "followed_by_following"）
+	Count      int    // 相关用户数量
+	Locale     string // 文案语言（为空时由配置服务使用默认语言）
+	Variant    string // 文案版本（A/B 实验的分组使用，为空时使用默认文案）
 }
 
 // UserInfo 用户信息（来自 user 服务）
@@ -117,9 +270,10 @@ type UserInfo struct {
 
 // PostInfo 帖子信息（来自 content 服务）
 type PostInfo struct {
-	PostID    int64
-	Content   string
-	CreatedAt string
+	PostID           int64
+	Content          string
+	CreatedAt        time.Time // 解析失败时为零值
+	ModerationStatus string    // 审核状态：approved / sensitive / nsfw（可能为空）
 }
 
 // NewRecommendationService 构造函数
@@ -138,6 +292,8 @@ type PostInfo struct {
 // - 单体应用：只传 contentRepo
 // - 微服务架构：只传 contentClient
 // - 混合架构：两者都传，优先远程服务，失败时降级到本地
+//
+// 需要其他顺序、其他来源或者健康信号时，使用 WithContentSources（此时忽略 contentRepo、contentClient）。
 func NewRecommendationService(
 	generator *service.RecommendationGenerator,
 	socialGraphRepo repository.SocialGraphRepository,
@@ -145,28 +301,53 @@ func NewRecommendationService(
 	contentClient ContentServiceClient,
 	userRPCClient UserRPCClient,
 	reasonConfigClient ReasonTextConfigClient,
+	opts ...RecommendationServiceOption,
 ) *RecommendationService {
-	return &RecommendationService{
+	s := &RecommendationService{
 		generator:          generator,
 		socialGraphRepo:    socialGraphRepo,
-		contentRepo:        contentRepo,
-		contentClient:      contentClient,
 		userRPCClient:      userRPCClient,
 		reasonConfigClient: reasonConfigClient,
+		metrics:            noopMetrics{},
+		logger:             noopLogger{},
+		tracer:             noopTracer{},
+		transactions:       noopTransactionManager{},
+		contentSafety:      DefaultContentSafetyPolicy(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.contentSources == nil {
+		s.contentSources = defaultContentSources(s.degradation.For(FallbackContent), contentClient, contentRepo)
+	}
+	return s
 }
 
 // GetFollowingBasedRecommendations 用例：获取基于关注的推荐
 //
 // 这是一个完整的业务用例（Use Case），展示了应用服务如何编排。
 //
-// 用例流程：
+// 用例分为两个阶段：
+//
+// 阶段一：召回与排序（rankCandidates，便宜）
 // 1. 参数转换：int64 → 领域对象（UserID）
 // 2. 调用领域服务：生成推荐列表
-// 3. 获取 Top N：按分数排序取前 N 个
+// 3. 按分数排序，执行每日曝光配额
+// 产物只有排好序的推荐对象（ID、分数、理由），不涉及任何展示数据。
+//
+// 阶段二：补全展示数据（hydrate，昂贵）
 // 4. 批量获取用户信息：调用 user 服务（性能优化）
-// 5. 获取用户帖子：调用 content 服务
+// 5. 获取用户帖子：调用 content 服务（按场景执行内容安全策略）
 // 6. 组装响应：领域对象 → DTO
+// 只对本次要返回的那一页执行。
+//
+// 最后：
+// 7. 记录曝光：返回给用户的推荐记为曝光
+//
+// 为什么要拆成两个阶段？
+// 跨服务调用（用户信息、帖子）是最贵的部分，应该只为真正返回的推荐付出这个成本。
+// 拆开之后，分页、批量等用例可以复用阶段一的排序结果，
+// 只对需要的那一页执行阶段二。
 //
 // 为什么这些逻辑在应用层？
 // - 跨服务调用：涉及技术细节（RPC）
@@ -183,81 +364,591 @@ func NewRecommendationService(
 // 性能考虑：
 // - 批量获取用户信息：避免 N+1 查询问题
 // - 容错处理：某个用户信息获取失败不影响整体
-// - 限制数量：通过 limit 参数控制返回数量
+// - 限制数量：通过 limit 参数控制返回数量（只补全这一页）
+// - 超时预算：补全超出请求剩余时间时，返回已组装好的部分结果（degraded）
 func (s *RecommendationService) GetFollowingBasedRecommendations(
 	ctx context.Context,
-	userID int64,
-	limit int,
+	req *dto.RecommendationRequest,
 ) (*dto.RecommendationResponse, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "RecommendationService.GetFollowingBasedRecommendations")
+	defer span.End()
+	span.SetAttribute(FieldUserID, req.UserID)
+	span.SetAttribute(FieldSurface, string(req.Surface))
 
 	// 步骤1：转换为领域对象
-	domainUserID, err := valueobject.NewUserID(userID)
+	domainUserID, err := valueobject.NewUserID(req.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 步骤2：调用领域服务生成推荐
-	recommendationList, err := s.generator.GenerateFollowingBasedRecommendations(
-		ctx, domainUserID, 7, // 最近7天
-	)
+	// 展示场景的数量上限（不修改调用方的请求对象）
+	policy := s.surfaces.For(req.Surface)
+	if maxLimit := policy.MaxLimit; maxLimit > 0 && req.Limit > maxLimit {
+		capped := *req
+		capped.Limit = maxLimit
+		req = &capped
+	}
+
+	// 时间窗口：和场景默认值相同时按没有指定处理（可以使用快照、增量同步）
+	if req.Days != 0 {
+		if req.Days < MinRecencyDays || req.Days > MaxRecencyDays {
+			return nil, fmt.Errorf("%w: %d days", ErrInvalidRecencyDays, req.Days)
+		}
+		if req.Days == policy.recencyDays() {
+			normalized := *req
+			normalized.Days = 0
+			req = &normalized
+		}
+	}
+
+	// 调用方的过滤条件（理由类型无法识别时返回 ErrUnknownReasonType）
+	criteria, err := requestCriteria(req.Filter)
 	if err != nil {
 		return nil, err
 	}
 
-	// 步骤3：获取 Top N 推荐
-	topRecommendations := recommendationList.GetTopN(limit)
+	// 用户已删除数据：不生成推荐
+	// 查询失败时返回错误，而不是当作"没有删除"继续生成
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, domainUserID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return notModifiedOr(req, &dto.RecommendationResponse{
+				Recommendations: []*dto.UserRecommendationDTO{},
+				Version:         recommendationVersion(nil, req.Surface, ""),
+			}), nil
+		}
+	}
+
+	// 记录活跃：预计算任务为最近活跃的用户提前生成推荐列表
+	s.touchActiveUser(ctx, domainUserID)
+
+	// 调试请求：快照中的推荐没有打分特征，总是重新生成（只接受快照的请求除外）
+	if req.Debug && req.Freshness != dto.FreshnessCachedOnly {
+		realtime := *req
+		realtime.Freshness = dto.FreshnessRealtime
+		req = &realtime
+	}
+
+	// 分页请求：按页返回同一份推荐列表（见 recommendationPage）
+	if req.Paginated() {
+		return s.recommendationPage(ctx, domainUserID, req, criteria)
+	}
+
+	// A/B 实验：用户所在的分组决定推荐策略、打分权重和理由文案（随 ctx 传递）
+	ctx = s.assignExperiment(ctx, domainUserID, req.Surface)
+
+	now := time.Now()
+	snapshotKey := SnapshotKey{
+		Tenant:  valueobject.TenantFromContext(ctx),
+		UserID:  req.UserID,
+		Surface: req.Surface,
+	}
+
+	// 会话内已经返回过的推荐对象（翻页、重复请求不再返回）
+	sessionKey := SessionKey{
+		Tenant:    snapshotKey.Tenant,
+		UserID:    req.UserID,
+		Surface:   req.Surface,
+		SessionID: req.SessionID,
+	}
+	returned := s.sessionReturned(ctx, sessionKey)
+
+	// 条件请求的快速路径：客户端缓存的就是现在可以直接使用的快照时，不召回排序、不补全
+	// 校准参数的版本也是列表版本的一部分：参数更新后客户端缓存的校准分数都需要刷新
+	calibrations := s.currentCalibrations()
+	if version, ok := s.unchangedSnapshotVersion(ctx, snapshotKey, req, returned, calibrations.version(), now); ok {
+		metadata := convertSamplingToMetadata(aggregate.FollowingSampling{}) // 快照不记录采样情况
+		metadata.CalibrationVersion = calibrations.version()
+		experimentArmFrom(ctx).stamp(metadata)
+		return s.notModified(req, metadata, version), nil
+	}
+
+	// 阶段一（步骤2~3）：召回并排序候选，取本次要返回的一页
+	// 新鲜度要求允许时直接使用最近的快照，跳过召回和排序
+	page, reserve, assignment, sampling, precomputed, err := s.rankPage(ctx, domainUserID, snapshotKey, req, returned, criteria, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 条件请求：列表版本和客户端缓存的相同时，不再补全展示数据
+	version := recommendationVersion(page, req.Surface, calibrations.version())
+	metadata := convertSamplingToMetadata(sampling)
+	metadata.CalibrationVersion = calibrations.version()
+	experimentArmFrom(ctx).stamp(metadata)
 
 	// 如果没有推荐，直接返回空列表
-	if len(topRecommendations) == 0 {
-		return &dto.RecommendationResponse{
+	if len(page) == 0 {
+		return notModifiedOr(req, &dto.RecommendationResponse{
 			Recommendations: []*dto.UserRecommendationDTO{},
-		}, nil
+			Metadata:        metadata,
+			Version:         version,
+		}), nil
+	}
+	if req.KnownVersion == version || req.SinceVersion == version {
+		return s.notModified(req, metadata, version), nil
 	}
 
-	// 步骤4：批量获取用户信息（优化性能）
-	userIDs := make([]int64, 0, len(topRecommendations))
-	for _, rec := range topRecommendations {
-		userIDs = append(userIDs, rec.TargetUserID().Value())
+	// 增量同步：找到客户端上次同步的快照时，只补全新增、变化的推荐
+	// 会话内去重之后的列表是接着上一页的一页，自定义时间窗口、过滤条件的列表和场景的列表不同，
+	// 都不和快照比较，也不保存为快照
+	fullList := returned == nil && req.Days == 0 && criteria.IsEmpty()
+	toHydrate := page
+	var tombstones []*dto.TombstoneDTO
+	var base *RecommendationSnapshot
+	if fullList {
+		base = s.findSyncBase(ctx, snapshotKey, req)
+	}
+	if base != nil && base.CalibrationVersion != calibrations.version() {
+		base = nil // 校准参数变了：没有变化的推荐校准分数也变了，返回完整列表
+	}
+	if base != nil {
+		toHydrate, tombstones = diffAgainstSnapshot(base, page, now)
 	}
 
-	userInfoMap, err := s.getUserInfoMap(ctx, userIDs)
+	// 阶段二（步骤4~6）：只为这一页补全展示数据
+	// 超时预算用完时返回已经组装好的部分推荐（degraded）
+	// 增量响应不补位：补位的候选不在快照中，客户端无法按墓碑、变化合并
+	if base != nil {
+		reserve = nil
+	}
+	recommendations, shown, degraded, err := s.hydrate(ctx, toHydrate, reserve, req.Surface)
 	if err != nil {
 		return nil, err
 	}
+	calibrations.applyCalibration(recommendations, shown)
+	if req.Debug && !precomputed {
+		applyScoreDetails(recommendations, shown)
+	}
+
+	// 步骤7：记录曝光（增量响应只记录新下发的推荐，其余的上次同步时已经记录过）
+	// 会话记录同步写入：下一次请求可能在曝光写入之前就到达
+	s.rememberSession(ctx, sessionKey, shown)
+	s.recordImpressions(ctx, domainUserID, req.Surface, shown, assignment)
+
+	resp := &dto.RecommendationResponse{
+		Recommendations: recommendations,
+		Degraded:        degraded,
+		Metadata:        metadata,
+		Delta:           base != nil,
+		Tombstones:      tombstones,
+		Precomputed:     precomputed,
+	}
+	if !degraded {
+		// 降级响应不完整，不带版本：客户端下次轮询会拿到完整列表
+		resp.Version = version
+		if !precomputed && fullList {
+			// 来自快照的列表不再保存：保存会刷新快照时间，旧列表就永远不会过期
+			snapshot := newRecommendationSnapshot(version, page, now)
+			snapshot.CalibrationVersion = calibrations.version()
+			s.saveSnapshot(ctx, snapshotKey, snapshot)
+		}
+	}
+	return resp, nil
+}
+
+// notModified 辅助方法：列表没有变化的响应
+//
+// 不记录曝光：客户端展示的是已经记录过曝光的缓存列表。
+func (s *RecommendationService) notModified(req *dto.RecommendationRequest, metadata *dto.ResponseMetadata, version string) *dto.RecommendationResponse {
+	s.metrics.IncCounter(MetricNotModifiedResponses, 1, map[string]string{
+		"surface": string(req.Surface),
+	})
+	return &dto.RecommendationResponse{
+		Recommendations: []*dto.UserRecommendationDTO{},
+		Metadata:        metadata,
+		Version:         version,
+		NotModified:     true,
+	}
+}
+
+// notModifiedOr 辅助方法：空列表的版本和客户端缓存（或上次同步）的相同时，返回 not_modified
+func notModifiedOr(req *dto.RecommendationRequest, resp *dto.RecommendationResponse) *dto.RecommendationResponse {
+	if resp.Version != "" && (req.KnownVersion == resp.Version || req.SinceVersion == resp.Version) {
+		resp.NotModified = true
+	}
+	return resp
+}
+
+// rankCandidates 阶段一：召回并排序候选（不调用任何展示相关的服务）
+//
+// 展示场景单独配置了推荐策略时使用场景的生成器，并按场景执行多样性规则、设置有效期。
+// days 是推荐信号的时间窗口，为 0 时使用场景的默认值；
+// freshness 决定能不能使用持久化的推荐列表（见 generateList）；
+// criteria 是调用方的过滤条件，在多样性规则之前执行。
+//
+// 开启了策略权重 bandit 时按各策略的权重调整顺序；
+// 用户在 A/B 实验中时使用分组的生成器，分组有自己的排序器时按分组的排序器排序（不再按策略权重调整）；
+// 场景配置了 RepeatWindow 时，最近展示过的推荐对象排到后面；
+// 开启了特征快照日志时，按采样比例记录全部候选打分时的特征。
+//
+// 返回：
+// - 按分数降序排列、已经执行过曝光配额的全部推荐
+// - 推荐信号来源的采样情况（写入响应元数据）
+func (s *RecommendationService) rankCandidates(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+	days int,
+	freshness dto.Freshness,
+	criteria aggregate.RecommendationCriteria,
+) ([]*aggregate.UserRecommendation, aggregate.FollowingSampling, error) {
+	policy := s.surfaces.For(surface)
+	generator, strategy, days := s.listSource(surface, days)
+	arm := experimentArmFrom(ctx)
+	generator, strategy = arm.listSource(generator, strategy)
+
+	// 调用领域服务生成推荐（或者使用缓存、持久化的推荐列表，见 generateList）
+	recommendationList, err := s.generateList(ctx, generator, strategy, viewerID, days, freshness)
+	if err != nil {
+		return nil, aggregate.FollowingSampling{}, err
+	}
+	recommendationList.ApplyCriteria(criteria)
+	recommendationList.ApplyDiversity(policy.MaxPerReasonType)
+	recommendationList.ApplyReferrerDiversity(policy.MaxPerReferrer)
+	s.reportExclusions(recommendationList)
+
+	// 按分数排序（按策略权重或实验分组的排序器调整顺序，降权最近展示过的，执行每日曝光配额）
+	ranked := recommendationList.GetTopN(recommendationList.Count())
+	if arm.ranks() {
+		ranked = arm.rank(ranked)
+	} else {
+		ranked = s.blendStrategies(ctx, surface, ranked)
+	}
+	ranked = s.applyRepeatSuppression(ctx, viewerID, surface, ranked)
+	ranked = s.applyExposureQuota(ctx, viewerID, ranked)
+	if policy.Expiry > 0 {
+		for _, rec := range ranked {
+			rec.ExpireIn(policy.Expiry)
+		}
+	}
+	s.featureLogger.Log(ctx, viewerID, surface, ranked)
+	return ranked, recommendationList.FollowingSampling(), nil
+}
+
+// listSource 辅助方法：展示场景使用的生成器、推荐策略名（列表缓存的 key）和时间窗口
+//
+// 场景单独配置了生成器时策略名为场景名，否则为 DefaultListStrategy；
+// days 为 0 时使用场景的默认值。
+func (s *RecommendationService) listSource(
+	surface dto.Surface,
+	days int,
+) (generator *service.RecommendationGenerator, strategy string, recencyDays int) {
+	policy := s.surfaces.For(surface)
+	generator, strategy = s.generator, DefaultListStrategy
+	if policy.Generator != nil {
+		generator, strategy = policy.Generator, string(surface)
+	}
+	if days == 0 {
+		days = policy.recencyDays()
+	}
+	return generator, strategy, days
+}
+
+// convertSamplingToMetadata 辅助方法：把采样情况转换为响应元数据
+//
+// 客户端和数据分析据此对比"采样"和"未采样"请求的推荐质量（如点击率、关注率）
+func convertSamplingToMetadata(sampling aggregate.FollowingSampling) *dto.ResponseMetadata {
+	return &dto.ResponseMetadata{
+		FollowingsSampled: sampling.Sampled(),
+		FollowingsTotal:   sampling.Total,
+		FollowingsUsed:    sampling.Used,
+	}
+}
+
+// responseAssemblyReserve 为组装和返回响应预留的时间
+//
+// 补全展示数据时，不能把请求的剩余时间全部用完：
+// 还需要时间把已经补全的推荐转换成响应、序列化、写回网络。
+const responseAssemblyReserve = 20 * time.Millisecond
+
+// hydratedItem 一条补全完成的推荐（index 是它在这一页中的位置）
+type hydratedItem struct {
+	index int
+	dto   *dto.UserRecommendationDTO
+}
+
+// hydrate 阶段二：为一页推荐补全用户信息、帖子和理由文案
+//
+// 超时预算：
+// 请求带有 deadline 时，补全只能使用"剩余时间 - responseAssemblyReserve"。
+// 预算用完时，不再等待还没补全的推荐，只返回已经完整组装好的那些，
+// 并把响应标记为降级（degraded = true），而不是让整个请求超时失败。
+//
+// 为什么按推荐逐个跟踪？
+// 每条推荐的帖子、理由文案来自不同的下游调用，完成时间各不相同。
+// 每条推荐独立补全，补全完成才算"组装好"，
+// 预算用完时，半成品（如帖子还没取回）不会出现在响应中。
+//
+// 资料缺失：
+// user 服务没有返回某个推荐对象的资料时，按 WithProfileMissPolicy 跳过、用 reserve 补位或返回占位资料。
+//
+// 返回：
+// - 组装好的 DTO（保持 page 的顺序，可能少于 page）
+// - 实际返回给用户的推荐（用于记录曝光）
+// - 是否因为超时预算而降级
+func (s *RecommendationService) hydrate(
+	ctx context.Context,
+	page []*aggregate.UserRecommendation,
+	reserve []*aggregate.UserRecommendation,
+	surface dto.Surface,
+) ([]*dto.UserRecommendationDTO, []*aggregate.UserRecommendation, bool, error) {
+	if len(page) == 0 {
+		// 增量同步时可能只有墓碑，没有需要补全的推荐
+		return []*dto.UserRecommendationDTO{}, []*aggregate.UserRecommendation{}, false, nil
+	}
+	budget, cancel := hydrationBudget(ctx)
+	defer cancel()
+
+	// 批量获取用户信息（优化性能，补位的候选一起获取）
+	userIDs := make([]int64, 0, len(page)+len(reserve))
+	for _, rec := range page {
+		userIDs = append(userIDs, rec.TargetUserID().Value())
+	}
+	for _, rec := range reserve {
+		userIDs = append(userIDs, rec.TargetUserID().Value())
+	}
+
+	userInfoMap, err := s.getUserInfoMap(budget, userIDs)
+	if err != nil {
+		if budget.Err() != nil && ctx.Err() == nil {
+			// 预算内连用户信息都没拿到：没有任何可以返回的推荐
+			return []*dto.UserRecommendationDTO{}, []*aggregate.UserRecommendation{}, true, nil
+		}
+		return nil, nil, false, err
+	}
+
+	// 资料缺失的推荐：跳过、补位或使用占位资料
+	page, profilePending := s.resolveProfileMisses(page, reserve, userInfoMap, surface)
+
+	// 批量获取所有推荐用户的帖子（一次调用，而不是每条推荐一次）
+	pageUserIDs := make([]int64, 0, len(page))
+	for _, rec := range page {
+		pageUserIDs = append(pageUserIDs, rec.TargetUserID().Value())
+	}
+	postsByUser := s.getRecentPostsForUsers(budget, pageUserIDs, s.surfaces.For(surface).previewPosts(), surface)
 
-	// 步骤5：组装响应数据
-	response := &dto.RecommendationResponse{}
-	response.Recommendations = make([]*dto.UserRecommendationDTO, 0, len(topRecommendations))
+	// 批量获取这一页的推荐理由文案（同样只调用一次配置服务）
+	reasons := make([]valueobject.RecommendationReason, 0, len(page))
+	for _, rec := range page {
+		reasons = append(reasons, rec.Reason())
+	}
+	reasonTexts := s.prefetchReasonTexts(budget, reasons)
+
+	// 每条推荐独立补全，完成后通过 channel 交回
+	// channel 有足够的缓冲：预算用完后才完成的推荐也不会阻塞
+	completed := make(chan hydratedItem, len(page))
+	pending := 0
+	for i, rec := range page {
+		userInfo := userInfoMap[rec.TargetUserID().Value()]
+		pending++
+		go func(i int, rec *aggregate.UserRecommendation, userInfo *UserInfo) {
+			completed <- hydratedItem{index: i, dto: s.hydrateOne(budget, rec, userInfo, profilePending[rec.TargetUserID().Value()], postsByUser, reasonTexts, surface)}
+		}(i, rec, userInfo)
+	}
 
-	for _, rec := range topRecommendations {
-		// 获取用户详情
-		userInfo, exists := userInfoMap[rec.TargetUserID().Value()]
-		if !exists {
-			continue // 跳过无法获取信息的用户
+	// 收集补全完成的推荐，直到全部完成或预算用完
+	items := make([]*dto.UserRecommendationDTO, len(page))
+	degraded := false
+collect:
+	for ; pending > 0; pending-- {
+		select {
+		case item := <-completed:
+			items[item.index] = item.dto
+		case <-budget.Done():
+			degraded = true
+			break collect
 		}
+	}
 
-		// 获取用户最近的帖子
-		// 优先使用远程服务，失败时降级到本地数据库
-		posts := s.getRecentPosts(ctx, rec.TargetUserID().Value(), 3)
-
-		// 获取推荐理由文案（优先使用配置服务）
-		reasonText := s.getReasonText(ctx, rec.Reason())
-
-		// 转换为 DTO
-		recommendationDTO := &dto.UserRecommendationDTO{
-			UserID:      rec.TargetUserID().Value(),
-			Username:    userInfo.Username,
-			Avatar:      userInfo.Avatar,
-			Bio:         userInfo.Bio,
-			Reason:      reasonText,
-			Score:       rec.Score(),
-			RecentPosts: posts,
+	// 组装响应数据：按原来的顺序，跳过没有补全的推荐
+	recommendations := make([]*dto.UserRecommendationDTO, 0, len(page))
+	shown := make([]*aggregate.UserRecommendation, 0, len(page))
+	for i, item := range items {
+		if item == nil {
+			continue
 		}
+		recommendations = append(recommendations, item)
+		shown = append(shown, page[i])
+	}
 
-		response.Recommendations = append(response.Recommendations, recommendationDTO)
+	if degraded {
+		s.metrics.IncCounter(MetricPartialResponses, 1, map[string]string{
+			"surface": string(surface),
+		})
 	}
+	return recommendations, shown, degraded, nil
+}
 
-	return response, nil
+// hydrateOne 辅助方法：补全一条推荐的帖子和理由文案
+//
+// 帖子、理由文案优先使用批量获取的结果（postsByUser、reasonTexts），批量结果中没有时单独获取。
+//
+// 资料是占位的（profilePending）、帖子没有取到时，在 DTO 的 Degraded 中标记，
+// 客户端可以只刷新缺失的部分，而不是把整条推荐当作失败。
+func (s *RecommendationService) hydrateOne(
+	ctx context.Context,
+	rec *aggregate.UserRecommendation,
+	userInfo *UserInfo,
+	profilePending bool,
+	postsByUser map[int64][]*dto.PostDTO,
+	reasonTexts map[ReasonTextRequest]string,
+	surface dto.Surface,
+) *dto.UserRecommendationDTO {
+	// 获取用户最近的帖子（篇数、预览长度按展示场景配置）
+	// 优先使用远程服务，失败时降级到本地数据库
+	policy := s.surfaces.For(surface)
+	posts, postsLoaded := postsByUser[rec.TargetUserID().Value()]
+	if !postsLoaded {
+		posts, postsLoaded = s.getRecentPosts(ctx, rec.TargetUserID().Value(), policy.previewPosts(), surface)
+	}
+	if policy.PreviewLength > 0 {
+		for _, post := range posts {
+			post.Content = truncatePreview(post.Content, policy.PreviewLength)
+		}
+	}
+
+	// 获取推荐理由文案（优先使用配置服务）
+	reasonText := s.getReasonText(ctx, rec.Reason(), reasonTexts)
+
+	// 转换为 DTO
+	item := &dto.UserRecommendationDTO{
+		UserID:         rec.TargetUserID().Value(),
+		Username:       userInfo.Username,
+		Avatar:         userInfo.Avatar,
+		Bio:            userInfo.Bio,
+		Reason:         reasonText,
+		ReasonType:     reasonTypeKey(rec.Reason().Type()),
+		Score:          rec.Score(),
+		RecentPosts:    posts,
+		ProfilePending: profilePending,
+	}
+	if profilePending {
+		item.Degraded = append(item.Degraded, dto.DegradedProfile)
+	}
+	if !postsLoaded {
+		item.Degraded = append(item.Degraded, dto.DegradedPosts)
+	}
+	return item
+}
+
+// truncatePreview 辅助函数：把帖子预览截断到 maxRunes 个字符（含省略号）
+//
+// 按字符（rune）而不是字节截断：按字节截断会把中文、emoji 切成无效的 UTF-8。
+func truncatePreview(content string, maxRunes int) string {
+	if utf8.RuneCountInString(content) <= maxRunes {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:maxRunes-1]) + "…"
+}
+
+// hydrationBudget 辅助方法：计算补全展示数据可以使用的时间预算
+//
+// 请求没有 deadline 时不限制（与原来的行为一致）
+func hydrationBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline.Add(-responseAssemblyReserve))
+}
+
+// applyExposureQuota 辅助方法：对排好序的推荐执行每日曝光配额
+//
+// 容错设计：
+// - 没有开启配额：原样返回
+// - 曝光记录查询失败：不执行配额（不因为配额功能影响推荐可用性）
+func (s *RecommendationService) applyExposureQuota(
+	ctx context.Context,
+	userID valueobject.UserID,
+	ranked []*aggregate.UserRecommendation,
+) []*aggregate.UserRecommendation {
+	if s.exposureQuota == nil || s.impressionRepo == nil {
+		return ranked
+	}
+
+	since := s.exposureQuota.WindowStart(time.Now())
+	shownTargets, err := s.impressionRepo.GetImpressedTargets(ctx, userID, since)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "exposure quota skipped: impression lookup failed", map[string]any{
+			FieldUserID: userID.Value(),
+			FieldError:  err.Error(),
+		})
+		return ranked
+	}
+
+	shownToday := make(map[valueobject.UserID]bool, len(shownTargets))
+	for _, target := range shownTargets {
+		shownToday[target] = true
+	}
+
+	selected, suppressed := s.exposureQuota.Select(ranked, shownToday, len(ranked))
+	if suppressed > 0 {
+		s.metrics.IncCounter(MetricQuotaSuppressed, suppressed, nil)
+	}
+	return selected
+}
+
+// recordImpressions 辅助方法：把返回给用户的推荐记录为曝光
+//
+// 交错实验的列表（assignment 不为 nil）在曝光上标记实验名和排序器；
+// 用户在 A/B 实验中时（ctx 中有分组）标记实验名和分组名。
+//
+// 容错设计：写入失败不影响本次响应（曝光记录是辅助数据）
+func (s *RecommendationService) recordImpressions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+	shown []*aggregate.UserRecommendation,
+	assignment *interleavingAssignment,
+) {
+	if s.impressionRepo == nil || len(shown) == 0 {
+		return
+	}
+
+	now := time.Now()
+	arm := experimentArmFrom(ctx)
+	impressions := make([]*entity.Impression, 0, len(shown))
+	for _, rec := range shown {
+		impression := entity.NewImpression(
+			rec.ID(),
+			viewerID,
+			rec.TargetUserID(),
+			rec.Reason().Type(),
+			string(surface),
+			now,
+		)
+		if team, ok := assignment.teamOf(rec); ok {
+			impression.TagExperiment(assignment.experiment, team)
+		}
+		if arm != nil {
+			impression.AssignVariant(arm.assignment)
+		}
+		impressions = append(impressions, impression)
+	}
+	if err := s.impressionRepo.RecordImpressions(ctx, impressions); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "record impressions failed", map[string]any{
+			FieldViewerID: viewerID.Value(),
+			FieldCount:    len(impressions),
+			FieldError:    err.Error(),
+		})
+		return
+	}
+	s.bandit.RecordPulls(ctx, string(surface), shown)
+}
+
+// reportExclusions 辅助方法：上报生成过程中被排除的候选数
+//
+// 例如机器人账号被风控过滤的数量，用于观察垃圾账号的规模和过滤效果。
+func (s *RecommendationService) reportExclusions(list *aggregate.RecommendationList) {
+	for reason, count := range list.Exclusions() {
+		s.metrics.IncCounter(MetricExcludedCandidates, count, map[string]string{
+			"reason": string(reason),
+		})
+	}
 }
 
 // getUserInfoMap 辅助方法：批量获取用户信息并转换为 map
@@ -281,86 +972,154 @@ func (s *RecommendationService) getUserInfoMap(
 //
 // 这个方法展示了如何在微服务架构中处理跨服务调用，同时保持降级能力。
 //
-// 调用策略（优先级从高到低）：
-// 1. 优先使用远程服务（contentClient）
-// 2. 如果远程服务不可用或失败，降级到本地数据库（contentRepo）
+// 调用策略（来源链 ContentSourceChain，在组合根中配置，见 WithContentSources）：
+// 1. 按顺序尝试每个来源（默认先远程服务 contentClient，再本地数据库 contentRepo）
+// 2. 来源失败、超时或者健康信号为不健康时，转到下一个来源
 // 3. 如果都失败，返回空列表（容错）
 //
 // 为什么需要这种设计？
 // - 微服务架构：帖子数据可能在其他服务
 // - 容错性：远程服务不可用时不影响推荐功能
-// - 灵活性：支持单体和微服务两种架构
-//
-// 实际场景：
-//
-//	// 场景1：纯微服务架构
-//	contentClient != nil, contentRepo = nil
-//	→ 只调用远程服务
-//
-//	// 场景2：单体应用
-//	contentClient = nil, contentRepo != nil
-//	→ 只查本地数据库
-//
-//	// 场景3：混合架构（推荐）
-//	contentClient != nil, contentRepo != nil
-//	→ 优先远程服务，失败时降级到本地
+// - 灵活性：支持单体和微服务两种架构，也可以接入其他来源
 //
 // 性能考虑：
 // - 远程调用失败不重试（避免级联延迟）
-// - 降级到本地数据库（快速响应）
-// - 最坏情况返回空列表（不阻塞推荐）
-func (s *RecommendationService) getRecentPosts(ctx context.Context, userID int64, limit int) []*dto.PostDTO {
-	// 策略1：优先使用远程服务
-	if s.contentClient != nil {
-		posts, err := s.contentClient.GetRecentPosts(ctx, userID, limit)
-		if err == nil && posts != nil {
-			// 转换 PostInfo → PostDTO
-			result := make([]*dto.PostDTO, 0, len(posts))
-			for _, post := range posts {
-				result = append(result, &dto.PostDTO{
-					PostID:    post.PostID,
-					Content:   post.Content,
-					CreatedAt: post.CreatedAt,
-				})
-			}
-			return result
+// - 每个来源有自己的超时（慢的远程服务不拖住整个请求）
+// - 熔断器打开的来源直接跳过，不再等它超时
+// - 最坏情况返回空列表（不阻塞推荐），第二个返回值为 false（调用方标记为降级）
+//
+// 内容安全：
+// 无论帖子来自哪个来源，都按展示场景执行内容安全策略，
+// 被标记为敏感/NSFW 的帖子会被遮挡或移除，不会原样返回给客户端。
+func (s *RecommendationService) getRecentPosts(
+	ctx context.Context,
+	userID int64,
+	limit int,
+	surface dto.Surface,
+) ([]*dto.PostDTO, bool) {
+	posts, err := loadFromChain(ctx, s.contentSources, s.degradation, func(ctx context.Context, source ContentSource) ([]*PostInfo, error) {
+		posts, err := source.GetRecentPosts(ctx, userID, limit)
+		if err == nil && posts == nil {
+			return nil, errEmptyResult
 		}
-		// 远程服务失败，继续尝试本地数据库
+		return posts, err
+	})
+	if err != nil {
+		// 容错 - 返回空列表
+		return []*dto.PostDTO{}, false
 	}
+	return s.convertPostInfosToDTO(posts, surface), true
+}
 
-	// 策略2：降级到本地数据库
-	if s.contentRepo != nil {
-		domainUserID, err := valueobject.NewUserID(userID)
-		if err != nil {
-			return []*dto.PostDTO{} // 容错：ID 无效
+// getRecentPostsForUsers 辅助方法：批量获取多个用户最近的帖子
+//
+// 来源和 getRecentPosts 相同（同一个来源链），每个来源只调用一次批量接口。
+// 批量获取成功时，结果中包含每个用户（没有帖子的用户为空列表）。
+//
+// 返回 nil 或者缺少某个用户时，调用方对这些用户逐个调用 getRecentPosts（并发执行，见 hydrate）：
+// - 来源没有批量接口（ErrBatchUnavailable）：返回空 map，不算降级，也不转而批量查询下一个来源
+// - 所有来源的批量接口都失败：返回 nil
+func (s *RecommendationService) getRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []int64,
+	limit int,
+	surface dto.Surface,
+) map[int64][]*dto.PostDTO {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	posts, err := loadFromChain(ctx, s.contentSources, s.degradation, func(ctx context.Context, source ContentSource) (map[int64][]*PostInfo, error) {
+		posts, err := source.GetRecentPostsForUsers(ctx, userIDs, limit)
+		if errors.Is(err, ErrBatchUnavailable) {
+			return nil, nil
 		}
+		return posts, err
+	})
+	if err != nil {
+		// 容错：逐个获取
+		return nil
+	}
+	if posts == nil {
+		return map[int64][]*dto.PostDTO{}
+	}
+	result := make(map[int64][]*dto.PostDTO, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = s.convertPostInfosToDTO(posts[userID], surface)
+	}
+	return result
+}
 
-		posts, err := s.contentRepo.GetRecentPosts(ctx, domainUserID, limit)
-		if err == nil && posts != nil {
-			return s.convertPostsToDTO(posts)
+// convertPostInfosToDTO 辅助方法：转换内容服务返回的帖子为 DTO（执行内容安全策略）
+func (s *RecommendationService) convertPostInfosToDTO(posts []*PostInfo, surface dto.Surface) []*dto.PostDTO {
+	result := make([]*dto.PostDTO, 0, len(posts))
+	for _, post := range posts {
+		postDTO := s.applyContentSafety(surface, &dto.PostDTO{
+			PostID:    post.PostID,
+			Content:   post.Content,
+			CreatedAt: post.CreatedAt,
+		}, valueobject.ParseModerationStatus(post.ModerationStatus))
+		if postDTO != nil {
+			result = append(result, postDTO)
 		}
-		// 本地数据库也失败，返回空列表
 	}
+	return result
+}
 
-	// 策略3：容错 - 返回空列表
-	return []*dto.PostDTO{}
+// applyContentSafety 辅助方法：执行内容安全策略并上报被处理的帖子数
+//
+// 返回 nil 表示帖子不应该展示
+func (s *RecommendationService) applyContentSafety(
+	surface dto.Surface,
+	post *dto.PostDTO,
+	status valueobject.ModerationStatus,
+) *dto.PostDTO {
+	action := s.contentSafety.ActionFor(surface, status)
+	if action != PreviewShow {
+		s.metrics.IncCounter(MetricModeratedPreviews, 1, map[string]string{
+			"surface": string(surface),
+			"status":  status.String(),
+		})
+	}
+	return s.contentSafety.Apply(surface, post, status)
 }
 
-// convertPostsToDTO 辅助方法：转换帖子实体为 DTO
-func (s *RecommendationService) convertPostsToDTO(posts []*entity.Post) []*dto.PostDTO {
-	if posts == nil {
-		return []*dto.PostDTO{}
+// reasonTypeKey 辅助函数：推荐理由类型的外部标识（配置服务的文案 key、效果分析的维度）
+func reasonTypeKey(reasonType valueobject.ReasonType) string {
+	switch reasonType {
+	case valueobject.ReasonFollowedByFollowing:
+		return "followed_by_following"
+	case valueobject.ReasonPopularInNetwork:
+		return "popular_in_network"
+	case valueobject.ReasonTopicFollowedByFollowing:
+		return "topic_followed_by_following"
+	case valueobject.ReasonFriendsAreMembers:
+		return "friends_are_members"
+	case valueobject.ReasonFollowedUserMilestone:
+		return "followed_user_milestone"
+	case valueobject.ReasonFollowedUserReturned:
+		return "followed_user_returned"
+	case valueobject.ReasonMissedPosts:
+		return "missed_posts"
+	case valueobject.ReasonManyMutualFollows:
+		return "many_mutual_follows"
+	case valueobject.ReasonPopularOnPlatform:
+		return "popular_on_platform"
+	case valueobject.ReasonEditorPick:
+		return "editor_pick"
+	case valueobject.ReasonBackfill:
+		return "backfill"
+	default:
+		return "default"
 	}
+}
 
-	result := make([]*dto.PostDTO, 0, len(posts))
-	for _, post := range posts {
-		result = append(result, &dto.PostDTO{
-			PostID:    post.ID().Value(),
-			Content:   post.Content(),
-			CreatedAt: post.CreatedAt().Format("2006-01-02 15:04:05"),
-		})
+// reasonTypeKeys 辅助函数：一组推荐理由类型的名称（顺序不变）
+func reasonTypeKeys(reasonTypes []valueobject.ReasonType) []string {
+	keys := make([]string, 0, len(reasonTypes))
+	for _, reasonType := range reasonTypes {
+		keys = append(keys, reasonTypeKey(reasonType))
 	}
-	return result
+	return keys
 }
 
 // getReasonText 辅助方法：获取推荐理由文案
@@ -397,38 +1156,112 @@ func (s *RecommendationService) convertPostsToDTO(posts []*entity.Post) []*dto.P
 // - 配置服务返回空字符串时降级到本地逻辑
 //
 // 扩展性：
-// 未来可以添加更多逻辑：
-// - 缓存配置文案（减少 HTTP 调用）
-// - A/B 测试（根据用户分组返回不同文案）
-// - 多语言支持（根据用户语言返回对应文案）
-func (s *RecommendationService) getReasonText(ctx context.Context, reason valueobject.RecommendationReason) string {
-	// 如果没有配置客户端，直接使用本地逻辑
-	if s.reasonConfigClient == nil {
-		return reason.Description()
+// - A/B 测试：用户在实验中时按分组请求不同版本的文案（见 WithExperiments）
+// - 多语言支持（目前按租户配置的语言，未来可以按用户语言）
+//
+// 减少 HTTP 调用：文案由 client.CachedReasonTextConfigClient 缓存；
+// prefetched 是 prefetchReasonTexts 批量获取的文案（可以为 nil），其中有的请求不再调用配置服务。
+func (s *RecommendationService) getReasonText(
+	ctx context.Context,
+	reason valueobject.RecommendationReason,
+	prefetched map[ReasonTextRequest]string,
+) string {
+	return reasonText(ctx, s.reasonConfigClient, s.tenants, s.degradation, reason, prefetched)
+}
+
+// prefetchReasonTexts 辅助方法：批量获取一组推荐理由的文案（相同的类型、人数、语言只请求一次）
+//
+// 批量获取成功时，结果中包含每个请求（没有配置文案的请求为空字符串，getReasonText 降级到本地逻辑）。
+// 返回 nil 时 getReasonText 逐个调用配置服务（降级在那里统一处理和上报），包括：
+// - 没有配置服务，或者降级策略的第一个来源不是配置服务
+// - 配置服务没有批量接口（ErrBatchUnavailable）
+// - 批量调用失败、超时（超时和单个调用相同：DegradationPolicy 中 reason_text 的 remote）
+func (s *RecommendationService) prefetchReasonTexts(
+	ctx context.Context,
+	reasons []valueobject.RecommendationReason,
+) map[ReasonTextRequest]string {
+	policy := s.degradation.For(FallbackReasonText)
+	if s.reasonConfigClient == nil || len(reasons) == 0 || len(policy.Order) == 0 || policy.Order[0] != SourceRemote {
+		return nil
+	}
+	seen := make(map[ReasonTextRequest]bool, len(reasons))
+	requests := make([]ReasonTextRequest, 0, len(reasons))
+	for _, reason := range reasons {
+		request := reasonTextRequest(ctx, s.tenants, reason)
+		if !seen[request] {
+			seen[request] = true
+			requests = append(requests, request)
+		}
 	}
 
-	// 将领域对象的类型转换为配置服务的类型标识
-	var reasonType string
-	switch reason.Type() {
-	case valueobject.ReasonFollowedByFollowing:
-		reasonType = "followed_by_following"
-	case valueobject.ReasonPopularInNetwork:
-		reasonType = "popular_in_network"
-	default:
-		reasonType = "default"
+	texts, err := loadWithTimeout(ctx, policy.TimeoutFor(SourceRemote), func(ctx context.Context) (map[ReasonTextRequest]string, error) {
+		return s.reasonConfigClient.GetReasonTexts(ctx, requests)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrBatchUnavailable) {
+			s.logger.Log(ctx, LogLevelWarn, "batch reason texts failed, fetching one by one", map[string]any{
+				FieldError: err.Error(),
+			})
+		}
+		return nil
+	}
+	result := make(map[ReasonTextRequest]string, len(requests))
+	for _, request := range requests {
+		result[request] = texts[request]
 	}
+	return result
+}
 
-	// 尝试从配置服务获取文案
-	configText, err := s.reasonConfigClient.GetReasonText(
-		ctx,
-		reasonType,
-		len(reason.RelatedUsers()),
-	)
+// reasonTextRequest 辅助函数：推荐理由 → 配置服务的请求（领域对象的类型转换为配置服务的类型标识）
+//
+// 用户在 A/B 实验中时（ctx 中有分组）请求分组的文案版本。
+func reasonTextRequest(ctx context.Context, tenants *Tenants, reason valueobject.RecommendationReason) ReasonTextRequest {
+	return ReasonTextRequest{
+		ReasonType: reasonTypeKey(reason.Type()),
+		Count:      reason.RelatedCount(),
+		Locale:     tenants.Locale(ctx).String(),
+		Variant:    experimentArmFrom(ctx).reasonTextVariant(),
+	}
+}
 
-	// 容错处理：配置服务异常或返回空，降级到本地逻辑
-	if err != nil || configText == "" {
-		return reason.Description()
+// reasonText 辅助函数：getReasonText 的实现（话题推荐等其他应用服务共用）
+//
+// degradation 为 nil 时按默认顺序（配置服务 → 本地逻辑），不上报降级。
+// prefetched 中有的请求直接使用其中的文案（空字符串同样降级到本地逻辑），不再调用配置服务。
+func reasonText(
+	ctx context.Context,
+	client ReasonTextConfigClient,
+	tenants *Tenants,
+	degradation *DegradationPolicy,
+	reason valueobject.RecommendationReason,
+	prefetched map[ReasonTextRequest]string,
+) string {
+	loaders := map[FallbackSource]func(ctx context.Context) (string, error){
+		SourceLocal: func(ctx context.Context) (string, error) {
+			return reason.DescriptionIn(tenants.Locale(ctx)), nil
+		},
+	}
+	// 如果没有配置客户端，直接使用本地逻辑
+	if client != nil {
+		loaders[SourceRemote] = func(ctx context.Context) (string, error) {
+			request := reasonTextRequest(ctx, tenants, reason)
+			configText, ok := prefetched[request]
+			var err error
+			if !ok {
+				// 尝试从配置服务获取文案
+				configText, err = client.GetReasonText(ctx, request.ReasonType, request.Count, request.Locale, request.Variant)
+			}
+			if err == nil && configText == "" {
+				err = errEmptyResult
+			}
+			return configText, err
+		}
 	}
 
-	return configText
+	// 容错处理：配置服务异常或返回空，降级到本地逻辑（本地逻辑不在降级顺序中时也使用它兜底）
+	text, err := degrade(ctx, degradation, FallbackReasonText, loaders)
+	if err != nil {
+		return reason.DescriptionIn(tenants.Locale(ctx))
+	}
+	return text
 }
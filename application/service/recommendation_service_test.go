@@ -0,0 +1,1338 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	domainService "service/domain/service"
+
+	"service/domain/aggregate"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// stubSocialGraphRepo 测试用社交图谱：用户 1 关注了 2、3；2 最近关注了 10、11；3 最近关注了 10、12
+type stubSocialGraphRepo struct{}
+
+func (stubSocialGraphRepo) GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	return userIDs(2, 3), nil
+}
+
+func (stubSocialGraphRepo) GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error) {
+	switch userID.Value() {
+	case 2:
+		return userIDs(10, 11), nil
+	case 3:
+		return userIDs(10, 12), nil
+	}
+	return nil, nil
+}
+
+func (r stubSocialGraphRepo) GetRecentFollowingsBatch(ctx context.Context, ids []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error) {
+	result := make(map[valueobject.UserID][]valueobject.UserID, len(ids))
+	for _, id := range ids {
+		result[id], _ = r.GetRecentFollowings(ctx, id, days)
+	}
+	return result, nil
+}
+
+func (stubSocialGraphRepo) CountFollowings(ctx context.Context, userID valueobject.UserID) (int, error) {
+	return 2, nil
+}
+
+func (r stubSocialGraphRepo) ScanFollowings(ctx context.Context, userID valueobject.UserID, pageSize int, fn func(page []valueobject.UserID) error) error {
+	followings, _ := r.GetFollowings(ctx, userID)
+	return fn(followings)
+}
+
+func (stubSocialGraphRepo) IsFollowing(ctx context.Context, followerID, followingID valueobject.UserID) (bool, error) {
+	return false, nil
+}
+
+func (stubSocialGraphRepo) GetMutualFollows(ctx context.Context, userID valueobject.UserID, limit int) ([]valueobject.UserID, error) {
+	return nil, nil
+}
+
+func (stubSocialGraphRepo) FindFollow(ctx context.Context, followerID, followingID valueobject.UserID) (*entity.Follow, error) {
+	return nil, nil
+}
+
+func (stubSocialGraphRepo) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	return nil
+}
+
+func (stubSocialGraphRepo) RemoveFollow(ctx context.Context, followerID, followingID valueobject.UserID, removedAt time.Time) error {
+	return nil
+}
+
+// stubContentRepo 测试用内容仓储：每个用户都有 1 篇最近的帖子
+type stubContentRepo struct{}
+
+func (stubContentRepo) CountRecentPosts(ctx context.Context, userID valueobject.UserID, days int) (int, error) {
+	return 1, nil
+}
+
+func (stubContentRepo) CountRecentPostsBatch(ctx context.Context, ids []valueobject.UserID, days int) (map[valueobject.UserID]int, error) {
+	result := make(map[valueobject.UserID]int, len(ids))
+	for _, id := range ids {
+		result[id] = 1
+	}
+	return result, nil
+}
+
+func (stubContentRepo) GetRecentPosts(ctx context.Context, userID valueobject.UserID, limit int) ([]*entity.Post, error) {
+	return nil, nil
+}
+
+func (stubContentRepo) GetRecentPostsForUsers(ctx context.Context, ids []valueobject.UserID, limit int) (map[valueobject.UserID][]*entity.Post, error) {
+	return nil, nil
+}
+
+// stubUserRPCClient 测试用用户服务：所有用户都存在
+type stubUserRPCClient struct{}
+
+func (stubUserRPCClient) GetUserInfo(ctx context.Context, userID int64) (*UserInfo, error) {
+	return &UserInfo{UserID: userID}, nil
+}
+
+func (stubUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*UserInfo, error) {
+	result := make([]*UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		result = append(result, &UserInfo{UserID: userID})
+	}
+	return result, nil
+}
+
+// slowContentClient 测试用内容服务：slowUserID 的帖子要等到请求超时
+type slowContentClient struct {
+	slowUserID int64
+}
+
+func (c slowContentClient) GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error) {
+	if userID == c.slowUserID {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return []*PostInfo{{PostID: userID * 100, Content: "hello"}}, nil
+}
+
+// GetRecentPostsForUsers 模拟没有批量接口的内容服务（逐个调用 GetRecentPosts）
+func (c slowContentClient) GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error) {
+	return nil, ErrBatchUnavailable
+}
+
+// batchContentClient 测试用内容服务：提供批量接口，统计单个用户接口的调用次数
+type batchContentClient struct {
+	singleCalls atomic.Int32
+}
+
+func (c *batchContentClient) GetRecentPosts(ctx context.Context, userID int64, limit int) ([]*PostInfo, error) {
+	c.singleCalls.Add(1)
+	return []*PostInfo{{PostID: userID * 100, Content: "hello"}}, nil
+}
+
+func (c *batchContentClient) GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error) {
+	result := make(map[int64][]*PostInfo, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = []*PostInfo{{PostID: userID * 100, Content: "hello"}}
+	}
+	return result, nil
+}
+
+func userIDs(ids ...int64) []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, _ := valueobject.NewUserID(id)
+		result = append(result, userID)
+	}
+	return result
+}
+
+func newTestRecommendationService(contentClient ContentServiceClient) *RecommendationService {
+	generator := domainService.NewRecommendationGenerator(stubSocialGraphRepo{}, stubContentRepo{})
+	return NewRecommendationService(
+		generator,
+		stubSocialGraphRepo{},
+		stubContentRepo{},
+		contentClient,
+		stubUserRPCClient{},
+		nil,
+	)
+}
+
+func TestGetFollowingBasedRecommendations_PartialResponseOnDeadline(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: 11})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if !resp.Degraded {
+		t.Errorf("Degraded = false, want true")
+	}
+	if len(resp.Recommendations) != 2 {
+		t.Fatalf("len(Recommendations) = %d, want 2", len(resp.Recommendations))
+	}
+	for _, rec := range resp.Recommendations {
+		if rec.UserID == 11 {
+			t.Errorf("unfinished recommendation %d should not be returned", rec.UserID)
+		}
+		if len(rec.RecentPosts) != 1 {
+			t.Errorf("recommendation %d has %d posts, want 1", rec.UserID, len(rec.RecentPosts))
+		}
+	}
+	// 排序保持不变：10 被两个人关注，分数最高
+	if resp.Recommendations[0].UserID != 10 {
+		t.Errorf("Recommendations[0].UserID = %d, want 10", resp.Recommendations[0].UserID)
+	}
+}
+
+func TestGetFollowingBasedRecommendations_CompleteWithinBudget(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if resp.Degraded {
+		t.Errorf("Degraded = true, want false")
+	}
+	if len(resp.Recommendations) != 3 {
+		t.Errorf("len(Recommendations) = %d, want 3", len(resp.Recommendations))
+	}
+}
+
+func TestGetFollowingBasedRecommendations_BatchPosts(t *testing.T) {
+	content := &batchContentClient{}
+	s := newTestRecommendationService(content)
+
+	resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if got := content.singleCalls.Load(); got != 0 {
+		t.Errorf("GetRecentPosts called %d times, want 0 (one batch call for the page)", got)
+	}
+	for _, rec := range resp.Recommendations {
+		if len(rec.RecentPosts) != 1 || rec.RecentPosts[0].PostID != rec.UserID*100 {
+			t.Errorf("recommendation %d posts = %+v, want its own post", rec.UserID, rec.RecentPosts)
+		}
+	}
+}
+
+// batchReasonClient 测试用配置服务：只为人数大于 1 的推荐理由配置了文案，统计单个、批量接口的调用次数
+type batchReasonClient struct {
+	singleCalls atomic.Int32
+	batchCalls  atomic.Int32
+}
+
+func (c *batchReasonClient) GetReasonText(ctx context.Context, reasonType string, count int, locale string, variant string) (string, error) {
+	c.singleCalls.Add(1)
+	return "", nil
+}
+
+func (c *batchReasonClient) GetReasonTexts(ctx context.Context, requests []ReasonTextRequest) (map[ReasonTextRequest]string, error) {
+	c.batchCalls.Add(1)
+	result := make(map[ReasonTextRequest]string)
+	for _, request := range requests {
+		if request.Count > 1 {
+			result[request] = "configured"
+		}
+	}
+	return result, nil
+}
+
+func TestGetFollowingBasedRecommendations_BatchReasonTexts(t *testing.T) {
+	reasons := &batchReasonClient{}
+	generator := domainService.NewRecommendationGenerator(stubSocialGraphRepo{}, stubContentRepo{})
+	s := NewRecommendationService(generator, stubSocialGraphRepo{}, stubContentRepo{}, &batchContentClient{}, stubUserRPCClient{}, reasons)
+
+	resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if got := reasons.batchCalls.Load(); got != 1 {
+		t.Errorf("GetReasonTexts called %d times, want 1", got)
+	}
+	if got := reasons.singleCalls.Load(); got != 0 {
+		t.Errorf("GetReasonText called %d times, want 0 (unconfigured texts fall back to the local description)", got)
+	}
+	for _, rec := range resp.Recommendations {
+		// 10 被两个人关注，其他推荐对象只被一个人关注
+		if configured := rec.Reason == "configured"; configured != (rec.UserID == 10) {
+			t.Errorf("recommendation %d reason = %q", rec.UserID, rec.Reason)
+		}
+	}
+}
+
+func TestGetFollowingBasedRecommendations_NotModified(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	ctx := context.Background()
+
+	first, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Version == "" || first.NotModified {
+		t.Fatalf("first response: version=%q not_modified=%v", first.Version, first.NotModified)
+	}
+
+	// 带上版本再次请求：列表没有变化
+	second, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 10, KnownVersion: first.Version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.NotModified || len(second.Recommendations) != 0 || second.Version != first.Version {
+		t.Errorf("second response: not_modified=%v len=%d version=%q", second.NotModified, len(second.Recommendations), second.Version)
+	}
+
+	// 请求的数量变了，列表也就变了
+	third, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 2, KnownVersion: first.Version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.NotModified || len(third.Recommendations) != 2 {
+		t.Errorf("third response: not_modified=%v len=%d", third.NotModified, len(third.Recommendations))
+	}
+}
+
+// countingSocialGraphRepo 测试用社交图谱：记录召回时查询关注列表的次数
+type countingSocialGraphRepo struct {
+	stubSocialGraphRepo
+	calls *int
+}
+
+func (r countingSocialGraphRepo) GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	*r.calls++
+	return r.stubSocialGraphRepo.GetFollowings(ctx, userID)
+}
+
+func TestGetFollowingBasedRecommendations_NotModifiedBeforeRanking(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSnapshotStore(memorySnapshotStore{})(s)
+	WithPrecomputedServing(time.Minute)(s)
+	ctx := context.Background()
+
+	// 第一次生成并保存快照
+	first, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	s.generator = domainService.NewRecommendationGenerator(countingSocialGraphRepo{calls: &calls}, stubContentRepo{})
+
+	// 客户端的版本就是可以直接使用的快照：不召回排序
+	resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 2, KnownVersion: first.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.NotModified || resp.Version != first.Version || calls != 0 {
+		t.Fatalf("not_modified=%v version=%q ranking calls=%d, want not modified without ranking", resp.NotModified, resp.Version, calls)
+	}
+
+	// 高优先级请求不使用快照：召回排序之后再比较版本
+	resp, err = s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 2, KnownVersion: first.Version, Freshness: dto.FreshnessRealtime,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.NotModified || calls == 0 {
+		t.Fatalf("realtime: not_modified=%v ranking calls=%d, want ranked and not modified", resp.NotModified, calls)
+	}
+}
+
+// memorySnapshotStore 测试用快照存储
+type memorySnapshotStore map[string]*RecommendationSnapshot
+
+func (m memorySnapshotStore) Save(ctx context.Context, key SnapshotKey, snapshot *RecommendationSnapshot) error {
+	m[snapshot.Version] = snapshot
+	return nil
+}
+
+func (m memorySnapshotStore) FindByVersion(ctx context.Context, key SnapshotKey, version string) (*RecommendationSnapshot, error) {
+	return m[version], nil
+}
+
+func (m memorySnapshotStore) FindLatestBefore(ctx context.Context, key SnapshotKey, at time.Time) (*RecommendationSnapshot, error) {
+	var latest *RecommendationSnapshot
+	for _, snapshot := range m {
+		if !snapshot.CreatedAt.After(at) && (latest == nil || snapshot.CreatedAt.After(latest.CreatedAt)) {
+			latest = snapshot
+		}
+	}
+	return latest, nil
+}
+
+func TestGetFollowingBasedRecommendations_Delta(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSnapshotStore(memorySnapshotStore{})(s)
+	ctx := context.Background()
+
+	// 第一次同步：只有 1 个推荐
+	first, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 列表扩大到 3 个：只下发新增的 2 个
+	second, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 3, SinceVersion: first.Version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Delta || len(second.Recommendations) != 2 || len(second.Tombstones) != 0 {
+		t.Fatalf("second response: delta=%v len=%d tombstones=%d", second.Delta, len(second.Recommendations), len(second.Tombstones))
+	}
+
+	// 列表缩小到 1 个：没有新增，下发 2 个墓碑
+	third, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 1, SinceVersion: second.Version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !third.Delta || len(third.Recommendations) != 0 || len(third.Tombstones) != 2 {
+		t.Fatalf("third response: delta=%v len=%d tombstones=%d", third.Delta, len(third.Recommendations), len(third.Tombstones))
+	}
+	for _, tombstone := range third.Tombstones {
+		if tombstone.Reason != dto.TombstoneRemoved {
+			t.Errorf("tombstone %d reason = %q, want %q", tombstone.UserID, tombstone.Reason, dto.TombstoneRemoved)
+		}
+	}
+
+	// 找不到基准快照：返回完整列表
+	fourth, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1, Limit: 3, SinceVersion: "v1-unknown",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fourth.Delta || len(fourth.Recommendations) != 3 {
+		t.Errorf("fourth response: delta=%v len=%d", fourth.Delta, len(fourth.Recommendations))
+	}
+}
+
+func TestGetFollowingBasedRecommendations_Freshness(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	ctx := context.Background()
+	req := func(freshness dto.Freshness) *dto.RecommendationRequest {
+		return &dto.RecommendationRequest{UserID: 1, Limit: 2, Freshness: freshness}
+	}
+
+	// 还没有快照：只接受快照的请求失败
+	WithSnapshotStore(memorySnapshotStore{})(s)
+	if _, err := s.GetFollowingBasedRecommendations(ctx, req(dto.FreshnessCachedOnly)); !errors.Is(err, ErrNoPrecomputedRecommendations) {
+		t.Fatalf("cached only without snapshot: err = %v", err)
+	}
+
+	// 第一次生成并保存快照
+	WithPrecomputedServing(time.Minute)(s)
+	first, err := s.GetFollowingBasedRecommendations(ctx, req(dto.FreshnessDefault))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Precomputed {
+		t.Fatal("first response should be generated")
+	}
+
+	// 快照在有效期内：直接使用，版本和快照一致
+	for _, freshness := range []dto.Freshness{dto.FreshnessDefault, dto.FreshnessCachedOnly} {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, req(freshness))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.Precomputed || resp.Version != first.Version || len(resp.Recommendations) != 2 {
+			t.Errorf("%q: precomputed=%v version=%q len=%d", freshness, resp.Precomputed, resp.Version, len(resp.Recommendations))
+		}
+	}
+
+	// 高优先级：总是重新生成
+	realtime, err := s.GetFollowingBasedRecommendations(ctx, req(dto.FreshnessRealtime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if realtime.Precomputed {
+		t.Error("realtime response should not be precomputed")
+	}
+}
+
+func TestGetFollowingBasedRecommendations_ScoreDetails(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSnapshotStore(memorySnapshotStore{})(s)
+	WithPrecomputedServing(time.Minute)(s)
+	ctx := context.Background()
+
+	// 普通请求：没有分数明细（第一次生成并保存快照）
+	plain, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range plain.Recommendations {
+		if rec.ScoreDetails != nil {
+			t.Errorf("recommendation %d: unexpected score details", rec.UserID)
+		}
+	}
+
+	// 调试请求：不使用快照，各因素的贡献之和等于分数
+	debug, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, Debug: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if debug.Precomputed {
+		t.Error("debug response should not be precomputed")
+	}
+	if len(debug.Recommendations) == 0 {
+		t.Fatal("debug response has no recommendations")
+	}
+	for _, rec := range debug.Recommendations {
+		details := rec.ScoreDetails
+		if details == nil {
+			t.Fatalf("recommendation %d: missing score details", rec.UserID)
+		}
+		sum := 0
+		for _, factor := range details.Factors {
+			sum += factor.Contribution
+		}
+		if sum != rec.Score {
+			t.Errorf("recommendation %d: factors sum to %d, want score %d", rec.UserID, sum, rec.Score)
+		}
+		if details.RulesVersion != aggregate.ScoringRulesVersion || details.PerRelatedUser != 10 {
+			t.Errorf("recommendation %d: details = %+v", rec.UserID, details)
+		}
+	}
+}
+
+// memoryListRepo 测试用推荐列表仓储：只保留最新的一份
+type memoryListRepo struct {
+	latest *aggregate.RecommendationList
+	saves  int
+}
+
+func (r *memoryListRepo) SaveList(ctx context.Context, list *aggregate.RecommendationList) error {
+	r.latest = list
+	r.saves++
+	return nil
+}
+
+func (r *memoryListRepo) GetLatestListForUser(ctx context.Context, userID valueobject.UserID) (*aggregate.RecommendationList, error) {
+	if r.latest == nil {
+		return nil, nil
+	}
+	return aggregate.RestoreRecommendationList(userID, r.latest.All(), r.latest.GeneratedAt(), r.latest.FollowingSampling()), nil
+}
+
+func (r *memoryListRepo) RemoveTarget(ctx context.Context, userID, targetUserID valueobject.UserID) error {
+	if r.latest == nil {
+		return nil
+	}
+	var kept []*aggregate.UserRecommendation
+	for _, rec := range r.latest.All() {
+		if !rec.TargetUserID().Equals(targetUserID) {
+			kept = append(kept, rec)
+		}
+	}
+	r.latest = aggregate.RestoreRecommendationList(userID, kept, r.latest.GeneratedAt(), r.latest.FollowingSampling())
+	return nil
+}
+
+func (r *memoryListRepo) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryListRepo) ListUserLists(ctx context.Context, userID valueobject.UserID) ([]*aggregate.RecommendationList, error) {
+	if r.latest == nil {
+		return nil, nil
+	}
+	return []*aggregate.RecommendationList{r.latest}, nil
+}
+
+func (r *memoryListRepo) DeleteUserLists(ctx context.Context, userID valueobject.UserID) error {
+	r.latest = nil
+	return nil
+}
+
+func (r *memoryListRepo) OldestListTime(ctx context.Context) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+func TestRankCandidates_PersistedList(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	repo := &memoryListRepo{}
+	WithRecommendationRepository(repo, time.Minute)(s)
+	ctx := context.Background()
+	viewerID := userIDs(1)[0]
+	rank := func(freshness dto.Freshness) []*aggregate.UserRecommendation {
+		t.Helper()
+		ranked, _, err := s.rankCandidates(ctx, viewerID, dto.SurfaceHomeFeed, 0, freshness, aggregate.RecommendationCriteria{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ranked
+	}
+	persisted := func(generatedAt time.Time) {
+		reason := valueobject.RestoreRecommendationReason(valueobject.ReasonFollowedByFollowing, 1)
+		rec := aggregate.RestoreUserRecommendation(userIDs(99)[0], reason, 10, 0, generatedAt, generatedAt.Add(time.Hour))
+		repo.latest = aggregate.RestoreRecommendationList(viewerID, []*aggregate.UserRecommendation{rec}, generatedAt, aggregate.FollowingSampling{})
+	}
+
+	// 没有保存过：生成并保存
+	if got := rank(dto.FreshnessDefault); len(got) != 3 || repo.saves != 1 {
+		t.Fatalf("first: got %d recommendations, %d saves; want 3, 1", len(got), repo.saves)
+	}
+
+	// 没有超过 maxAge：直接使用保存的列表
+	persisted(time.Now())
+	if got := rank(dto.FreshnessDefault); len(got) != 1 || got[0].TargetUserID().Value() != 99 {
+		t.Fatalf("persisted: got %d recommendations, want only user 99", len(got))
+	}
+
+	// 高优先级、超过 maxAge：重新生成
+	if got := rank(dto.FreshnessRealtime); len(got) != 3 {
+		t.Errorf("realtime: got %d recommendations, want 3", len(got))
+	}
+	persisted(time.Now().Add(-2 * time.Minute))
+	if got := rank(dto.FreshnessDefault); len(got) != 3 || repo.saves != 3 {
+		t.Errorf("stale: got %d recommendations, %d saves; want 3, 3", len(got), repo.saves)
+	}
+}
+
+// memoryListCache 测试用推荐列表缓存
+type memoryListCache map[ListCacheKey]*aggregate.RecommendationList
+
+func (c memoryListCache) Get(ctx context.Context, key ListCacheKey) (*aggregate.RecommendationList, error) {
+	list, ok := c[key]
+	if !ok {
+		return nil, nil
+	}
+	return aggregate.RestoreRecommendationList(list.ForUserID(), list.All(), list.GeneratedAt(), list.FollowingSampling()), nil
+}
+
+func (c memoryListCache) Set(ctx context.Context, key ListCacheKey, list *aggregate.RecommendationList) error {
+	c[key] = list
+	return nil
+}
+
+func (c memoryListCache) InvalidateUser(ctx context.Context, userID int64) error {
+	for key := range c {
+		if key.UserID == userID {
+			delete(c, key)
+		}
+	}
+	return nil
+}
+
+func TestRankCandidates_ListCache(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	cache := memoryListCache{}
+	WithRecommendationListCache(cache)(s)
+	ctx := context.Background()
+	viewerID := userIDs(1)[0]
+	rank := func(freshness dto.Freshness) []*aggregate.UserRecommendation {
+		t.Helper()
+		ranked, _, err := s.rankCandidates(ctx, viewerID, dto.SurfaceHomeFeed, 0, freshness, aggregate.RecommendationCriteria{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ranked
+	}
+
+	// 未命中：生成并按默认策略、默认时间窗口缓存
+	if got := rank(dto.FreshnessDefault); len(got) != 3 {
+		t.Fatalf("miss: got %d recommendations, want 3", len(got))
+	}
+	key := ListCacheKey{Tenant: valueobject.TenantFromContext(ctx), UserID: 1, Strategy: DefaultListStrategy, Days: defaultRecencyDays}
+	if cache[key] == nil {
+		t.Fatalf("list not cached under %+v", key)
+	}
+
+	// 命中：直接使用缓存的列表
+	reason := valueobject.RestoreRecommendationReason(valueobject.ReasonFollowedByFollowing, 1)
+	rec := aggregate.RestoreUserRecommendation(userIDs(99)[0], reason, 10, 0, time.Now(), time.Now().Add(time.Hour))
+	cache[key] = aggregate.RestoreRecommendationList(viewerID, []*aggregate.UserRecommendation{rec}, time.Now(), aggregate.FollowingSampling{})
+	if got := rank(dto.FreshnessDefault); len(got) != 1 || got[0].TargetUserID().Value() != 99 {
+		t.Fatalf("hit: got %d recommendations, want only user 99", len(got))
+	}
+
+	// 删除缓存之后重新生成
+	if err := s.InvalidateRecommendations(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := rank(dto.FreshnessDefault); len(got) != 3 {
+		t.Errorf("invalidated: got %d recommendations, want 3", len(got))
+	}
+
+	// 高优先级不读缓存
+	cache[key] = aggregate.RestoreRecommendationList(viewerID, []*aggregate.UserRecommendation{rec}, time.Now(), aggregate.FollowingSampling{})
+	if got := rank(dto.FreshnessRealtime); len(got) != 3 {
+		t.Errorf("realtime: got %d recommendations, want 3", len(got))
+	}
+}
+
+func TestGetFollowingBasedRecommendations_Pagination(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithRecommendationListCache(memoryListCache{})(s)
+	ctx := context.Background()
+
+	// 3 个推荐，每页 2 个：两页不重复，第二页没有更多
+	seen := make(map[int64]bool)
+	req := &dto.RecommendationRequest{UserID: 1, PageSize: 2}
+	var cursors []string
+	for page := 0; ; page++ {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, req)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, rec := range resp.Recommendations {
+			if seen[rec.UserID] {
+				t.Fatalf("page %d: duplicate recommendation %d", page, rec.UserID)
+			}
+			seen[rec.UserID] = true
+		}
+		if resp.HasMore != (resp.NextCursor != "") {
+			t.Fatalf("page %d: has_more = %v, next_cursor = %q", page, resp.HasMore, resp.NextCursor)
+		}
+		if !resp.HasMore {
+			break
+		}
+		cursors = append(cursors, resp.NextCursor)
+		req = &dto.RecommendationRequest{UserID: 1, PageSize: 2, PageToken: resp.NextCursor}
+	}
+	if len(seen) != 3 || len(cursors) != 1 {
+		t.Fatalf("got %d recommendations in %d pages, want 3 in 2", len(seen), len(cursors)+1)
+	}
+
+	// 列表被删除（下一次会重新生成）之后，旧的分页位置过期
+	if err := s.InvalidateRecommendations(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, PageSize: 2, PageToken: cursors[0]})
+	if !errors.Is(err, ErrPageTokenExpired) || !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expired cursor: err = %v, want ErrPageTokenExpired", err)
+	}
+	_, err = s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, PageToken: "not-a-cursor"})
+	if !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("malformed cursor: err = %v, want ErrInvalidPageToken", err)
+	}
+}
+
+func TestGetRecommendationHistory_Pagination(t *testing.T) {
+	now := time.Now()
+	viewer := userIDs(1)[0]
+	// 两次请求各展示了 3 个推荐（同一批曝光时间相同），还有一条超出时间范围
+	var impressions []*entity.Impression
+	for batch, shownAt := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now.Add(-48 * time.Hour)} {
+		for _, target := range userIDs(10, 11, 12) {
+			if batch == 2 && target.Value() != 10 {
+				continue
+			}
+			impressions = append(impressions, entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonFollowedByFollowing, "home_feed", shownAt))
+		}
+	}
+
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithImpressionRepository(&fakeImpressionRepo{listed: impressions})(s)
+	ctx := context.Background()
+
+	seen := make(map[string]bool)
+	var shownAt []string
+	req := &dto.RecommendationHistoryRequest{UserID: 1, From: now.Add(-24 * time.Hour), Limit: 2}
+	for page := 0; ; page++ {
+		resp, err := s.GetRecommendationHistory(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range resp.Items {
+			if seen[item.RecommendationID] {
+				t.Fatalf("page %d: duplicate item %s", page, item.RecommendationID)
+			}
+			seen[item.RecommendationID] = true
+			shownAt = append(shownAt, item.ShownAt)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	if len(seen) != 6 {
+		t.Fatalf("got %d items, want 6 (within range)", len(seen))
+	}
+	want := now.Add(-time.Hour).Format(time.RFC3339)
+	if shownAt[0] != want || shownAt[5] == want {
+		t.Errorf("items not ordered by shown_at desc: %v", shownAt)
+	}
+
+	if _, err := s.GetRecommendationHistory(ctx, &dto.RecommendationHistoryRequest{UserID: 1, PageToken: "not-a-token"}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("invalid token: err = %v", err)
+	}
+}
+
+func TestGetFollowingBasedRecommendations_SurfacePolicy(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSurfacePolicies(NewSurfacePolicies(map[dto.Surface]SurfacePolicy{
+		dto.SurfaceDefault:        {MaxLimit: 2},
+		dto.SurfacePush:           {MaxLimit: 10, MaxPerReasonType: 1, Expiry: time.Hour},
+		dto.SurfaceProfileSidebar: {PreviewPosts: 1, PreviewLength: 4},
+	}))(s)
+	ctx := context.Background()
+
+	// 没有单独配置的场景使用 default：最多 2 条
+	resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, Surface: dto.SurfaceHomeFeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 2 {
+		t.Errorf("home_feed: got %d recommendations, want 2", len(resp.Recommendations))
+	}
+
+	// push：3 个候选都是同一种理由，多样性规则只保留分数最高的一个
+	ranked, _, err := s.rankCandidates(ctx, userIDs(1)[0], dto.SurfacePush, 0, dto.FreshnessDefault, aggregate.RecommendationCriteria{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranked) != 1 || ranked[0].TargetUserID().Value() != 10 {
+		t.Fatalf("push: got %d recommendations, want only user 10", len(ranked))
+	}
+	if ttl := ranked[0].ExpiresAt().Sub(ranked[0].CreatedAt()); ttl != time.Hour {
+		t.Errorf("push: expiry = %v, want 1h", ttl)
+	}
+	// profile_sidebar：帖子预览截断到 4 个字符（含省略号）
+	resp, err = s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, Surface: dto.SurfaceProfileSidebar})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range resp.Recommendations {
+		if len(rec.RecentPosts) != 1 || rec.RecentPosts[0].Content != "hel…" {
+			t.Errorf("profile_sidebar: recommendation %d posts = %+v, want one truncated preview", rec.UserID, rec.RecentPosts)
+		}
+	}
+	if got := truncatePreview("你好，世界", 3); got != "你好…" {
+		t.Errorf("truncatePreview = %q, want 你好…", got)
+	}
+}
+
+// stubSectionLayoutClient 测试用分栏配置
+type stubSectionLayoutClient struct {
+	layout []SectionLayout
+	err    error
+}
+
+func (c stubSectionLayoutClient) GetSectionLayout(ctx context.Context, surface string, locale string) ([]SectionLayout, error) {
+	return c.layout, c.err
+}
+
+func TestGetRecommendationBundle_Sections(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSectionLayoutClient(stubSectionLayoutClient{layout: []SectionLayout{
+		{Key: "close_friends", Title: "Close friends", ReasonTypes: []string{"followed_by_following"}, Limit: 1},
+		{Key: "popular", Title: "Popular", ReasonTypes: []string{"popular_in_network"}, Limit: 5},
+		{Key: "more", Title: "More", ReasonTypes: []string{"followed_by_following"}, Limit: 5},
+	}})(s)
+
+	resp, err := s.GetRecommendationBundle(context.Background(), &dto.RecommendationBundleRequest{UserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 分数最高的 10 放进第一个分栏，其余放进下一个接受这种理由的分栏；没有推荐的分栏不返回
+	if len(resp.Sections) != 2 || resp.Sections[0].Key != "close_friends" || resp.Sections[1].Key != "more" {
+		t.Fatalf("sections = %+v, want [close_friends more]", resp.Sections)
+	}
+	if got := resp.Sections[0].Recommendations; len(got) != 1 || got[0].UserID != 10 {
+		t.Errorf("close_friends = %+v, want only user 10", got)
+	}
+	if got := len(resp.Sections[1].Recommendations); got != 2 {
+		t.Errorf("more: got %d recommendations, want 2", got)
+	}
+
+	// 配置服务失败：使用默认分栏
+	WithSectionLayoutClient(stubSectionLayoutClient{err: errors.New("config service unavailable")})(s)
+	resp, err = s.GetRecommendationBundle(context.Background(), &dto.RecommendationBundleRequest{UserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sections) != 1 || resp.Sections[0].Key != "people_you_may_know" || len(resp.Sections[0].Recommendations) != 3 {
+		t.Errorf("sections = %+v, want default people_you_may_know with 3 recommendations", resp.Sections)
+	}
+}
+
+// memoryFeatureSink 测试用特征快照去处
+type memoryFeatureSink struct {
+	records []FeatureSnapshot
+}
+
+func (s *memoryFeatureSink) Write(ctx context.Context, record []byte) error {
+	var snapshot FeatureSnapshot
+	if err := json.Unmarshal(record, &snapshot); err != nil {
+		return err
+	}
+	s.records = append(s.records, snapshot)
+	return nil
+}
+
+func TestGetFollowingBasedRecommendations_FeatureLog(t *testing.T) {
+	sink := &memoryFeatureSink{}
+	logger := NewFeatureLogger(sink, 1, 10)
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithFeatureLogger(logger)(s)
+
+	resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Close()
+
+	// 只返回了一条，但全部候选的特征都记录下来
+	if len(sink.records) != 3 {
+		t.Fatalf("len(records) = %d, want 3", len(sink.records))
+	}
+	first := sink.records[0]
+	if first.TargetUserID != resp.Recommendations[0].UserID || first.Score != resp.Recommendations[0].Score || first.Position != 0 {
+		t.Errorf("records[0] = %+v, want the returned recommendation at position 0", first)
+	}
+	if first.RecommendationID == "" || first.RelatedCount != 2 || len(first.SignalUserIDs) != 2 {
+		t.Errorf("records[0] features = %+v, want user 10 followed by 2 users", first)
+	}
+	if first.PolicyVersion == "" {
+		t.Errorf("records[0] = %+v, want policy version", first)
+	}
+}
+
+// staticCalibrationSource 测试用校准参数来源
+type staticCalibrationSource struct {
+	calibrations *ScoreCalibrations
+}
+
+func (s *staticCalibrationSource) ScoreCalibrations() *ScoreCalibrations {
+	return s.calibrations
+}
+
+func TestGetFollowingBasedRecommendations_ScoreCalibration(t *testing.T) {
+	calibration, err := valueobject.NewPiecewiseCalibration([]valueobject.CalibrationPoint{
+		{Raw: 0, Calibrated: 0}, {Raw: 100, Calibrated: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := &staticCalibrationSource{calibrations: &ScoreCalibrations{
+		Version:  "v1",
+		ByReason: map[string]valueobject.ScoreCalibration{"followed_by_following": calibration},
+	}}
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithScoreCalibration(source)(s)
+	ctx := context.Background()
+
+	first, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Metadata.CalibrationVersion != "v1" {
+		t.Errorf("CalibrationVersion = %q, want v1", first.Metadata.CalibrationVersion)
+	}
+	for _, rec := range first.Recommendations {
+		if rec.CalibratedScore == nil || *rec.CalibratedScore != float64(rec.Score)/100 {
+			t.Errorf("recommendation %d: CalibratedScore = %v, want %v", rec.UserID, rec.CalibratedScore, float64(rec.Score)/100)
+		}
+	}
+
+	// 参数更新后列表版本变化：客户端缓存的校准分数需要刷新
+	source.calibrations = &ScoreCalibrations{Version: "v2", Default: calibration}
+	second, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, KnownVersion: first.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NotModified || second.Version == first.Version || second.Metadata.CalibrationVersion != "v2" {
+		t.Errorf("after calibration update: not_modified = %v, version = %s (was %s), calibration = %s",
+			second.NotModified, second.Version, first.Version, second.Metadata.CalibrationVersion)
+	}
+}
+
+func TestGetFollowingBasedRecommendations_RepeatSuppression(t *testing.T) {
+	now := time.Now()
+	viewer := userIDs(1)[0]
+	// 10（分数最高）昨天展示过，11 在窗口之外展示过
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithImpressionRepository(&fakeImpressionRepo{listed: []*entity.Impression{
+		entity.NewImpression(valueobject.NewRecommendationID(), viewer, userIDs(10)[0], valueobject.ReasonFollowedByFollowing, "home_feed", now.Add(-24*time.Hour)),
+		entity.NewImpression(valueobject.NewRecommendationID(), viewer, userIDs(11)[0], valueobject.ReasonFollowedByFollowing, "home_feed", now.Add(-10*24*time.Hour)),
+	}})(s)
+	WithSurfacePolicies(NewSurfacePolicies(map[dto.Surface]SurfacePolicy{
+		dto.SurfaceHomeFeed: {RepeatWindow: 3 * 24 * time.Hour},
+	}))(s)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		surface dto.Surface
+		limit   int
+		want    []int64
+	}{
+		{dto.SurfaceHomeFeed, 2, []int64{11, 12}},     // 新面孔够一页：不出现重复
+		{dto.SurfaceHomeFeed, 3, []int64{11, 12, 10}}, // 不够一页：重复的排在最后补齐
+		{dto.SurfaceDiscovery, 2, []int64{10, 11}},    // 没有配置的场景不调整
+	} {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+			UserID: 1, Limit: tc.limit, Surface: tc.surface, Freshness: dto.FreshnessRealtime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for _, rec := range resp.Recommendations {
+			got = append(got, rec.UserID)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s limit %d: got %v, want %v", tc.surface, tc.limit, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s limit %d: got %v, want %v", tc.surface, tc.limit, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+// memorySessionStore 测试用会话存储
+type memorySessionStore struct {
+	returned map[SessionKey][]int64
+}
+
+func (s *memorySessionStore) Returned(ctx context.Context, key SessionKey) ([]int64, error) {
+	return s.returned[key], nil
+}
+
+func (s *memorySessionStore) Add(ctx context.Context, key SessionKey, targetUserIDs []int64) error {
+	s.returned[key] = append(s.returned[key], targetUserIDs...)
+	return nil
+}
+
+func TestGetFollowingBasedRecommendations_SessionDedup(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithSessionStore(&memorySessionStore{returned: make(map[SessionKey][]int64)})(s)
+	ctx := context.Background()
+
+	fetch := func(sessionID string) []int64 {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 2, SessionID: sessionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for _, rec := range resp.Recommendations {
+			got = append(got, rec.UserID)
+		}
+		return got
+	}
+
+	// 同一个会话：第二次只剩 12，第三次没有新的推荐
+	for i, want := range [][]int64{{10, 11}, {12}, nil} {
+		if got := fetch("s1"); len(got) != len(want) || (len(want) > 0 && got[0] != want[0]) {
+			t.Errorf("fetch %d in session s1: got %v, want %v", i+1, got, want)
+		}
+	}
+	// 新的会话、不带会话ID：从头开始
+	for _, sessionID := range []string{"s2", ""} {
+		if got := fetch(sessionID); len(got) != 2 || got[0] != 10 {
+			t.Errorf("session %q: got %v, want [10 11]", sessionID, got)
+		}
+	}
+}
+
+func TestExplainRecommendation(t *testing.T) {
+	calibration, err := valueobject.NewPiecewiseCalibration([]valueobject.CalibrationPoint{
+		{Raw: 0, Calibrated: 0}, {Raw: 100, Calibrated: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithScoreCalibration(&staticCalibrationSource{calibrations: &ScoreCalibrations{Version: "v1", Default: calibration}})(s)
+	ctx := context.Background()
+
+	// 10 被两个人关注：理由 2 × 10 + 帖子 1 × 2，排在第一
+	explanation, err := s.ExplainRecommendation(ctx, &dto.ExplainRecommendationRequest{ViewerID: 1, TargetUserID: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explanation.Found || explanation.Position != 0 || explanation.Candidates != 3 || explanation.Score != 22 {
+		t.Fatalf("explanation = %+v, want found at 0 of 3 with score 22", explanation)
+	}
+	contributions := make(map[string]int)
+	for _, factor := range explanation.Factors {
+		contributions[factor.Name] = factor.Contribution
+	}
+	if contributions["reason"] != 20 || contributions["recent_posts"] != 2 {
+		t.Errorf("contributions = %v, want reason 20, recent_posts 2", contributions)
+	}
+	if explanation.StrategyWeight != 1 || explanation.CalibrationVersion != "v1" ||
+		explanation.CalibratedScore == nil || *explanation.CalibratedScore != 0.22 {
+		t.Errorf("weight = %v, calibration = %s %v, want 1, v1 0.22",
+			explanation.StrategyWeight, explanation.CalibrationVersion, explanation.CalibratedScore)
+	}
+
+	// 没有推荐信号的人：没有找到，位置为 -1
+	explanation, err = s.ExplainRecommendation(ctx, &dto.ExplainRecommendationRequest{ViewerID: 1, TargetUserID: 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explanation.Found || explanation.Position != -1 || len(explanation.Factors) != 0 {
+		t.Errorf("unknown target: found = %v, position = %d, factors = %d", explanation.Found, explanation.Position, len(explanation.Factors))
+	}
+}
+
+// partialUserRPCClient 测试用用户服务：missing 中的用户没有资料
+type partialUserRPCClient struct {
+	missing int64
+}
+
+func (c partialUserRPCClient) GetUserInfo(ctx context.Context, userID int64) (*UserInfo, error) {
+	return &UserInfo{UserID: userID, Username: "user"}, nil
+}
+
+func (c partialUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*UserInfo, error) {
+	result := make([]*UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != c.missing {
+			result = append(result, &UserInfo{UserID: userID, Username: "user"})
+		}
+	}
+	return result, nil
+}
+
+func TestGetFollowingBasedRecommendations_ProfileMissPolicy(t *testing.T) {
+	// 排序为 10、11、12，取前 2 个；11 没有资料
+	tests := []struct {
+		policy      ProfileMissPolicy
+		want        []int64
+		wantPending int64
+	}{
+		{ProfileMissDrop, []int64{10}, 0},
+		{ProfileMissBackfill, []int64{10, 12}, 0},
+		{ProfileMissPlaceholder, []int64{10, 11}, 11},
+	}
+	for _, tt := range tests {
+		generator := domainService.NewRecommendationGenerator(stubSocialGraphRepo{}, stubContentRepo{})
+		s := NewRecommendationService(generator, stubSocialGraphRepo{}, stubContentRepo{},
+			slowContentClient{slowUserID: -1}, partialUserRPCClient{missing: 11}, nil,
+			WithProfileMissPolicy(tt.policy))
+
+		resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for _, rec := range resp.Recommendations {
+			got = append(got, rec.UserID)
+			if rec.ProfilePending != (rec.UserID == tt.wantPending) || (rec.Status() == dto.ItemStatusDegraded) != rec.ProfilePending {
+				t.Errorf("%s: recommendation %d ProfilePending = %v, status = %s", tt.policy, rec.UserID, rec.ProfilePending, rec.Status())
+			}
+		}
+		if len(got) != len(tt.want) || got[0] != tt.want[0] || (len(got) > 1 && got[1] != tt.want[1]) {
+			t.Errorf("%s: got %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+// recencySocialGraphRepo 测试用社交图谱：最近关注的人只出现在 14 天及以上的窗口中
+type recencySocialGraphRepo struct {
+	stubSocialGraphRepo
+}
+
+func (r recencySocialGraphRepo) GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error) {
+	if days < 14 {
+		return nil, nil
+	}
+	return r.stubSocialGraphRepo.GetRecentFollowings(ctx, userID, days)
+}
+
+func (r recencySocialGraphRepo) GetRecentFollowingsBatch(ctx context.Context, ids []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error) {
+	if days < 14 {
+		return nil, nil
+	}
+	return r.stubSocialGraphRepo.GetRecentFollowingsBatch(ctx, ids, days)
+}
+
+func TestGetFollowingBasedRecommendations_RecencyDays(t *testing.T) {
+	generator := domainService.NewRecommendationGenerator(recencySocialGraphRepo{}, stubContentRepo{})
+	s := NewRecommendationService(generator, recencySocialGraphRepo{}, stubContentRepo{},
+		slowContentClient{slowUserID: -1}, stubUserRPCClient{}, nil,
+		WithSurfacePolicies(NewSurfacePolicies(map[dto.Surface]SurfacePolicy{
+			dto.SurfaceOnboarding: {RecencyDays: 14},
+		})))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		surface dto.Surface
+		days    int
+		want    int
+	}{
+		{"default window", dto.SurfaceDefault, 0, 0},
+		{"request window", dto.SurfaceDefault, 14, 3},
+		{"surface window", dto.SurfaceOnboarding, 0, 3},
+	}
+	for _, tt := range tests {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, Surface: tt.surface, Days: tt.days})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(resp.Recommendations) != tt.want {
+			t.Errorf("%s: got %d recommendations, want %d", tt.name, len(resp.Recommendations), tt.want)
+		}
+	}
+
+	for _, days := range []int{-1, MaxRecencyDays + 1} {
+		_, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Days: days})
+		if !errors.Is(err, ErrInvalidRecencyDays) {
+			t.Errorf("days %d: got %v, want ErrInvalidRecencyDays", days, err)
+		}
+	}
+}
+
+func TestGetFollowingBasedRecommendations_Filter(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	ctx := context.Background()
+
+	// 候选 10（分数 22）、11、12（分数较低），都是"你关注的人关注了TA"
+	tests := []struct {
+		name   string
+		filter *dto.RecommendationFilter
+		want   []int64
+	}{
+		{"min score", &dto.RecommendationFilter{MinScore: 20}, []int64{10}},
+		{"exclude users", &dto.RecommendationFilter{ExcludeUserIDs: []int64{10, 12}}, []int64{11}},
+		{"reason types", &dto.RecommendationFilter{ReasonTypes: []string{"missed_posts"}}, nil},
+	}
+	for _, tt := range tests {
+		resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 10, Filter: tt.filter})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []int64
+		for _, rec := range resp.Recommendations {
+			got = append(got, rec.UserID)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	_, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1,
+		Filter: &dto.RecommendationFilter{ReasonTypes: []string{"no_such_reason"}},
+	})
+	if !errors.Is(err, ErrUnknownReasonType) {
+		t.Errorf("got %v, want ErrUnknownReasonType", err)
+	}
+}
+
+// stubPopularUsersRepo 测试用冷启动名单
+type stubPopularUsersRepo struct {
+	popular, curated []int64
+}
+
+func (r stubPopularUsersRepo) GetPopularUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	return userIDs(r.popular...), nil
+}
+
+func (r stubPopularUsersRepo) GetCuratedUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	return userIDs(r.curated...), nil
+}
+
+func TestGetFollowingBasedRecommendations_Backfill(t *testing.T) {
+	s := newTestRecommendationService(slowContentClient{slowUserID: -1})
+	WithBackfill(stubPopularUsersRepo{curated: []int64{3, 20}, popular: []int64{10, 1, 21, 22, 23}})(s)
+	ctx := context.Background()
+
+	// 个性化推荐只有 10、11、12：精选在前、热门在后补齐 6 个；已经关注的 3、已经在这一页的 10、自己不补
+	resp, err := s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{UserID: 1, Limit: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for _, rec := range resp.Recommendations {
+		got = append(got, rec.UserID)
+		if backfilled := rec.UserID >= 20; backfilled != (rec.ReasonType == "backfill") {
+			t.Errorf("user %d: reason type = %q", rec.UserID, rec.ReasonType)
+		}
+	}
+	if want := []int64{10, 11, 12, 20, 21, 22}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// 只要某几种理由的请求不补位
+	resp, err = s.GetFollowingBasedRecommendations(ctx, &dto.RecommendationRequest{
+		UserID: 1,
+		Limit:  6,
+		Filter: &dto.RecommendationFilter{ReasonTypes: []string{"followed_by_following"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 3 {
+		t.Errorf("with reason filter: got %d recommendations, want 3", len(resp.Recommendations))
+	}
+}
+
+// recordingUserRPCClient 测试用 user 服务：记录请求了哪些用户的资料
+type recordingUserRPCClient struct {
+	stubUserRPCClient
+	requested []int64
+}
+
+func (c *recordingUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*UserInfo, error) {
+	c.requested = append(c.requested, userIDs...)
+	return c.stubUserRPCClient.GetUserInfoBatch(ctx, userIDs)
+}
+
+// recordingContentClient 测试用内容服务：记录请求了哪些用户的帖子
+type recordingContentClient struct {
+	batchContentClient
+	requested []int64
+}
+
+func (c *recordingContentClient) GetRecentPostsForUsers(ctx context.Context, userIDs []int64, limit int) (map[int64][]*PostInfo, error) {
+	c.requested = append(c.requested, userIDs...)
+	return c.batchContentClient.GetRecentPostsForUsers(ctx, userIDs, limit)
+}
+
+func TestGetFollowingBasedRecommendations_HydratesOnlyThePage(t *testing.T) {
+	content := &recordingContentClient{}
+	users := &recordingUserRPCClient{}
+	s := newTestRecommendationService(content)
+	s.userRPCClient = users
+
+	resp, err := s.GetFollowingBasedRecommendations(context.Background(), &dto.RecommendationRequest{UserID: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 召回排序了 3 个候选，只为返回的那一个补全资料和帖子
+	if len(resp.Recommendations) != 1 || resp.Recommendations[0].UserID != 10 {
+		t.Fatalf("recommendations = %+v, want only the top-ranked user 10", resp.Recommendations)
+	}
+	if !slices.Equal(users.requested, []int64{10}) {
+		t.Errorf("user info requested for %v, want [10]", users.requested)
+	}
+	if !slices.Equal(content.requested, []int64{10}) {
+		t.Errorf("posts requested for %v, want [10]", content.requested)
+	}
+}
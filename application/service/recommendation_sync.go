@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// RecommendationSnapshot 一次完整推荐列表的快照（增量同步的基准）
+//
+// 只记录阶段一的结果（推荐对象、分数、理由），和 recommendationVersion 的输入一致：
+// 同一个版本号一定对应同一份快照。
+type RecommendationSnapshot struct {
+	Version   string          `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Entries   []SnapshotEntry `json:"entries"`
+	// CalibrationVersion 下发这份列表时的分数校准参数版本（参数变化后不能作为增量同步的基准）
+	CalibrationVersion string `json:"calibration_version,omitempty"`
+}
+
+// SnapshotEntry 快照中的一条推荐
+type SnapshotEntry struct {
+	UserID       int64     `json:"user_id"`
+	Score        int       `json:"score"`
+	ReasonType   int       `json:"reason_type"`
+	RelatedCount int       `json:"related_count"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// SnapshotKey 快照属于谁：租户 + 用户 + 展示场景
+type SnapshotKey struct {
+	Tenant  valueobject.TenantID
+	UserID  int64
+	Surface dto.Surface
+}
+
+// RecommendationSnapshotStore 推荐列表快照存储
+//
+// 每个 SnapshotKey 只保留最近几份快照，并且有过期时间：
+// 找不到基准快照时，服务返回完整列表让客户端整体替换，不影响正确性。
+type RecommendationSnapshotStore interface {
+	// Save 保存一份快照（同一版本重复保存时只更新时间）
+	Save(ctx context.Context, key SnapshotKey, snapshot *RecommendationSnapshot) error
+	// FindByVersion 按版本查找快照，找不到时返回 nil, nil
+	FindByVersion(ctx context.Context, key SnapshotKey, version string) (*RecommendationSnapshot, error)
+	// FindLatestBefore 查找 at 之前（含）最近的一份快照，找不到时返回 nil, nil
+	FindLatestBefore(ctx context.Context, key SnapshotKey, at time.Time) (*RecommendationSnapshot, error)
+}
+
+// WithSnapshotStore 开启增量同步（since_version / since_timestamp）
+//
+// 注入后，每次完整返回的列表都会保存快照；
+// 请求带上次同步的版本或时间时，只返回新增、变化的推荐和被移除推荐的墓碑。
+func WithSnapshotStore(store RecommendationSnapshotStore) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.snapshotStore = store
+	}
+}
+
+// newRecommendationSnapshot 辅助函数：一页推荐 → 快照
+func newRecommendationSnapshot(version string, page []*aggregate.UserRecommendation, now time.Time) *RecommendationSnapshot {
+	entries := make([]SnapshotEntry, 0, len(page))
+	for _, rec := range page {
+		entries = append(entries, SnapshotEntry{
+			UserID:       rec.TargetUserID().Value(),
+			Score:        rec.Score(),
+			ReasonType:   int(rec.Reason().Type()),
+			RelatedCount: rec.Reason().RelatedCount(),
+			ExpiresAt:    rec.ExpiresAt(),
+		})
+	}
+	return &RecommendationSnapshot{Version: version, CreatedAt: now, Entries: entries}
+}
+
+// diffAgainstSnapshot 增量同步：当前这一页和基准快照比较
+//
+// 返回：
+// - changed: 新增或分数、理由变化的推荐（需要补全展示数据）
+// - tombstones: 基准中有、这一页没有的推荐（已经过期的标记为 expired，其余为 removed）
+//
+// 位置变化不单独下发：客户端合并后按分数排序，和完整列表的顺序一致。
+func diffAgainstSnapshot(
+	base *RecommendationSnapshot,
+	page []*aggregate.UserRecommendation,
+	now time.Time,
+) ([]*aggregate.UserRecommendation, []*dto.TombstoneDTO) {
+	previous := make(map[int64]SnapshotEntry, len(base.Entries))
+	for _, entry := range base.Entries {
+		previous[entry.UserID] = entry
+	}
+
+	changed := make([]*aggregate.UserRecommendation, 0, len(page))
+	for _, rec := range page {
+		userID := rec.TargetUserID().Value()
+		entry, ok := previous[userID]
+		delete(previous, userID)
+		if ok && entry.Score == rec.Score() &&
+			entry.ReasonType == int(rec.Reason().Type()) && entry.RelatedCount == rec.Reason().RelatedCount() {
+			continue
+		}
+		changed = append(changed, rec)
+	}
+
+	tombstones := make([]*dto.TombstoneDTO, 0, len(previous))
+	for _, entry := range base.Entries {
+		if _, removed := previous[entry.UserID]; !removed {
+			continue
+		}
+		reason := dto.TombstoneRemoved
+		if !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+			reason = dto.TombstoneExpired
+		}
+		tombstones = append(tombstones, &dto.TombstoneDTO{UserID: entry.UserID, Reason: reason})
+	}
+	return changed, tombstones
+}
+
+// findSyncBase 辅助方法：查找增量同步的基准快照
+//
+// 优先使用 SinceVersion，其次 SinceTimestamp；都没有或找不到时返回 nil（返回完整列表）。
+// 查询失败只记日志：增量同步是优化，不应该让请求失败。
+func (s *RecommendationService) findSyncBase(
+	ctx context.Context,
+	key SnapshotKey,
+	req *dto.RecommendationRequest,
+) *RecommendationSnapshot {
+	if s.snapshotStore == nil || !req.WantsDelta() {
+		return nil
+	}
+	var base *RecommendationSnapshot
+	var err error
+	if req.SinceVersion != "" {
+		base, err = s.snapshotStore.FindByVersion(ctx, key, req.SinceVersion)
+	} else {
+		base, err = s.snapshotStore.FindLatestBefore(ctx, key, req.SinceTimestamp)
+	}
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "find recommendation snapshot failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+		return nil
+	}
+	return base
+}
+
+// saveSnapshot 辅助方法：保存完整列表的快照（失败只记日志）
+func (s *RecommendationService) saveSnapshot(ctx context.Context, key SnapshotKey, snapshot *RecommendationSnapshot) {
+	if s.snapshotStore == nil {
+		return
+	}
+	if err := s.snapshotStore.Save(ctx, key, snapshot); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "save recommendation snapshot failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+)
+
+// recommendationVersionPrefix 版本号前缀（计算方式变化时修改，让所有客户端缓存失效）
+const recommendationVersionPrefix = "v1-"
+
+// recommendationVersion 推荐列表的版本（相当于 HTTP 的 ETag）
+//
+// 为什么需要？
+// 移动端频繁轮询推荐列表，绝大多数时候拿到的列表和上一次完全相同。
+// 客户端带上上次的版本（known_version），版本没变时只返回 not_modified，
+// 省掉阶段二（用户信息、帖子、文案等跨服务调用）和响应的传输。
+//
+// 版本只由阶段一（召回与排序）的结果决定：推荐对象、顺序、分数、理由，以及展示场景。
+// 展示数据（昵称、头像、帖子）变化不会改变版本，客户端在列表变化时才会刷新它们，
+// 这是"便宜地判断没有变化"的代价。
+//
+// 开启分数校准时，校准参数的版本也参与计算（校准分数是阶段一的结果在响应中的另一种形式）；
+// 没有开启时和原来的版本相同。
+func recommendationVersion(page []*aggregate.UserRecommendation, surface dto.Surface, calibrationVersion string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|", surface)
+	if calibrationVersion != "" {
+		fmt.Fprintf(h, "calibration=%s|", calibrationVersion)
+	}
+	for _, rec := range page {
+		fmt.Fprintf(h, "%d:%d:%d:%d;",
+			rec.TargetUserID().Value(), rec.Score(), rec.Reason().Type(), rec.Reason().RelatedCount())
+	}
+	return recommendationVersionPrefix + strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+// FieldReencryptor 一张表的加密字段：密钥轮换后换成当前密钥
+//
+// 由持久化层的仓储实现（只有可修改的表；只追加的表如审计日志不重新加密，旧密钥保留到数据过期），
+// 由重新加密任务（interface/job.ReencryptionJob）周期性调用。
+type FieldReencryptor interface {
+	// ReencryptFields 把未加密、或者不是用当前密钥加密的字段用当前密钥重新加密，返回更新的行数
+	ReencryptFields(ctx context.Context) (int64, error)
+}
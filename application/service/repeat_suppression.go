@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// demoteRepeats 把最近展示过的推荐对象排到没展示过的后面（各自保持原有顺序）
+//
+// 为什么是降权而不是直接过滤？
+// 候选不够一页时，重复展示总比返回一个很短（甚至空）的列表好：
+// 新面孔足够时，排在后面的重复推荐自然不会出现在这一页；不够时再用它们补齐。
+//
+// 返回：
+// - 调整后的推荐
+// - 被排到后面的推荐数
+func demoteRepeats(
+	ranked []*aggregate.UserRecommendation,
+	shownRecently map[valueobject.UserID]bool,
+) ([]*aggregate.UserRecommendation, int) {
+	fresh := make([]*aggregate.UserRecommendation, 0, len(ranked))
+	var repeats []*aggregate.UserRecommendation
+	for _, rec := range ranked {
+		if shownRecently[rec.TargetUserID()] {
+			repeats = append(repeats, rec)
+			continue
+		}
+		fresh = append(fresh, rec)
+	}
+	return append(fresh, repeats...), len(repeats)
+}
+
+// applyRepeatSuppression 辅助方法：按展示场景的 RepeatWindow 降权最近展示过的推荐对象
+//
+// 为什么需要？
+// 候选变化很慢时，每次生成的排序几乎一样，用户每天打开都是同样几个人。
+// 曝光记录里有"最近给他看过谁"，把这些人排到后面，新面孔就有机会出现。
+//
+// 最近展示过的范围跨所有展示场景：在信息流里看过的人，在发现页再出现也是重复。
+//
+// 容错设计：
+// - 场景没有配置 RepeatWindow、没有曝光记录：原样返回
+// - 曝光记录查询失败：不降权（不因为这个功能影响推荐可用性）
+func (s *RecommendationService) applyRepeatSuppression(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	surface dto.Surface,
+	ranked []*aggregate.UserRecommendation,
+) []*aggregate.UserRecommendation {
+	window := s.surfaces.For(surface).RepeatWindow
+	if window <= 0 || s.impressionRepo == nil || len(ranked) == 0 {
+		return ranked
+	}
+
+	shownTargets, err := s.impressionRepo.GetImpressedTargets(ctx, viewerID, time.Now().Add(-window))
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "repeat suppression skipped: impression lookup failed", map[string]any{
+			FieldUserID: viewerID.Value(),
+			FieldError:  err.Error(),
+		})
+		return ranked
+	}
+	if len(shownTargets) == 0 {
+		return ranked
+	}
+
+	shownRecently := make(map[valueobject.UserID]bool, len(shownTargets))
+	for _, target := range shownTargets {
+		shownRecently[target] = true
+	}
+	ranked, demoted := demoteRepeats(ranked, shownRecently)
+	if demoted > 0 {
+		s.metrics.IncCounter(MetricRepeatsDemoted, demoted, map[string]string{
+			"surface": string(surface),
+		})
+	}
+	return ranked
+}
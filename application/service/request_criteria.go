@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ErrUnknownReasonType 过滤条件中的理由类型不存在
+var ErrUnknownReasonType = errors.New("unknown reason type")
+
+// requestCriteria 辅助函数：请求的过滤条件 → 领域的过滤规格
+//
+// 数量、取值范围由接口层验证；这里只负责把理由类型的外部标识（见 reasonTypeKey）转换回领域类型。
+func requestCriteria(filter *dto.RecommendationFilter) (aggregate.RecommendationCriteria, error) {
+	if filter.IsEmpty() {
+		return aggregate.RecommendationCriteria{}, nil
+	}
+	criteria := aggregate.RecommendationCriteria{MinScore: filter.MinScore}
+	for _, key := range filter.ReasonTypes {
+		reasonType, ok := parseReasonTypeKey(key)
+		if !ok {
+			return aggregate.RecommendationCriteria{}, fmt.Errorf("%w: %q", ErrUnknownReasonType, key)
+		}
+		criteria.ReasonTypes = append(criteria.ReasonTypes, reasonType)
+	}
+	if len(filter.ExcludeUserIDs) > 0 {
+		criteria.ExcludeUserIDs = make(map[valueobject.UserID]bool, len(filter.ExcludeUserIDs))
+		for _, id := range filter.ExcludeUserIDs {
+			userID, err := valueobject.NewUserID(id)
+			if err != nil {
+				return aggregate.RecommendationCriteria{}, err
+			}
+			criteria.ExcludeUserIDs[userID] = true
+		}
+	}
+	return criteria, nil
+}
+
+// parseReasonTypeKey 辅助函数：理由类型的外部标识 → 理由类型（reasonTypeKey 的逆过程）
+func parseReasonTypeKey(key string) (valueobject.ReasonType, bool) {
+	for reasonType := valueobject.ReasonFollowedByFollowing; reasonType <= valueobject.ReasonBackfill; reasonType++ {
+		if reasonTypeKey(reasonType) == key {
+			return reasonType, true
+		}
+	}
+	return 0, false
+}
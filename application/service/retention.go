@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+)
+
+// DataClass 数据类别（数据保留期按类别配置）
+type DataClass string
+
+const (
+	DataClassImpressions     DataClass = "impressions"     // 曝光记录
+	DataClassFeedback        DataClass = "feedback"        // 用户对推荐的反馈
+	DataClassRecommendations DataClass = "recommendations" // 生成过的推荐结果
+)
+
+// RetentionPolicy 一类数据的保留期
+type RetentionPolicy struct {
+	Class  DataClass
+	MaxAge time.Duration
+}
+
+// DefaultRetentionPolicies 默认保留期
+//
+// - 曝光记录：90 天（曝光配额、效果分析只需要最近的数据）
+// - 反馈：1 年
+// - 推荐结果：30 天
+func DefaultRetentionPolicies() []RetentionPolicy {
+	return []RetentionPolicy{
+		{Class: DataClassImpressions, MaxAge: 90 * 24 * time.Hour},
+		{Class: DataClassFeedback, MaxAge: 365 * 24 * time.Hour},
+		{Class: DataClassRecommendations, MaxAge: 30 * 24 * time.Hour},
+	}
+}
+
+// RetentionStore 一类数据的存储：按保留期删除、报告最早的数据
+//
+// 每类数据由对应的仓储适配（如 NewImpressionRetentionStore）。
+type RetentionStore interface {
+	// PurgeBefore 删除 cutoff 之前的数据，返回删除的条数
+	PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// OldestRecordTime 最早一条数据的时间（没有数据时 ok 为 false）
+	OldestRecordTime(ctx context.Context) (oldest time.Time, ok bool, err error)
+}
+
+// RetentionEnforcer 应用服务：执行数据保留期
+//
+// 由定时清理任务（interface/job.RetentionJob）周期性调用。
+//
+// 每类数据：
+// 1. 删除超过保留期的数据
+// 2. 检查删除后最早的数据是否仍然超过保留期（删除不完整、存储异常）
+// 3. 上报删除条数和超限情况，生成报告
+//
+// 配置了保留期、但没有注册存储的数据类别（本服务目前还不保存这类数据）
+// 在报告中标记为 Unmanaged，提醒新增存储时同时注册保留期。
+//
+// 监控：
+// - MetricRetentionPurgedRows：删除的条数（标签：class）
+// - MetricRetentionExceeded：执行后仍超过保留期的数据类别数（标签：class）
+type RetentionEnforcer struct {
+	policies []RetentionPolicy
+	stores   map[DataClass]RetentionStore
+	metrics  Metrics
+}
+
+// NewRetentionEnforcer 构造函数
+//
+// 参数：
+// - policies: 每类数据的保留期（通常来自配置，见 DefaultRetentionPolicies）
+// - stores: 每类数据对应的存储
+// - metrics: 监控指标（可以为 nil）
+func NewRetentionEnforcer(
+	policies []RetentionPolicy,
+	stores map[DataClass]RetentionStore,
+	metrics Metrics,
+) *RetentionEnforcer {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	return &RetentionEnforcer{
+		policies: policies,
+		stores:   stores,
+		metrics:  metrics,
+	}
+}
+
+// Enforce 用例：对所有数据类别执行一次保留期
+//
+// 某一类数据失败不影响其他类别，失败信息记录在报告里。
+func (e *RetentionEnforcer) Enforce(ctx context.Context) *dto.RetentionReport {
+	now := time.Now()
+	report := &dto.RetentionReport{
+		RanAt:   now,
+		Classes: make([]*dto.RetentionClassReport, 0, len(e.policies)),
+	}
+	for _, policy := range e.policies {
+		report.Classes = append(report.Classes, e.enforceClass(ctx, policy, now))
+	}
+	return report
+}
+
+// enforceClass 辅助方法：执行一类数据的保留期
+func (e *RetentionEnforcer) enforceClass(
+	ctx context.Context,
+	policy RetentionPolicy,
+	now time.Time,
+) *dto.RetentionClassReport {
+	result := &dto.RetentionClassReport{
+		Class:  string(policy.Class),
+		MaxAge: policy.MaxAge,
+	}
+
+	store, ok := e.stores[policy.Class]
+	if !ok {
+		result.Unmanaged = true
+		return result
+	}
+
+	labels := map[string]string{"class": string(policy.Class)}
+	cutoff := now.Add(-policy.MaxAge)
+
+	purged, err := store.PurgeBefore(ctx, cutoff)
+	result.PurgedRows = purged
+	if purged > 0 {
+		e.metrics.IncCounter(MetricRetentionPurgedRows, int(purged), labels)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	oldest, ok, err := store.OldestRecordTime(ctx)
+	if err != nil {
+		if result.Error == "" {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	if ok {
+		result.OldestRecord = oldest
+		result.ExceedsLimit = oldest.Before(cutoff)
+	}
+	if result.ExceedsLimit {
+		e.metrics.IncCounter(MetricRetentionExceeded, 1, labels)
+	}
+	return result
+}
+
+// recommendationListRetentionStore 推荐结果的保留期存储（适配 RecommendationRepository）
+type recommendationListRetentionStore struct {
+	repo repository.RecommendationRepository
+}
+
+// NewRecommendationListRetentionStore 把推荐列表仓储适配为 RetentionStore（删除条数按列表计）
+func NewRecommendationListRetentionStore(repo repository.RecommendationRepository) RetentionStore {
+	return recommendationListRetentionStore{repo: repo}
+}
+
+func (s recommendationListRetentionStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repo.DeleteExpired(ctx, cutoff)
+}
+
+func (s recommendationListRetentionStore) OldestRecordTime(ctx context.Context) (time.Time, bool, error) {
+	return s.repo.OldestListTime(ctx)
+}
+
+// impressionRetentionStore 曝光记录的保留期存储（适配 ImpressionRepository）
+type impressionRetentionStore struct {
+	repo repository.ImpressionRepository
+}
+
+// NewImpressionRetentionStore 把曝光记录仓储适配为 RetentionStore
+func NewImpressionRetentionStore(repo repository.ImpressionRepository) RetentionStore {
+	return impressionRetentionStore{repo: repo}
+}
+
+func (s impressionRetentionStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repo.DeleteImpressionsBefore(ctx, cutoff)
+}
+
+func (s impressionRetentionStore) OldestRecordTime(ctx context.Context) (time.Time, bool, error) {
+	return s.repo.OldestImpressionTime(ctx)
+}
+
+// feedbackRetentionStore 推荐反馈的保留期存储（适配 FeedbackRepository）
+type feedbackRetentionStore struct {
+	repo repository.FeedbackRepository
+}
+
+// NewFeedbackRetentionStore 把反馈记录仓储适配为 RetentionStore
+func NewFeedbackRetentionStore(repo repository.FeedbackRepository) RetentionStore {
+	return feedbackRetentionStore{repo: repo}
+}
+
+func (s feedbackRetentionStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repo.DeleteFeedbackBefore(ctx, cutoff)
+}
+
+func (s feedbackRetentionStore) OldestRecordTime(ctx context.Context) (time.Time, bool, error) {
+	return s.repo.OldestFeedbackTime(ctx)
+}
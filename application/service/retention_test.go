@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeRetentionStore 测试用存储：记录删除的 cutoff，删除后最早的数据时间固定为 oldest
+type fakeRetentionStore struct {
+	purged int64
+	oldest time.Time
+	cutoff time.Time
+}
+
+func (s *fakeRetentionStore) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.cutoff = cutoff
+	return s.purged, nil
+}
+
+func (s *fakeRetentionStore) OldestRecordTime(ctx context.Context) (time.Time, bool, error) {
+	return s.oldest, !s.oldest.IsZero(), nil
+}
+
+func TestRetentionEnforcer_Enforce(t *testing.T) {
+	now := time.Now()
+	impressions := &fakeRetentionStore{purged: 42, oldest: now.Add(-24 * time.Hour)}
+	feedback := &fakeRetentionStore{oldest: now.Add(-400 * 24 * time.Hour)} // 删除失败，仍然超期
+
+	enforcer := NewRetentionEnforcer(
+		DefaultRetentionPolicies(),
+		map[DataClass]RetentionStore{
+			DataClassImpressions: impressions,
+			DataClassFeedback:    feedback,
+		},
+		nil,
+	)
+	report := enforcer.Enforce(context.Background())
+
+	if len(report.Classes) != 3 {
+		t.Fatalf("len(Classes) = %d, want 3", len(report.Classes))
+	}
+	byClass := make(map[string]int)
+	for i, class := range report.Classes {
+		byClass[class.Class] = i
+	}
+
+	got := report.Classes[byClass["impressions"]]
+	if got.PurgedRows != 42 || got.ExceedsLimit {
+		t.Errorf("impressions: purged=%d exceeds=%v, want 42/false", got.PurgedRows, got.ExceedsLimit)
+	}
+	if age := now.Sub(impressions.cutoff); age < 89*24*time.Hour || age > 91*24*time.Hour {
+		t.Errorf("impressions cutoff age = %v, want ~90 days", age)
+	}
+
+	if !report.Classes[byClass["feedback"]].ExceedsLimit {
+		t.Errorf("feedback should be reported as exceeding its retention target")
+	}
+	if !report.Classes[byClass["recommendations"]].Unmanaged {
+		t.Errorf("recommendations has no store and should be reported as unmanaged")
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// ScoreCalibrations 一个版本的分数校准参数
+//
+// 每种推荐理由类型（策略）可以有自己的校准：不同策略的原始分数尺度不同，
+// 各自校准之后才可以比较。没有单独配置的类型使用 Default。
+type ScoreCalibrations struct {
+	Version  string                                  // 参数版本（写入响应元数据，分析时按版本区分）
+	Default  valueobject.ScoreCalibration            // 默认校准（零值表示不校准）
+	ByReason map[string]valueobject.ScoreCalibration // 推荐理由类型（如 followed_by_following）→ 校准
+}
+
+// ScoreCalibrationSource 分数校准参数的来源（热加载，实现在基础设施层）
+type ScoreCalibrationSource interface {
+	// ScoreCalibrations 当前生效的校准参数，没有时返回 nil
+	ScoreCalibrations() *ScoreCalibrations
+}
+
+// WithScoreCalibration 开启分数校准（为 nil 时不校准）
+//
+// 校准只改变返回的 calibrated_score，不改变排序：排序仍然使用原始分数。
+func WithScoreCalibration(source ScoreCalibrationSource) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.calibration = source
+	}
+}
+
+// currentCalibrations 辅助方法：本次请求使用的校准参数（没有开启时返回 nil）
+//
+// 一个请求只读取一次：参数在请求中途热加载时，同一个响应里的分数仍然来自同一个版本。
+func (s *RecommendationService) currentCalibrations() *ScoreCalibrations {
+	if s.calibration == nil {
+		return nil
+	}
+	return s.calibration.ScoreCalibrations()
+}
+
+// version 校准参数的版本（nil 返回空字符串）
+func (c *ScoreCalibrations) version() string {
+	if c == nil {
+		return ""
+	}
+	return c.Version
+}
+
+// Calibrate 一条推荐的校准分数（这种理由类型没有校准时返回 false）
+func (c *ScoreCalibrations) Calibrate(rec *aggregate.UserRecommendation) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	calibration, ok := c.ByReason[reasonTypeKey(rec.Reason().Type())]
+	if !ok {
+		calibration = c.Default
+	}
+	if calibration.IsZero() {
+		return 0, false
+	}
+	return calibration.Calibrate(rec.Score()), true
+}
+
+// applyCalibration 辅助方法：为补全好的推荐填写校准分数（items 和 shown 一一对应）
+func (c *ScoreCalibrations) applyCalibration(items []*dto.UserRecommendationDTO, shown []*aggregate.UserRecommendation) {
+	for i, item := range items {
+		if calibrated, ok := c.Calibrate(shown[i]); ok {
+			item.CalibratedScore = &calibrated
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// SessionKey 会话去重的 key：同一个用户、同一个展示场景、同一个客户端会话
+type SessionKey struct {
+	Tenant    valueobject.TenantID
+	UserID    int64
+	Surface   dto.Surface
+	SessionID string
+}
+
+// SessionStore 会话内已经返回过的推荐对象（短期存储）
+//
+// 为什么不用曝光记录？
+// 曝光是异步写入的（也可能没有开启），用户快速翻页、下拉刷新时，
+// 上一次返回的推荐可能还没有出现在曝光记录里，下一次请求又会返回它们。
+// 会话存储在返回响应之前同步写入，只保留很短的时间（会话结束就没有意义了）。
+type SessionStore interface {
+	// Returned 会话内已经返回过的推荐对象（没有记录时返回空）
+	Returned(ctx context.Context, key SessionKey) ([]int64, error)
+	// Add 记录本次返回的推荐对象（延长会话的有效期）
+	Add(ctx context.Context, key SessionKey, targetUserIDs []int64) error
+}
+
+// WithSessionStore 开启会话内去重
+//
+// 注入后，请求带会话ID（RecommendationRequest.SessionID）时，
+// 同一个会话内翻页、重复请求不会再返回已经返回过的推荐对象（不够一页时返回的更少）。
+//
+// 去重之后的列表是"接着上一页"的一页，不是完整列表：
+// 不作为增量同步的基准保存快照，也不按客户端上次同步的快照计算增量。
+func WithSessionStore(store SessionStore) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.sessions = store
+	}
+}
+
+// sessionReturned 辅助方法：会话内已经返回过的推荐对象
+//
+// 没有开启、请求不带会话ID时返回 nil；查询失败只记日志，按没有返回过处理。
+func (s *RecommendationService) sessionReturned(ctx context.Context, key SessionKey) map[valueobject.UserID]bool {
+	if s.sessions == nil || key.SessionID == "" {
+		return nil
+	}
+	targets, err := s.sessions.Returned(ctx, key)
+	if err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "find session returned targets failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+		return nil
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	returned := make(map[valueobject.UserID]bool, len(targets))
+	for _, target := range targets {
+		targetUserID, err := valueobject.NewUserID(target)
+		if err != nil {
+			continue
+		}
+		returned[targetUserID] = true
+	}
+	return returned
+}
+
+// rememberSession 辅助方法：把本次返回的推荐对象记入会话（失败只记日志）
+func (s *RecommendationService) rememberSession(ctx context.Context, key SessionKey, shown []*aggregate.UserRecommendation) {
+	if s.sessions == nil || key.SessionID == "" || len(shown) == 0 {
+		return
+	}
+	targets := make([]int64, 0, len(shown))
+	for _, rec := range shown {
+		targets = append(targets, rec.TargetUserID().Value())
+	}
+	if err := s.sessions.Add(ctx, key, targets); err != nil {
+		s.logger.Log(ctx, LogLevelWarn, "remember session returned targets failed", map[string]any{
+			FieldUserID: key.UserID,
+			FieldError:  err.Error(),
+		})
+	}
+}
+
+// excludeReturned 辅助函数：去掉会话内已经返回过的推荐（保持原有顺序）
+func excludeReturned(ranked []*aggregate.UserRecommendation, returned map[valueobject.UserID]bool) []*aggregate.UserRecommendation {
+	if len(returned) == 0 {
+		return ranked
+	}
+	remaining := make([]*aggregate.UserRecommendation, 0, len(ranked))
+	for _, rec := range ranked {
+		if !returned[rec.TargetUserID()] {
+			remaining = append(remaining, rec)
+		}
+	}
+	return remaining
+}
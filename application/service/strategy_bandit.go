@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/entity"
+	domainRepository "service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+const (
+	// maxStrategyWeight 人工覆盖允许的最大权重
+	maxStrategyWeight = 10
+)
+
+var (
+	// ErrStrategyBanditDisabled 没有开启策略权重 bandit（配置 strategy_bandit.enabled）
+	ErrStrategyBanditDisabled = errors.New("strategy bandit is not enabled")
+	// ErrInvalidStrategyWeight 人工覆盖的权重不合法
+	ErrInvalidStrategyWeight = errors.New("invalid strategy weight")
+)
+
+// StrategyBandit 应用服务：按展示场景、根据转化反馈动态调整各策略的混合权重
+//
+// 流程：
+// 1. 推荐返回时记录各策略的曝光（臂被拉动），关注归因到曝光时记录转化（奖励）
+// 2. 曝光和转化先在进程内累加，定期批量写入仓储（每个请求都写数据库太贵）
+// 3. 同一个定时任务重新读取累计统计和人工覆盖，用 StrategyBanditPolicy 计算权重
+// 4. 排序时把每个候选的分数乘以它的策略权重（RecommendationService.blendStrategies）
+//
+// 人工覆盖（管理接口）：
+// - 固定权重：运营确定的权重，bandit 的统计继续累计但不生效
+// - 关闭：所有策略权重为 1（紧急止损，如 bandit 把某个策略推得过高）
+// 覆盖保存在仓储中，其他实例在下一次刷新时生效。
+//
+// 权重只影响排序，不影响返回的分数。
+// 统计是近似的：进程退出前没有写入的增量会丢失，转化事件重试时可能重复计数，对权重的影响可以忽略。
+type StrategyBandit struct {
+	repo   domainRepository.StrategyBanditRepository
+	audit  *AuditLogService // 记录人工覆盖（可以为 nil）
+	policy domainService.StrategyBanditPolicy
+	logger Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[banditKey]map[string]*domainRepository.StrategyArmStats // 还没有写入仓储的增量
+	states  map[banditKey]*banditState                                  // 为 nil 表示还没有加载
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// banditKey 统计按租户、展示场景隔离
+type banditKey struct {
+	tenant  valueobject.TenantID
+	surface string
+}
+
+// banditState 一个展示场景最近一次刷新的结果
+type banditState struct {
+	arms          []*domainRepository.StrategyArmStats
+	banditWeights map[string]float64
+	override      *domainRepository.StrategyWeightOverride
+}
+
+// weights 实际生效的权重（为 nil 时所有策略权重为 1）
+func (st *banditState) weights() map[string]float64 {
+	switch {
+	case st == nil:
+		return nil
+	case st.override == nil:
+		return st.banditWeights
+	case st.override.Disabled:
+		return nil
+	default:
+		return st.override.Weights
+	}
+}
+
+// NewStrategyBandit 构造函数（启动后台刷新）
+//
+// 参数：
+// - refreshInterval: 写入增量、重新计算权重的间隔
+// - logger: 后台刷新失败时记录（可以为 nil）
+func NewStrategyBandit(
+	repo domainRepository.StrategyBanditRepository,
+	audit *AuditLogService,
+	policy domainService.StrategyBanditPolicy,
+	refreshInterval time.Duration,
+	logger Logger,
+) *StrategyBandit {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	b := &StrategyBandit{
+		repo:    repo,
+		audit:   audit,
+		policy:  policy,
+		logger:  logger,
+		now:     time.Now,
+		pending: make(map[banditKey]map[string]*domainRepository.StrategyArmStats),
+		states:  make(map[banditKey]*banditState),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go b.run(refreshInterval)
+	return b
+}
+
+// Weights 展示场景上各策略（推荐理由类型）的当前权重，为 nil 时所有策略权重为 1
+//
+// 只读取最近一次刷新的结果，不访问仓储；第一次请求的场景在下一次刷新后才有权重。
+// 返回的 map 不能修改。
+func (b *StrategyBandit) Weights(ctx context.Context, surface string) map[string]float64 {
+	if b == nil {
+		return nil
+	}
+	key := banditKey{tenant: valueobject.TenantFromContext(ctx), surface: surface}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.states[key]
+	if !ok {
+		b.states[key] = nil
+	}
+	return state.weights()
+}
+
+// RecordPulls 记录一次返回的推荐（每条推荐是它的策略被拉动一次）
+func (b *StrategyBandit) RecordPulls(ctx context.Context, surface string, shown []*aggregate.UserRecommendation) {
+	if b == nil || len(shown) == 0 {
+		return
+	}
+	key := banditKey{tenant: valueobject.TenantFromContext(ctx), surface: surface}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, rec := range shown {
+		b.pendingArm(key, reasonTypeKey(rec.Reason().Type())).Pulls++
+	}
+}
+
+// RecordReward 记录一次转化（关注归因到了这个场景上这种推荐理由的曝光）
+func (b *StrategyBandit) RecordReward(ctx context.Context, surface string, reasonType valueobject.ReasonType) {
+	if b == nil {
+		return
+	}
+	key := banditKey{tenant: valueobject.TenantFromContext(ctx), surface: surface}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pendingArm(key, reasonTypeKey(reasonType)).Rewards++
+}
+
+// Refresh 写入累加的增量，重新加载统计和人工覆盖、计算权重
+//
+// 写入失败的增量放回去，下次刷新再写；加载失败的场景保留上一次的权重。
+func (b *StrategyBandit) Refresh(ctx context.Context) error {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[banditKey]map[string]*domainRepository.StrategyArmStats)
+	keys := make([]banditKey, 0, len(b.states)+len(pending))
+	for key := range b.states {
+		keys = append(keys, key)
+	}
+	for key := range pending {
+		if _, ok := b.states[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	b.mu.Unlock()
+
+	var errs []error
+	for key, arms := range pending {
+		deltas := make([]*domainRepository.StrategyArmStats, 0, len(arms))
+		for _, delta := range arms {
+			deltas = append(deltas, delta)
+		}
+		if err := b.repo.AddArmStats(valueobject.WithTenant(ctx, key.tenant), key.surface, deltas); err != nil {
+			errs = append(errs, err)
+			b.restore(key, deltas)
+		}
+	}
+	for _, key := range keys {
+		state, err := b.load(valueobject.WithTenant(ctx, key.tenant), key.surface)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		b.mu.Lock()
+		b.states[key] = state
+		b.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Status 用例：管理接口查询展示场景上各策略的统计和权重（直接读取仓储）
+func (b *StrategyBandit) Status(ctx context.Context, surface string) (*dto.StrategyWeightsDTO, error) {
+	if b == nil {
+		return nil, ErrStrategyBanditDisabled
+	}
+	state, err := b.load(ctx, surface)
+	if err != nil {
+		return nil, err
+	}
+	effective := state.weights()
+	result := &dto.StrategyWeightsDTO{
+		Surface: surface,
+		Arms:    make([]*dto.StrategyArmDTO, 0, len(state.arms)),
+	}
+	for _, arm := range state.arms {
+		row := &dto.StrategyArmDTO{
+			Arm:          arm.Arm,
+			Pulls:        arm.Pulls,
+			Rewards:      arm.Rewards,
+			BanditWeight: weightOf(state.banditWeights, arm.Arm),
+			Weight:       weightOf(effective, arm.Arm),
+		}
+		if arm.Pulls > 0 {
+			row.ConversionRate = float64(arm.Rewards) / float64(arm.Pulls)
+		}
+		result.Arms = append(result.Arms, row)
+	}
+	if state.override != nil {
+		result.Override = &dto.StrategyWeightOverrideDTO{
+			Disabled:  state.override.Disabled,
+			Weights:   state.override.Weights,
+			UpdatedBy: state.override.UpdatedBy,
+			UpdatedAt: state.override.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+// SetOverride 用例：管理接口设置（或删除）展示场景的人工覆盖
+//
+// 和其他修改类的管理操作一样，审计写入失败时操作失败。
+// 操作人必须是认证后的身份（管理接口从 Principal 取），没有时返回 ErrUnauthenticated。
+// 本实例立即生效，其他实例在下一次刷新时生效。
+func (b *StrategyBandit) SetOverride(ctx context.Context, req *dto.StrategyWeightOverrideRequest) error {
+	if b == nil {
+		return ErrStrategyBanditDisabled
+	}
+	if req.Operator == "" {
+		return ErrUnauthenticated
+	}
+	var override *domainRepository.StrategyWeightOverride
+	if !req.Clear {
+		if !req.Disabled && len(req.Weights) == 0 {
+			return fmt.Errorf("%w: weights are required unless disabled", ErrInvalidStrategyWeight)
+		}
+		for arm, weight := range req.Weights {
+			if weight <= 0 || weight > maxStrategyWeight || math.IsNaN(weight) {
+				return fmt.Errorf("%w: %s = %v (must be in (0, %d])", ErrInvalidStrategyWeight, arm, weight, maxStrategyWeight)
+			}
+		}
+		override = &domainRepository.StrategyWeightOverride{
+			Disabled:  req.Disabled,
+			Weights:   req.Weights,
+			UpdatedBy: req.Operator,
+			UpdatedAt: b.now(),
+		}
+	}
+
+	before, err := b.repo.FindOverride(ctx, req.Surface)
+	if err != nil {
+		return err
+	}
+	if b.audit != nil {
+		if err := b.audit.Record(ctx, &dto.AuditRecord{
+			Actor:     req.Operator,
+			Action:    string(entity.AuditActionStrategyWeightOverride),
+			Target:    req.Surface,
+			Before:    before,
+			After:     override,
+			RequestID: req.RequestID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := b.repo.SaveOverride(ctx, req.Surface, override); err != nil {
+		return err
+	}
+
+	key := banditKey{tenant: valueobject.TenantFromContext(ctx), surface: req.Surface}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if state := b.states[key]; state != nil {
+		updated := *state
+		updated.override = override
+		b.states[key] = &updated
+	}
+	return nil
+}
+
+// Close 停止后台刷新（最后写入一次累加的增量）
+func (b *StrategyBandit) Close() {
+	b.closeOnce.Do(func() { close(b.stop) })
+	<-b.done
+}
+
+// run 后台刷新
+func (b *StrategyBandit) run(interval time.Duration) {
+	defer close(b.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			_ = b.Refresh(context.Background())
+			return
+		case <-ticker.C:
+			if err := b.Refresh(context.Background()); err != nil {
+				b.logger.Log(context.Background(), LogLevelWarn, "refresh strategy bandit failed", map[string]any{
+					FieldError: err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// load 辅助方法：读取展示场景的累计统计和人工覆盖，计算 bandit 权重
+func (b *StrategyBandit) load(ctx context.Context, surface string) (*banditState, error) {
+	arms, err := b.repo.ListArmStats(ctx, surface)
+	if err != nil {
+		return nil, err
+	}
+	override, err := b.repo.FindOverride(ctx, surface)
+	if err != nil {
+		return nil, err
+	}
+	return &banditState{arms: arms, banditWeights: b.policy.Weights(arms), override: override}, nil
+}
+
+// pendingArm 辅助方法：某个场景、某个策略还没有写入的增量（调用方持有锁）
+func (b *StrategyBandit) pendingArm(key banditKey, arm string) *domainRepository.StrategyArmStats {
+	if b.pending[key] == nil {
+		b.pending[key] = make(map[string]*domainRepository.StrategyArmStats)
+	}
+	delta, ok := b.pending[key][arm]
+	if !ok {
+		delta = &domainRepository.StrategyArmStats{Arm: arm}
+		b.pending[key][arm] = delta
+	}
+	return delta
+}
+
+// restore 辅助方法：写入失败的增量放回去
+func (b *StrategyBandit) restore(key banditKey, deltas []*domainRepository.StrategyArmStats) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, delta := range deltas {
+		arm := b.pendingArm(key, delta.Arm)
+		arm.Pulls += delta.Pulls
+		arm.Rewards += delta.Rewards
+	}
+}
+
+// weightOf 辅助函数：权重表中策略的权重（没有列出的为 1）
+func weightOf(weights map[string]float64, arm string) float64 {
+	if weight, ok := weights[arm]; ok {
+		return weight
+	}
+	return 1
+}
+
+// blendStrategies 辅助方法：按策略权重重新排序（分数 × 权重，相同时保持原来的顺序）
+//
+// 没有开启 bandit 或没有权重时原样返回；推荐的分数不变。
+func (s *RecommendationService) blendStrategies(
+	ctx context.Context,
+	surface dto.Surface,
+	ranked []*aggregate.UserRecommendation,
+) []*aggregate.UserRecommendation {
+	weights := s.bandit.Weights(ctx, string(surface))
+	if len(weights) == 0 {
+		return ranked
+	}
+	blended := func(rec *aggregate.UserRecommendation) float64 {
+		return float64(rec.Score()) * weightOf(weights, reasonTypeKey(rec.Reason().Type()))
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return blended(ranked[i]) > blended(ranked[j])
+	})
+	return ranked
+}
+
+// WithStrategyBandit 开启策略权重 bandit（为 nil 时不调整）
+func WithStrategyBandit(bandit *StrategyBandit) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.bandit = bandit
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+// fakeStrategyBanditRepo 测试用 bandit 仓储（只有一个展示场景）
+type fakeStrategyBanditRepo struct {
+	arms     map[string]*repository.StrategyArmStats
+	override *repository.StrategyWeightOverride
+}
+
+func (r *fakeStrategyBanditRepo) AddArmStats(ctx context.Context, surface string, deltas []*repository.StrategyArmStats) error {
+	for _, delta := range deltas {
+		if r.arms[delta.Arm] == nil {
+			r.arms[delta.Arm] = &repository.StrategyArmStats{Arm: delta.Arm}
+		}
+		r.arms[delta.Arm].Pulls += delta.Pulls
+		r.arms[delta.Arm].Rewards += delta.Rewards
+	}
+	return nil
+}
+
+func (r *fakeStrategyBanditRepo) ListArmStats(ctx context.Context, surface string) ([]*repository.StrategyArmStats, error) {
+	var stats []*repository.StrategyArmStats
+	for _, arm := range r.arms {
+		copied := *arm
+		stats = append(stats, &copied)
+	}
+	return stats, nil
+}
+
+func (r *fakeStrategyBanditRepo) SaveOverride(ctx context.Context, surface string, override *repository.StrategyWeightOverride) error {
+	r.override = override
+	return nil
+}
+
+func (r *fakeStrategyBanditRepo) FindOverride(ctx context.Context, surface string) (*repository.StrategyWeightOverride, error) {
+	return r.override, nil
+}
+
+func TestStrategyBandit(t *testing.T) {
+	repo := &fakeStrategyBanditRepo{arms: make(map[string]*repository.StrategyArmStats)}
+	audit := &fakeAuditLogRepo{}
+	policy := domainService.StrategyBanditPolicy{MinWeight: 0.5, MaxWeight: 2, MinPulls: 1}
+	b := NewStrategyBandit(repo, NewAuditLogService(audit), policy, time.Hour, nil)
+	defer b.Close()
+	ctx := context.Background()
+
+	if weights := b.Weights(ctx, "home_feed"); weights != nil {
+		t.Fatalf("weights before refresh = %v, want nil", weights)
+	}
+
+	// 共同关注转化率 50%，重新互动 0%
+	following, _ := aggregate.NewUserRecommendation(userIDs(10)[0], valueobject.NewFollowedByFollowingReason(userIDs(2)), 0)
+	missed, _ := aggregate.NewUserRecommendation(userIDs(11)[0], valueobject.NewMissedPostsReason(1), 1)
+	for i := 0; i < 2; i++ {
+		b.RecordPulls(ctx, "home_feed", []*aggregate.UserRecommendation{following, missed})
+	}
+	b.RecordReward(ctx, "home_feed", valueobject.ReasonFollowedByFollowing)
+	if err := b.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if repo.arms["followed_by_following"].Pulls != 2 || repo.arms["followed_by_following"].Rewards != 1 {
+		t.Fatalf("stored arm = %+v, want 2 pulls and 1 reward", repo.arms["followed_by_following"])
+	}
+	weights := b.Weights(ctx, "home_feed")
+	if weights["followed_by_following"] != 2 || weights["missed_posts"] != 0.5 {
+		t.Errorf("weights = %v, want followed_by_following 2 and missed_posts 0.5", weights)
+	}
+
+	// 人工关闭：本实例立即生效，并写入审计日志
+	err := b.SetOverride(ctx, &dto.StrategyWeightOverrideRequest{Surface: "home_feed", Operator: "ops", Disabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weights := b.Weights(ctx, "home_feed"); weights != nil {
+		t.Errorf("weights after disable = %v, want nil", weights)
+	}
+	if len(audit.entries) != 1 {
+		t.Errorf("audit entries = %d, want 1", len(audit.entries))
+	}
+
+	// 不合法的固定权重
+	err = b.SetOverride(ctx, &dto.StrategyWeightOverrideRequest{
+		Surface: "home_feed", Operator: "ops", Weights: map[string]float64{"missed_posts": 0},
+	})
+	if !errors.Is(err, ErrInvalidStrategyWeight) {
+		t.Errorf("SetOverride with zero weight: err = %v, want ErrInvalidStrategyWeight", err)
+	}
+
+	// 没有操作人（没有经过认证）时拒绝，不写审计日志
+	err = b.SetOverride(ctx, &dto.StrategyWeightOverrideRequest{Surface: "home_feed", Disabled: true})
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("SetOverride without operator err = %v, want ErrUnauthenticated", err)
+	}
+	if len(audit.entries) != 1 {
+		t.Errorf("audit entries = %d after unauthenticated override, want 1", len(audit.entries))
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	domainService "service/domain/service"
+)
+
+// defaultPreviewPosts 每条推荐默认带几篇最近的帖子
+const defaultPreviewPosts = 3
+
+// 推荐信号的时间窗口（最近几天的关注、帖子）
+const (
+	defaultRecencyDays = 7
+	MinRecencyDays     = 1
+	MaxRecencyDays     = 30 // 更长的窗口要扫描的关注关系太多，信号也已经不"新"了
+)
+
+// ErrInvalidRecencyDays 请求的时间窗口超出 [MinRecencyDays, MaxRecencyDays]
+var ErrInvalidRecencyDays = errors.New("invalid recency window")
+
+// SurfacePolicy 一个展示场景的推荐行为
+//
+// 为什么需要？
+// 各个场景一直共用一套行为，但产品对它们的要求已经不同：
+// 个人主页侧边栏只放得下几个推荐，推送里的推荐过了当天就没有意义，
+// 新用户引导希望列表不要被同一类推荐占满。
+type SurfacePolicy struct {
+	MaxLimit         int           // 每次最多返回多少条（0 表示不限制）
+	Expiry           time.Duration // 推荐的有效期（0 表示使用默认的 7 天）
+	MaxPerReasonType int           // 同一种推荐理由最多多少条（0 表示不限制）
+	MaxPerReferrer   int           // 只由同一个人带来的推荐最多多少条（0 表示不限制）
+	RepeatWindow     time.Duration // 这段时间内展示过的推荐对象排到后面（0 表示不调整，需要曝光记录）
+	PreviewPosts     int           // 每条推荐带几篇最近的帖子（0 表示默认的 3 篇）
+	PreviewLength    int           // 帖子预览最多多少个字符，超出的截断并加上省略号（0 表示不截断）
+	RecencyDays      int           // 请求没有指定时的时间窗口（0 表示默认的 7 天）
+
+	// Generator 这个场景使用的推荐策略（候选来源、过滤器），为 nil 时使用默认的生成器
+	Generator *domainService.RecommendationGenerator
+
+	// Interleaving 这个场景上的交错实验，为 nil 时不做实验
+	Interleaving *InterleavingExperiment
+}
+
+// SurfacePolicies 各展示场景的推荐行为
+//
+// 没有单独配置的场景使用 SurfaceDefault 的行为；都没有配置时不做任何调整。
+type SurfacePolicies struct {
+	policies map[dto.Surface]SurfacePolicy
+}
+
+// NewSurfacePolicies 构造函数
+func NewSurfacePolicies(policies map[dto.Surface]SurfacePolicy) *SurfacePolicies {
+	return &SurfacePolicies{policies: policies}
+}
+
+// For 展示场景的推荐行为（s 为 nil 时返回零值，即默认行为）
+func (s *SurfacePolicies) For(surface dto.Surface) SurfacePolicy {
+	if s == nil {
+		return SurfacePolicy{}
+	}
+	if policy, ok := s.policies[surface]; ok {
+		return policy
+	}
+	return s.policies[dto.SurfaceDefault]
+}
+
+// previewPosts 辅助方法：每条推荐带几篇最近的帖子
+func (p SurfacePolicy) previewPosts() int {
+	if p.PreviewPosts > 0 {
+		return p.PreviewPosts
+	}
+	return defaultPreviewPosts
+}
+
+// recencyDays 辅助方法：这个场景默认的时间窗口
+func (p SurfacePolicy) recencyDays() int {
+	if p.RecencyDays > 0 {
+		return p.RecencyDays
+	}
+	return defaultRecencyDays
+}
+
+// ParseSurface 解析展示场景（配置中的 default 表示未指定场景）
+func ParseSurface(raw string) (dto.Surface, error) {
+	if raw == "default" {
+		return dto.SurfaceDefault, nil
+	}
+	switch surface := dto.Surface(raw); surface {
+	case dto.SurfaceHomeFeed, dto.SurfaceProfileSidebar, dto.SurfaceOnboarding, dto.SurfacePush, dto.SurfaceExplore, dto.SurfaceDiscovery:
+		return surface, nil
+	}
+	return "", fmt.Errorf("unknown surface %q", raw)
+}
+
+// WithSurfacePolicies 按展示场景调整推荐行为（数量上限、有效期、时间窗口、多样性、重复展示、帖子预览、推荐策略、交错实验）
+func WithSurfacePolicies(policies *SurfacePolicies) RecommendationServiceOption {
+	return func(s *RecommendationService) {
+		s.surfaces = policies
+	}
+}
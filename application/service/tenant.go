@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"service/domain/valueobject"
+)
+
+// ErrUnknownTenant 请求的租户没有配置
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// TenantConfig 单个租户（App 品牌）的配置
+type TenantConfig struct {
+	ID             valueobject.TenantID
+	Locale         string                     // 推荐理由文案的语言（如 "zh-CN"），为空时由配置服务决定
+	ScoringWeights valueobject.ScoringWeights // 打分权重
+}
+
+// Tenants 租户配置表
+//
+// 职责：
+// - 接口层识别租户：Resolve 校验请求带来的租户标识（没有配置的租户直接拒绝）
+// - 按租户提供配置：文案语言、打分权重
+//
+// 默认租户总是存在：没有配置时使用默认权重和默认语言，
+// 多租户之前的调用方（不带租户标识）不受影响。
+type Tenants struct {
+	configs map[valueobject.TenantID]TenantConfig
+}
+
+// NewTenants 构造函数
+//
+// 租户标识不合法或重复时返回错误（配置写错应该在启动时发现）。
+func NewTenants(configs []TenantConfig) (*Tenants, error) {
+	t := &Tenants{configs: map[valueobject.TenantID]TenantConfig{
+		valueobject.DefaultTenantID: {
+			ID:             valueobject.DefaultTenantID,
+			ScoringWeights: valueobject.DefaultScoringWeights(),
+		},
+	}}
+	seen := make(map[valueobject.TenantID]bool, len(configs))
+	for _, cfg := range configs {
+		if _, err := valueobject.NewTenantID(cfg.ID.String()); err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", cfg.ID, err)
+		}
+		if seen[cfg.ID] {
+			return nil, fmt.Errorf("tenant %q: duplicated", cfg.ID)
+		}
+		seen[cfg.ID] = true
+		if cfg.ScoringWeights == (valueobject.ScoringWeights{}) {
+			cfg.ScoringWeights = valueobject.DefaultScoringWeights()
+		}
+		t.configs[cfg.ID] = cfg
+	}
+	return t, nil
+}
+
+// Resolve 识别请求的租户
+//
+// 为空时返回默认租户；格式不合法或没有配置时返回 ErrUnknownTenant。
+func (t *Tenants) Resolve(raw string) (valueobject.TenantID, error) {
+	if raw == "" {
+		return valueobject.DefaultTenantID, nil
+	}
+	id, err := valueobject.NewTenantID(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTenant, raw)
+	}
+	if _, ok := t.configs[id]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTenant, raw)
+	}
+	return id, nil
+}
+
+// Config 租户的配置（nil 或没有配置的租户返回默认配置）
+func (t *Tenants) Config(id valueobject.TenantID) TenantConfig {
+	if t != nil {
+		if cfg, ok := t.configs[id]; ok {
+			return cfg
+		}
+	}
+	return TenantConfig{ID: id, ScoringWeights: valueobject.DefaultScoringWeights()}
+}
+
+// Locale 当前请求的文案语言
+//
+// 请求带来的语言（valueobject.WithLocale，接口层从 metainfo / Accept-Language 识别）优先，
+// 没有时使用租户配置的语言；都没有时为零值（配置服务使用默认语言，本地文案使用简体中文）。
+func (t *Tenants) Locale(ctx context.Context) valueobject.Locale {
+	if locale := valueobject.LocaleFromContext(ctx); !locale.IsZero() {
+		return locale
+	}
+	return valueobject.ParseLocale(t.Config(valueobject.TenantFromContext(ctx)).Locale)
+}
+
+// ScoringWeights 每个租户的打分权重（用于 service.WithTenantScoringWeights）
+func (t *Tenants) ScoringWeights() map[valueobject.TenantID]valueobject.ScoringWeights {
+	weights := make(map[valueobject.TenantID]valueobject.ScoringWeights, len(t.configs))
+	for id, cfg := range t.configs {
+		weights[id] = cfg.ScoringWeights
+	}
+	return weights
+}
+
+// IDs 所有租户（包括默认租户，按标识排序）
+func (t *Tenants) IDs() []valueobject.TenantID {
+	if t == nil {
+		return []valueobject.TenantID{valueobject.DefaultTenantID}
+	}
+	ids := make([]valueobject.TenantID, 0, len(t.configs))
+	for id := range t.configs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"service/domain/valueobject"
+)
+
+func TestTenants_Resolve(t *testing.T) {
+	tenants, err := NewTenants([]TenantConfig{
+		{ID: "brand_b", Locale: "en-US", ScoringWeights: valueobject.ScoringWeights{PerRelatedUser: 5, PerRecentPost: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		raw     string
+		want    valueobject.TenantID
+		wantErr bool
+	}{
+		{"", valueobject.DefaultTenantID, false},
+		{"default", valueobject.DefaultTenantID, false},
+		{"brand_b", "brand_b", false},
+		{"brand_c", "", true}, // 没有配置
+		{"Brand_B", "", true}, // 格式不合法
+		{"rec:x", "", true},   // 不能拼进缓存 key
+	}
+	for _, c := range cases {
+		got, err := tenants.Resolve(c.raw)
+		if c.wantErr {
+			if !errors.Is(err, ErrUnknownTenant) {
+				t.Errorf("Resolve(%q) err = %v, want ErrUnknownTenant", c.raw, err)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", c.raw, got, err, c.want)
+		}
+	}
+
+	if got := tenants.Config("brand_b").Locale; got != "en-US" {
+		t.Errorf("locale = %q, want en-US", got)
+	}
+	if got := tenants.ScoringWeights()[valueobject.DefaultTenantID]; got != valueobject.DefaultScoringWeights() {
+		t.Errorf("default weights = %+v", got)
+	}
+	if _, err := NewTenants([]TenantConfig{{ID: "a"}, {ID: "a"}}); err == nil {
+		t.Error("duplicated tenant should be rejected")
+	}
+}
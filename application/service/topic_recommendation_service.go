@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"service/application/dto"
+	"service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+)
+
+const (
+	defaultTopicLimit = 10
+	maxTopicLimit     = 50
+)
+
+// TopicRecommendationService 应用服务：话题推荐
+//
+// 为什么不放在 RecommendationService 里？
+// 话题推荐是 App 中单独的模块：请求路径、展示数据、缓存和降级都和用户推荐不同，
+// 它只需要话题推荐器和少量共用的能力（数据删除、推荐理由文案、租户配置）。
+type TopicRecommendationService struct {
+	recommender        *domainService.TopicRecommender
+	erasureRepo        repository.UserErasureRepository // 可以为 nil
+	reasonConfigClient ReasonTextConfigClient           // 可以为 nil（使用默认文案）
+	tenants            *Tenants                         // 可以为 nil（使用默认配置）
+}
+
+// NewTopicRecommendationService 构造函数
+func NewTopicRecommendationService(
+	recommender *domainService.TopicRecommender,
+	erasureRepo repository.UserErasureRepository,
+	reasonConfigClient ReasonTextConfigClient,
+	tenants *Tenants,
+) *TopicRecommendationService {
+	return &TopicRecommendationService{
+		recommender:        recommender,
+		erasureRepo:        erasureRepo,
+		reasonConfigClient: reasonConfigClient,
+		tenants:            tenants,
+	}
+}
+
+// GetTopicRecommendations 用例：为用户推荐话题
+//
+// 1. 已删除数据的用户返回空列表
+// 2. 按租户的打分权重生成话题推荐（我关注的人关注的话题，排除我已关注的）
+// 3. 取前 limit 个，补全推荐理由文案
+func (s *TopicRecommendationService) GetTopicRecommendations(
+	ctx context.Context,
+	req *dto.TopicRecommendationRequest,
+) (*dto.TopicRecommendationResponse, error) {
+	userID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTopicLimit
+	}
+	limit = min(limit, maxTopicLimit)
+
+	resp := &dto.TopicRecommendationResponse{Topics: []*dto.TopicRecommendationDTO{}}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	weights := s.tenants.Config(valueobject.TenantFromContext(ctx)).ScoringWeights
+	recommendations, err := s.recommender.RecommendTopics(ctx, userID, weights)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for _, rec := range recommendations {
+		resp.Topics = append(resp.Topics, &dto.TopicRecommendationDTO{
+			RecommendationID: rec.ID().Value(),
+			Topic:            rec.Topic().Name(),
+			Hashtag:          rec.Topic().Hashtag(),
+			Reason:           reasonText(ctx, s.reasonConfigClient, s.tenants, nil, rec.Reason(), nil),
+			Score:            rec.Score(),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import "context"
+
+// TransactionManager 事务边界（Unit of Work）：fn 中对多个仓储的写入一起提交或者一起回滚
+//
+// 为什么定义在应用层？
+// 哪些写入必须一起成功是用例的规则（如关注关系 + 待发送的事件、反馈 + 它对应的曝光），
+// 由应用服务决定事务的范围；怎么开启、提交事务是技术细节，由基础设施层实现
+// （persistence.GormTransactionManager）。
+//
+// 仓储不需要额外的参数：事务随 fn 收到的 ctx 传递，
+// 使用这个 ctx 的仓储调用都在同一个事务中（不支持事务的实现，如内存仓储、Redis，照常执行）。
+//
+// 使用示例：
+//
+//	err := s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+//	    if err := s.socialGraphRepo.SaveFollow(ctx, follow); err != nil {
+//	        return err
+//	    }
+//	    return s.outbox.Append(ctx, follow.FollowedEvent())
+//	})
+type TransactionManager interface {
+	// WithinTransaction 在事务中执行 fn：fn 返回错误（或者 panic）时回滚，否则提交
+	//
+	// ctx 中已经有事务时加入这个事务（由最外层决定提交还是回滚）。
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// noopTransactionManager 空实现：没有注入 TransactionManager 时使用（直接执行 fn，没有事务）
+type noopTransactionManager struct{}
+
+func (noopTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
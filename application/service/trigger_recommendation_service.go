@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"service/application/dto"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+const (
+	// triggerFanoutPageSize 扇出时每页遍历多少个粉丝（一页写入一次存储）
+	triggerFanoutPageSize = 1000
+	// maxTriggerRecommendations 推送管道每次最多拿到几条（推送是打扰，宁缺毋滥）
+	maxTriggerRecommendations = 3
+)
+
+// ErrUnknownTrigger 未知的触发事件类型
+var ErrUnknownTrigger = errors.New("unknown trigger type")
+
+// errFanoutLimitReached 扇出达到上限（停止遍历粉丝，不是错误）
+var errFanoutLimitReached = errors.New("fanout limit reached")
+
+// TriggerPolicy 一种触发事件的推荐策略
+type TriggerPolicy struct {
+	Expiry    time.Duration // 从事件发生开始多久后过期（过期后不再推送）
+	MaxFanout int           // 最多扇出给多少个粉丝（最近关注的在前）
+}
+
+// DefaultTriggerPolicies 默认的触发策略
+//
+// - 里程碑：48 小时内有效
+// - 回归："回来了"过一天就不新鲜了，24 小时内有效
+//
+// 扇出都限制在最近关注的 10 万个粉丝：更早的粉丝和 TA 的联系通常已经很弱。
+func DefaultTriggerPolicies() map[dto.TriggerType]TriggerPolicy {
+	return map[dto.TriggerType]TriggerPolicy{
+		dto.TriggerMilestone: {Expiry: 48 * time.Hour, MaxFanout: 100000},
+		dto.TriggerReturned:  {Expiry: 24 * time.Hour, MaxFanout: 100000},
+	}
+}
+
+// TriggerEntry 保存的一条触发式推荐
+type TriggerEntry struct {
+	SubjectUserID int64           `json:"subject_user_id"`
+	Trigger       dto.TriggerType `json:"trigger"`
+	ReasonType    int             `json:"reason_type"`
+	RelatedCount  int             `json:"related_count"`
+	Score         int             `json:"score"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+}
+
+// TriggerRecommendationStore 触发式推荐存储（推送管道通过 GetTriggerRecommendations 读取）
+//
+// 同一个用户的列表中，同一个被推荐用户的同一种触发只保留最新的一条；租户从 ctx 中读取。
+type TriggerRecommendationStore interface {
+	// Add 给一批用户各添加同一条推荐
+	Add(ctx context.Context, userIDs []int64, entry TriggerEntry) error
+	// List 用户的全部触发式推荐（可能包含已经过期的）
+	List(ctx context.Context, userID int64) ([]TriggerEntry, error)
+}
+
+// TriggerRecommendationService 应用服务：触发式推荐
+//
+// 为什么需要？
+// 常规推荐是"用户来拉"，触发式推荐是"事件来推"：
+// 你关注的人粉丝突破了 1 万、很久没发帖的人回来了，这些时刻值得发一条推送。
+//
+// 流程：
+// 1. 事件（消息队列）到达：按触发类型构造推荐理由，扇出给关注了 TA 的人，写入存储
+// 2. 推送管道为某个用户拉取：过滤过期的、已删除数据的，按分数取前几条
+//
+// 和常规推荐的区别：
+// - 列表很小（最多 3 条），推荐对象都是用户已经关注的人
+// - 每种触发有自己的有效期（TriggerPolicy），过期后不再推送
+// - 事件本身过了有效期才到达（如消费积压）时直接丢弃
+type TriggerRecommendationService struct {
+	followerRepo       repository.FollowerRepository
+	erasureRepo        repository.UserErasureRepository // 可以为 nil
+	store              TriggerRecommendationStore
+	reasonConfigClient ReasonTextConfigClient // 可以为 nil（使用默认文案）
+	tenants            *Tenants               // 可以为 nil（使用默认配置）
+	policies           map[dto.TriggerType]TriggerPolicy
+	now                func() time.Time
+}
+
+// NewTriggerRecommendationService 构造函数（使用 DefaultTriggerPolicies）
+func NewTriggerRecommendationService(
+	followerRepo repository.FollowerRepository,
+	erasureRepo repository.UserErasureRepository,
+	store TriggerRecommendationStore,
+	reasonConfigClient ReasonTextConfigClient,
+	tenants *Tenants,
+) *TriggerRecommendationService {
+	return &TriggerRecommendationService{
+		followerRepo:       followerRepo,
+		erasureRepo:        erasureRepo,
+		store:              store,
+		reasonConfigClient: reasonConfigClient,
+		tenants:            tenants,
+		policies:           DefaultTriggerPolicies(),
+		now:                time.Now,
+	}
+}
+
+// HandleTrigger 用例：处理一个触发事件，返回扇出给了多少个粉丝
+//
+// 事件已经过了有效期、触发事件的用户已删除数据时什么都不做（返回 0）。
+// 扇出中途失败时返回错误，已经写入的不会回滚：重试时同一条推荐覆盖写入（幂等）。
+func (s *TriggerRecommendationService) HandleTrigger(ctx context.Context, req *dto.TriggerEventRequest) (int, error) {
+	policy, ok := s.policies[req.Type]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownTrigger, req.Type)
+	}
+	subjectID, err := valueobject.NewUserID(req.SubjectUserID)
+	if err != nil {
+		return 0, err
+	}
+	reason, err := triggerReason(req)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	occurredAt := req.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+	expiresAt := occurredAt.Add(policy.Expiry)
+	if !now.Before(expiresAt) {
+		return 0, nil
+	}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, subjectID)
+		if err != nil {
+			return 0, err
+		}
+		if erased {
+			return 0, nil
+		}
+	}
+
+	weights := s.tenants.Config(valueobject.TenantFromContext(ctx)).ScoringWeights
+	entry := TriggerEntry{
+		SubjectUserID: subjectID.Value(),
+		Trigger:       req.Type,
+		ReasonType:    int(reason.Type()),
+		RelatedCount:  reason.RelatedCount(),
+		Score:         reason.WeightWith(weights),
+		CreatedAt:     occurredAt,
+		ExpiresAt:     expiresAt,
+	}
+
+	fanout := 0
+	err = s.followerRepo.ScanFollowers(ctx, subjectID, triggerFanoutPageSize, func(page []valueobject.UserID) error {
+		if remaining := policy.MaxFanout - fanout; len(page) > remaining {
+			page = page[:remaining]
+		}
+		userIDs := make([]int64, 0, len(page))
+		for _, followerID := range page {
+			userIDs = append(userIDs, followerID.Value())
+		}
+		if err := s.store.Add(ctx, userIDs, entry); err != nil {
+			return err
+		}
+		fanout += len(userIDs)
+		if fanout >= policy.MaxFanout {
+			return errFanoutLimitReached
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFanoutLimitReached) {
+		return fanout, err
+	}
+	return fanout, nil
+}
+
+// GetTriggerRecommendations 用例：推送管道拉取用户的触发式推荐
+//
+// 1. 已删除数据的用户返回空列表
+// 2. 跳过已过期的推荐、触发事件的用户已删除数据的推荐
+// 3. 按分数从高到低（相同时新的在前）取前 maxTriggerRecommendations 条
+func (s *TriggerRecommendationService) GetTriggerRecommendations(
+	ctx context.Context,
+	req *dto.TriggerRecommendationRequest,
+) (*dto.TriggerRecommendationResponse, error) {
+	userID, err := valueobject.NewUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.TriggerRecommendationResponse{Recommendations: []*dto.TriggerRecommendationDTO{}}
+	if s.erasureRepo != nil {
+		erased, err := s.erasureRepo.IsErased(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			return resp, nil
+		}
+	}
+
+	entries, err := s.store.List(ctx, userID.Value())
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	for _, entry := range entries {
+		if len(resp.Recommendations) == maxTriggerRecommendations {
+			break
+		}
+		if !now.Before(entry.ExpiresAt) {
+			continue
+		}
+		subjectID, err := valueobject.NewUserID(entry.SubjectUserID)
+		if err != nil {
+			continue
+		}
+		if s.erasureRepo != nil {
+			erased, err := s.erasureRepo.IsErased(ctx, subjectID)
+			if err != nil {
+				return nil, err
+			}
+			if erased {
+				continue
+			}
+		}
+		reason := valueobject.RestoreRecommendationReason(valueobject.ReasonType(entry.ReasonType), entry.RelatedCount)
+		resp.Recommendations = append(resp.Recommendations, &dto.TriggerRecommendationDTO{
+			UserID:    entry.SubjectUserID,
+			Trigger:   string(entry.Trigger),
+			Reason:    reasonText(ctx, s.reasonConfigClient, s.tenants, nil, reason, nil),
+			Score:     entry.Score,
+			ExpiresAt: entry.ExpiresAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// triggerReason 辅助函数：触发事件 → 推荐理由
+func triggerReason(req *dto.TriggerEventRequest) (valueobject.RecommendationReason, error) {
+	switch req.Type {
+	case dto.TriggerMilestone:
+		if req.Milestone <= 0 {
+			return valueobject.RecommendationReason{}, fmt.Errorf("invalid milestone %d", req.Milestone)
+		}
+		return valueobject.NewFollowedUserMilestoneReason(req.Milestone), nil
+	case dto.TriggerReturned:
+		if req.InactiveDays <= 0 {
+			return valueobject.RecommendationReason{}, fmt.Errorf("invalid inactive days %d", req.InactiveDays)
+		}
+		return valueobject.NewFollowedUserReturnedReason(req.InactiveDays), nil
+	}
+	return valueobject.RecommendationReason{}, fmt.Errorf("%w: %q", ErrUnknownTrigger, req.Type)
+}
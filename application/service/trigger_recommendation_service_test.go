@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/valueobject"
+)
+
+// stubFollowerRepo 测试用反向关注关系：每个用户有 followers 个粉丝（ID 从 100 开始），每页 2 个
+type stubFollowerRepo struct {
+	followers int
+}
+
+func (r stubFollowerRepo) ScanFollowers(ctx context.Context, userID valueobject.UserID, pageSize int, fn func(page []valueobject.UserID) error) error {
+	var page []valueobject.UserID
+	for i := 0; i < r.followers; i++ {
+		id, _ := valueobject.NewUserID(int64(100 + i))
+		page = append(page, id)
+		if len(page) == 2 || i == r.followers-1 {
+			if err := fn(page); err != nil {
+				return err
+			}
+			page = nil
+		}
+	}
+	return nil
+}
+
+// memoryTriggerStore 测试用触发式推荐存储
+type memoryTriggerStore struct {
+	entries map[int64]map[string]TriggerEntry
+}
+
+func (s *memoryTriggerStore) Add(ctx context.Context, userIDs []int64, entry TriggerEntry) error {
+	for _, userID := range userIDs {
+		if s.entries[userID] == nil {
+			s.entries[userID] = make(map[string]TriggerEntry)
+		}
+		s.entries[userID][fmt.Sprintf("%s:%d", entry.Trigger, entry.SubjectUserID)] = entry
+	}
+	return nil
+}
+
+func (s *memoryTriggerStore) List(ctx context.Context, userID int64) ([]TriggerEntry, error) {
+	var entries []TriggerEntry
+	for _, entry := range s.entries[userID] {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func TestTriggerRecommendationService(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryTriggerStore{entries: make(map[int64]map[string]TriggerEntry)}
+	s := NewTriggerRecommendationService(stubFollowerRepo{followers: 5}, nil, store, nil, nil)
+	s.now = func() time.Time { return now }
+	s.policies[dto.TriggerMilestone] = TriggerPolicy{Expiry: 48 * time.Hour, MaxFanout: 3}
+	ctx := context.Background()
+
+	// 扇出在上限处停止（第二页只写入一个粉丝）
+	fanout, err := s.HandleTrigger(ctx, &dto.TriggerEventRequest{Type: dto.TriggerMilestone, SubjectUserID: 7, Milestone: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fanout != 3 || len(store.entries) != 3 {
+		t.Fatalf("fanout = %d (stored for %d users), want 3", fanout, len(store.entries))
+	}
+
+	// 已经过了有效期才到达的事件直接丢弃
+	fanout, err = s.HandleTrigger(ctx, &dto.TriggerEventRequest{
+		Type: dto.TriggerReturned, SubjectUserID: 8, InactiveDays: 30, OccurredAt: now.Add(-25 * time.Hour),
+	})
+	if err != nil || fanout != 0 {
+		t.Fatalf("stale event: fanout = %d, err = %v, want 0, nil", fanout, err)
+	}
+
+	// 回归（分数较低）排在里程碑后面
+	if _, err := s.HandleTrigger(ctx, &dto.TriggerEventRequest{
+		Type: dto.TriggerReturned, SubjectUserID: 9, InactiveDays: 30, OccurredAt: now.Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.GetTriggerRecommendations(ctx, &dto.TriggerRecommendationRequest{UserID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 2 || resp.Recommendations[0].UserID != 7 || resp.Recommendations[1].UserID != 9 {
+		t.Fatalf("recommendations = %+v, want [7 9]", resp.Recommendations)
+	}
+
+	// 回归的推荐 24 小时后过期，里程碑的还在
+	now = now.Add(24 * time.Hour)
+	resp, err = s.GetTriggerRecommendations(ctx, &dto.TriggerRecommendationRequest{UserID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 1 || resp.Recommendations[0].Trigger != string(dto.TriggerMilestone) {
+		t.Errorf("after 24h: recommendations = %+v, want only the milestone", resp.Recommendations)
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// UserCacheInvalidator 用户缓存清理接口
+//
+// 为什么定义在应用层？
+// 和 UserRPCClient 一样，缓存是技术细节：
+// 应用层只声明"删除用户数据时要清理缓存"，
+// 具体清理哪些 key（进程内、Redis）由基础设施层的缓存装饰器实现。
+type UserCacheInvalidator interface {
+	// InvalidateUser 删除与用户有关的缓存（幂等）
+	InvalidateUser(ctx context.Context, userID int64) error
+}
+
+// UserDataService 应用服务：用户数据删除（删除权 / right to erasure）和导出（访问权 / right of access）
+//
+// 用例：
+// - DeleteUserData：删除推荐服务保存的用户数据，并停止为该用户生成推荐
+// - ReactivateUser：用户重新完成引导后，恢复生成推荐
+// - ExportUserData：导出推荐服务保存的用户数据
+//
+// 推荐服务保存了哪些用户数据？
+// - 曝光记录：给他展示过谁、他被展示给了谁（ImpressionRepository）
+// - 转化记录：哪次曝光带来了他的关注、他被谁关注（ConversionRepository，可选）
+// - 反馈记录：他点开、关注了哪些推荐，他被谁点开、关注（FeedbackRepository，可选）
+// - 推荐列表：为他生成的推荐列表和其中的推荐理由（RecommendationRepository，可选）
+// - 实验分组：他在 A/B 实验中固定的分组（ExperimentAssignmentRepository，可选）
+// - 活跃记录：他最近一次请求推荐的时间（ActiveUsersRepository，可选）
+// - 反向关注索引：他关注的人最近关注了谁（RecentFollowIndexRepository，可选）
+// - 缓存：他的资料、他的关注列表（UserCacheInvalidator）
+//
+// 关注关系、帖子、风控分级由各自的上游服务保存，
+// 它们会消费同一个删除事件自行删除，推荐服务只读，不负责删除。
+//
+// 为什么先写删除标记？
+// 删除过程中如果有推荐请求进来，会重新生成曝光记录。
+// 先写标记，之后的请求直接返回空列表，不再产生新数据。
+//
+// 幂等：
+// 每一步都可以重复执行，删除事件重复投递或部分失败后重试都是安全的。
+type UserDataService struct {
+	erasureRepo    repository.UserErasureRepository
+	impressionRepo repository.ImpressionRepository
+	conversionRepo repository.ConversionRepository           // 可以为 nil（没有归因）
+	feedbackRepo   repository.FeedbackRepository             // 可以为 nil（不记录反馈）
+	listRepo       repository.RecommendationRepository       // 可以为 nil（不保存推荐列表）
+	assignmentRepo repository.ExperimentAssignmentRepository // 可以为 nil（不保存实验分组）
+	activeUsers    repository.ActiveUsersRepository          // 可以为 nil（不记录活跃用户）
+	followIndex    repository.RecentFollowIndexRepository    // 可以为 nil（没有开启反向关注索引）
+	caches         []UserCacheInvalidator
+}
+
+// NewUserDataService 构造函数
+func NewUserDataService(
+	erasureRepo repository.UserErasureRepository,
+	impressionRepo repository.ImpressionRepository,
+	conversionRepo repository.ConversionRepository,
+	feedbackRepo repository.FeedbackRepository,
+	listRepo repository.RecommendationRepository,
+	assignmentRepo repository.ExperimentAssignmentRepository,
+	activeUsers repository.ActiveUsersRepository,
+	followIndex repository.RecentFollowIndexRepository,
+	caches []UserCacheInvalidator,
+) *UserDataService {
+	return &UserDataService{
+		erasureRepo:    erasureRepo,
+		impressionRepo: impressionRepo,
+		conversionRepo: conversionRepo,
+		feedbackRepo:   feedbackRepo,
+		listRepo:       listRepo,
+		assignmentRepo: assignmentRepo,
+		activeUsers:    activeUsers,
+		followIndex:    followIndex,
+		caches:         caches,
+	}
+}
+
+// DeleteUserData 用例：删除用户数据
+//
+// 步骤：
+// 1. 写入删除标记（之后不再为该用户生成推荐）
+// 2. 删除曝光记录、转化记录、反馈记录、推荐列表、实验分组、活跃记录
+// 3. 删除反向关注索引
+// 4. 清理缓存
+//
+// 步骤 2~4 互不依赖：某一步失败时其他步骤照常执行，
+// 最后把所有失败合并返回，调用方（删除事件消费者）重试即可。
+func (s *UserDataService) DeleteUserData(ctx context.Context, userID int64) error {
+	domainUserID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	// 步骤1：删除标记写入失败时不继续，否则删完之后可能又生成新数据
+	if err := s.erasureRepo.MarkErased(ctx, domainUserID, time.Now()); err != nil {
+		return fmt.Errorf("mark user erased: %w", err)
+	}
+
+	var errs []error
+
+	// 步骤2：曝光记录、转化记录、反馈记录、推荐列表、实验分组、活跃记录
+	if err := s.impressionRepo.DeleteUserImpressions(ctx, domainUserID); err != nil {
+		errs = append(errs, fmt.Errorf("delete impressions: %w", err))
+	}
+	if s.conversionRepo != nil {
+		if err := s.conversionRepo.DeleteUserConversions(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete conversions: %w", err))
+		}
+	}
+	if s.feedbackRepo != nil {
+		if err := s.feedbackRepo.DeleteUserFeedback(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete feedback: %w", err))
+		}
+	}
+	if s.listRepo != nil {
+		if err := s.listRepo.DeleteUserLists(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete recommendation lists: %w", err))
+		}
+	}
+	if s.assignmentRepo != nil {
+		if err := s.assignmentRepo.DeleteUserAssignments(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete experiment assignments: %w", err))
+		}
+	}
+	if s.activeUsers != nil {
+		if err := s.activeUsers.DeleteUser(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete active user: %w", err))
+		}
+	}
+
+	// 步骤3：反向关注索引
+	if s.followIndex != nil {
+		if err := s.followIndex.DeleteUserIndex(ctx, domainUserID); err != nil {
+			errs = append(errs, fmt.Errorf("delete recent follow index: %w", err))
+		}
+	}
+
+	// 步骤4：缓存
+	for _, cache := range s.caches {
+		if err := cache.InvalidateUser(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("invalidate cache: %w", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("delete user data: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ReactivateUser 用例：用户重新完成引导，恢复生成推荐
+//
+// 由引导流程（onboarding）调用。删除的数据不会恢复，推荐从零开始积累。
+func (s *UserDataService) ReactivateUser(ctx context.Context, userID int64) error {
+	domainUserID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return err
+	}
+	return s.erasureRepo.ClearErased(ctx, domainUserID)
+}
+
+// notStoredDataClasses 推荐服务不保存的数据类别（导出时说明）
+var notStoredDataClasses = []string{
+	"follows, posts, trust levels: owned by upstream services, export them there",
+}
+
+// ExportUserData 用例：导出用户数据（访问权）
+//
+// 导出范围和 DeleteUserData 的删除范围对应：
+// - 展示给该用户的曝光记录
+// - 该用户对推荐的反馈、归因到推荐的关注
+// - 为该用户保存的推荐列表（包括推荐理由）
+// - 该用户在 A/B 实验中固定的分组
+// - 是否已停止为该用户生成推荐
+//
+// 不导出"他被展示给了谁"、"谁点开或关注了他"：那是其他用户的数据。
+// 缓存和反向关注索引是上游数据的副本，由上游服务导出。
+func (s *UserDataService) ExportUserData(ctx context.Context, userID int64) (*dto.UserDataExport, error) {
+	domainUserID, err := valueobject.NewUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	impressions, err := s.impressionRepo.ListUserImpressions(ctx, domainUserID)
+	if err != nil {
+		return nil, fmt.Errorf("list impressions: %w", err)
+	}
+	erased, err := s.erasureRepo.IsErased(ctx, domainUserID)
+	if err != nil {
+		return nil, fmt.Errorf("check erasure: %w", err)
+	}
+
+	var feedback []*entity.RecommendationFeedback
+	if s.feedbackRepo != nil {
+		if feedback, err = s.feedbackRepo.ListUserFeedback(ctx, domainUserID); err != nil {
+			return nil, fmt.Errorf("list feedback: %w", err)
+		}
+	}
+	var conversions []*entity.Conversion
+	if s.conversionRepo != nil {
+		if conversions, err = s.conversionRepo.ListUserConversions(ctx, domainUserID); err != nil {
+			return nil, fmt.Errorf("list conversions: %w", err)
+		}
+	}
+	var lists []*aggregate.RecommendationList
+	if s.listRepo != nil {
+		if lists, err = s.listRepo.ListUserLists(ctx, domainUserID); err != nil {
+			return nil, fmt.Errorf("list recommendation lists: %w", err)
+		}
+	}
+	var assignments []valueobject.ExperimentAssignment
+	if s.assignmentRepo != nil {
+		if assignments, err = s.assignmentRepo.ListUserAssignments(ctx, domainUserID); err != nil {
+			return nil, fmt.Errorf("list experiment assignments: %w", err)
+		}
+	}
+
+	export := &dto.UserDataExport{
+		UserID:                    userID,
+		GeneratedAt:               time.Now().UTC(),
+		RecommendationsShown:      make([]*dto.ExportedImpression, 0, len(impressions)),
+		RecommendationLists:       make([]*dto.ExportedRecommendationList, 0, len(lists)),
+		Feedback:                  make([]*dto.ExportedFeedback, 0, len(feedback)),
+		Conversions:               make([]*dto.ExportedConversion, 0, len(conversions)),
+		ExperimentAssignments:     make([]*dto.ExportedExperimentAssignment, 0, len(assignments)),
+		RecommendationsSuppressed: erased,
+		NotStored:                 notStoredDataClasses,
+	}
+	for _, impression := range impressions {
+		export.RecommendationsShown = append(export.RecommendationsShown, &dto.ExportedImpression{
+			RecommendationID: impression.RecommendationID().Value(),
+			TargetUserID:     impression.TargetUserID().Value(),
+			Surface:          impression.Surface(),
+			ShownAt:          impression.ShownAt().UTC(),
+		})
+	}
+	for _, f := range feedback {
+		export.Feedback = append(export.Feedback, &dto.ExportedFeedback{
+			RecommendationID: f.RecommendationID().Value(),
+			TargetUserID:     f.TargetUserID().Value(),
+			Action:           f.Action().String(),
+			At:               f.At().UTC(),
+		})
+	}
+	for _, c := range conversions {
+		export.Conversions = append(export.Conversions, &dto.ExportedConversion{
+			RecommendationID: c.RecommendationID().Value(),
+			TargetUserID:     c.TargetUserID().Value(),
+			Direct:           c.Direct(),
+			ConvertedAt:      c.ConvertedAt().UTC(),
+		})
+	}
+	for _, list := range lists {
+		exported := &dto.ExportedRecommendationList{
+			GeneratedAt:     list.GeneratedAt().UTC(),
+			Recommendations: make([]*dto.ExportedRecommendation, 0, list.Count()),
+		}
+		for _, rec := range list.All() {
+			exported.Recommendations = append(exported.Recommendations, &dto.ExportedRecommendation{
+				TargetUserID: rec.TargetUserID().Value(),
+				ReasonType:   reasonTypeKey(rec.Reason().Type()),
+				RelatedCount: rec.Reason().RelatedCount(),
+				Score:        rec.Score(),
+				ExpiresAt:    rec.ExpiresAt().UTC(),
+			})
+		}
+		export.RecommendationLists = append(export.RecommendationLists, exported)
+	}
+	for _, assignment := range assignments {
+		export.ExperimentAssignments = append(export.ExperimentAssignments, &dto.ExportedExperimentAssignment{
+			Experiment: assignment.Experiment(),
+			Variant:    assignment.Variant(),
+		})
+	}
+	return export, nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	domainService "service/domain/service"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// fakeImpressionRepo 测试用曝光记录：记录删除了谁，返回预设的曝光
+type fakeImpressionRepo struct {
+	listed  []*entity.Impression
+	deleted []valueobject.UserID
+	err     error
+}
+
+func (r *fakeImpressionRepo) RecordImpressions(ctx context.Context, impressions []*entity.Impression) error {
+	return nil
+}
+
+func (r *fakeImpressionRepo) GetImpressedTargets(ctx context.Context, viewerID valueobject.UserID, since time.Time) ([]valueobject.UserID, error) {
+	var targets []valueobject.UserID
+	for _, impression := range r.listed {
+		if !impression.ShownAt().Before(since) {
+			targets = append(targets, impression.TargetUserID())
+		}
+	}
+	return targets, nil
+}
+
+func (r *fakeImpressionRepo) ListUserImpressions(ctx context.Context, viewerID valueobject.UserID) ([]*entity.Impression, error) {
+	return r.listed, nil
+}
+
+func (r *fakeImpressionRepo) ListImpressionHistory(ctx context.Context, viewerID valueobject.UserID, from, to time.Time, after *repository.ImpressionCursor, limit int) ([]*entity.Impression, error) {
+	result := make([]*entity.Impression, 0, len(r.listed))
+	for _, impression := range r.listed {
+		if impression.ShownAt().Before(from) || !impression.ShownAt().Before(to) {
+			continue
+		}
+		if after == nil || after.Precedes(impression) {
+			result = append(result, impression)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return repository.CursorOf(result[i]).Precedes(result[j])
+	})
+	return result[:min(limit, len(result))], nil
+}
+
+func (r *fakeImpressionRepo) FindLatestImpression(ctx context.Context, viewerID, targetUserID valueobject.UserID, from, to time.Time) (*entity.Impression, bool, error) {
+	var latest *entity.Impression
+	for _, impression := range r.listed {
+		if impression.ViewerID() != viewerID || impression.TargetUserID() != targetUserID ||
+			impression.ShownAt().Before(from) || impression.ShownAt().After(to) {
+			continue
+		}
+		if latest == nil || impression.ShownAt().After(latest.ShownAt()) {
+			latest = impression
+		}
+	}
+	return latest, latest != nil, nil
+}
+
+func (r *fakeImpressionRepo) FindImpression(ctx context.Context, viewerID valueobject.UserID, recommendationID valueobject.RecommendationID) (*entity.Impression, bool, error) {
+	for _, impression := range r.listed {
+		if impression.ViewerID() == viewerID && impression.RecommendationID() == recommendationID {
+			return impression, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (r *fakeImpressionRepo) DeleteUserImpressions(ctx context.Context, userID valueobject.UserID) error {
+	r.deleted = append(r.deleted, userID)
+	return r.err
+}
+
+func (r *fakeImpressionRepo) DeleteImpressionsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeImpressionRepo) OldestImpressionTime(ctx context.Context) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+// fakeErasureRepo 测试用删除记录
+type fakeErasureRepo struct {
+	erased map[valueobject.UserID]bool
+}
+
+func (r *fakeErasureRepo) MarkErased(ctx context.Context, userID valueobject.UserID, erasedAt time.Time) error {
+	r.erased[userID] = true
+	return nil
+}
+
+func (r *fakeErasureRepo) IsErased(ctx context.Context, userID valueobject.UserID) (bool, error) {
+	return r.erased[userID], nil
+}
+
+func (r *fakeErasureRepo) ClearErased(ctx context.Context, userID valueobject.UserID) error {
+	delete(r.erased, userID)
+	return nil
+}
+
+// fakeCacheInvalidator 测试用缓存：记录清理了谁
+type fakeCacheInvalidator struct {
+	invalidated []int64
+}
+
+func (c *fakeCacheInvalidator) InvalidateUser(ctx context.Context, userID int64) error {
+	c.invalidated = append(c.invalidated, userID)
+	return nil
+}
+
+func TestDeleteUserData_SuppressesGenerationUntilReactivated(t *testing.T) {
+	ctx := context.Background()
+	impressions := &fakeImpressionRepo{}
+	erasures := &fakeErasureRepo{erased: make(map[valueobject.UserID]bool)}
+	cache := &fakeCacheInvalidator{}
+
+	generator := domainService.NewRecommendationGenerator(stubSocialGraphRepo{}, stubContentRepo{})
+	recommendations := NewRecommendationService(
+		generator,
+		stubSocialGraphRepo{},
+		stubContentRepo{},
+		slowContentClient{slowUserID: -1},
+		stubUserRPCClient{},
+		nil,
+		WithUserErasureRepository(erasures),
+	)
+	userData := NewUserDataService(erasures, impressions, nil, nil, nil, nil, nil, nil, []UserCacheInvalidator{cache})
+
+	if err := userData.DeleteUserData(ctx, 1); err != nil {
+		t.Fatalf("DeleteUserData failed: %v", err)
+	}
+	if len(impressions.deleted) != 1 || impressions.deleted[0].Value() != 1 {
+		t.Errorf("deleted impressions of %v, want [1]", impressions.deleted)
+	}
+	if len(cache.invalidated) != 1 || cache.invalidated[0] != 1 {
+		t.Errorf("invalidated caches of %v, want [1]", cache.invalidated)
+	}
+
+	req := &dto.RecommendationRequest{UserID: 1, Limit: 10}
+	resp, err := recommendations.GetFollowingBasedRecommendations(ctx, req)
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if len(resp.Recommendations) != 0 {
+		t.Errorf("erased user got %d recommendations, want 0", len(resp.Recommendations))
+	}
+
+	if err := userData.ReactivateUser(ctx, 1); err != nil {
+		t.Fatalf("ReactivateUser failed: %v", err)
+	}
+	resp, err = recommendations.GetFollowingBasedRecommendations(ctx, req)
+	if err != nil {
+		t.Fatalf("GetFollowingBasedRecommendations failed: %v", err)
+	}
+	if len(resp.Recommendations) == 0 {
+		t.Errorf("reactivated user got no recommendations")
+	}
+}
+
+func TestDeleteUserData_ContinuesAfterPartialFailure(t *testing.T) {
+	impressions := &fakeImpressionRepo{err: errors.New("db down")}
+	erasures := &fakeErasureRepo{erased: make(map[valueobject.UserID]bool)}
+	cache := &fakeCacheInvalidator{}
+	userData := NewUserDataService(erasures, impressions, nil, nil, nil, nil, nil, nil, []UserCacheInvalidator{cache})
+
+	if err := userData.DeleteUserData(context.Background(), 1); err == nil {
+		t.Fatal("DeleteUserData err = nil, want error so the caller retries")
+	}
+	if len(cache.invalidated) != 1 {
+		t.Errorf("caches should still be invalidated when impression deletion fails")
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	viewer, _ := valueobject.NewUserID(1)
+	target, _ := valueobject.NewUserID(2)
+	shownAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	impression := entity.NewImpression(valueobject.NewRecommendationID(), viewer, target, valueobject.ReasonFollowedByFollowing, "home_feed", shownAt)
+	impressions := &fakeImpressionRepo{listed: []*entity.Impression{impression}}
+	feedback := &fakeFeedbackRepo{listed: []*entity.RecommendationFeedback{
+		entity.NewRecommendationFeedback(impression, valueobject.FeedbackFollowed, shownAt.Add(time.Minute)),
+	}}
+	conversion := entity.NewConversion(impression, shownAt.Add(time.Minute), true)
+	conversions := &fakeConversionRepo{conversions: map[valueobject.RecommendationID]*entity.Conversion{
+		conversion.RecommendationID(): conversion,
+	}}
+	erasures := &fakeErasureRepo{erased: map[valueobject.UserID]bool{viewer: true}}
+	lists := &memoryListRepo{latest: aggregate.RestoreRecommendationList(viewer, []*aggregate.UserRecommendation{
+		aggregate.RestoreUserRecommendation(target, valueobject.RestoreRecommendationReason(valueobject.ReasonFollowedByFollowing, 3), 30, 0, shownAt, shownAt.Add(time.Hour)),
+	}, shownAt, aggregate.FollowingSampling{})}
+	assignments := &fakeAssignmentRepo{saved: make(map[string]valueobject.ExperimentAssignment)}
+	for _, experiment := range []string{"ranking", "copy"} {
+		assignment, err := valueobject.NewExperimentAssignment(experiment, "control")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := assignments.SaveAssignment(context.Background(), viewer, assignment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other, _ := valueobject.NewUserID(3)
+	activeUsers := &fakeActiveUsers{touched: []valueobject.UserID{viewer, other}}
+	userData := NewUserDataService(erasures, impressions, conversions, feedback, lists, assignments, activeUsers, nil, nil)
+
+	export, err := userData.ExportUserData(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExportUserData failed: %v", err)
+	}
+	if len(export.RecommendationsShown) != 1 {
+		t.Fatalf("exported %d impressions, want 1", len(export.RecommendationsShown))
+	}
+	shown := export.RecommendationsShown[0]
+	if shown.TargetUserID != 2 || shown.Surface != "home_feed" || !shown.ShownAt.Equal(shownAt) {
+		t.Errorf("exported impression = %+v", shown)
+	}
+	if !export.RecommendationsSuppressed {
+		t.Errorf("erased user should be exported as suppressed")
+	}
+	if len(export.Feedback) != 1 || export.Feedback[0].TargetUserID != 2 || export.Feedback[0].Action != valueobject.FeedbackFollowed.String() {
+		t.Errorf("exported feedback = %+v, want one follow of user 2", export.Feedback)
+	}
+	if len(export.Conversions) != 1 || export.Conversions[0].TargetUserID != 2 || !export.Conversions[0].Direct {
+		t.Errorf("exported conversions = %+v, want one direct conversion to user 2", export.Conversions)
+	}
+	for _, class := range export.NotStored {
+		if strings.HasPrefix(class, "feedback") {
+			t.Errorf("not_stored lists %q, but feedback is stored and exported", class)
+		}
+	}
+	if len(export.RecommendationLists) != 1 || len(export.RecommendationLists[0].Recommendations) != 1 {
+		t.Fatalf("exported lists = %+v, want one list with one recommendation", export.RecommendationLists)
+	}
+	if rec := export.RecommendationLists[0].Recommendations[0]; rec.TargetUserID != 2 || rec.RelatedCount != 3 || rec.ReasonType == "" {
+		t.Errorf("exported recommendation = %+v", rec)
+	}
+	wantAssignments := []dto.ExportedExperimentAssignment{{Experiment: "copy", Variant: "control"}, {Experiment: "ranking", Variant: "control"}}
+	if len(export.ExperimentAssignments) != len(wantAssignments) {
+		t.Fatalf("exported assignments = %+v, want %+v", export.ExperimentAssignments, wantAssignments)
+	}
+	for i, want := range wantAssignments {
+		if got := *export.ExperimentAssignments[i]; got != want {
+			t.Errorf("exported assignment %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// 删除用户数据时推荐列表一起删除
+	if err := userData.DeleteUserData(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteUserData failed: %v", err)
+	}
+	if lists.latest != nil {
+		t.Errorf("recommendation lists should be deleted with the user's data")
+	}
+	if len(assignments.saved) != 0 {
+		t.Errorf("experiment assignments = %v, want deleted with the user's data", assignments.saved)
+	}
+	if len(activeUsers.touched) != 1 || activeUsers.touched[0] != other {
+		t.Errorf("active users = %v, want only user 3 (no precomputed lists for the deleted user)", activeUsers.touched)
+	}
+}
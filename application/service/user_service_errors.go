@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// user 服务调用失败的分类（由防腐层把上游错误码翻译过来）
+//
+// 应用层和接口层只依赖这些分类，不需要知道 user 服务的错误码：
+// user 服务调整错误码时，只需要修改防腐层的映射。
+var (
+	ErrUserNotFound           = errors.New("user not found")             // 用户不存在或已注销
+	ErrUserServiceUnavailable = errors.New("user service unavailable")   // 超时、过载、内部错误（可以重试）
+	ErrUserServiceRejected    = errors.New("user service rejected call") // 参数错误、没有权限（重试也不会成功）
+)
+
+// UserServiceError user 服务调用失败（结构化错误）
+//
+// errors.Is(err, ErrUserServiceUnavailable) 判断分类，
+// UpstreamCode、Message 保留上游的原始信息，用于日志排查。
+type UserServiceError struct {
+	Kind         error  // 分类：ErrUserNotFound / ErrUserServiceUnavailable / ErrUserServiceRejected
+	UpstreamCode int32  // user 服务的错误码（没有错误码时为 0）
+	Message      string // user 服务的错误信息
+}
+
+func (e *UserServiceError) Error() string {
+	return fmt.Sprintf("%v (upstream code %d: %s)", e.Kind, e.UpstreamCode, e.Message)
+}
+
+// Unwrap 支持 errors.Is 判断分类
+func (e *UserServiceError) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"service/application/dto"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// WarmupStep 预热的一个步骤
+type WarmupStep struct {
+	Name     string
+	Run      func(ctx context.Context) error
+	Required bool // 必需步骤失败时启动失败；可选步骤失败只记日志
+}
+
+// Warmup 启动预热
+//
+// 为什么需要？
+// 刚启动的实例什么都是冷的：数据库、Redis 连接池是空的，进程内缓存是空的，
+// 到配置服务的 HTTP 连接也没有建立。发布后第一批请求要替这些"第一次"买单，
+// 每次发布都能在监控上看到一个延迟尖刺。
+//
+// 预热在服务开始监听端口之前执行（服务注册、接收流量都在监听之后），
+// 按顺序执行每个步骤：
+//  1. 建立连接：数据库、Redis（必需，连不上说明配置或网络有问题，启动失败比带病上线好）
+//  2. 预加载推荐理由文案：每个租户的语言、每种理由各取一次（可选）
+//  3. 预生成：为少量预热用户按各自租户生成一次推荐（可选），
+//     走一遍完整链路，打分权重、候选管道、各级缓存都被用到一次
+//
+// 打分权重、候选管道这些配置在组装依赖时已经加载和校验（配置错误时 Provider panic），
+// 预热只负责让它们所在的链路"热"起来。
+//
+// 整个预热有总超时：超时后剩下的可选步骤跳过，不能因为预热拖住发布。
+type Warmup struct {
+	steps   []WarmupStep
+	timeout time.Duration
+	logger  Logger
+	done    atomic.Bool
+}
+
+// NewWarmup 构造函数
+//
+// 参数：
+// - steps: 按顺序执行的步骤
+// - timeout: 整个预热的超时时间
+// - logger: 日志（可以为 nil）
+func NewWarmup(steps []WarmupStep, timeout time.Duration, logger Logger) *Warmup {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Warmup{steps: steps, timeout: timeout, logger: logger}
+}
+
+// Run 执行预热（必需步骤失败时返回错误）
+func (w *Warmup) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	for _, step := range w.steps {
+		start := time.Now()
+		err := step.Run(ctx)
+		fields := map[string]any{
+			FieldStep:       step.Name,
+			FieldDurationMS: time.Since(start).Milliseconds(),
+		}
+		switch {
+		case err != nil && step.Required:
+			return fmt.Errorf("warmup %s: %w", step.Name, err)
+		case err != nil:
+			fields[FieldError] = err.Error()
+			w.logger.Log(ctx, LogLevelWarn, "warmup step failed", fields)
+		default:
+			w.logger.Log(ctx, LogLevelInfo, "warmup step done", fields)
+		}
+	}
+	w.done.Store(true)
+	return nil
+}
+
+// Done 预热是否已经完成（可用于就绪检查）
+func (w *Warmup) Done() bool {
+	return w.done.Load()
+}
+
+// PreloadReasonTexts 预热：每个租户的语言、每种推荐理由各获取一次文案
+//
+// 没有配置服务时什么都不做。返回所有失败（不会因为一个失败停止）。
+func (s *RecommendationService) PreloadReasonTexts(ctx context.Context) error {
+	if s.reasonConfigClient == nil {
+		return nil
+	}
+	var errs []error
+	for _, tenant := range s.tenants.IDs() {
+		locale := s.tenants.Config(tenant).Locale
+		for _, reasonType := range []string{"followed_by_following", "popular_in_network"} {
+			if _, err := s.reasonConfigClient.GetReasonText(ctx, reasonType, 1, locale, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %w", tenant, reasonType, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Prewarm 预热：为一组用户生成推荐并补全第一页
+//
+// 和 GetFollowingBasedRecommendations 走同一条链路，但不记录曝光、不保存快照：
+// 预热用户没有真的看到这些推荐。
+// 总是重新生成（不使用持久化的推荐列表），生成的列表照常保存，预热用户的第一次请求可以直接使用。
+func (s *RecommendationService) Prewarm(ctx context.Context, tenant valueobject.TenantID, userIDs []int64) error {
+	ctx = valueobject.WithTenant(ctx, tenant)
+	var errs []error
+	for _, userID := range userIDs {
+		domainUserID, err := valueobject.NewUserID(userID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ranked, _, err := s.rankCandidates(ctx, domainUserID, dto.SurfaceHomeFeed, 0, dto.FreshnessRealtime, aggregate.RecommendationCriteria{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		if len(ranked) > warmupPageSize {
+			ranked = ranked[:warmupPageSize]
+		}
+		if _, _, _, err := s.hydrate(ctx, ranked, nil, dto.SurfaceHomeFeed); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warmupPageSize 预生成时补全的数量（和默认的 limit 一致）
+const warmupPageSize = 10
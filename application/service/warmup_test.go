@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWarmup_Run(t *testing.T) {
+	var ran []string
+	step := func(name string, err error, required bool) WarmupStep {
+		return WarmupStep{Name: name, Required: required, Run: func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	// 可选步骤失败不影响后面的步骤
+	w := NewWarmup([]WarmupStep{
+		step("database", nil, true),
+		step("reason_texts", errors.New("config service down"), false),
+		step("cohort", nil, false),
+	}, time.Second, nil)
+	if err := w.Run(context.Background()); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if len(ran) != 3 || !w.Done() {
+		t.Fatalf("ran = %v, done = %v", ran, w.Done())
+	}
+
+	// 必需步骤失败：停止预热，返回错误
+	ran = nil
+	w = NewWarmup([]WarmupStep{
+		step("redis", errors.New("connection refused"), true),
+		step("cohort", nil, false),
+	}, time.Second, nil)
+	if err := w.Run(context.Background()); err == nil {
+		t.Fatal("Run() = nil, want error")
+	}
+	if len(ran) != 1 || w.Done() {
+		t.Errorf("ran = %v, done = %v", ran, w.Done())
+	}
+}
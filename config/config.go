@@ -0,0 +1,483 @@
+// Package config 服务配置（config.yaml）
+//
+// 目前只解析装配依赖需要的部分：环境 profile、服务监听端口和优雅停止、注册中心、数据库、Redis、HTTP 服务地址、候选来源和过滤器、扇出查询、租户、启动预热、用户优先级、流量抓取、特征快照日志、分数校准、策略权重 bandit、展示场景、降级策略、推荐列表缓存、社交图谱缓存、HTTP 接口、授权白名单、mTLS、负载保护、异常请求检测、关注事件消费、推荐列表预计算。
+// 其他配置项（限流、熔断等）仍然写在 Provider 中，逐步迁移到这里。
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv 覆盖配置文件中 profile 的环境变量
+const ProfileEnv = "APP_PROFILE"
+
+// DefaultProfile 没有指定 profile 时使用的环境
+const DefaultProfile = "dev"
+
+// Config 服务配置
+//
+// 环境 profile：
+// profiles 中每个环境（dev、staging、prod）为每个端口选择一种实现（mock、memory、mysql、redis、http），
+// 切换环境只需要设置 APP_PROFILE，不需要修改代码重新编译。
+// 生效的选择在启动时写入日志（WiringReport）。
+type Config struct {
+	Profile     string                  `yaml:"profile"`
+	Profiles    map[string]Profile      `yaml:"profiles"`
+	Server      ServerConfig            `yaml:"server"`
+	Database    DatabaseConfig          `yaml:"database"`
+	Redis       RedisConfig             `yaml:"redis"`
+	HTTPClients map[Port]string         `yaml:"http_clients"` // 端口 → HTTP 服务地址
+	RPCClients  map[string]string       `yaml:"rpc_clients"`  // Kitex 服务名 → host:port（没有使用注册中心时）
+	DualRead    map[Port]DualReadConfig `yaml:"dual_read"`    // 端口 → 迁移期间同时查询的另一个实现（只比较，不返回）
+
+	CandidatePipeline CandidatePipelineConfig  `yaml:"candidate_pipeline"`
+	Fanout            FanoutConfig             `yaml:"fanout"`
+	Tenants           []TenantConfig           `yaml:"tenants"`
+	Warmup            WarmupConfig             `yaml:"warmup"`
+	Priority          PriorityConfig           `yaml:"priority"`
+	TrafficCapture    TrafficCaptureConfig     `yaml:"traffic_capture"`
+	PostTimestamps    PostTimestampsConfig     `yaml:"post_timestamps"`
+	Hydration         HydrationConfig          `yaml:"hydration"`
+	FeatureLog        FeatureLogConfig         `yaml:"feature_log"`
+	DomainEvents      DomainEventsConfig       `yaml:"domain_events"`
+	ScoreCalibration  ScoreCalibrationConfig   `yaml:"score_calibration"`
+	StrategyBandit    StrategyBanditConfig     `yaml:"strategy_bandit"`
+	Hybrid            HybridConfig             `yaml:"hybrid"`
+	Surfaces          map[string]SurfaceConfig `yaml:"surfaces"` // 展示场景 → 推荐行为（default 用于没有列出的场景）
+	Experiments       []ExperimentConfig       `yaml:"experiments"`
+
+	Degradation map[string]DegradationConfig `yaml:"degradation"` // 依赖（content、reason_text、ranking）→ 降级策略，没有列出的依赖使用默认策略
+
+	RecommendationCache RecommendationCacheConfig `yaml:"recommendation_cache"`
+	SocialGraphCache    SocialGraphCacheConfig    `yaml:"social_graph_cache"`
+	HotCache            HotCacheConfig            `yaml:"hot_cache"`
+	HTTPServer          HTTPServerConfig          `yaml:"http_server"`
+
+	Authorization  AuthorizationConfig  `yaml:"authorization"`
+	MTLS           MTLSConfig           `yaml:"mtls"`
+	Secrets        SecretsConfig        `yaml:"secrets"`
+	Log            LogConfig            `yaml:"log"`
+	Encryption     EncryptionConfig     `yaml:"encryption"`
+	Retention      RetentionConfig      `yaml:"retention"`
+	LoadShedding   LoadSheddingConfig   `yaml:"load_shedding"`
+	AbuseDetection AbuseDetectionConfig `yaml:"abuse_detection"`
+
+	FollowEvents FollowEventsConfig `yaml:"follow_events"`
+	Precompute   PrecomputeConfig   `yaml:"precompute"`
+}
+
+// ServerConfig Kitex 服务
+type ServerConfig struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"` // 监听端口，为 0 时 8888
+
+	// 管理接口（RecommendationAdminService）的监听端口，为 0 时不启动
+	AdminPort int `yaml:"admin_port"`
+
+	// 收到退出信号之后等待处理中的请求、释放连接最多多久（为 0 时 10 秒）
+	ShutdownTimeoutSeconds int `yaml:"shutdown_timeout_seconds"`
+
+	Registry RegistryConfig `yaml:"registry"`
+}
+
+// RegistryConfig 注册中心（使用 etcd 还是 Nacos 由 profile 中的 registry 端口决定）
+type RegistryConfig struct {
+	Endpoints  []string `yaml:"endpoints"`   // 注册中心节点（host:port），按顺序尝试
+	Namespace  string   `yaml:"namespace"`   // Nacos 命名空间（etcd 不使用）
+	TTLSeconds int      `yaml:"ttl_seconds"` // 实例的存活时间（为 0 时 10 秒），每 1/3 TTL 续约一次
+}
+
+// AuthorizationConfig 授权白名单（内部服务名；终端用户只能查询自己的推荐）
+type AuthorizationConfig struct {
+	OnBehalfServices      []string `yaml:"on_behalf_services"`      // 可以代表任意用户查询推荐
+	PrivacyServices       []string `yaml:"privacy_services"`        // 可以调用用户数据删除、导出接口
+	QualityReviewServices []string `yaml:"quality_review_services"` // 可以调用推荐解释接口
+	AdminServices         []string `yaml:"admin_services"`          // 可以调用管理接口（审计日志、效果统计、策略权重）
+	FollowWriteServices   []string `yaml:"follow_write_services"`   // 可以代表任意用户关注、取关（为空时只有用户自己可以）
+}
+
+// MTLSConfig 应用层 mTLS（没有服务网格的环境使用；cert_file 为空时不开启）
+//
+// 证书文件定期检查，轮换后新连接自动使用新证书。入站、出站分别校验对端身份（证书中的 SPIFFE ID）。
+type MTLSConfig struct {
+	CertFile       string        `yaml:"cert_file"`       // 本服务证书（可以是 spiffe-helper 写出的 SVID），为空时不开启
+	KeyFile        string        `yaml:"key_file"`        // 本服务私钥
+	CAFile         string        `yaml:"ca_file"`         // 校验对端证书的 CA
+	ReloadInterval time.Duration `yaml:"reload_interval"` // 检查证书文件变化的间隔，为 0 时 1 分钟
+
+	// Clients 入站：允许的调用方 SPIFFE ID → 服务名（授权白名单中使用的名字），为空时只校验 CA
+	Clients map[string]string `yaml:"clients"`
+	// Servers 出站：下游（http_clients 的端口名、rpc_clients 的服务名）→ 下游证书中的 SPIFFE ID，没有列出时按主机名校验
+	Servers map[string]string `yaml:"servers"`
+}
+
+// Enabled 是否开启 mTLS
+func (c MTLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// SecretsConfig 密钥读取（vault_address 为空时从环境变量读取，本地开发使用）
+type SecretsConfig struct {
+	VaultAddress string            `yaml:"vault_address"` // 如 https://vault.internal:8200，访问令牌来自环境变量 VAULT_TOKEN
+	Paths        SecretPathsConfig `yaml:"paths"`
+}
+
+// SecretPathsConfig 各类密钥的路径（为空时使用括号中的默认路径）
+type SecretPathsConfig struct {
+	Database      string `yaml:"database"`       // 动态数据库账号 username、password（database/creds/recommendation），轮换后连接池换用新账号
+	Redis         string `yaml:"redis"`          // Redis 密码 password（secret/data/redis）
+	LogRedaction  string `yaml:"log_redaction"`  // 日志脱敏的哈希密钥 hash_key（log/redaction）
+	IdentityToken string `yaml:"identity_token"` // 身份令牌的签名密钥 signing_key（auth/identity-token）
+}
+
+// LogConfig 日志（目前只读取脱敏配置，日志和链路追踪共用）
+type LogConfig struct {
+	Redaction RedactionConfig `yaml:"redaction"`
+}
+
+// RedactionConfig 日志和链路追踪的脱敏
+type RedactionConfig struct {
+	Environment string   `yaml:"environment"`  // 为 production 时 allowlist 不生效
+	UserIDMode  string   `yaml:"user_id_mode"` // hash（带密钥哈希）/ mask（完全掩码），为空时 hash
+	HashKey     string   `yaml:"hash_key"`     // 哈希密钥，secrets 中有 hash_key 时使用 secrets 的；都没有时用户ID完全掩码
+	Allowlist   []string `yaml:"allowlist"`    // 调试环境保留原文的字段，如 [user_id]
+}
+
+// EncryptionConfig 敏感字段加密（持久化层写入前加密，只在当前环境使用 MySQL 时生效）
+type EncryptionConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	KMSKeyRing        string        `yaml:"kms_key_ring"`       // 密钥环在 secrets 中的路径：密钥ID → base64 编码的 32 字节密钥
+	CurrentKeyID      string        `yaml:"current_key_id"`     // 新数据使用的密钥
+	BlindIndexKeyID   string        `yaml:"blind_index_key_id"` // 盲索引（按加密字段查找）使用的密钥，不随 current_key_id 轮换
+	ReencryptInterval time.Duration `yaml:"reencrypt_interval"` // 重新加密任务的间隔，为 0 时 1 小时
+}
+
+// RetentionConfig 数据保留期（由定时清理任务删除超期数据）
+type RetentionConfig struct {
+	Interval time.Duration            `yaml:"interval"` // 清理任务的间隔，为 0 时 24 小时
+	MaxAge   map[string]time.Duration `yaml:"max_age"`  // 数据类别（impressions、feedback、recommendations）→ 保留期，没有列出的类别使用默认值
+}
+
+// LoadSheddingConfig 负载保护（Handler 入口处的过载拒绝，数值为 0 时使用括号中的默认值）
+type LoadSheddingConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	MaxInFlight      int           `yaml:"max_in_flight"`      // 最多同时生成推荐的请求数（100）
+	MaxQueue         int           `yaml:"max_queue"`          // 最多排队等待的请求数（200）
+	QueueTimeout     time.Duration `yaml:"queue_timeout"`      // 最长排队时间（50ms）
+	MinExecution     time.Duration `yaml:"min_execution"`      // 剩余时间不足这个值的请求不再排队（100ms）
+	LowPriorityLimit int           `yaml:"low_priority_limit"` // 低优先级请求最多使用的名额（max_in_flight 的 80%）
+}
+
+// AbuseDetectionConfig 异常请求检测（阈值为 0 表示不检查）
+type AbuseDetectionConfig struct {
+	Enabled            bool          `yaml:"enabled"`
+	Window             time.Duration `yaml:"window"` // 统计窗口，为 0 时 1 分钟
+	MaxCallerRequests  int           `yaml:"max_caller_requests"`
+	MaxDistinctTargets int           `yaml:"max_distinct_targets"`
+	MaxTargetRequests  int           `yaml:"max_target_requests"`
+	MaxBatchSize       int           `yaml:"max_batch_size"`
+	BlockDuration      time.Duration `yaml:"block_duration"` // 调用方异常时封禁多久（0：只告警）
+}
+
+// HTTPServerConfig JSON REST 接口（和 Kitex 服务并列，供只能发 HTTP 请求的内部管理后台使用）
+type HTTPServerConfig struct {
+	Addr                   string `yaml:"addr"`                     // 监听地址（如 ":8080"），为空时不启动
+	MetricsAddr            string `yaml:"metrics_addr"`             // 监控指标（GET /metrics）的监听地址（如 ":9090"，只对监控网段开放），为空时不输出
+	ShutdownTimeoutSeconds int    `yaml:"shutdown_timeout_seconds"` // 停止时最多等待处理中的请求多久，为 0 时 10 秒
+}
+
+// RecommendationCacheConfig 推荐列表缓存（生成器的结果，按用户 + 推荐策略缓存）
+type RecommendationCacheConfig struct {
+	TTLSeconds int `yaml:"ttl_seconds"` // 列表从生成开始缓存多久，为 0 时不缓存（cache 端口为 redis 时多实例共享）
+}
+
+// HotCacheConfig 进程内热点缓存（ristretto，位于 Redis 之前）
+type HotCacheConfig struct {
+	Followings              HotCacheSizeConfig `yaml:"followings"`               // 关注列表（成本按列表长度计算）：默认 1000000、30 秒，TTL 应比 social_graph_cache.ttl_seconds 短
+	UserInfo                HotCacheSizeConfig `yaml:"user_info"`                // 用户资料（每个用户成本为 1）：默认 100000、60 秒
+	RecommendationSnapshots HotCacheSizeConfig `yaml:"recommendation_snapshots"` // 推荐列表快照（成本按快照份数计算）：默认 500000、600 秒
+}
+
+// HotCacheSizeConfig 一个进程内缓存的容量和过期时间（为 0 时使用默认值）
+type HotCacheSizeConfig struct {
+	MaxCost    int64 `yaml:"max_cost"`    // 总成本上限
+	TTLSeconds int   `yaml:"ttl_seconds"` // 条目过期时间
+}
+
+// SocialGraphCacheConfig 社交图谱的读穿透缓存（关注列表、最近关注，进程内 → Redis → 数据库）
+//
+// cache 端口不为 redis 时只使用进程内缓存；失效消息需要 Redis。
+type SocialGraphCacheConfig struct {
+	Disabled            bool   `yaml:"disabled"`             // 为 true 时直接查询社交图谱仓储（排查缓存问题时使用）
+	TTLSeconds          int    `yaml:"ttl_seconds"`          // Redis 中缓存多久，为 0 时 120 秒
+	InvalidationChannel string `yaml:"invalidation_channel"` // 失效消息的 Pub/Sub channel，为空时其他实例的进程内缓存随 TTL 过期
+}
+
+// DegradationConfig 一个依赖的降级策略
+type DegradationConfig struct {
+	Order      []string `yaml:"order"`       // 依次尝试的来源（content、reason_text：remote / local），为空时 [remote, local]
+	TimeoutMS  int      `yaml:"timeout_ms"`  // 每个来源的超时（毫秒），为 0 时跟随请求
+	ServeStale bool     `yaml:"serve_stale"` // 所有来源都失败时使用过期的数据（只有 ranking 支持：召回排序失败时使用最近的快照）
+
+	SourceTimeoutsMS map[string]int `yaml:"source_timeouts_ms"` // 单独指定某个来源的超时（毫秒），没有列出的来源使用 timeout_ms
+	SkipUnhealthy    bool           `yaml:"skip_unhealthy"`     // 远程来源不健康（熔断器打开、成功率过低）时直接跳过（只有 content 支持）
+}
+
+// SurfaceConfig 一个展示场景的推荐行为（零值表示不调整）
+type SurfaceConfig struct {
+	MaxLimit          int                     `yaml:"max_limit"`           // 每次最多返回多少条
+	ExpiryHours       int                     `yaml:"expiry_hours"`        // 推荐的有效期，为 0 时 7 天
+	MaxPerReasonType  int                     `yaml:"max_per_reason_type"` // 同一种推荐理由最多多少条
+	MaxPerReferrer    int                     `yaml:"max_per_referrer"`    // 只由同一个你关注的人带来的推荐最多多少条
+	RepeatWindowDays  int                     `yaml:"repeat_window_days"`  // 最近几天展示过的推荐对象排到后面（新面孔不够一页时才补上），为 0 时不调整
+	PreviewPosts      int                     `yaml:"preview_posts"`       // 每条推荐带几篇最近的帖子，为 0 时 3 篇
+	PreviewLength     int                     `yaml:"preview_length"`      // 帖子预览最多多少个字符，为 0 时不截断
+	RecencyDays       int                     `yaml:"recency_days"`        // 请求没有指定时的时间窗口（最近几天的关注、帖子），为 0 时 7 天
+	CandidatePipeline CandidatePipelineConfig `yaml:"candidate_pipeline"`  // 这个场景的候选来源和过滤器，为空时使用全局的 candidate_pipeline
+	Interleaving      InterleavingConfig      `yaml:"interleaving"`        // 这个场景上的交错实验，experiment 为空时不做实验
+}
+
+// InterleavingConfig 交错实验：比较两套打分权重的排序效果
+type InterleavingConfig struct {
+	Experiment     string              `yaml:"experiment"`      // 实验名（写入曝光记录，最长 32 个字符）
+	TrafficPercent int                 `yaml:"traffic_percent"` // 参与实验的用户比例（0~100）
+	RankerA        TenantScoringConfig `yaml:"ranker_a"`        // 排序器 A 的打分权重（通常是线上的权重）
+	RankerB        TenantScoringConfig `yaml:"ranker_b"`        // 排序器 B 的打分权重
+}
+
+// ExperimentConfig A/B 实验：按用户分组，比较推荐策略、打分权重、理由文案
+type ExperimentConfig struct {
+	Name     string                    `yaml:"name"`     // 实验名（写入曝光记录、反馈事件和响应元数据，最长 32 个字符）
+	Surfaces []string                  `yaml:"surfaces"` // 参与实验的展示场景，为空时所有场景（一个场景同时最多一个实验）
+	Sticky   bool                      `yaml:"sticky"`   // 保存用户第一次分到的分组（调整比例时已经参与的用户不换组）
+	Variants []ExperimentVariantConfig `yaml:"variants"` // 分组，比例之和不超过 100（剩下的用户不参与实验）
+}
+
+// ExperimentVariantConfig 实验的一个分组（各项为空时和不参与实验相同，用作对照组）
+type ExperimentVariantConfig struct {
+	Name              string                  `yaml:"name"`                // 分组名（最长 32 个字符）
+	Percent           int                     `yaml:"percent"`             // 分到这个分组的用户比例（0~100）
+	CandidatePipeline CandidatePipelineConfig `yaml:"candidate_pipeline"`  // 这个分组的候选来源和过滤器，为空时使用场景的
+	Scoring           TenantScoringConfig     `yaml:"scoring"`             // 这个分组的打分权重，都为 0 时使用场景的排序
+	ReasonTextVariant string                  `yaml:"reason_text_variant"` // 理由文案的版本（配置服务按版本返回文案），为空时使用默认文案
+}
+
+// TrafficCaptureConfig 流量抓取（排查排序问题时临时开启，抓取结果用 -replay 回放）
+type TrafficCaptureConfig struct {
+	SampleRate float64 `yaml:"sample_rate"` // 采样比例（0~1），为 0 时不抓取
+	Dir        string  `yaml:"dir"`         // 抓取文件写入的本地目录
+}
+
+// HydrationConfig 补全展示数据（用户资料、帖子）
+type HydrationConfig struct {
+	ProfileMiss string `yaml:"profile_miss"` // user 服务没有返回资料时：drop（默认）/ backfill / placeholder
+}
+
+// PostTimestampsConfig 帖子发布时间的协议格式
+type PostTimestampsConfig struct {
+	OmitLegacy bool `yaml:"omit_legacy"` // 不再填充旧格式的 created_at（所有客户端都改用 created_at_rfc3339 之后开启）
+}
+
+// FeatureLogConfig 特征快照日志（离线训练排序模型使用）
+type FeatureLogConfig struct {
+	SampleRate float64 `yaml:"sample_rate"` // 请求的采样比例（0~1），为 0 时不记录
+	Dir        string  `yaml:"dir"`         // 写入的本地目录
+}
+
+// DomainEventsConfig 领域事件（如推荐反馈）的去处
+type DomainEventsConfig struct {
+	Dir string `yaml:"dir"` // 写入的本地目录（每个事件一行 JSON，由日志采集转发），为空时不发布
+}
+
+// FollowEventsConfig 社交服务关注事件的消费（消费者组读取 Kafka topic 或 Redis Stream）
+//
+// 收到关注、取关事件后删除关注者的推荐列表缓存，从已经保存的推荐列表中去掉新关注的用户，并做归因。
+type FollowEventsConfig struct {
+	Backend    string   `yaml:"backend"`     // kafka / redis（为空时 redis）
+	Brokers    []string `yaml:"brokers"`     // Kafka 集群地址（backend 为 kafka 时必填）
+	Topic      string   `yaml:"topic"`       // 关注事件的 Kafka topic（backend 为 kafka 时使用），为空时不消费
+	Stream     string   `yaml:"stream"`      // 关注事件的 Stream（backend 为 redis 时使用），为空时不消费
+	Group      string   `yaml:"group"`       // 消费者组，为空时 recommendation-service（同一个组的实例分担消息）
+	Consumer   string   `yaml:"consumer"`    // 组内成员名（只用于 Redis Stream，Kafka 由协调者分配），为空时使用主机名
+	DeadLetter string   `yaml:"dead_letter"` // 死信 topic / Stream（和 backend 相同），为空时处理失败的事件只记录日志
+
+	MaxAttempts      int `yaml:"max_attempts"`       // 一条事件最多处理几次，为 0 时 5 次
+	BackoffMillis    int `yaml:"backoff_millis"`     // 第一次重试前的等待时间（之后每次翻倍），为 0 时 100ms
+	ClaimIdleSeconds int `yaml:"claim_idle_seconds"` // 其他实例超过这个时间没有确认的事件被接管（只用于 Redis Stream），为 0 时 5 分钟
+}
+
+// PrecomputeConfig 推荐列表预计算：定期为最近活跃的用户生成并保存推荐列表
+//
+// 需要推荐列表仓储（recommendations 端口不为 none）和活跃用户（active_users 端口不为 none）；
+// 在线请求在 priority.list_max_age_seconds 内直接使用保存的列表，所以 interval_seconds 必须比它短。
+type PrecomputeConfig struct {
+	Enabled             bool `yaml:"enabled"`
+	IntervalSeconds     int  `yaml:"interval_seconds"`      // 每轮的间隔，为 0 时 5 分钟
+	ActiveWindowSeconds int  `yaml:"active_window_seconds"` // 多久以内请求过推荐的用户算作活跃，为 0 时 24 小时
+	MaxUsers            int  `yaml:"max_users"`             // 每个租户每轮最多处理的用户数（最近活跃的优先），为 0 时 10000
+	Concurrency         int  `yaml:"concurrency"`           // 同时生成的用户数，为 0 时 4
+}
+
+// ScoreCalibrationConfig 分数校准（原始分数 → 不同策略、不同时间之间可比较的校准分数）
+type ScoreCalibrationConfig struct {
+	File          string `yaml:"file"`           // 校准参数文件（离线拟合产出），为空时不校准
+	ReloadSeconds int    `yaml:"reload_seconds"` // 检查文件变化的间隔，为 0 时 60 秒
+}
+
+// DualReadConfig 存储迁移期间的双读比较：同时查询另一个实现，异步比较结果、上报差异
+//
+// 返回给调用方的始终是 profile 中选择的实现（主实现）的结果；
+// 新实现稳定之后在 profile 中切换为新实现，旧实现改为 shadow，再观察一段时间后去掉。
+type DualReadConfig struct {
+	Shadow     Implementation `yaml:"shadow"`      // 同时查询的实现（必须是端口支持的、和主实现不同的实现）
+	SampleRate float64        `yaml:"sample_rate"` // 比较的请求比例（0~1），为 0 时全部比较
+}
+
+// StrategyBanditConfig 策略权重 bandit（按各展示场景的转化反馈调整各策略的混合权重）
+type StrategyBanditConfig struct {
+	Enabled        bool    `yaml:"enabled"`         // 关闭时不调整权重（全局开关；单个场景可以在管理接口中关闭）
+	MinWeight      float64 `yaml:"min_weight"`      // 权重下限（探索的保底），为 0 时 0.5
+	MaxWeight      float64 `yaml:"max_weight"`      // 权重上限，为 0 时 2
+	Exploration    float64 `yaml:"exploration"`     // UCB 探索系数，为 0 时 1
+	MinPulls       int64   `yaml:"min_pulls"`       // 曝光少于这个数的策略按上限权重探索，为 0 时 1000
+	RefreshSeconds int     `yaml:"refresh_seconds"` // 写入统计、重新计算权重的间隔，为 0 时 60 秒
+}
+
+// HybridConfig 混合推荐（GetHybridRecommendations）
+type HybridConfig struct {
+	Weights map[string]float64 `yaml:"weights"` // 策略（following、popularity）→ 权重，没有列出的策略使用默认权重，为 0 时不执行
+	Fusion  string             `yaml:"fusion"`  // 融合方式：weighted（加权合并，默认）、rrf（倒数排名融合）
+	RRFK    int                `yaml:"rrf_k"`   // 倒数排名融合的平滑常数，为 0 时 60
+}
+
+// PriorityConfig 用户优先级（推荐的新鲜度、过载时的处理）
+type PriorityConfig struct {
+	DefaultTier         string                        `yaml:"default_tier"`          // 没有列出的用户的优先级，为空时 standard
+	Users               map[string]map[string][]int64 `yaml:"users"`                 // 租户 → 优先级 → 用户
+	MaxStalenessSeconds int                           `yaml:"max_staleness_seconds"` // 快照在这个时间内直接使用（high 除外），为 0 时只在过载时使用
+	ListMaxAgeSeconds   int                           `yaml:"list_max_age_seconds"`  // 持久化的推荐列表在这个时间内直接使用（high 除外），为 0 时只保存不使用
+}
+
+// WarmupConfig 启动预热
+type WarmupConfig struct {
+	TimeoutSeconds int                `yaml:"timeout_seconds"` // 整个预热的超时，为 0 时 30 秒
+	Cohort         map[string][]int64 `yaml:"cohort"`          // 租户 → 预生成推荐的用户（为空时不预生成）
+}
+
+// TenantConfig 租户（App 品牌）配置
+//
+// 默认租户（default）不需要列出；列出时覆盖它的文案语言和打分权重。
+type TenantConfig struct {
+	ID      string              `yaml:"id"`
+	Locale  string              `yaml:"locale"` // 推荐理由文案的语言，为空时由配置服务决定
+	Scoring TenantScoringConfig `yaml:"scoring"`
+}
+
+// TenantScoringConfig 打分权重（都为 0 时使用默认权重）
+type TenantScoringConfig struct {
+	PerRelatedUser       int `yaml:"per_related_user"`       // 每个相关用户的分数
+	PerRecentPost        int `yaml:"per_recent_post"`        // 每篇最近帖子的分数
+	PerHundredEngagement int `yaml:"per_hundred_engagement"` // 每 100 个收到的互动分（点赞 1、评论 3）的分数，为 0 时不加分
+}
+
+// CandidatePipelineConfig 启用的候选来源和过滤器（按名称，按顺序执行）
+//
+// Sources 为空时使用默认（following 来源 + trust 过滤器）；
+// Sources 不为空时 Filters 就是全部过滤器（为空表示不过滤）。
+type CandidatePipelineConfig struct {
+	Sources []string `yaml:"sources"`
+	Filters []string `yaml:"filters"`
+}
+
+// FanoutConfig 推荐生成时的扇出查询（逐个查询关注的人最近关注了谁、每个候选的帖子数）
+type FanoutConfig struct {
+	Concurrency   int `yaml:"concurrency"`     // 同时执行的仓储查询数，为 0 时 16，为 1 时串行
+	CallTimeoutMS int `yaml:"call_timeout_ms"` // 每次查询的超时，超时的查询按失败跳过；为 0 时只受请求超时限制
+}
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	MySQL MySQLConfig `yaml:"mysql"`
+}
+
+// MySQLConfig MySQL 配置（生产环境的账号密码来自 secrets，这里的只用于本地开发）
+type MySQLConfig struct {
+	Host            string `yaml:"host"`
+	Port            int    `yaml:"port"`
+	User            string `yaml:"user"`
+	Password        string `yaml:"password"`
+	Database        string `yaml:"database"`
+	Charset         string `yaml:"charset"`
+	MaxIdleConns    int    `yaml:"max_idle_conns"`
+	MaxOpenConns    int    `yaml:"max_open_conns"`
+	ConnMaxLifetime int    `yaml:"conn_max_lifetime"` // 秒
+}
+
+// DSN 连接串（user、password 由调用方传入，可能来自 secrets）
+func (m MySQLConfig) DSN(user, password string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=%s&parseTime=True&loc=Local",
+		user, password, net.JoinHostPort(m.Host, strconv.Itoa(m.Port)), m.Database, m.Charset)
+}
+
+// RedisConfig Redis 配置
+type RedisConfig struct {
+	Host         string `yaml:"host"`
+	Port         int    `yaml:"port"`
+	Password     string `yaml:"password"`
+	DB           int    `yaml:"db"`
+	PoolSize     int    `yaml:"pool_size"`
+	MinIdleConns int    `yaml:"min_idle_conns"`
+}
+
+// Addr host:port
+func (r RedisConfig) Addr() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+}
+
+// Load 读取配置文件
+//
+// 文件中的 ${VAR} 会替换为环境变量；
+// 设置了 APP_PROFILE 时覆盖文件中的 profile，两者都为空时使用 dev。
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	if profile := os.Getenv(ProfileEnv); profile != "" {
+		cfg.Profile = profile
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = DefaultProfile
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Default 没有配置文件时的配置：dev 环境，所有端口使用默认实现（mock、内存）
+//
+// 授权白名单和 config.yaml 相同（本地调试 BFF 需要代表用户查询）；
+// 负载保护使用默认参数，异常请求检测关闭。
+func Default() *Config {
+	return &Config{
+		Profile:  DefaultProfile,
+		Profiles: map[string]Profile{DefaultProfile: {}},
+		Authorization: AuthorizationConfig{
+			OnBehalfServices:      []string{"push-pipeline", "bff", "ops-dashboard"},
+			PrivacyServices:       []string{"privacy-tooling"},
+			QualityReviewServices: []string{"quality-review"},
+		},
+		LoadShedding: LoadSheddingConfig{Enabled: true},
+	}
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Port 需要选择实现的依赖（端口）
+type Port string
+
+const (
+	PortUserRPC           Port = "user_rpc"            // 用户服务客户端
+	PortContentService    Port = "content_service"     // 内容服务客户端
+	PortReasonConfig      Port = "reason_config"       // 推荐理由配置服务客户端
+	PortTrust             Port = "trust"               // 风控分级
+	PortSocialGraph       Port = "social_graph"        // 社交图谱仓储
+	PortContentRepository Port = "content_repository"  // 内容仓储
+	PortImpressions       Port = "impressions"         // 曝光记录仓储
+	PortUserErasures      Port = "user_erasures"       // 用户数据删除记录仓储
+	PortAuditLog          Port = "audit_log"           // 审计日志仓储
+	PortRecentFollowIndex Port = "recent_follow_index" // 反向关注索引
+	PortCache             Port = "cache"               // 热点缓存的第二级（进程内缓存之后）
+	PortTopics            Port = "topics"              // 话题关注仓储
+	PortGroups            Port = "groups"              // 小组成员关系仓储
+	PortInteractions      Port = "interactions"        // 互动记录仓储（最近一次互动的时间）
+	PortEngagement        Port = "engagement"          // 实时互动信号（最近一小时的点赞、评论）
+	PortBlocks            Port = "blocks"              // 屏蔽关系仓储
+	PortRecommendations   Port = "recommendations"     // 推荐列表仓储（生成过的推荐列表）
+	PortPopularUsers      Port = "popular_users"       // 冷启动名单（全站热门、编辑精选）
+	PortActiveUsers       Port = "active_users"        // 最近活跃的用户（预计算推荐列表的对象）
+	PortRegistry          Port = "registry"            // 服务注册与发现（server.registry）
+)
+
+// Implementation 端口的实现
+type Implementation string
+
+const (
+	ImplMock   Implementation = "mock"   // 固定的示例数据
+	ImplMemory Implementation = "memory" // 进程内存储（重启丢失）
+	ImplMySQL  Implementation = "mysql"  // database.mysql
+	ImplRedis  Implementation = "redis"  // redis
+	ImplHTTP   Implementation = "http"   // http_clients 中的地址
+	ImplNone   Implementation = "none"   // 不使用（可选依赖）
+	ImplEtcd   Implementation = "etcd"   // server.registry 中的 etcd 节点
+	ImplNacos  Implementation = "nacos"  // server.registry 中的 Nacos 节点
+)
+
+// dualReadPorts 支持双读比较的端口（仓储有对应的双读装饰器）
+var dualReadPorts = []Port{PortSocialGraph}
+
+// supported 每个端口支持的实现，第一个是默认值（profile 中没有配置时使用）
+//
+// 只列出仓库中已有的实现：
+// user_rpc 只有 mock（user 服务的 Kitex 客户端还没有生成），
+// content_service 的 RPC 客户端也只是示例骨架，暂不开放。
+var supported = []struct {
+	port  Port
+	impls []Implementation
+}{
+	{PortUserRPC, []Implementation{ImplMock}},
+	{PortContentService, []Implementation{ImplNone, ImplHTTP}},
+	{PortReasonConfig, []Implementation{ImplNone, ImplHTTP}},
+	{PortTrust, []Implementation{ImplMock, ImplHTTP}},
+	{PortSocialGraph, []Implementation{ImplMock, ImplMySQL}},
+	{PortContentRepository, []Implementation{ImplMock, ImplMySQL}},
+	{PortImpressions, []Implementation{ImplMemory, ImplMySQL}},
+	{PortUserErasures, []Implementation{ImplMemory, ImplMySQL}},
+	{PortAuditLog, []Implementation{ImplMemory, ImplMySQL}},
+	{PortRecentFollowIndex, []Implementation{ImplNone, ImplRedis}},
+	{PortCache, []Implementation{ImplNone, ImplRedis}},
+	{PortTopics, []Implementation{ImplMock, ImplMySQL}},
+	{PortGroups, []Implementation{ImplMock, ImplMySQL}},
+	{PortInteractions, []Implementation{ImplMock, ImplMySQL}},
+	{PortEngagement, []Implementation{ImplNone, ImplHTTP}},
+	{PortBlocks, []Implementation{ImplMock, ImplMySQL}},
+	{PortRecommendations, []Implementation{ImplNone, ImplMemory, ImplMySQL}},
+	{PortPopularUsers, []Implementation{ImplMock, ImplMySQL, ImplNone}},
+	{PortActiveUsers, []Implementation{ImplNone, ImplMemory, ImplRedis}},
+	{PortRegistry, []Implementation{ImplNone, ImplEtcd, ImplNacos}},
+}
+
+// Profile 一个环境（dev、staging、prod）中每个端口使用的实现
+type Profile map[Port]Implementation
+
+// WiringEntry 生效的装配：一个端口使用的实现和目标地址
+type WiringEntry struct {
+	Port           Port
+	Implementation Implementation
+	Target         string // MySQL / Redis 的主机、HTTP 服务的地址；mock、memory、none 为空
+}
+
+// Implementation 返回当前 profile 中端口使用的实现（没有配置时返回默认值）
+func (c *Config) Implementation(port Port) Implementation {
+	if impl, ok := c.Profiles[c.Profile][port]; ok {
+		return impl
+	}
+	for _, s := range supported {
+		if s.port == port {
+			return s.impls[0]
+		}
+	}
+	return ImplNone
+}
+
+// ShadowImplementation 返回当前 profile 中端口双读比较的实现（没有配置双读时返回空）
+func (c *Config) ShadowImplementation(port Port) Implementation {
+	return c.DualRead[port].Shadow
+}
+
+// Uses 当前 profile 中是否有端口使用了 impl（包括双读比较的实现）
+func (c *Config) Uses(impl Implementation) bool {
+	for _, s := range supported {
+		if c.Implementation(s.port) == impl || c.ShadowImplementation(s.port) == impl {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsDatabase 是否需要连接 MySQL
+//
+// 反向关注索引存在 Redis 中，但写扩散时要从数据库查询粉丝列表。
+func (c *Config) NeedsDatabase() bool {
+	return c.Uses(ImplMySQL) || c.Implementation(PortRecentFollowIndex) == ImplRedis
+}
+
+// Validate 检查当前 profile：profile 必须存在，每个端口的实现必须受支持，HTTP 实现、注册中心必须配置地址
+func (c *Config) Validate() error {
+	profile, ok := c.Profiles[c.Profile]
+	if !ok {
+		return fmt.Errorf("config: unknown profile %q", c.Profile)
+	}
+	for port := range profile {
+		if !isKnownPort(port) {
+			return fmt.Errorf("config: profile %q: unknown port %q", c.Profile, port)
+		}
+	}
+	for _, s := range supported {
+		impl := c.Implementation(s.port)
+		if !contains(s.impls, impl) {
+			return fmt.Errorf("config: profile %q: port %s does not support %q (supported: %v)", c.Profile, s.port, impl, s.impls)
+		}
+		if impl == ImplHTTP && c.target(s.port, impl) == "" {
+			return fmt.Errorf("config: profile %q: port %s uses http but http_clients has no URL for it", c.Profile, s.port)
+		}
+		if (impl == ImplEtcd || impl == ImplNacos) && (len(c.Server.Registry.Endpoints) == 0 || slices.Contains(c.Server.Registry.Endpoints, "")) {
+			return fmt.Errorf("config: profile %q: port %s uses %s but server.registry has no endpoints", c.Profile, s.port, impl)
+		}
+	}
+	return c.validateDualRead()
+}
+
+// validateDualRead 辅助方法：双读比较的端口必须支持双读，shadow 必须是端口支持的、和主实现不同的实现
+func (c *Config) validateDualRead() error {
+	for port, dualRead := range c.DualRead {
+		if !containsPort(dualReadPorts, port) {
+			return fmt.Errorf("config: dual_read: port %q does not support dual reads (supported: %v)", port, dualReadPorts)
+		}
+		if !contains(supportedImplementations(port), dualRead.Shadow) || dualRead.Shadow == c.Implementation(port) {
+			return fmt.Errorf("config: dual_read: port %s: shadow %q must be a supported implementation other than %q", port, dualRead.Shadow, c.Implementation(port))
+		}
+		if dualRead.SampleRate < 0 || dualRead.SampleRate > 1 {
+			return fmt.Errorf("config: dual_read: port %s: sample_rate %v out of [0, 1]", port, dualRead.SampleRate)
+		}
+	}
+	return nil
+}
+
+// WiringReport 生效的装配报告（启动时写入日志）
+func (c *Config) WiringReport() []WiringEntry {
+	report := make([]WiringEntry, 0, len(supported))
+	for _, s := range supported {
+		impl := c.Implementation(s.port)
+		report = append(report, WiringEntry{Port: s.port, Implementation: impl, Target: c.target(s.port, impl)})
+	}
+	return report
+}
+
+// target 辅助方法：端口实现的目标地址（不包含账号密码）
+func (c *Config) target(port Port, impl Implementation) string {
+	switch impl {
+	case ImplMySQL:
+		return net.JoinHostPort(c.Database.MySQL.Host, strconv.Itoa(c.Database.MySQL.Port)) + "/" + c.Database.MySQL.Database
+	case ImplRedis:
+		return c.Redis.Addr()
+	case ImplHTTP:
+		return c.HTTPClients[port]
+	case ImplEtcd, ImplNacos:
+		return strings.Join(c.Server.Registry.Endpoints, ",")
+	}
+	return ""
+}
+
+// isKnownPort 辅助函数
+func isKnownPort(port Port) bool {
+	for _, s := range supported {
+		if s.port == port {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedImplementations 辅助函数：端口支持的实现
+func supportedImplementations(port Port) []Implementation {
+	for _, s := range supported {
+		if s.port == port {
+			return s.impls
+		}
+	}
+	return nil
+}
+
+// containsPort 辅助函数
+func containsPort(ports []Port, port Port) bool {
+	for _, candidate := range ports {
+		if candidate == port {
+			return true
+		}
+	}
+	return false
+}
+
+// contains 辅助函数
+func contains(impls []Implementation, impl Implementation) bool {
+	for _, candidate := range impls {
+		if candidate == impl {
+			return true
+		}
+	}
+	return false
+}
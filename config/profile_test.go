@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+profile: dev
+database:
+  mysql:
+    host: ${TEST_MYSQL_HOST}
+    port: 3306
+    database: recommendation
+http_clients:
+  trust: http://trust-safety:8080
+profiles:
+  dev:
+    social_graph: mock
+  prod:
+    social_graph: mysql
+    impressions: mysql
+    trust: http
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_ProfileSelectsImplementations(t *testing.T) {
+	t.Setenv("TEST_MYSQL_HOST", "mysql.prod")
+	t.Setenv(ProfileEnv, "prod")
+
+	cfg, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "prod" {
+		t.Fatalf("profile = %q, want prod (APP_PROFILE overrides the file)", cfg.Profile)
+	}
+
+	want := map[Port]WiringEntry{
+		PortSocialGraph: {PortSocialGraph, ImplMySQL, "mysql.prod:3306/recommendation"},
+		PortTrust:       {PortTrust, ImplHTTP, "http://trust-safety:8080"},
+		PortAuditLog:    {PortAuditLog, ImplMemory, ""}, // 没有配置，使用默认实现
+	}
+	for _, entry := range cfg.WiringReport() {
+		if w, ok := want[entry.Port]; ok && entry != w {
+			t.Errorf("wiring %s = %+v, want %+v", entry.Port, entry, w)
+		}
+	}
+	if !cfg.NeedsDatabase() {
+		t.Error("prod profile uses mysql, NeedsDatabase() = false")
+	}
+}
+
+func TestLoad_RejectsUnsupportedChoices(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	tests := map[string]string{
+		"unknown profile":            "profile: qa\nprofiles:\n  dev: {}\n",
+		"unknown port":               "profiles:\n  dev:\n    kafka: mock\n",
+		"unsupported impl":           "profiles:\n  dev:\n    user_rpc: mysql\n",
+		"http without url":           "profiles:\n  dev:\n    trust: http\n",
+		"registry without endpoints": "profiles:\n  dev:\n    registry: etcd\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("Load() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestLoad_DualRead(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	cfg, err := Load(writeConfig(t, "profiles:\n  dev:\n    social_graph: mock\ndual_read:\n  social_graph:\n    shadow: mysql\n    sample_rate: 0.1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.ShadowImplementation(PortSocialGraph); got != ImplMySQL {
+		t.Errorf("shadow = %q, want mysql", got)
+	}
+	if !cfg.NeedsDatabase() {
+		t.Error("shadow uses mysql, NeedsDatabase() = false")
+	}
+
+	invalid := map[string]string{
+		"unsupported port":    "dual_read:\n  topics:\n    shadow: mysql\n",
+		"same implementation": "dual_read:\n  social_graph:\n    shadow: mock\n",
+		"unsupported shadow":  "dual_read:\n  social_graph:\n    shadow: redis\n",
+		"sample rate":         "dual_read:\n  social_graph:\n    shadow: mysql\n    sample_rate: 2\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, "profiles:\n  dev: {}\n"+content)); err == nil {
+				t.Error("Load() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestLoad_ServerAndProtectionSettings(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	cfg, err := Load(writeConfig(t, `
+profiles:
+  dev: {}
+server:
+  port: 9999
+authorization:
+  on_behalf_services: [bff]
+load_shedding:
+  enabled: true
+  queue_timeout: 50ms
+abuse_detection:
+  window: 1m
+  block_duration: 5m
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 9999 {
+		t.Errorf("server.port = %d, want 9999", cfg.Server.Port)
+	}
+	if got := cfg.Authorization.OnBehalfServices; len(got) != 1 || got[0] != "bff" {
+		t.Errorf("on_behalf_services = %v, want [bff]", got)
+	}
+	if cfg.LoadShedding.QueueTimeout != 50*time.Millisecond {
+		t.Errorf("queue_timeout = %v, want 50ms", cfg.LoadShedding.QueueTimeout)
+	}
+	if cfg.AbuseDetection.Enabled || cfg.AbuseDetection.BlockDuration != 5*time.Minute {
+		t.Errorf("abuse_detection = %+v, want disabled with 5m block", cfg.AbuseDetection)
+	}
+}
+
+func TestLoad_Retention(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	cfg, err := Load(writeConfig(t, `
+profiles:
+  dev: {}
+retention:
+  interval: 12h
+  max_age:
+    impressions: 2160h
+    feedback: 8760h
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Retention.Interval != 12*time.Hour {
+		t.Errorf("retention.interval = %v, want 12h", cfg.Retention.Interval)
+	}
+	if got := cfg.Retention.MaxAge["impressions"]; got != 90*24*time.Hour {
+		t.Errorf("max_age.impressions = %v, want 2160h", got)
+	}
+	if _, ok := cfg.Retention.MaxAge["recommendations"]; ok {
+		t.Errorf("max_age.recommendations should be unset (default)")
+	}
+}
+
+func TestLoad_FollowEventsKafka(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	t.Setenv("KAFKA_BROKERS", "kafka-0:9092,kafka-1:9092")
+	cfg, err := Load(writeConfig(t, `
+profiles:
+  dev: {}
+follow_events:
+  backend: kafka
+  brokers: [${KAFKA_BROKERS}]
+  topic: social.follow-events
+  dead_letter: social.follow-events.dead-letter
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := cfg.FollowEvents
+	if events.Backend != "kafka" || events.Topic != "social.follow-events" || events.DeadLetter != "social.follow-events.dead-letter" {
+		t.Errorf("follow_events = %+v", events)
+	}
+	if len(events.Brokers) != 2 || events.Brokers[0] != "kafka-0:9092" || events.Brokers[1] != "kafka-1:9092" {
+		t.Errorf("follow_events.brokers = %v, want both brokers from KAFKA_BROKERS", events.Brokers)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+
+	kitexclient "github.com/cloudwego/kitex/client"
+	kitexdiscovery "github.com/cloudwego/kitex/pkg/discovery"
+	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/cloudwego/kitex/pkg/remote/trans/gonet"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/server"
+
+	"service/config"
+	"service/infrastructure/discovery"
+	"service/infrastructure/mtls"
+)
+
+// serverRegistryOptions Kitex 服务的注册选项（当前环境没有使用注册中心时为空）
+//
+// 注册的服务名是 server.name；Kitex 在开始监听之后注册、Stop 时先注销再等待处理中的请求，
+// 调用方在实例停止之前就不会再发来新请求。
+func serverRegistryOptions(settings *serverSettings) []server.Option {
+	if settings.Registry == nil {
+		return nil
+	}
+	return []server.Option{
+		server.WithServerBasicInfo(&rpcinfo.EndpointBasicInfo{ServiceName: settings.ServiceName}),
+		server.WithRegistry(kitexRegistry{registry: settings.Registry}),
+	}
+}
+
+// rpcClientOptions 下游 Kitex 客户端的寻址、传输选项
+//
+// 使用注册中心时按服务名发现实例（client.WithResolver），否则使用 rpc_clients 中配置的地址。
+// 开启了 mTLS 时通过 TLS 连接下游（按 mtls.servers 中的 SPIFFE ID 校验），同时换成支持 TLS 的 gonet 传输层。
+// 下游的 Kitex 客户端生成之后这样创建（见 provideUserRPCClient）：
+//
+//	userservice.MustNewClient("user-service", rpcClientOptions(cfg, registry, reloader, "user-service")...)
+func rpcClientOptions(cfg *config.Config, reg discovery.Registry, reloader *mtls.Reloader, service string) []kitexclient.Option {
+	var opts []kitexclient.Option
+	if reg != nil {
+		opts = append(opts, kitexclient.WithResolver(kitexResolver{registry: reg}))
+	} else if addr := cfg.RPCClients[service]; addr != "" {
+		opts = append(opts, kitexclient.WithHostPorts(addr))
+	}
+	if reloader != nil {
+		opts = append(opts,
+			kitexclient.WithDialer(mtls.NewDialer(reloader, service, cfg.MTLS.Servers[service])),
+			kitexclient.WithTransHandlerFactory(gonet.NewCliTransHandlerFactory()),
+		)
+	}
+	return opts
+}
+
+// kitexRegistry discovery.Registry → Kitex 的 registry.Registry
+type kitexRegistry struct {
+	registry discovery.Registry
+}
+
+// Register 实现 registry.Registry
+func (r kitexRegistry) Register(info *registry.Info) error {
+	instance, err := registryInstance(info)
+	if err != nil {
+		return err
+	}
+	return r.registry.Register(context.Background(), instance)
+}
+
+// Deregister 实现 registry.Registry
+func (r kitexRegistry) Deregister(info *registry.Info) error {
+	instance, err := registryInstance(info)
+	if err != nil {
+		return err
+	}
+	return r.registry.Deregister(context.Background(), instance)
+}
+
+// registryInstance 辅助函数：Kitex 的注册信息 → 注册的实例（监听 0.0.0.0 时换成本机 IP）
+func registryInstance(info *registry.Info) (discovery.Instance, error) {
+	addr, err := discovery.AdvertiseAddr(info.Addr.String())
+	if err != nil {
+		return discovery.Instance{}, err
+	}
+	return discovery.Instance{
+		Service: info.ServiceName,
+		Addr:    addr,
+		Weight:  info.Weight,
+		Tags:    info.Tags,
+	}, nil
+}
+
+// kitexResolver discovery.Registry → Kitex 的 discovery.Resolver
+//
+// 结果可以缓存（key 是服务名）：Kitex 定期重新解析，实例变化时通过 Diff 通知负载均衡。
+type kitexResolver struct {
+	registry discovery.Registry
+}
+
+// Target 实现 discovery.Resolver：按服务名解析
+func (r kitexResolver) Target(_ context.Context, target rpcinfo.EndpointInfo) string {
+	return target.ServiceName()
+}
+
+// Resolve 实现 discovery.Resolver
+func (r kitexResolver) Resolve(ctx context.Context, desc string) (kitexdiscovery.Result, error) {
+	instances, err := r.registry.Resolve(ctx, desc)
+	if err != nil {
+		return kitexdiscovery.Result{}, err
+	}
+	result := kitexdiscovery.Result{Cacheable: true, CacheKey: desc}
+	for _, instance := range instances {
+		result.Instances = append(result.Instances, kitexdiscovery.NewInstance("tcp", instance.Addr, instance.Weight, instance.Tags))
+	}
+	return result, nil
+}
+
+// Diff 实现 discovery.Resolver
+func (r kitexResolver) Diff(cacheKey string, prev, next kitexdiscovery.Result) (kitexdiscovery.Change, bool) {
+	return kitexdiscovery.DefaultDiff(cacheKey, prev, next)
+}
+
+// Name 实现 discovery.Resolver
+func (r kitexResolver) Name() string {
+	return r.registry.Name()
+}
@@ -0,0 +1,151 @@
+package aggregate
+
+import (
+	"errors"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+var ErrDuplicateGroupRecommendation = errors.New("duplicate group recommendation")
+
+// groupOverlapBonus 成员重合度的最高加分（小组成员全部是"我关注的人"时）
+const groupOverlapBonus = 20
+
+// GroupRecommendation 聚合根：小组（社区）推荐
+//
+// 推荐理由是"你关注的 3 个人是小组成员"，分数由两部分组成：
+// - 和用户推荐相同的推荐信号（是成员的"我关注的人"越多越高）
+// - 成员重合度：这些人占小组成员的比例（见 calculateGroupScore）
+type GroupRecommendation struct {
+	id          valueobject.RecommendationID
+	groupID     valueobject.GroupID
+	reason      valueobject.RecommendationReason
+	memberCount int // 小组成员总数（未知时为 0）
+	score       int
+	createdAt   time.Time
+}
+
+// NewGroupRecommendation 工厂方法：创建新的小组推荐
+//
+// 业务规则：必须有推荐理由（至少 1 个关注的人是小组成员）
+func NewGroupRecommendation(
+	groupID valueobject.GroupID,
+	reason valueobject.RecommendationReason,
+	memberCount int,
+	weights valueobject.ScoringWeights,
+	now time.Time,
+) (*GroupRecommendation, error) {
+	if reason.RelatedCount() == 0 {
+		return nil, ErrNoReasonForRecommendation
+	}
+	return &GroupRecommendation{
+		id:          valueobject.NewRecommendationID(),
+		groupID:     groupID,
+		reason:      reason,
+		memberCount: memberCount,
+		score:       calculateGroupScore(reason, memberCount, weights),
+		createdAt:   now,
+	}, nil
+}
+
+// calculateGroupScore 业务规则：小组推荐的分数
+//
+// 为什么要考虑成员重合度？
+// 同样有 3 个"我关注的人"，一个 10 人的小组几乎就是我的圈子，
+// 一个 10 万人的大组只是恰好有几个熟人，前者更值得推荐。
+// 重合度加分 = groupOverlapBonus × 重合人数 / 成员总数（成员数未知时不加分）。
+func calculateGroupScore(reason valueobject.RecommendationReason, memberCount int, weights valueobject.ScoringWeights) int {
+	score := reason.WeightWith(weights)
+	if memberCount > 0 {
+		overlap := min(reason.RelatedCount(), memberCount)
+		score += groupOverlapBonus * overlap / memberCount
+	}
+	return score
+}
+
+// --- 访问器方法 ---
+
+func (r *GroupRecommendation) ID() valueobject.RecommendationID {
+	return r.id
+}
+
+func (r *GroupRecommendation) GroupID() valueobject.GroupID {
+	return r.groupID
+}
+
+func (r *GroupRecommendation) Reason() valueobject.RecommendationReason {
+	return r.reason
+}
+
+func (r *GroupRecommendation) MemberCount() int {
+	return r.memberCount
+}
+
+func (r *GroupRecommendation) Score() int {
+	return r.score
+}
+
+func (r *GroupRecommendation) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// GroupRecommendationList 聚合：小组推荐列表
+//
+// 和 RecommendationList 相同的列表规则：
+// - 去重：同一个小组只推荐一次
+// - 排序：按分数降序取前 N 个（共用 topByScore）
+// - 记录被排除的候选（如用户已经是成员），用于监控
+type GroupRecommendationList struct {
+	forUserID       valueobject.UserID
+	recommendations []*GroupRecommendation
+	exclusions      map[ExclusionReason]int
+}
+
+// NewGroupRecommendationList 工厂方法
+func NewGroupRecommendationList(forUserID valueobject.UserID) *GroupRecommendationList {
+	return &GroupRecommendationList{
+		forUserID:  forUserID,
+		exclusions: make(map[ExclusionReason]int),
+	}
+}
+
+// AddRecommendation 业务行为：添加推荐（同一个小组不能推荐两次）
+func (l *GroupRecommendationList) AddRecommendation(rec *GroupRecommendation) error {
+	for _, existing := range l.recommendations {
+		if existing.GroupID().Equals(rec.GroupID()) {
+			return ErrDuplicateGroupRecommendation
+		}
+	}
+	l.recommendations = append(l.recommendations, rec)
+	return nil
+}
+
+// GetTopN 业务行为：获取分数最高的 N 个推荐
+func (l *GroupRecommendationList) GetTopN(n int) []*GroupRecommendation {
+	return topByScore(l.recommendations, n)
+}
+
+// RecordExclusion 业务行为：记录一个被排除的候选
+func (l *GroupRecommendationList) RecordExclusion(reason ExclusionReason) {
+	l.exclusions[reason]++
+}
+
+// Exclusions 查询方法：获取按原因统计的排除数量（返回副本）
+func (l *GroupRecommendationList) Exclusions() map[ExclusionReason]int {
+	result := make(map[ExclusionReason]int, len(l.exclusions))
+	for reason, count := range l.exclusions {
+		result[reason] = count
+	}
+	return result
+}
+
+// Count 查询方法：获取推荐数量
+func (l *GroupRecommendationList) Count() int {
+	return len(l.recommendations)
+}
+
+// ForUserID 访问器：获取目标用户ID
+func (l *GroupRecommendationList) ForUserID() valueobject.UserID {
+	return l.forUserID
+}
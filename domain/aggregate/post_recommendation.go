@@ -0,0 +1,117 @@
+package aggregate
+
+import (
+	"errors"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+var ErrPostTooOld = errors.New("post is older than the recommendation window")
+
+// postFreshnessBonus 刚发布的帖子的新鲜度加分（在推荐窗口内线性衰减到 0）
+const postFreshnessBonus = 20
+
+// PostRecommendation 聚合根：帖子推荐
+//
+// 和 UserRecommendation 的区别：
+// UserRecommendation 推荐的是"值得关注的人"，PostRecommendation 推荐的是"值得看的一篇帖子"。
+// 推荐理由仍然来自作者（"你关注的 3 个人也关注了作者"），分数还要考虑帖子本身的新鲜度。
+//
+// 为什么持有帖子实体而不是只持有帖子ID？
+// 帖子在这里是推荐内容的一部分（展示、内容安全都需要它），
+// 生成时已经取回，不需要再按ID查询一次。
+type PostRecommendation struct {
+	id        valueobject.RecommendationID
+	post      *entity.Post
+	reason    valueobject.RecommendationReason // 作者的推荐理由
+	score     int
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// NewPostRecommendation 工厂方法：创建新的帖子推荐
+//
+// 在创建时执行的业务规则：
+// 1. 作者必须有推荐理由（至少1个关注者）
+// 2. 帖子必须在推荐窗口内发布（window 之前的帖子不推荐）
+// 3. 分数 = 作者的推荐信号 + 帖子的新鲜度（见 calculatePostScore）
+// 4. 推荐的有效期和窗口内帖子剩余的新鲜时间一致
+func NewPostRecommendation(
+	post *entity.Post,
+	reason valueobject.RecommendationReason,
+	weights valueobject.ScoringWeights,
+	window time.Duration,
+	now time.Time,
+) (*PostRecommendation, error) {
+	if reason.RelatedCount() == 0 {
+		return nil, ErrNoReasonForRecommendation
+	}
+	age := now.Sub(post.CreatedAt())
+	if age >= window {
+		return nil, ErrPostTooOld
+	}
+
+	return &PostRecommendation{
+		id:        valueobject.NewRecommendationID(),
+		post:      post,
+		reason:    reason,
+		score:     calculatePostScore(reason, age, window, weights),
+		createdAt: now,
+		expiresAt: post.CreatedAt().Add(window),
+	}, nil
+}
+
+// calculatePostScore 业务规则：帖子推荐的分数
+//
+// - 作者的推荐信号：和用户推荐相同（关注作者的"我关注的人"越多越高）
+// - 新鲜度：刚发布的帖子加 postFreshnessBonus 分，在推荐窗口内线性衰减到 0
+//
+// 帖子本身的互动数据（点赞、评论）目前拿不到，接入后在这里加上互动加分。
+func calculatePostScore(
+	reason valueobject.RecommendationReason,
+	age, window time.Duration,
+	weights valueobject.ScoringWeights,
+) int {
+	score := reason.WeightWith(weights)
+	if age < 0 {
+		age = 0
+	}
+	return score + int(postFreshnessBonus*(window-age)/window)
+}
+
+// IsExpired 业务规则：推荐是否过期
+func (r *PostRecommendation) IsExpired() bool {
+	return time.Now().After(r.expiresAt)
+}
+
+// --- 访问器方法 ---
+
+func (r *PostRecommendation) ID() valueobject.RecommendationID {
+	return r.id
+}
+
+func (r *PostRecommendation) Post() *entity.Post {
+	return r.post
+}
+
+func (r *PostRecommendation) AuthorID() valueobject.UserID {
+	return r.post.AuthorID()
+}
+
+func (r *PostRecommendation) Reason() valueobject.RecommendationReason {
+	return r.reason
+}
+
+func (r *PostRecommendation) Score() int {
+	return r.score
+}
+
+func (r *PostRecommendation) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+func (r *PostRecommendation) ExpiresAt() time.Time {
+	return r.expiresAt
+}
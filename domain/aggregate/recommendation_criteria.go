@@ -0,0 +1,64 @@
+package aggregate
+
+import "service/domain/valueobject"
+
+// ExclusionRequestCriteria 不满足调用方在请求中指定的过滤条件
+const ExclusionRequestCriteria ExclusionReason = "request_criteria"
+
+// RecommendationCriteria 值对象：调用方对推荐列表的过滤条件（规格）
+//
+// 为什么需要？
+// 业务规则（风控、多样性）由服务端统一执行；
+// 但 BFF 上的实验经常需要"只要分数高于 X 的"、"只要某几种理由的"、"不要这几个人"，
+// 以前每调整一次都要改服务端代码并重新部署。
+// 把这些条件作为请求参数传进来，由推荐列表按同样的方式执行和统计。
+//
+// 零值表示不过滤。
+type RecommendationCriteria struct {
+	MinScore       int                         // 最低分数（0 表示不限制）
+	ReasonTypes    []valueobject.ReasonType    // 只保留这些理由类型（为空表示不限制）
+	ExcludeUserIDs map[valueobject.UserID]bool // 不推荐这些用户
+}
+
+// IsEmpty 是否没有任何过滤条件
+func (c RecommendationCriteria) IsEmpty() bool {
+	return c.MinScore <= 0 && len(c.ReasonTypes) == 0 && len(c.ExcludeUserIDs) == 0
+}
+
+// IsSatisfiedBy 规格判断：推荐是否满足全部过滤条件
+func (c RecommendationCriteria) IsSatisfiedBy(rec *UserRecommendation) bool {
+	if rec.Score() < c.MinScore {
+		return false
+	}
+	if c.ExcludeUserIDs[rec.TargetUserID()] {
+		return false
+	}
+	if len(c.ReasonTypes) == 0 {
+		return true
+	}
+	for _, reasonType := range c.ReasonTypes {
+		if rec.Reason().Type() == reasonType {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyCriteria 业务行为：去掉不满足过滤条件的推荐
+//
+// 被去掉的推荐记录为 ExclusionRequestCriteria（和其他排除原因一起统计），
+// 在多样性规则之前执行：多样性名额只分配给满足条件的推荐。
+func (l *RecommendationList) ApplyCriteria(criteria RecommendationCriteria) {
+	if criteria.IsEmpty() {
+		return
+	}
+	kept := make([]*UserRecommendation, 0, len(l.recommendations))
+	for _, rec := range l.recommendations {
+		if !criteria.IsSatisfiedBy(rec) {
+			l.RecordExclusion(ExclusionRequestCriteria)
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	l.recommendations = kept
+}
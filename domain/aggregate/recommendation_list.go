@@ -11,6 +11,27 @@ import (
 var (
 	ErrCannotRecommendSelf     = errors.New("cannot recommend self")
 	ErrDuplicateRecommendation = errors.New("duplicate recommendation")
+	ErrBlockedUser             = errors.New("blocked user")
+)
+
+// ExclusionReason 候选用户被排除（没有进入推荐列表）的原因
+//
+// 被排除的候选不会出现在列表里，但排除的次数和原因需要被记录下来：
+// - 监控：统计每种原因排除了多少候选（如机器人账号）
+// - 排查：解释"为什么某个用户没有被推荐"
+type ExclusionReason string
+
+const (
+	// ExclusionHighRisk 风控系统判定为高风险账号（垃圾/机器人账号）
+	ExclusionHighRisk ExclusionReason = "high_risk"
+	// ExclusionDiversity 同一种推荐理由的推荐太多，为了多样性被去掉
+	ExclusionDiversity ExclusionReason = "diversity"
+	// ExclusionReferrerDiversity 只由同一个人带来的推荐太多（都来自社交网络中的同一个圈子），为了多样性被去掉
+	ExclusionReferrerDiversity ExclusionReason = "referrer_diversity"
+	// ExclusionAlreadyMember 用户已经是小组成员
+	ExclusionAlreadyMember ExclusionReason = "already_member"
+	// ExclusionBlocked 用户屏蔽了 TA，或者 TA 屏蔽了用户
+	ExclusionBlocked ExclusionReason = "blocked"
 )
 
 // RecommendationList 聚合：推荐列表
@@ -24,7 +45,7 @@ var (
 // 1. 去重规则：不能推荐同一个用户两次
 // 2. 排序规则：按分数排序
 // 3. 过滤规则：移除过期推荐、低分推荐
-// 4. 约束规则：不能推荐自己
+// 4. 约束规则：不能推荐自己、不能推荐有屏蔽关系的人
 //
 // 如果只是简单的 []UserRecommendation，这些规则会散落在各处。
 // 通过创建 RecommendationList 聚合，我们把这些规则集中管理。
@@ -46,6 +67,24 @@ type RecommendationList struct {
 	forUserID       valueobject.UserID    // 为哪个用户生成的推荐
 	recommendations []*UserRecommendation // 推荐列表
 	generatedAt     time.Time             // 生成时间
+
+	exclusions map[ExclusionReason]int     // 生成过程中被排除的候选数（按原因统计）
+	sampling   FollowingSampling           // 推荐信号来源（关注的人）的采样情况
+	blocked    map[valueobject.UserID]bool // 和用户有屏蔽关系的人（任一方向，见 ExcludeBlocked）
+}
+
+// FollowingSampling 推荐信号来源的采样情况
+//
+// 关注了几万人的用户，遍历全部关注的人代价太高，生成时只会使用其中一部分。
+// 记录采样情况，用于评估采样对推荐质量的影响。
+type FollowingSampling struct {
+	Total int // 关注的人总数
+	Used  int // 实际用作推荐信号来源的人数
+}
+
+// Sampled 是否发生了采样
+func (s FollowingSampling) Sampled() bool {
+	return s.Used < s.Total
 }
 
 // NewRecommendationList 工厂方法：创建新的推荐列表
@@ -54,6 +93,25 @@ func NewRecommendationList(forUserID valueobject.UserID) *RecommendationList {
 		forUserID:       forUserID,
 		recommendations: make([]*UserRecommendation, 0),
 		generatedAt:     time.Now(),
+		exclusions:      make(map[ExclusionReason]int),
+	}
+}
+
+// RestoreRecommendationList 从持久化数据重建推荐列表（仓储使用，不检查业务规则）
+//
+// 排除统计、屏蔽关系不持久化：重新使用时由调用方重新登记屏蔽关系（见 ExcludeBlocked）。
+func RestoreRecommendationList(
+	forUserID valueobject.UserID,
+	recommendations []*UserRecommendation,
+	generatedAt time.Time,
+	sampling FollowingSampling,
+) *RecommendationList {
+	return &RecommendationList{
+		forUserID:       forUserID,
+		recommendations: recommendations,
+		generatedAt:     generatedAt,
+		exclusions:      make(map[ExclusionReason]int),
+		sampling:        sampling,
 	}
 }
 
@@ -64,6 +122,7 @@ func NewRecommendationList(forUserID valueobject.UserID) *RecommendationList {
 // 业务不变量：
 // 1. 不能推荐自己（产品规则：自己不需要关注自己）
 // 2. 不能重复推荐（产品规则：同一用户只推荐一次）
+// 3. 不能推荐有屏蔽关系的人（产品规则，见 ExcludeBlocked；同时记录为 ExclusionBlocked）
 //
 // 为什么在聚合中验证？
 // 如果在外部验证，可能会遗漏或不一致。
@@ -85,6 +144,12 @@ func (l *RecommendationList) AddRecommendation(rec *UserRecommendation) error {
 		return ErrCannotRecommendSelf
 	}
 
+	// 业务规则：不能推荐有屏蔽关系的人
+	if l.blocked[rec.TargetUserID()] {
+		l.RecordExclusion(ExclusionBlocked)
+		return ErrBlockedUser
+	}
+
 	// 业务规则：不能重复推荐
 	for _, existing := range l.recommendations {
 		if existing.TargetUserID().Equals(rec.TargetUserID()) {
@@ -96,6 +161,53 @@ func (l *RecommendationList) AddRecommendation(rec *UserRecommendation) error {
 	return nil
 }
 
+// MergeRecommendation 业务行为：添加推荐，已经推荐了同一个人时合并推荐理由
+//
+// 和 AddRecommendation 的区别：同一个人被多种策略发现时（如"你关注的人也关注了TA"
+// 和"你们有很多共同好友"），不丢弃后来的推荐，而是合并为组合理由（见 UserRecommendation.MergeReasons）。
+// 不能推荐自己、不能推荐有屏蔽关系的人这两条规则不变。
+func (l *RecommendationList) MergeRecommendation(rec *UserRecommendation) error {
+	for _, existing := range l.recommendations {
+		if existing.TargetUserID().Equals(rec.TargetUserID()) {
+			return existing.MergeReasons(rec)
+		}
+	}
+	return l.AddRecommendation(rec)
+}
+
+// ExcludeBlocked 业务行为：登记和用户有屏蔽关系的人（用户屏蔽的人、屏蔽了用户的人）
+//
+// 为什么是聚合的不变量，而不是一个可选的过滤器？
+// 屏蔽是硬性的产品要求：被屏蔽的人出现在推荐里，用户会认为屏蔽"没有生效"，
+// 屏蔽了你的人被推荐给你，等于泄露了对方的屏蔽。
+// 候选来源、过滤器可以按场景配置，这条规则不能被配置掉，所以由聚合自己保证：
+// 登记之后 AddRecommendation 拒绝这些人，已经在列表中的也会被移除（都记录为 ExclusionBlocked）。
+func (l *RecommendationList) ExcludeBlocked(userIDs []valueobject.UserID) {
+	if len(userIDs) == 0 {
+		return
+	}
+	if l.blocked == nil {
+		l.blocked = make(map[valueobject.UserID]bool, len(userIDs))
+	}
+	for _, userID := range userIDs {
+		l.blocked[userID] = true
+	}
+	kept := l.recommendations[:0]
+	for _, rec := range l.recommendations {
+		if l.blocked[rec.TargetUserID()] {
+			l.RecordExclusion(ExclusionBlocked)
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	l.recommendations = kept
+}
+
+// IsBlocked 查询方法：userID 和用户之间是否有屏蔽关系（登记过的）
+func (l *RecommendationList) IsBlocked(userID valueobject.UserID) bool {
+	return l.blocked[userID]
+}
+
 // GetTopN 业务行为：获取分数最高的 N 个推荐
 //
 // 这是一个查询方法，展示了聚合如何封装业务逻辑。
@@ -120,12 +232,24 @@ func (l *RecommendationList) AddRecommendation(rec *UserRecommendation) error {
 // - 返回副本：不修改原列表，避免副作用
 // - 性能：每次调用都排序，如果频繁调用可以优化（缓存排序结果）
 func (l *RecommendationList) GetTopN(n int) []*UserRecommendation {
+	return topByScore(l.recommendations, n)
+}
+
+// scored 有推荐分数的推荐（用户推荐、小组推荐）
+type scored interface {
+	Score() int
+}
+
+// topByScore 辅助函数：按分数降序排列的前 n 个（推荐列表共用的排序规则）
+//
+// 返回副本，不修改原切片；分数相同时保持加入的顺序。
+func topByScore[T scored](items []T, n int) []T {
 	// 创建副本进行排序，不修改原列表
-	sorted := make([]*UserRecommendation, len(l.recommendations))
-	copy(sorted, l.recommendations)
+	sorted := make([]T, len(items))
+	copy(sorted, items)
 
 	// 按分数降序排序
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Score() > sorted[j].Score()
 	})
 
@@ -166,6 +290,102 @@ func (l *RecommendationList) FilterByMinScore(minScore int) {
 	l.recommendations = filtered
 }
 
+// ApplyDiversity 业务行为：同一种推荐理由最多保留 maxPerReasonType 个
+//
+// 业务规则：
+// - 列表不应该被同一类推荐占满，每种理由按分数从高到低保留
+// - 被去掉的推荐记录为 ExclusionDiversity（和生成时排除的候选一起统计）
+// - maxPerReasonType <= 0 时不限制
+func (l *RecommendationList) ApplyDiversity(maxPerReasonType int) {
+	if maxPerReasonType <= 0 {
+		return
+	}
+	counts := make(map[valueobject.ReasonType]int)
+	kept := make([]*UserRecommendation, 0, len(l.recommendations))
+	for _, rec := range l.GetTopN(len(l.recommendations)) {
+		reasonType := rec.Reason().Type()
+		if counts[reasonType] >= maxPerReasonType {
+			l.RecordExclusion(ExclusionDiversity)
+			continue
+		}
+		counts[reasonType]++
+		kept = append(kept, rec)
+	}
+	l.recommendations = kept
+}
+
+// ApplyReferrerDiversity 业务行为：只由同一个人带来的推荐最多保留 maxPerReferrer 个
+//
+// 为什么需要？
+// 用户关注了一个特别活跃的人，TA 最近关注的几十个人都会以"1 位你关注的人关注了TA"进入列表，
+// 前几名可能全部来自 TA 的圈子。按理由类型限制（ApplyDiversity）管不住这种情况：理由类型都一样，
+// 但问题不是类型单一，而是信号只来自一个人。
+//
+// 业务规则：
+// - 只有一个相关用户的推荐，按这个人（推荐人）分组，每组按分数从高到低保留
+// - 有多个相关用户的推荐不受限制：它们被社交网络中的多个人共同支持，不属于某一个圈子
+// - 没有相关用户样本的推荐（冷启动、从存储中恢复的推荐理由）不受限制
+// - 被去掉的推荐记录为 ExclusionReferrerDiversity
+// - maxPerReferrer <= 0 时不限制
+func (l *RecommendationList) ApplyReferrerDiversity(maxPerReferrer int) {
+	if maxPerReferrer <= 0 {
+		return
+	}
+	counts := make(map[valueobject.UserID]int)
+	kept := make([]*UserRecommendation, 0, len(l.recommendations))
+	for _, rec := range l.GetTopN(len(l.recommendations)) {
+		referrer, ok := singleReferrer(rec.Reason())
+		if ok && counts[referrer] >= maxPerReferrer {
+			l.RecordExclusion(ExclusionReferrerDiversity)
+			continue
+		}
+		if ok {
+			counts[referrer]++
+		}
+		kept = append(kept, rec)
+	}
+	l.recommendations = kept
+}
+
+// singleReferrer 辅助函数：只有一个相关用户的推荐理由的推荐人
+func singleReferrer(reason valueobject.RecommendationReason) (valueobject.UserID, bool) {
+	if reason.RelatedCount() != 1 {
+		return valueobject.UserID{}, false
+	}
+	related := reason.RelatedUsers()
+	if len(related) != 1 {
+		return valueobject.UserID{}, false
+	}
+	return related[0], true
+}
+
+// RecordExclusion 业务行为：记录一个被排除的候选
+//
+// 由领域服务在生成过程中调用：
+// 候选因为业务规则（如高风险账号）被排除时，记录排除原因。
+func (l *RecommendationList) RecordExclusion(reason ExclusionReason) {
+	l.exclusions[reason]++
+}
+
+// Exclusions 查询方法：获取按原因统计的排除数量（返回副本）
+func (l *RecommendationList) Exclusions() map[ExclusionReason]int {
+	result := make(map[ExclusionReason]int, len(l.exclusions))
+	for reason, count := range l.exclusions {
+		result[reason] = count
+	}
+	return result
+}
+
+// RecordFollowingSampling 业务行为：记录推荐信号来源的采样情况
+func (l *RecommendationList) RecordFollowingSampling(total, used int) {
+	l.sampling = FollowingSampling{Total: total, Used: used}
+}
+
+// FollowingSampling 查询方法：获取推荐信号来源的采样情况
+func (l *RecommendationList) FollowingSampling() FollowingSampling {
+	return l.sampling
+}
+
 // Count 查询方法：获取推荐数量
 func (l *RecommendationList) Count() int {
 	return len(l.recommendations)
@@ -0,0 +1,55 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+
+	"service/domain/valueobject"
+)
+
+func TestRecommendationList_ApplyReferrerDiversity(t *testing.T) {
+	userID := func(id int64) valueobject.UserID {
+		u, _ := valueobject.NewUserID(id)
+		return u
+	}
+	list := NewRecommendationList(userID(1))
+	add := func(target int64, recentPosts int, referrers ...int64) {
+		related := make([]valueobject.UserID, 0, len(referrers))
+		for _, id := range referrers {
+			related = append(related, userID(id))
+		}
+		rec, err := NewUserRecommendation(userID(target), valueobject.NewFollowedByFollowingReason(related), recentPosts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := list.AddRecommendation(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2 带来了 10、11、12（分数依次降低），3 带来了 13；14 被 2、3 共同支持
+	add(10, 3, 2)
+	add(11, 2, 2)
+	add(12, 1, 2)
+	add(13, 0, 3)
+	add(14, 0, 2, 3)
+
+	list.ApplyReferrerDiversity(2)
+
+	var got []int64
+	for _, rec := range list.GetTopN(list.Count()) {
+		got = append(got, rec.TargetUserID().Value())
+	}
+	// 14 分数最高且不受限制；2 带来的只保留分数最高的两个
+	if want := []int64{14, 10, 11, 13}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if n := list.Exclusions()[ExclusionReferrerDiversity]; n != 1 {
+		t.Errorf("referrer diversity exclusions = %d, want 1", n)
+	}
+
+	// maxPerReferrer <= 0 时不限制
+	list.ApplyReferrerDiversity(0)
+	if list.Count() != 4 {
+		t.Errorf("Count() = %d after ApplyReferrerDiversity(0), want 4", list.Count())
+	}
+}
@@ -0,0 +1,127 @@
+package aggregate
+
+import "service/domain/valueobject"
+
+// ScoringInput 打分的输入（推荐创建、更新帖子数、更新互动时重新打分）
+type ScoringInput struct {
+	Reason          valueobject.CompositeReason // 全部推荐理由（权重是各条之和）
+	RecentPostCount int
+	Engagement      valueobject.RecentEngagement  // 最近一小时的互动（没有拿到时为零值）
+	Creator         valueobject.CreatorEngagement // 最近 N 天收到的互动（没有拿到时为零值）
+}
+
+// ScoringPolicy 领域接口：推荐打分策略
+//
+// 为什么是接口？
+// 打分公式是产品策略，会经常调整：调整权重通过配置（WeightedScoringPolicy），
+// 换一套公式（如按互动率打分）实现这个接口，在生成器上注入（service.WithScoringPolicy），
+// 都不需要修改聚合本身。
+//
+// 为什么返回各因素的贡献，而不是一个分数？
+// 推荐分数 = 各因素的贡献之和：分数和分数拆解（ScoreBreakdown）由同一份结果得到，
+// 换了策略也不会出现"拆解加起来不等于分数"。
+type ScoringPolicy interface {
+	Factors(input ScoringInput) []ScoreFactor
+}
+
+// WeightedScoringPolicy 按权重打分（默认策略，权重来自租户配置）
+//
+// 计算公式（默认权重见 valueobject.DefaultScoringWeights）：
+// - 基础分数 = 推荐理由权重（关注者数 × 10）
+// - 活跃度加分 = 帖子数量 × 2
+// - 实时互动加分（有实时互动信号时，见 engagementBoost）
+// - 创作者互动加分（最近 N 天收到的点赞、评论，见 creatorBoost）
+//
+// 业务逻辑：
+// - 被更多人关注的用户分数更高
+// - 有活跃内容的用户更值得推荐
+// - 内容有人看的创作者比只是发帖多的人更值得推荐
+//
+// 实际示例：
+//
+//	用户A：3个关注者，5个帖子 → 分数 = 3×10 + 5×2 = 40
+//	用户B：1个关注者，10个帖子 → 分数 = 1×10 + 10×2 = 30
+//	结果：优先推荐用户A（社交信号更强）
+//
+//	用户C：1个关注者，2个帖子，收到 400 个点赞 → 分数 = 1×10 + 2×2 + 20 = 34
+//	结果：用户C 排在只是发帖多的用户B 前面
+type WeightedScoringPolicy struct {
+	weights valueobject.ScoringWeights
+}
+
+// NewWeightedScoringPolicy 构造函数
+func NewWeightedScoringPolicy(weights valueobject.ScoringWeights) WeightedScoringPolicy {
+	return WeightedScoringPolicy{weights: weights}
+}
+
+// DefaultScoringPolicy 默认打分策略：默认权重
+func DefaultScoringPolicy() WeightedScoringPolicy {
+	return NewWeightedScoringPolicy(valueobject.DefaultScoringWeights())
+}
+
+// Weights 打分权重（特征快照、分数拆解中记录）
+func (p WeightedScoringPolicy) Weights() valueobject.ScoringWeights {
+	return p.weights
+}
+
+// Factors 实现 ScoringPolicy
+func (p WeightedScoringPolicy) Factors(input ScoringInput) []ScoreFactor {
+	recentPosts := 0
+	if input.RecentPostCount > 0 {
+		recentPosts = input.RecentPostCount * p.weights.PerRecentPost
+	}
+	return []ScoreFactor{
+		{Name: FactorReason, Value: input.Reason.RelatedCount(), Contribution: input.Reason.WeightWith(p.weights)},
+		{Name: FactorRecentPosts, Value: input.RecentPostCount, Contribution: recentPosts},
+		{Name: FactorEngagement, Value: engagementPoints(input.Engagement), Contribution: engagementBoost(input.Engagement, p.weights)},
+		{Name: FactorCreatorEngagement, Value: input.Creator.Points(), Contribution: creatorBoost(input.Creator, p.weights)},
+	}
+}
+
+// engagementBoost 业务规则：实时互动加分
+//
+// 每个点赞 1 分、每条评论 3 分（评论比点赞更能说明内容在被讨论），
+// 最多加"两个推荐信号来源"的分数：热度只用来在社交信号相近的候选之间调整顺序，
+// 不能让一个陌生的热门账号压过很多好友都关注了的人。
+//
+// 实际示例（默认权重，最多加 20 分）：
+//
+//	最近一小时 5 个点赞、2 条评论 → 5 + 2×3 = 11 分
+//	最近一小时 300 个点赞 → 20 分
+func engagementBoost(engagement valueobject.RecentEngagement, weights valueobject.ScoringWeights) int {
+	if engagement.IsZero() {
+		return 0
+	}
+	return min(engagementPoints(engagement), 2*weights.PerRelatedUser)
+}
+
+// engagementPoints 辅助函数：封顶前的互动分（每个点赞 1 分、每条评论 3 分）
+func engagementPoints(engagement valueobject.RecentEngagement) int {
+	return max(engagement.Likes, 0) + 3*max(engagement.Comments, 0)
+}
+
+// creatorBoost 业务规则：创作者互动加分
+//
+// 每 100 个互动分（点赞 1 分、评论 3 分）加 PerHundredEngagement 分，
+// 最多加"三个推荐信号来源"的分数：和实时互动加分一样，互动只在社交信号之间调整顺序。
+//
+// 实际示例（默认权重，最多加 30 分）：
+//
+//	最近 7 天 400 个点赞 → 400 / 100 × 5 = 20 分
+//	最近 7 天 5000 个点赞、1000 条评论 → 30 分
+func creatorBoost(engagement valueobject.CreatorEngagement, weights valueobject.ScoringWeights) int {
+	if engagement.IsZero() {
+		return 0
+	}
+	return min(engagement.Points()/100*weights.PerHundredEngagement, 3*weights.PerRelatedUser)
+}
+
+// policyWeights 辅助函数：按权重打分的策略使用的权重（其他策略没有权重，为零值）
+func policyWeights(policy ScoringPolicy) valueobject.ScoringWeights {
+	if weighted, ok := policy.(interface {
+		Weights() valueobject.ScoringWeights
+	}); ok {
+		return weighted.Weights()
+	}
+	return valueobject.ScoringWeights{}
+}
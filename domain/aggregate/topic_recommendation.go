@@ -0,0 +1,62 @@
+package aggregate
+
+import (
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// TopicRecommendation 聚合根：话题推荐
+//
+// 推荐的是"值得关注的话题"，推荐理由来自用户的社交网络
+// （"你关注的 3 个人关注了这个话题"），分数规则和用户推荐相同。
+type TopicRecommendation struct {
+	id        valueobject.RecommendationID
+	topic     valueobject.Topic
+	reason    valueobject.RecommendationReason
+	score     int
+	createdAt time.Time
+}
+
+// NewTopicRecommendation 工厂方法：创建新的话题推荐
+//
+// 业务规则：必须有推荐理由（至少 1 个关注的人关注了这个话题）
+func NewTopicRecommendation(
+	topic valueobject.Topic,
+	reason valueobject.RecommendationReason,
+	weights valueobject.ScoringWeights,
+	now time.Time,
+) (*TopicRecommendation, error) {
+	if reason.RelatedCount() == 0 {
+		return nil, ErrNoReasonForRecommendation
+	}
+	return &TopicRecommendation{
+		id:        valueobject.NewRecommendationID(),
+		topic:     topic,
+		reason:    reason,
+		score:     reason.WeightWith(weights),
+		createdAt: now,
+	}, nil
+}
+
+// --- 访问器方法 ---
+
+func (r *TopicRecommendation) ID() valueobject.RecommendationID {
+	return r.id
+}
+
+func (r *TopicRecommendation) Topic() valueobject.Topic {
+	return r.topic
+}
+
+func (r *TopicRecommendation) Reason() valueobject.RecommendationReason {
+	return r.reason
+}
+
+func (r *TopicRecommendation) Score() int {
+	return r.score
+}
+
+func (r *TopicRecommendation) CreatedAt() time.Time {
+	return r.createdAt
+}
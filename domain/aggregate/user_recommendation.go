@@ -9,6 +9,7 @@ import (
 
 var (
 	ErrNoReasonForRecommendation = errors.New("no reason for recommendation")
+	ErrMergeDifferentTarget      = errors.New("cannot merge recommendations for different users")
 )
 
 // UserRecommendation 聚合根：用户推荐
@@ -39,12 +40,15 @@ var (
 type UserRecommendation struct {
 	// 私有字段，只能通过方法访问，保证封装性
 	id              valueobject.RecommendationID
-	targetUserID    valueobject.UserID // 被推荐的用户
-	reason          valueobject.RecommendationReason
-	score           int       // 推荐分数
-	recentPostCount int       // 最近帖子数
-	createdAt       time.Time // 创建时间
-	expiresAt       time.Time // 过期时间
+	targetUserID    valueobject.UserID            // 被推荐的用户
+	reasons         valueobject.CompositeReason   // 推荐理由（多种策略发现时有多条，见 MergeReasons）
+	score           int                           // 推荐分数
+	policy          ScoringPolicy                 // 打分策略（重新计算分数时使用）
+	recentPostCount int                           // 最近帖子数
+	engagement      valueobject.RecentEngagement  // 最近一小时的互动（没有拿到时为零值）
+	creator         valueobject.CreatorEngagement // 最近 N 天收到的互动（没有拿到时为零值）
+	createdAt       time.Time                     // 创建时间
+	expiresAt       time.Time                     // 过期时间
 }
 
 // NewUserRecommendation 工厂方法：创建新的用户推荐
@@ -57,7 +61,7 @@ type UserRecommendation struct {
 //
 // 在创建时执行的业务规则：
 // 1. 必须有推荐理由（至少1个关注者）
-// 2. 自动计算推荐分数（根据关注者数和帖子数）
+// 2. 自动计算推荐分数（根据关注者数和帖子数，默认打分策略）
 // 3. 设置过期时间（7天后过期）
 // 4. 生成唯一的推荐ID
 //
@@ -79,62 +83,181 @@ func NewUserRecommendation(
 	reason valueobject.RecommendationReason,
 	recentPostCount int,
 ) (*UserRecommendation, error) {
-	// 业务规则：至少要有1个关注者才能推荐
-	if len(reason.RelatedUsers()) == 0 {
+	return NewUserRecommendationWithPolicy(targetUserID, reason, recentPostCount, DefaultScoringPolicy())
+}
+
+// NewUserRecommendationWithWeights 工厂方法：按指定的打分权重创建推荐（如租户自己的权重）
+func NewUserRecommendationWithWeights(
+	targetUserID valueobject.UserID,
+	reason valueobject.RecommendationReason,
+	recentPostCount int,
+	weights valueobject.ScoringWeights,
+) (*UserRecommendation, error) {
+	return NewUserRecommendationWithPolicy(targetUserID, reason, recentPostCount, NewWeightedScoringPolicy(weights))
+}
+
+// NewUserRecommendationWithPolicy 工厂方法：按指定的打分策略创建推荐
+func NewUserRecommendationWithPolicy(
+	targetUserID valueobject.UserID,
+	reason valueobject.RecommendationReason,
+	recentPostCount int,
+	policy ScoringPolicy,
+) (*UserRecommendation, error) {
+	// 业务规则：至少要有1个关注者才能推荐（冷启动、补足一页的推荐理由和社交网络无关，没有关注者）
+	if reason.RelatedCount() == 0 && !reason.IsUnpersonalized() {
 		return nil, ErrNoReasonForRecommendation
 	}
 
 	// 业务规则：计算推荐分数
-	score := calculateScore(reason, recentPostCount)
+	reasons := valueobject.NewCompositeReason(reason)
+	score := calculateScore(policy, ScoringInput{Reason: reasons, RecentPostCount: recentPostCount})
 
 	now := time.Now()
 	return &UserRecommendation{
 		id:              valueobject.NewRecommendationID(),
 		targetUserID:    targetUserID,
-		reason:          reason,
+		reasons:         reasons,
 		score:           score,
+		policy:          policy,
 		recentPostCount: recentPostCount,
 		createdAt:       now,
 		expiresAt:       now.Add(7 * 24 * time.Hour), // 7天过期
 	}, nil
 }
 
+// RestoreUserRecommendation 从存储中恢复推荐（如推荐列表快照、持久化的推荐列表）
+//
+// 和工厂方法不同，恢复不执行创建时的业务规则：
+// 分数、过期时间都是当时计算好的，原样恢复（打分规则之后变化也不重新计算）。
+// recentPostCount 只用于按另一套权重重新计算分数（ScoreWith），存储中没有时传 0。
+// 存储中只有最强的一条推荐理由：组合理由恢复后只有这一条。
+func RestoreUserRecommendation(
+	targetUserID valueobject.UserID,
+	reason valueobject.RecommendationReason,
+	score int,
+	recentPostCount int,
+	createdAt time.Time,
+	expiresAt time.Time,
+) *UserRecommendation {
+	return &UserRecommendation{
+		id:              valueobject.NewRecommendationID(),
+		targetUserID:    targetUserID,
+		reasons:         valueobject.NewCompositeReason(reason),
+		score:           score,
+		policy:          DefaultScoringPolicy(),
+		recentPostCount: recentPostCount,
+		createdAt:       createdAt,
+		expiresAt:       expiresAt,
+	}
+}
+
 // calculateScore 业务规则：推荐分数计算
 //
 // 这是核心业务规则，决定了推荐的排序。
-//
-// 计算公式：
-// - 基础分数 = 推荐理由权重（关注者数 × 10）
-// - 活跃度加分 = 帖子数量 × 2
-//
-// 业务逻辑：
-// - 被更多人关注的用户分数更高
-// - 有活跃内容的用户更值得推荐
-//
-// 实际示例：
-//
-//	用户A：3个关注者，5个帖子 → 分数 = 3×10 + 5×2 = 40
-//	用户B：1个关注者，10个帖子 → 分数 = 1×10 + 10×2 = 30
-//	结果：优先推荐用户A（社交信号更强）
+// 分数 = 打分策略给出的各因素贡献之和（默认策略见 WeightedScoringPolicy）。
 //
 // 为什么这个逻辑在领域层？
 // 因为这是核心业务规则，产品经理定义的推荐策略。
-// 如果策略改变（如调整权重），只需修改这里。
+// 调整权重只需修改配置，换一套公式只需实现 ScoringPolicy。
 //
 // 扩展性：
 // 未来可以添加更多因素：
 // - 用户活跃度（最后登录时间）
-// - 内容质量（点赞数、评论数）
 // - 个性化因素（兴趣匹配度）
-func calculateScore(reason valueobject.RecommendationReason, postCount int) int {
-	score := reason.Weight()
+func calculateScore(policy ScoringPolicy, input ScoringInput) int {
+	score := 0
+	for _, factor := range policy.Factors(input) {
+		score += factor.Contribution
+	}
+	return score
+}
+
+// scoringInput 辅助方法：当前的打分输入
+func (r *UserRecommendation) scoringInput() ScoringInput {
+	return ScoringInput{Reason: r.reasons, RecentPostCount: r.recentPostCount, Engagement: r.engagement, Creator: r.creator}
+}
+
+// ScoreWith 按另一套打分权重计算的分数（不修改推荐本身）
+//
+// 用于比较两套权重的排序效果（交错实验），推荐的分数仍然是创建时的权重算出来的。
+func (r *UserRecommendation) ScoreWith(weights valueobject.ScoringWeights) int {
+	return calculateScore(NewWeightedScoringPolicy(weights), r.scoringInput())
+}
+
+// ScoringRulesVersion 打分规则（默认打分策略 WeightedScoringPolicy）的版本
+//
+// 修改打分公式时同时修改版本号：特征快照中带着这个版本，离线训练可以区分新旧规则下的数据。
+//
+// v2：加入实时互动加分（engagementBoost）
+// v3：加入创作者互动加分（creatorBoost）
+// v4：多种策略发现的同一个人，推荐理由的权重相加（valueobject.CompositeReason）
+const ScoringRulesVersion = "v4"
 
-	// 有活跃内容加分
-	if postCount > 0 {
-		score += postCount * 2
+// ScoringFeatures 打分时使用的全部特征
+type ScoringFeatures struct {
+	ReasonType      valueobject.ReasonType   // 最强的一条推荐理由的类型
+	ReasonTypes     []valueobject.ReasonType // 全部推荐理由的类型（多种策略发现时有多个）
+	SignalUserIDs   []valueobject.UserID     // 推荐信号来源（最强的一条理由的，可能只是样本）
+	RelatedCount    int                      // 推荐信号来源总数（全部理由之和）
+	RecentPostCount int
+	Engagement      valueobject.RecentEngagement  // 最近一小时的互动（没有拿到时为零值）
+	Creator         valueobject.CreatorEngagement // 最近 N 天收到的互动（没有拿到时为零值）
+	Weights         valueobject.ScoringWeights    // 按权重打分时的权重（其他打分策略为零值）
+	Score           int
+	RulesVersion    string
+}
+
+// Features 打分时使用的全部特征（特征快照日志：离线训练看到的和线上排序看到的一样）
+func (r *UserRecommendation) Features() ScoringFeatures {
+	return ScoringFeatures{
+		ReasonType:      r.Reason().Type(),
+		ReasonTypes:     r.ReasonTypes(),
+		SignalUserIDs:   r.Reason().RelatedUsers(),
+		RelatedCount:    r.reasons.RelatedCount(),
+		RecentPostCount: r.recentPostCount,
+		Engagement:      r.engagement,
+		Creator:         r.creator,
+		Weights:         policyWeights(r.policy),
+		Score:           r.score,
+		RulesVersion:    ScoringRulesVersion,
 	}
+}
 
-	return score
+// 打分因素名称（ScoreFactor.Name）
+const (
+	FactorReason      = "reason"       // 推荐理由（特征值：推荐信号来源数）
+	FactorRecentPosts = "recent_posts" // 最近帖子数
+	FactorEngagement  = "engagement"   // 实时互动（特征值：点赞数 + 评论数 × 3，加分有封顶）
+
+	FactorCreatorEngagement = "creator_engagement" // 最近 N 天收到的互动（特征值：点赞数 + 评论数 × 3，加分有封顶）
+)
+
+// ScoreFactor 一个打分因素对分数的贡献
+type ScoreFactor struct {
+	Name         string
+	Value        int // 特征值
+	Contribution int // 贡献的分数
+}
+
+// ScoreBreakdown 分数的组成：各因素的贡献之和等于推荐分数
+type ScoreBreakdown struct {
+	Factors      []ScoreFactor
+	Score        int
+	Weights      valueobject.ScoringWeights // 按权重打分时的权重（其他打分策略为零值）
+	RulesVersion string
+}
+
+// ScoreBreakdown 查询方法：按打分策略拆开推荐分数（质量评审时解释"为什么排在这里"）
+//
+// 分数和拆解来自同一个 ScoringPolicy.Factors，各因素的贡献之和等于分数。
+// 从快照恢复的推荐（RestoreUserRecommendation）没有特征，拆出来的结果没有意义。
+func (r *UserRecommendation) ScoreBreakdown() ScoreBreakdown {
+	return ScoreBreakdown{
+		Factors:      r.policy.Factors(r.scoringInput()),
+		Score:        r.score,
+		Weights:      policyWeights(r.policy),
+		RulesVersion: ScoringRulesVersion,
+	}
 }
 
 // IsExpired 业务规则：推荐是否过期
@@ -158,8 +281,24 @@ func (r *UserRecommendation) TargetUserID() valueobject.UserID {
 	return r.targetUserID
 }
 
+// Reason 最强的一条推荐理由（展示文案、理由类型都按这一条）
 func (r *UserRecommendation) Reason() valueobject.RecommendationReason {
-	return r.reason
+	return r.reasons.Primary()
+}
+
+// Reasons 全部推荐理由
+func (r *UserRecommendation) Reasons() valueobject.CompositeReason {
+	return r.reasons
+}
+
+// ReasonTypes 全部推荐理由的类型（按加入顺序）
+func (r *UserRecommendation) ReasonTypes() []valueobject.ReasonType {
+	components := r.reasons.Components()
+	types := make([]valueobject.ReasonType, 0, len(components))
+	for _, reason := range components {
+		types = append(types, reason.Type())
+	}
+	return types
 }
 
 func (r *UserRecommendation) Score() int {
@@ -187,8 +326,41 @@ func (r *UserRecommendation) Refresh() {
 	r.expiresAt = time.Now().Add(7 * 24 * time.Hour)
 }
 
+// ExpireIn 业务行为：按有效期重新设置过期时间（从创建时间开始计算）
+//
+// 不同展示场景对推荐有效期的要求不同（如推送里的推荐只在当天有效）。
+func (r *UserRecommendation) ExpireIn(ttl time.Duration) {
+	r.expiresAt = r.createdAt.Add(ttl)
+}
+
 // UpdatePostCount 业务行为：更新帖子数量并重新计算分数
 func (r *UserRecommendation) UpdatePostCount(newCount int) {
 	r.recentPostCount = newCount
-	r.score = calculateScore(r.reason, newCount)
+	r.score = calculateScore(r.policy, r.scoringInput())
+}
+
+// MergeReasons 业务行为：合并另一种策略对同一个人的推荐并重新计算分数
+//
+// 推荐理由合并为组合理由（权重相加，展示最强的一条），帖子数取较大的
+// （不同来源统计的时间范围可能不同）。other 必须是同一个被推荐的人，否则返回 ErrMergeDifferentTarget。
+func (r *UserRecommendation) MergeReasons(other *UserRecommendation) error {
+	if !other.targetUserID.Equals(r.targetUserID) {
+		return ErrMergeDifferentTarget
+	}
+	r.reasons = r.reasons.Merge(other.reasons)
+	r.recentPostCount = max(r.recentPostCount, other.recentPostCount)
+	r.score = calculateScore(r.policy, r.scoringInput())
+	return nil
+}
+
+// UpdateEngagement 业务行为：更新最近一小时的互动并重新计算分数
+func (r *UserRecommendation) UpdateEngagement(engagement valueobject.RecentEngagement) {
+	r.engagement = engagement
+	r.score = calculateScore(r.policy, r.scoringInput())
+}
+
+// UpdateCreatorEngagement 业务行为：更新最近 N 天收到的互动并重新计算分数
+func (r *UserRecommendation) UpdateCreatorEngagement(engagement valueobject.CreatorEngagement) {
+	r.creator = engagement
+	r.score = calculateScore(r.policy, r.scoringInput())
 }
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAuditActorRequired  = errors.New("audit entry requires an actor")
+	ErrAuditActionRequired = errors.New("audit entry requires an action")
+)
+
+// AuditAction 管理操作类型
+type AuditAction string
+
+const (
+	AuditActionScoringWeightChange    AuditAction = "scoring_weight.change"    // 修改打分权重
+	AuditActionCuratedPackEdit        AuditAction = "curated_pack.edit"        // 编辑人工精选推荐包
+	AuditActionSuppressionOverride    AuditAction = "suppression.override"     // 人工覆盖推荐屏蔽
+	AuditActionStrategyWeightOverride AuditAction = "strategy_weight.override" // 人工覆盖策略权重（bandit）
+)
+
+// AuditEntry 实体：一条管理操作审计记录
+//
+// 记录"谁、在什么时候、因为哪个请求、把什么从什么改成了什么"。
+//
+// 为什么是实体而不是值对象？
+// 和 Impression 一样，每一次管理操作都是独立发生的事件，
+// 即使两次操作的内容完全相同，也是两条不同的审计记录。
+//
+// 只追加（append-only）：
+// 审计记录创建后不能修改、不能删除，所以这里没有任何修改方法，
+// 仓储接口也只有追加和查询。
+type AuditEntry struct {
+	actor      string      // 操作人（管理员账号）
+	action     AuditAction // 操作类型
+	target     string      // 操作对象（如权重名、精选包ID、用户ID）
+	before     string      // 修改前的值（JSON）
+	after      string      // 修改后的值（JSON）
+	requestID  string      // 请求ID（关联请求日志）
+	occurredAt time.Time
+}
+
+// NewAuditEntry 工厂方法
+//
+// 业务规则：必须知道是谁做了什么操作
+func NewAuditEntry(
+	actor string,
+	action AuditAction,
+	target string,
+	before string,
+	after string,
+	requestID string,
+	occurredAt time.Time,
+) (*AuditEntry, error) {
+	if actor == "" {
+		return nil, ErrAuditActorRequired
+	}
+	if action == "" {
+		return nil, ErrAuditActionRequired
+	}
+	return &AuditEntry{
+		actor:      actor,
+		action:     action,
+		target:     target,
+		before:     before,
+		after:      after,
+		requestID:  requestID,
+		occurredAt: occurredAt,
+	}, nil
+}
+
+// --- 访问器方法 ---
+
+func (e *AuditEntry) Actor() string {
+	return e.actor
+}
+
+func (e *AuditEntry) Action() AuditAction {
+	return e.action
+}
+
+func (e *AuditEntry) Target() string {
+	return e.target
+}
+
+func (e *AuditEntry) Before() string {
+	return e.before
+}
+
+func (e *AuditEntry) After() string {
+	return e.after
+}
+
+func (e *AuditEntry) RequestID() string {
+	return e.requestID
+}
+
+func (e *AuditEntry) OccurredAt() time.Time {
+	return e.occurredAt
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// Conversion 实体：一次归因到推荐曝光的转化
+//
+// 用户关注了某人，并且在这之前的归因窗口内看到过"推荐关注 TA"，
+// 这次关注就归因到那次曝光（有多次曝光时归因到最近的一次）。
+//
+// 为什么要单独保存？
+// 统计时现场 JOIN 关注表只能回答"曝光后有没有关注"，
+// 取关之后转化就消失了，也区分不了"在推荐卡片上直接点了关注"和"之后自己去关注"。
+// 在关注事件发生时写下归因结果，效果数据就不再依赖关注关系的当前状态。
+type Conversion struct {
+	recommendationID valueobject.RecommendationID // 归因到的那次曝光
+	viewerID         valueobject.UserID           // 看到推荐、发起关注的用户
+	targetUserID     valueobject.UserID           // 被推荐、被关注的用户
+	reasonType       valueobject.ReasonType       // 曝光时的推荐理由类型（统计按策略汇总）
+	surface          string
+	shownAt          time.Time
+	convertedAt      time.Time
+	direct           bool // 是否直接在推荐卡片上关注
+}
+
+// NewConversion 工厂方法：把关注归因到一次曝光
+func NewConversion(impression *Impression, convertedAt time.Time, direct bool) *Conversion {
+	return &Conversion{
+		recommendationID: impression.RecommendationID(),
+		viewerID:         impression.ViewerID(),
+		targetUserID:     impression.TargetUserID(),
+		reasonType:       impression.ReasonType(),
+		surface:          impression.Surface(),
+		shownAt:          impression.ShownAt(),
+		convertedAt:      convertedAt,
+		direct:           direct,
+	}
+}
+
+// --- 访问器方法 ---
+
+func (c *Conversion) RecommendationID() valueobject.RecommendationID {
+	return c.recommendationID
+}
+
+func (c *Conversion) ViewerID() valueobject.UserID {
+	return c.viewerID
+}
+
+func (c *Conversion) TargetUserID() valueobject.UserID {
+	return c.targetUserID
+}
+
+func (c *Conversion) ReasonType() valueobject.ReasonType {
+	return c.reasonType
+}
+
+func (c *Conversion) Surface() string {
+	return c.surface
+}
+
+func (c *Conversion) ShownAt() time.Time {
+	return c.shownAt
+}
+
+func (c *Conversion) ConvertedAt() time.Time {
+	return c.convertedAt
+}
+
+func (c *Conversion) Direct() bool {
+	return c.direct
+}
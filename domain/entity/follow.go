@@ -0,0 +1,192 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+var (
+	// ErrCannotFollowSelf 关注关系的两端是同一个用户
+	ErrCannotFollowSelf = errors.New("cannot follow self")
+	// ErrInvalidFollowTransition 当前状态不允许这个操作（如对已经取关的关系静音）
+	ErrInvalidFollowTransition = errors.New("invalid follow status transition")
+	// ErrAlreadyFollowing 用户主动关注时已经在关注（包括静音的关注）
+	ErrAlreadyFollowing = errors.New("already following")
+	// ErrNotFollowing 用户主动取关时没有在关注
+	ErrNotFollowing = errors.New("not following")
+)
+
+// FollowStatus 关注关系的状态
+type FollowStatus string
+
+const (
+	// FollowActive 正常关注（推荐信号的来源）
+	FollowActive FollowStatus = "active"
+	// FollowMuted 仍然关注，但静音了 TA 的动态（不再作为推荐信号的来源）
+	FollowMuted FollowStatus = "muted"
+	// FollowRemoved 已取关（保留记录：重新关注时复用，事件乱序时用于判断先后）
+	FollowRemoved FollowStatus = "removed"
+)
+
+// Follow 实体：一条关注关系（follower 关注了 following）
+//
+// 为什么是实体？
+// 以前关注关系只是 follows 表的一行（FollowPO），推荐服务只读，状态是一个随意的字符串。
+// 现在推荐服务要根据社交服务的事件自己维护一份关注关系，
+// 状态怎么变化（能不能对取关的关系静音、重新关注算不算新的关注）就是业务规则，
+// 应该集中在领域对象里，而不是散落在消息处理器和 SQL 里。
+//
+// 标识：follower + following（同一对用户只有一条关注关系）
+//
+// 状态转换：
+//
+//	active ──Mute──→ muted ──Unmute──→ active
+//	active / muted ──Remove──→ removed ──Refollow──→ active（重新关注，关注时间更新）
+//
+// 事件可能重复投递、乱序到达：
+// - 转换到当前状态是空操作（重复投递的事件）
+// - 早于最近一次变化的操作被忽略（乱序到达的旧事件），返回 false
+//
+// 两组业务行为：
+// - Refollow、Mute、Unmute、Remove：处理社交服务的事件，宽松（重复的事件是空操作）
+// - FollowAgain、Unfollow：用户通过推荐服务主动关注、取关，严格（重复关注、取关返回错误）
+//
+// Follow 自己是一个聚合（聚合根就是它自己）：不变量（不能关注自己、同一对用户只有一条关注关系、
+// 不能重复关注）都在这里检查，仓储按 follower + following 整体加载和保存。
+type Follow struct {
+	followerID  valueobject.UserID
+	followingID valueobject.UserID
+	status      FollowStatus
+	followedAt  time.Time // 最近一次关注的时间（重新关注时更新，"最近N天关注"按这个时间）
+	updatedAt   time.Time // 最近一次状态变化的时间
+}
+
+// NewFollow 工厂方法：新的关注关系（状态为 active）
+func NewFollow(followerID, followingID valueobject.UserID, followedAt time.Time) (*Follow, error) {
+	if followerID.Equals(followingID) {
+		return nil, ErrCannotFollowSelf
+	}
+	return &Follow{
+		followerID:  followerID,
+		followingID: followingID,
+		status:      FollowActive,
+		followedAt:  followedAt,
+		updatedAt:   followedAt,
+	}, nil
+}
+
+// RestoreFollow 从持久化数据重建关注关系（仓储使用，不检查业务规则）
+func RestoreFollow(followerID, followingID valueobject.UserID, status FollowStatus, followedAt, updatedAt time.Time) *Follow {
+	return &Follow{
+		followerID:  followerID,
+		followingID: followingID,
+		status:      status,
+		followedAt:  followedAt,
+		updatedAt:   updatedAt,
+	}
+}
+
+// Refollow 业务行为：重新关注
+//
+// 已经取关的关系重新变为 active，关注时间更新为 at；
+// 已经是 active 或 muted 时是空操作（静音的关系不会因为重复的关注事件被取消静音）。
+func (f *Follow) Refollow(at time.Time) (bool, error) {
+	if f.status != FollowRemoved {
+		return false, nil
+	}
+	return f.transition(FollowActive, at, func() { f.followedAt = at })
+}
+
+// Mute 业务行为：静音（已经取关的关系不能静音）
+func (f *Follow) Mute(at time.Time) (bool, error) {
+	if at.Before(f.updatedAt) {
+		return false, nil // 乱序到达的旧事件（可能早于取关）
+	}
+	if f.status == FollowRemoved {
+		return false, ErrInvalidFollowTransition
+	}
+	return f.transition(FollowMuted, at, nil)
+}
+
+// Unmute 业务行为：取消静音（已经取关的关系不能取消静音）
+func (f *Follow) Unmute(at time.Time) (bool, error) {
+	if at.Before(f.updatedAt) {
+		return false, nil // 乱序到达的旧事件（可能早于取关）
+	}
+	if f.status == FollowRemoved {
+		return false, ErrInvalidFollowTransition
+	}
+	return f.transition(FollowActive, at, nil)
+}
+
+// Remove 业务行为：取关
+func (f *Follow) Remove(at time.Time) (bool, error) {
+	return f.transition(FollowRemoved, at, nil)
+}
+
+// FollowAgain 业务行为：用户主动重新关注一个取关过的人
+//
+// 和 Refollow 的区别：已经在关注（包括静音）时返回 ErrAlreadyFollowing，而不是空操作，
+// 调用方（客户端）据此提示"已关注"。
+func (f *Follow) FollowAgain(at time.Time) error {
+	if f.status != FollowRemoved {
+		return ErrAlreadyFollowing
+	}
+	_, err := f.Refollow(at)
+	return err
+}
+
+// Unfollow 业务行为：用户主动取关（静音的关注也可以取关）
+//
+// 和 Remove 的区别：已经取关时返回 ErrNotFollowing，而不是空操作。
+func (f *Follow) Unfollow(at time.Time) error {
+	if f.status == FollowRemoved {
+		return ErrNotFollowing
+	}
+	_, err := f.Remove(at)
+	return err
+}
+
+// transition 辅助方法：执行一次状态转换，返回状态是否发生了变化
+//
+// 乱序到达的旧操作（早于最近一次变化）被忽略；apply 在状态变化时额外执行。
+func (f *Follow) transition(to FollowStatus, at time.Time, apply func()) (bool, error) {
+	if f.status == to || at.Before(f.updatedAt) {
+		return false, nil
+	}
+	f.status = to
+	f.updatedAt = at
+	if apply != nil {
+		apply()
+	}
+	return true, nil
+}
+
+// --- 访问器方法 ---
+
+func (f *Follow) FollowerID() valueobject.UserID {
+	return f.followerID
+}
+
+func (f *Follow) FollowingID() valueobject.UserID {
+	return f.followingID
+}
+
+func (f *Follow) Status() FollowStatus {
+	return f.status
+}
+
+func (f *Follow) FollowedAt() time.Time {
+	return f.followedAt
+}
+
+func (f *Follow) UpdatedAt() time.Time {
+	return f.updatedAt
+}
+
+// IsActive 是否是推荐信号的来源（正常关注，没有静音）
+func (f *Follow) IsActive() bool {
+	return f.status == FollowActive
+}
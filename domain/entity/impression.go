@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// Impression 实体：一次推荐曝光
+//
+// 记录"某个推荐在什么时间、什么场景下展示给了哪个用户"。
+//
+// 为什么是实体而不是值对象？
+// 每一次曝光都是独立发生的事件，即使两次曝光的内容完全相同，
+// 它们也是两次不同的曝光（曝光次数、时间都有业务意义）。
+//
+// 用途：
+// - 曝光配额：限制每个用户每天看到的不同推荐数量
+// - 效果分析：曝光 → 关注的转化率
+type Impression struct {
+	recommendationID valueobject.RecommendationID
+	viewerID         valueobject.UserID     // 看到推荐的用户
+	targetUserID     valueobject.UserID     // 被推荐的用户
+	reasonType       valueobject.ReasonType // 推荐理由类型（效果分析按理由类型统计）
+	surface          string                 // 展示场景（如 home_feed）
+	shownAt          time.Time
+	experiment       string // 交错实验名（不在实验中时为空）
+	ranker           string // 交错实验中产生这条推荐的排序器（a 或 b）
+
+	variant valueobject.ExperimentAssignment // A/B 实验的分组（不在实验中时为零值）
+}
+
+// NewImpression 工厂方法
+func NewImpression(
+	recommendationID valueobject.RecommendationID,
+	viewerID valueobject.UserID,
+	targetUserID valueobject.UserID,
+	reasonType valueobject.ReasonType,
+	surface string,
+	shownAt time.Time,
+) *Impression {
+	return &Impression{
+		recommendationID: recommendationID,
+		viewerID:         viewerID,
+		targetUserID:     targetUserID,
+		reasonType:       reasonType,
+		surface:          surface,
+		shownAt:          shownAt,
+	}
+}
+
+// TagExperiment 标记曝光来自交错实验的哪个排序器（效果统计按排序器计算胜率）
+func (i *Impression) TagExperiment(experiment, ranker string) {
+	i.experiment = experiment
+	i.ranker = ranker
+}
+
+// AssignVariant 标记曝光来自 A/B 实验的哪个分组（效果分析按分组对比）
+//
+// 和交错实验互相独立：交错实验比较同一个列表中的两个排序器，A/B 实验比较不同用户看到的不同列表。
+func (i *Impression) AssignVariant(variant valueobject.ExperimentAssignment) {
+	i.variant = variant
+}
+
+// --- 访问器方法 ---
+
+func (i *Impression) RecommendationID() valueobject.RecommendationID {
+	return i.recommendationID
+}
+
+func (i *Impression) ViewerID() valueobject.UserID {
+	return i.viewerID
+}
+
+func (i *Impression) TargetUserID() valueobject.UserID {
+	return i.targetUserID
+}
+
+func (i *Impression) ReasonType() valueobject.ReasonType {
+	return i.reasonType
+}
+
+func (i *Impression) Surface() string {
+	return i.surface
+}
+
+func (i *Impression) ShownAt() time.Time {
+	return i.shownAt
+}
+
+func (i *Impression) Experiment() string {
+	return i.experiment
+}
+
+func (i *Impression) Ranker() string {
+	return i.ranker
+}
+
+func (i *Impression) Variant() valueobject.ExperimentAssignment {
+	return i.variant
+}
@@ -41,10 +41,11 @@ import (
 // - 推荐上下文：Post 是简单实体，只关心内容和作者
 // - 内容上下文：Post 是聚合根，管理评论、点赞、审核状态等
 type Post struct {
-	id        valueobject.PostID
-	authorID  valueobject.UserID
-	content   string
-	createdAt time.Time
+	id               valueobject.PostID
+	authorID         valueobject.UserID
+	content          string
+	createdAt        time.Time
+	moderationStatus valueobject.ModerationStatus // 审核状态（默认未知）
 }
 
 // NewPost 工厂方法
@@ -79,3 +80,17 @@ func (p *Post) Content() string {
 func (p *Post) CreatedAt() time.Time {
 	return p.createdAt
 }
+
+func (p *Post) ModerationStatus() valueobject.ModerationStatus {
+	return p.moderationStatus
+}
+
+// --- 领域行为方法 ---
+
+// ApplyModeration 业务行为：记录审核结果
+//
+// 仓储从数据库/内容服务加载帖子时，把审核状态一起还原到实体上。
+// 审核结果可能变化（如复审后从 sensitive 改为 approved），所以是可变的。
+func (p *Post) ApplyModeration(status valueobject.ModerationStatus) {
+	p.moderationStatus = status
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"time"
+
+	"service/domain/event"
+	"service/domain/valueobject"
+)
+
+// RecommendationFeedback 实体：用户对一次推荐曝光的反馈（点开、关注）
+//
+// 和 Conversion 的区别：
+// 转化是服务端根据关注事件归因出来的（曝光后一天内关注了就算），
+// 反馈是客户端在推荐卡片上直接上报的，能区分"点开看了"和"直接关注"，
+// 排序团队用它计算点击率、卡片上的关注率。
+//
+// 只能对展示给自己的推荐反馈：反馈从曝光记录创建，推荐理由类型、场景、展示时间都来自曝光。
+type RecommendationFeedback struct {
+	recommendationID valueobject.RecommendationID
+	viewerID         valueobject.UserID
+	targetUserID     valueobject.UserID
+	reasonType       valueobject.ReasonType
+	surface          string
+	shownAt          time.Time
+	variant          valueobject.ExperimentAssignment // 曝光时的 A/B 实验分组
+	action           valueobject.FeedbackAction
+	at               time.Time
+}
+
+// NewRecommendationFeedback 工厂方法：对一次曝光的反馈
+func NewRecommendationFeedback(impression *Impression, action valueobject.FeedbackAction, at time.Time) *RecommendationFeedback {
+	return &RecommendationFeedback{
+		recommendationID: impression.RecommendationID(),
+		viewerID:         impression.ViewerID(),
+		targetUserID:     impression.TargetUserID(),
+		reasonType:       impression.ReasonType(),
+		surface:          impression.Surface(),
+		shownAt:          impression.ShownAt(),
+		variant:          impression.Variant(),
+		action:           action,
+		at:               at,
+	}
+}
+
+// RecordedEvent 反馈被记录之后发布的领域事件
+func (f *RecommendationFeedback) RecordedEvent(tenant valueobject.TenantID) event.RecommendationFeedbackRecorded {
+	return event.RecommendationFeedbackRecorded{
+		Tenant:           tenant.String(),
+		RecommendationID: f.recommendationID.Value(),
+		ViewerID:         f.viewerID.Value(),
+		TargetUserID:     f.targetUserID.Value(),
+		ReasonType:       int(f.reasonType),
+		Surface:          f.surface,
+		Experiment:       f.variant.Experiment(),
+		Variant:          f.variant.Variant(),
+		Action:           f.action.String(),
+		ShownAt:          f.shownAt,
+		At:               f.at,
+	}
+}
+
+// --- 访问器方法 ---
+
+func (f *RecommendationFeedback) RecommendationID() valueobject.RecommendationID {
+	return f.recommendationID
+}
+
+func (f *RecommendationFeedback) ViewerID() valueobject.UserID {
+	return f.viewerID
+}
+
+func (f *RecommendationFeedback) TargetUserID() valueobject.UserID {
+	return f.targetUserID
+}
+
+func (f *RecommendationFeedback) ReasonType() valueobject.ReasonType {
+	return f.reasonType
+}
+
+func (f *RecommendationFeedback) Surface() string {
+	return f.surface
+}
+
+func (f *RecommendationFeedback) ShownAt() time.Time {
+	return f.shownAt
+}
+
+func (f *RecommendationFeedback) Variant() valueobject.ExperimentAssignment {
+	return f.variant
+}
+
+func (f *RecommendationFeedback) Action() valueobject.FeedbackAction {
+	return f.action
+}
+
+func (f *RecommendationFeedback) At() time.Time {
+	return f.at
+}
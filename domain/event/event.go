@@ -0,0 +1,30 @@
+// Package event 领域事件：领域中已经发生的、其他部分关心的事情
+//
+// 为什么需要？
+// 推荐反馈写入之后，排序团队的离线任务、策略权重、效果看板都要知道，
+// 应用服务不应该逐个去调用它们。领域对象只描述"发生了什么"（事件），
+// 由 Publisher 发送出去，谁关心谁订阅。
+//
+// 事件是不可变的值，字段都是导出的基本类型，可以直接序列化后发送到消息队列。
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// Event 领域事件
+type Event interface {
+	// Name 事件名（消息队列的消息类型，如 recommendation.feedback_recorded）
+	Name() string
+
+	// OccurredAt 事件发生的时间
+	OccurredAt() time.Time
+}
+
+// Publisher 领域事件的发布者（实现在基础设施层：消息队列、本地文件）
+//
+// 发布是至少一次的：订阅方需要按事件中的标识去重。
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,32 @@
+package event
+
+import "time"
+
+// RecommendationFeedbackRecorded 领域事件：用户对一条推荐的反馈被记录了（点开、关注）
+//
+// 携带曝光时的推荐理由类型和展示场景：订阅方（离线训练、策略权重）不需要再查询曝光记录。
+// 曝光来自 A/B 实验时带上实验名和分组名（不在实验中时为空），订阅方可以直接按分组统计。
+// 同一条推荐的同一种反馈只发布一次（重复上报的反馈不会再记录）。
+type RecommendationFeedbackRecorded struct {
+	Tenant           string    `json:"tenant"`
+	RecommendationID string    `json:"recommendation_id"`
+	ViewerID         int64     `json:"viewer_id"`
+	TargetUserID     int64     `json:"target_user_id"`
+	ReasonType       int       `json:"reason_type"`
+	Surface          string    `json:"surface"`
+	Experiment       string    `json:"experiment,omitempty"`
+	Variant          string    `json:"variant,omitempty"`
+	Action           string    `json:"action"`
+	ShownAt          time.Time `json:"shown_at"`
+	At               time.Time `json:"at"`
+}
+
+// Name 实现 Event
+func (e RecommendationFeedbackRecorded) Name() string {
+	return "recommendation.feedback_recorded"
+}
+
+// OccurredAt 实现 Event
+func (e RecommendationFeedbackRecorded) OccurredAt() time.Time {
+	return e.At
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// ActiveUsersRepository 仓储接口：最近活跃的用户（最近一次请求推荐的时间）
+//
+// 业务含义：预计算任务只为最近活跃的用户提前生成推荐列表，
+// 长期不来的用户不值得占用召回、打分的资源（他们的请求回退到实时生成）。
+//
+// 按租户区分（租户从 ctx 中获取）；每个用户只保留最近一次活跃的时间。
+//
+// 实现：
+// - infrastructure/persistence.RedisActiveUsersRepository：Redis 有序集合（多实例共享）
+// - infrastructure/repository.MemoryActiveUsersRepository：进程内存储
+type ActiveUsersRepository interface {
+	// TouchUser 记录用户在 at 活跃（只保留最近一次）
+	TouchUser(ctx context.Context, userID valueobject.UserID, at time.Time) error
+
+	// ListActiveUsers since 之后活跃过的用户（最近活跃的在前，最多 limit 个）
+	ListActiveUsers(ctx context.Context, since time.Time, limit int) ([]valueobject.UserID, error)
+
+	// DeleteUser 删除用户的活跃记录（用户数据删除，幂等）
+	DeleteUser(ctx context.Context, userID valueobject.UserID) error
+}
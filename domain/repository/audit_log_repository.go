@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/entity"
+)
+
+// AuditLogRepository 仓储接口：管理操作审计日志
+//
+// 业务含义：记录和查询所有管理操作（合规审查要求）。
+//
+// 只追加（append-only）：
+// 接口只有 Append 和 Query，没有修改和删除。
+// 实现时数据库账号也只应授予 INSERT 和 SELECT 权限，
+// 保证即使应用代码有 bug，审计记录也不会被篡改。
+type AuditLogRepository interface {
+	// Append 追加一条审计记录
+	Append(ctx context.Context, entry *entity.AuditEntry) error
+
+	// Query 查询审计记录，按发生时间倒序（最新的在前）
+	Query(ctx context.Context, query AuditLogQuery) ([]*entity.AuditEntry, error)
+}
+
+// AuditLogQuery 审计日志查询条件（零值字段表示不过滤）
+type AuditLogQuery struct {
+	Actor  string
+	Action entity.AuditAction
+	Target string
+	Since  time.Time // 起始时间（包含）
+	Until  time.Time // 结束时间（不包含）
+	Limit  int       // 最多返回多少条
+}
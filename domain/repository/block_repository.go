@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// BlockRepository 仓储接口：屏蔽关系（由社交服务维护，推荐服务只读）
+//
+// 屏蔽是双向的产品规则：A 屏蔽了 B 之后，A 不会被推荐给 B，B 也不会被推荐给 A。
+// 一个用户屏蔽的人、屏蔽了 TA 的人都不会多（屏蔽是少数用户的少数操作），可以一次加载。
+type BlockRepository interface {
+	// GetBlockedUsers 获取用户屏蔽的所有人
+	GetBlockedUsers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error)
+
+	// GetBlockers 获取屏蔽了用户的所有人（反向查询，走 blocked_id 索引）
+	GetBlockers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error)
+
+	// IsBlocked 检查 blockerID 是否屏蔽了 blockedID
+	IsBlocked(ctx context.Context, blockerID, blockedID valueobject.UserID) (bool, error)
+}
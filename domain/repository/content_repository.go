@@ -17,6 +17,13 @@ type ContentRepository interface {
 	// 用于推荐分数计算
 	CountRecentPosts(ctx context.Context, userID valueobject.UserID, days int) (int, error)
 
+	// CountRecentPostsBatch 批量统计多个用户最近N天的帖子数
+	//
+	// 业务含义：生成推荐时评估所有候选的活跃度，
+	// 一次查询代替逐个调用 CountRecentPosts（避免每个候选一次查询）
+	// 返回：用户ID → 帖子数（没有帖子的用户可以不出现在结果中，等同于 0）
+	CountRecentPostsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID]int, error)
+
 	// GetRecentPosts 获取用户最近的帖子
 	//
 	// 业务含义：展示推荐用户的内容
@@ -24,4 +31,13 @@ type ContentRepository interface {
 	// - userID: 用户ID
 	// - limit: 最多返回多少条
 	GetRecentPosts(ctx context.Context, userID valueobject.UserID, limit int) ([]*entity.Post, error)
+
+	// GetRecentPostsForUsers 批量获取多个用户最近的帖子
+	//
+	// 业务含义：一次获取所有推荐用户的帖子预览（避免每条推荐一次查询）
+	// 参数：
+	// - userIDs: 用户ID列表
+	// - limit: 每个用户最多返回多少条
+	// 返回：用户ID → 帖子（按发布时间倒序；没有帖子的用户可以不出现在结果中）
+	GetRecentPostsForUsers(ctx context.Context, userIDs []valueobject.UserID, limit int) (map[valueobject.UserID][]*entity.Post, error)
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// ConversionRepository 仓储接口：归因转化记录
+//
+// 业务含义：记录"哪次曝光带来了一次关注"，效果统计按策略汇总。
+type ConversionRepository interface {
+	// RecordConversion 写入一次转化
+	//
+	// 幂等：同一次曝光只记录一次，关注事件重复投递时保留第一次的结果
+	RecordConversion(ctx context.Context, conversion *entity.Conversion) error
+
+	// DeleteUserConversions 删除与用户有关的所有转化记录（用户数据删除）
+	//
+	// 删除范围：viewer 是该用户的记录 + target 是该用户的记录
+	// 幂等：没有记录时返回 nil
+	DeleteUserConversions(ctx context.Context, userID valueobject.UserID) error
+
+	// ListUserConversions 获取归因到展示给用户的推荐的所有转化（按转化时间升序，用户数据导出）
+	ListUserConversions(ctx context.Context, viewerID valueobject.UserID) ([]*entity.Conversion, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// EngagementSignalRepository 仓储接口：近实时的互动信号（来自流式计数服务）
+//
+// 为什么需要？
+// 最近帖子数只能说明 TA 在发帖，说明不了有没有人看；
+// 正在被大量点赞、评论的账号，推荐出去更容易产生互动。
+// 计数服务消费点赞、评论事件流，按一小时的滑动窗口计数，延迟在秒级。
+//
+// 为什么定义在领域层？
+// "正在产生互动的候选加分"是打分规则的一部分，领域服务（RecommendationGenerator）在打分时需要这份数据。
+//
+// 实现：
+// - infrastructure/client.EngagementSignalHTTPClient：调用实时计数服务
+type EngagementSignalRepository interface {
+	// GetRecentEngagement 批量获取用户最近一小时的互动
+	//
+	// 返回：userID → 互动计数；没有互动的用户可以不出现在结果中
+	GetRecentEngagement(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID]valueobject.RecentEngagement, error)
+}
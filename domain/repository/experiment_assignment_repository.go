@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// ExperimentAssignmentRepository 仓储接口：用户在 A/B 实验中固定的分组
+//
+// 业务含义：分组默认按用户哈希计算，不需要保存；
+// 保存下来的分组优先于哈希结果，用于：
+// - 调整分组比例（如放量）时，已经参与实验的用户不换组
+// - 测试、运营把指定的用户放进某个分组（直接写入记录）
+type ExperimentAssignmentRepository interface {
+	// FindAssignment 用户在实验中保存过的分组（没有时 found 为 false）
+	FindAssignment(ctx context.Context, experiment string, userID valueobject.UserID) (assignment valueobject.ExperimentAssignment, found bool, err error)
+
+	// SaveAssignment 保存用户的分组
+	//
+	// 幂等：用户在这个实验中已经有分组时不覆盖（第一次保存的分组一直有效）
+	SaveAssignment(ctx context.Context, userID valueobject.UserID, assignment valueobject.ExperimentAssignment) error
+
+	// ListUserAssignments 用户保存过的所有分组（按实验名排序，用户数据导出）
+	ListUserAssignments(ctx context.Context, userID valueobject.UserID) ([]valueobject.ExperimentAssignment, error)
+
+	// DeleteUserAssignments 删除用户保存过的所有分组（用户数据删除）
+	//
+	// 幂等：没有记录时返回 nil
+	DeleteUserAssignments(ctx context.Context, userID valueobject.UserID) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// FeedbackRepository 仓储接口：推荐反馈记录（客户端上报的点开、关注）
+//
+// 业务含义：记录"用户对哪次曝光做了什么"，排序团队按推荐理由类型、场景计算点击率和关注率。
+type FeedbackRepository interface {
+	// RecordFeedback 写入一次反馈，返回是否是新的反馈
+	//
+	// 幂等：同一次曝光的同一种反馈只记录一次（客户端重试、重复上报时 recorded 为 false）
+	RecordFeedback(ctx context.Context, feedback *entity.RecommendationFeedback) (recorded bool, err error)
+
+	// DeleteUserFeedback 删除与用户有关的所有反馈记录（用户数据删除）
+	//
+	// 删除范围：viewer 是该用户的记录 + target 是该用户的记录
+	// 幂等：没有记录时返回 nil
+	DeleteUserFeedback(ctx context.Context, userID valueobject.UserID) error
+
+	// ListUserFeedback 获取用户做出的所有反馈（按反馈时间升序，用户数据导出）
+	ListUserFeedback(ctx context.Context, viewerID valueobject.UserID) ([]*entity.RecommendationFeedback, error)
+
+	// DeleteFeedbackBefore 删除反馈时间早于 cutoff 的记录，返回删除的条数（数据保留期）
+	DeleteFeedbackBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// OldestFeedbackTime 最早一条反馈的时间（没有记录时 ok 为 false）
+	OldestFeedbackTime(ctx context.Context) (oldest time.Time, ok bool, err error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// FollowerRepository 仓储接口：反向的关注关系（谁关注了这个用户）
+//
+// 为什么不放进 SocialGraphRepository？
+// 生成推荐只需要"我关注了谁"；"谁关注了我"只有触发式推荐（扇出给粉丝）使用，
+// 大 V 的粉丝可能有几百万，只提供分页遍历，不提供一次加载全部的方法。
+type FollowerRepository interface {
+	// ScanFollowers 分页遍历关注了 userID 的人（最近关注的在前），fn 返回错误时停止遍历
+	ScanFollowers(ctx context.Context, userID valueobject.UserID, pageSize int, fn func(page []valueobject.UserID) error) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// GroupMembershipRepository 仓储接口：小组成员关系
+//
+// 小组和成员关系由社区服务负责，推荐上下文只读。
+type GroupMembershipRepository interface {
+	// GetUserGroups 用户加入的全部小组
+	GetUserGroups(ctx context.Context, userID valueobject.UserID) ([]valueobject.GroupID, error)
+
+	// GetGroupsOfUsers 批量查询多个用户加入的小组（一次查询，避免 N+1）
+	//
+	// 没有加入任何小组的用户不出现在结果中。
+	GetGroupsOfUsers(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID][]valueobject.GroupID, error)
+
+	// CountMembers 批量统计小组的成员数（用于计算成员重合度）
+	CountMembers(ctx context.Context, groupIDs []valueobject.GroupID) (map[valueobject.GroupID]int, error)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// ImpressionRepository 仓储接口：推荐曝光记录
+//
+// 业务含义：记录和查询"给谁展示过哪些推荐"。
+//
+// 数据量提示：
+// 曝光是写多读少的数据，实现时通常按 viewer_id + shown_at 建索引，
+// 并配合数据保留策略定期清理。
+type ImpressionRepository interface {
+	// RecordImpressions 记录一批曝光
+	RecordImpressions(ctx context.Context, impressions []*entity.Impression) error
+
+	// GetImpressedTargets 获取某个时间之后曝光给用户的推荐对象（去重）
+	//
+	// 业务含义：用户从 since 开始看到过哪些被推荐的人
+	// 参数：
+	// - viewerID: 看到推荐的用户
+	// - since: 起始时间（包含）
+	GetImpressedTargets(ctx context.Context, viewerID valueobject.UserID, since time.Time) ([]valueobject.UserID, error)
+
+	// ListUserImpressions 获取展示给用户的所有曝光记录（按时间升序）
+	//
+	// 业务含义：用户行使访问权时，导出"推荐服务给他展示过什么"
+	ListUserImpressions(ctx context.Context, viewerID valueobject.UserID) ([]*entity.Impression, error)
+
+	// ListImpressionHistory 分页查询一段时间内展示给用户的曝光记录（按时间倒序）
+	//
+	// 业务含义：用户"之前推荐过的人"、客服排查"给他推荐过什么"
+	// 参数：
+	// - from, to: 时间范围 [from, to)，from 为零值时不限制起始时间
+	// - after: 上一页最后一条的位置（为 nil 时从最新的开始）
+	// - limit: 最多返回多少条
+	//
+	// 顺序：shown_at 倒序，同一时间按推荐ID倒序（一次请求的一批曝光时间相同，需要第二个排序键才能稳定分页）
+	ListImpressionHistory(
+		ctx context.Context,
+		viewerID valueobject.UserID,
+		from, to time.Time,
+		after *ImpressionCursor,
+		limit int,
+	) ([]*entity.Impression, error)
+
+	// FindLatestImpression 查询一段时间内把 target 推荐给 viewer 的最近一次曝光（没有时 ok 为 false）
+	//
+	// 业务含义：关注发生时，找出应该归因到的那次曝光
+	// 参数：
+	// - from, to: 时间范围 [from, to]（关注时间往前一个归因窗口）
+	FindLatestImpression(
+		ctx context.Context,
+		viewerID, targetUserID valueobject.UserID,
+		from, to time.Time,
+	) (impression *entity.Impression, ok bool, err error)
+
+	// FindImpression 查询展示给 viewer 的一次曝光（按推荐ID；没有时 ok 为 false）
+	//
+	// 业务含义：客户端上报反馈时，确认这条推荐确实展示给了这个用户，并找到曝光时的理由类型、场景
+	FindImpression(
+		ctx context.Context,
+		viewerID valueobject.UserID,
+		recommendationID valueobject.RecommendationID,
+	) (impression *entity.Impression, ok bool, err error)
+
+	// DeleteUserImpressions 删除与用户有关的所有曝光记录（用户数据删除）
+	//
+	// 业务含义：用户行使删除权后，不再保留"给他展示过什么"和"他被展示给了谁"
+	// 删除范围：viewer 是该用户的记录 + target 是该用户的记录
+	// 幂等：没有记录时返回 nil
+	DeleteUserImpressions(ctx context.Context, userID valueobject.UserID) error
+
+	// DeleteImpressionsBefore 删除 cutoff 之前的曝光记录（数据保留期）
+	//
+	// 返回删除的条数。实现应分批删除，避免一次删除大量数据锁表。
+	DeleteImpressionsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// OldestImpressionTime 最早一条曝光记录的时间（没有记录时 ok 为 false）
+	OldestImpressionTime(ctx context.Context) (oldest time.Time, ok bool, err error)
+}
+
+// ImpressionCursor 曝光记录的分页位置（一条曝光的排序键）
+type ImpressionCursor struct {
+	ShownAt          time.Time
+	RecommendationID string
+}
+
+// CursorOf 一条曝光记录的分页位置
+func CursorOf(impression *entity.Impression) *ImpressionCursor {
+	return &ImpressionCursor{
+		ShownAt:          impression.ShownAt(),
+		RecommendationID: impression.RecommendationID().Value(),
+	}
+}
+
+// Precedes c 是否排在曝光记录之前（按 ListImpressionHistory 的顺序，即这条记录在下一页中）
+func (c *ImpressionCursor) Precedes(impression *entity.Impression) bool {
+	if !impression.ShownAt().Equal(c.ShownAt) {
+		return impression.ShownAt().Before(c.ShownAt)
+	}
+	return impression.RecommendationID().Value() < c.RecommendationID
+}
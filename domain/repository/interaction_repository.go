@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// InteractionRepository 仓储接口：用户之间的互动记录
+//
+// 互动（点赞、评论、私信、访问主页）由互动上下文记录，推荐上下文只读：
+// - 最近一次互动的时间：判断"关注了但很久没有互动"（见 DormantConnectionSource）
+// - 每个人最近 N 天收到的点赞、评论数：打分时区分"内容有人看的创作者"和"只是发帖多的人"
+type InteractionRepository interface {
+	// GetLastInteractions userID 最近一次和 targets 中每个人互动的时间
+	//
+	// 从来没有互动过的人不出现在结果中。
+	GetLastInteractions(ctx context.Context, userID valueobject.UserID, targets []valueobject.UserID) (map[valueobject.UserID]time.Time, error)
+
+	// CountReceivedEngagement 批量统计 userIDs 中每个人最近 days 天收到的点赞、评论数
+	//
+	// 没有收到互动的人可以不出现在结果中。
+	CountReceivedEngagement(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID]valueobject.CreatorEngagement, error)
+}
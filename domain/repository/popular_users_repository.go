@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// PopularUsersRepository 仓储接口：冷启动推荐的用户（全站热门、编辑精选）
+//
+// 为什么需要？
+// 基于关注的推荐从"你关注的人"出发，刚注册、还没有关注任何人的用户什么也拿不到；
+// 新用户最需要推荐，这时只能推荐和社交网络无关的人。
+//
+// 两份名单都按租户区分（租户从 ctx 中获取），由离线任务（全站热门）和运营后台（编辑精选）写入，
+// 推荐服务只读。
+//
+// 实现：
+// - infrastructure/persistence.PopularUsersRepositoryImpl：MySQL
+// - infrastructure/repository.MockPopularUsersRepository：固定的示例数据
+type PopularUsersRepository interface {
+	// GetPopularUsers 全站热门用户（按热度降序，最多 limit 个）
+	GetPopularUsers(ctx context.Context, limit int) ([]valueobject.UserID, error)
+
+	// GetCuratedUsers 编辑精选的用户（按编辑排好的顺序，最多 limit 个）
+	GetCuratedUsers(ctx context.Context, limit int) ([]valueobject.UserID, error)
+}
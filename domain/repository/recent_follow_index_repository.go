@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"service/domain/valueobject"
+)
+
+// ErrRecentFollowIndexMiss 反向关注索引无法回答本次查询
+//
+// 常见原因：
+// - 用户的索引还没有建立（新用户、索引刚上线）
+// - 查询的时间窗口超过了索引保留的时间
+//
+// 调用方收到这个错误时应该回退到逐个查询的方式，而不是认为"没有候选"。
+var ErrRecentFollowIndexMiss = errors.New("recent follow index miss")
+
+// RecentFollow 一条"我关注的人最近关注了谁"的记录
+type RecentFollow struct {
+	Referrer  valueobject.UserID // 我关注的人（推荐信号来源）
+	Candidate valueobject.UserID // 他最近关注的人（推荐候选）
+}
+
+// RecentFollowIndexRepository 仓储接口：反向关注索引
+//
+// 为什么需要反向索引？
+// 没有索引时，推荐生成需要对"我关注的每个人"各查一次最近关注（fan-out on read），
+// 关注了几百人的用户，一次推荐就是几百次查询。
+//
+// 反向索引把这部分工作挪到写入时（fan-out on write）：
+// 每当 B 关注了 E，就把 "B → E" 写进所有关注了 B 的人的索引里。
+// 生成推荐时只需要读一次自己的索引。
+//
+// 为什么接口定义在领域层？
+// 领域服务只关心"我关注的人最近关注了谁"这个业务问题，
+// 索引存在哪里（Redis、其他 KV）、如何维护，是基础设施的事情。
+// 没有配置索引时，领域服务仍然用 SocialGraphRepository 逐个查询。
+type RecentFollowIndexRepository interface {
+	// GetRecentFollowsOfFollowings 获取用户关注的人最近N天关注了谁
+	//
+	// 业务含义：一次查出所有"推荐信号"
+	// 参数：
+	// - userID: 为哪个用户查询
+	// - days: 最近多少天
+	// 返回：
+	// - 每个"我关注的人 → 他最近关注的人"一条记录
+	// - 索引无法回答时返回 ErrRecentFollowIndexMiss
+	GetRecentFollowsOfFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]RecentFollow, error)
+
+	// DeleteUserIndex 删除与用户有关的索引数据（用户数据删除）
+	//
+	// 删除范围：
+	// - 用户自己的索引
+	// - 用户作为推荐信号来源（referrer）写进粉丝索引的记录
+	// 幂等：没有数据时返回 nil
+	DeleteUserIndex(ctx context.Context, userID valueobject.UserID) error
+}
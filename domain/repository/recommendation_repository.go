@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// RecommendationRepository 仓储接口：生成过的推荐列表（RecommendationList 聚合）
+//
+// 业务含义：保存生成器的结果，同一个用户接下来的几次请求（翻页、刷新）直接使用，
+// 不必每次 RPC 都重新召回、打分。
+//
+// 和推荐列表快照（RecommendationSnapshotStore）的区别：
+// - 快照是下发给客户端的那一页（已经执行过场景策略、曝光配额），用于增量同步，可以丢失
+// - 这里保存的是生成器的完整结果（执行场景策略之前），可以按任意场景、任意 limit 重新排出一页
+//
+// 一个用户可以有多份列表（每次生成保存一份），只使用最新的一份；旧的由 DeleteExpired 清理。
+type RecommendationRepository interface {
+	// SaveList 保存一份生成的推荐列表
+	SaveList(ctx context.Context, list *aggregate.RecommendationList) error
+
+	// GetLatestListForUser 获取为用户生成的最新一份推荐列表（没有时返回 nil, nil）
+	GetLatestListForUser(ctx context.Context, userID valueobject.UserID) (*aggregate.RecommendationList, error)
+
+	// RemoveTarget 从用户保存的推荐列表中移除一个被推荐的人（所有份都移除，幂等）
+	//
+	// 业务含义：用户关注了列表中的人之后，maxAge 内重新使用的列表不应该再推荐 TA
+	RemoveTarget(ctx context.Context, userID, targetUserID valueobject.UserID) error
+
+	// DeleteExpired 删除 cutoff 之前生成的推荐列表（数据保留期）
+	//
+	// 返回删除的列表数。实现应分批删除，避免一次删除大量数据锁表。
+	DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// ListUserLists 为用户生成的所有推荐列表（按生成时间倒序，用户数据导出）
+	ListUserLists(ctx context.Context, userID valueobject.UserID) ([]*aggregate.RecommendationList, error)
+
+	// DeleteUserLists 删除为用户生成的所有推荐列表和其中的推荐（用户数据删除，幂等）
+	DeleteUserLists(ctx context.Context, userID valueobject.UserID) error
+
+	// OldestListTime 最早一份推荐列表的生成时间（没有列表时 ok 为 false）
+	OldestListTime(ctx context.Context) (oldest time.Time, ok bool, err error)
+}
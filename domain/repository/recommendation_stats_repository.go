@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// DailyRecommendationStats 一天、一个展示场景、一种推荐理由的曝光效果
+type DailyRecommendationStats struct {
+	Day         time.Time // 当天零点
+	Surface     string
+	ReasonType  valueobject.ReasonType
+	Impressions int64 // 曝光次数
+	Conversions int64 // 曝光之后（归因窗口内）关注了被推荐用户的曝光次数
+}
+
+// ReasonConversionStats 一种推荐理由在一段时间内的曝光和归因转化
+type ReasonConversionStats struct {
+	ReasonType        valueobject.ReasonType
+	Impressions       int64 // 曝光次数
+	Conversions       int64 // 归因到这些曝光的关注次数
+	DirectConversions int64 // 其中直接在推荐卡片上关注的次数
+}
+
+// InterleavingOutcome 一个交错实验在一段时间内的结果
+//
+// 一次请求返回的一个交错列表是一次"比赛"：比较列表中两个排序器各自的推荐带来了多少关注。
+type InterleavingOutcome struct {
+	Sessions int64 // 交错列表数
+	WinsA    int64 // 排序器 A 的推荐带来的关注更多的列表数
+	WinsB    int64 // 排序器 B 的推荐带来的关注更多的列表数
+	Ties     int64 // 有关注、两边一样多的列表数（没有任何关注的列表不计入胜负）
+}
+
+// RecommendationStatsRepository 仓储接口：推荐效果统计（只读）
+//
+// 业务含义：运营看板需要的"推荐展示了多少、带来了多少关注"。
+//
+// 统计直接在曝光记录、关注关系和转化记录上聚合，没有单独的统计表：
+// 看板按天查询，数据量受曝光保留期限制。
+type RecommendationStatsRepository interface {
+	// DailyStats 按天、展示场景、推荐理由类型汇总 [from, to) 内的曝光和转化
+	//
+	// 参数：
+	// - conversionWindow: 归因窗口，曝光后这段时间内发生的关注才算作这次曝光的转化
+	DailyStats(ctx context.Context, from, to time.Time, conversionWindow time.Duration) ([]*DailyRecommendationStats, error)
+
+	// ReasonStats 按推荐理由类型汇总 [from, to) 内的曝光和归因转化（ConversionRepository 写入的记录）
+	//
+	// 转化按曝光时间归入时间范围：统计的是"这段时间的曝光带来了多少关注"
+	ReasonStats(ctx context.Context, from, to time.Time) ([]*ReasonConversionStats, error)
+
+	// InterleavingOutcome 汇总交错实验在 [from, to) 内的胜负（按曝光时间，转化来自归因记录）
+	//
+	// 同一个 viewer 同一时间写入的曝光属于同一个交错列表（一次请求的曝光时间相同）
+	InterleavingOutcome(ctx context.Context, experiment string, from, to time.Time) (*InterleavingOutcome, error)
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"service/domain/entity"
 	"service/domain/valueobject"
 )
 
@@ -44,7 +47,7 @@ type SocialGraphRepository interface {
 	// GetFollowings 获取用户关注的所有人
 	//
 	// 业务含义：查询用户的关注列表
-	// 返回：用户ID列表
+	// 返回：用户ID列表（按关注时间倒序，最近关注的在前）
 	GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error)
 
 	// GetRecentFollowings 获取用户最近N天关注的人
@@ -56,8 +59,96 @@ type SocialGraphRepository interface {
 	// 返回：用户ID列表
 	GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error)
 
+	// GetRecentFollowingsBatch 批量获取多个用户最近N天关注的人
+	//
+	// 业务含义：生成推荐时查询"我关注的每个人最近关注了谁"，
+	// 一次查询代替逐个调用 GetRecentFollowings（避免 N+1 查询）
+	// 参数：
+	// - userIDs: 用户ID列表
+	// - days: 最近多少天
+	// 返回：用户ID → 最近关注的人（最近没有关注任何人的用户可以不出现在结果中）
+	GetRecentFollowingsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error)
+
+	// CountFollowings 获取用户关注的人数
+	//
+	// 业务含义：判断用户的关注列表有多大（决定一次加载还是分页遍历）
+	CountFollowings(ctx context.Context, userID valueobject.UserID) (int, error)
+
+	// ScanFollowings 分页遍历用户关注的人
+	//
+	// 业务含义：与 GetFollowings 相同，但不会一次把整个关注列表加载到内存
+	// 参数：
+	// - userID: 用户ID
+	// - pageSize: 每页多少人
+	// - fn: 每页调用一次（按关注时间倒序），返回错误时停止遍历并返回该错误
+	//
+	// 使用场景：关注了几万人的用户，一次加载会占用大量内存
+	ScanFollowings(ctx context.Context, userID valueobject.UserID, pageSize int, fn func(page []valueobject.UserID) error) error
+
 	// IsFollowing 检查用户A是否关注了用户B
 	//
 	// 业务含义：判断关注关系是否存在
 	IsFollowing(ctx context.Context, followerID, followingID valueobject.UserID) (bool, error)
+
+	// GetMutualFollows 获取和用户互相关注的人（好友）
+	//
+	// 业务含义：共同好友推荐（"你们有 N 位共同好友"）的输入
+	// 返回：最多 limit 个用户ID（按关注时间倒序，最近关注的在前）
+	//
+	// 为什么不提供 GetFollowers？
+	// "谁关注了我"可能有几百万人，只通过 FollowerRepository 分页遍历；
+	// 互相关注的人不会多于"我关注的人"，可以一次返回。
+	GetMutualFollows(ctx context.Context, userID valueobject.UserID, limit int) ([]valueobject.UserID, error)
+
+	// FindFollow 查询一条关注关系（包括已静音、已取关的）
+	//
+	// 业务含义：处理关注事件前加载关注关系，按状态转换规则修改
+	// 返回：不存在时返回 nil（不是错误）
+	FindFollow(ctx context.Context, followerID, followingID valueobject.UserID) (*entity.Follow, error)
+
+	// SaveFollow 保存关注关系（写侧）
+	//
+	// 业务含义：根据社交服务的事件维护本地的关注关系
+	// 不存在时创建，存在时更新状态和时间；上面的查询只返回 active 的关注关系
+	SaveFollow(ctx context.Context, follow *entity.Follow) error
+
+	// RemoveFollow 取关（软删除，写侧）
+	//
+	// 业务含义：用户取关之后，上面的查询不再返回这条关注关系
+	// 只把状态改为 removed、记录取关时间，不删除记录：重新关注时复用，乱序的事件据此判断先后
+	// 关注关系不存在或者已经取关时是空操作
+	RemoveFollow(ctx context.Context, followerID, followingID valueobject.UserID, removedAt time.Time) error
+}
+
+// ErrTwoHopExpansionUnsupported 仓储不支持一次查询完成二度扩展
+//
+// 装饰器（缓存、双读）总是实现 TwoHopExpander，被装饰的实现不支持时返回这个错误，
+// 调用方收到后回退到逐个查询。
+var ErrTwoHopExpansionUnsupported = errors.New("two-hop expansion unsupported")
+
+// TwoHopCandidate 二度扩展的一个候选：我关注的人中，最近关注了候选的有哪些
+type TwoHopCandidate struct {
+	Candidate valueobject.UserID   // 推荐候选
+	Referrers []valueobject.UserID // 最近关注了候选的"我关注的人"（推荐信号来源，不重复）
+}
+
+// TwoHopExpander 可选能力：一次查询完成"我关注的人最近关注了谁"（二度扩展）
+//
+// 为什么是可选能力，而不是 SocialGraphRepository 的方法？
+// 只有关注关系和查询在同一个库里的实现（如 MySQL 自连接）才能一次查出来，
+// mock、按用户分片的存储只能逐个查询。
+// 推荐生成器检查社交图谱仓储是否实现了这个接口（类型断言），实现了就优先使用。
+//
+// 与 RecentFollowIndexRepository 的区别：
+// 反向索引在写入时维护（fan-out on write），这里是读取时由数据库完成连接和分组，不需要额外维护数据。
+type TwoHopExpander interface {
+	// ExpandTwoHop 获取用户关注的人最近N天关注了谁，按候选分组
+	//
+	// 参数：
+	// - userID: 为哪个用户查询
+	// - days: 最近多少天
+	// 返回：
+	// - 每个候选一条记录（按信号来源数量降序）
+	// - 不支持时返回 ErrTwoHopExpansionUnsupported
+	ExpandTwoHop(ctx context.Context, userID valueobject.UserID, days int) ([]TwoHopCandidate, error)
 }
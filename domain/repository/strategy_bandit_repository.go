@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// StrategyArmStats 一个展示场景上一种推荐策略（bandit 的一个臂）的累计统计
+type StrategyArmStats struct {
+	Arm     string // 推荐理由类型（如 followed_by_following）
+	Pulls   int64  // 曝光次数
+	Rewards int64  // 归因到这些曝光的关注次数
+}
+
+// StrategyWeightOverride 人工覆盖一个展示场景的策略权重（管理后台设置）
+type StrategyWeightOverride struct {
+	Disabled  bool               // 关闭 bandit：所有策略的权重都是 1（紧急止损）
+	Weights   map[string]float64 // 固定权重（推荐理由类型 → 权重，没有列出的为 1），Disabled 时忽略
+	UpdatedBy string             // 操作人
+	UpdatedAt time.Time
+}
+
+// StrategyBanditRepository 仓储接口：策略权重 bandit 的臂统计和人工覆盖
+//
+// 业务含义：按展示场景累计每种策略的曝光和转化，所有实例共享同一份统计，重启后不会丢失。
+// 租户从 ctx 中读取。
+type StrategyBanditRepository interface {
+	// AddArmStats 累加统计（deltas 中的 Pulls、Rewards 是增量，没有记录时新建）
+	AddArmStats(ctx context.Context, surface string, deltas []*StrategyArmStats) error
+
+	// ListArmStats 一个展示场景上所有策略的累计统计（没有记录时返回空列表）
+	ListArmStats(ctx context.Context, surface string) ([]*StrategyArmStats, error)
+
+	// SaveOverride 保存展示场景的人工覆盖（为 nil 时删除覆盖，恢复 bandit 的权重）
+	SaveOverride(ctx context.Context, surface string, override *StrategyWeightOverride) error
+
+	// FindOverride 展示场景的人工覆盖，没有时返回 nil, nil
+	FindOverride(ctx context.Context, surface string) (*StrategyWeightOverride, error)
+}
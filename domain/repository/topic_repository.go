@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// TopicRepository 仓储接口：用户关注的话题
+//
+// 话题的创建、关注、取消关注由内容上下文负责，推荐上下文只读。
+type TopicRepository interface {
+	// GetFollowedTopics 用户关注的全部话题
+	GetFollowedTopics(ctx context.Context, userID valueobject.UserID) ([]valueobject.Topic, error)
+
+	// GetFollowedTopicsBatch 批量查询多个用户关注的话题（一次查询，避免 N+1）
+	//
+	// 没有关注任何话题的用户不出现在结果中。
+	GetFollowedTopicsBatch(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID][]valueobject.Topic, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"service/domain/valueobject"
+)
+
+// TrustRepository 仓储接口：账号风险分级（来自信任与安全系统）
+//
+// 为什么需要这个接口？
+// 推荐算法会放大"被很多人关注"的账号，
+// 机器人团伙互相关注就能把自己刷进推荐列表。
+// 生成推荐时必须知道哪些账号是高风险的，并把它们排除在外。
+//
+// 为什么定义在领域层？
+// "不推荐高风险账号"是推荐领域的业务规则，
+// 领域服务需要这份数据，但不关心它来自风控 HTTP 服务还是本地缓存。
+//
+// 实现：
+// - infrastructure/client.TrustSafetyHTTPClient：调用风控服务
+// - infrastructure/repository.MockTrustRepository：演示/测试用
+type TrustRepository interface {
+	// GetRiskLevels 批量获取用户的风险等级
+	//
+	// 业务含义：查询一批用户在风控系统中的分级
+	// 返回：userID → 风险等级；风控系统没有数据的用户可以不出现在结果中
+	// （调用方按 RiskUnknown 处理）
+	GetRiskLevels(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID]valueobject.RiskLevel, error)
+}
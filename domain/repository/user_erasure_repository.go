@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// UserErasureRepository 仓储接口：用户数据删除记录（删除权 / right to erasure）
+//
+// 业务含义：
+// 用户要求删除个人数据后，推荐服务不仅要删除已有数据，
+// 还要保证之后不再为他生成推荐（生成推荐本身就会产生新的曝光记录）。
+// 这个仓储记录"哪些用户的数据已被删除"，直到用户重新完成引导（re-onboarding）。
+//
+// 为什么单独一个仓储？
+// 删除标记必须比被删除的数据活得更久：
+// 曝光记录、索引、缓存都删掉了，只有这条标记告诉系统"不要再为他生成"。
+// 标记本身只包含用户ID和时间，不包含个人数据。
+type UserErasureRepository interface {
+	// MarkErased 标记用户的数据已被删除（幂等：重复标记不报错）
+	MarkErased(ctx context.Context, userID valueobject.UserID, erasedAt time.Time) error
+
+	// IsErased 用户的数据是否已被删除（且还没有重新完成引导）
+	IsErased(ctx context.Context, userID valueobject.UserID) (bool, error)
+
+	// ClearErased 清除删除标记（用户重新完成引导后调用，幂等）
+	ClearErased(ctx context.Context, userID valueobject.UserID) error
+}
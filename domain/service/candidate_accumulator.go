@@ -0,0 +1,125 @@
+package service
+
+import (
+	"service/domain/valueobject"
+)
+
+// maxReferrerSample 每个候选最多保留的推荐信号来源（"你关注的人"）样本数
+//
+// 推荐理由只需要"总人数 + 少量头像"，保留全部来源没有意义，
+// 对于被几百个关注的人同时关注的大 V，还会带来大量内存分配。
+const maxReferrerSample = 5
+
+// candidateCounter 单个候选的计数器
+//
+// 使用定长数组保存样本，计数器本身存放在连续的 slice 中，
+// 每个候选不会再单独分配 slice 或结构体。
+type candidateCounter struct {
+	candidate int64                    // 候选用户ID
+	count     int                      // 推荐信号来源总数
+	sampleLen int                      // 样本实际长度
+	sample    [maxReferrerSample]int64 // 推荐信号来源样本（前 maxReferrerSample 个）
+}
+
+// candidateAccumulator 候选累加器：统计"我关注的人最近关注了谁"
+//
+// 为什么不用 map[UserID][]UserID？
+// 关注图很大时（几百个关注 × 每人几十个新关注），旧的实现：
+// - 每个候选一个不断 append 扩容的 slice
+// - map 没有预分配，边插入边扩容
+// - UserID 结构体作为 key 的哈希也比 int64 更慢
+//
+// 新的实现：
+// - int64 作为 key，map 只保存候选在 counters 中的下标
+// - 按估算的候选数预分配 map 和 counters
+// - 计数器是定长结构体（总数 + 有上限的样本），连续存放，没有额外分配
+//
+// 顺序：
+// counters 按候选第一次出现的顺序排列，遍历结果稳定，
+// 不受 map 随机遍历顺序影响。
+type candidateAccumulator struct {
+	index    map[int64]int // 候选ID → counters 下标
+	counters []candidateCounter
+}
+
+// newCandidateAccumulator 构造函数
+//
+// sizeHint: 预估的候选数上限（如所有"最近关注"结果的总条数）
+func newCandidateAccumulator(sizeHint int) *candidateAccumulator {
+	return &candidateAccumulator{
+		index:    make(map[int64]int, sizeHint),
+		counters: make([]candidateCounter, 0, sizeHint),
+	}
+}
+
+// add 记录一次"referrer 最近关注了 candidate"
+func (a *candidateAccumulator) add(candidate, referrer int64) {
+	i, ok := a.index[candidate]
+	if !ok {
+		i = len(a.counters)
+		a.index[candidate] = i
+		a.counters = append(a.counters, candidateCounter{candidate: candidate})
+	}
+
+	counter := &a.counters[i]
+	counter.count++
+	if counter.sampleLen < maxReferrerSample {
+		counter.sample[counter.sampleLen] = referrer
+		counter.sampleLen++
+	}
+}
+
+// Add 实现 CandidateSink：供其他候选来源写入
+func (a *candidateAccumulator) Add(candidate, referrer valueobject.UserID) {
+	a.add(candidate.Value(), referrer.Value())
+}
+
+// merge 合并另一个来源的统计：推荐信号总数相加，样本按先后顺序保留
+func (a *candidateAccumulator) merge(other *candidateAccumulator) {
+	for _, counter := range other.counters {
+		i, ok := a.index[counter.candidate]
+		if !ok {
+			a.index[counter.candidate] = len(a.counters)
+			a.counters = append(a.counters, counter)
+			continue
+		}
+		target := &a.counters[i]
+		target.count += counter.count
+		for _, referrer := range counter.sample[:counter.sampleLen] {
+			if target.sampleLen == maxReferrerSample {
+				break
+			}
+			target.sample[target.sampleLen] = referrer
+			target.sampleLen++
+		}
+	}
+}
+
+// get 获取某个候选的计数器（候选不存在时返回 nil）
+func (a *candidateAccumulator) get(candidate int64) *candidateCounter {
+	i, ok := a.index[candidate]
+	if !ok {
+		return nil
+	}
+	return &a.counters[i]
+}
+
+// all 按第一次出现的顺序返回所有候选的计数器
+//
+// 返回的是内部存储，调用方只读不写
+func (a *candidateAccumulator) all() []candidateCounter {
+	return a.counters
+}
+
+// referrerSample 把计数器中的样本转换为领域对象
+func (c *candidateCounter) referrerSample() []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, c.sampleLen)
+	for _, id := range c.sample[:c.sampleLen] {
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		result = append(result, userID)
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// 内置的候选来源、过滤器名称
+const (
+	SourceFollowing = "following" // "我关注的人最近关注了谁"
+	FilterTrust     = "trust"     // 排除高风险账号（需要 WithTrustFilter）
+)
+
+var errBuiltinComponent = errors.New("built-in candidate component must run inside RecommendationGenerator")
+
+// CandidateSink 候选来源把候选写入这里
+type CandidateSink interface {
+	// Add 记录一次"referrer 推荐了 candidate"（同一个候选的推荐信号会累加）
+	Add(candidate, referrer valueobject.UserID)
+}
+
+// CandidateSource 候选来源
+//
+// 每个来源独立产生候选和推荐信号，多个来源的结果累加到同一个候选统计中。
+// 例如附近的人（geo）、通讯录匹配等新来源实现这个接口，在组合根注册到 CandidateRegistry。
+type CandidateSource interface {
+	Collect(ctx context.Context, forUserID valueobject.UserID, days int, sink CandidateSink) error
+}
+
+// RecommendationSource 自带推荐理由的来源（如"你可能错过了 TA 的新帖子"）
+//
+// CandidateSource 只产生推荐信号，推荐理由总是"你关注的人也关注了TA"；
+// 理由不同的策略实现这个接口，直接产生推荐。
+// 生成器对这些推荐执行同样的过滤器，和其他来源的推荐一起排序（同一个人被多个来源推荐时合并推荐理由）。
+// 需要按名次而不是分数合并多路结果时，见 FusionRanker。
+type RecommendationSource interface {
+	Recommend(ctx context.Context, forUserID valueobject.UserID, days int, policy aggregate.ScoringPolicy) ([]*aggregate.UserRecommendation, error)
+}
+
+// CandidateFilter 候选过滤器：在打分前排除不应该推荐的候选
+type CandidateFilter interface {
+	// Exclude 返回需要排除的候选及原因（排除数量按原因记录在推荐列表中）
+	Exclude(ctx context.Context, forUserID valueobject.UserID, candidates []valueobject.UserID) map[valueobject.UserID]aggregate.ExclusionReason
+}
+
+// followingSource 内置来源：由生成器自己的仓储和选项（索引、采样、分页）实现
+type followingSource struct{}
+
+func (followingSource) Collect(context.Context, valueobject.UserID, int, CandidateSink) error {
+	return errBuiltinComponent
+}
+
+// trustFilter 内置过滤器：使用 WithTrustFilter 配置的风控仓储和阈值
+type trustFilter struct{}
+
+func (trustFilter) Exclude(context.Context, valueobject.UserID, []valueobject.UserID) map[valueobject.UserID]aggregate.ExclusionReason {
+	return nil
+}
+
+// CandidateRegistry 候选来源、过滤器注册表（按名称）
+//
+// 为什么需要？
+// 去掉某个来源（如某个场景不需要 geo 来源）、调整过滤器顺序，
+// 以前要改代码重新发布；现在组合根把所有可用的实现注册到这里，
+// 配置（candidate_pipeline）按名称选择启用哪些、按什么顺序执行。
+//
+// 内置的 following 来源和 trust 过滤器已经注册。
+type CandidateRegistry struct {
+	sources               map[string]CandidateSource
+	recommendationSources map[string]RecommendationSource
+	filters               map[string]CandidateFilter
+}
+
+// NewCandidateRegistry 构造函数
+func NewCandidateRegistry() *CandidateRegistry {
+	return &CandidateRegistry{
+		sources:               map[string]CandidateSource{SourceFollowing: followingSource{}},
+		recommendationSources: map[string]RecommendationSource{},
+		filters:               map[string]CandidateFilter{FilterTrust: trustFilter{}},
+	}
+}
+
+// RegisterSource 注册候选来源（同名覆盖）
+func (r *CandidateRegistry) RegisterSource(name string, source CandidateSource) {
+	delete(r.recommendationSources, name)
+	r.sources[name] = source
+}
+
+// RegisterRecommendationSource 注册自带推荐理由的来源（和 RegisterSource 共用名称空间，同名覆盖）
+func (r *CandidateRegistry) RegisterRecommendationSource(name string, source RecommendationSource) {
+	delete(r.sources, name)
+	r.recommendationSources[name] = source
+}
+
+// RegisterFilter 注册过滤器（同名覆盖）
+func (r *CandidateRegistry) RegisterFilter(name string, filter CandidateFilter) {
+	r.filters[name] = filter
+}
+
+// Pipeline 按名称选出启用的来源和过滤器（按给定顺序执行）
+//
+// 名称不存在时返回错误（配置写错应该在启动时发现，而不是悄悄少一个来源）。
+// sourceNames 不能为空；filterNames 为空表示不做过滤。
+func (r *CandidateRegistry) Pipeline(sourceNames, filterNames []string) (GeneratorOption, error) {
+	if len(sourceNames) == 0 {
+		return nil, errors.New("candidate pipeline: at least one source is required")
+	}
+	sources := make([]CandidateSource, 0, len(sourceNames))
+	var recommendationSources []RecommendationSource
+	for _, name := range sourceNames {
+		if source, ok := r.sources[name]; ok {
+			sources = append(sources, source)
+			continue
+		}
+		source, ok := r.recommendationSources[name]
+		if !ok {
+			return nil, fmt.Errorf("candidate pipeline: unknown source %q", name)
+		}
+		recommendationSources = append(recommendationSources, source)
+	}
+	filters := make([]CandidateFilter, 0, len(filterNames))
+	for _, name := range filterNames {
+		filter, ok := r.filters[name]
+		if !ok {
+			return nil, fmt.Errorf("candidate pipeline: unknown filter %q", name)
+		}
+		filters = append(filters, filter)
+	}
+	return func(g *RecommendationGenerator) {
+		g.sources = sources
+		g.recommendationSources = recommendationSources
+		g.filters = filters
+		g.filterNames = filterNames
+	}, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// maxColdStartUsers 冷启动时每份名单（编辑精选、全站热门）最多取多少人
+const maxColdStartUsers = 50
+
+// ColdStartSource 冷启动推荐：编辑精选 + 全站热门（"编辑推荐"、"很多人都在关注TA"）
+//
+// 为什么需要？
+// 刚注册的用户还没有关注任何人，二度关系、共同好友这些来源都没有输入，推荐列表是空的；
+// 新用户恰恰最需要"先关注几个人"，空列表等于把人留在空白的首页。
+//
+// 规则：
+// 1. 编辑精选在前（理由权重更高），全站热门在后；同一个人只推荐一次（保留编辑推荐）
+// 2. 排除自己、已经关注的人
+// 3. 两份名单都按仓储返回的顺序，分数相同时先加入的在前（见 RecommendationList.GetTopN）
+//
+// 容错：一份名单查询失败时只用另一份；都失败时返回错误（生成器跳过这个来源，列表仍然为空）。
+//
+// 不是候选来源配置（candidate_pipeline）中的来源：只在其他来源都没有推荐时使用（见 WithColdStart）。
+type ColdStartSource struct {
+	popularRepo     repository.PopularUsersRepository
+	socialGraphRepo repository.SocialGraphRepository
+}
+
+// NewColdStartSource 构造函数
+func NewColdStartSource(
+	popularRepo repository.PopularUsersRepository,
+	socialGraphRepo repository.SocialGraphRepository,
+) *ColdStartSource {
+	return &ColdStartSource{popularRepo: popularRepo, socialGraphRepo: socialGraphRepo}
+}
+
+// Recommend 实现 RecommendationSource（days 不使用：名单和时间窗口无关）
+func (s *ColdStartSource) Recommend(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	days int,
+	policy aggregate.ScoringPolicy,
+) ([]*aggregate.UserRecommendation, error) {
+	curated, curatedErr := s.popularRepo.GetCuratedUsers(ctx, maxColdStartUsers)
+	popular, popularErr := s.popularRepo.GetPopularUsers(ctx, maxColdStartUsers)
+	if curatedErr != nil && popularErr != nil {
+		return nil, popularErr
+	}
+
+	// 已经关注的人不推荐（冷启动的用户通常一个也没有关注，查询很便宜）
+	followings, err := s.socialGraphRepo.GetFollowings(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[valueobject.UserID]bool, len(followings)+1)
+	skip[forUserID] = true
+	for _, userID := range followings {
+		skip[userID] = true
+	}
+
+	recommendations := make([]*aggregate.UserRecommendation, 0, len(curated)+len(popular))
+	add := func(users []valueobject.UserID, reason valueobject.RecommendationReason) {
+		for _, userID := range users {
+			if skip[userID] {
+				continue
+			}
+			skip[userID] = true
+			rec, err := aggregate.NewUserRecommendationWithPolicy(userID, reason, 0, policy)
+			if err != nil {
+				continue
+			}
+			recommendations = append(recommendations, rec)
+		}
+	}
+	add(curated, valueobject.NewEditorPickReason())
+	add(popular, valueobject.NewPopularOnPlatformReason())
+	return recommendations, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// SourceDormant 推荐来源名称：你关注了、但很久没有互动的人
+const SourceDormant = "dormant"
+
+const (
+	// defaultDormantAfter 多久没有互动算"很久"
+	defaultDormantAfter = 30 * 24 * time.Hour
+	// maxDormantFollowings 最多检查多少个关注的人（关注列表的前 N 个）
+	maxDormantFollowings = 500
+	// maxDormantRecommendations 最多产生多少条推荐（不让重新互动的推荐占满列表）
+	maxDormantRecommendations = 10
+)
+
+// DormantConnectionSource 推荐来源：重新互动（"你可能错过了 TA 的新帖子"）
+//
+// 为什么需要？
+// 关注了很多人之后，大部分关注关系会慢慢"沉睡"：不再点赞、评论、访问主页，
+// TA 发的帖子也被信息流里更活跃的人淹没。
+// 把这些人里最近还在发帖的重新推荐出来，比推荐陌生人更容易产生互动。
+//
+// 规则：
+// 1. 候选是用户已经关注的人（关注列表的前 maxDormantFollowings 个）
+// 2. 从来没有互动过、或者最近一次互动早于 dormantAfter 的，算沉睡的关系
+// 3. 只推荐最近 days 天发过帖子的人（没有新帖子就没有"错过"）
+// 4. 按分数取前 maxDormantRecommendations 个
+//
+// 通过候选来源配置启用（candidate_pipeline 的 sources 中加入 dormant），
+// 和其他来源的推荐一起排序。
+type DormantConnectionSource struct {
+	socialGraphRepo repository.SocialGraphRepository
+	contentRepo     repository.ContentRepository
+	interactionRepo repository.InteractionRepository
+	dormantAfter    time.Duration
+	concurrency     int
+	now             func() time.Time
+}
+
+// NewDormantConnectionSource 构造函数（30 天没有互动算沉睡）
+func NewDormantConnectionSource(
+	socialGraphRepo repository.SocialGraphRepository,
+	contentRepo repository.ContentRepository,
+	interactionRepo repository.InteractionRepository,
+) *DormantConnectionSource {
+	return &DormantConnectionSource{
+		socialGraphRepo: socialGraphRepo,
+		contentRepo:     contentRepo,
+		interactionRepo: interactionRepo,
+		dormantAfter:    defaultDormantAfter,
+		concurrency:     defaultConcurrency,
+		now:             time.Now,
+	}
+}
+
+// Recommend 实现 RecommendationSource
+func (s *DormantConnectionSource) Recommend(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	days int,
+	policy aggregate.ScoringPolicy,
+) ([]*aggregate.UserRecommendation, error) {
+	followings, err := s.socialGraphRepo.GetFollowings(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(followings) > maxDormantFollowings {
+		followings = followings[:maxDormantFollowings]
+	}
+	lastInteractions, err := s.interactionRepo.GetLastInteractions(ctx, forUserID, followings)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := s.now().Add(-s.dormantAfter)
+	dormant := make([]valueobject.UserID, 0, len(followings))
+	for _, following := range followings {
+		if last, ok := lastInteractions[following]; ok && last.After(cutoff) {
+			continue
+		}
+		dormant = append(dormant, following)
+	}
+
+	postCounts := make([]int, len(dormant))
+	err = runBounded(ctx, len(dormant), s.concurrency, func(ctx context.Context, i int) {
+		postCount, err := s.contentRepo.CountRecentPosts(ctx, dormant[i], days)
+		if err != nil {
+			postCount = 0 // 容错：获取失败按没有新帖子处理（不推荐）
+		}
+		postCounts[i] = postCount
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]*aggregate.UserRecommendation, 0, len(dormant))
+	for i, targetUserID := range dormant {
+		if postCounts[i] == 0 {
+			continue
+		}
+		reason := valueobject.NewMissedPostsReason(postCounts[i])
+		rec, err := aggregate.NewUserRecommendationWithPolicy(targetUserID, reason, postCounts[i], policy)
+		if err != nil {
+			continue
+		}
+		recommendations = append(recommendations, rec)
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score() > recommendations[j].Score()
+	})
+	if len(recommendations) > maxDormantRecommendations {
+		recommendations = recommendations[:maxDormantRecommendations]
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency 推荐生成时默认的并发度
+//
+// 并发度决定同时有多少个仓储查询在执行：
+// - 太小：关注了几百人的用户，串行查询延迟很高
+// - 太大：一次请求打出大量并发查询，容易压垮数据库
+const defaultConcurrency = 16
+
+// runBounded 以有限并发执行 n 个任务（固定大小的 worker pool）
+//
+// 语义：
+// - 最多 concurrency 个任务同时执行；concurrency <= 1 时串行执行
+// - ctx 取消后不再派发新任务，已开始的任务通过 ctx 自行感知取消
+// - 任务的错误由任务函数自己处理（容错语义由调用方决定）
+// - 返回 ctx.Err()：调用方据此判断任务是否被取消而没有全部执行
+//
+// 结果收集：
+// 任务函数只写入自己下标 i 对应的结果槽位（如 results[i]），
+// 不同任务之间没有共享写入，不需要加锁。
+func runBounded(ctx context.Context, n, concurrency int, task func(ctx context.Context, i int)) error {
+	if concurrency <= 1 {
+		for i := 0; i < n; i++ {
+			if ctx.Err() != nil {
+				break
+			}
+			task(ctx, i)
+		}
+		return ctx.Err()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				task(ctx, i)
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+// FollowingSamplingPolicy 关注列表采样策略
+//
+// 为什么需要采样？
+// 推荐生成要对"我关注的每个人"查询最近关注，
+// 关注了几万人的用户，一次推荐就是几万次查询，代价无法接受。
+//
+// 采样规则（关注数超过 Threshold 时生效）：
+// - 最近关注的 RecentK 个人：全部保留（最近关注的人最能代表当前兴趣）
+// - 其余的人中：随机抽取 RandomN 个（保留长期兴趣的覆盖面）
+//
+// 随机种子：
+// 按"用户 + 日期"生成，同一个用户同一天内采样结果稳定，
+// 反复刷新不会因为采样不同而看到完全不同的推荐。
+//
+// 实际场景：
+//
+//	策略：Threshold=5000, RecentK=1000, RandomN=1000
+//	用户关注了 30000 人 → 使用最近关注的 1000 人 + 随机 1000 人
+//	用户关注了 800 人 → 不采样，全部使用
+type FollowingSamplingPolicy struct {
+	Threshold int // 关注数超过该值时采样
+	RecentK   int // 保留最近关注的人数
+	RandomN   int // 从其余的人中随机抽取的人数
+}
+
+// DefaultFollowingSamplingPolicy 默认采样策略
+func DefaultFollowingSamplingPolicy() FollowingSamplingPolicy {
+	return FollowingSamplingPolicy{
+		Threshold: 5000,
+		RecentK:   1000,
+		RandomN:   1000,
+	}
+}
+
+// Sample 对关注列表采样
+//
+// 参数：
+// - forUserID: 为哪个用户采样（用于生成随机种子）
+// - followings: 关注的人，按关注时间倒序（最近关注的在前）
+// - now: 当前时间（用于生成随机种子）
+//
+// 返回采样后的关注列表（不修改传入的 slice）
+func (p FollowingSamplingPolicy) Sample(
+	forUserID valueobject.UserID,
+	followings []valueobject.UserID,
+	now time.Time,
+) []valueobject.UserID {
+	if !p.Applies(len(followings)) {
+		return followings
+	}
+
+	sampler := p.newSampler(forUserID, now)
+	for _, following := range followings {
+		sampler.offer(following)
+	}
+	return sampler.result()
+}
+
+// Applies 关注数为 total 时是否需要采样
+func (p FollowingSamplingPolicy) Applies(total int) bool {
+	return p.Threshold > 0 && total > p.Threshold
+}
+
+// followingSampler 流式采样器：关注的人逐个（按关注时间倒序）传入，内存占用只有样本大小
+//
+// - 前 RecentK 个：直接保留
+// - 之后的：蓄水池采样（Reservoir Sampling）保留 RandomN 个
+//
+// 蓄水池采样中每个人被选中的概率相同，不需要提前知道总数。
+//
+// 一次加载整个列表（Sample）和分页遍历（ScanFollowings）使用同一个采样器，
+// 两种方式对同一个用户的采样结果一致。
+type followingSampler struct {
+	recentK   int
+	randomN   int
+	rng       *rand.Rand
+	recent    []valueobject.UserID
+	reservoir []valueobject.UserID
+	seen      int // 进入蓄水池阶段后见过的人数
+}
+
+// newSampler 创建流式采样器（随机种子按"用户 + 日期"生成）
+func (p FollowingSamplingPolicy) newSampler(forUserID valueobject.UserID, now time.Time) *followingSampler {
+	day := now.Year()*1000 + now.YearDay()
+	return &followingSampler{
+		recentK:   max(p.RecentK, 0),
+		randomN:   max(p.RandomN, 0),
+		rng:       rand.New(rand.NewPCG(uint64(forUserID.Value()), uint64(day))),
+		recent:    make([]valueobject.UserID, 0, max(p.RecentK, 0)),
+		reservoir: make([]valueobject.UserID, 0, max(p.RandomN, 0)),
+	}
+}
+
+// offer 传入下一个关注的人
+func (s *followingSampler) offer(following valueobject.UserID) {
+	if len(s.recent) < s.recentK {
+		s.recent = append(s.recent, following)
+		return
+	}
+	if s.randomN == 0 {
+		return
+	}
+
+	s.seen++
+	if len(s.reservoir) < s.randomN {
+		s.reservoir = append(s.reservoir, following)
+		return
+	}
+	if j := s.rng.IntN(s.seen); j < s.randomN {
+		s.reservoir[j] = following
+	}
+}
+
+// result 采样结果：最近关注的人在前，随机样本在后
+func (s *followingSampler) result() []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, len(s.recent)+len(s.reservoir))
+	result = append(result, s.recent...)
+	return append(result, s.reservoir...)
+}
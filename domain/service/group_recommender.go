@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// GroupRecommender 领域服务：小组（社区）推荐
+//
+// 业务规则：
+// "我关注的人"参与的小组，我很可能也感兴趣。
+// 是成员的"我关注的人"越多、他们在小组中的占比越高，分数越高（见 aggregate.NewGroupRecommendation）；
+// 我已经加入的小组不推荐。
+type GroupRecommender struct {
+	socialGraphRepo repository.SocialGraphRepository
+	membershipRepo  repository.GroupMembershipRepository
+}
+
+// NewGroupRecommender 构造函数
+func NewGroupRecommender(
+	socialGraphRepo repository.SocialGraphRepository,
+	membershipRepo repository.GroupMembershipRepository,
+) *GroupRecommender {
+	return &GroupRecommender{
+		socialGraphRepo: socialGraphRepo,
+		membershipRepo:  membershipRepo,
+	}
+}
+
+// groupCounter 单个小组的计数器（推荐信号来源总数 + 样本）
+type groupCounter struct {
+	groupID valueobject.GroupID
+	count   int
+	sample  []valueobject.UserID
+}
+
+// RecommendGroups 为用户推荐小组
+//
+// 步骤：
+// 1. 获取用户关注的人（最多 maxNetworkSources 个）
+// 2. 分批查询他们加入的小组，统计每个小组有多少个"我关注的人"
+// 3. 排除用户已经加入的小组（记录为 ExclusionAlreadyMember）
+// 4. 批量查询成员数，创建推荐并加入列表（列表负责去重和排序）
+//
+// 成员数查询失败时不计算重合度加分，不让整个推荐失败。
+func (g *GroupRecommender) RecommendGroups(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	weights valueobject.ScoringWeights,
+) (*aggregate.GroupRecommendationList, error) {
+	list := aggregate.NewGroupRecommendationList(forUserID)
+
+	followings, err := g.socialGraphRepo.GetFollowings(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(followings) > maxNetworkSources {
+		followings = followings[:maxNetworkSources]
+	}
+
+	var counters []*groupCounter // 按第一次出现的顺序，结果稳定
+	index := make(map[int64]*groupCounter)
+	for start := 0; start < len(followings); start += networkBatchSize {
+		batch := followings[start:min(start+networkBatchSize, len(followings))]
+		groupsByUser, err := g.membershipRepo.GetGroupsOfUsers(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, following := range batch {
+			for _, groupID := range groupsByUser[following] {
+				counter, ok := index[groupID.Value()]
+				if !ok {
+					counter = &groupCounter{groupID: groupID}
+					index[groupID.Value()] = counter
+					counters = append(counters, counter)
+				}
+				counter.count++
+				if len(counter.sample) < maxReferrerSample {
+					counter.sample = append(counter.sample, following)
+				}
+			}
+		}
+	}
+
+	joined, err := g.membershipRepo.GetUserGroups(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	member := make(map[int64]bool, len(joined))
+	for _, groupID := range joined {
+		member[groupID.Value()] = true
+	}
+
+	candidates := make([]*groupCounter, 0, len(counters))
+	groupIDs := make([]valueobject.GroupID, 0, len(counters))
+	for _, counter := range counters {
+		if member[counter.groupID.Value()] {
+			list.RecordExclusion(aggregate.ExclusionAlreadyMember)
+			continue
+		}
+		candidates = append(candidates, counter)
+		groupIDs = append(groupIDs, counter.groupID)
+	}
+	if len(candidates) == 0 {
+		return list, nil
+	}
+
+	memberCounts, err := g.membershipRepo.CountMembers(ctx, groupIDs)
+	if err != nil {
+		memberCounts = nil // 容错：不计算重合度加分
+	}
+
+	now := time.Now()
+	for _, counter := range candidates {
+		reason := valueobject.NewFriendsAreMembersReason(counter.sample, counter.count)
+		rec, err := aggregate.NewGroupRecommendation(counter.groupID, reason, memberCounts[counter.groupID], weights, now)
+		if err != nil {
+			continue
+		}
+		_ = list.AddRecommendation(rec)
+	}
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// fakeGroupMembershipRepo 测试用小组成员关系仓储
+type fakeGroupMembershipRepo struct {
+	groups       map[int64][]int64 // 用户 → 加入的小组
+	memberCounts map[int64]int     // 小组 → 成员数
+	countErr     error
+}
+
+func (r *fakeGroupMembershipRepo) GetUserGroups(ctx context.Context, userID valueobject.UserID) ([]valueobject.GroupID, error) {
+	var result []valueobject.GroupID
+	for _, id := range r.groups[userID.Value()] {
+		groupID, _ := valueobject.NewGroupID(id)
+		result = append(result, groupID)
+	}
+	return result, nil
+}
+
+func (r *fakeGroupMembershipRepo) GetGroupsOfUsers(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID][]valueobject.GroupID, error) {
+	result := make(map[valueobject.UserID][]valueobject.GroupID)
+	for _, userID := range userIDs {
+		if groups, _ := r.GetUserGroups(ctx, userID); len(groups) > 0 {
+			result[userID] = groups
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeGroupMembershipRepo) CountMembers(ctx context.Context, groupIDs []valueobject.GroupID) (map[valueobject.GroupID]int, error) {
+	if r.countErr != nil {
+		return nil, r.countErr
+	}
+	result := make(map[valueobject.GroupID]int, len(groupIDs))
+	for _, groupID := range groupIDs {
+		result[groupID] = r.memberCounts[groupID.Value()]
+	}
+	return result, nil
+}
+
+func TestGroupRecommender_RecommendGroups(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 用户 1 关注了 2、3、4，已经加入小组 10
+	membership := &fakeGroupMembershipRepo{
+		groups: map[int64][]int64{
+			1: {10},
+			2: {10, 20, 30},
+			3: {20, 30},
+			4: {40},
+		},
+		// 小组 20 和 30 都有 2 个"我关注的人"，但 30 是个小组，重合度更高
+		memberCounts: map[int64]int{20: 10000, 30: 4, 40: 100},
+	}
+	recommender := NewGroupRecommender(&fakeSocialGraphRepo{followingCount: 3}, membership)
+
+	list, err := recommender.RecommendGroups(context.Background(), forUserID, valueobject.DefaultScoringWeights())
+	if err != nil {
+		t.Fatalf("RecommendGroups failed: %v", err)
+	}
+	if got := list.Exclusions()[aggregate.ExclusionAlreadyMember]; got != 1 {
+		t.Errorf("already_member exclusions = %d, want 1", got)
+	}
+	want := []int64{30, 20, 40}
+	top := list.GetTopN(10)
+	if len(top) != len(want) {
+		t.Fatalf("len(top) = %d, want %d", len(top), len(want))
+	}
+	for i, id := range want {
+		if top[i].GroupID().Value() != id {
+			t.Errorf("top[%d] = group %d (score %d), want group %d", i, top[i].GroupID().Value(), top[i].Score(), id)
+		}
+	}
+
+	// 成员数查询失败：不计算重合度加分，推荐仍然返回
+	membership.countErr = errors.New("community service unavailable")
+	list, err = recommender.RecommendGroups(context.Background(), forUserID, valueobject.DefaultScoringWeights())
+	if err != nil {
+		t.Fatalf("RecommendGroups failed: %v", err)
+	}
+	if list.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", list.Count())
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"sort"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// Ranker 排序器：把一批候选排成推荐列表（交错实验比较两个排序器）
+type Ranker interface {
+	// Rank 返回排好序的推荐（不修改传入的 slice）
+	Rank(candidates []*aggregate.UserRecommendation) []*aggregate.UserRecommendation
+}
+
+// WeightsRanker 排序器：按一套打分权重的分数降序排列（分数相同时保持原来的顺序）
+type WeightsRanker struct {
+	weights valueobject.ScoringWeights
+}
+
+// NewWeightsRanker 构造函数
+func NewWeightsRanker(weights valueobject.ScoringWeights) *WeightsRanker {
+	return &WeightsRanker{weights: weights}
+}
+
+// Rank 实现 Ranker
+func (r *WeightsRanker) Rank(candidates []*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	ranked := make([]*aggregate.UserRecommendation, len(candidates))
+	copy(ranked, candidates)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].ScoreWith(r.weights) > ranked[j].ScoreWith(r.weights)
+	})
+	return ranked
+}
+
+// InterleavingTeam 交错列表中一条推荐来自哪个排序器
+type InterleavingTeam string
+
+const (
+	TeamA InterleavingTeam = "a"
+	TeamB InterleavingTeam = "b"
+)
+
+// InterleavedRecommendation 交错列表中的一条推荐
+type InterleavedRecommendation struct {
+	Recommendation *aggregate.UserRecommendation
+	Team           InterleavingTeam
+}
+
+// TeamDraftInterleave 团队选秀交错（Team-Draft Interleaving）
+//
+// 为什么需要？
+// 按流量切分的 A/B 实验要积累几周的数据才能分出两个排序的好坏；
+// 交错把两个排序的结果混在同一个列表里给同一个用户看，
+// 用户关注了谁就是在两个排序之间投票，同样的流量能更快得出结论。
+//
+// 规则（像两个队长轮流挑人）：
+// 1. 每一轮，已经挑得少的一方先挑；挑得一样多时抛硬币（coin 返回 true 时 A 先）
+// 2. 挑人的一方选自己排序中第一个还没被挑走的推荐，并把它记在自己名下
+// 3. 一方的排序用完后，另一方继续挑，直到 limit 条或两边都用完
+//
+// 同一个被推荐用户只出现一次（按 TargetUserID 判断）；limit < 0 表示不限制。
+func TeamDraftInterleave(
+	rankingA, rankingB []*aggregate.UserRecommendation,
+	limit int,
+	coin func() bool,
+) []InterleavedRecommendation {
+	total := len(rankingA) + len(rankingB)
+	if limit < 0 || limit > total {
+		limit = total
+	}
+	result := make([]InterleavedRecommendation, 0, limit)
+	picked := make(map[valueobject.UserID]bool, limit)
+	nextA, nextB := 0, 0
+	countA, countB := 0, 0
+
+	// next 辅助函数：排序中下一个还没被挑走的位置（用完时返回 len）
+	next := func(ranking []*aggregate.UserRecommendation, i int) int {
+		for i < len(ranking) && picked[ranking[i].TargetUserID()] {
+			i++
+		}
+		return i
+	}
+
+	for len(result) < limit {
+		nextA, nextB = next(rankingA, nextA), next(rankingB, nextB)
+		hasA, hasB := nextA < len(rankingA), nextB < len(rankingB)
+		if !hasA && !hasB {
+			break
+		}
+		pickA := hasA && (!hasB || countA < countB || (countA == countB && coin()))
+		if pickA {
+			rec := rankingA[nextA]
+			picked[rec.TargetUserID()] = true
+			result = append(result, InterleavedRecommendation{Recommendation: rec, Team: TeamA})
+			countA++
+		} else {
+			rec := rankingB[nextB]
+			picked[rec.TargetUserID()] = true
+			result = append(result, InterleavedRecommendation{Recommendation: rec, Team: TeamB})
+			countB++
+		}
+	}
+	return result
+}
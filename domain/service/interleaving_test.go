@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+func TestTeamDraftInterleave(t *testing.T) {
+	recs := make(map[int64]*aggregate.UserRecommendation)
+	ranking := func(ids ...int64) []*aggregate.UserRecommendation {
+		result := make([]*aggregate.UserRecommendation, 0, len(ids))
+		for _, id := range ids {
+			if recs[id] == nil {
+				target, _ := valueobject.NewUserID(id)
+				recs[id], _ = aggregate.NewUserRecommendation(target, valueobject.NewMissedPostsReason(1), 0)
+			}
+			result = append(result, recs[id])
+		}
+		return result
+	}
+	a := ranking(1, 2, 3, 4)
+	b := ranking(2, 5, 1, 6)
+
+	// 硬币总是让 A 先挑：A 1，B 2，A 3（B 的 2 已被挑走，A 的 2 跳过），B 5，A 4
+	got := TeamDraftInterleave(a, b, 5, func() bool { return true })
+	want := []struct {
+		id   int64
+		team InterleavingTeam
+	}{{1, TeamA}, {2, TeamB}, {3, TeamA}, {5, TeamB}, {4, TeamA}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d items, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Recommendation.TargetUserID().Value() != w.id || got[i].Team != w.team {
+			t.Errorf("item %d = (%d, %s), want (%d, %s)",
+				i, got[i].Recommendation.TargetUserID().Value(), got[i].Team, w.id, w.team)
+		}
+	}
+
+	// 不限制数量：两边的推荐都用完，每个被推荐用户只出现一次
+	all := TeamDraftInterleave(a, b, -1, func() bool { return false })
+	if len(all) != 6 {
+		t.Errorf("unlimited: got %d items, want 6", len(all))
+	}
+	if all[0].Team != TeamB || all[0].Recommendation.TargetUserID().Value() != 2 {
+		t.Errorf("coin false: first item = (%d, %s), want (2, b)", all[0].Recommendation.TargetUserID().Value(), all[0].Team)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// SourceMutual 推荐来源名称：和你有很多共同好友的人
+const SourceMutual = "mutual"
+
+const (
+	// maxMutualFriends 最多使用多少个好友（自己的好友、好友的好友各取前 N 个）
+	maxMutualFriends = 200
+	// minMutualFriends 至少有几位共同好友才推荐（只有一位共同好友的信号太弱）
+	minMutualFriends = 2
+	// maxMutualRecommendations 最多产生多少条推荐
+	maxMutualRecommendations = 20
+)
+
+// MutualFollowSource 推荐来源：共同好友（"你们有 N 位共同好友"）
+//
+// 为什么需要？
+// following 来源只看单向的关注："你关注的人关注了 TA" 可能只是都关注了同一个大 V。
+// 互相关注（好友）代表真实的社交关系，和你的好友也互相关注的人，
+// 很可能是同学、同事这类你在现实中认识、但还没有关注的人。
+//
+// 规则：
+// 1. 取用户的好友（互相关注，最近的前 maxMutualFriends 个）
+// 2. 取每个好友的好友，统计每个人是多少位好友的好友（共同好友数）
+// 3. 排除自己、已经关注的人，共同好友少于 minMutualFriends 的不推荐
+// 4. 按共同好友数打分（ReasonManyMutualFollows 的权重），取前 maxMutualRecommendations 个
+//
+// 不查询帖子数：共同好友推荐的是"认识的人"，和 TA 最近是否发帖无关。
+//
+// 通过候选来源配置启用（candidate_pipeline 的 sources 中加入 mutual）。
+type MutualFollowSource struct {
+	socialGraphRepo repository.SocialGraphRepository
+	concurrency     int
+}
+
+// NewMutualFollowSource 构造函数
+func NewMutualFollowSource(socialGraphRepo repository.SocialGraphRepository) *MutualFollowSource {
+	return &MutualFollowSource{
+		socialGraphRepo: socialGraphRepo,
+		concurrency:     defaultConcurrency,
+	}
+}
+
+// Recommend 实现 RecommendationSource（days 不使用：好友关系没有时间窗口）
+func (s *MutualFollowSource) Recommend(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	days int,
+	policy aggregate.ScoringPolicy,
+) ([]*aggregate.UserRecommendation, error) {
+	friends, err := s.socialGraphRepo.GetMutualFollows(ctx, forUserID, maxMutualFriends)
+	if err != nil {
+		return nil, err
+	}
+	if len(friends) < minMutualFriends {
+		return nil, nil
+	}
+
+	// 共同好友统计：候选 → 是哪些好友的好友
+	var mu sync.Mutex
+	mutuals := make(map[valueobject.UserID][]valueobject.UserID)
+	err = runBounded(ctx, len(friends), s.concurrency, func(ctx context.Context, i int) {
+		friendsOfFriend, err := s.socialGraphRepo.GetMutualFollows(ctx, friends[i], maxMutualFriends)
+		if err != nil {
+			return // 容错：某个好友查询失败只是少一些信号
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, candidate := range friendsOfFriend {
+			mutuals[candidate] = append(mutuals[candidate], friends[i])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 已经关注的人（包括好友）不推荐
+	followings, err := s.socialGraphRepo.GetFollowings(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	following := make(map[valueobject.UserID]bool, len(followings))
+	for _, userID := range followings {
+		following[userID] = true
+	}
+
+	recommendations := make([]*aggregate.UserRecommendation, 0, len(mutuals))
+	for candidate, via := range mutuals {
+		if len(via) < minMutualFriends || candidate.Equals(forUserID) || following[candidate] {
+			continue
+		}
+		sample := via
+		if len(sample) > maxReferrerSample {
+			sample = sample[:maxReferrerSample]
+		}
+		reason := valueobject.NewManyMutualFollowsReason(sample, len(via))
+		rec, err := aggregate.NewUserRecommendationWithPolicy(candidate, reason, 0, policy)
+		if err != nil {
+			continue
+		}
+		recommendations = append(recommendations, rec)
+	}
+	// 分数相同时按用户ID排序（map 的遍历顺序不固定，保证结果稳定）
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score() != recommendations[j].Score() {
+			return recommendations[i].Score() > recommendations[j].Score()
+		}
+		return recommendations[i].TargetUserID().Value() < recommendations[j].TargetUserID().Value()
+	})
+	if len(recommendations) > maxMutualRecommendations {
+		recommendations = recommendations[:maxMutualRecommendations]
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// GeneratePostRecommendations 生成帖子推荐："我关注的人最近关注的作者"最近发的帖子
+//
+// 业务需求（探索页）：
+// "给我看看圈子里正在被关注的人最近发了什么"，而不只是推荐关注这个人。
+//
+// 和用户推荐共用候选收集（候选来源、过滤器、采样、反向关注索引）：
+// 1. 得到二度关系中的作者（即用户推荐的候选，已经执行过滤器）
+// 2. 并发取每个作者最近的 postsPerAuthor 篇帖子
+// 3. 只保留最近 days 天内发布的帖子，按作者的推荐信号和帖子的新鲜度打分
+//
+// 容错：某个作者的帖子获取失败时跳过这个作者。
+//
+// 返回按分数降序排列的帖子推荐。
+func (g *RecommendationGenerator) GeneratePostRecommendations(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	days int,
+	postsPerAuthor int,
+) ([]*aggregate.PostRecommendation, error) {
+	// 用户推荐列表只用来承接候选收集的记录（排除、采样），不返回
+	list := aggregate.NewRecommendationList(forUserID)
+	authors, accumulator, err := g.selectCandidates(ctx, list, days)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([][]*entity.Post, len(authors))
+	err = runBounded(ctx, len(authors), g.concurrency, func(ctx context.Context, i int) {
+		recent, err := g.contentRepo.GetRecentPosts(ctx, authors[i], postsPerAuthor)
+		if err != nil {
+			return // 容错：跳过这个作者
+		}
+		posts[i] = recent
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	window := time.Duration(days) * 24 * time.Hour
+	weights := g.scoringWeights(ctx)
+	var result []*aggregate.PostRecommendation
+	for i, author := range authors {
+		counter := accumulator.get(author.Value())
+		reason := valueobject.NewSampledFollowedByFollowingReason(counter.referrerSample(), counter.count)
+		for _, post := range posts[i] {
+			rec, err := aggregate.NewPostRecommendation(post, reason, weights, window, now)
+			if err != nil {
+				continue // 跳过窗口之外的帖子
+			}
+			result = append(result, rec)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score() > result[j].Score()
+	})
+	return result, nil
+}
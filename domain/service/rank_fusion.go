@@ -0,0 +1,140 @@
+package service
+
+import (
+	"sort"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// DefaultRRFK 倒数排名融合的平滑常数（论文和常见实现的取值）
+const DefaultRRFK = 60
+
+// FusionRanker 融合排序器：把多路（候选来源、推荐策略）各自排好序的推荐合并成一个列表
+//
+// 和 Ranker 的区别：Ranker 给一批候选排序；FusionRanker 的输入是多个已经排好序的列表，
+// 同一个人可能出现在多路中，融合后只出现一次。
+type FusionRanker interface {
+	// Fuse 合并各路排序（路名 → 按顺序排好的推荐），不修改传入的 slice
+	Fuse(rankings map[string][]*aggregate.UserRecommendation) []*aggregate.UserRecommendation
+}
+
+// WeightedMergeRanker 加权合并：候选的融合分数 = 推荐分数 × 这一路的权重
+//
+// 同一个人出现在多路时取融合分数最高的一条（推荐理由、分数都来自那一路）。
+// 适合各路分数可以比较的情况（都是 ScoringPolicy 打的分）。
+type WeightedMergeRanker struct {
+	weights map[string]float64
+}
+
+// NewWeightedMergeRanker 构造函数（没有列出的路权重为 1；权重为 0 的路不参与融合）
+func NewWeightedMergeRanker(weights map[string]float64) *WeightedMergeRanker {
+	return &WeightedMergeRanker{weights: weights}
+}
+
+// Fuse 实现 FusionRanker
+func (r *WeightedMergeRanker) Fuse(rankings map[string][]*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	fused := make(map[valueobject.UserID]fusedCandidate)
+	for name, ranking := range rankings {
+		weight := fusionWeight(r.weights, name)
+		if weight <= 0 {
+			continue
+		}
+		for _, rec := range ranking {
+			score := float64(rec.Score()) * weight
+			if existing, ok := fused[rec.TargetUserID()]; !ok || score > existing.score {
+				fused[rec.TargetUserID()] = fusedCandidate{rec: rec, score: score, best: score}
+			}
+		}
+	}
+	return sortFused(fused)
+}
+
+// ReciprocalRankFusion 倒数排名融合（RRF）：候选的融合分数 = Σ 权重 / (k + 名次)，名次从 1 开始
+//
+// 为什么需要？
+// 不同来源的分数尺度不一样（热度推荐的分数和二度关系的分数不能直接比），
+// RRF 只看名次，不看分数；被多路同时排在前面的候选分数更高。
+// k 越大，名次靠后的候选和靠前的差距越小。
+//
+// 同一个人出现在多路时，保留贡献最大（权重 / (k + 名次) 最大）的那一路的推荐。
+type ReciprocalRankFusion struct {
+	k       int
+	weights map[string]float64
+}
+
+// NewReciprocalRankFusion 构造函数（k <= 0 时使用 DefaultRRFK；没有列出的路权重为 1）
+func NewReciprocalRankFusion(k int, weights map[string]float64) *ReciprocalRankFusion {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+	return &ReciprocalRankFusion{k: k, weights: weights}
+}
+
+// Fuse 实现 FusionRanker
+func (r *ReciprocalRankFusion) Fuse(rankings map[string][]*aggregate.UserRecommendation) []*aggregate.UserRecommendation {
+	fused := make(map[valueobject.UserID]fusedCandidate)
+	for name, ranking := range rankings {
+		weight := fusionWeight(r.weights, name)
+		if weight <= 0 {
+			continue
+		}
+		seen := make(map[valueobject.UserID]bool, len(ranking))
+		rank := 0
+		for _, rec := range ranking {
+			// 同一路中重复出现的人按第一次出现的名次计算
+			if seen[rec.TargetUserID()] {
+				continue
+			}
+			seen[rec.TargetUserID()] = true
+			rank++
+			contribution := weight / float64(r.k+rank)
+			existing, ok := fused[rec.TargetUserID()]
+			if !ok {
+				fused[rec.TargetUserID()] = fusedCandidate{rec: rec, score: contribution, best: contribution}
+				continue
+			}
+			existing.score += contribution
+			if contribution > existing.best {
+				existing.rec = rec
+				existing.best = contribution
+			}
+			fused[rec.TargetUserID()] = existing
+		}
+	}
+	return sortFused(fused)
+}
+
+// fusedCandidate 融合中的一个候选
+type fusedCandidate struct {
+	rec   *aggregate.UserRecommendation
+	score float64 // 融合分数
+	best  float64 // 保留的那一路的贡献
+}
+
+// fusionWeight 辅助函数：一路的权重（没有列出时为 1）
+func fusionWeight(weights map[string]float64, name string) float64 {
+	if weight, ok := weights[name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// sortFused 辅助函数：按融合分数降序排列，相同时按用户ID升序（结果不取决于 map 的遍历顺序）
+func sortFused(fused map[valueobject.UserID]fusedCandidate) []*aggregate.UserRecommendation {
+	candidates := make([]fusedCandidate, 0, len(fused))
+	for _, c := range fused {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].rec.TargetUserID().Value() < candidates[j].rec.TargetUserID().Value()
+	})
+	ranked := make([]*aggregate.UserRecommendation, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, c.rec)
+	}
+	return ranked
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// fusionRanking 辅助函数：同一种推荐理由的一路排序
+func fusionRanking(t *testing.T, reason valueobject.RecommendationReason, ids ...int64) []*aggregate.UserRecommendation {
+	t.Helper()
+	ranking := make([]*aggregate.UserRecommendation, 0, len(ids))
+	for _, id := range ids {
+		target, _ := valueobject.NewUserID(id)
+		rec, err := aggregate.NewUserRecommendation(target, reason, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ranking = append(ranking, rec)
+	}
+	return ranking
+}
+
+func fusedIDs(ranked []*aggregate.UserRecommendation) []int64 {
+	ids := make([]int64, 0, len(ranked))
+	for _, rec := range ranked {
+		ids = append(ids, rec.TargetUserID().Value())
+	}
+	return ids
+}
+
+func TestReciprocalRankFusion(t *testing.T) {
+	u2, _ := valueobject.NewUserID(2)
+	rankings := map[string][]*aggregate.UserRecommendation{
+		"following":  fusionRanking(t, valueobject.NewMissedPostsReason(1), 1, 2, 3),
+		"popularity": fusionRanking(t, valueobject.NewPopularInNetworkReason([]valueobject.UserID{u2}), 3, 4),
+	}
+
+	// 3：1/63 + 1/61；1：1/61；2 和 4 都是 1/62，按用户ID升序
+	ranked := NewReciprocalRankFusion(0, nil).Fuse(rankings)
+	want := []int64{3, 1, 2, 4}
+	if got := fusedIDs(ranked); !equalInt64s(got, want) {
+		t.Fatalf("Fuse() = %v, want %v", got, want)
+	}
+	// 3 在 popularity 中排第一，贡献更大，保留那一路的推荐
+	if got := ranked[0].Reason().Type(); got != valueobject.ReasonPopularInNetwork {
+		t.Errorf("ranked[0] reason = %v, want popular in network", got)
+	}
+
+	// 权重为 0 的路不参与融合
+	ranked = NewReciprocalRankFusion(DefaultRRFK, map[string]float64{"popularity": 0}).Fuse(rankings)
+	if got := fusedIDs(ranked); !equalInt64s(got, []int64{1, 2, 3}) {
+		t.Errorf("popularity weight 0: Fuse() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestWeightedMergeRanker(t *testing.T) {
+	u2, _ := valueobject.NewUserID(2)
+	u3, _ := valueobject.NewUserID(3)
+	strong := valueobject.NewFollowedByFollowingReason([]valueobject.UserID{u2, u3}) // 20
+	weak := valueobject.NewFollowedByFollowingReason([]valueobject.UserID{u2})       // 10
+	following := append(fusionRanking(t, strong, 10), fusionRanking(t, weak, 11)...)
+	popularity := fusionRanking(t, weak, 11, 12)
+
+	// 11：max(10 × 1, 10 × 3) = 30；12：30；10：20
+	ranked := NewWeightedMergeRanker(map[string]float64{"popularity": 3}).Fuse(map[string][]*aggregate.UserRecommendation{
+		"following":  following,
+		"popularity": popularity,
+	})
+	want := []int64{11, 12, 10}
+	if got := fusedIDs(ranked); !equalInt64s(got, want) {
+		t.Fatalf("Fuse() = %v, want %v", got, want)
+	}
+	// 11 保留加权分数更高的 popularity 那一路的推荐
+	if ranked[0] != popularity[0] {
+		t.Error("ranked[0] should be the recommendation from popularity")
+	}
+}
+
+func equalInt64s(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"service/domain/repository"
 
@@ -46,17 +48,204 @@ import (
 type RecommendationGenerator struct {
 	socialGraphRepo repository.SocialGraphRepository
 	contentRepo     repository.ContentRepository
+
+	// 可选依赖（通过 GeneratorOption 注入）
+	trustRepo     repository.TrustRepository             // 风控分级（为 nil 时不做风险过滤）
+	riskThreshold valueobject.RiskLevel                  // 超过该等级的账号会被排除
+	concurrency   int                                    // 仓储查询的最大并发度
+	callTimeout   time.Duration                          // 扇出查询中每次仓储查询的超时（0 表示只受请求的 ctx 限制）
+	followIndex   repository.RecentFollowIndexRepository // 反向关注索引（为 nil 时逐个查询）
+	sampling      *FollowingSamplingPolicy               // 关注列表采样策略（为 nil 时不采样）
+	scanThreshold int                                    // 关注数超过该值时分页遍历（0 表示不分页）
+	scanPageSize  int                                    // 分页遍历时每页的人数
+	engagement    repository.EngagementSignalRepository  // 实时互动信号（为 nil 时不加分）
+	interactions  repository.InteractionRepository       // 创作者收到的互动（为 nil 时不加分）
+	blockRepo     repository.BlockRepository             // 屏蔽关系（为 nil 时不检查）
+
+	// 租户 → 打分权重（没有配置的租户使用默认权重）
+	tenantWeights map[valueobject.TenantID]valueobject.ScoringWeights
+	// 打分策略（为 nil 时按租户的打分权重，见 scoringPolicy）
+	policy aggregate.ScoringPolicy
+
+	// 候选来源和过滤器（按顺序执行，见 CandidateRegistry.Pipeline）
+	// 默认只有内置的 following 来源和 trust 过滤器
+	sources               []CandidateSource
+	recommendationSources []RecommendationSource // 自带推荐理由的来源（默认没有）
+	coldStart             RecommendationSource   // 其他来源都没有推荐时使用（为 nil 时返回空列表）
+	filters               []CandidateFilter
+	filterNames           []string // 和 filters 一一对应（解释推荐时列出通过了哪些过滤器）
+}
+
+// GeneratorOption 可选配置：为推荐生成器注入可选依赖
+//
+// 为什么用 Option 而不是继续加构造函数参数？
+// 必需依赖（社交图谱、内容仓储）仍然放在参数里，
+// 可选能力（风控过滤等）通过 Option 按需开启，
+// 新增能力时不需要修改所有调用方。
+//
+// 使用示例：
+//
+//	generator := NewRecommendationGenerator(
+//	    socialGraphRepo,
+//	    contentRepo,
+//	    WithTrustFilter(trustRepo, valueobject.RiskMedium),
+//	)
+type GeneratorOption func(*RecommendationGenerator)
+
+// WithTrustFilter 开启风险账号过滤
+//
+// 风险等级超过 threshold 的账号：
+// - 不会被推荐（记录为 ExclusionHighRisk）
+// - 也不会作为推荐信号（它的关注行为不计入"你关注的人也关注了TA"）
+func WithTrustFilter(trustRepo repository.TrustRepository, threshold valueobject.RiskLevel) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.trustRepo = trustRepo
+		g.riskThreshold = threshold
+	}
+}
+
+// WithBlockRepository 排除和用户有屏蔽关系的人（用户屏蔽的人、屏蔽了用户的人）
+//
+// 和风控过滤不同，屏蔽关系查询失败时生成失败（fail-closed）：
+// 屏蔽是硬性的产品要求，宁可这次没有推荐，也不能把被屏蔽的人推荐出去。
+// 被排除的候选记录为 ExclusionBlocked（见 RecommendationList.ExcludeBlocked）。
+func WithBlockRepository(blockRepo repository.BlockRepository) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.blockRepo = blockRepo
+	}
+}
+
+// WithConcurrency 设置仓储查询的最大并发度
+//
+// n <= 1 表示串行执行（便于排查问题或保护脆弱的下游）
+func WithConcurrency(n int) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.concurrency = n
+	}
+}
+
+// WithCallTimeout 设置扇出查询中每次仓储查询的超时（逐个查询最近关注的人、候选的帖子数）
+//
+// 为什么需要？
+// 扇出查询的总耗时取决于最慢的那一个：一个关注的人的查询卡住，整个请求等到 ctx 超时。
+// 单次查询超时按查询失败处理（和其他错误一样容错）：跳过这个人的推荐信号、帖子数记为 0，
+// 其余查询的结果照常使用。
+//
+// timeout <= 0 表示不单独设置超时。
+func WithCallTimeout(timeout time.Duration) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.callTimeout = timeout
+	}
+}
+
+// WithRecentFollowIndex 使用反向关注索引获取推荐信号
+//
+// 开启后，"我关注的人最近关注了谁"由一次索引读取完成，
+// 不再对每个关注的人单独查询。
+// 索引无法回答（未建立、时间窗口不够）或查询失败时，自动回退到逐个查询。
+func WithRecentFollowIndex(index repository.RecentFollowIndexRepository) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.followIndex = index
+	}
+}
+
+// WithFollowingSampling 开启关注列表采样
+//
+// 关注了大量账号的用户，逐个查询前先按策略采样（见 FollowingSamplingPolicy），
+// 采样情况记录在推荐列表中（RecommendationList.FollowingSampling）。
+func WithFollowingSampling(policy FollowingSamplingPolicy) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.sampling = &policy
+	}
+}
+
+// WithTenantScoringWeights 按租户配置打分权重
+//
+// 生成推荐时从 ctx 取出租户（valueobject.TenantFromContext），
+// 没有配置的租户使用默认权重（valueobject.DefaultScoringWeights）。
+func WithTenantScoringWeights(weights map[valueobject.TenantID]valueobject.ScoringWeights) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.tenantWeights = weights
+	}
+}
+
+// WithCreatorEngagement 开启创作者互动加分
+//
+// 打分时查询候选最近 N 天（和最近帖子数相同的时间窗口）收到的点赞、评论，
+// 内容常有人互动的创作者加分（见 aggregate 的 creatorBoost），排在只是发帖多的人前面。
+// 查询失败时不加分（fail-open），推荐照常生成。
+func WithCreatorEngagement(repo repository.InteractionRepository) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.interactions = repo
+	}
+}
+
+// WithScoringPolicy 使用自定义的打分策略（所有租户）
+//
+// 设置后不再使用租户的打分权重（WithTenantScoringWeights）：
+// 调整权重用配置就够了，换一套打分公式才需要自定义策略。
+func WithScoringPolicy(policy aggregate.ScoringPolicy) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.policy = policy
+	}
+}
+
+// WithEngagementSignals 开启实时互动加分
+//
+// 打分时查询候选最近一小时的点赞、评论，正在产生互动的候选加分（见 aggregate 的 engagementBoost）。
+// 计数服务不可用时不加分（fail-open），推荐照常生成。
+func WithEngagementSignals(repo repository.EngagementSignalRepository) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.engagement = repo
+	}
+}
+
+// WithColdStart 开启冷启动推荐
+//
+// 所有来源都没有推荐时（用户还没有关注任何人，或者关注的人最近都没有新的关注），
+// 用 source 的推荐填充列表（如 ColdStartSource 的编辑精选、全站热门），
+// 执行同样的过滤器和屏蔽规则。有其他推荐时不使用：冷启动推荐和用户的社交网络无关，只是兜底。
+func WithColdStart(source RecommendationSource) GeneratorOption {
+	return func(g *RecommendationGenerator) {
+		g.coldStart = source
+	}
+}
+
+// defaultScanPageSize 分页遍历关注列表时默认的每页人数
+const defaultScanPageSize = 1000
+
+// WithFollowingScan 关注列表很大时分页遍历
+//
+// 关注数超过 threshold 时，用 ScanFollowings 每次加载 pageSize 个人，
+// 而不是用 GetFollowings 一次把整个关注列表加载到内存。
+func WithFollowingScan(threshold, pageSize int) GeneratorOption {
+	if pageSize <= 0 {
+		pageSize = defaultScanPageSize
+	}
+	return func(g *RecommendationGenerator) {
+		g.scanThreshold = threshold
+		g.scanPageSize = pageSize
+	}
 }
 
 // NewRecommendationGenerator 构造函数
 func NewRecommendationGenerator(
 	socialGraphRepo repository.SocialGraphRepository,
 	contentRepo repository.ContentRepository,
+	opts ...GeneratorOption,
 ) *RecommendationGenerator {
-	return &RecommendationGenerator{
+	g := &RecommendationGenerator{
 		socialGraphRepo: socialGraphRepo,
 		contentRepo:     contentRepo,
+		concurrency:     defaultConcurrency,
+		sources:         []CandidateSource{followingSource{}},
+		filters:         []CandidateFilter{trustFilter{}},
+		filterNames:     []string{FilterTrust},
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // GenerateFollowingBasedRecommendations 核心领域逻辑：生成基于关注的推荐
@@ -91,6 +280,17 @@ func NewRecommendationGenerator(
 // - 某个用户数据获取失败不影响整体
 // - 帖子数获取失败默认为0
 // - 无效推荐会被跳过
+// - 风控服务异常时不做风险过滤（fail-open，不影响推荐可用性）
+// - 实时计数服务异常时不做互动加分（同上）
+// - 请求被取消或超时：停止查询并返回 ctx.Err()
+//
+// 性能设计：
+//   - 步骤3（最近关注）和步骤6（帖子数）各是一次批量查询
+//     （GetRecentFollowingsBatch、CountRecentPostsBatch），而不是每个关注的人、每个候选各查一次。
+//     批量查询失败时回退到逐个查询，以有限并发执行（见 WithConcurrency）。
+//   - 步骤4 使用 candidateAccumulator 统计候选，避免大关注图下的大量内存分配。
+//   - 配置了反向关注索引时（见 WithRecentFollowIndex），步骤1~3 变成一次索引读取；
+//     社交图谱仓储支持二度扩展时（见 repository.TwoHopExpander），步骤1~3 变成一次查询。
 //
 // 参数：
 // - forUserID: 为哪个用户生成推荐
@@ -104,83 +304,769 @@ func (g *RecommendationGenerator) GenerateFollowingBasedRecommendations(
 	// 创建推荐列表聚合
 	list := aggregate.NewRecommendationList(forUserID)
 
-	// 步骤1：获取用户关注的人
-	followings, err := g.socialGraphRepo.GetFollowings(ctx, forUserID)
+	// 步骤1~5：收集候选并执行过滤器
+	targets, accumulator, err := g.selectCandidates(ctx, list, days)
 	if err != nil {
 		return nil, err
 	}
 
-	// 如果用户没有关注任何人，返回空列表
-	if len(followings) == 0 {
-		return list, nil
+	// 步骤6：获取候选用户最近的帖子数
+	postCounts, err := g.countRecentPosts(ctx, targets, days)
+	if err != nil {
+		return nil, err
 	}
 
-	// 步骤2：获取这些人最近关注的人（去重）
-	// key: 被关注的用户ID
-	// value: 哪些用户关注了这个人
-	recentFollowedUsers := make(map[valueobject.UserID][]valueobject.UserID)
+	// 步骤7：为每个推荐用户创建推荐对象（按租户的打分策略）
+	policy := g.scoringPolicy(ctx)
+	for i, targetUserID := range targets {
+		counter := accumulator.get(targetUserID.Value())
 
-	for _, following := range followings {
-		// 获取这个用户最近关注的人
-		recentFollows, err := g.socialGraphRepo.GetRecentFollowings(
-			ctx, following, days,
+		// 创建推荐理由（只保留少量推荐信号来源样本，总数用于打分和文案）
+		reason := valueobject.NewSampledFollowedByFollowingReason(
+			counter.referrerSample(),
+			counter.count,
+		)
+
+		// 创建推荐聚合
+		recommendation, err := aggregate.NewUserRecommendationWithPolicy(
+			targetUserID,
+			reason,
+			postCounts[i],
+			policy,
 		)
 		if err != nil {
-			// 容错处理：某个用户的数据获取失败不影响整体
+			// 跳过无效推荐（如没有推荐理由）
 			continue
 		}
 
-		// 记录谁关注了谁
-		for _, newFollow := range recentFollows {
-			recentFollowedUsers[newFollow] = append(
-				recentFollowedUsers[newFollow],
-				following,
-			)
+		// 添加到推荐列表
+		if err := list.AddRecommendation(recommendation); err != nil {
+			// 跳过重复或无效推荐（如推荐自己）
+			continue
+		}
+	}
+
+	// 步骤8：自带推荐理由的来源（如"你可能错过了 TA 的新帖子"）
+	if err := g.addFromRecommendationSources(ctx, list, days, policy, g.recommendationSources); err != nil {
+		return nil, err
+	}
+
+	// 步骤9：冷启动（所有来源都没有推荐时，见 WithColdStart）
+	if list.IsEmpty() && g.coldStart != nil {
+		if err := g.addFromRecommendationSources(ctx, list, days, policy, []RecommendationSource{g.coldStart}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 步骤10：实时互动加分（所有来源的推荐一起查询）
+	g.applyEngagementSignals(ctx, list)
+
+	// 步骤11：创作者互动加分（所有来源的推荐一起查询）
+	g.applyCreatorEngagement(ctx, list, days)
+
+	return list, nil
+}
+
+// applyCreatorEngagement 辅助方法：按最近 days 天收到的互动为推荐加分（一次批量查询）
+//
+// 容错和 applyEngagementSignals 相同：查询失败时不加分，所有推荐保持原来的分数。
+func (g *RecommendationGenerator) applyCreatorEngagement(ctx context.Context, list *aggregate.RecommendationList, days int) {
+	if g.interactions == nil || list.IsEmpty() {
+		return
+	}
+	recommendations := list.All()
+	targets := make([]valueobject.UserID, 0, len(recommendations))
+	for _, rec := range recommendations {
+		targets = append(targets, rec.TargetUserID())
+	}
+	engagement, err := g.interactions.CountReceivedEngagement(ctx, targets, days)
+	if err != nil {
+		return
+	}
+	for _, rec := range recommendations {
+		if e, ok := engagement[rec.TargetUserID()]; ok {
+			rec.UpdateCreatorEngagement(e)
+		}
+	}
+}
+
+// applyEngagementSignals 辅助方法：按最近一小时的互动为推荐加分（一次批量查询）
+//
+// 容错：计数服务失败、超时时不加分，所有推荐保持原来的分数（不影响推荐可用性）。
+func (g *RecommendationGenerator) applyEngagementSignals(ctx context.Context, list *aggregate.RecommendationList) {
+	if g.engagement == nil || list.IsEmpty() {
+		return
+	}
+	recommendations := list.All()
+	targets := make([]valueobject.UserID, 0, len(recommendations))
+	for _, rec := range recommendations {
+		targets = append(targets, rec.TargetUserID())
+	}
+	engagement, err := g.engagement.GetRecentEngagement(ctx, targets)
+	if err != nil {
+		return
+	}
+	for _, rec := range recommendations {
+		if e, ok := engagement[rec.TargetUserID()]; ok {
+			rec.UpdateEngagement(e)
+		}
+	}
+}
+
+// FilterNames 查询方法：生成推荐时执行的过滤器（按执行顺序）
+//
+// 内置的 trust 过滤器只在开启了风险过滤（WithTrustFilter）时列出。
+func (g *RecommendationGenerator) FilterNames() []string {
+	names := make([]string, 0, len(g.filterNames))
+	for _, name := range g.filterNames {
+		if name == FilterTrust && g.trustRepo == nil {
+			continue
 		}
+		names = append(names, name)
 	}
+	return names
+}
 
-	// 步骤3：为每个推荐用户创建推荐对象
-	for targetUserID, followedBy := range recentFollowedUsers {
-		// 获取该用户最近的帖子数
-		postCount, err := g.contentRepo.CountRecentPosts(ctx, targetUserID, days)
+// addFromRecommendationSources 辅助方法：按顺序执行自带推荐理由的来源，过滤后加入推荐列表
+//
+// 容错和 collectFromSources 相同：某个来源失败时跳过它；请求被取消时返回 ctx.Err()。
+// 已经在列表中的人（步骤7 或前面的来源推荐过）不丢弃，合并推荐理由（见 RecommendationList.MergeRecommendation）。
+func (g *RecommendationGenerator) addFromRecommendationSources(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+	policy aggregate.ScoringPolicy,
+	sources []RecommendationSource,
+) error {
+	forUserID := list.ForUserID()
+	for _, source := range sources {
+		recommendations, err := source.Recommend(ctx, forUserID, days, policy)
 		if err != nil {
-			postCount = 0 // 容错：获取失败默认为0
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		// 业务规则：和其他来源的候选一样执行过滤器
+		targets := make([]valueobject.UserID, 0, len(recommendations))
+		for _, rec := range recommendations {
+			targets = append(targets, rec.TargetUserID())
+		}
+		excluded := make(map[valueobject.UserID]aggregate.ExclusionReason)
+		for _, filter := range g.filters {
+			for userID, reason := range g.runFilter(ctx, filter, forUserID, targets) {
+				excluded[userID] = reason
+			}
+		}
+		for _, rec := range recommendations {
+			if reason, ok := excluded[rec.TargetUserID()]; ok {
+				list.RecordExclusion(reason)
+				continue
+			}
+			// 其他来源已经推荐了这个人：合并推荐理由（权重相加，展示最强的一条）
+			_ = list.MergeRecommendation(rec)
 		}
+	}
+	return nil
+}
 
-		// 创建推荐理由
-		reason := valueobject.NewFollowedByFollowingReason(followedBy)
+// selectCandidates 辅助方法：收集候选并执行过滤器（算法步骤1~5，用户推荐和帖子推荐共用）
+//
+// 返回通过过滤的候选和候选的推荐信号统计；被过滤的候选按原因记录在 list 中。
+func (g *RecommendationGenerator) selectCandidates(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+) ([]valueobject.UserID, *candidateAccumulator, error) {
+	forUserID := list.ForUserID()
 
-		// 创建推荐聚合
-		recommendation, err := aggregate.NewUserRecommendation(
-			targetUserID,
-			reason,
-			postCount,
-		)
+	// 业务规则：有屏蔽关系的人不推荐（查询失败时不生成，见 WithBlockRepository）
+	blocked, err := g.BlockedUsers(ctx, forUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	list.ExcludeBlocked(blocked)
+
+	// 步骤1~4：收集候选，统计每个候选被多少个"我关注的人"关注
+	// （启用了其他候选来源时，它们的推荐信号也累加进来）
+	accumulator, err := g.collectFromSources(ctx, list, days)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 步骤5：应用业务规则，确定最终候选
+	counters := accumulator.all()
+	candidateIDs := make([]valueobject.UserID, 0, len(counters))
+	for i := range counters {
+		candidateID, err := valueobject.NewUserID(counters[i].candidate)
+		if err != nil || candidateID.Equals(forUserID) {
+			continue // 业务规则：不推荐自己
+		}
+		if list.IsBlocked(candidateID) {
+			list.RecordExclusion(aggregate.ExclusionBlocked)
+			continue
+		}
+		candidateIDs = append(candidateIDs, candidateID)
+	}
+
+	// 业务规则：按顺序执行过滤器（如高风险账号不推荐）
+	targets := candidateIDs
+	for _, filter := range g.filters {
+		excluded := g.runFilter(ctx, filter, forUserID, targets)
+		if len(excluded) == 0 {
+			continue
+		}
+		kept := make([]valueobject.UserID, 0, len(targets))
+		for _, candidateID := range targets {
+			if reason, ok := excluded[candidateID]; ok {
+				list.RecordExclusion(reason)
+				continue
+			}
+			kept = append(kept, candidateID)
+		}
+		targets = kept
+	}
+	return targets, accumulator, nil
+}
+
+// BlockedUsers 查询方法：和用户有屏蔽关系的人（用户屏蔽的人 + 屏蔽了用户的人）
+//
+// 没有配置屏蔽关系仓储时返回 nil。应用层直接使用快照时也用它过滤（快照可能早于屏蔽）。
+func (g *RecommendationGenerator) BlockedUsers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	if g.blockRepo == nil {
+		return nil, nil
+	}
+	blocked, err := g.blockRepo.GetBlockedUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	blockers, err := g.blockRepo.GetBlockers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append(blocked, blockers...), nil
+}
+
+// scoringWeights 辅助方法：ctx 中租户的打分权重
+func (g *RecommendationGenerator) scoringWeights(ctx context.Context) valueobject.ScoringWeights {
+	if weights, ok := g.tenantWeights[valueobject.TenantFromContext(ctx)]; ok {
+		return weights
+	}
+	return valueobject.DefaultScoringWeights()
+}
+
+// scoringPolicy 辅助方法：ctx 中租户的打分策略（设置了自定义策略时使用它，否则按租户的打分权重）
+func (g *RecommendationGenerator) scoringPolicy(ctx context.Context) aggregate.ScoringPolicy {
+	if g.policy != nil {
+		return g.policy
+	}
+	return aggregate.NewWeightedScoringPolicy(g.scoringWeights(ctx))
+}
+
+// collectFromSources 辅助方法：依次执行启用的候选来源，合并推荐信号
+//
+// 容错：某个来源失败时跳过它，不影响其他来源；所有来源都失败时返回最后一个错误。
+// 请求被取消时直接返回 ctx.Err()。
+func (g *RecommendationGenerator) collectFromSources(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+) (*candidateAccumulator, error) {
+	var accumulator *candidateAccumulator
+	var lastErr error
+	for _, source := range g.sources {
+		var collected *candidateAccumulator
+		var err error
+		if _, ok := source.(followingSource); ok {
+			collected, err = g.collectCandidates(ctx, list, days)
+		} else {
+			collected = newCandidateAccumulator(0)
+			err = source.Collect(ctx, list.ForUserID(), days, collected)
+		}
 		if err != nil {
-			// 跳过无效推荐（如没有推荐理由）
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
 			continue
 		}
+		if accumulator == nil {
+			accumulator = collected
+		} else {
+			accumulator.merge(collected)
+		}
+	}
+	if accumulator == nil {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		accumulator = newCandidateAccumulator(0)
+	}
+	return accumulator, nil
+}
 
-		// 添加到推荐列表
-		if err := list.AddRecommendation(recommendation); err != nil {
-			// 跳过重复或无效推荐（如推荐自己）
+// runFilter 辅助方法：执行一个过滤器（内置的 trust 过滤器使用生成器的风控配置）
+func (g *RecommendationGenerator) runFilter(
+	ctx context.Context,
+	filter CandidateFilter,
+	forUserID valueobject.UserID,
+	candidates []valueobject.UserID,
+) map[valueobject.UserID]aggregate.ExclusionReason {
+	if _, ok := filter.(trustFilter); !ok {
+		return filter.Exclude(ctx, forUserID, candidates)
+	}
+	risky := g.findRiskyUsers(ctx, candidates)
+	excluded := make(map[valueobject.UserID]aggregate.ExclusionReason, len(risky))
+	for userID := range risky {
+		excluded[userID] = aggregate.ExclusionHighRisk
+	}
+	return excluded
+}
+
+// collectCandidates 辅助方法：收集推荐候选（算法步骤1~4）
+//
+// 按顺序尝试：
+// 1. 有反向关注索引时读索引（一次读取）
+// 2. 社交图谱仓储支持二度扩展（repository.TwoHopExpander）时一次查询完成
+// 3. 逐个查询（fan-out）
+//
+// 前一种方式无法回答或出错时回退到下一种。
+// 请求被取消时不再回退，直接返回 ctx.Err()。
+func (g *RecommendationGenerator) collectCandidates(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+) (*candidateAccumulator, error) {
+	if g.followIndex != nil {
+		accumulator, err := g.collectFromIndex(ctx, list.ForUserID(), days)
+		if err == nil {
+			return accumulator, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// 容错：索引不可用时回退到逐个查询
+	}
+	if expander, ok := g.socialGraphRepo.(repository.TwoHopExpander); ok {
+		accumulator, err := g.collectByExpansion(ctx, expander, list, days)
+		if err == nil {
+			return accumulator, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// 容错：不支持、不适用或查询失败时回退到逐个查询
+	}
+	return g.collectByFanOut(ctx, list, days)
+}
+
+// errExpansionNotApplicable 关注列表需要采样或分页遍历，不使用二度扩展查询
+var errExpansionNotApplicable = errors.New("two-hop expansion not applicable")
+
+// collectByExpansion 辅助方法：一次二度扩展查询收集候选
+//
+// 只用于不需要采样、分页遍历的关注列表：
+// 需要采样的用户只能用样本作为信号来源，关注列表太大时连接的行数太多，这两种情况回退到逐个查询。
+func (g *RecommendationGenerator) collectByExpansion(
+	ctx context.Context,
+	expander repository.TwoHopExpander,
+	list *aggregate.RecommendationList,
+	days int,
+) (*candidateAccumulator, error) {
+	var total int
+	if g.sampling != nil || g.scanThreshold > 0 {
+		var err error
+		if total, err = g.socialGraphRepo.CountFollowings(ctx, list.ForUserID()); err != nil {
+			return nil, err
+		}
+		if (g.sampling != nil && g.sampling.Applies(total)) || (g.scanThreshold > 0 && total > g.scanThreshold) {
+			return nil, errExpansionNotApplicable
+		}
+	}
+
+	candidates, err := expander.ExpandTwoHop(ctx, list.ForUserID(), days)
+	if err != nil {
+		return nil, err
+	}
+	if g.sampling != nil {
+		list.RecordFollowingSampling(total, total)
+	}
+
+	// 业务规则：高风险账号的关注行为不作为推荐信号（与逐个查询时一致）
+	var referrers []valueobject.UserID
+	seen := make(map[valueobject.UserID]bool)
+	for _, candidate := range candidates {
+		for _, referrer := range candidate.Referrers {
+			if !seen[referrer] {
+				seen[referrer] = true
+				referrers = append(referrers, referrer)
+			}
+		}
+	}
+	riskyReferrers := g.findRiskyUsers(ctx, referrers)
+
+	accumulator := newCandidateAccumulator(len(candidates))
+	for _, candidate := range candidates {
+		for _, referrer := range candidate.Referrers {
+			if riskyReferrers[referrer] {
+				continue
+			}
+			accumulator.add(candidate.Candidate.Value(), referrer.Value())
+		}
+	}
+	return accumulator, nil
+}
+
+// collectFromIndex 辅助方法：从反向关注索引收集候选
+func (g *RecommendationGenerator) collectFromIndex(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	days int,
+) (*candidateAccumulator, error) {
+	recentFollows, err := g.followIndex.GetRecentFollowsOfFollowings(ctx, forUserID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	// 业务规则：高风险账号的关注行为不作为推荐信号（与逐个查询时一致）
+	referrers := make([]valueobject.UserID, 0, len(recentFollows))
+	seen := make(map[valueobject.UserID]bool, len(recentFollows))
+	for _, follow := range recentFollows {
+		if !seen[follow.Referrer] {
+			seen[follow.Referrer] = true
+			referrers = append(referrers, follow.Referrer)
+		}
+	}
+	riskyReferrers := g.findRiskyUsers(ctx, referrers)
+
+	accumulator := newCandidateAccumulator(len(recentFollows))
+	for _, follow := range recentFollows {
+		if riskyReferrers[follow.Referrer] {
 			continue
 		}
+		accumulator.add(follow.Candidate.Value(), follow.Referrer.Value())
 	}
+	return accumulator, nil
+}
 
-	return list, nil
+// collectByFanOut 辅助方法：逐个查询关注的人的最近关注，收集候选
+//
+// 关注列表超过 WithFollowingScan 设置的阈值时，改为分页遍历（collectByScan），
+// 不把整个关注列表一次加载到内存。
+func (g *RecommendationGenerator) collectByFanOut(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+) (*candidateAccumulator, error) {
+	if g.scanThreshold > 0 {
+		total, err := g.socialGraphRepo.CountFollowings(ctx, list.ForUserID())
+		if err == nil && total > g.scanThreshold {
+			return g.collectByScan(ctx, list, days, total)
+		}
+		// 容错：计数失败时按原来的方式一次加载
+	}
+
+	// 步骤1：获取用户关注的人
+	followings, err := g.socialGraphRepo.GetFollowings(ctx, list.ForUserID())
+	if err != nil {
+		return nil, err
+	}
+
+	// 关注了大量账号的用户：逐个查询前先采样，并记录采样情况
+	if g.sampling != nil {
+		total := len(followings)
+		followings = g.sampling.Sample(list.ForUserID(), followings, time.Now())
+		list.RecordFollowingSampling(total, len(followings))
+	}
+
+	// 步骤2~3：排除高风险来源，查询最近关注
+	followings, recentFollowsByFollowing, err := g.fetchRecentFollows(ctx, followings, days)
+	if err != nil {
+		return nil, err
+	}
+
+	// 步骤4：统计每个候选被多少个"我关注的人"关注（去重）
+	// 候选数不会超过结果总条数，按总条数预分配
+	sizeHint := 0
+	for _, recentFollows := range recentFollowsByFollowing {
+		sizeHint += len(recentFollows)
+	}
+	accumulator := newCandidateAccumulator(sizeHint)
+	accumulateRecentFollows(accumulator, followings, recentFollowsByFollowing)
+	return accumulator, nil
 }
 
-// GeneratePopularityBasedRecommendations 扩展示例：基于热度的推荐
+// collectByScan 辅助方法：分页遍历关注列表，收集候选
 //
-// 这展示了如何扩展新的推荐策略：
-// 1. 在同一个领域服务中添加新方法
-// 2. 或者创建新的领域服务类
+// 内存占用：
+// - 开启采样时：遍历过程中只保留样本（流式采样），遍历结束后按样本查询
+// - 未开启采样时：每页查询完立即累加到 candidateAccumulator（同一时刻只有一页在内存中）
+func (g *RecommendationGenerator) collectByScan(
+	ctx context.Context,
+	list *aggregate.RecommendationList,
+	days int,
+	total int,
+) (*candidateAccumulator, error) {
+	forUserID := list.ForUserID()
+
+	// 开启采样：流式采样，遍历结束后只对样本查询
+	if g.sampling != nil && g.sampling.Applies(total) {
+		sampler := g.sampling.newSampler(forUserID, time.Now())
+		err := g.socialGraphRepo.ScanFollowings(ctx, forUserID, g.scanPageSize, func(page []valueobject.UserID) error {
+			for _, following := range page {
+				sampler.offer(following)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		followings := sampler.result()
+		list.RecordFollowingSampling(total, len(followings))
+
+		followings, recentFollowsByFollowing, err := g.fetchRecentFollows(ctx, followings, days)
+		if err != nil {
+			return nil, err
+		}
+		accumulator := newCandidateAccumulator(len(followings))
+		accumulateRecentFollows(accumulator, followings, recentFollowsByFollowing)
+		return accumulator, nil
+	}
+
+	// 未采样：逐页查询、逐页累加
+	if g.sampling != nil {
+		list.RecordFollowingSampling(total, total)
+	}
+	accumulator := newCandidateAccumulator(0)
+	err := g.socialGraphRepo.ScanFollowings(ctx, forUserID, g.scanPageSize, func(page []valueobject.UserID) error {
+		followings, recentFollowsByFollowing, err := g.fetchRecentFollows(ctx, page, days)
+		if err != nil {
+			return err
+		}
+		accumulateRecentFollows(accumulator, followings, recentFollowsByFollowing)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accumulator, nil
+}
+
+// fetchRecentFollows 辅助方法：排除高风险来源后，查询每个人最近关注的人（算法步骤2~3）
+//
+// 先用 GetRecentFollowingsBatch 一次查询所有人；批量查询失败时回退到并发逐个查询
+// （每次查询受 WithCallTimeout 限制，单个人查询失败不影响整体）。
+//
+// 返回：
+// - 排除高风险账号后的关注列表
+// - 与之一一对应的最近关注结果
+func (g *RecommendationGenerator) fetchRecentFollows(
+	ctx context.Context,
+	followings []valueobject.UserID,
+	days int,
+) ([]valueobject.UserID, [][]valueobject.UserID, error) {
+	// 步骤2：排除高风险的推荐信号来源（垃圾/机器人账号）
+	// 业务规则：高风险账号的关注行为不作为推荐信号，
+	// 避免机器人团伙互相关注把彼此刷进推荐列表。
+	// 提前排除还能省掉对这些账号的查询。
+	followings = excludeUsers(followings, g.findRiskyUsers(ctx, followings))
+
+	// 步骤3：获取这些人最近关注的人
+	// 一次批量查询（一条 IN 查询），而不是每个人查一次（N+1）
+	recentFollowsByFollowing := make([][]valueobject.UserID, len(followings))
+	if len(followings) == 0 {
+		return followings, recentFollowsByFollowing, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	batch, err := g.socialGraphRepo.GetRecentFollowingsBatch(ctx, followings, days)
+	if err == nil {
+		for i, following := range followings {
+			recentFollowsByFollowing[i] = batch[following]
+		}
+		return followings, recentFollowsByFollowing, nil
+	}
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	// 容错：批量查询失败时回退到逐个查询
+	// 并发查询：每个任务只写自己的结果槽位，查询结束后再串行合并
+	err = runBounded(ctx, len(followings), g.concurrency, func(ctx context.Context, i int) {
+		ctx, cancel := g.callContext(ctx)
+		defer cancel()
+		// 获取这个用户最近关注的人
+		recentFollows, err := g.socialGraphRepo.GetRecentFollowings(
+			ctx, followings[i], days,
+		)
+		if err != nil {
+			// 容错处理：某个用户的数据获取失败（包括单次查询超时）不影响整体
+			return
+		}
+		recentFollowsByFollowing[i] = recentFollows
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return followings, recentFollowsByFollowing, nil
+}
+
+// countRecentPosts 辅助方法：获取候选用户最近的帖子数（算法步骤6）
+//
+// 先用 CountRecentPostsBatch 一次查询所有候选；批量查询失败时回退到并发逐个查询
+// （每次查询受 WithCallTimeout 限制，获取失败的候选帖子数记为 0）。
+func (g *RecommendationGenerator) countRecentPosts(
+	ctx context.Context,
+	targets []valueobject.UserID,
+	days int,
+) ([]int, error) {
+	postCounts := make([]int, len(targets))
+	if len(targets) == 0 {
+		return postCounts, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	batch, err := g.contentRepo.CountRecentPostsBatch(ctx, targets, days)
+	if err == nil {
+		for i, target := range targets {
+			postCounts[i] = batch[target]
+		}
+		return postCounts, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// 容错：批量查询失败时回退到逐个查询
+	err = runBounded(ctx, len(targets), g.concurrency, func(ctx context.Context, i int) {
+		ctx, cancel := g.callContext(ctx)
+		defer cancel()
+		postCount, err := g.contentRepo.CountRecentPosts(ctx, targets[i], days)
+		if err != nil {
+			postCount = 0 // 容错：获取失败默认为0
+		}
+		postCounts[i] = postCount
+	})
+	if err != nil {
+		return nil, err
+	}
+	return postCounts, nil
+}
+
+// callContext 辅助方法：扇出查询中一次仓储查询的 ctx（设置了 WithCallTimeout 时带超时）
+func (g *RecommendationGenerator) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if g.callTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, g.callTimeout)
+}
+
+// accumulateRecentFollows 辅助方法：把最近关注结果累加到候选统计中（算法步骤4）
+func accumulateRecentFollows(
+	accumulator *candidateAccumulator,
+	followings []valueobject.UserID,
+	recentFollowsByFollowing [][]valueobject.UserID,
+) {
+	for i, following := range followings {
+		for _, newFollow := range recentFollowsByFollowing[i] {
+			accumulator.add(newFollow.Value(), following.Value())
+		}
+	}
+}
+
+// findRiskyUsers 辅助方法：找出风险等级超过阈值的账号
+//
+// 容错设计：
+// - 没有配置风控仓储：不过滤
+// - 风控服务异常：不过滤（fail-open），推荐功能不受影响
+func (g *RecommendationGenerator) findRiskyUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) map[valueobject.UserID]bool {
+	if g.trustRepo == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	levels, err := g.trustRepo.GetRiskLevels(ctx, userIDs)
+	if err != nil {
+		return nil
+	}
+
+	risky := make(map[valueobject.UserID]bool)
+	for userID, level := range levels {
+		if level.Exceeds(g.riskThreshold) {
+			risky[userID] = true
+		}
+	}
+	return risky
+}
+
+// excludeUsers 辅助方法：从用户列表中移除指定用户
+func excludeUsers(users []valueobject.UserID, excluded map[valueobject.UserID]bool) []valueobject.UserID {
+	if len(excluded) == 0 {
+		return users
+	}
+
+	result := make([]valueobject.UserID, 0, len(users))
+	for _, user := range users {
+		if !excluded[user] {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+// 热度推荐的参数
+const (
+	PopularityWindowDays = 30 // 统计最近多少天的关注（比关注推荐的默认 7 天长：热度是更慢的信号）
+	MinPopularFollowers  = 3  // 至少被几个"你关注的人"关注，才算在你的社交网络中受欢迎
+)
+
+// GeneratePopularityBasedRecommendations 基于热度的推荐：在用户社交网络中被多人关注的用户
+//
+// 和 GenerateFollowingBasedRecommendations 的区别：
+// - 统计最近 PopularityWindowDays 天的关注（而不是请求的时间窗口）
+// - 只保留至少被 MinPopularFollowers 个"你关注的人"关注的候选
+// - 推荐理由是"在你的社交网络中很受欢迎"：理由只给基础分，排序主要看帖子和互动
+//
+// 候选的收集、过滤器、屏蔽关系和关注推荐相同（selectCandidates），
+// 两个策略的结果可以在应用层按权重混合（见 RecommendationService.GetHybridRecommendations）。
 func (g *RecommendationGenerator) GeneratePopularityBasedRecommendations(
 	ctx context.Context,
 	forUserID valueobject.UserID,
 ) (*aggregate.RecommendationList, error) {
-	// TODO: 实现基于热度的推荐逻辑
-	// 例如：推荐在用户社交网络中被多人关注的用户
-	return aggregate.NewRecommendationList(forUserID), nil
+	list := aggregate.NewRecommendationList(forUserID)
+
+	targets, accumulator, err := g.selectCandidates(ctx, list, PopularityWindowDays)
+	if err != nil {
+		return nil, err
+	}
+	popular := make([]valueobject.UserID, 0, len(targets))
+	for _, targetUserID := range targets {
+		if accumulator.get(targetUserID.Value()).count >= MinPopularFollowers {
+			popular = append(popular, targetUserID)
+		}
+	}
+
+	postCounts, err := g.countRecentPosts(ctx, popular, PopularityWindowDays)
+	if err != nil {
+		return nil, err
+	}
+	policy := g.scoringPolicy(ctx)
+	for i, targetUserID := range popular {
+		reason := valueobject.NewPopularInNetworkReason(accumulator.get(targetUserID.Value()).referrerSample())
+		recommendation, err := aggregate.NewUserRecommendationWithPolicy(targetUserID, reason, postCounts[i], policy)
+		if err != nil {
+			continue
+		}
+		_ = list.AddRecommendation(recommendation)
+	}
+
+	g.applyEngagementSignals(ctx, list)
+	g.applyCreatorEngagement(ctx, list, PopularityWindowDays)
+	return list, nil
 }
@@ -0,0 +1,1246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// fakeSocialGraphRepo 测试用社交图谱仓储：每次查询模拟固定的网络延迟
+//
+// 关注关系：用户关注了 followingCount 个人，
+// 第 i 个人最近关注了 [i+1000, i+1001, i+1002]（相邻的人有重叠的关注）
+type fakeSocialGraphRepo struct {
+	followingCount int
+	latency        time.Duration
+	stuckUser      int64 // 这个人的最近关注查询一直等到 ctx 结束（模拟卡住的查询）
+	batchErr       error // 批量查询返回的错误（模拟批量查询失败，生成器回退到逐个查询）
+}
+
+func (r *fakeSocialGraphRepo) GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	result := make([]valueobject.UserID, 0, r.followingCount)
+	for i := 1; i <= r.followingCount; i++ {
+		id, _ := valueobject.NewUserID(int64(i + 1))
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+func (r *fakeSocialGraphRepo) GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error) {
+	if userID.Value() == r.stuckUser {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	time.Sleep(r.latency)
+	return recentFollowingsOf(userID), nil
+}
+
+func (r *fakeSocialGraphRepo) GetRecentFollowingsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error) {
+	if r.batchErr != nil {
+		return nil, r.batchErr
+	}
+	time.Sleep(r.latency) // 一次查询，只有一次延迟
+	result := make(map[valueobject.UserID][]valueobject.UserID, len(userIDs))
+	for _, userID := range userIDs {
+		if userID.Value() == r.stuckUser {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		result[userID] = recentFollowingsOf(userID)
+	}
+	return result, nil
+}
+
+// recentFollowingsOf 第 i 个人最近关注的人：[i+1000, i+1001, i+1002]
+func recentFollowingsOf(userID valueobject.UserID) []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, 3)
+	for j := int64(0); j < 3; j++ {
+		id, _ := valueobject.NewUserID(userID.Value() + 1000 + j)
+		result = append(result, id)
+	}
+	return result
+}
+
+func (r *fakeSocialGraphRepo) CountFollowings(ctx context.Context, userID valueobject.UserID) (int, error) {
+	return r.followingCount, nil
+}
+
+func (r *fakeSocialGraphRepo) ScanFollowings(ctx context.Context, userID valueobject.UserID, pageSize int, fn func(page []valueobject.UserID) error) error {
+	followings, _ := r.GetFollowings(ctx, userID)
+	for start := 0; start < len(followings); start += pageSize {
+		if err := fn(followings[start:min(start+pageSize, len(followings))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeSocialGraphRepo) IsFollowing(ctx context.Context, followerID, followingID valueobject.UserID) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeSocialGraphRepo) GetMutualFollows(ctx context.Context, userID valueobject.UserID, limit int) ([]valueobject.UserID, error) {
+	return nil, nil
+}
+
+func (r *fakeSocialGraphRepo) FindFollow(ctx context.Context, followerID, followingID valueobject.UserID) (*entity.Follow, error) {
+	return nil, nil
+}
+
+func (r *fakeSocialGraphRepo) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	return nil
+}
+
+func (r *fakeSocialGraphRepo) RemoveFollow(ctx context.Context, followerID, followingID valueobject.UserID, removedAt time.Time) error {
+	return nil
+}
+
+// fakeContentRepo 测试用内容仓储：每次查询模拟固定的网络延迟
+type fakeContentRepo struct {
+	latency  time.Duration
+	batchErr error // 批量查询返回的错误（模拟批量查询失败，生成器回退到逐个查询）
+}
+
+func (r *fakeContentRepo) CountRecentPosts(ctx context.Context, userID valueobject.UserID, days int) (int, error) {
+	time.Sleep(r.latency)
+	return 3, nil
+}
+
+func (r *fakeContentRepo) CountRecentPostsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID]int, error) {
+	if r.batchErr != nil {
+		return nil, r.batchErr
+	}
+	time.Sleep(r.latency) // 一次查询，只有一次延迟
+	result := make(map[valueobject.UserID]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = 3
+	}
+	return result, nil
+}
+
+func (r *fakeContentRepo) GetRecentPosts(ctx context.Context, userID valueobject.UserID, limit int) ([]*entity.Post, error) {
+	return nil, nil
+}
+
+func (r *fakeContentRepo) GetRecentPostsForUsers(ctx context.Context, userIDs []valueobject.UserID, limit int) (map[valueobject.UserID][]*entity.Post, error) {
+	return nil, nil
+}
+
+// BenchmarkGenerateFollowingBasedRecommendations 对比批量查询与串行、并发 fan-out 的延迟
+//
+// 场景：用户关注了几百人，每次仓储查询约 200µs（fan-out 通过让批量查询失败来触发）
+//
+//	go test ./domain/service -bench GenerateFollowingBased -benchtime 20x
+func BenchmarkGenerateFollowingBasedRecommendations(b *testing.B) {
+	forUserID, _ := valueobject.NewUserID(1)
+	errBatchDisabled := errors.New("batch disabled")
+
+	for _, followingCount := range []int{100, 500} {
+		for _, concurrency := range []int{0, 1, defaultConcurrency} {
+			name := fmt.Sprintf("followings=%d/batch", followingCount)
+			var batchErr error
+			if concurrency > 0 {
+				name = fmt.Sprintf("followings=%d/fan-out/concurrency=%d", followingCount, concurrency)
+				batchErr = errBatchDisabled
+			}
+			b.Run(name, func(b *testing.B) {
+				generator := NewRecommendationGenerator(
+					&fakeSocialGraphRepo{followingCount: followingCount, latency: 200 * time.Microsecond, batchErr: batchErr},
+					&fakeContentRepo{latency: 200 * time.Microsecond, batchErr: batchErr},
+					WithConcurrency(max(concurrency, 1)),
+				)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := generator.GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// buildRecentFollows 构造大关注图：followingCount 个关注的人，每人最近关注 perFollowing 个人，
+// 候选从 candidatePool 个用户中选取（候选之间有大量重叠）
+func buildRecentFollows(followingCount, perFollowing, candidatePool int) ([]valueobject.UserID, [][]valueobject.UserID) {
+	followings := make([]valueobject.UserID, 0, followingCount)
+	recentFollows := make([][]valueobject.UserID, 0, followingCount)
+	for i := 0; i < followingCount; i++ {
+		following, _ := valueobject.NewUserID(int64(i + 1))
+		followings = append(followings, following)
+
+		follows := make([]valueobject.UserID, 0, perFollowing)
+		for j := 0; j < perFollowing; j++ {
+			candidate, _ := valueobject.NewUserID(int64(100000 + (i*7+j*13)%candidatePool))
+			follows = append(follows, candidate)
+		}
+		recentFollows = append(recentFollows, follows)
+	}
+	return followings, recentFollows
+}
+
+// BenchmarkCandidateAccumulation 对比候选统计的内存分配
+//
+//	slice_map：旧实现 map[UserID][]UserID，每个候选一个不断扩容的 slice
+//	counter：新实现 candidateAccumulator，int64 key + 预分配 + 定长计数器
+//
+//	go test ./domain/service -run xxx -bench CandidateAccumulation -benchmem
+func BenchmarkCandidateAccumulation(b *testing.B) {
+	followings, recentFollows := buildRecentFollows(500, 40, 5000)
+
+	b.Run("slice_map", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			recentFollowedUsers := make(map[valueobject.UserID][]valueobject.UserID)
+			for i, following := range followings {
+				for _, newFollow := range recentFollows[i] {
+					recentFollowedUsers[newFollow] = append(recentFollowedUsers[newFollow], following)
+				}
+			}
+		}
+	})
+
+	b.Run("counter", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			sizeHint := 0
+			for _, follows := range recentFollows {
+				sizeHint += len(follows)
+			}
+			accumulator := newCandidateAccumulator(sizeHint)
+			for i, following := range followings {
+				for _, newFollow := range recentFollows[i] {
+					accumulator.add(newFollow.Value(), following.Value())
+				}
+			}
+		}
+	})
+}
+
+func TestCandidateAccumulator(t *testing.T) {
+	accumulator := newCandidateAccumulator(0)
+	for referrer := int64(1); referrer <= maxReferrerSample+3; referrer++ {
+		accumulator.add(100, referrer)
+	}
+	accumulator.add(200, 1)
+
+	all := accumulator.all()
+	if len(all) != 2 || all[0].candidate != 100 || all[1].candidate != 200 {
+		t.Fatalf("all() = %+v, want candidates [100 200] in insertion order", all)
+	}
+
+	counter := accumulator.get(100)
+	if counter.count != maxReferrerSample+3 {
+		t.Errorf("count = %d, want %d", counter.count, maxReferrerSample+3)
+	}
+	if sample := counter.referrerSample(); len(sample) != maxReferrerSample {
+		t.Errorf("len(referrerSample()) = %d, want %d", len(sample), maxReferrerSample)
+	}
+	if accumulator.get(300) != nil {
+		t.Errorf("get(300) = %+v, want nil", accumulator.get(300))
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_ConcurrencyKeepsResult(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	social := &fakeSocialGraphRepo{followingCount: 50}
+	content := &fakeContentRepo{}
+
+	sequential, err := NewRecommendationGenerator(social, content, WithConcurrency(1)).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatalf("sequential generate failed: %v", err)
+	}
+	concurrent, err := NewRecommendationGenerator(social, content, WithConcurrency(8)).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatalf("concurrent generate failed: %v", err)
+	}
+
+	if sequential.Count() != concurrent.Count() {
+		t.Fatalf("Count() = %d, want %d", concurrent.Count(), sequential.Count())
+	}
+
+	want := make(map[valueobject.UserID]int)
+	for _, rec := range sequential.All() {
+		want[rec.TargetUserID()] = rec.Score()
+	}
+	for _, rec := range concurrent.All() {
+		if score, ok := want[rec.TargetUserID()]; !ok || score != rec.Score() {
+			t.Errorf("recommendation %v score = %d, want %d", rec.TargetUserID(), rec.Score(), score)
+		}
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_Cancelled(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewRecommendationGenerator(&fakeSocialGraphRepo{followingCount: 10}, &fakeContentRepo{}).
+		GenerateFollowingBasedRecommendations(ctx, forUserID, 7)
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_CallTimeoutSkipsStuckFollowing(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 关注了 2~11，批量查询失败后逐个查询，其中 11 的查询卡住
+	social := &fakeSocialGraphRepo{followingCount: 10, stuckUser: 11, batchErr: errors.New("batch query failed")}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithCallTimeout(20*time.Millisecond)).
+		GenerateFollowingBasedRecommendations(ctx, forUserID, 7)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("generate took %v, want the stuck call to time out after 20ms", elapsed)
+	}
+
+	// 11 最近关注的 1011~1013 中，1013 只有 11 关注了（其余的也被 9、10 关注）
+	got := make(map[int64]bool)
+	for _, rec := range list.All() {
+		got[rec.TargetUserID().Value()] = true
+	}
+	if got[1013] || !got[1002] || !got[1012] {
+		t.Errorf("recommendations = %v, want all but 1013 (only the stuck following recently followed it)", got)
+	}
+}
+
+// fakeRecentFollowIndex 测试用反向关注索引
+type fakeRecentFollowIndex struct {
+	follows []repository.RecentFollow
+	err     error
+}
+
+func (i *fakeRecentFollowIndex) GetRecentFollowsOfFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]repository.RecentFollow, error) {
+	return i.follows, i.err
+}
+
+func (i *fakeRecentFollowIndex) DeleteUserIndex(ctx context.Context, userID valueobject.UserID) error {
+	return nil
+}
+
+// countingSocialGraphRepo 统计 GetRecentFollowings、GetRecentFollowingsBatch 的调用次数
+type countingSocialGraphRepo struct {
+	fakeSocialGraphRepo
+	recentCalls atomic.Int32
+	batchCalls  atomic.Int32
+}
+
+func (r *countingSocialGraphRepo) GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error) {
+	r.recentCalls.Add(1)
+	return r.fakeSocialGraphRepo.GetRecentFollowings(ctx, userID, days)
+}
+
+func (r *countingSocialGraphRepo) GetRecentFollowingsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error) {
+	r.batchCalls.Add(1)
+	return r.fakeSocialGraphRepo.GetRecentFollowingsBatch(ctx, userIDs, days)
+}
+
+func TestGenerateFollowingBasedRecommendations_RecentFollowIndex(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	b, _ := valueobject.NewUserID(2)
+	c, _ := valueobject.NewUserID(3)
+	e, _ := valueobject.NewUserID(5)
+	f, _ := valueobject.NewUserID(6)
+
+	t.Run("reads index instead of fan-out", func(t *testing.T) {
+		social := &countingSocialGraphRepo{fakeSocialGraphRepo: fakeSocialGraphRepo{followingCount: 2}}
+		index := &fakeRecentFollowIndex{follows: []repository.RecentFollow{
+			{Referrer: b, Candidate: e},
+			{Referrer: c, Candidate: e},
+			{Referrer: c, Candidate: f},
+			{Referrer: b, Candidate: forUserID},
+		}}
+
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithRecentFollowIndex(index)).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		if got := social.recentCalls.Load() + social.batchCalls.Load(); got != 0 {
+			t.Errorf("recent followings queried %d times, want 0", got)
+		}
+		if list.Count() != 2 {
+			t.Fatalf("Count() = %d, want 2", list.Count())
+		}
+		for _, rec := range list.All() {
+			if rec.TargetUserID().Equals(e) && rec.Reason().RelatedCount() != 2 {
+				t.Errorf("RelatedCount() for %v = %d, want 2", e, rec.Reason().RelatedCount())
+			}
+		}
+	})
+
+	t.Run("falls back to fan-out on index miss", func(t *testing.T) {
+		social := &countingSocialGraphRepo{fakeSocialGraphRepo: fakeSocialGraphRepo{followingCount: 2}}
+		index := &fakeRecentFollowIndex{err: repository.ErrRecentFollowIndexMiss}
+
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithRecentFollowIndex(index)).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		// 回退时一次批量查询，而不是每个关注的人查一次
+		if got := social.batchCalls.Load(); got != 1 {
+			t.Errorf("GetRecentFollowingsBatch called %d times, want 1", got)
+		}
+		if got := social.recentCalls.Load(); got != 0 {
+			t.Errorf("GetRecentFollowings called %d times, want 0", got)
+		}
+		if list.Count() == 0 {
+			t.Errorf("Count() = 0, want fan-out results")
+		}
+	})
+}
+
+// expandingSocialGraphRepo 支持二度扩展的社交图谱仓储
+type expandingSocialGraphRepo struct {
+	countingSocialGraphRepo
+	candidates []repository.TwoHopCandidate
+	err        error
+}
+
+func (r *expandingSocialGraphRepo) ExpandTwoHop(ctx context.Context, userID valueobject.UserID, days int) ([]repository.TwoHopCandidate, error) {
+	return r.candidates, r.err
+}
+
+func TestGenerateFollowingBasedRecommendations_TwoHopExpansion(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	b, _ := valueobject.NewUserID(2)
+	c, _ := valueobject.NewUserID(3)
+	e, _ := valueobject.NewUserID(5)
+	f, _ := valueobject.NewUserID(6)
+	candidates := []repository.TwoHopCandidate{
+		{Candidate: e, Referrers: []valueobject.UserID{b, c}},
+		{Candidate: f, Referrers: []valueobject.UserID{c}},
+	}
+
+	t.Run("expands in one query", func(t *testing.T) {
+		social := &expandingSocialGraphRepo{
+			countingSocialGraphRepo: countingSocialGraphRepo{fakeSocialGraphRepo: fakeSocialGraphRepo{followingCount: 2}},
+			candidates:              candidates,
+		}
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		if got := social.recentCalls.Load() + social.batchCalls.Load(); got != 0 {
+			t.Errorf("recent followings queried %d times, want 0", got)
+		}
+		if list.Count() != 2 {
+			t.Fatalf("Count() = %d, want 2", list.Count())
+		}
+		for _, rec := range list.All() {
+			if rec.TargetUserID().Equals(e) && rec.Reason().RelatedCount() != 2 {
+				t.Errorf("RelatedCount() for %v = %d, want 2", e, rec.Reason().RelatedCount())
+			}
+		}
+	})
+
+	t.Run("falls back when unsupported", func(t *testing.T) {
+		social := &expandingSocialGraphRepo{
+			countingSocialGraphRepo: countingSocialGraphRepo{fakeSocialGraphRepo: fakeSocialGraphRepo{followingCount: 2}},
+			err:                     repository.ErrTwoHopExpansionUnsupported,
+		}
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		if got := social.batchCalls.Load(); got != 1 {
+			t.Errorf("GetRecentFollowingsBatch called %d times, want 1", got)
+		}
+		if list.Count() == 0 {
+			t.Errorf("Count() = 0, want fan-out results")
+		}
+	})
+
+	t.Run("falls back when the following list is sampled", func(t *testing.T) {
+		social := &expandingSocialGraphRepo{
+			countingSocialGraphRepo: countingSocialGraphRepo{fakeSocialGraphRepo: fakeSocialGraphRepo{followingCount: 30}},
+			candidates:              candidates,
+		}
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{},
+			WithFollowingSampling(FollowingSamplingPolicy{Threshold: 20, RecentK: 5, RandomN: 5})).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		if got := social.batchCalls.Load(); got != 1 {
+			t.Errorf("GetRecentFollowingsBatch called %d times, want 1 (sampled followings only)", got)
+		}
+		if sampling := list.FollowingSampling(); sampling.Total != 30 || sampling.Used != 10 {
+			t.Errorf("FollowingSampling() = %+v, want 30 total, 10 used", sampling)
+		}
+	})
+}
+
+func TestFollowingSamplingPolicy_Sample(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	followings := make([]valueobject.UserID, 0, 100)
+	for i := int64(1); i <= 100; i++ {
+		id, _ := valueobject.NewUserID(i + 1)
+		followings = append(followings, id)
+	}
+	policy := FollowingSamplingPolicy{Threshold: 50, RecentK: 10, RandomN: 20}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	sampled := policy.Sample(forUserID, followings, now)
+	if len(sampled) != 30 {
+		t.Fatalf("len(Sample()) = %d, want 30", len(sampled))
+	}
+	for i := 0; i < 10; i++ {
+		if !sampled[i].Equals(followings[i]) {
+			t.Errorf("sampled[%d] = %v, want most recent following %v", i, sampled[i], followings[i])
+		}
+	}
+	seen := make(map[valueobject.UserID]bool)
+	for _, id := range sampled {
+		if seen[id] {
+			t.Errorf("duplicate following %v in sample", id)
+		}
+		seen[id] = true
+	}
+
+	// 同一用户同一天采样结果稳定
+	again := policy.Sample(forUserID, followings, now.Add(time.Hour))
+	for i := range sampled {
+		if !sampled[i].Equals(again[i]) {
+			t.Fatalf("Sample() is not stable within a day")
+		}
+	}
+
+	// 未超过阈值：不采样
+	if got := policy.Sample(forUserID, followings[:50], now); len(got) != 50 {
+		t.Errorf("len(Sample()) below threshold = %d, want 50", len(got))
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_FollowingScan(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	social := &fakeSocialGraphRepo{followingCount: 50}
+	content := &fakeContentRepo{}
+
+	scores := func(opts ...GeneratorOption) map[valueobject.UserID]int {
+		list, err := NewRecommendationGenerator(social, content, opts...).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatalf("generate failed: %v", err)
+		}
+		result := make(map[valueobject.UserID]int)
+		for _, rec := range list.All() {
+			result[rec.TargetUserID()] = rec.Score()
+		}
+		return result
+	}
+
+	sampling := WithFollowingSampling(FollowingSamplingPolicy{Threshold: 20, RecentK: 5, RandomN: 10})
+	cases := []struct {
+		name         string
+		loaded, scan []GeneratorOption
+	}{
+		{"without sampling", nil, []GeneratorOption{WithFollowingScan(10, 7)}},
+		{"with sampling", []GeneratorOption{sampling}, []GeneratorOption{sampling, WithFollowingScan(10, 7)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := scores(tc.loaded...)
+			got := scores(tc.scan...)
+			if len(got) != len(want) {
+				t.Fatalf("scan produced %d recommendations, want %d", len(got), len(want))
+			}
+			for target, score := range want {
+				if got[target] != score {
+					t.Errorf("score of %v = %d, want %d", target, got[target], score)
+				}
+			}
+		})
+	}
+}
+
+// fakeGeoSource 测试用候选来源：固定推荐 candidates，推荐信号来源为 referrer
+type fakeGeoSource struct {
+	referrer   int64
+	candidates []int64
+}
+
+func (s *fakeGeoSource) Collect(ctx context.Context, forUserID valueobject.UserID, days int, sink CandidateSink) error {
+	referrer, _ := valueobject.NewUserID(s.referrer)
+	for _, id := range s.candidates {
+		candidate, _ := valueobject.NewUserID(id)
+		sink.Add(candidate, referrer)
+	}
+	return nil
+}
+
+// blocklistFilter 测试用过滤器：排除指定用户
+type blocklistFilter map[int64]bool
+
+func (f blocklistFilter) Exclude(ctx context.Context, forUserID valueobject.UserID, candidates []valueobject.UserID) map[valueobject.UserID]aggregate.ExclusionReason {
+	excluded := make(map[valueobject.UserID]aggregate.ExclusionReason)
+	for _, candidate := range candidates {
+		if f[candidate.Value()] {
+			excluded[candidate] = "blocklist"
+		}
+	}
+	return excluded
+}
+
+func TestCandidateRegistry_Pipeline(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 关注了 2、3：2 最近关注了 1002~1004，3 最近关注了 1003~1005
+	social := &fakeSocialGraphRepo{followingCount: 2}
+
+	registry := NewCandidateRegistry()
+	registry.RegisterSource("geo", &fakeGeoSource{referrer: 7, candidates: []int64{1002, 5000}})
+	registry.RegisterFilter("blocklist", blocklistFilter{1005: true})
+
+	pipeline, err := registry.Pipeline([]string{SourceFollowing, "geo"}, []string{"blocklist", FilterTrust})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, pipeline).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[int64]int)
+	for _, rec := range list.All() {
+		got[rec.TargetUserID().Value()] = rec.Reason().RelatedCount()
+	}
+	want := map[int64]int{1002: 2, 1003: 2, 1004: 2, 5000: 1} // 1002 的信号来自两个来源，1005 被过滤
+	if len(got) != len(want) {
+		t.Fatalf("recommendations = %v, want %v", got, want)
+	}
+	for id, count := range want {
+		if got[id] != count {
+			t.Errorf("candidate %d related count = %d, want %d", id, got[id], count)
+		}
+	}
+	if n := list.Exclusions()["blocklist"]; n != 1 {
+		t.Errorf("blocklist exclusions = %d, want 1", n)
+	}
+
+	// 配置写错时在启动时报错
+	if _, err := registry.Pipeline([]string{"contacts"}, nil); err == nil {
+		t.Error("unknown source: want error")
+	}
+	if _, err := registry.Pipeline([]string{SourceFollowing}, []string{"nope"}); err == nil {
+		t.Error("unknown filter: want error")
+	}
+}
+
+// fakePostContentRepo 测试用内容仓储：每个作者有一篇 1 小时前和一篇 30 天前的帖子
+type fakePostContentRepo struct {
+	fakeContentRepo
+	now time.Time
+}
+
+func (r *fakePostContentRepo) GetRecentPosts(ctx context.Context, userID valueobject.UserID, limit int) ([]*entity.Post, error) {
+	fresh, _ := valueobject.NewPostID(userID.Value()*10 + 1)
+	stale, _ := valueobject.NewPostID(userID.Value()*10 + 2)
+	return []*entity.Post{
+		entity.NewPost(fresh, userID, "fresh", r.now.Add(-time.Hour)),
+		entity.NewPost(stale, userID, "stale", r.now.Add(-30*24*time.Hour)),
+	}, nil
+}
+
+func TestGeneratePostRecommendations(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	generator := NewRecommendationGenerator(
+		&fakeSocialGraphRepo{followingCount: 3},
+		&fakePostContentRepo{now: time.Now()},
+	)
+
+	posts, err := generator.GeneratePostRecommendations(context.Background(), forUserID, 7, 5)
+	if err != nil {
+		t.Fatalf("GeneratePostRecommendations failed: %v", err)
+	}
+	// 作者 1002~1006（每人一篇窗口内的帖子），窗口之外的帖子不推荐
+	if len(posts) != 5 {
+		t.Fatalf("len(posts) = %d, want 5", len(posts))
+	}
+	for i, rec := range posts {
+		if rec.Post().Content() != "fresh" {
+			t.Errorf("posts[%d] content = %q, want fresh", i, rec.Post().Content())
+		}
+		if i > 0 && posts[i-1].Score() < rec.Score() {
+			t.Errorf("posts not sorted by score: %d < %d", posts[i-1].Score(), rec.Score())
+		}
+	}
+	// 被三个"我关注的人"都关注的作者排在最前面
+	if author := posts[0].AuthorID().Value(); author != 1004 {
+		t.Errorf("posts[0] author = %d, want 1004", author)
+	}
+}
+
+// fakeInteractionRepo 测试用互动记录：用户 → 最近一次互动的时间
+type fakeInteractionRepo map[int64]time.Time
+
+func (r fakeInteractionRepo) GetLastInteractions(ctx context.Context, userID valueobject.UserID, targets []valueobject.UserID) (map[valueobject.UserID]time.Time, error) {
+	result := make(map[valueobject.UserID]time.Time)
+	for _, target := range targets {
+		if last, ok := r[target.Value()]; ok {
+			result[target] = last
+		}
+	}
+	return result, nil
+}
+
+func (r fakeInteractionRepo) CountReceivedEngagement(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID]valueobject.CreatorEngagement, error) {
+	return nil, nil
+}
+
+// stubCreatorEngagementRepo 测试用创作者互动：用户 → 最近 N 天收到的互动
+type stubCreatorEngagementRepo struct {
+	fakeInteractionRepo
+	engagement map[int64]valueobject.CreatorEngagement
+	err        error
+}
+
+func (r stubCreatorEngagementRepo) CountReceivedEngagement(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID]valueobject.CreatorEngagement, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := make(map[valueobject.UserID]valueobject.CreatorEngagement)
+	for _, userID := range userIDs {
+		if e, ok := r.engagement[userID.Value()]; ok {
+			result[userID] = e
+		}
+	}
+	return result, nil
+}
+
+func TestGenerateFollowingBasedRecommendations_CreatorEngagement(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	social := &fakeSocialGraphRepo{followingCount: 2} // 候选：1003、1004 各 2 个信号来源，1002、1005 各 1 个
+
+	generate := func(repo stubCreatorEngagementRepo) *aggregate.RecommendationList {
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithCreatorEngagement(repo)).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list
+	}
+
+	list := generate(stubCreatorEngagementRepo{engagement: map[int64]valueobject.CreatorEngagement{
+		1005: {Likes: 400},                  // 400 / 100 × 5
+		1002: {Likes: 5000, Comments: 1000}, // 加分封顶：3 × 10
+		1004: {Likes: 99},                   // 不满 100 不加分
+	}})
+	want := map[int64]int{1002: 46, 1003: 26, 1004: 26, 1005: 36}
+	for _, rec := range list.All() {
+		if got := rec.Score(); got != want[rec.TargetUserID().Value()] {
+			t.Errorf("user %d score = %d, want %d", rec.TargetUserID().Value(), got, want[rec.TargetUserID().Value()])
+		}
+	}
+	// 内容常有人互动的创作者排在社交信号更多、只是发帖的人前面
+	if top := list.GetTopN(1); top[0].TargetUserID().Value() != 1002 {
+		t.Errorf("top = %d, want 1002", top[0].TargetUserID().Value())
+	}
+
+	// 查询失败：不加分，推荐照常生成
+	want = map[int64]int{1002: 16, 1003: 26, 1004: 26, 1005: 16}
+	list = generate(stubCreatorEngagementRepo{err: errors.New("interactions unavailable")})
+	if list.Count() != len(want) {
+		t.Fatalf("got %d recommendations, want %d", list.Count(), len(want))
+	}
+	for _, rec := range list.All() {
+		if got := rec.Score(); got != want[rec.TargetUserID().Value()] {
+			t.Errorf("unavailable: user %d score = %d, want %d", rec.TargetUserID().Value(), got, want[rec.TargetUserID().Value()])
+		}
+	}
+}
+
+func TestDormantConnectionSource_Pipeline(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	now := time.Now()
+	// 关注了 2、3、4：2 昨天互动过，3 两个月前互动过，4 从来没有互动过（被 blocklist 过滤）
+	social := &fakeSocialGraphRepo{followingCount: 3}
+	dormant := NewDormantConnectionSource(social, &fakeContentRepo{}, fakeInteractionRepo{
+		2: now.Add(-24 * time.Hour),
+		3: now.Add(-60 * 24 * time.Hour),
+	})
+
+	registry := NewCandidateRegistry()
+	registry.RegisterRecommendationSource(SourceDormant, dormant)
+	registry.RegisterFilter("blocklist", blocklistFilter{4: true})
+	pipeline, err := registry.Pipeline([]string{SourceFollowing, SourceDormant}, []string{"blocklist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, pipeline).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var missed []int64
+	for _, rec := range list.All() {
+		if rec.Reason().Type() == valueobject.ReasonMissedPosts {
+			missed = append(missed, rec.TargetUserID().Value())
+			if rec.Reason().RelatedCount() != 3 {
+				t.Errorf("user %d: missed posts = %d, want 3", rec.TargetUserID().Value(), rec.Reason().RelatedCount())
+			}
+		}
+	}
+	if len(missed) != 1 || missed[0] != 3 {
+		t.Errorf("missed posts recommendations = %v, want [3]", missed)
+	}
+	if n := list.Exclusions()["blocklist"]; n != 1 {
+		t.Errorf("blocklist exclusions = %d, want 1", n)
+	}
+	// following 来源的候选 1002~1006 不受影响
+	if list.Count() != 6 {
+		t.Errorf("count = %d, want 6", list.Count())
+	}
+}
+
+// mutualGraphRepo 测试用社交图谱：指定每个用户的好友（互相关注）
+type mutualGraphRepo struct {
+	*fakeSocialGraphRepo
+	mutuals map[int64][]int64
+}
+
+func (r mutualGraphRepo) GetMutualFollows(ctx context.Context, userID valueobject.UserID, limit int) ([]valueobject.UserID, error) {
+	result := make([]valueobject.UserID, 0, len(r.mutuals[userID.Value()]))
+	for _, id := range r.mutuals[userID.Value()] {
+		friendID, _ := valueobject.NewUserID(id)
+		result = append(result, friendID)
+	}
+	return result, nil
+}
+
+func TestMutualFollowSource_Recommend(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 用户 1 关注了 2、3、4，三个都是好友；10 是三位好友的好友，11 是两位的，12 只是一位的
+	social := mutualGraphRepo{
+		fakeSocialGraphRepo: &fakeSocialGraphRepo{followingCount: 3},
+		mutuals: map[int64][]int64{
+			1: {2, 3, 4},
+			2: {1, 4, 10, 11},
+			3: {1, 10, 11, 12},
+			4: {1, 2, 10},
+		},
+	}
+
+	recs, err := NewMutualFollowSource(social).Recommend(context.Background(), forUserID, 7, aggregate.DefaultScoringPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 已经关注的 2、4 和自己不推荐；12 的共同好友太少
+	want := []struct {
+		userID int64
+		count  int
+		score  int
+	}{
+		{10, 3, 60},
+		{11, 2, 40},
+	}
+	if len(recs) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(recs), len(want))
+	}
+	for i, w := range want {
+		rec := recs[i]
+		if rec.TargetUserID().Value() != w.userID || rec.Reason().Type() != valueobject.ReasonManyMutualFollows ||
+			rec.Reason().RelatedCount() != w.count || rec.Score() != w.score {
+			t.Errorf("recs[%d] = user %d (%s, score %d), want user %d with %d mutual friends, score %d",
+				i, rec.TargetUserID().Value(), rec.Reason().Description(), rec.Score(), w.userID, w.count, w.score)
+		}
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_MergesReasons(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 关注了 2、3：2 最近关注了 1002~1004，3 最近关注了 1003~1005
+	// 2、3 都是好友，1003 也是他们两个的好友：following 和 mutual 两个来源都发现了 1003
+	social := mutualGraphRepo{
+		fakeSocialGraphRepo: &fakeSocialGraphRepo{followingCount: 2},
+		mutuals: map[int64][]int64{
+			1: {2, 3},
+			2: {1, 1003},
+			3: {1, 1003},
+		},
+	}
+	generate := func(sources ...string) map[int64]*aggregate.UserRecommendation {
+		registry := NewCandidateRegistry()
+		registry.RegisterRecommendationSource(SourceMutual, NewMutualFollowSource(social))
+		pipeline, err := registry.Pipeline(sources, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, pipeline).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make(map[int64]*aggregate.UserRecommendation)
+		for _, rec := range list.All() {
+			result[rec.TargetUserID().Value()] = rec
+		}
+		return result
+	}
+
+	base := generate(SourceFollowing)
+	merged := generate(SourceFollowing, SourceMutual)
+	if len(merged) != len(base) {
+		t.Fatalf("count = %d, want %d (1003 should be merged, not added twice)", len(merged), len(base))
+	}
+
+	// 1003：两条理由的权重相加（2 位关注的人 20 分 + 2 位共同好友 40 分），展示更强的共同好友
+	rec := merged[1003]
+	if got, want := rec.Score(), base[1003].Score()+40; got != want {
+		t.Errorf("1003 score = %d, want %d", got, want)
+	}
+	if rec.Reason().Type() != valueobject.ReasonManyMutualFollows {
+		t.Errorf("1003 primary reason = %v, want many mutual follows", rec.Reason().Type())
+	}
+	if types := rec.ReasonTypes(); len(types) != 2 || types[0] != valueobject.ReasonFollowedByFollowing {
+		t.Errorf("1003 reason types = %v, want [followed_by_following many_mutual_follows]", types)
+	}
+	// 只被一个来源发现的候选不受影响
+	if merged[1002].Score() != base[1002].Score() || merged[1002].Reasons().IsComposite() {
+		t.Errorf("1002 = score %d (%v), want unchanged %d", merged[1002].Score(), merged[1002].ReasonTypes(), base[1002].Score())
+	}
+}
+
+// fakePopularUsersRepo 测试用冷启动名单
+type fakePopularUsersRepo struct {
+	popular, curated []int64
+	popularErr       error
+}
+
+func (r fakePopularUsersRepo) GetPopularUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	if r.popularErr != nil {
+		return nil, r.popularErr
+	}
+	return userIDsOf(r.popular), nil
+}
+
+func (r fakePopularUsersRepo) GetCuratedUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	return userIDsOf(r.curated), nil
+}
+
+func userIDsOf(ids []int64) []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, _ := valueobject.NewUserID(id)
+		result = append(result, userID)
+	}
+	return result
+}
+
+func TestGenerateFollowingBasedRecommendations_ColdStart(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	popular := fakePopularUsersRepo{curated: []int64{5, 1}, popular: []int64{5, 6, 7}}
+	generate := func(social repository.SocialGraphRepository, popular fakePopularUsersRepo) []*aggregate.UserRecommendation {
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{},
+			WithColdStart(NewColdStartSource(popular, social)),
+			WithBlockRepository(fakeBlockRepo{blocked: []int64{7}}),
+		).GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list.GetTopN(list.Count())
+	}
+
+	// 没有关注任何人：编辑精选在前，全站热门在后；自己、重复的 5、屏蔽的 7 不推荐
+	recs := generate(&fakeSocialGraphRepo{}, popular)
+	want := []struct {
+		userID int64
+		reason valueobject.ReasonType
+	}{
+		{5, valueobject.ReasonEditorPick},
+		{6, valueobject.ReasonPopularOnPlatform},
+	}
+	if len(recs) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(recs), len(want))
+	}
+	for i, w := range want {
+		if recs[i].TargetUserID().Value() != w.userID || recs[i].Reason().Type() != w.reason {
+			t.Errorf("recs[%d] = user %d (%v), want user %d (%v)",
+				i, recs[i].TargetUserID().Value(), recs[i].Reason().Type(), w.userID, w.reason)
+		}
+	}
+
+	// 全站热门查询失败：只用编辑精选
+	failing := popular
+	failing.popularErr = errors.New("popular users unavailable")
+	if recs := generate(&fakeSocialGraphRepo{}, failing); len(recs) != 1 || recs[0].TargetUserID().Value() != 5 {
+		t.Errorf("popular failed: got %d recommendations, want only user 5", len(recs))
+	}
+
+	// 有其他推荐时不使用冷启动
+	for _, rec := range generate(&fakeSocialGraphRepo{followingCount: 2}, popular) {
+		if rec.Reason().IsColdStart() {
+			t.Errorf("user %d: cold start reason %v with following-based recommendations", rec.TargetUserID().Value(), rec.Reason().Type())
+		}
+	}
+}
+
+func TestGeneratePopularityBasedRecommendations(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 关注了 2~5：1005 被 3、4、5 关注，1004 被 2、3、4 关注，1006 被 4、5 关注（不够热门）
+	social := &fakeSocialGraphRepo{followingCount: 4}
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}).
+		GeneratePopularityBasedRecommendations(context.Background(), forUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[int64]bool)
+	for _, rec := range list.All() {
+		got[rec.TargetUserID().Value()] = true
+		if rec.Reason().Type() != valueobject.ReasonPopularInNetwork {
+			t.Errorf("user %d: reason = %v, want popular in network", rec.TargetUserID().Value(), rec.Reason().Type())
+		}
+	}
+	if len(got) != 2 || !got[1004] || !got[1005] {
+		t.Errorf("popular users = %v, want 1004 and 1005", got)
+	}
+}
+
+// stubEngagementRepo 测试用实时互动信号
+type stubEngagementRepo struct {
+	engagement map[int64]valueobject.RecentEngagement
+	err        error
+}
+
+func (r stubEngagementRepo) GetRecentEngagement(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID]valueobject.RecentEngagement, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := make(map[valueobject.UserID]valueobject.RecentEngagement)
+	for _, userID := range userIDs {
+		if e, ok := r.engagement[userID.Value()]; ok {
+			result[userID] = e
+		}
+	}
+	return result, nil
+}
+
+func TestGenerateFollowingBasedRecommendations_EngagementSignals(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	social := &fakeSocialGraphRepo{followingCount: 2} // 候选：1003、1004 各 2 个信号来源，1002、1005 各 1 个
+	engagement := stubEngagementRepo{engagement: map[int64]valueobject.RecentEngagement{
+		1005: {Likes: 300},  // 加分封顶：2 × 10
+		1002: {Comments: 2}, // 2 × 3
+		1003: {Likes: -1},   // 异常数据不加分
+	}}
+
+	scores := func(repo stubEngagementRepo) map[int64]int {
+		list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithEngagementSignals(repo)).
+			GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make(map[int64]int)
+		for _, rec := range list.All() {
+			result[rec.TargetUserID().Value()] = rec.Score()
+		}
+		return result
+	}
+
+	want := map[int64]int{1002: 22, 1003: 26, 1004: 26, 1005: 36}
+	for target, score := range scores(engagement) {
+		if want[target] != score {
+			t.Errorf("user %d score = %d, want %d", target, score, want[target])
+		}
+	}
+
+	// 计数服务不可用：不加分，推荐照常生成
+	want = map[int64]int{1002: 16, 1003: 26, 1004: 26, 1005: 16}
+	got := scores(stubEngagementRepo{err: errors.New("counter service unavailable")})
+	if len(got) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(got), len(want))
+	}
+	for target, score := range got {
+		if want[target] != score {
+			t.Errorf("unavailable: user %d score = %d, want %d", target, score, want[target])
+		}
+	}
+}
+
+// postsOnlyPolicy 测试用打分策略：只看最近帖子数
+type postsOnlyPolicy struct{}
+
+func (postsOnlyPolicy) Factors(input aggregate.ScoringInput) []aggregate.ScoreFactor {
+	return []aggregate.ScoreFactor{
+		{Name: aggregate.FactorRecentPosts, Value: input.RecentPostCount, Contribution: input.RecentPostCount},
+	}
+}
+
+func TestGenerateFollowingBasedRecommendations_ScoringPolicy(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	social := &fakeSocialGraphRepo{followingCount: 2}
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithScoringPolicy(postsOnlyPolicy{}),
+		WithTenantScoringWeights(map[valueobject.TenantID]valueobject.ScoringWeights{
+			valueobject.DefaultTenantID: valueobject.DefaultScoringWeights(),
+		})).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count() == 0 {
+		t.Fatal("want recommendations")
+	}
+	// 自定义策略优先于租户的打分权重：分数只看帖子数（每人 3 篇）
+	for _, rec := range list.All() {
+		breakdown := rec.ScoreBreakdown()
+		if rec.Score() != 3 || breakdown.Score != rec.Score() {
+			t.Errorf("user %d score = %d, breakdown = %+v, want 3", rec.TargetUserID().Value(), rec.Score(), breakdown)
+		}
+		if breakdown.Weights != (valueobject.ScoringWeights{}) {
+			t.Errorf("custom policy breakdown weights = %+v, want zero", breakdown.Weights)
+		}
+	}
+}
+
+// fakeBlockRepo 测试用屏蔽关系：blocked 是用户屏蔽的人，blockers 是屏蔽了用户的人
+type fakeBlockRepo struct {
+	blocked, blockers []int64
+	err               error
+}
+
+func (r fakeBlockRepo) GetBlockedUsers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	return toTestUserIDs(r.blocked), r.err
+}
+
+func (r fakeBlockRepo) GetBlockers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	return toTestUserIDs(r.blockers), r.err
+}
+
+func (r fakeBlockRepo) IsBlocked(ctx context.Context, blockerID, blockedID valueobject.UserID) (bool, error) {
+	return false, r.err
+}
+
+func toTestUserIDs(ids []int64) []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, _ := valueobject.NewUserID(id)
+		result = append(result, userID)
+	}
+	return result
+}
+
+func TestGenerateFollowingBasedRecommendations_Blocks(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 候选 1002~1006；dormant 来源推荐 3（两个月没有互动）
+	social := &fakeSocialGraphRepo{followingCount: 3}
+	registry := NewCandidateRegistry()
+	registry.RegisterRecommendationSource(SourceDormant, NewDormantConnectionSource(social, &fakeContentRepo{}, fakeInteractionRepo{
+		2: time.Now(),
+		4: time.Now(),
+	}))
+	pipeline, err := registry.Pipeline([]string{SourceFollowing, SourceDormant}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户屏蔽了 1003，1005 屏蔽了用户，3 也屏蔽了用户（自带理由的来源同样排除）
+	blocks := fakeBlockRepo{blocked: []int64{1003}, blockers: []int64{1005, 3}}
+	list, err := NewRecommendationGenerator(social, &fakeContentRepo{}, pipeline, WithBlockRepository(blocks)).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range list.All() {
+		if id := rec.TargetUserID().Value(); id == 1003 || id == 1005 || id == 3 {
+			t.Errorf("blocked user %d was recommended", id)
+		}
+	}
+	if list.Count() != 3 {
+		t.Errorf("count = %d, want 3", list.Count())
+	}
+	if n := list.Exclusions()[aggregate.ExclusionBlocked]; n != 3 {
+		t.Errorf("blocked exclusions = %d, want 3", n)
+	}
+
+	// 屏蔽关系查询失败时不生成（fail-closed）
+	failing := fakeBlockRepo{err: errors.New("blocks unavailable")}
+	if _, err := NewRecommendationGenerator(social, &fakeContentRepo{}, WithBlockRepository(failing)).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7); err == nil {
+		t.Error("expected error when blocks cannot be loaded")
+	}
+}
+
+// fakeTrustRepo 测试用风控仓储：预设的风险等级（或错误）
+type fakeTrustRepo struct {
+	levels map[int64]valueobject.RiskLevel
+	err    error
+}
+
+func (r fakeTrustRepo) GetRiskLevels(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID]valueobject.RiskLevel, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := make(map[valueobject.UserID]valueobject.RiskLevel, len(userIDs))
+	for _, userID := range userIDs {
+		if level, ok := r.levels[userID.Value()]; ok {
+			result[userID] = level
+		}
+	}
+	return result, nil
+}
+
+func TestGenerateFollowingBasedRecommendations_TrustFilter(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 关注 2、3、4；候选 1002~1006（2 → 1002~1004，3 → 1003~1005，4 → 1004~1006）
+	social := &fakeSocialGraphRepo{followingCount: 3}
+	trust := fakeTrustRepo{levels: map[int64]valueobject.RiskLevel{
+		2:    valueobject.RiskBanned, // 推荐信号来源是机器人：它的关注不计入
+		1004: valueobject.RiskHigh,   // 候选是机器人：不推荐
+		1005: valueobject.RiskMedium, // 没有超过阈值：正常推荐
+	}}
+
+	generator := NewRecommendationGenerator(social, &fakeContentRepo{}, WithTrustFilter(trust, valueobject.RiskMedium))
+	list, err := generator.GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[int64][]valueobject.UserID)
+	for _, rec := range list.All() {
+		got[rec.TargetUserID().Value()] = rec.Reason().RelatedUsers()
+	}
+	// 1002 只被 2 关注，2 的信号被排除后没有推荐理由
+	if len(got) != 3 || got[1003] == nil || got[1005] == nil || got[1006] == nil {
+		t.Fatalf("recommended %v, want 1003, 1005 and 1006", got)
+	}
+	if len(got[1003]) != 1 || got[1003][0].Value() != 3 {
+		t.Errorf("1003 followed by %v, want only 3", got[1003])
+	}
+	if n := list.Exclusions()[aggregate.ExclusionHighRisk]; n != 1 {
+		t.Errorf("high risk exclusions = %d, want 1", n)
+	}
+	if names := generator.FilterNames(); len(names) != 1 || names[0] != FilterTrust {
+		t.Errorf("filters = %v, want [trust]", names)
+	}
+
+	// 风控服务异常时不过滤（fail-open）
+	failing := fakeTrustRepo{err: errors.New("trust unavailable")}
+	list, err = NewRecommendationGenerator(social, &fakeContentRepo{}, WithTrustFilter(failing, valueobject.RiskMedium)).
+		GenerateFollowingBasedRecommendations(context.Background(), forUserID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count() != 5 {
+		t.Errorf("count = %d with the trust service down, want all 5 candidates", list.Count())
+	}
+}
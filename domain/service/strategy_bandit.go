@@ -0,0 +1,76 @@
+package service
+
+import (
+	"math"
+
+	"service/domain/repository"
+)
+
+// StrategyBanditPolicy 领域服务：按转化反馈计算各推荐策略的混合权重（多臂 bandit）
+//
+// 为什么需要？
+// 一个推荐列表里混合了多种策略的候选（共同关注、网络内热门、重新互动……），
+// 原始分数的尺度由打分规则决定，哪种策略更容易带来关注，要看线上的反馈。
+// 人工调权重既慢又容易过拟合某一天的数据；bandit 按累计的曝光和转化自动调整。
+//
+// 算法（UCB1）：
+// 1. 每种策略的上置信界 = 转化率 + Exploration × sqrt(2 ln 总曝光 / 策略曝光)，
+// 曝光少的策略置信区间宽，会得到更多的探索机会
+// 2. 权重 = 上置信界 / 所有策略上置信界的平均值（平均权重为 1，不改变整体分数尺度）
+// 3. 权重限制在 [MinWeight, MaxWeight]：再差的策略也保留一定的曝光（继续收集反馈），
+// 再好的策略也不会独占整个列表
+//
+// 曝光少于 MinPulls 的策略数据还不可信，直接使用 MaxWeight 探索。
+type StrategyBanditPolicy struct {
+	MinWeight   float64 // 权重下限（探索的保底）
+	MaxWeight   float64 // 权重上限
+	Exploration float64 // 探索系数（0 表示只按转化率）
+	MinPulls    int64   // 曝光少于这个数的策略按上限权重探索
+}
+
+// DefaultStrategyBanditPolicy 默认策略：权重在 0.5~2 之间，标准 UCB1，曝光 1000 次之后才按数据调整
+func DefaultStrategyBanditPolicy() StrategyBanditPolicy {
+	return StrategyBanditPolicy{MinWeight: 0.5, MaxWeight: 2, Exploration: 1, MinPulls: 1000}
+}
+
+// Weights 计算各策略的权重（策略 → 权重），没有任何曝光时返回空 map（所有策略权重为 1）
+func (p StrategyBanditPolicy) Weights(arms []*repository.StrategyArmStats) map[string]float64 {
+	var totalPulls int64
+	for _, arm := range arms {
+		totalPulls += arm.Pulls
+	}
+	weights := make(map[string]float64, len(arms))
+	if totalPulls == 0 {
+		return weights
+	}
+
+	bounds := make(map[string]float64, len(arms))
+	sum := 0.0
+	for _, arm := range arms {
+		if arm.Pulls < p.MinPulls || arm.Pulls == 0 {
+			continue
+		}
+		rate := float64(arm.Rewards) / float64(arm.Pulls)
+		bound := rate + p.Exploration*math.Sqrt(2*math.Log(float64(totalPulls))/float64(arm.Pulls))
+		bounds[arm.Arm] = bound
+		sum += bound
+	}
+
+	for _, arm := range arms {
+		bound, ok := bounds[arm.Arm]
+		switch {
+		case !ok:
+			weights[arm.Arm] = p.MaxWeight
+		case sum == 0:
+			weights[arm.Arm] = 1
+		default:
+			weights[arm.Arm] = p.clamp(bound / (sum / float64(len(bounds))))
+		}
+	}
+	return weights
+}
+
+// clamp 辅助方法：把权重限制在 [MinWeight, MaxWeight]
+func (p StrategyBanditPolicy) clamp(weight float64) float64 {
+	return math.Min(math.Max(weight, p.MinWeight), p.MaxWeight)
+}
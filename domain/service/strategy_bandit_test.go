@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"service/domain/repository"
+)
+
+func TestStrategyBanditPolicy_Weights(t *testing.T) {
+	policy := StrategyBanditPolicy{MinWeight: 0.5, MaxWeight: 2, Exploration: 0, MinPulls: 100}
+
+	if weights := policy.Weights(nil); len(weights) != 0 {
+		t.Errorf("no pulls: weights = %v, want empty", weights)
+	}
+
+	weights := policy.Weights([]*repository.StrategyArmStats{
+		{Arm: "followed_by_following", Pulls: 1000, Rewards: 30}, // 3%
+		{Arm: "popular_in_network", Pulls: 1000, Rewards: 10},    // 1%
+		{Arm: "missed_posts", Pulls: 1000, Rewards: 0},           // 0%
+		{Arm: "new_strategy", Pulls: 10, Rewards: 0},             // 数据不足
+	})
+	// 平均转化率 4/3%：3% → 2.25（限制为 2），1% → 0.75，0% → 0（限制为 0.5）
+	want := map[string]float64{
+		"followed_by_following": 2,
+		"popular_in_network":    0.75,
+		"missed_posts":          0.5,
+		"new_strategy":          2,
+	}
+	for arm, weight := range want {
+		if got := weights[arm]; got < weight-1e-9 || got > weight+1e-9 {
+			t.Errorf("weights[%s] = %v, want %v", arm, got, weight)
+		}
+	}
+
+	// 探索：转化率相同时，曝光少的策略权重更高
+	policy.Exploration = 1
+	weights = policy.Weights([]*repository.StrategyArmStats{
+		{Arm: "a", Pulls: 10000, Rewards: 100},
+		{Arm: "b", Pulls: 1000, Rewards: 10},
+	})
+	if weights["b"] <= weights["a"] {
+		t.Errorf("weights = %v, want less explored arm b weighted higher", weights)
+	}
+}
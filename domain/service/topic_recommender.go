@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+const (
+	// maxNetworkSources 话题、小组推荐最多用多少个"我关注的人"作为推荐信号来源
+	maxNetworkSources = 1000
+	// networkBatchSize 每次批量查询多少人的话题、小组（控制 IN 列表的长度）
+	networkBatchSize = 200
+)
+
+// TopicRecommender 领域服务：话题推荐
+//
+// 业务规则：
+// "我关注的人"关注的话题，我很可能也感兴趣。
+// 关注某个话题的"我关注的人"越多，这个话题的分数越高；我已经关注的话题不推荐。
+//
+// 为什么是单独的领域服务，而不是 RecommendationGenerator 的一个策略？
+// 两者的推荐对象不同（话题 vs 用户），候选来源、过滤器（风控、机器人）都是针对用户的，
+// 共用只会让生成器的每一步都多一个分支。
+type TopicRecommender struct {
+	socialGraphRepo repository.SocialGraphRepository
+	topicRepo       repository.TopicRepository
+}
+
+// NewTopicRecommender 构造函数
+func NewTopicRecommender(
+	socialGraphRepo repository.SocialGraphRepository,
+	topicRepo repository.TopicRepository,
+) *TopicRecommender {
+	return &TopicRecommender{
+		socialGraphRepo: socialGraphRepo,
+		topicRepo:       topicRepo,
+	}
+}
+
+// topicCounter 单个话题的计数器（推荐信号来源总数 + 样本）
+type topicCounter struct {
+	topic  valueobject.Topic
+	count  int
+	sample []valueobject.UserID
+}
+
+// RecommendTopics 为用户推荐话题
+//
+// 步骤：
+// 1. 获取用户关注的人（最多 maxNetworkSources 个）
+// 2. 分批查询他们关注的话题，统计每个话题被多少个"我关注的人"关注
+// 3. 排除用户已经关注的话题
+// 4. 按分数降序排列（分数相同时按话题名称，结果稳定）
+//
+// weights 是租户的打分权重（和用户推荐相同的"每个相关用户加分"）。
+func (t *TopicRecommender) RecommendTopics(
+	ctx context.Context,
+	forUserID valueobject.UserID,
+	weights valueobject.ScoringWeights,
+) ([]*aggregate.TopicRecommendation, error) {
+	followings, err := t.socialGraphRepo.GetFollowings(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(followings) > maxNetworkSources {
+		followings = followings[:maxNetworkSources]
+	}
+
+	counters := make(map[string]*topicCounter)
+	for start := 0; start < len(followings); start += networkBatchSize {
+		batch := followings[start:min(start+networkBatchSize, len(followings))]
+		topicsByUser, err := t.topicRepo.GetFollowedTopicsBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		// 按关注列表的顺序遍历（样本稳定，不受 map 遍历顺序影响）
+		for _, following := range batch {
+			for _, topic := range topicsByUser[following] {
+				counter, ok := counters[topic.Name()]
+				if !ok {
+					counter = &topicCounter{topic: topic}
+					counters[topic.Name()] = counter
+				}
+				counter.count++
+				if len(counter.sample) < maxReferrerSample {
+					counter.sample = append(counter.sample, following)
+				}
+			}
+		}
+	}
+
+	followed, err := t.topicRepo.GetFollowedTopics(ctx, forUserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range followed {
+		delete(counters, topic.Name())
+	}
+
+	now := time.Now()
+	result := make([]*aggregate.TopicRecommendation, 0, len(counters))
+	for _, counter := range counters {
+		reason := valueobject.NewTopicFollowedByFollowingReason(counter.sample, counter.count)
+		rec, err := aggregate.NewTopicRecommendation(counter.topic, reason, weights, now)
+		if err != nil {
+			continue
+		}
+		result = append(result, rec)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score() != result[j].Score() {
+			return result[i].Score() > result[j].Score()
+		}
+		return result[i].Topic().Name() < result[j].Topic().Name()
+	})
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"service/domain/valueobject"
+)
+
+// fakeTopicRepo 测试用话题仓储
+type fakeTopicRepo struct {
+	followed map[int64][]string
+}
+
+func (r *fakeTopicRepo) GetFollowedTopics(ctx context.Context, userID valueobject.UserID) ([]valueobject.Topic, error) {
+	var topics []valueobject.Topic
+	for _, name := range r.followed[userID.Value()] {
+		topic, _ := valueobject.NewTopic(name)
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+func (r *fakeTopicRepo) GetFollowedTopicsBatch(ctx context.Context, userIDs []valueobject.UserID) (map[valueobject.UserID][]valueobject.Topic, error) {
+	result := make(map[valueobject.UserID][]valueobject.Topic)
+	for _, userID := range userIDs {
+		if topics, _ := r.GetFollowedTopics(ctx, userID); len(topics) > 0 {
+			result[userID] = topics
+		}
+	}
+	return result, nil
+}
+
+func TestTopicRecommender_RecommendTopics(t *testing.T) {
+	forUserID, _ := valueobject.NewUserID(1)
+	// 用户 1 关注了 2、3、4
+	topics := &fakeTopicRepo{followed: map[int64][]string{
+		1: {"golang"},
+		2: {"golang", "rust", "#DDD"},
+		3: {"rust", "ddd"},
+		4: {"rust", "kitex"},
+	}}
+
+	recs, err := NewTopicRecommender(&fakeSocialGraphRepo{followingCount: 3}, topics).
+		RecommendTopics(context.Background(), forUserID, valueobject.DefaultScoringWeights())
+	if err != nil {
+		t.Fatalf("RecommendTopics failed: %v", err)
+	}
+
+	// golang 已经关注，不推荐；#DDD 和 ddd 是同一个话题
+	want := []struct {
+		topic string
+		count int
+	}{{"rust", 3}, {"ddd", 2}, {"kitex", 1}}
+	if len(recs) != len(want) {
+		t.Fatalf("len(recs) = %d, want %d", len(recs), len(want))
+	}
+	for i, w := range want {
+		if recs[i].Topic().Name() != w.topic || recs[i].Reason().RelatedCount() != w.count {
+			t.Errorf("recs[%d] = %s (%d), want %s (%d)",
+				i, recs[i].Topic().Name(), recs[i].Reason().RelatedCount(), w.topic, w.count)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package valueobject
+
+// CompositeReason 值对象：组合推荐理由
+//
+// 为什么需要组合理由？
+// 同一个人可能被多种策略同时发现：你关注的 3 个人关注了 TA，TA 又和你有 5 位共同好友。
+// 只保留先发现的那条理由，会丢掉另一条信号：分数偏低，展示的理由也不一定是最有说服力的。
+//
+// 业务规则：
+// - 权重 = 各条理由的权重之和（多种信号同时指向一个人，比单一信号更可靠）
+// - 展示最强的一条理由（Primary）：一条推荐只展示一句理由
+// - 同一类型的理由只保留一条（相关用户更多的那条），同一份信号不重复计分
+//
+// 实际示例（默认权重）：
+//
+//	composite := NewCompositeReason(
+//	    NewFollowedByFollowingReason([]UserID{u1, u2}), // 2 × 10 = 20
+//	    NewPopularInNetworkReason([]UserID{u3}),        // 5
+//	)
+//	composite.Weight()      // 25
+//	composite.Description() // "2 位你关注的人也关注了TA"
+type CompositeReason struct {
+	components []RecommendationReason // 按加入顺序（权重相同时先加入的更强）
+}
+
+// NewCompositeReason 工厂方法：由一条或多条推荐理由组成
+func NewCompositeReason(reasons ...RecommendationReason) CompositeReason {
+	var composite CompositeReason
+	for _, reason := range reasons {
+		composite = composite.With(reason)
+	}
+	return composite
+}
+
+// With 加入一条推荐理由，返回新的组合理由（不修改原来的）
+//
+// 已经有同一类型的理由时，保留相关用户更多的那条。
+func (c CompositeReason) With(reason RecommendationReason) CompositeReason {
+	components := make([]RecommendationReason, 0, len(c.components)+1)
+	replaced := false
+	for _, existing := range c.components {
+		if existing.Type() == reason.Type() {
+			replaced = true
+			if reason.RelatedCount() > existing.RelatedCount() {
+				existing = reason
+			}
+		}
+		components = append(components, existing)
+	}
+	if !replaced {
+		components = append(components, reason)
+	}
+	return CompositeReason{components: components}
+}
+
+// Merge 合并另一个组合理由的全部理由，返回新的组合理由
+func (c CompositeReason) Merge(other CompositeReason) CompositeReason {
+	for _, reason := range other.components {
+		c = c.With(reason)
+	}
+	return c
+}
+
+// Components 访问器：全部推荐理由（返回副本，保证不可变性）
+func (c CompositeReason) Components() []RecommendationReason {
+	result := make([]RecommendationReason, len(c.components))
+	copy(result, c.components)
+	return result
+}
+
+// Primary 最强的一条推荐理由（默认权重最高的，权重相同时先加入的）
+//
+// 推荐理由类型（多样性规则、策略权重、曝光记录）和展示文案都按这一条。
+func (c CompositeReason) Primary() RecommendationReason {
+	if len(c.components) == 0 {
+		return RecommendationReason{}
+	}
+	primary := c.components[0]
+	for _, reason := range c.components[1:] {
+		if reason.Weight() > primary.Weight() {
+			primary = reason
+		}
+	}
+	return primary
+}
+
+// Description 最强的一条推荐理由的描述
+func (c CompositeReason) Description() string {
+	return c.Primary().Description()
+}
+
+// DescriptionIn 最强的一条推荐理由的指定语言描述
+func (c CompositeReason) DescriptionIn(locale Locale) string {
+	return c.Primary().DescriptionIn(locale)
+}
+
+// RelatedCount 各条理由的相关用户总数之和（打分特征：推荐信号来源数）
+func (c CompositeReason) RelatedCount() int {
+	total := 0
+	for _, reason := range c.components {
+		total += reason.RelatedCount()
+	}
+	return total
+}
+
+// Weight 业务规则：各条理由的权重之和（默认权重）
+func (c CompositeReason) Weight() int {
+	return c.WeightWith(DefaultScoringWeights())
+}
+
+// WeightWith 业务规则：按指定的打分权重计算各条理由的权重之和
+func (c CompositeReason) WeightWith(weights ScoringWeights) int {
+	total := 0
+	for _, reason := range c.components {
+		total += reason.WeightWith(weights)
+	}
+	return total
+}
+
+// IsComposite 是否由多条理由组成
+func (c CompositeReason) IsComposite() bool {
+	return len(c.components) > 1
+}
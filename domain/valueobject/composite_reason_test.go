@@ -0,0 +1,48 @@
+package valueobject
+
+import "testing"
+
+func TestCompositeReason(t *testing.T) {
+	u1, _ := NewUserID(1)
+	u2, _ := NewUserID(2)
+	u3, _ := NewUserID(3)
+
+	composite := NewCompositeReason(
+		NewPopularInNetworkReason([]UserID{u3}),
+		NewFollowedByFollowingReason([]UserID{u1, u2}),
+	)
+	if got := composite.Weight(); got != 25 {
+		t.Errorf("Weight() = %d, want 25 (5 + 2×10)", got)
+	}
+	if got := composite.RelatedCount(); got != 3 {
+		t.Errorf("RelatedCount() = %d, want 3", got)
+	}
+	if got := composite.Primary().Type(); got != ReasonFollowedByFollowing {
+		t.Errorf("Primary() = %v, want followed by following", got)
+	}
+	if got, want := composite.Description(), "2 位你关注的人也关注了TA"; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+
+	// 同一类型只保留相关用户更多的那条：同一份信号不重复计分
+	more := composite.With(NewSampledFollowedByFollowingReason(nil, 4))
+	if got := more.Weight(); got != 45 {
+		t.Errorf("after With: Weight() = %d, want 45 (5 + 4×10)", got)
+	}
+	if len(more.Components()) != 2 {
+		t.Errorf("after With: %d components, want 2", len(more.Components()))
+	}
+	if fewer := composite.With(NewFollowedByFollowingReason([]UserID{u1})); fewer.Weight() != 25 {
+		t.Errorf("weaker reason of the same type: Weight() = %d, want 25", fewer.Weight())
+	}
+	// 不修改原来的组合理由
+	if composite.Weight() != 25 {
+		t.Errorf("original composite changed: Weight() = %d", composite.Weight())
+	}
+
+	// 权重相同时先加入的更强
+	tie := NewCompositeReason(NewMissedPostsReason(3), NewPopularInNetworkReason([]UserID{u1}))
+	if got := tie.Primary().Type(); got != ReasonMissedPosts {
+		t.Errorf("tie: Primary() = %v, want missed posts", got)
+	}
+}
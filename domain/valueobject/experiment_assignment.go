@@ -0,0 +1,69 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxExperimentNameLength 实验名、分组名的最大长度（写入曝光记录）
+const maxExperimentNameLength = 32
+
+// ErrInvalidExperimentAssignment 实验名或分组名为空、太长或者包含 "/"
+var ErrInvalidExperimentAssignment = errors.New("invalid experiment assignment")
+
+// ExperimentAssignment 值对象：用户在一个 A/B 实验中分到的分组
+//
+// 实验名和分组名一起写入曝光记录、领域事件和响应元数据，
+// 效果分析按"实验 + 分组"对比点击率、关注率。
+//
+// 零值表示不在任何实验中。
+type ExperimentAssignment struct {
+	experiment string
+	variant    string
+}
+
+// NewExperimentAssignment 工厂方法
+func NewExperimentAssignment(experiment, variant string) (ExperimentAssignment, error) {
+	for _, name := range []string{experiment, variant} {
+		if name == "" || len(name) > maxExperimentNameLength || strings.Contains(name, "/") {
+			return ExperimentAssignment{}, fmt.Errorf("%w: %q/%q", ErrInvalidExperimentAssignment, experiment, variant)
+		}
+	}
+	return ExperimentAssignment{experiment: experiment, variant: variant}, nil
+}
+
+// ParseExperimentAssignment 解析 String() 的结果（空字符串为零值）
+func ParseExperimentAssignment(raw string) (ExperimentAssignment, error) {
+	if raw == "" {
+		return ExperimentAssignment{}, nil
+	}
+	experiment, variant, ok := strings.Cut(raw, "/")
+	if !ok {
+		return ExperimentAssignment{}, fmt.Errorf("%w: %q", ErrInvalidExperimentAssignment, raw)
+	}
+	return NewExperimentAssignment(experiment, variant)
+}
+
+// Experiment 访问器：实验名
+func (a ExperimentAssignment) Experiment() string {
+	return a.experiment
+}
+
+// Variant 访问器：分组名
+func (a ExperimentAssignment) Variant() string {
+	return a.variant
+}
+
+// IsZero 是否不在任何实验中
+func (a ExperimentAssignment) IsZero() bool {
+	return a.experiment == ""
+}
+
+// String 实现 Stringer：实验名/分组名（零值为空字符串）
+func (a ExperimentAssignment) String() string {
+	if a.IsZero() {
+		return ""
+	}
+	return a.experiment + "/" + a.variant
+}
@@ -0,0 +1,34 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownFeedbackAction 反馈类型不是 clicked / followed
+var ErrUnknownFeedbackAction = errors.New("unknown feedback action")
+
+// FeedbackAction 值对象：用户对一条推荐的反馈
+type FeedbackAction string
+
+const (
+	// FeedbackClicked 点开了推荐卡片（进入了被推荐用户的主页）
+	FeedbackClicked FeedbackAction = "clicked"
+	// FeedbackFollowed 直接在推荐卡片上关注了被推荐的用户
+	FeedbackFollowed FeedbackAction = "followed"
+)
+
+// ParseFeedbackAction 工厂方法：从客户端上报的字符串创建反馈类型
+func ParseFeedbackAction(value string) (FeedbackAction, error) {
+	switch action := FeedbackAction(value); action {
+	case FeedbackClicked, FeedbackFollowed:
+		return action, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFeedbackAction, value)
+	}
+}
+
+// String 实现 Stringer 接口
+func (a FeedbackAction) String() string {
+	return string(a)
+}
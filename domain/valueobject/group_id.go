@@ -0,0 +1,39 @@
+package valueobject
+
+import (
+	"errors"
+	"strconv"
+)
+
+var ErrInvalidGroupID = errors.New("invalid group id: must be positive")
+
+// GroupID 值对象：小组（社区）ID
+//
+// 小组由社区服务管理，推荐上下文只通过ID引用它，
+// 小组名称、封面等展示数据由客户端向社区服务获取。
+type GroupID struct {
+	value int64
+}
+
+// NewGroupID 工厂方法：创建小组ID（必须为正数）
+func NewGroupID(value int64) (GroupID, error) {
+	if value <= 0 {
+		return GroupID{}, ErrInvalidGroupID
+	}
+	return GroupID{value: value}, nil
+}
+
+// Value 访问器
+func (g GroupID) Value() int64 {
+	return g.value
+}
+
+// Equals 值对象相等性比较
+func (g GroupID) Equals(other GroupID) bool {
+	return g.value == other.value
+}
+
+// String 实现 Stringer 接口
+func (g GroupID) String() string {
+	return strconv.FormatInt(g.value, 10)
+}
@@ -0,0 +1,83 @@
+package valueobject
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+// Locale 值对象：展示文案的语言（BCP 47，如 zh-CN、en-US）
+//
+// 语言来自请求（RPC 的 metainfo、HTTP 的 Accept-Language），没有时使用租户配置的语言。
+// 零值表示没有指定：配置服务使用默认语言，本地文案使用简体中文。
+//
+// 为什么是值对象而不是字符串？
+// 请求带来的语言五花八门（"en-GB"、"zh-Hant-TW"、"en;q=0.8,zh"），
+// 本地文案只支持几种语言，"哪些写法算同一种语言"由 language.Matcher 统一判断，
+// 调用方不需要自己比较字符串。
+type Locale struct {
+	tag language.Tag
+}
+
+// supportedLocales 本地文案支持的语言（第一个是默认语言：没有匹配的语言时使用）
+var supportedLocales = []language.Tag{
+	language.SimplifiedChinese,
+	language.English,
+	language.Japanese,
+}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+// ParseLocale 工厂方法：解析请求带来的语言
+//
+// 支持单个语言（"en-US"）和 Accept-Language 格式（"en-US,en;q=0.9,zh;q=0.8"，取权重最高的语言）。
+// 为空或者无法解析时返回零值（客户端传错的语言不应该让请求失败）。
+func ParseLocale(raw string) Locale {
+	if raw == "" {
+		return Locale{}
+	}
+	tags, _, err := language.ParseAcceptLanguage(raw)
+	if err != nil || len(tags) == 0 {
+		return Locale{}
+	}
+	return Locale{tag: tags[0]}
+}
+
+// IsZero 是否没有指定语言
+func (l Locale) IsZero() bool {
+	return l.tag == language.Und
+}
+
+// String 实现 Stringer 接口，也是传给配置服务的语言标识（没有指定时为空字符串）
+func (l Locale) String() string {
+	if l.IsZero() {
+		return ""
+	}
+	return l.tag.String()
+}
+
+// supported 辅助方法：本地文案使用的语言（匹配不到时为默认语言）
+func (l Locale) supported() language.Tag {
+	if l.IsZero() {
+		return supportedLocales[0]
+	}
+	_, index, _ := localeMatcher.Match(l.tag)
+	return supportedLocales[index]
+}
+
+// localeKey context 中保存请求语言的 key
+type localeKey struct{}
+
+// WithLocale 把请求的语言放进 ctx（接口层识别一次，之后随 ctx 传到每一层，和租户一样）
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	if locale.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext 取出请求的语言（没有时为零值）
+func LocaleFromContext(ctx context.Context) Locale {
+	locale, _ := ctx.Value(localeKey{}).(Locale)
+	return locale
+}
@@ -0,0 +1,58 @@
+package valueobject
+
+// ModerationStatus 值对象：内容审核状态
+//
+// 审核状态由内容审核系统给出，推荐上下文只关心结果，不关心审核过程。
+//
+// 为什么需要审核状态？
+// 推荐卡片会展示被推荐用户的最近帖子（预览），
+// 如果预览里出现色情/敏感内容，直接展示给用户是不可接受的。
+//
+// 状态说明：
+// - ModerationUnknown：没有审核信息（如旧数据、上游未返回）
+// - ModerationApproved：审核通过
+// - ModerationSensitive：敏感内容（如暴力、争议话题），部分场景需要遮挡
+// - ModerationNSFW：不适合公开展示的内容
+type ModerationStatus int
+
+const (
+	ModerationUnknown ModerationStatus = iota
+	ModerationApproved
+	ModerationSensitive
+	ModerationNSFW
+)
+
+// ParseModerationStatus 工厂方法：从审核系统/内容服务的字符串标识解析审核状态
+//
+// 无法识别的标识视为 ModerationUnknown
+func ParseModerationStatus(value string) ModerationStatus {
+	switch value {
+	case "approved":
+		return ModerationApproved
+	case "sensitive":
+		return ModerationSensitive
+	case "nsfw":
+		return ModerationNSFW
+	default:
+		return ModerationUnknown
+	}
+}
+
+// IsFlagged 业务规则：内容是否被审核标记（敏感或 NSFW）
+func (m ModerationStatus) IsFlagged() bool {
+	return m == ModerationSensitive || m == ModerationNSFW
+}
+
+// String 实现 Stringer 接口，也是持久化/传输时使用的标识
+func (m ModerationStatus) String() string {
+	switch m {
+	case ModerationApproved:
+		return "approved"
+	case ModerationSensitive:
+		return "sensitive"
+	case ModerationNSFW:
+		return "nsfw"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,104 @@
+package valueobject
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// 推荐理由本地文案的 key（Description 的降级文案，配置服务可用时不使用）
+const (
+	msgFollowedByFollowing      = "reason.followed_by_following"
+	msgPopularInNetwork         = "reason.popular_in_network"
+	msgTopicFollowedByFollowing = "reason.topic_followed_by_following"
+	msgFriendsAreMembers        = "reason.friends_are_members"
+	msgFollowedUserMilestone    = "reason.followed_user_milestone"
+	msgFollowedUserReturned     = "reason.followed_user_returned"
+	msgMissedPosts              = "reason.missed_posts"
+	msgManyMutualFollows        = "reason.many_mutual_follows"
+	msgPopularOnPlatform        = "reason.popular_on_platform"
+	msgEditorPick               = "reason.editor_pick"
+	msgBackfill                 = "reason.backfill"
+	msgDefault                  = "reason.default"
+)
+
+// reasonMessages 推荐理由的本地文案（supportedLocales 中的每种语言）
+//
+// 为什么用 x/text 的 plural？
+// 中文、日文的数量词没有单复数："1 位你关注的人"和"3 位你关注的人"是同一个句式；
+// 英文要区分 "1 person you follow" 和 "3 people you follow"，其他语言的规则更多（如俄语有三种）。
+// 单复数规则由 CLDR 数据决定（plural.Selectf），新增语言时只需要在这里加文案，不需要写 if count == 1。
+var reasonMessages = map[language.Tag]map[string]catalog.Message{
+	language.SimplifiedChinese: {
+		msgFollowedByFollowing:      catalog.String("%d 位你关注的人也关注了TA"),
+		msgPopularInNetwork:         catalog.String("在你的社交网络中很受欢迎"),
+		msgTopicFollowedByFollowing: catalog.String("%d 位你关注的人关注了这个话题"),
+		msgFriendsAreMembers:        catalog.String("%d 位你关注的人是小组成员"),
+		msgFollowedUserMilestone:    catalog.String("你关注的TA粉丝突破了 %d"),
+		msgFollowedUserReturned:     catalog.String("你关注的TA时隔 %d 天回来了"),
+		msgMissedPosts:              catalog.String("你可能错过了TA的 %d 篇新帖子"),
+		msgManyMutualFollows:        catalog.String("你们有 %d 位共同好友"),
+		msgPopularOnPlatform:        catalog.String("很多人都在关注TA"),
+		msgEditorPick:               catalog.String("编辑推荐"),
+		msgBackfill:                 catalog.String("热门用户"),
+		msgDefault:                  catalog.String("推荐给你"),
+	},
+	language.English: {
+		msgFollowedByFollowing: plural.Selectf(1, "%d",
+			"one", "%[1]d person you follow also follows them",
+			"other", "%[1]d people you follow also follow them"),
+		msgPopularInNetwork: catalog.String("Popular in your network"),
+		msgTopicFollowedByFollowing: plural.Selectf(1, "%d",
+			"one", "%[1]d person you follow follows this topic",
+			"other", "%[1]d people you follow follow this topic"),
+		msgFriendsAreMembers: plural.Selectf(1, "%d",
+			"one", "%[1]d person you follow is a member",
+			"other", "%[1]d people you follow are members"),
+		msgFollowedUserMilestone: catalog.String("Someone you follow just passed %d followers"),
+		msgFollowedUserReturned: plural.Selectf(1, "%d",
+			"one", "Someone you follow is back after %[1]d day",
+			"other", "Someone you follow is back after %[1]d days"),
+		msgMissedPosts: plural.Selectf(1, "%d",
+			"one", "You may have missed %[1]d new post from them",
+			"other", "You may have missed %[1]d new posts from them"),
+		msgManyMutualFollows: plural.Selectf(1, "%d",
+			"one", "You have %[1]d mutual friend",
+			"other", "You have %[1]d mutual friends"),
+		msgPopularOnPlatform: catalog.String("Popular on the platform"),
+		msgEditorPick:        catalog.String("Editor's pick"),
+		msgBackfill:          catalog.String("Popular accounts"),
+		msgDefault:           catalog.String("Recommended for you"),
+	},
+	language.Japanese: {
+		msgFollowedByFollowing:      catalog.String("フォロー中の%d人もフォローしています"),
+		msgPopularInNetwork:         catalog.String("あなたのネットワークで人気です"),
+		msgTopicFollowedByFollowing: catalog.String("フォロー中の%d人がこのトピックをフォローしています"),
+		msgFriendsAreMembers:        catalog.String("フォロー中の%d人がこのグループのメンバーです"),
+		msgFollowedUserMilestone:    catalog.String("フォロー中のユーザーのフォロワーが%dを突破しました"),
+		msgFollowedUserReturned:     catalog.String("フォロー中のユーザーが%d日ぶりに戻ってきました"),
+		msgMissedPosts:              catalog.String("新しい投稿%d件を見逃しているかもしれません"),
+		msgManyMutualFollows:        catalog.String("共通の友達が%d人います"),
+		msgPopularOnPlatform:        catalog.String("多くの人がフォローしています"),
+		msgEditorPick:               catalog.String("編集部のおすすめ"),
+		msgBackfill:                 catalog.String("人気のユーザー"),
+		msgDefault:                  catalog.String("おすすめ"),
+	},
+}
+
+// reasonPrinters 每种支持的语言一个 Printer（Printer 可以并发使用）
+var reasonPrinters = func() map[language.Tag]*message.Printer {
+	builder := catalog.NewBuilder(catalog.Fallback(supportedLocales[0]))
+	for tag, messages := range reasonMessages {
+		for key, msg := range messages {
+			if err := builder.Set(tag, key, msg); err != nil {
+				panic(err) // 文案写错：启动时（包初始化）就发现
+			}
+		}
+	}
+	printers := make(map[language.Tag]*message.Printer, len(supportedLocales))
+	for _, tag := range supportedLocales {
+		printers[tag] = message.NewPrinter(tag, message.Catalog(builder))
+	}
+	return printers
+}()
@@ -0,0 +1,33 @@
+package valueobject
+
+// RecentEngagement 值对象：一个账号最近一小时产生的互动（来自实时计数服务）
+//
+// 统计的是别人对 TA 的内容的点赞、评论，用于判断 TA "现在是不是正在被讨论"。
+type RecentEngagement struct {
+	Likes    int // 最近一小时收到的点赞数
+	Comments int // 最近一小时收到的评论数
+}
+
+// IsZero 最近一小时没有互动（或者没有拿到数据）
+func (e RecentEngagement) IsZero() bool {
+	return e.Likes <= 0 && e.Comments <= 0
+}
+
+// CreatorEngagement 值对象：一个账号最近 N 天收到的互动（来自互动记录，N 和最近帖子数的时间窗口相同）
+//
+// 和 RecentEngagement 不同，统计的不是"现在热不热"，而是"TA 发的内容有没有人看"：
+// 发帖很多但没有互动的账号，不应该排在内容常被点赞、评论的创作者前面。
+type CreatorEngagement struct {
+	Likes    int // 最近 N 天收到的点赞数
+	Comments int // 最近 N 天收到的评论数
+}
+
+// IsZero 最近 N 天没有互动（或者没有拿到数据）
+func (e CreatorEngagement) IsZero() bool {
+	return e.Likes <= 0 && e.Comments <= 0
+}
+
+// Points 互动分：每个点赞 1 分、每条评论 3 分（异常的负数按 0 处理）
+func (e CreatorEngagement) Points() int {
+	return max(e.Likes, 0) + 3*max(e.Comments, 0)
+}
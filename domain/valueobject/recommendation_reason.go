@@ -1,7 +1,5 @@
 package valueobject
 
-import "fmt"
-
 // ReasonType 推荐理由类型
 type ReasonType int
 
@@ -10,6 +8,24 @@ const (
 	ReasonFollowedByFollowing ReasonType = iota
 	// ReasonPopularInNetwork 在你的社交网络中很受欢迎
 	ReasonPopularInNetwork
+	// ReasonTopicFollowedByFollowing 你关注的人关注了这个话题
+	ReasonTopicFollowedByFollowing
+	// ReasonFriendsAreMembers 你关注的人是这个小组的成员
+	ReasonFriendsAreMembers
+	// ReasonFollowedUserMilestone 你关注的人达到了一个里程碑（如粉丝数突破 1 万）
+	ReasonFollowedUserMilestone
+	// ReasonFollowedUserReturned 你关注的人很久没有活跃，最近回来了
+	ReasonFollowedUserReturned
+	// ReasonMissedPosts 你关注了 TA 但很久没有互动，可能错过了 TA 的新帖子
+	ReasonMissedPosts
+	// ReasonManyMutualFollows 你们有很多共同好友（和你互相关注的人也和 TA 互相关注）
+	ReasonManyMutualFollows
+	// ReasonPopularOnPlatform 全站热门用户（冷启动：用户还没有关注任何人）
+	ReasonPopularOnPlatform
+	// ReasonEditorPick 编辑推荐（冷启动：人工精选的用户）
+	ReasonEditorPick
+	// ReasonBackfill 补足一页的推荐（个性化推荐不够一页时，用热门、精选用户补齐）
+	ReasonBackfill
 )
 
 // RecommendationReason 值对象：推荐理由
@@ -39,7 +55,8 @@ const (
 // 3. 渐进式迁移，不需要前后端同时上线
 type RecommendationReason struct {
 	reasonType   ReasonType
-	relatedUsers []UserID // 哪些关注的人关注了这个推荐用户
+	relatedUsers []UserID // 哪些关注的人关注了这个推荐用户（可能只是样本）
+	relatedCount int      // 相关用户总数（relatedUsers 是样本时，总数大于样本数）
 	displayText  string   // 后端配置的展示文案（可选，为空时使用本地逻辑）
 }
 
@@ -48,15 +65,141 @@ func NewFollowedByFollowingReason(users []UserID) RecommendationReason {
 	return RecommendationReason{
 		reasonType:   ReasonFollowedByFollowing,
 		relatedUsers: users,
+		relatedCount: len(users),
+		displayText:  "", // 使用本地逻辑生成文案
+	}
+}
+
+// NewSampledFollowedByFollowingReason 工厂方法：创建只保留相关用户样本的"关注的人关注了TA"推荐理由
+//
+// 为什么需要样本？
+// 大 V 可能被用户关注的几百个人同时关注，
+// 推荐理由只需要"总人数 + 少量头像"，没必要保存全部相关用户。
+//
+// 参数：
+// - sample: 相关用户样本（用于展示）
+// - total: 相关用户总数（用于权重计算和文案），小于样本数时按样本数计
+func NewSampledFollowedByFollowingReason(sample []UserID, total int) RecommendationReason {
+	if total < len(sample) {
+		total = len(sample)
+	}
+	return RecommendationReason{
+		reasonType:   ReasonFollowedByFollowing,
+		relatedUsers: sample,
+		relatedCount: total,
 		displayText:  "", // 使用本地逻辑生成文案
 	}
 }
 
+// NewTopicFollowedByFollowingReason 工厂方法：创建"关注的人关注了这个话题"类型的推荐理由
+//
+// 参数和 NewSampledFollowedByFollowingReason 相同：sample 是相关用户样本，total 是总数。
+func NewTopicFollowedByFollowingReason(sample []UserID, total int) RecommendationReason {
+	if total < len(sample) {
+		total = len(sample)
+	}
+	return RecommendationReason{
+		reasonType:   ReasonTopicFollowedByFollowing,
+		relatedUsers: sample,
+		relatedCount: total,
+	}
+}
+
+// NewFriendsAreMembersReason 工厂方法：创建"关注的人是这个小组的成员"类型的推荐理由
+//
+// 参数和 NewSampledFollowedByFollowingReason 相同：sample 是相关用户样本，total 是总数。
+func NewFriendsAreMembersReason(sample []UserID, total int) RecommendationReason {
+	if total < len(sample) {
+		total = len(sample)
+	}
+	return RecommendationReason{
+		reasonType:   ReasonFriendsAreMembers,
+		relatedUsers: sample,
+		relatedCount: total,
+	}
+}
+
+// NewFollowedUserMilestoneReason 工厂方法：创建"你关注的人达到了里程碑"类型的推荐理由（触发式推荐）
+//
+// 触发式推荐没有相关用户：relatedCount 保存文案中的数字（这里是达到的粉丝数），
+// 这样 RestoreRecommendationReason 和配置服务的文案（count 参数）不需要新的字段。
+func NewFollowedUserMilestoneReason(milestone int) RecommendationReason {
+	return RecommendationReason{
+		reasonType:   ReasonFollowedUserMilestone,
+		relatedCount: milestone,
+	}
+}
+
+// NewFollowedUserReturnedReason 工厂方法：创建"你关注的人回来了"类型的推荐理由（触发式推荐）
+//
+// relatedCount 保存不活跃的天数（见 NewFollowedUserMilestoneReason）。
+func NewFollowedUserReturnedReason(inactiveDays int) RecommendationReason {
+	return RecommendationReason{
+		reasonType:   ReasonFollowedUserReturned,
+		relatedCount: inactiveDays,
+	}
+}
+
+// NewMissedPostsReason 工厂方法：创建"你可能错过了 TA 的新帖子"类型的推荐理由（重新互动）
+//
+// relatedCount 保存错过的帖子数（见 NewFollowedUserMilestoneReason）。
+func NewMissedPostsReason(postCount int) RecommendationReason {
+	return RecommendationReason{
+		reasonType:   ReasonMissedPosts,
+		relatedCount: postCount,
+	}
+}
+
+// NewManyMutualFollowsReason 工厂方法：创建"你们有 N 位共同好友"类型的推荐理由
+//
+// 参数和 NewSampledFollowedByFollowingReason 相同：sample 是共同好友样本，total 是共同好友总数。
+func NewManyMutualFollowsReason(sample []UserID, total int) RecommendationReason {
+	if total < len(sample) {
+		total = len(sample)
+	}
+	return RecommendationReason{
+		reasonType:   ReasonManyMutualFollows,
+		relatedUsers: sample,
+		relatedCount: total,
+	}
+}
+
+// NewPopularOnPlatformReason 工厂方法：创建"全站热门用户"类型的推荐理由（冷启动）
+//
+// 冷启动推荐没有相关用户：新用户还没有关注任何人，推荐理由和用户的社交网络无关。
+func NewPopularOnPlatformReason() RecommendationReason {
+	return RecommendationReason{reasonType: ReasonPopularOnPlatform}
+}
+
+// NewEditorPickReason 工厂方法：创建"编辑推荐"类型的推荐理由（冷启动）
+func NewEditorPickReason() RecommendationReason {
+	return RecommendationReason{reasonType: ReasonEditorPick}
+}
+
+// NewBackfillReason 工厂方法：创建"补足一页"类型的推荐理由
+//
+// 和冷启动一样没有相关用户；单独的类型让客户端、统计可以区分"个性化推荐不够，补上的热门用户"。
+func NewBackfillReason() RecommendationReason {
+	return RecommendationReason{reasonType: ReasonBackfill}
+}
+
+// RestoreRecommendationReason 从存储中恢复推荐理由（如推荐列表快照）
+//
+// 存储中只有类型和相关用户总数，没有相关用户样本：
+// 恢复的理由可以生成文案、计算权重，但不能展示相关用户头像。
+func RestoreRecommendationReason(reasonType ReasonType, relatedCount int) RecommendationReason {
+	return RecommendationReason{
+		reasonType:   reasonType,
+		relatedCount: relatedCount,
+	}
+}
+
 // NewPopularInNetworkReason 工厂方法：创建"网络中受欢迎"类型的推荐理由
 func NewPopularInNetworkReason(users []UserID) RecommendationReason {
 	return RecommendationReason{
 		reasonType:   ReasonPopularInNetwork,
 		relatedUsers: users,
+		relatedCount: len(users),
 		displayText:  "", // 使用本地逻辑生成文案
 	}
 }
@@ -92,6 +235,7 @@ func NewRecommendationReasonWithText(reasonType ReasonType, users []UserID, disp
 	return RecommendationReason{
 		reasonType:   reasonType,
 		relatedUsers: users,
+		relatedCount: len(users),
 		displayText:  displayText, // 使用后端配置的文案
 	}
 }
@@ -142,27 +286,59 @@ func NewRecommendationReasonWithText(reasonType ReasonType, users []UserID, disp
 // - 后端应该保证返回的文案不为空，否则会降级
 // - 如果需要强制使用后端文案（即使为空），可以增加一个标志位
 func (r RecommendationReason) Description() string {
+	return r.DescriptionIn(Locale{})
+}
+
+// DescriptionIn 生成指定语言的推荐理由描述（本地文案见 reasonMessages）
+//
+// 和 Description 相同，优先使用后端配置的文案（displayText 由配置服务按语言返回）。
+// 本地文案支持简体中文、英文、日文，数量按各语言的单复数规则展示
+// （"1 person you follow…" / "3 people you follow…"）；
+// 其他语言匹配最接近的支持语言（如 zh-TW → 简体中文，en-GB → 英文），都不接近时使用简体中文。
+func (r RecommendationReason) DescriptionIn(locale Locale) string {
 	// 优先使用后端配置的文案
 	if r.displayText != "" {
 		return r.displayText
 	}
 
 	// 降级到本地逻辑（兼容旧版本或后端异常）
+	printer := reasonPrinters[locale.supported()]
 	switch r.reasonType {
 	case ReasonFollowedByFollowing:
-		count := len(r.relatedUsers)
-		if count == 1 {
-			return "1 位你关注的人也关注了TA"
-		}
-		return fmt.Sprintf("%d 位你关注的人也关注了TA", count)
+		return printer.Sprintf(msgFollowedByFollowing, r.relatedCount)
 	case ReasonPopularInNetwork:
-		return "在你的社交网络中很受欢迎"
+		return printer.Sprintf(msgPopularInNetwork)
+	case ReasonTopicFollowedByFollowing:
+		return printer.Sprintf(msgTopicFollowedByFollowing, r.relatedCount)
+	case ReasonFriendsAreMembers:
+		return printer.Sprintf(msgFriendsAreMembers, r.relatedCount)
+	case ReasonFollowedUserMilestone:
+		return printer.Sprintf(msgFollowedUserMilestone, r.relatedCount)
+	case ReasonFollowedUserReturned:
+		return printer.Sprintf(msgFollowedUserReturned, r.relatedCount)
+	case ReasonMissedPosts:
+		return printer.Sprintf(msgMissedPosts, r.relatedCount)
+	case ReasonManyMutualFollows:
+		return printer.Sprintf(msgManyMutualFollows, r.relatedCount)
+	case ReasonPopularOnPlatform:
+		return printer.Sprintf(msgPopularOnPlatform)
+	case ReasonEditorPick:
+		return printer.Sprintf(msgEditorPick)
+	case ReasonBackfill:
+		return printer.Sprintf(msgBackfill)
 	default:
-		return "推荐给你"
+		return printer.Sprintf(msgDefault)
 	}
 }
 
-// RelatedUsers 访问器：获取相关用户列表
+// RelatedCount 访问器：获取相关用户总数
+//
+// 注意：RelatedUsers() 可能只是样本，计数相关的逻辑应该使用 RelatedCount()
+func (r RecommendationReason) RelatedCount() int {
+	return r.relatedCount
+}
+
+// RelatedUsers 访问器：获取相关用户列表（可能只是样本）
 func (r RecommendationReason) RelatedUsers() []UserID {
 	// 返回副本，保证不可变性
 	result := make([]UserID, len(r.relatedUsers))
@@ -175,6 +351,16 @@ func (r RecommendationReason) Type() ReasonType {
 	return r.reasonType
 }
 
+// IsColdStart 查询方法：是否是冷启动的推荐理由（全站热门、编辑推荐：没有相关用户）
+func (r RecommendationReason) IsColdStart() bool {
+	return r.reasonType == ReasonPopularOnPlatform || r.reasonType == ReasonEditorPick
+}
+
+// IsUnpersonalized 查询方法：是否是和用户的社交网络无关的推荐理由（冷启动、补足一页：没有相关用户）
+func (r RecommendationReason) IsUnpersonalized() bool {
+	return r.IsColdStart() || r.reasonType == ReasonBackfill
+}
+
 // Weight 业务规则：不同推荐理由的权重
 //
 // 这个方法展示了值对象如何参与业务计算。
@@ -201,12 +387,29 @@ func (r RecommendationReason) Type() ReasonType {
 // - 考虑关注的时间衰减
 // - 考虑用户的兴趣匹配度
 func (r RecommendationReason) Weight() int {
+	return r.WeightWith(DefaultScoringWeights())
+}
+
+// WeightWith 业务规则：按指定的打分权重计算推荐理由的权重（不同租户可以配置不同的权重）
+func (r RecommendationReason) WeightWith(weights ScoringWeights) int {
 	switch r.reasonType {
-	case ReasonFollowedByFollowing:
+	case ReasonFollowedByFollowing, ReasonTopicFollowedByFollowing, ReasonFriendsAreMembers:
 		// 关注的人越多，权重越高
-		return len(r.relatedUsers) * 10
-	case ReasonPopularInNetwork:
+		return r.relatedCount * weights.PerRelatedUser
+	case ReasonManyMutualFollows:
+		// 共同好友是双向的关系，比单向的"你关注的人也关注了TA"更可靠：每位按两倍计算
+		return r.relatedCount * weights.PerRelatedUser * 2
+	case ReasonPopularInNetwork, ReasonMissedPosts, ReasonPopularOnPlatform:
+		// 错过的帖子已经按帖子数加分（PerRecentPost），理由本身只给基础分
 		return 5
+	case ReasonEditorPick:
+		// 冷启动推荐之间比较：人工精选的排在全站热门前面
+		return 10
+	case ReasonFollowedUserMilestone:
+		// 触发式推荐之间比较：里程碑比回归更值得推送
+		return 30
+	case ReasonFollowedUserReturned:
+		return 20
 	default:
 		return 1
 	}
@@ -0,0 +1,85 @@
+package valueobject
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecommendationReason_DescriptionIn(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason RecommendationReason
+		locale string
+		want   string
+	}{
+		{
+			name:   "没有指定语言：简体中文",
+			reason: NewSampledFollowedByFollowingReason(nil, 3),
+			locale: "",
+			want:   "3 位你关注的人也关注了TA",
+		},
+		{
+			name:   "英文：单数",
+			reason: NewSampledFollowedByFollowingReason(nil, 1),
+			locale: "en-US",
+			want:   "1 person you follow also follows them",
+		},
+		{
+			name:   "英文：复数",
+			reason: NewSampledFollowedByFollowingReason(nil, 3),
+			locale: "en-GB",
+			want:   "3 people you follow also follow them",
+		},
+		{
+			name:   "Accept-Language 格式：取权重最高的语言",
+			reason: NewMissedPostsReason(2),
+			locale: "ja;q=0.5,en;q=0.9",
+			want:   "You may have missed 2 new posts from them",
+		},
+		{
+			name:   "日文",
+			reason: NewManyMutualFollowsReason(nil, 5),
+			locale: "ja-JP",
+			want:   "共通の友達が5人います",
+		},
+		{
+			name:   "繁体中文：匹配简体中文",
+			reason: NewPopularInNetworkReason(nil),
+			locale: "zh-TW",
+			want:   "在你的社交网络中很受欢迎",
+		},
+		{
+			name:   "不支持的语言：简体中文",
+			reason: NewFollowedUserReturnedReason(30),
+			locale: "ko-KR",
+			want:   "你关注的TA时隔 30 天回来了",
+		},
+		{
+			name:   "后端文案优先",
+			reason: NewRecommendationReasonWithText(ReasonPopularInNetwork, nil, "配置的文案"),
+			locale: "en-US",
+			want:   "配置的文案",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reason.DescriptionIn(ParseLocale(tt.locale)); got != tt.want {
+				t.Errorf("DescriptionIn(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	ctx := WithLocale(context.Background(), ParseLocale("en-US,en;q=0.9"))
+	if got := LocaleFromContext(ctx).String(); got != "en-US" {
+		t.Errorf("LocaleFromContext() = %q, want %q", got, "en-US")
+	}
+	if got := LocaleFromContext(context.Background()); !got.IsZero() {
+		t.Errorf("LocaleFromContext() = %q, want zero", got)
+	}
+	if got := ParseLocale("not a locale!"); !got.IsZero() {
+		t.Errorf("ParseLocale() = %q, want zero", got)
+	}
+}
@@ -0,0 +1,78 @@
+package valueobject
+
+// RiskLevel 值对象：账号风险等级
+//
+// 风险等级来自信任与安全（Trust & Safety）系统，
+// 用于识别垃圾账号、机器人账号等异常账号。
+//
+// 为什么是值对象？
+// - 风险等级没有唯一标识，只有"值"
+// - 等级之间有明确的顺序（可以比较高低）
+// - 解析、比较等规则集中在这里，避免到处写字符串判断
+//
+// 等级顺序：
+//
+//	RiskUnknown < RiskLow < RiskMedium < RiskHigh < RiskBanned
+//
+// 实际场景：
+// 风控系统标记某个账号为 high（疑似机器人），
+// 推荐生成时该账号不应该被推荐，也不应该作为推荐信号来源。
+type RiskLevel int
+
+const (
+	// RiskUnknown 未知（风控系统没有该用户的数据）
+	RiskUnknown RiskLevel = iota
+	// RiskLow 低风险：正常用户
+	RiskLow
+	// RiskMedium 中风险：有可疑行为，但未确认
+	RiskMedium
+	// RiskHigh 高风险：疑似垃圾/机器人账号
+	RiskHigh
+	// RiskBanned 已封禁
+	RiskBanned
+)
+
+// ParseRiskLevel 工厂方法：从风控系统的字符串标识解析风险等级
+//
+// 无法识别的标识统一视为 RiskUnknown（容错：不因为风控系统新增等级而报错）
+func ParseRiskLevel(value string) RiskLevel {
+	switch value {
+	case "low":
+		return RiskLow
+	case "medium":
+		return RiskMedium
+	case "high":
+		return RiskHigh
+	case "banned":
+		return RiskBanned
+	default:
+		return RiskUnknown
+	}
+}
+
+// Exceeds 业务规则：风险等级是否超过阈值
+//
+// 使用示例：
+//
+//	level := ParseRiskLevel("high")
+//	level.Exceeds(RiskMedium) // true，需要排除
+//	level.Exceeds(RiskHigh)   // false
+func (r RiskLevel) Exceeds(threshold RiskLevel) bool {
+	return r > threshold
+}
+
+// String 实现 Stringer 接口，方便日志和指标标签输出
+func (r RiskLevel) String() string {
+	switch r {
+	case RiskLow:
+		return "low"
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	case RiskBanned:
+		return "banned"
+	default:
+		return "unknown"
+	}
+}
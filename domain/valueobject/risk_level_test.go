@@ -0,0 +1,27 @@
+package valueobject
+
+import "testing"
+
+func TestRiskLevel_ParseAndExceeds(t *testing.T) {
+	tests := []struct {
+		value         string
+		want          RiskLevel
+		exceedsMedium bool
+	}{
+		{"low", RiskLow, false},
+		{"medium", RiskMedium, false},
+		{"high", RiskHigh, true},
+		{"banned", RiskBanned, true},
+		{"quarantined", RiskUnknown, false}, // 风控系统新增的等级：按未知处理
+		{"", RiskUnknown, false},
+	}
+	for _, tt := range tests {
+		level := ParseRiskLevel(tt.value)
+		if level != tt.want {
+			t.Errorf("ParseRiskLevel(%q) = %v, want %v", tt.value, level, tt.want)
+		}
+		if got := level.Exceeds(RiskMedium); got != tt.exceedsMedium {
+			t.Errorf("%v.Exceeds(medium) = %v, want %v", level, got, tt.exceedsMedium)
+		}
+	}
+}
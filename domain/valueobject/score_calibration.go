@@ -0,0 +1,93 @@
+package valueobject
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidCalibration 校准参数不合法
+var ErrInvalidCalibration = errors.New("invalid score calibration")
+
+// CalibrationPoint 分段线性校准的一个点：原始分数 → 校准后的分数
+type CalibrationPoint struct {
+	Raw        int
+	Calibrated float64
+}
+
+// ScoreCalibration 值对象：分数校准（原始分数 → 0~1 之间的校准分数）
+//
+// 为什么需要？
+// 原始分数是打分规则的产物：不同推荐策略（理由类型）的分数尺度不同，
+// 调整权重后同一个用户的分数也会变化。
+// 校准把原始分数映射为"被关注的概率"这样有统一含义的值，
+// 不同策略之间、不同时间之间的分数才可以比较。
+//
+// 两种映射：
+// - 分段线性：按离线统计的若干个点插值（区间外取两端的值）
+// - Platt：calibrated = 1 / (1 + exp(A × raw + B))（A 通常为负数，分数越高概率越大）
+//
+// 零值表示不校准（IsZero），由调用方决定怎么处理。
+type ScoreCalibration struct {
+	points []CalibrationPoint // 分段线性（按 Raw 升序）
+	plattA float64
+	plattB float64
+	platt  bool
+}
+
+// NewPiecewiseCalibration 工厂方法：分段线性校准
+//
+// 规则：
+// - 至少 2 个点，原始分数不能重复
+// - 校准后的分数在 0~1 之间，并且随原始分数单调不减（分数高的不能校准得更低）
+func NewPiecewiseCalibration(points []CalibrationPoint) (ScoreCalibration, error) {
+	if len(points) < 2 {
+		return ScoreCalibration{}, ErrInvalidCalibration
+	}
+	sorted := append([]CalibrationPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Raw < sorted[j].Raw })
+	for i, point := range sorted {
+		if point.Calibrated < 0 || point.Calibrated > 1 || math.IsNaN(point.Calibrated) {
+			return ScoreCalibration{}, ErrInvalidCalibration
+		}
+		if i > 0 && (point.Raw == sorted[i-1].Raw || point.Calibrated < sorted[i-1].Calibrated) {
+			return ScoreCalibration{}, ErrInvalidCalibration
+		}
+	}
+	return ScoreCalibration{points: sorted}, nil
+}
+
+// NewPlattCalibration 工厂方法：Platt 校准（A 不能为 0，否则所有分数都校准成同一个值）
+func NewPlattCalibration(a, b float64) (ScoreCalibration, error) {
+	if a == 0 || math.IsNaN(a) || math.IsInf(a, 0) || math.IsNaN(b) || math.IsInf(b, 0) {
+		return ScoreCalibration{}, ErrInvalidCalibration
+	}
+	return ScoreCalibration{plattA: a, plattB: b, platt: true}, nil
+}
+
+// IsZero 是否为零值（不校准）
+func (c ScoreCalibration) IsZero() bool {
+	return !c.platt && len(c.points) == 0
+}
+
+// Calibrate 业务规则：原始分数 → 校准后的分数（零值返回 0）
+func (c ScoreCalibration) Calibrate(raw int) float64 {
+	if c.platt {
+		return 1 / (1 + math.Exp(c.plattA*float64(raw)+c.plattB))
+	}
+	if len(c.points) == 0 {
+		return 0
+	}
+	first, last := c.points[0], c.points[len(c.points)-1]
+	if raw <= first.Raw {
+		return first.Calibrated
+	}
+	if raw >= last.Raw {
+		return last.Calibrated
+	}
+	// 第一个原始分数不小于 raw 的点，和它前面的点之间线性插值
+	i := sort.Search(len(c.points), func(i int) bool { return c.points[i].Raw >= raw })
+	lo, hi := c.points[i-1], c.points[i]
+	ratio := float64(raw-lo.Raw) / float64(hi.Raw-lo.Raw)
+	return lo.Calibrated + ratio*(hi.Calibrated-lo.Calibrated)
+}
@@ -0,0 +1,17 @@
+package valueobject
+
+// ScoringWeights 值对象：推荐打分权重
+//
+// 推荐分数 = 推荐信号来源数 × PerRelatedUser + 最近帖子数 × PerRecentPost
+// + 最近 N 天收到的互动分 / 100 × PerHundredEngagement。
+// 不同租户（App 品牌）的产品策略不同，可以配置不同的权重。
+type ScoringWeights struct {
+	PerRelatedUser       int // 每个推荐信号来源（"你关注的人"）的分数
+	PerRecentPost        int // 每个最近帖子的分数
+	PerHundredEngagement int // 每 100 个互动分（收到的点赞 1 分、评论 3 分）的分数，为 0 时不加分
+}
+
+// DefaultScoringWeights 默认权重：每个推荐信号来源 10 分，每个最近帖子 2 分，每 100 个互动分 5 分
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{PerRelatedUser: 10, PerRecentPost: 2, PerHundredEngagement: 5}
+}
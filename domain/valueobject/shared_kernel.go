@@ -0,0 +1,43 @@
+package valueobject
+
+import (
+	"context"
+
+	"service/internal/sharedkernel"
+)
+
+// 共享内核中的值对象（定义见 internal/sharedkernel）
+//
+// 推荐领域使用类型别名：valueobject.UserID 和 sharedkernel.UserID 是同一个类型，
+// 其他上下文（内容集成、用户服务防腐层）直接依赖共享内核，不需要依赖推荐领域。
+type (
+	UserID   = sharedkernel.UserID
+	PostID   = sharedkernel.PostID
+	TenantID = sharedkernel.TenantID
+)
+
+// DefaultTenantID 默认租户
+const DefaultTenantID = sharedkernel.DefaultTenantID
+
+var (
+	ErrInvalidUserID   = sharedkernel.ErrInvalidUserID
+	ErrInvalidPostID   = sharedkernel.ErrInvalidPostID
+	ErrInvalidTenantID = sharedkernel.ErrInvalidTenantID
+)
+
+// NewUserID 见 sharedkernel.NewUserID
+func NewUserID(value int64) (UserID, error) { return sharedkernel.NewUserID(value) }
+
+// NewPostID 见 sharedkernel.NewPostID
+func NewPostID(value int64) (PostID, error) { return sharedkernel.NewPostID(value) }
+
+// NewTenantID 见 sharedkernel.NewTenantID
+func NewTenantID(value string) (TenantID, error) { return sharedkernel.NewTenantID(value) }
+
+// WithTenant 见 sharedkernel.WithTenant
+func WithTenant(ctx context.Context, tenant TenantID) context.Context {
+	return sharedkernel.WithTenant(ctx, tenant)
+}
+
+// TenantFromContext 见 sharedkernel.TenantFromContext
+func TenantFromContext(ctx context.Context) TenantID { return sharedkernel.TenantFromContext(ctx) }
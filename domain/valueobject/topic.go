@@ -0,0 +1,61 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidTopic = errors.New("话题名称不能为空、不能包含空白字符，且不能超过64个字符")
+
+// maxTopicLength 话题名称的最大长度（字符数，不含 #）
+const maxTopicLength = 64
+
+// Topic 值对象：话题（hashtag）
+//
+// 为什么话题是值对象？
+// 话题没有自己的生命周期：#Golang 和 #golang 是同一个话题，
+// 两个名称相同的话题就是相等的，不需要单独的ID。
+//
+// 业务规则：
+// - 名称规范化：去掉开头的 #，统一为小写（大小写不同的写法是同一个话题）
+// - 不能为空、不能包含空白字符，最长 64 个字符
+//
+// 使用示例：
+//
+//	topic, err := NewTopic("#Golang")
+//	topic.Name()    // "golang"
+//	topic.Hashtag() // "#golang"
+type Topic struct {
+	name string
+}
+
+// NewTopic 工厂方法：从用户输入或存储中的名称创建话题（执行规范化和验证）
+func NewTopic(raw string) (Topic, error) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "#"))
+	if name == "" || utf8.RuneCountInString(name) > maxTopicLength || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return Topic{}, ErrInvalidTopic
+	}
+	return Topic{name: name}, nil
+}
+
+// Name 访问器：规范化后的名称（不含 #）
+func (t Topic) Name() string {
+	return t.name
+}
+
+// Hashtag 展示用的形式（如 #golang）
+func (t Topic) Hashtag() string {
+	return "#" + t.name
+}
+
+// Equals 值对象相等性比较
+func (t Topic) Equals(other Topic) bool {
+	return t.name == other.name
+}
+
+// String 实现 Stringer 接口
+func (t Topic) String() string {
+	return t.name
+}
@@ -0,0 +1,307 @@
+//go:build fx
+
+package main
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"service/application/service"
+	domainRepository "service/domain/repository"
+	domainService "service/domain/service"
+	"service/infrastructure/lifecycle"
+	"service/infrastructure/messaging"
+	"service/interface/handler"
+	"service/interface/job"
+)
+
+// Fx 组装（可选的组合根）
+//
+// 和 wire.go 是同一套 Provider（providers.go），只是换了组装方式：
+// - Wire：编译期生成代码，main.go 手动启动服务
+// - Fx：运行时用反射解析依赖，服务启动、停止通过生命周期钩子（fx.Lifecycle）管理
+//
+// 选择方式（构建标签）：
+//
+//	go build .            # Wire（默认）
+//	go build -tags fx .   # Fx，入口在 main_fx.go
+//
+// 模块按层划分，和 wire.go 的 ProviderSet 一一对应：
+//
+//	configSet + infrastructureSet → infrastructureModule
+//	repositorySet         → repositoryModule
+//	domainServiceSet      → domainModule
+//	applicationServiceSet → applicationModule
+//	handlerSet + adminHandlerSet + serverSet → handlerModule
+//	retentionJobSet + reencryptionJobSet + followEventSet + precomputeSet → jobModule
+//
+// 和 Wire 的一个区别：Fx 只有一个依赖图，
+// 推荐链路、清理任务共用同一个仓储实例（Wire 的每个 Injector 各自构造一份；管理接口和推荐链路在同一个 Injector 里）。
+
+// infrastructureModule 基础设施层
+var infrastructureModule = fx.Module("infrastructure",
+	fx.Provide(
+		lifecycle.NewClosers,
+		provideConfig,
+		providePrometheusMetrics,
+		provideMetrics,
+		provideTenants,
+		provideDownstreamGovernor,
+		provideMTLS,
+		provideDatabase,
+		provideTransactionManager,
+		provideRedis,
+		provideServiceRegistry,
+		provideUserRPCClient,
+		provideContentServiceClient,
+		provideReasonConfigClient,
+		provideSnapshotStore,
+		provideRecommendationListCache,
+		provideTriggerRecommendationStore,
+		provideSessionStore,
+		provideEventPublisher,
+		provideSecretsProvider,
+		provideFieldCodec,
+		provideRedactor,
+		provideLogger,
+		provideTracer,
+	),
+	fx.Invoke(registerClosers),
+)
+
+// repositoryModule 仓储层
+var repositoryModule = fx.Module("repository",
+	fx.Provide(
+		provideSocialGraphRepository,
+		provideContentRepository,
+		provideTrustRepository,
+		provideEngagementSignalRepository,
+		provideImpressionRepository,
+		provideConversionRepository,
+		provideFeedbackRepository,
+		provideExperimentAssignmentRepository,
+		provideStrategyBanditRepository,
+		provideUserErasureRepository,
+		provideRecentFollowIndex,
+		provideAuditLogRepository,
+		provideTopicRepository,
+		provideGroupMembershipRepository,
+		provideFollowerRepository,
+		provideInteractionRepository,
+		providePopularUsersRepository,
+		provideBlockRepository,
+		provideRecommendationRepository,
+		provideActiveUsersRepository,
+	),
+)
+
+// domainModule 领域服务层
+var domainModule = fx.Module("domain",
+	fx.Provide(
+		provideCandidateRegistry,
+		provideGeneratorOptions,
+		newRecommendationGenerator,
+		domainService.NewTopicRecommender,
+		domainService.NewGroupRecommender,
+	),
+)
+
+// applicationModule 应用服务层
+var applicationModule = fx.Module("application",
+	fx.Provide(
+		provideSurfacePolicies,
+		provideExperimentAssigner,
+		provideRecommendationServiceOptions,
+		newRecommendationService,
+		provideUserCacheInvalidators,
+		service.NewUserDataService,
+		provideAttributionService,
+		provideFollowService,
+		service.NewTopicRecommendationService,
+		service.NewGroupRecommendationService,
+		service.NewTriggerRecommendationService,
+		service.NewAuditLogService,
+		provideStrategyBandit,
+		provideWarmup,
+	),
+	fx.Invoke(registerWarmup),
+)
+
+// handlerModule 接口层
+var handlerModule = fx.Module("handler",
+	fx.Provide(
+		provideAuthorizer,
+		providePriorityTiers,
+		provideTrafficCapture,
+		provideAbuseDetector,
+		provideLoadShedder,
+		provideHandlerOptions,
+		newRecommendationHandler,
+		provideDependencyStatusService,
+		provideFeedbackAnalyticsService,
+		handler.NewAdminHandler,
+		handler.NewTenantResolver,
+		provideServerSettings,
+		provideHTTPServer,
+		handler.NewRPCMetrics,
+		provideIdentityVerifier,
+		provideAuthenticator,
+	),
+)
+
+// jobModule 后台任务
+var jobModule = fx.Module("job",
+	fx.Provide(
+		provideRetentionEnforcer,
+		provideRetentionJob,
+		provideReencryptionJob,
+		provideFollowEventHandler,
+		provideFollowEventConsumer,
+		providePrecomputeJob,
+	),
+	fx.Invoke(registerRetentionJob, registerReencryptionJob, registerFollowEventConsumer, registerPrecomputeJob),
+)
+
+// 可变参数的构造函数
+//
+// Fx 不会为可变参数（...Option）注入依赖，
+// 这里包装成接收切片的函数，对应 Wire 中把 []Option 当作普通依赖的做法。
+
+func newRecommendationGenerator(
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	contentRepo domainRepository.ContentRepository,
+	opts []domainService.GeneratorOption,
+) *domainService.RecommendationGenerator {
+	return domainService.NewRecommendationGenerator(socialGraphRepo, contentRepo, opts...)
+}
+
+func newRecommendationService(
+	generator *domainService.RecommendationGenerator,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	contentRepo domainRepository.ContentRepository,
+	contentClient service.ContentServiceClient,
+	userRPCClient service.UserRPCClient,
+	reasonConfigClient service.ReasonTextConfigClient,
+	opts []service.RecommendationServiceOption,
+) *service.RecommendationService {
+	return service.NewRecommendationService(
+		generator,
+		socialGraphRepo,
+		contentRepo,
+		contentClient,
+		userRPCClient,
+		reasonConfigClient,
+		opts...,
+	)
+}
+
+func newRecommendationHandler(
+	recommendationService *service.RecommendationService,
+	userDataService *service.UserDataService,
+	topicService *service.TopicRecommendationService,
+	groupService *service.GroupRecommendationService,
+	triggerService *service.TriggerRecommendationService,
+	authorizer *service.Authorizer,
+	opts []handler.HandlerOption,
+) *handler.RecommendationHandler {
+	return handler.NewRecommendationHandler(recommendationService, userDataService, topicService, groupService, triggerService, authorizer, opts...)
+}
+
+// 生命周期钩子
+
+// registerClosers 停止时释放 Provider 登记的资源（数据库、Redis、Vault 租约续期、策略权重 bandit 等）
+//
+// 基础设施模块最先注册，OnStop 逆序执行：这个钩子最后执行，
+// 服务停止、后台任务结束之后才关闭连接池。
+func registerClosers(lc fx.Lifecycle, closers *lifecycle.Closers) {
+	lc.Append(fx.StopHook(closers.Close))
+}
+
+// registerWarmup 启动时执行预热（必需步骤失败时启动失败）
+//
+// applicationModule 在 registerServer 之前注册，OnStart 按注册顺序执行：
+// 预热完成之后才开始监听端口。
+func registerWarmup(lc fx.Lifecycle, warmup *service.Warmup) {
+	lc.Append(fx.StartHook(warmup.Run))
+}
+
+// registerRetentionJob 启动时开始数据保留期清理，停止时结束
+func registerRetentionJob(lc fx.Lifecycle, retentionJob *job.RetentionJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go retentionJob.Run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// registerReencryptionJob 启动时开始重新加密，停止时结束（没有开启加密时 job 为 nil，不注册）
+func registerReencryptionJob(lc fx.Lifecycle, reencryptionJob *job.ReencryptionJob) {
+	if reencryptionJob == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go reencryptionJob.Run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// registerFollowEventConsumer 启动时开始消费关注事件，停止时等正在处理的事件结束
+//
+// 没有配置要消费的 topic / Stream（follow_events.topic、follow_events.stream）时 consumer 为 nil，不注册。
+func registerFollowEventConsumer(lc fx.Lifecycle, consumer *messaging.Consumer) {
+	if consumer == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				consumer.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+// registerPrecomputeJob 启动时开始预计算，停止时结束（precompute.enabled 为 false 时 job 为 nil，不注册）
+func registerPrecomputeJob(lc fx.Lifecycle, precomputeJob *job.PrecomputeJob) {
+	if precomputeJob == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go precomputeJob.Run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,129 @@
+//go:build fx
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"service/infrastructure/lifecycle"
+	"service/infrastructure/messaging"
+	httpapi "service/interface/http"
+)
+
+// 依赖图完整：每个构造函数、钩子的参数都有 Provider，没有重复提供的类型（不执行构造函数）
+func TestFxApp_Validates(t *testing.T) {
+	err := fx.ValidateApp(
+		infrastructureModule,
+		repositoryModule,
+		domainModule,
+		applicationModule,
+		handlerModule,
+		jobModule,
+		fx.Invoke(registerServer, registerAdminServer, registerHTTPServer),
+	)
+	if err != nil {
+		t.Fatalf("fx.ValidateApp() = %v", err)
+	}
+}
+
+// 停止时才释放 Provider 登记的资源
+func TestRegisterClosers_ClosesOnStop(t *testing.T) {
+	closers := lifecycle.NewClosers()
+	closed := false
+	closers.AddFunc("db", func() { closed = true })
+
+	lc := fxtest.NewLifecycle(t)
+	registerClosers(lc, closers)
+	lc.RequireStart()
+	if closed {
+		t.Fatal("resources closed on start")
+	}
+	lc.RequireStop()
+	if !closed {
+		t.Error("resources not closed on stop")
+	}
+}
+
+// blockingReader 测试用消息队列：没有消息，Fetch 阻塞到 ctx 取消
+type blockingReader struct {
+	fetching chan struct{}
+	canceled bool
+}
+
+func (r *blockingReader) Fetch(ctx context.Context) ([]messaging.Message, error) {
+	close(r.fetching)
+	<-ctx.Done()
+	r.canceled = true
+	return nil, ctx.Err()
+}
+
+func (r *blockingReader) Ack(ctx context.Context, msg messaging.Message) error {
+	return nil
+}
+
+// 停止时取消消费，等 Run 返回之后 OnStop 才结束；没有配置关注事件时不注册钩子
+func TestRegisterFollowEventConsumer(t *testing.T) {
+	lc := fxtest.NewLifecycle(t)
+	registerFollowEventConsumer(lc, nil)
+	lc.RequireStart().RequireStop()
+
+	reader := &blockingReader{fetching: make(chan struct{})}
+	consumer := messaging.NewConsumer("follow_events", reader, func(ctx context.Context, payload []byte) error {
+		return nil
+	}, messaging.ConsumerConfig{})
+
+	lc = fxtest.NewLifecycle(t)
+	registerFollowEventConsumer(lc, consumer)
+	lc.RequireStart()
+	select {
+	case <-reader.fetching:
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumer not started")
+	}
+	lc.RequireStop()
+	if !reader.canceled {
+		t.Error("OnStop returned before the consumer stopped")
+	}
+}
+
+// fakeShutdowner 测试用 fx.Shutdowner：记录是否请求了退出
+type fakeShutdowner struct {
+	calls chan struct{}
+}
+
+func (s *fakeShutdowner) Shutdown(...fx.ShutdownOption) error {
+	s.calls <- struct{}{}
+	return nil
+}
+
+// HTTP 接口监听失败时让整个应用退出，而不是只剩 Kitex 服务在运行
+func TestRegisterHTTPServer_ListenFailureShutsDownApp(t *testing.T) {
+	shutdowner := &fakeShutdowner{calls: make(chan struct{}, 1)}
+
+	lc := fxtest.NewLifecycle(t)
+	registerHTTPServer(lc, shutdowner, nil)
+	lc.RequireStart().RequireStop()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	server := httpapi.NewServer(ln.Addr().String(), nil, nil, nil, nil)
+
+	lc = fxtest.NewLifecycle(t)
+	registerHTTPServer(lc, shutdowner, server)
+	lc.RequireStart()
+	select {
+	case <-shutdowner.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("app not shut down after the HTTP listener failed")
+	}
+	lc.RequireStop()
+}
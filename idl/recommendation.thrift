@@ -4,12 +4,54 @@ namespace go recommendation
 struct GetRecommendationsRequest {
     1: required i64 user_id,  // 用户ID
     2: optional i32 limit = 10,  // 返回数量限制
-    3: optional i32 day = 7, // 时间范围 (7 天)
+    3: optional i32 day,  // 推荐信号的时间窗口（最近几天，1~30），不传时使用展示场景的默认值（onboarding 30 天、push 2 天，其余 7 天）
+    4: optional string surface,  // 展示场景：home_feed / profile_sidebar / onboarding / push
+    5: optional string known_version,  // 客户端缓存的列表版本（上次响应的 version）；没有变化时返回 not_modified
+    6: optional string since_version,  // 增量同步：上次同步拿到的 version，只返回之后新增、变化的推荐和墓碑
+    7: optional i64 since_timestamp,  // 增量同步：上次同步的时间（Unix 秒），没有 since_version 时使用
+    8: optional string session_id,  // 客户端会话ID（最长 64 个字符）：同一个会话内翻页、重复请求不再返回已经返回过的推荐
+    9: optional string timezone,  // 客户端时区（IANA 名称，如 Asia/Shanghai），用于帖子的 created_at_display
+    10: optional RecommendationFilter filter,  // 调用方指定的过滤条件（BFF 实验用）；指定后不使用快照、不支持增量同步
+    11: optional string page_token,  // 分页：上一页响应的 next_cursor（翻页时其他参数保持不变）
+    12: optional i32 page_size,  // 分页：每页数量（大于 0 或带 page_token 时按页返回，代替 limit）；分页响应不带 version，不支持增量同步
+    13: optional bool debug,  // 调试请求（需要质量评审权限）：每条推荐带上 score_details；总是重新生成，不使用快照
+}
+
+// 请求级别的过滤条件（各条件同时满足才返回）
+struct RecommendationFilter {
+    1: optional i32 min_score,  // 最低分数（不传或 0 表示不限制）
+    2: optional list<string> reason_types,  // 只返回这些理由类型（如 followed_by_following、popular_in_network），最多 10 个
+    3: optional list<i64> exclude_user_ids,  // 不推荐这些用户，最多 500 个
 }
 
 // 推荐响应
 struct GetRecommendationsResponse {
     1: required list<UserRecommendation> recommendations,
+    2: optional bool degraded,  // 降级响应：部分推荐因为超时没有返回
+    3: optional ResponseMetadata metadata,  // 生成过程的元数据
+    4: optional string version,  // 列表版本（相当于 HTTP 的 ETag）；降级响应不带版本
+    5: optional bool not_modified,  // 列表和 known_version 相同：recommendations 为空，客户端继续使用缓存
+    6: optional bool delta,  // 增量响应：recommendations 只有新增、变化的推荐；为 false 时是完整列表，客户端整体替换
+    7: optional list<Tombstone> tombstones,  // 增量响应：需要从客户端缓存删除的推荐
+    8: optional bool precomputed,  // 列表来自最近的快照，没有重新生成（过载或快照还在有效期内）
+    9: optional string next_cursor,  // 分页：下一页的 page_token（没有更多时为空）
+    10: optional bool has_more,  // 分页：是否还有下一页
+}
+
+// 墓碑：客户端缓存中需要删除的推荐
+struct Tombstone {
+    1: required i64 user_id,
+    2: required string reason,  // removed（不再被推荐）/ expired（推荐已过期）
+}
+
+// 推荐生成过程的元数据（用于评估推荐质量）
+struct ResponseMetadata {
+    1: optional bool followings_sampled,  // 是否对关注列表做了采样
+    2: optional i32 followings_total,  // 关注的人总数
+    3: optional i32 followings_used,  // 实际用作推荐信号来源的人数
+    4: optional string calibration_version,  // 分数校准参数的版本（没有开启校准时为空）
+    5: optional string experiment,  // 用户所在的 A/B 实验（不在实验中时为空，客户端埋点时带上）
+    6: optional string variant,  // 用户在实验中的分组
 }
 
 // 用户推荐
@@ -21,13 +63,281 @@ struct UserRecommendation {
     5: required string reason,  // 推荐理由
     6: required i32 score,  // 推荐分数
     7: required list<Post> recent_posts,  // 最近的帖子
+    8: optional double calibrated_score,  // 校准后的分数（0~1，不同策略、不同时间之间可比较）
+    9: optional bool profile_pending,  // 资料没有取到（username、avatar 为空），客户端应按 user_id 刷新资料
+    10: optional string status,  // ok / degraded
+    11: optional list<string> degraded,  // 降级的部分：profile（资料是占位的）、posts（帖子没有取到）
+    12: optional ScoreDetails score_details,  // 分数明细（只有调试请求才有）
+    13: optional string reason_type,  // 推荐理由类型（如 followed_by_following；backfill 表示个性化推荐不够一页时补上的热门用户）
+}
+
+// 一条推荐的分数明细：各因素的贡献之和等于 score
+struct ScoreDetails {
+    1: required list<ScoreFactor> factors,
+    2: required string rules_version,  // 打分规则版本
+    3: required i32 per_related_user,  // 打分权重（按权重打分时；其他打分策略为 0）
+    4: required i32 per_recent_post,
+    5: required i32 per_hundred_engagement,
 }
 
 // 帖子
 struct Post {
     1: required i64 post_id,
     2: required string content,
-    3: required string created_at,
+    3: required string created_at,  // 兼容字段：旧格式 "2006-01-02 15:04:05"（服务端时区），配置 post_timestamps.omit_legacy 后为空
+    4: optional bool masked,  // 内容被遮挡（敏感内容）
+    5: optional string created_at_rfc3339,  // 发布时间（RFC3339，UTC），新客户端使用这个字段
+    6: optional string created_at_display,  // 按请求的 timezone 格式化的展示时间（"2006-01-02 15:04"），没有传 timezone 时为空
+}
+
+// 用户数据删除请求（内部接口，只供隐私团队调用）
+struct DeleteUserDataRequest {
+    1: required i64 user_id,
+    2: optional string request_id,  // 隐私团队的删除工单号（用于对账）
+}
+
+// 用户数据删除响应
+struct DeleteUserDataResponse {
+    1: required i64 user_id,
+    2: optional string request_id,
+}
+
+// 用户数据导出请求（内部接口，只供隐私团队调用）
+struct ExportUserDataRequest {
+    1: required i64 user_id,
+    2: optional string request_id,  // 隐私团队的访问请求工单号
+}
+
+// 用户数据导出响应
+struct ExportUserDataResponse {
+    1: required i64 user_id,
+    2: optional string request_id,
+    3: required string data,  // JSON 格式的导出数据
+}
+
+// 推荐历史查询请求
+struct GetRecommendationHistoryRequest {
+    1: required i64 user_id,
+    2: optional i64 from,  // 起始时间（Unix 秒，包含）；不传时不限制
+    3: optional i64 to,  // 结束时间（Unix 秒，不包含）；不传时为当前时间
+    4: optional i32 limit = 20,  // 每页条数（最多 100）
+    5: optional string page_token,  // 上一页响应的 next_page_token
+}
+
+// 推荐历史查询响应
+struct GetRecommendationHistoryResponse {
+    1: required list<RecommendationHistoryItem> items,  // 最近展示的在前
+    2: optional string next_page_token,  // 为空表示没有更多
+}
+
+// 一次展示过的推荐
+struct RecommendationHistoryItem {
+    1: required string recommendation_id,
+    2: required i64 user_id,  // 被推荐的用户
+    3: optional string username,  // 资料获取失败时为空
+    4: optional string avatar,
+    5: optional string surface,  // 展示场景
+    6: required string shown_at,  // RFC3339
+}
+
+// 帖子推荐请求（探索页）
+struct GetSuggestedPostsRequest {
+    1: required i64 user_id,
+    2: optional i32 limit = 20,  // 返回数量（最多 50）
+    3: optional string surface,  // 展示场景，不传时为 explore
+    4: optional string timezone,  // 客户端时区（IANA 名称），用于帖子的 created_at_display
+}
+
+// 帖子推荐响应
+struct GetSuggestedPostsResponse {
+    1: required list<SuggestedPost> posts,  // 按推荐分数降序
+}
+
+// 推荐的帖子
+struct SuggestedPost {
+    1: required string recommendation_id,
+    2: required i64 post_id,
+    3: required i64 author_id,
+    4: optional string author_username,  // 资料获取失败时为空
+    5: optional string author_avatar,
+    6: required string content,
+    7: required string created_at,  // 兼容字段，同 Post.created_at
+    8: optional bool masked,  // 内容被遮挡（敏感内容）
+    9: required string reason,  // 推荐理由（关于作者）
+    10: required i32 score,
+    11: optional string created_at_rfc3339,  // 发布时间（RFC3339，UTC）
+    12: optional string created_at_display,  // 按请求的 timezone 格式化的展示时间
+}
+
+// 话题推荐请求
+struct GetTopicRecommendationsRequest {
+    1: required i64 user_id,
+    2: optional i32 limit = 10,  // 返回数量（最多 50）
+}
+
+// 话题推荐响应
+struct GetTopicRecommendationsResponse {
+    1: required list<TopicRecommendation> topics,  // 按推荐分数降序
+}
+
+// 推荐的话题
+struct TopicRecommendation {
+    1: required string recommendation_id,
+    2: required string topic,  // 规范化后的名称（小写、不含 #）
+    3: required string hashtag,  // 展示用的形式，如 #golang
+    4: required string reason,  // 推荐理由
+    5: required i32 score,
+}
+
+// 小组推荐请求
+struct GetGroupRecommendationsRequest {
+    1: required i64 user_id,
+    2: optional i32 limit = 10,  // 返回数量（最多 50）
+}
+
+// 小组推荐响应
+struct GetGroupRecommendationsResponse {
+    1: required list<GroupRecommendation> groups,  // 按推荐分数降序
+}
+
+// 推荐的小组（名称、封面等由客户端向社区服务获取）
+struct GroupRecommendation {
+    1: required string recommendation_id,
+    2: required i64 group_id,
+    3: optional i32 member_count,  // 成员总数（未知时为 0）
+    4: required string reason,  // 推荐理由
+    5: required i32 score,
+}
+
+// 分栏推荐请求（发现页）
+struct GetRecommendationBundleRequest {
+    1: required i64 user_id,
+}
+
+// 分栏推荐响应
+struct GetRecommendationBundleResponse {
+    1: required list<RecommendationSection> sections,  // 按展示顺序，没有推荐的分栏不返回
+    2: optional bool degraded,  // 部分推荐的用户信息获取超时被省略
+}
+
+// 发现页的一个分栏（如"你可能认识的人"、"热门创作者"）
+struct RecommendationSection {
+    1: required string key,  // 分栏标识，如 people_you_may_know
+    2: required string title,  // 分栏标题（配置服务按语言提供）
+    3: required list<UserRecommendation> recommendations,  // 按推荐分数降序
+}
+
+// 触发式推荐请求（推送管道）
+struct GetTriggerRecommendationsRequest {
+    1: required i64 user_id,
+}
+
+// 触发式推荐响应
+struct GetTriggerRecommendationsResponse {
+    1: required list<TriggerRecommendation> recommendations,  // 最多 3 条，按推荐分数降序
+}
+
+// 触发式推荐（推荐对象是用户已经关注的人）
+struct TriggerRecommendation {
+    1: required i64 user_id,
+    2: required string trigger,  // milestone / returned
+    3: required string reason,  // 推荐理由，如"你关注的TA粉丝突破了 10000"
+    4: required i32 score,
+    5: required i64 expires_at,  // 过期时间（Unix 秒），过期后不应再推送
+}
+
+// 推荐解释请求（内部接口，只供质量评审工具调用）
+struct ExplainRecommendationRequest {
+    1: required i64 viewer_id,  // 看到推荐的用户
+    2: required i64 target_user_id,  // 被推荐的用户
+    3: optional string surface,  // 展示场景，为空表示默认
+}
+
+// 推荐解释响应（推荐是重新生成的，关注关系等变化之后可能和当时展示的不同）
+struct ExplainRecommendationResponse {
+    1: required bool found,  // 重新生成的候选中有没有这个人，为 false 时打分明细为空
+    2: optional string reason_type,
+    3: required i32 position,  // 排序后的位置（从 0 开始，曝光配额之前），没有找到时为 -1
+    4: required i32 candidates,  // 候选总数
+    5: required i32 score,
+    6: required list<ScoreFactor> factors,  // 各打分因素的贡献，加起来等于 score
+    7: required double strategy_weight,  // 这种推荐理由的策略权重（没有开启 bandit 时为 1）
+    8: optional double calibrated_score,  // 校准后的分数，没有校准参数时为空
+    9: required string rules_version,  // 打分规则版本
+    10: required i32 per_related_user,  // 打分权重
+    11: required i32 per_recent_post,
+    12: optional string calibration_version,
+    13: required list<string> filters_passed,  // 生成推荐时执行的过滤器
+}
+
+// 打分因素
+struct ScoreFactor {
+    1: required string name,  // reason / recent_posts / engagement / creator_engagement
+    2: required i32 value,  // 因素的原始值（如共同关注人数、帖子数）
+    3: required i32 contribution,  // 对分数的贡献
+}
+
+// 混合推荐请求（关注推荐 + 热度推荐，按策略权重混合）
+struct GetHybridRecommendationsRequest {
+    1: required i64 user_id,
+    2: optional i32 limit = 10,  // 返回数量
+    3: optional string surface,  // 展示场景（数量上限、推荐策略按场景配置）
+    4: optional string timezone,  // 客户端时区（IANA 名称），用于帖子的 created_at_display
+}
+
+// 推荐反馈请求（客户端在推荐卡片上的点开、关注）
+struct RecordRecommendationFeedbackRequest {
+    1: required i64 user_id,  // 看到推荐的用户
+    2: required string recommendation_id,  // 推荐ID（曝光时响应中的推荐）
+    3: required string action,  // clicked（点开了推荐卡片）/ followed（在推荐卡片上关注）
+}
+
+// 推荐反馈响应
+struct RecordRecommendationFeedbackResponse {
+}
+
+// 关注请求（用户主动关注）
+struct FollowUserRequest {
+    1: required i64 follower_id,  // 发起关注的用户
+    2: required i64 following_id,  // 被关注的用户
+    3: optional string source,  // 关注入口：recommendation（在推荐卡片上关注，立即归因到推荐曝光）
+}
+
+// 关注响应
+struct FollowUserResponse {
+}
+
+// 取关请求（用户主动取关）
+struct UnfollowUserRequest {
+    1: required i64 follower_id,  // 发起取关的用户
+    2: required i64 following_id,  // 被取关的用户
+}
+
+// 取关响应
+struct UnfollowUserResponse {
+}
+
+// 批量推荐请求（推送管道：一次为一批用户生成推荐）
+struct BatchGetRecommendationsRequest {
+    1: required list<i64> user_ids,  // 最多 100 个
+    2: optional i32 limit = 10,  // 每个用户的返回数量
+    3: optional string surface,  // 展示场景，通常是 push
+    4: optional RecommendationFilter filter,  // 过滤条件（对每个用户相同）
+}
+
+// 批量推荐响应：每个用户一个结果（顺序和 user_ids 相同），失败的用户单独重试即可
+struct BatchGetRecommendationsResponse {
+    1: required list<BatchRecommendationResult> results,
+}
+
+// 一个用户的推荐结果
+struct BatchRecommendationResult {
+    1: required i64 user_id,
+    2: required string status,  // ok / degraded（超时预算用完或部分推荐没有补全完整）/ error
+    3: optional i32 error_code,  // status 为 error 时的错误码，和单个请求的错误码相同（如 403、503）
+    4: optional string error_message,
+    5: optional list<UserRecommendation> recommendations,
+    6: optional string version,
 }
 
 // 推荐服务
@@ -36,4 +346,265 @@ service RecommendationService {
     GetRecommendationsResponse GetFollowingBasedRecommendations(
         1: GetRecommendationsRequest req
     )
+
+    // 删除用户数据（内部接口）
+    DeleteUserDataResponse DeleteUserData(
+        1: DeleteUserDataRequest req
+    )
+
+    // 导出用户数据（内部接口）
+    ExportUserDataResponse ExportUserData(
+        1: ExportUserDataRequest req
+    )
+
+    // 查询展示过的推荐（"之前推荐过的人"、客服排查）
+    GetRecommendationHistoryResponse GetRecommendationHistory(
+        1: GetRecommendationHistoryRequest req
+    )
+
+    // 推荐帖子：二度关系中的作者最近发的帖子（探索页）
+    GetSuggestedPostsResponse GetSuggestedPosts(
+        1: GetSuggestedPostsRequest req
+    )
+
+    // 推荐话题：我关注的人关注的话题
+    GetTopicRecommendationsResponse GetTopicRecommendations(
+        1: GetTopicRecommendationsRequest req
+    )
+
+    // 推荐小组：我关注的人参与的小组
+    GetGroupRecommendationsResponse GetGroupRecommendations(
+        1: GetGroupRecommendationsRequest req
+    )
+
+    // 发现页的分栏推荐
+    GetRecommendationBundleResponse GetRecommendationBundle(
+        1: GetRecommendationBundleRequest req
+    )
+
+    // 触发式推荐：推送管道拉取（你关注的人达到里程碑、很久之后回来了）
+    GetTriggerRecommendationsResponse GetTriggerRecommendations(
+        1: GetTriggerRecommendationsRequest req
+    )
+
+    // 解释一条推荐的打分（内部接口，质量评审工具）
+    ExplainRecommendationResponse ExplainRecommendation(
+        1: ExplainRecommendationRequest req
+    )
+
+    // 批量获取推荐（推送管道）：每个用户单独返回状态和错误码
+    BatchGetRecommendationsResponse BatchGetRecommendations(
+        1: BatchGetRecommendationsRequest req
+    )
+
+    // 混合推荐：关注推荐和热度推荐按策略权重混合（不使用快照，响应不带 version）
+    GetRecommendationsResponse GetHybridRecommendations(
+        1: GetHybridRecommendationsRequest req
+    )
+
+    // 记录推荐反馈：点开、在卡片上关注（同一条推荐的同一种反馈重复上报只记录一次）
+    RecordRecommendationFeedbackResponse RecordRecommendationFeedback(
+        1: RecordRecommendationFeedbackRequest req
+    )
+
+    // 关注：不能关注自己，已经在关注时返回错误（关注之后立即删除关注者的推荐列表缓存）
+    FollowUserResponse FollowUser(
+        1: FollowUserRequest req
+    )
+
+    // 取关（软删除）：没有在关注时返回错误
+    UnfollowUserResponse UnfollowUser(
+        1: UnfollowUserRequest req
+    )
+}
+
+// 审计日志查询请求（管理接口）
+struct QueryAuditLogRequest {
+    1: optional string actor,  // 操作人
+    2: optional string action,  // 操作类型，如 scoring_weight.change
+    3: optional string target,  // 操作对象
+    4: optional i64 since,  // 起始时间（Unix 秒，包含）
+    5: optional i64 until,  // 结束时间（Unix 秒，不包含）
+    6: optional i32 limit = 100,  // 最多返回多少条（最多 1000）
+}
+
+// 审计日志查询响应
+struct QueryAuditLogResponse {
+    1: required list<AuditEntry> entries,  // 最新的在前
+}
+
+// 审计记录
+struct AuditEntry {
+    1: required string actor,
+    2: required string action,
+    3: optional string target,
+    4: optional string before,  // 修改前的值（JSON）
+    5: optional string after,  // 修改后的值（JSON）
+    6: optional string request_id,
+    7: required string occurred_at,  // RFC3339
+}
+
+// 依赖状态查询请求（管理接口）
+struct GetDependencyStatusRequest {
+}
+
+// 依赖状态查询响应
+struct GetDependencyStatusResponse {
+    1: required list<DependencyStatus> dependencies,
+}
+
+// 一个已装配依赖的状态
+struct DependencyStatus {
+    1: required string name,  // 端口名，如 social_graph
+    2: required string type,  // 端口类型，如 SocialGraphRepository
+    3: required string implementation,  // mock、memory、mysql、redis、http
+    4: optional string target,  // 目标地址（主机、URL，不含账号密码）
+    5: required string health,  // ok、failing、unchecked
+    6: optional string last_error,  // 最近一次检查失败的原因
+    7: optional string checked_at,  // 最近一次检查的时间（RFC3339）
+    8: optional i64 latency_ms,  // 最近一次检查的耗时
+    9: required string circuit_breaker,  // closed、open、half_open；没有熔断器时为 none
+}
+
+// 推荐效果统计查询请求（管理接口）
+struct GetFeedbackStatsRequest {
+    1: optional i64 from,  // 起始时间（Unix 秒，包含）；不传时为 to 之前 7 天
+    2: optional i64 to,  // 结束时间（Unix 秒，不包含）；不传时为当前时间；范围最多 90 天
+}
+
+// 推荐效果统计查询响应
+struct GetFeedbackStatsResponse {
+    1: required list<FeedbackStats> stats,  // 按天升序
+}
+
+// 一天、一种策略、一种推荐理由、一个展示场景的推荐效果
+struct FeedbackStats {
+    1: required string day,  // 2006-01-02
+    2: required string strategy,  // following_based
+    3: required string reason_type,  // followed_by_following、popular_in_network
+    4: optional string surface,
+    5: required i64 impressions,
+    6: required i64 conversions,  // 曝光后 24 小时内关注了被推荐用户的曝光次数
+    7: required double conversion_rate,
+}
+
+// 按策略汇总的推荐效果查询请求（管理接口）
+struct GetStrategyStatsRequest {
+    1: optional i64 from,  // 起始时间（Unix 秒，包含，按曝光时间）；不传时为 to 之前 7 天
+    2: optional i64 to,  // 结束时间（Unix 秒，不包含）；不传时为当前时间；范围最多 90 天
+}
+
+// 按策略汇总的推荐效果查询响应
+struct GetStrategyStatsResponse {
+    1: required list<StrategyStats> stats,  // 按策略名升序
+}
+
+// 一种策略的推荐效果（基于归因转化记录）
+struct StrategyStats {
+    1: required string strategy,  // following_based、topic_based
+    2: required i64 impressions,
+    3: required i64 clicks,  // 直接在推荐卡片上关注的次数
+    4: required i64 conversions,  // 曝光后 24 小时内归因到曝光的关注次数（包含 clicks）
+    5: required double click_through_rate,  // clicks / impressions
+    6: required double conversion_rate,  // conversions / impressions
+}
+
+// 交错实验结果查询请求（管理接口）
+struct GetInterleavingStatsRequest {
+    1: required string experiment,  // 实验名（config.yaml 中 surfaces.*.interleaving.experiment）
+    2: optional i64 from,  // 起始时间（Unix 秒，包含，按曝光时间）；不传时为 to 之前 7 天
+    3: optional i64 to,  // 结束时间（Unix 秒，不包含）；不传时为当前时间；范围最多 90 天
+}
+
+// 交错实验结果查询响应
+struct GetInterleavingStatsResponse {
+    1: required string experiment,
+    2: required i64 sessions,  // 交错列表数
+    3: required i64 wins_a,  // 排序器 A 的推荐带来的关注更多的列表数
+    4: required i64 wins_b,
+    5: required i64 ties,  // 有关注、两边一样多的列表数
+    6: required double win_rate_a,  // wins_a / (wins_a + wins_b + ties)
+    7: required double win_rate_b,
+}
+
+// 策略权重查询请求（管理接口）
+struct GetStrategyWeightsRequest {
+    1: optional string surface,  // 展示场景，不传时为未指定场景
+}
+
+// 一种策略（推荐理由类型）的 bandit 统计和权重
+struct StrategyArm {
+    1: required string arm,  // 推荐理由类型，如 followed_by_following
+    2: required i64 pulls,  // 曝光次数
+    3: required i64 rewards,  // 归因转化次数
+    4: required double conversion_rate,  // rewards / pulls
+    5: required double bandit_weight,  // bandit 计算的权重
+    6: required double weight,  // 实际生效的权重（有人工覆盖时以覆盖为准）
+}
+
+// 策略权重的人工覆盖
+struct StrategyWeightOverride {
+    1: required bool disabled,  // 关闭 bandit（所有策略权重为 1）
+    2: optional map<string, double> weights,  // 固定权重（推荐理由类型 → 权重，没有列出的为 1）
+    3: required string updated_by,
+    4: required i64 updated_at,  // Unix 秒
+}
+
+// 策略权重查询响应
+struct GetStrategyWeightsResponse {
+    1: required string surface,
+    2: required list<StrategyArm> arms,
+    3: optional StrategyWeightOverride override,  // 没有人工覆盖时为空
+}
+
+// 设置策略权重的人工覆盖（管理接口，写入审计日志）
+struct SetStrategyWeightOverrideRequest {
+    1: optional string surface,  // 展示场景，不传时为未指定场景
+    2: optional string operator,  // 已废弃：操作人取调用方认证后的身份（审计日志），这个字段不再使用
+    3: optional string request_id,
+    4: optional bool clear,  // 删除覆盖，恢复 bandit 的权重
+    5: optional bool disabled,  // 关闭 bandit（紧急止损）
+    6: optional map<string, double> weights,  // 固定权重，每个权重在 (0, 10] 之间
+}
+
+// 设置策略权重的人工覆盖响应
+struct SetStrategyWeightOverrideResponse {
+}
+
+// 推荐管理服务（只对内部管理后台开放）
+service RecommendationAdminService {
+    // 查询管理操作审计日志
+    QueryAuditLogResponse QueryAuditLog(
+        1: QueryAuditLogRequest req
+    )
+
+    // 查询本实例每个依赖的实现、地址、健康状况（值班排查用）
+    GetDependencyStatusResponse GetDependencyStatus(
+        1: GetDependencyStatusRequest req
+    )
+
+    // 查询推荐效果统计（运营看板）
+    GetFeedbackStatsResponse GetFeedbackStats(
+        1: GetFeedbackStatsRequest req
+    )
+
+    // 查询按策略汇总的推荐效果（点击率、转化率）
+    GetStrategyStatsResponse GetStrategyStats(
+        1: GetStrategyStatsRequest req
+    )
+
+    // 查询交错实验的胜率（比较两个排序器）
+    GetInterleavingStatsResponse GetInterleavingStats(
+        1: GetInterleavingStatsRequest req
+    )
+
+    // 查询各策略的 bandit 统计和当前权重
+    GetStrategyWeightsResponse GetStrategyWeights(
+        1: GetStrategyWeightsRequest req
+    )
+
+    // 固定某个展示场景的策略权重，或关闭 bandit（kill switch）
+    SetStrategyWeightOverrideResponse SetStrategyWeightOverride(
+        1: SetStrategyWeightOverrideRequest req
+    )
 }
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/domain/valueobject"
+)
+
+// 缓存数据的种类（key 的一部分）
+const (
+	KeyKindUserInfo           = "user"          // 用户资料
+	KeyKindFollowings         = "sg:followings" // 关注列表
+	KeyKindRecentFollows      = "sg:recent"     // 最近关注（附加参数：天数）
+	KeyKindSnapshot           = "snap"          // 推荐列表快照（附加参数：展示场景）
+	KeyKindTrigger            = "trigger"       // 触发式推荐（推送）
+	KeyKindSession            = "session"       // 会话内已经返回过的推荐对象（附加参数：展示场景、会话ID）
+	KeyKindRecommendationList = "list"          // 推荐列表缓存（Hash，field 是策略和时间窗口）
+)
+
+// KeyFormat 缓存 key 的格式：租户 + 用户 + 数据种类 + 附加参数 → key
+type KeyFormat func(tenant valueobject.TenantID, userID int64, kind string, parts ...string) string
+
+// KeyFormatV1 第一版格式：rec:{tenant}:{kind}:{userID}[:{parts}]
+//
+// 不带版本号、不带 hash tag：同一个用户的 key 分散在 Redis Cluster 的不同 slot 上。
+func KeyFormatV1(tenant valueobject.TenantID, userID int64, kind string, parts ...string) string {
+	return joinKey("rec:"+tenant.String()+":"+kind+":"+strconv.FormatInt(userID, 10), parts)
+}
+
+// KeyFormatV2 第二版格式：rec:v2:{tenant:userID}:{kind}[:{parts}]
+//
+// 花括号是 Redis Cluster 的 hash tag：只用花括号里的内容计算 slot（CRC16 mod 16384）。
+// 同一个用户的所有 key 落在同一个 slot，slot 只由租户和用户决定，所有实例算出的结果一致。
+func KeyFormatV2(tenant valueobject.TenantID, userID int64, kind string, parts ...string) string {
+	return joinKey("rec:v2:{"+tenant.String()+":"+strconv.FormatInt(userID, 10)+"}:"+kind, parts)
+}
+
+// joinKey 辅助函数：拼接附加参数
+func joinKey(base string, parts []string) string {
+	if len(parts) == 0 {
+		return base
+	}
+	return base + ":" + strings.Join(parts, ":")
+}
+
+// CacheKey 一个缓存条目的 key：当前格式，以及迁移期间的上一版格式（没有时为空）
+type CacheKey struct {
+	Current  string
+	Previous string
+}
+
+// KeyStrategy 缓存 key 策略
+//
+// 为什么需要？
+// 以前每个缓存组件自己拼 key，带来两个问题：
+// 1. Redis Cluster 下同一个用户的 key 分散在不同 slot，删除用户数据时的多 key DEL 会报 CROSSSLOT
+// 2. key 格式一改，所有 key 同时失效，发布后瞬间全部回源（缓存雪崩）
+//
+// KeyStrategy 统一构造 key：
+// - 带版本号：格式变化时升级版本
+// - 带 hash tag：同一个用户的 key 在同一个 slot，删除用户的所有 key 只需要一次 DEL
+// - 平滑迁移：升级期间同时配置上一版格式，当前格式未命中时读上一版，命中后写回当前格式
+//
+// 上一版的 key 全部过期（最长 TTL）后，去掉上一版格式。
+//
+// 注意：不同用户的 key 在不同 slot，批量读取不能用 MGET（Cluster 下会报 CROSSSLOT），
+// 要用 pipeline（go-redis 的 ClusterClient 按节点拆分 pipeline）。
+type KeyStrategy struct {
+	current  KeyFormat
+	previous KeyFormat // 可以为 nil（没有正在进行的迁移）
+}
+
+// NewKeyStrategy 构造函数
+//
+// 参数：
+// - current: 当前格式（写入只使用这个格式）
+// - previous: 上一版格式（只读，可以为 nil）
+func NewKeyStrategy(current, previous KeyFormat) *KeyStrategy {
+	return &KeyStrategy{current: current, previous: previous}
+}
+
+// DefaultKeyStrategy 默认策略：V2 格式，从 V1 平滑迁移
+//
+// 所有缓存的 TTL 都过去之后（目前最长的是快照，24 小时），改为 NewKeyStrategy(KeyFormatV2, nil)。
+func DefaultKeyStrategy() *KeyStrategy {
+	return NewKeyStrategy(KeyFormatV2, KeyFormatV1)
+}
+
+// Key 构造一个用户的缓存 key
+func (s *KeyStrategy) Key(tenant valueobject.TenantID, userID int64, kind string, parts ...string) CacheKey {
+	key := CacheKey{Current: s.current(tenant, userID, kind, parts...)}
+	if s.previous != nil {
+		key.Previous = s.previous(tenant, userID, kind, parts...)
+	}
+	return key
+}
+
+// Get 从 Redis 读取：当前格式未命中时读取上一版格式，命中后写回当前格式
+//
+// 未命中时返回 redis.Nil。
+func (s *KeyStrategy) Get(ctx context.Context, rdb redis.UniversalClient, key CacheKey, ttl time.Duration) ([]byte, error) {
+	data, err := rdb.Get(ctx, key.Current).Bytes()
+	if !errors.Is(err, redis.Nil) || key.Previous == "" {
+		return data, err
+	}
+	data, err = rdb.Get(ctx, key.Previous).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	_ = rdb.Set(ctx, key.Current, data, ttl).Err()
+	return data, nil
+}
+
+// GetMany 批量读取（pipeline），返回值和 keys 一一对应，未命中（或读取失败）的为 nil
+//
+// 和 Get 一样：当前格式未命中的，再用一个 pipeline 读取上一版格式并写回当前格式。
+func (s *KeyStrategy) GetMany(ctx context.Context, rdb redis.UniversalClient, keys []CacheKey, ttl time.Duration) [][]byte {
+	values := make([][]byte, len(keys))
+	missing := s.pipelineGet(ctx, rdb, keys, values, func(key CacheKey) string { return key.Current })
+	if len(missing) == 0 || s.previous == nil {
+		return values
+	}
+
+	previousKeys := make([]CacheKey, len(missing))
+	previousValues := make([][]byte, len(missing))
+	for i, index := range missing {
+		previousKeys[i] = keys[index]
+	}
+	s.pipelineGet(ctx, rdb, previousKeys, previousValues, func(key CacheKey) string { return key.Previous })
+
+	_, _ = rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, index := range missing {
+			if previousValues[i] == nil {
+				continue
+			}
+			values[index] = previousValues[i]
+			pipe.Set(ctx, keys[index].Current, previousValues[i], ttl)
+		}
+		return nil
+	})
+	return values
+}
+
+// DeleteUserKeys 删除同一个用户的一组 key（两种格式都删除）
+//
+// 当前格式的 key 在同一个 slot，一次 DEL；
+// 上一版格式没有 hash tag，分散在不同 slot，用 pipeline 逐个 DEL。
+func (s *KeyStrategy) DeleteUserKeys(ctx context.Context, rdb redis.UniversalClient, keys []CacheKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	current := make([]string, 0, len(keys))
+	for _, key := range keys {
+		current = append(current, key.Current)
+	}
+	if err := rdb.Del(ctx, current...).Err(); err != nil {
+		return err
+	}
+	if s.previous == nil {
+		return nil
+	}
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key.Previous)
+		}
+		return nil
+	})
+	return err
+}
+
+// pipelineGet 辅助方法：用 pipeline 读取 keys（pick 选择使用哪个格式），结果写入 values，返回未命中的下标
+func (s *KeyStrategy) pipelineGet(
+	ctx context.Context,
+	rdb redis.UniversalClient,
+	keys []CacheKey,
+	values [][]byte,
+	pick func(CacheKey) string,
+) []int {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, _ = rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, pick(key))
+		}
+		return nil
+	})
+
+	var missing []int
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err != nil {
+			missing = append(missing, i)
+			continue
+		}
+		values[i] = data
+	}
+	return missing
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"service/domain/valueobject"
+)
+
+func TestKeyStrategy_Key(t *testing.T) {
+	keys := DefaultKeyStrategy()
+	tenant := valueobject.TenantID("brand_b")
+
+	// 上一版格式和原来各组件拼出的 key 一致：升级后仍然能读到旧数据
+	legacy := map[string]CacheKey{
+		"rec:brand_b:user:42":           keys.Key(tenant, 42, KeyKindUserInfo),
+		"rec:brand_b:sg:followings:42":  keys.Key(tenant, 42, KeyKindFollowings),
+		"rec:brand_b:sg:recent:42:7":    keys.Key(tenant, 42, KeyKindRecentFollows, "7"),
+		"rec:brand_b:snap:42:home_feed": keys.Key(tenant, 42, KeyKindSnapshot, "home_feed"),
+	}
+	for want, key := range legacy {
+		if key.Previous != want {
+			t.Errorf("previous = %q, want %q", key.Previous, want)
+		}
+	}
+
+	// 当前格式：同一个用户的所有 key 使用同一个 hash tag
+	for _, key := range legacy {
+		if !strings.HasPrefix(key.Current, "rec:v2:{brand_b:42}:") {
+			t.Errorf("current = %q, want hash tag {brand_b:42}", key.Current)
+		}
+	}
+
+	// 没有迁移时不读上一版格式
+	if key := NewKeyStrategy(KeyFormatV2, nil).Key(tenant, 42, KeyKindUserInfo); key.Previous != "" {
+		t.Errorf("previous = %q, want empty", key.Previous)
+	}
+}
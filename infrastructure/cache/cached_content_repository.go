@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// CachedContentRepository 带缓存的内容仓储（装饰器）
+//
+// 缓存哪些查询？
+// - CountRecentPosts：生成推荐时每个候选调用一次
+// - CountRecentPostsBatch：和 CountRecentPosts 共用同一份缓存，只有缓存没命中的用户批量回源
+//
+// 同一个热门候选出现在大量用户的推荐中，而"最近 N 天的帖子数"几分钟内几乎不变，非常适合缓存。
+// GetRecentPosts、GetRecentPostsForUsers 直接透传：展示用的帖子内容要尽量新（刚发的帖子、刚删除的帖子）。
+type CachedContentRepository struct {
+	next        repository.ContentRepository
+	recentPosts *ReadThrough[int]
+}
+
+// NewCachedContentRepository 构造函数
+func NewCachedContentRepository(next repository.ContentRepository, recentPosts *ReadThrough[int]) repository.ContentRepository {
+	return &CachedContentRepository{
+		next:        next,
+		recentPosts: recentPosts,
+	}
+}
+
+// CountRecentPosts 实现接口：带缓存的最近帖子数查询
+func (r *CachedContentRepository) CountRecentPosts(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) (int, error) {
+	key := r.recentPosts.Key(ctx, "CountRecentPosts", userID.Value(), days)
+	return r.recentPosts.Get(ctx, key, func(ctx context.Context) (int, error) {
+		return r.next.CountRecentPosts(ctx, userID, days)
+	})
+}
+
+// CountRecentPostsBatch 实现接口：逐个用户查询缓存，没命中的用户一次批量回源
+//
+// 回源结果按用户回填（和 CountRecentPosts 同一个 key），没有帖子的用户缓存为 0。
+func (r *CachedContentRepository) CountRecentPostsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID]int, error) {
+	result := make(map[valueobject.UserID]int, len(userIDs))
+	var misses []valueobject.UserID
+	for _, userID := range userIDs {
+		key := r.recentPosts.Key(ctx, "CountRecentPosts", userID.Value(), days)
+		count, ok := r.recentPosts.Lookup(ctx, key, func(ctx context.Context) (int, error) {
+			return r.next.CountRecentPosts(ctx, userID, days)
+		})
+		if !ok {
+			misses = append(misses, userID)
+			continue
+		}
+		result[userID] = count
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := r.next.CountRecentPostsBatch(ctx, misses, days)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range misses {
+		r.recentPosts.Store(r.recentPosts.Key(ctx, "CountRecentPosts", userID.Value(), days), loaded[userID])
+		result[userID] = loaded[userID]
+	}
+	return result, nil
+}
+
+// GetRecentPosts 实现接口：透传
+func (r *CachedContentRepository) GetRecentPosts(
+	ctx context.Context,
+	userID valueobject.UserID,
+	limit int,
+) ([]*entity.Post, error) {
+	return r.next.GetRecentPosts(ctx, userID, limit)
+}
+
+// GetRecentPostsForUsers 实现接口：透传
+func (r *CachedContentRepository) GetRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	limit int,
+) (map[valueobject.UserID][]*entity.Post, error) {
+	return r.next.GetRecentPostsForUsers(ctx, userIDs, limit)
+}
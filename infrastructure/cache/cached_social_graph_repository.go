@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// CachedSocialGraphRepository 带缓存的社交图谱仓储（装饰器）
+//
+// 缓存层次：进程内 HotCache → Redis → 被装饰的仓储（数据库）
+//
+// 缓存哪些查询？
+// - GetFollowings：用户自己的关注列表
+// - GetRecentFollowings：大 V 的最近关注（被成千上万个粉丝的推荐请求反复查询，最典型的热点数据）
+// - GetRecentFollowingsBatch：和 GetRecentFollowings 共用同一份缓存，只有缓存没命中的用户批量回源
+//
+// 其他查询（计数、分页遍历、关系判断、二度扩展）直接透传。
+//
+// 失效：
+// - 通过这个仓储写入的关注、取关：写入后删除关注者的缓存
+// - 关注关系由其他服务写入时：关注事件的消费者调用 InvalidateUser
+// - 多实例部署：开启 WithSocialGraphInvalidation 时，InvalidateUser 广播失效消息，所有实例删除进程内缓存
+//
+// 为什么用装饰器？
+// 领域层只依赖 SocialGraphRepository 接口，不知道缓存的存在；
+// 缓存可以按需开启、关闭，不影响任何调用方。
+type CachedSocialGraphRepository struct {
+	next     repository.SocialGraphRepository
+	hot      *HotCache[[]valueobject.UserID]
+	rdb      redis.UniversalClient // 可以为 nil（只使用进程内缓存）
+	redisTTL time.Duration
+	keys     *KeyStrategy
+
+	invalidations *InvalidationBus // 失效消息（为 nil 时只删除本实例的进程内缓存）
+
+	// recentDays 出现过的"最近N天"参数（缓存 key 的一部分），删除用户缓存时逐个删除
+	recentDays sync.Map // int → struct{}
+}
+
+// CachedSocialGraphOption 可选配置
+type CachedSocialGraphOption func(*CachedSocialGraphRepository)
+
+// WithSocialGraphInvalidation 删除用户缓存时广播失效消息，收到失效消息时删除本实例的进程内缓存
+//
+// 订阅由调用方启动（bus.Subscribe(ctx, repo.HandleInvalidation)），进程退出时停止。
+func WithSocialGraphInvalidation(bus *InvalidationBus) CachedSocialGraphOption {
+	return func(r *CachedSocialGraphRepository) {
+		r.invalidations = bus
+	}
+}
+
+// NewCachedSocialGraphRepository 构造函数
+func NewCachedSocialGraphRepository(
+	next repository.SocialGraphRepository,
+	hot *HotCache[[]valueobject.UserID],
+	rdb redis.UniversalClient,
+	redisTTL time.Duration,
+	keys *KeyStrategy,
+	opts ...CachedSocialGraphOption,
+) *CachedSocialGraphRepository {
+	r := &CachedSocialGraphRepository{
+		next:     next,
+		hot:      hot,
+		rdb:      rdb,
+		redisTTL: redisTTL,
+		keys:     keys,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// GetFollowings 实现接口：带缓存的关注列表查询
+func (r *CachedSocialGraphRepository) GetFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.UserID, error) {
+	key := r.keys.Key(valueobject.TenantFromContext(ctx), userID.Value(), KeyKindFollowings)
+	return r.load(ctx, key, func() ([]valueobject.UserID, error) {
+		return r.next.GetFollowings(ctx, userID)
+	})
+}
+
+// GetRecentFollowings 实现接口：带缓存的最近关注查询
+func (r *CachedSocialGraphRepository) GetRecentFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]valueobject.UserID, error) {
+	r.recentDays.Store(days, struct{}{})
+	key := r.keys.Key(valueobject.TenantFromContext(ctx), userID.Value(), KeyKindRecentFollows, strconv.Itoa(days))
+	return r.load(ctx, key, func() ([]valueobject.UserID, error) {
+		return r.next.GetRecentFollowings(ctx, userID, days)
+	})
+}
+
+// GetRecentFollowingsBatch 实现接口：逐个用户查询缓存，没命中的用户一次批量回源
+//
+// 回源结果按用户写入缓存（和 GetRecentFollowings 同一个 key），最近没有关注任何人的用户缓存为空列表，
+// 下一次不会再回源。
+func (r *CachedSocialGraphRepository) GetRecentFollowingsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID][]valueobject.UserID, error) {
+	r.recentDays.Store(days, struct{}{})
+	tenant := valueobject.TenantFromContext(ctx)
+	result := make(map[valueobject.UserID][]valueobject.UserID, len(userIDs))
+	var misses []valueobject.UserID
+	for _, userID := range userIDs {
+		key := r.keys.Key(tenant, userID.Value(), KeyKindRecentFollows, strconv.Itoa(days))
+		if followings, ok := r.cached(ctx, key); ok {
+			result[userID] = followings
+			continue
+		}
+		misses = append(misses, userID)
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := r.next.GetRecentFollowingsBatch(ctx, misses, days)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range misses {
+		followings := loaded[userID]
+		if followings == nil {
+			followings = []valueobject.UserID{}
+		}
+		r.store(ctx, r.keys.Key(tenant, userID.Value(), KeyKindRecentFollows, strconv.Itoa(days)), followings)
+		result[userID] = followings
+	}
+	return result, nil
+}
+
+// CountFollowings 实现接口：透传
+func (r *CachedSocialGraphRepository) CountFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (int, error) {
+	return r.next.CountFollowings(ctx, userID)
+}
+
+// ScanFollowings 实现接口：透传（分页遍历的就是缓存装不下的大列表）
+func (r *CachedSocialGraphRepository) ScanFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+	return r.next.ScanFollowings(ctx, userID, pageSize, fn)
+}
+
+// IsFollowing 实现接口：透传
+func (r *CachedSocialGraphRepository) IsFollowing(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (bool, error) {
+	return r.next.IsFollowing(ctx, followerID, followingID)
+}
+
+// GetMutualFollows 实现接口：透传
+func (r *CachedSocialGraphRepository) GetMutualFollows(
+	ctx context.Context,
+	userID valueobject.UserID,
+	limit int,
+) ([]valueobject.UserID, error) {
+	return r.next.GetMutualFollows(ctx, userID, limit)
+}
+
+// ExpandTwoHop 实现 repository.TwoHopExpander：透传
+//
+// 被装饰的仓储没有这个能力时返回 repository.ErrTwoHopExpansionUnsupported（调用方回退到逐个查询）。
+func (r *CachedSocialGraphRepository) ExpandTwoHop(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]repository.TwoHopCandidate, error) {
+	expander, ok := r.next.(repository.TwoHopExpander)
+	if !ok {
+		return nil, repository.ErrTwoHopExpansionUnsupported
+	}
+	return expander.ExpandTwoHop(ctx, userID, days)
+}
+
+// FindFollow 实现接口：透传（写侧读取，需要最新的状态）
+func (r *CachedSocialGraphRepository) FindFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (*entity.Follow, error) {
+	return r.next.FindFollow(ctx, followerID, followingID)
+}
+
+// SaveFollow 实现接口：写入后删除关注者的关注列表缓存
+//
+// 删除失败不返回错误（写入已经成功），缓存随 TTL 过期。
+func (r *CachedSocialGraphRepository) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	if err := r.next.SaveFollow(ctx, follow); err != nil {
+		return err
+	}
+	_ = r.InvalidateUser(ctx, follow.FollowerID().Value())
+	return nil
+}
+
+// RemoveFollow 实现接口：取关后删除关注者的关注列表缓存（同 SaveFollow）
+func (r *CachedSocialGraphRepository) RemoveFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+	removedAt time.Time,
+) error {
+	if err := r.next.RemoveFollow(ctx, followerID, followingID, removedAt); err != nil {
+		return err
+	}
+	_ = r.InvalidateUser(ctx, followerID.Value())
+	return nil
+}
+
+// InvalidateUser 实现 service.UserCacheInvalidator：删除用户自己的关注列表缓存（关注关系变化、用户数据删除）
+//
+// 用户出现在别人关注列表缓存里的记录不逐个查找，随 TTL 过期。
+// 用户自己的 key 都在同一个 slot（KeyStrategy 的 hash tag），一次 DEL 删除。
+// 开启了失效消息时，Redis 中的缓存删除之后再广播，其他实例收到后删除进程内缓存。
+func (r *CachedSocialGraphRepository) InvalidateUser(ctx context.Context, userID int64) error {
+	tenant := valueobject.TenantFromContext(ctx)
+	cacheKeys := r.userKeys(tenant, userID)
+	r.evictHot(cacheKeys)
+	if r.rdb == nil {
+		return nil
+	}
+	if err := r.keys.DeleteUserKeys(ctx, r.rdb, cacheKeys); err != nil {
+		return err
+	}
+	if r.invalidations == nil {
+		return nil
+	}
+	return r.invalidations.Publish(ctx, tenant, userID)
+}
+
+// HandleInvalidation 处理其他实例广播的失效消息：删除本实例的进程内缓存
+//
+// Redis 中的缓存已经由发送消息的实例删除；未知的租户跳过。
+func (r *CachedSocialGraphRepository) HandleInvalidation(ctx context.Context, msg InvalidationMessage) {
+	tenant, err := valueobject.NewTenantID(msg.Tenant)
+	if err != nil {
+		return
+	}
+	r.evictHot(r.userKeys(tenant, msg.UserID))
+}
+
+// userKeys 辅助方法：用户自己的所有关注列表缓存 key
+func (r *CachedSocialGraphRepository) userKeys(tenant valueobject.TenantID, userID int64) []CacheKey {
+	cacheKeys := []CacheKey{r.keys.Key(tenant, userID, KeyKindFollowings)}
+	r.recentDays.Range(func(days, _ any) bool {
+		cacheKeys = append(cacheKeys, r.keys.Key(tenant, userID, KeyKindRecentFollows, strconv.Itoa(days.(int))))
+		return true
+	})
+	return cacheKeys
+}
+
+// evictHot 辅助方法：删除进程内缓存
+func (r *CachedSocialGraphRepository) evictHot(cacheKeys []CacheKey) {
+	for _, key := range cacheKeys {
+		r.hot.Del(key.Current)
+	}
+}
+
+// load 辅助方法：逐层查询缓存，都没有命中时回源，并回填各层缓存
+//
+// 容错设计：Redis 读写失败都当作未命中处理，不影响回源
+func (r *CachedSocialGraphRepository) load(
+	ctx context.Context,
+	key CacheKey,
+	source func() ([]valueobject.UserID, error),
+) ([]valueobject.UserID, error) {
+	if userIDs, ok := r.cached(ctx, key); ok {
+		return userIDs, nil
+	}
+
+	// 回源
+	userIDs, err := source()
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, key, userIDs)
+	return userIDs, nil
+}
+
+// cached 辅助方法：逐层查询缓存（Redis 命中时回填进程内缓存）
+func (r *CachedSocialGraphRepository) cached(ctx context.Context, key CacheKey) ([]valueobject.UserID, bool) {
+	// 第一层：进程内缓存
+	if userIDs, ok := r.hot.Get(key.Current); ok {
+		return userIDs, true
+	}
+
+	// 第二层：Redis（当前格式未命中时读取上一版格式）
+	if r.rdb != nil {
+		if data, err := r.keys.Get(ctx, r.rdb, key, r.redisTTL); err == nil {
+			if userIDs, ok := decodeUserIDs(data); ok {
+				r.hot.Set(key.Current, userIDs, userIDsCost(userIDs))
+				return userIDs, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// store 辅助方法：回源结果写入各层缓存
+func (r *CachedSocialGraphRepository) store(ctx context.Context, key CacheKey, userIDs []valueobject.UserID) {
+	if r.rdb != nil {
+		_ = r.rdb.Set(ctx, key.Current, encodeUserIDs(userIDs), r.redisTTL).Err()
+	}
+	r.hot.Set(key.Current, userIDs, userIDsCost(userIDs))
+}
+
+// userIDsCost 列表的缓存成本：按长度计算，大列表占用更多容量
+func userIDsCost(userIDs []valueobject.UserID) int64 {
+	return int64(len(userIDs)) + 1
+}
+
+// encodeUserIDs 序列化：UserID 列表 → JSON（[1,2,3]）
+func encodeUserIDs(userIDs []valueobject.UserID) []byte {
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.Value())
+	}
+	data, _ := json.Marshal(ids)
+	return data
+}
+
+// decodeUserIDs 反序列化：JSON → UserID 列表
+func decodeUserIDs(data []byte) ([]valueobject.UserID, bool) {
+	var ids []int64
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, false
+	}
+
+	userIDs := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, true
+}
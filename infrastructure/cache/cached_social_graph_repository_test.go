@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// countingGraph 测试用社交图谱仓储：记录回源次数
+type countingGraph struct {
+	repository.SocialGraphRepository
+	loads      int                    // 单个用户查询的回源次数
+	batchLoads [][]valueobject.UserID // 每次批量回源请求的用户
+	followings []valueobject.UserID
+}
+
+func (g *countingGraph) GetRecentFollowings(ctx context.Context, userID valueobject.UserID, days int) ([]valueobject.UserID, error) {
+	g.loads++
+	return g.followings, nil
+}
+
+func (g *countingGraph) GetRecentFollowingsBatch(ctx context.Context, userIDs []valueobject.UserID, days int) (map[valueobject.UserID][]valueobject.UserID, error) {
+	g.batchLoads = append(g.batchLoads, userIDs)
+	result := make(map[valueobject.UserID][]valueobject.UserID)
+	for _, userID := range userIDs {
+		if userID.Value() == 1 { // 只有用户 1 最近有关注
+			result[userID] = g.followings
+		}
+	}
+	return result, nil
+}
+
+func (g *countingGraph) GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	g.loads++
+	return g.followings, nil
+}
+
+func TestCachedSocialGraphRepository_Invalidation(t *testing.T) {
+	hot, err := NewHotCache[[]valueobject.UserID]("test", 1000, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+	userID, _ := valueobject.NewUserID(1)
+	followingID, _ := valueobject.NewUserID(2)
+	next := &countingGraph{followings: []valueobject.UserID{followingID}}
+	repo := NewCachedSocialGraphRepository(next, hot, nil, time.Minute, DefaultKeyStrategy())
+	tenant, _ := valueobject.NewTenantID("brand_b")
+	ctx := valueobject.WithTenant(context.Background(), tenant)
+
+	// load 回源并写入进程内缓存（ristretto 异步写入，等写入完成）
+	load := func() {
+		t.Helper()
+		got, err := repo.GetFollowings(ctx, userID)
+		if err != nil || len(got) != 1 || got[0] != followingID {
+			t.Fatalf("GetFollowings = %v, %v", got, err)
+		}
+		hot.cache.Wait()
+	}
+	load()
+	load()
+	if next.loads != 1 {
+		t.Fatalf("loads = %d, want 1 (second read from cache)", next.loads)
+	}
+
+	// 其他租户的失效消息不影响这个租户的缓存
+	repo.HandleInvalidation(context.Background(), InvalidationMessage{Tenant: valueobject.DefaultTenantID.String(), UserID: 1})
+	load()
+	if next.loads != 1 {
+		t.Fatalf("loads = %d after another tenant's invalidation, want 1", next.loads)
+	}
+
+	// 其他实例广播的失效消息：删除进程内缓存，下一次回源
+	repo.HandleInvalidation(context.Background(), InvalidationMessage{Tenant: tenant.String(), UserID: 1})
+	load()
+	if next.loads != 2 {
+		t.Fatalf("loads = %d after invalidation message, want 2", next.loads)
+	}
+
+	// 本实例删除（如关注事件）
+	if err := repo.InvalidateUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	load()
+	if next.loads != 3 {
+		t.Fatalf("loads = %d after InvalidateUser, want 3", next.loads)
+	}
+}
+
+func TestCachedSocialGraphRepository_HotBatch(t *testing.T) {
+	hot, err := NewHotCache[[]valueobject.UserID]("test", 1000, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+	u1, _ := valueobject.NewUserID(1)
+	u2, _ := valueobject.NewUserID(2)
+	u3, _ := valueobject.NewUserID(3)
+	next := &countingGraph{followings: []valueobject.UserID{u3}}
+	repo := NewCachedSocialGraphRepository(next, hot, nil, time.Minute, DefaultKeyStrategy())
+	ctx := context.Background()
+
+	batch := func(days int, userIDs ...valueobject.UserID) map[valueobject.UserID][]valueobject.UserID {
+		t.Helper()
+		got, err := repo.GetRecentFollowingsBatch(ctx, userIDs, days)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hot.cache.Wait()
+		return got
+	}
+
+	got := batch(7, u1, u2)
+	if len(got[u1]) != 1 || got[u1][0] != u3 || got[u2] == nil || len(got[u2]) != 0 {
+		t.Fatalf("first batch = %v, want u1 → [3], u2 → []", got)
+	}
+
+	// 两个用户都命中进程内缓存（用户 2 缓存的是空列表），不再回源
+	batch(7, u1, u2)
+	if len(next.batchLoads) != 1 {
+		t.Fatalf("batch loads = %v, want 1 (second batch from cache)", next.batchLoads)
+	}
+
+	// 不同的天数是不同的 key
+	batch(30, u1)
+	if len(next.batchLoads) != 2 {
+		t.Fatalf("batch loads = %v, want 2 (days is part of the key)", next.batchLoads)
+	}
+
+	// 单个查询和批量查询共用 key
+	if _, err := repo.GetRecentFollowings(ctx, u1, 7); err != nil || next.loads != 0 {
+		t.Fatalf("GetRecentFollowings loads = %d, err = %v, want served from the batch's cache", next.loads, err)
+	}
+
+	// 删除用户 1 的缓存：所有天数的 key 都删除，只有用户 1 回源
+	if err := repo.InvalidateUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	batch(7, u1, u2)
+	batch(30, u1)
+	if len(next.batchLoads) != 4 || len(next.batchLoads[2]) != 1 || next.batchLoads[2][0] != u1 {
+		t.Fatalf("batch loads after invalidation = %v, want [1] for each days", next.batchLoads)
+	}
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// CachedUserRPCClient 带缓存的用户服务客户端（装饰器）
+//
+// 缓存层次：进程内 HotCache → Redis（pipeline 批量读取）→ 被装饰的客户端（user 服务）
+//
+// 热门用户（大 V）几乎出现在每个人的推荐结果里，
+// 他们的资料由进程内缓存直接返回，只有冷门用户才需要访问 Redis 或 user 服务。
+type CachedUserRPCClient struct {
+	next     service.UserRPCClient
+	hot      *HotCache[*service.UserInfo]
+	rdb      redis.UniversalClient // 可以为 nil（只使用进程内缓存）
+	redisTTL time.Duration
+	keys     *KeyStrategy
+}
+
+// NewCachedUserRPCClient 构造函数
+func NewCachedUserRPCClient(
+	next service.UserRPCClient,
+	hot *HotCache[*service.UserInfo],
+	rdb redis.UniversalClient,
+	redisTTL time.Duration,
+	keys *KeyStrategy,
+) service.UserRPCClient {
+	return &CachedUserRPCClient{
+		next:     next,
+		hot:      hot,
+		rdb:      rdb,
+		redisTTL: redisTTL,
+		keys:     keys,
+	}
+}
+
+// GetUserInfo 实现接口：复用批量查询的缓存逻辑
+func (c *CachedUserRPCClient) GetUserInfo(ctx context.Context, userID int64) (*service.UserInfo, error) {
+	infos, err := c.GetUserInfoBatch(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return c.next.GetUserInfo(ctx, userID)
+	}
+	return infos[0], nil
+}
+
+// GetUserInfoBatch 实现接口：逐层查询，只把各层都没命中的用户交给 user 服务
+//
+// 返回结果保持 userIDs 的顺序（查不到的用户跳过）
+func (c *CachedUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*service.UserInfo, error) {
+	found := make(map[int64]*service.UserInfo, len(userIDs))
+	tenant := valueobject.TenantFromContext(ctx)
+
+	// 第一层：进程内缓存
+	missing := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if info, ok := c.hot.Get(c.userInfoKey(tenant, userID).Current); ok {
+			found[userID] = info
+			continue
+		}
+		missing = append(missing, userID)
+	}
+
+	// 第二层：Redis（一次 pipeline）
+	if len(missing) > 0 && c.rdb != nil {
+		missing = c.loadFromRedis(ctx, missing, found)
+	}
+
+	// 回源：user 服务
+	if len(missing) > 0 {
+		infos, err := c.next.GetUserInfoBatch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		c.fill(ctx, infos)
+		for _, info := range infos {
+			found[info.UserID] = info
+		}
+	}
+
+	result := make([]*service.UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if info, ok := found[userID]; ok {
+			result = append(result, info)
+		}
+	}
+	return result, nil
+}
+
+// loadFromRedis 辅助方法：从 Redis 批量读取，返回仍然缺失的用户
+//
+// 不同用户的 key 在不同的 slot，不能用 MGET（Redis Cluster 下会报 CROSSSLOT），用 pipeline 读取。
+//
+// 容错设计：Redis 失败时当作全部未命中
+func (c *CachedUserRPCClient) loadFromRedis(
+	ctx context.Context,
+	userIDs []int64,
+	found map[int64]*service.UserInfo,
+) []int64 {
+	tenant := valueobject.TenantFromContext(ctx)
+	keys := make([]CacheKey, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, c.userInfoKey(tenant, userID))
+	}
+
+	values := c.keys.GetMany(ctx, c.rdb, keys, c.redisTTL)
+
+	missing := make([]int64, 0, len(userIDs))
+	for i, data := range values {
+		if data == nil {
+			missing = append(missing, userIDs[i])
+			continue
+		}
+		var info service.UserInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			missing = append(missing, userIDs[i])
+			continue
+		}
+		found[userIDs[i]] = &info
+		c.hot.Set(keys[i].Current, &info, 1)
+	}
+	return missing
+}
+
+// fill 辅助方法：把回源结果写回 Redis 和进程内缓存
+func (c *CachedUserRPCClient) fill(ctx context.Context, infos []*service.UserInfo) {
+	tenant := valueobject.TenantFromContext(ctx)
+	if c.rdb != nil && len(infos) > 0 {
+		_, _ = c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, info := range infos {
+				data, err := json.Marshal(info)
+				if err != nil {
+					continue
+				}
+				pipe.Set(ctx, c.userInfoKey(tenant, info.UserID).Current, data, c.redisTTL)
+			}
+			return nil
+		})
+	}
+	for _, info := range infos {
+		c.hot.Set(c.userInfoKey(tenant, info.UserID).Current, info, 1)
+	}
+}
+
+// InvalidateUser 实现 service.UserCacheInvalidator：删除用户资料缓存（用户数据删除）
+func (c *CachedUserRPCClient) InvalidateUser(ctx context.Context, userID int64) error {
+	key := c.userInfoKey(valueobject.TenantFromContext(ctx), userID)
+	c.hot.Del(key.Current)
+	if c.rdb == nil {
+		return nil
+	}
+	return c.keys.DeleteUserKeys(ctx, c.rdb, []CacheKey{key})
+}
+
+// userInfoKey 用户资料的缓存 key（按租户隔离：不同 App 品牌的同一个用户ID 是不同的用户）
+func (c *CachedUserRPCClient) userInfoKey(tenant valueobject.TenantID, userID int64) CacheKey {
+	return c.keys.Key(tenant, userID, KeyKindUserInfo)
+}
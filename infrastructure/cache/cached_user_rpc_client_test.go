@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// countingUserClient 测试用 user 服务客户端：记录每次批量回源请求的用户，用户 404 不存在
+type countingUserClient struct {
+	batches [][]int64
+}
+
+func (c *countingUserClient) GetUserInfo(ctx context.Context, userID int64) (*service.UserInfo, error) {
+	infos, err := c.GetUserInfoBatch(ctx, []int64{userID})
+	if err != nil || len(infos) == 0 {
+		return nil, err
+	}
+	return infos[0], nil
+}
+
+func (c *countingUserClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*service.UserInfo, error) {
+	c.batches = append(c.batches, userIDs)
+	infos := make([]*service.UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != 404 {
+			infos = append(infos, &service.UserInfo{UserID: userID, Username: valueobject.TenantFromContext(ctx).String()})
+		}
+	}
+	return infos, nil
+}
+
+func TestCachedUserRPCClient_HotLayer(t *testing.T) {
+	hot, err := NewHotCache[*service.UserInfo]("test", 1000, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+	next := &countingUserClient{}
+	client := NewCachedUserRPCClient(next, hot, nil, time.Minute, DefaultKeyStrategy())
+	ctx := context.Background()
+
+	batch := func(ctx context.Context, userIDs ...int64) []*service.UserInfo {
+		t.Helper()
+		infos, err := client.GetUserInfoBatch(ctx, userIDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hot.cache.Wait()
+		return infos
+	}
+
+	batch(ctx, 1, 2)
+
+	// 部分命中：只有没缓存的用户回源；结果保持请求的顺序，不存在的用户跳过
+	infos := batch(ctx, 3, 1, 404, 2)
+	if len(infos) != 3 || infos[0].UserID != 3 || infos[1].UserID != 1 || infos[2].UserID != 2 {
+		t.Fatalf("infos = %v, want users 3, 1, 2 in order", infos)
+	}
+	if len(next.batches) != 2 || len(next.batches[1]) != 2 || next.batches[1][0] != 3 || next.batches[1][1] != 404 {
+		t.Fatalf("batches = %v, want second batch [3 404]", next.batches)
+	}
+
+	// 单个查询复用同一份缓存
+	if info, err := client.GetUserInfo(ctx, 1); err != nil || info.UserID != 1 || len(next.batches) != 2 {
+		t.Fatalf("GetUserInfo(1) = %v, %v, batches = %d, want served from cache", info, err, len(next.batches))
+	}
+
+	// 按租户隔离：另一个租户的同一个用户ID 回源
+	tenant, _ := valueobject.NewTenantID("brand_b")
+	tenantCtx := valueobject.WithTenant(ctx, tenant)
+	if infos := batch(tenantCtx, 1); len(infos) != 1 || infos[0].Username != "brand_b" || len(next.batches) != 3 {
+		t.Fatalf("other tenant infos = %v, batches = %d, want loaded for brand_b", infos, len(next.batches))
+	}
+
+	// 删除用户缓存（用户数据删除）后回源
+	if err := client.(service.UserCacheInvalidator).InvalidateUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	batch(ctx, 1, 2)
+	if len(next.batches) != 4 || len(next.batches[3]) != 1 || next.batches[3][0] != 1 {
+		t.Fatalf("batches = %v, want [1] after invalidation", next.batches)
+	}
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"service/application/service"
+)
+
+// hotCacheMetrics 测试用监控：按 指标名/result 记录计数
+type hotCacheMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func (m *hotCacheMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name+"/"+labels["result"]] += delta
+}
+
+func (m *hotCacheMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
+
+func (m *hotCacheMetrics) count(name, result string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name+"/"+result]
+}
+
+func TestHotCache_HitMissAndDel(t *testing.T) {
+	metrics := &hotCacheMetrics{counters: map[string]int{}}
+	hot, err := NewHotCache[string]("test", 100, time.Minute, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+
+	if _, ok := hot.Get("a"); ok {
+		t.Fatal("Get(a) hit before Set")
+	}
+	hot.Set("a", "A", 1)
+	hot.cache.Wait() // ristretto 异步写入
+	if got, ok := hot.Get("a"); !ok || got != "A" {
+		t.Fatalf("Get(a) = %q, %v, want A", got, ok)
+	}
+	hot.Del("a")
+	if _, ok := hot.Get("a"); ok {
+		t.Fatal("Get(a) hit after Del")
+	}
+
+	if hits, misses := metrics.count(service.MetricHotCacheRequests, "hit"), metrics.count(service.MetricHotCacheRequests, "miss"); hits != 1 || misses != 2 {
+		t.Errorf("hits = %d, misses = %d, want 1, 2", hits, misses)
+	}
+	if offered := metrics.count(service.MetricHotCacheSets, "offered"); offered != 1 {
+		t.Errorf("offered = %d, want 1", offered)
+	}
+}
+
+func TestHotCache_TTL(t *testing.T) {
+	hot, err := NewHotCache[string]("test", 100, 50*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+
+	hot.Set("a", "A", 1)
+	hot.cache.Wait()
+	if _, ok := hot.Get("a"); !ok {
+		t.Fatal("Get(a) missed before TTL")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := hot.Get("a"); ok {
+		t.Fatal("Get(a) hit after TTL")
+	}
+}
+
+func TestHotCache_CostBound(t *testing.T) {
+	metrics := &hotCacheMetrics{counters: map[string]int{}}
+	hot, err := NewHotCache[int]("test", 10, time.Minute, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hot.Close()
+
+	// 成本超过上限的条目直接拒绝
+	hot.Set("huge", 0, 11)
+	hot.cache.Wait()
+	if _, ok := hot.Get("huge"); ok {
+		t.Fatal("entry larger than max cost was cached")
+	}
+	if rejected := metrics.count(service.MetricHotCacheSets, "rejected"); rejected != 1 {
+		t.Errorf("rejected = %d, want 1", rejected)
+	}
+
+	// 写入的总成本超过上限：淘汰或拒绝一部分，缓存的条目总成本不超过上限
+	for i := range 100 {
+		hot.Set(fmt.Sprint(i), i, 1)
+		hot.cache.Wait()
+	}
+	cached := 0
+	for i := range 100 {
+		if _, ok := hot.Get(fmt.Sprint(i)); ok {
+			cached++
+		}
+	}
+	if cached == 0 || cached > 10 {
+		t.Errorf("cached entries = %d, want 1..10", cached)
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/domain/valueobject"
+)
+
+// InvalidationMessage 缓存失效消息：删除一个用户的缓存
+type InvalidationMessage struct {
+	Tenant string `json:"tenant"`
+	UserID int64  `json:"user_id"`
+}
+
+// InvalidationBus 缓存失效消息的广播（Redis Pub/Sub）
+//
+// 为什么需要？
+// 多实例部署时，每个实例都有自己的进程内 HotCache。
+// 一个实例删除了 Redis 中的缓存，其他实例的进程内缓存还是旧的，要等 TTL 过期。
+// 删除缓存的实例再发布一条失效消息，所有实例（包括自己）收到后删除进程内缓存。
+//
+// Pub/Sub 不保证送达（订阅断开期间的消息会丢失），丢失时进程内缓存随 TTL 过期，
+// 所以进程内缓存的 TTL 仍然要短。
+type InvalidationBus struct {
+	rdb     redis.UniversalClient
+	channel string
+}
+
+// NewInvalidationBus 构造函数
+func NewInvalidationBus(rdb redis.UniversalClient, channel string) *InvalidationBus {
+	return &InvalidationBus{rdb: rdb, channel: channel}
+}
+
+// Publish 发布一条失效消息
+func (b *InvalidationBus) Publish(ctx context.Context, tenant valueobject.TenantID, userID int64) error {
+	data, err := json.Marshal(InvalidationMessage{Tenant: tenant.String(), UserID: userID})
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, b.channel, data).Err()
+}
+
+// Subscribe 订阅失效消息，每条消息调用一次 fn（在后台协程中，按收到的顺序）
+//
+// 返回的函数停止订阅，并等待正在处理的消息处理完（进程退出时调用）。
+// 连接断开时 go-redis 自动重连并重新订阅；无法解析的消息跳过。
+func (b *InvalidationBus) Subscribe(ctx context.Context, fn func(ctx context.Context, msg InvalidationMessage)) func() {
+	pubsub := b.rdb.Subscribe(ctx, b.channel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for message := range pubsub.Channel() {
+			var msg InvalidationMessage
+			if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil || msg.UserID <= 0 {
+				continue
+			}
+			fn(ctx, msg)
+		}
+	}()
+	return func() {
+		_ = pubsub.Close()
+		<-done
+	}
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// defaultReadThroughLoadTimeout 回源的超时时间（回源不跟随单个请求的 context，见 Get）
+const defaultReadThroughLoadTimeout = 2 * time.Second
+
+// ReadThrough 通用的读穿透缓存：仓储查询的缓存装饰器使用
+//
+// 为什么需要？
+// CachedSocialGraphRepository 为每一种查询手写了一套"查缓存 → 回源 → 回填"，
+// 新增一个要缓存的查询就要再写一遍；而且手写的版本有两个问题：
+//  1. 缓存击穿：热点 key 过期的瞬间，所有并发请求同时回源
+//  2. 过期抖动：key 过期后第一个请求要等回源，延迟出现周期性的尖刺
+//
+// ReadThrough 把这些统一处理：
+//   - singleflight：同一个 key 同时只有一次回源，其他请求等待同一个结果
+//   - 软过期（softTTL）：条目超过 softTTL 但还没有硬过期（HotCache 的 TTL）时，
+//     直接返回旧值，同时在后台刷新；热点 key 几乎不会出现"等回源"的请求
+//
+// key 由方法名和参数组成（见 Key），租户自动加入 key。
+//
+// 只有进程内一层缓存：适合每次请求调用很多次、单次回源便宜但总量大的查询（如每个候选的帖子数）。
+type ReadThrough[V any] struct {
+	name        string
+	hot         *HotCache[readThroughEntry[V]]
+	softTTL     time.Duration
+	loadTimeout time.Duration
+	group       singleflight.Group
+	metrics     service.Metrics
+	now         func() time.Time
+}
+
+// readThroughEntry 缓存条目：值和加载时间（判断软过期）
+type readThroughEntry[V any] struct {
+	value    V
+	loadedAt time.Time
+}
+
+// NewReadThrough 构造函数
+//
+// 参数：
+// - name: 缓存名（用作监控标签）
+// - maxEntries: 最多缓存多少个条目
+// - softTTL: 超过这个时间的条目在后台刷新（仍然返回旧值）
+// - hardTTL: 超过这个时间的条目被删除，下一次查询同步回源（应该比 softTTL 长）
+// - metrics: 监控指标（可以为 nil）
+func NewReadThrough[V any](name string, maxEntries int64, softTTL, hardTTL time.Duration, metrics service.Metrics) (*ReadThrough[V], error) {
+	if softTTL <= 0 || hardTTL < softTTL {
+		return nil, fmt.Errorf("read-through cache %s: soft ttl %s must be positive and not longer than hard ttl %s", name, softTTL, hardTTL)
+	}
+	hot, err := NewHotCache[readThroughEntry[V]](name, maxEntries, hardTTL, metrics)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadThrough[V]{
+		name:        name,
+		hot:         hot,
+		softTTL:     softTTL,
+		loadTimeout: defaultReadThroughLoadTimeout,
+		metrics:     metrics,
+		now:         time.Now,
+	}, nil
+}
+
+// Key 构造缓存 key：租户 + 方法名 + 参数（如 "default:CountRecentPosts:42:7"）
+func (c *ReadThrough[V]) Key(ctx context.Context, method string, args ...any) string {
+	parts := make([]string, 0, len(args)+2)
+	parts = append(parts, valueobject.TenantFromContext(ctx).String(), method)
+	for _, arg := range args {
+		parts = append(parts, fmt.Sprint(arg))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Get 查询缓存，未命中时回源（同一个 key 同时只回源一次）
+//
+// 回源使用不可取消的 context（带 loadTimeout）：
+// 多个请求在等待同一次回源，第一个请求被取消不应该让其他请求一起失败。
+// 每个请求仍然按自己的 context 停止等待。
+//
+// 回源失败时不缓存（下一次查询重新回源）。
+func (c *ReadThrough[V]) Get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if value, ok := c.Lookup(ctx, key, load); ok {
+		return value, nil
+	}
+
+	select {
+	case result := <-c.group.DoChan(key, c.loader(ctx, key, load, "miss")):
+		if result.Err != nil {
+			var zero V
+			return zero, result.Err
+		}
+		return result.Val.(V), nil
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Lookup 只查询缓存，未命中时不回源（批量查询：先逐个查缓存，未命中的 key 由调用方一次批量回源，再用 Store 回填）
+//
+// 软过期的条目和 Get 一样：返回旧值，用 load 在后台刷新。
+func (c *ReadThrough[V]) Lookup(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, bool) {
+	entry, ok := c.hot.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if c.now().Sub(entry.loadedAt) >= c.softTTL {
+		// 软过期：返回旧值，后台刷新（正在回源时不重复刷新）
+		c.group.DoChan(key, c.loader(ctx, key, load, "refresh"))
+	}
+	return entry.value, true
+}
+
+// Store 回填缓存（调用方自己回源的结果，如批量回源）
+func (c *ReadThrough[V]) Store(key string, value V) {
+	c.hot.Set(key, readThroughEntry[V]{value: value, loadedAt: c.now()}, 1)
+}
+
+// Del 删除缓存（数据变更时调用）
+func (c *ReadThrough[V]) Del(key string) {
+	c.hot.Del(key)
+}
+
+// Close 释放缓存的后台资源
+func (c *ReadThrough[V]) Close() {
+	c.hot.Close()
+}
+
+// loader 辅助方法：回源并回填缓存（在 singleflight 中执行）
+//
+// trigger 是回源的原因（miss / refresh），和结果一起上报 MetricReadThroughLoads。
+func (c *ReadThrough[V]) loader(
+	ctx context.Context,
+	key string,
+	load func(ctx context.Context) (V, error),
+	trigger string,
+) func() (any, error) {
+	return func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.loadTimeout)
+		defer cancel()
+
+		value, err := load(loadCtx)
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		if c.metrics != nil {
+			c.metrics.IncCounter(service.MetricReadThroughLoads, 1, map[string]string{
+				"cache":   c.name,
+				"trigger": trigger,
+				"result":  result,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.Store(key, value)
+		return value, nil
+	}
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service/domain/valueobject"
+)
+
+func TestReadThrough_Singleflight(t *testing.T) {
+	c, err := NewReadThrough[int]("test", 100, time.Minute, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// 10 个请求同时未命中：只回源一次，都拿到回源的结果
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+		return 3, nil
+	}
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.Get(context.Background(), "key", load)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("loads = %d, want 1", got)
+	}
+	for i, got := range results {
+		if got != 3 {
+			t.Errorf("results[%d] = %d, want 3", i, got)
+		}
+	}
+}
+
+func TestReadThrough_SoftTTLRefresh(t *testing.T) {
+	c, err := NewReadThrough[int]("test", 100, time.Minute, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	count := 1
+	refreshed := make(chan struct{}, 1)
+	load := func(ctx context.Context) (int, error) {
+		defer func() { refreshed <- struct{}{} }()
+		return count, nil
+	}
+	if got, _ := c.Get(ctx, "key", load); got != 1 {
+		t.Fatalf("first get = %d, want 1", got)
+	}
+	<-refreshed
+	c.hot.cache.Wait()
+
+	// 软过期之后：先返回旧值，后台刷新完成后返回新值
+	count = 2
+	now = now.Add(2 * time.Minute)
+	if got, _ := c.Get(ctx, "key", load); got != 1 {
+		t.Errorf("stale get = %d, want 1", got)
+	}
+	<-refreshed
+
+	// 回源返回之后才写回缓存：等待写回完成
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.hot.cache.Wait()
+		if entry, ok := c.hot.Get("key"); ok && entry.value == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cache was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadThrough_Key(t *testing.T) {
+	c, err := NewReadThrough[int]("test", 100, time.Minute, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := valueobject.WithTenant(context.Background(), valueobject.TenantID("brand_b"))
+	if got, want := c.Key(ctx, "CountRecentPosts", int64(42), 7), "brand_b:CountRecentPosts:42:7"; got != want {
+		t.Errorf("key = %q, want %q", got, want)
+	}
+}
+
+func TestReadThrough_LookupStore(t *testing.T) {
+	c, err := NewReadThrough[int]("test", 100, time.Minute, 5*time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx := context.Background()
+	load := func(ctx context.Context) (int, error) {
+		t.Error("Lookup must not load on a miss")
+		return 0, nil
+	}
+
+	if _, ok := c.Lookup(ctx, "key", load); ok {
+		t.Fatal("Lookup() hit on an empty cache")
+	}
+	// 批量回源之后回填：之后的 Get 不再回源
+	c.Store("key", 4)
+	c.hot.cache.Wait()
+	if got, ok := c.Lookup(ctx, "key", load); !ok || got != 4 {
+		t.Errorf("Lookup() = %d, %v, want 4, true", got, ok)
+	}
+	if got, _ := c.Get(ctx, "key", load); got != 4 {
+		t.Errorf("Get() = %d, want 4", got)
+	}
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/application/service"
+	"service/domain/aggregate"
+	"service/domain/valueobject"
+)
+
+// RecommendationListCache 推荐列表缓存（生成器的结果）
+//
+// 缓存层次：进程内 HotCache → Redis（可选，多实例共享）。
+//
+// 每个用户一个 Redis Hash，field 是"策略:时间窗口"，value 是列表的 JSON；
+// 删除用户的缓存（InvalidateUser）只需要一次 DEL，不需要知道用过哪些策略。
+// Hash 的 TTL 在每次写入后重新设置；单个 field 的过期按列表的生成时间判断。
+//
+// 进程内缓存按用户保存所有策略（map 整体替换，不修改共享的 map）；
+// 同一个用户的并发写入可能丢掉其中一个策略的列表，只是多一次生成。
+//
+// 缓存的是序列化后的列表：每次 Get 都重建一份独立的聚合，
+// 调用方在列表上执行过滤、调整有效期（ExpireIn）不会影响缓存。
+type RecommendationListCache struct {
+	hot  *HotCache[map[string]*cachedRecommendationList]
+	rdb  redis.UniversalClient // 可以为 nil（只使用进程内缓存）
+	ttl  time.Duration
+	keys *KeyStrategy
+	now  func() time.Time
+}
+
+// NewRecommendationListCache 构造函数
+//
+// 参数：
+// - rdb: Redis（可以为 nil）
+// - ttl: 列表从生成开始缓存多久（进程内缓存使用同样的 TTL）
+// - maxUsers: 进程内缓存最多保存多少个用户
+func NewRecommendationListCache(
+	rdb redis.UniversalClient,
+	ttl time.Duration,
+	maxUsers int64,
+	keys *KeyStrategy,
+) (*RecommendationListCache, error) {
+	hot, err := NewHotCache[map[string]*cachedRecommendationList]("recommendation_lists", maxUsers, ttl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RecommendationListCache{hot: hot, rdb: rdb, ttl: ttl, keys: keys, now: time.Now}, nil
+}
+
+// Close 释放进程内缓存的后台资源
+func (c *RecommendationListCache) Close() {
+	c.hot.Close()
+}
+
+// Get 实现接口：逐层读取（进程内缓存没有这个策略时读 Redis）
+func (c *RecommendationListCache) Get(ctx context.Context, key service.ListCacheKey) (*aggregate.RecommendationList, error) {
+	cacheKey := c.cacheKey(key)
+	field := listField(key)
+	if lists, ok := c.hot.Get(cacheKey.Current); ok {
+		if cached := lists[field]; cached != nil && !c.expired(cached) {
+			return cached.restore(key), nil
+		}
+	}
+	if c.rdb == nil {
+		return nil, nil
+	}
+
+	data, err := c.rdb.HGet(ctx, cacheKey.Current, field).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedRecommendationList
+	if err := json.Unmarshal(data, &cached); err != nil || c.expired(&cached) {
+		return nil, nil // 格式不对（如结构升级）、已经过期都当作没有缓存
+	}
+	c.setHot(cacheKey, field, &cached)
+	return cached.restore(key), nil
+}
+
+// Set 实现接口：写入进程内缓存和 Redis（HSET + EXPIRE）
+func (c *RecommendationListCache) Set(ctx context.Context, key service.ListCacheKey, list *aggregate.RecommendationList) error {
+	cacheKey := c.cacheKey(key)
+	field := listField(key)
+	cached := newCachedRecommendationList(list)
+	c.setHot(cacheKey, field, cached)
+	if c.rdb == nil {
+		return nil
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, cacheKey.Current, field, data)
+		pipe.Expire(ctx, cacheKey.Current, c.ttl)
+		return nil
+	})
+	return err
+}
+
+// InvalidateUser 实现接口（以及 service.UserCacheInvalidator）：删除用户所有策略的推荐列表
+//
+// 用户出现在别人推荐列表中的记录不逐个查找，随 TTL 过期。
+func (c *RecommendationListCache) InvalidateUser(ctx context.Context, userID int64) error {
+	cacheKey := c.keys.Key(valueobject.TenantFromContext(ctx), userID, KeyKindRecommendationList)
+	c.hot.Del(cacheKey.Current)
+	if c.rdb == nil {
+		return nil
+	}
+	return c.keys.DeleteUserKeys(ctx, c.rdb, []CacheKey{cacheKey})
+}
+
+// setHot 辅助方法：替换进程内缓存中用户的一个策略（复制 map，不修改共享的 map）
+func (c *RecommendationListCache) setHot(cacheKey CacheKey, field string, cached *cachedRecommendationList) {
+	previous, _ := c.hot.Get(cacheKey.Current)
+	lists := make(map[string]*cachedRecommendationList, len(previous)+1)
+	for f, old := range previous {
+		if !c.expired(old) {
+			lists[f] = old
+		}
+	}
+	lists[field] = cached
+	c.hot.Set(cacheKey.Current, lists, 1)
+}
+
+// expired 辅助方法：列表是否超过缓存时间（按生成时间判断）
+func (c *RecommendationListCache) expired(cached *cachedRecommendationList) bool {
+	return c.now().Sub(cached.GeneratedAt) > c.ttl
+}
+
+// cacheKey 用户的缓存 key（按租户隔离；策略、时间窗口是 Hash 的 field）
+func (c *RecommendationListCache) cacheKey(key service.ListCacheKey) CacheKey {
+	return c.keys.Key(key.Tenant, key.UserID, KeyKindRecommendationList)
+}
+
+// listField 辅助函数：Hash 的 field（策略:时间窗口）
+func listField(key service.ListCacheKey) string {
+	return key.Strategy + ":" + strconv.Itoa(key.Days)
+}
+
+// cachedRecommendationList 缓存中的推荐列表
+type cachedRecommendationList struct {
+	GeneratedAt     time.Time                  `json:"generated_at"`
+	FollowingsTotal int                        `json:"followings_total"`
+	FollowingsUsed  int                        `json:"followings_used"`
+	Entries         []cachedRecommendationItem `json:"entries"`
+}
+
+// cachedRecommendationItem 缓存中的一条推荐
+type cachedRecommendationItem struct {
+	UserID          int64     `json:"user_id"`
+	ReasonType      int       `json:"reason_type"`
+	RelatedCount    int       `json:"related_count"`
+	Score           int       `json:"score"`
+	RecentPostCount int       `json:"recent_post_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// newCachedRecommendationList 辅助函数：推荐列表 → 缓存格式
+func newCachedRecommendationList(list *aggregate.RecommendationList) *cachedRecommendationList {
+	sampling := list.FollowingSampling()
+	cached := &cachedRecommendationList{
+		GeneratedAt:     list.GeneratedAt(),
+		FollowingsTotal: sampling.Total,
+		FollowingsUsed:  sampling.Used,
+		Entries:         make([]cachedRecommendationItem, 0, list.Count()),
+	}
+	for _, rec := range list.All() {
+		cached.Entries = append(cached.Entries, cachedRecommendationItem{
+			UserID:          rec.TargetUserID().Value(),
+			ReasonType:      int(rec.Reason().Type()),
+			RelatedCount:    rec.Reason().RelatedCount(),
+			Score:           rec.Score(),
+			RecentPostCount: rec.RecentPostCount(),
+			CreatedAt:       rec.CreatedAt(),
+			ExpiresAt:       rec.ExpiresAt(),
+		})
+	}
+	return cached
+}
+
+// restore 辅助方法：缓存格式 → 一份新的推荐列表
+func (c *cachedRecommendationList) restore(key service.ListCacheKey) *aggregate.RecommendationList {
+	forUserID, _ := valueobject.NewUserID(key.UserID)
+	recommendations := make([]*aggregate.UserRecommendation, 0, len(c.Entries))
+	for _, entry := range c.Entries {
+		targetUserID, err := valueobject.NewUserID(entry.UserID)
+		if err != nil {
+			continue
+		}
+		reason := valueobject.RestoreRecommendationReason(valueobject.ReasonType(entry.ReasonType), entry.RelatedCount)
+		recommendations = append(recommendations, aggregate.RestoreUserRecommendation(
+			targetUserID, reason, entry.Score, entry.RecentPostCount, entry.CreatedAt, entry.ExpiresAt,
+		))
+	}
+	return aggregate.RestoreRecommendationList(forUserID, recommendations, c.GeneratedAt, aggregate.FollowingSampling{
+		Total: c.FollowingsTotal,
+		Used:  c.FollowingsUsed,
+	})
+}
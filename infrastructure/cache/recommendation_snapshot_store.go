@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// RecommendationSnapshotStore 推荐列表快照存储（增量同步的基准）
+//
+// 每个用户、每个展示场景保留最近 keep 份快照（新的在前），整体作为一个值缓存：
+// 进程内 HotCache → Redis（可选，多实例共享）。
+//
+// 为什么可以放在缓存里？
+// 快照丢失（淘汰、过期、Redis 故障）时，服务返回完整列表让客户端整体替换，
+// 只损失一次增量同步的收益，不影响正确性。
+//
+// 注意：
+// - Save 是"读出 → 修改 → 写回"，同一用户的并发请求可能丢掉其中一份快照（同上，只影响收益）
+// - 只使用进程内缓存时，请求落到其他实例就找不到快照，多实例部署应该开启 Redis
+type RecommendationSnapshotStore struct {
+	hot  *HotCache[[]*service.RecommendationSnapshot]
+	rdb  redis.UniversalClient // 可以为 nil（只使用进程内缓存）
+	ttl  time.Duration
+	keep int
+	keys *KeyStrategy
+	now  func() time.Time
+}
+
+// NewRecommendationSnapshotStore 构造函数
+//
+// 参数：
+// - hot: 进程内缓存
+// - rdb: Redis（可以为 nil）
+// - ttl: 快照保留时间（超过这个时间没有同步的客户端拿到完整列表）
+// - keep: 每个用户、每个场景保留的快照数（客户端可能在几个版本之间来回，如多台设备）
+func NewRecommendationSnapshotStore(
+	hot *HotCache[[]*service.RecommendationSnapshot],
+	rdb redis.UniversalClient,
+	ttl time.Duration,
+	keep int,
+	keys *KeyStrategy,
+) *RecommendationSnapshotStore {
+	if keep <= 0 {
+		keep = 1
+	}
+	return &RecommendationSnapshotStore{hot: hot, rdb: rdb, ttl: ttl, keep: keep, keys: keys, now: time.Now}
+}
+
+// Save 实现接口：保存快照
+func (s *RecommendationSnapshotStore) Save(ctx context.Context, key service.SnapshotKey, snapshot *service.RecommendationSnapshot) error {
+	cacheKey := s.snapshotKey(key)
+	previous, err := s.load(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+
+	// 不修改缓存中共享的切片：复制一份
+	snapshots := make([]*service.RecommendationSnapshot, 0, s.keep)
+	snapshots = append(snapshots, snapshot)
+	for _, old := range previous {
+		if len(snapshots) == s.keep {
+			break
+		}
+		if old.Version == snapshot.Version || s.expired(old) {
+			continue
+		}
+		snapshots = append(snapshots, old)
+	}
+
+	s.hot.Set(cacheKey.Current, snapshots, int64(len(snapshots)))
+	if s.rdb == nil {
+		return nil
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, cacheKey.Current, data, s.ttl).Err()
+}
+
+// FindByVersion 实现接口：按版本查找快照
+func (s *RecommendationSnapshotStore) FindByVersion(ctx context.Context, key service.SnapshotKey, version string) (*service.RecommendationSnapshot, error) {
+	snapshots, err := s.load(ctx, s.snapshotKey(key))
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Version == version && !s.expired(snapshot) {
+			return snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindLatestBefore 实现接口：查找 at 之前（含）最近的一份快照
+func (s *RecommendationSnapshotStore) FindLatestBefore(ctx context.Context, key service.SnapshotKey, at time.Time) (*service.RecommendationSnapshot, error) {
+	snapshots, err := s.load(ctx, s.snapshotKey(key))
+	if err != nil {
+		return nil, err
+	}
+	// 新的在前：第一份不晚于 at 的就是最近的
+	for _, snapshot := range snapshots {
+		if !snapshot.CreatedAt.After(at) && !s.expired(snapshot) {
+			return snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// InvalidateUser 实现 service.UserCacheInvalidator：删除用户所有场景的快照（用户数据删除）
+//
+// 用户的 key 都在同一个 slot（KeyStrategy 的 hash tag），一次 DEL 删除。
+func (s *RecommendationSnapshotStore) InvalidateUser(ctx context.Context, userID int64) error {
+	tenant := valueobject.TenantFromContext(ctx)
+	keys := make([]CacheKey, 0, len(snapshotSurfaces))
+	for _, surface := range snapshotSurfaces {
+		key := s.snapshotKey(service.SnapshotKey{Tenant: tenant, UserID: userID, Surface: surface})
+		s.hot.Del(key.Current)
+		keys = append(keys, key)
+	}
+	if s.rdb == nil {
+		return nil
+	}
+	return s.keys.DeleteUserKeys(ctx, s.rdb, keys)
+}
+
+// load 辅助方法：逐层读取快照列表（都没有时返回空列表）
+func (s *RecommendationSnapshotStore) load(ctx context.Context, cacheKey CacheKey) ([]*service.RecommendationSnapshot, error) {
+	if snapshots, ok := s.hot.Get(cacheKey.Current); ok {
+		return snapshots, nil
+	}
+	if s.rdb == nil {
+		return nil, nil
+	}
+	data, err := s.keys.Get(ctx, s.rdb, cacheKey, s.ttl)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*service.RecommendationSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, nil // 格式不对（如快照结构升级）当作没有快照
+	}
+	s.hot.Set(cacheKey.Current, snapshots, int64(len(snapshots)))
+	return snapshots, nil
+}
+
+// expired 辅助方法：快照是否超过保留时间（进程内缓存的 TTL 从写入时算起，这里按快照时间再判断一次）
+func (s *RecommendationSnapshotStore) expired(snapshot *service.RecommendationSnapshot) bool {
+	return s.now().Sub(snapshot.CreatedAt) > s.ttl
+}
+
+// snapshotSurfaces 所有展示场景（删除用户数据时逐个删除）
+var snapshotSurfaces = []dto.Surface{
+	dto.SurfaceDefault,
+	dto.SurfaceHomeFeed,
+	dto.SurfaceProfileSidebar,
+	dto.SurfaceOnboarding,
+	dto.SurfacePush,
+}
+
+// snapshotKey 快照的缓存 key（按租户隔离）
+func (s *RecommendationSnapshotStore) snapshotKey(key service.SnapshotKey) CacheKey {
+	return s.keys.Key(key.Tenant, key.UserID, KeyKindSnapshot, string(key.Surface))
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/application/service"
+)
+
+// SessionStore 会话内已经返回过的推荐对象（会话去重）
+//
+// 每个会话一个 Redis Set，成员是推荐对象的用户ID；每次写入后重新设置 ttl，
+// 会话一段时间没有请求就整体过期。没有 Redis 时（本地开发）保存在进程内。
+//
+// 为什么不放进 HotCache？
+// 同一个会话的下一次请求可能落到任何实例，进程内缓存会让去重失效；
+// 而且 HotCache 允许淘汰，去重记录被淘汰就会重复返回。
+//
+// 用户数据删除时不清理：记录只是推荐对象的用户ID，会话结束后很快过期。
+type SessionStore struct {
+	rdb  redis.UniversalClient // 可以为 nil（只保存在进程内）
+	ttl  time.Duration
+	keys *KeyStrategy
+	now  func() time.Time
+
+	mu        sync.Mutex
+	local     map[string]*localSession // rdb 为 nil 时使用
+	lastSweep time.Time
+}
+
+// localSession 进程内的一个会话
+type localSession struct {
+	targets   map[int64]bool
+	expiresAt time.Time
+}
+
+// NewSessionStore 构造函数
+//
+// 参数：
+// - rdb: Redis（可以为 nil）
+// - ttl: 会话多久没有请求后过期
+func NewSessionStore(rdb redis.UniversalClient, ttl time.Duration, keys *KeyStrategy) *SessionStore {
+	return &SessionStore{
+		rdb:   rdb,
+		ttl:   ttl,
+		keys:  keys,
+		now:   time.Now,
+		local: make(map[string]*localSession),
+	}
+}
+
+// Returned 实现接口：会话内已经返回过的推荐对象
+func (s *SessionStore) Returned(ctx context.Context, key service.SessionKey) ([]int64, error) {
+	cacheKey := s.sessionKey(key)
+	if s.rdb == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		session := s.local[cacheKey]
+		if session == nil || !s.now().Before(session.expiresAt) {
+			return nil, nil
+		}
+		targets := make([]int64, 0, len(session.targets))
+		for target := range session.targets {
+			targets = append(targets, target)
+		}
+		return targets, nil
+	}
+
+	members, err := s.rdb.SMembers(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]int64, 0, len(members))
+	for _, member := range members {
+		target, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// Add 实现接口：记录本次返回的推荐对象（一个 pipeline）
+func (s *SessionStore) Add(ctx context.Context, key service.SessionKey, targetUserIDs []int64) error {
+	if len(targetUserIDs) == 0 {
+		return nil
+	}
+	cacheKey := s.sessionKey(key)
+	if s.rdb == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		now := s.now()
+		s.sweepLocked(now)
+		session := s.local[cacheKey]
+		if session == nil || !now.Before(session.expiresAt) {
+			session = &localSession{targets: make(map[int64]bool, len(targetUserIDs))}
+			s.local[cacheKey] = session
+		}
+		for _, target := range targetUserIDs {
+			session.targets[target] = true
+		}
+		session.expiresAt = now.Add(s.ttl)
+		return nil
+	}
+
+	members := make([]any, 0, len(targetUserIDs))
+	for _, target := range targetUserIDs {
+		members = append(members, strconv.FormatInt(target, 10))
+	}
+	_, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, cacheKey, members...)
+		pipe.Expire(ctx, cacheKey, s.ttl)
+		return nil
+	})
+	return err
+}
+
+// sweepLocked 辅助方法：删除进程内已经过期的会话（每个 ttl 最多一次，调用方持有锁）
+func (s *SessionStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+	for cacheKey, session := range s.local {
+		if !now.Before(session.expiresAt) {
+			delete(s.local, cacheKey)
+		}
+	}
+}
+
+// sessionKey 辅助方法：会话的 key（按租户隔离，和用户的其他 key 在同一个 slot）
+//
+// 新增的数据种类没有上一版格式的数据，只使用当前格式。
+func (s *SessionStore) sessionKey(key service.SessionKey) string {
+	return s.keys.Key(key.Tenant, key.UserID, KeyKindSession, string(key.Surface), key.SessionID).Current
+}
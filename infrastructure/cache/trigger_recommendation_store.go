@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// TriggerRecommendationStore 触发式推荐存储（推送管道的数据来源）
+//
+// 每个用户一个 Redis Hash：field 是"触发类型:被推荐用户"，value 是 JSON 格式的 TriggerEntry。
+// 同一条推荐重复写入（事件重试）只是覆盖，不需要先读再写。
+//
+// 为什么不用 HotCache？
+// 事件在一个实例上扇出写入，推送管道的请求可能落到任何实例，
+// 进程内缓存会让其他实例读到旧的列表。没有 Redis 时（本地开发）保存在进程内。
+//
+// 过期：
+// - 每条推荐有自己的过期时间，读取时删除已经过期的 field
+// - 整个 Hash 每次写入后重新设置 ttl（应该不短于最长的触发有效期），不再有新推荐的用户自然过期
+type TriggerRecommendationStore struct {
+	rdb  redis.UniversalClient // 可以为 nil（只保存在进程内）
+	ttl  time.Duration
+	keys *KeyStrategy
+	now  func() time.Time
+
+	mu    sync.Mutex
+	local map[string]map[string]service.TriggerEntry // rdb 为 nil 时使用
+}
+
+// NewTriggerRecommendationStore 构造函数
+//
+// 参数：
+// - rdb: Redis（可以为 nil）
+// - ttl: 用户的列表多久没有新推荐后整体删除
+func NewTriggerRecommendationStore(rdb redis.UniversalClient, ttl time.Duration, keys *KeyStrategy) *TriggerRecommendationStore {
+	return &TriggerRecommendationStore{
+		rdb:   rdb,
+		ttl:   ttl,
+		keys:  keys,
+		now:   time.Now,
+		local: make(map[string]map[string]service.TriggerEntry),
+	}
+}
+
+// Add 实现接口：给一批用户各添加同一条推荐（一个 pipeline）
+func (s *TriggerRecommendationStore) Add(ctx context.Context, userIDs []int64, entry service.TriggerEntry) error {
+	tenant := valueobject.TenantFromContext(ctx)
+	field := triggerField(entry)
+	if s.rdb == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, userID := range userIDs {
+			key := s.triggerKey(tenant, userID)
+			if s.local[key] == nil {
+				s.local[key] = make(map[string]service.TriggerEntry)
+			}
+			s.local[key][field] = entry
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			key := s.triggerKey(tenant, userID)
+			pipe.HSet(ctx, key, field, data)
+			pipe.Expire(ctx, key, s.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// List 实现接口：用户的触发式推荐（顺便删除已经过期的）
+func (s *TriggerRecommendationStore) List(ctx context.Context, userID int64) ([]service.TriggerEntry, error) {
+	key := s.triggerKey(valueobject.TenantFromContext(ctx), userID)
+	now := s.now()
+	if s.rdb == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		entries := make([]service.TriggerEntry, 0, len(s.local[key]))
+		for field, entry := range s.local[key] {
+			if !now.Before(entry.ExpiresAt) {
+				delete(s.local[key], field)
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+
+	values, err := s.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]service.TriggerEntry, 0, len(values))
+	var expired []string
+	for field, value := range values {
+		var entry service.TriggerEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil || !now.Before(entry.ExpiresAt) {
+			expired = append(expired, field) // 格式不对（如结构升级）也当作过期
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(expired) > 0 {
+		_ = s.rdb.HDel(ctx, key, expired...).Err()
+	}
+	return entries, nil
+}
+
+// InvalidateUser 实现 service.UserCacheInvalidator：删除用户的触发式推荐（用户数据删除）
+//
+// 用户作为被推荐对象出现在别人的列表中的推荐不在这里删除，读取时按删除记录过滤。
+func (s *TriggerRecommendationStore) InvalidateUser(ctx context.Context, userID int64) error {
+	tenant := valueobject.TenantFromContext(ctx)
+	if s.rdb == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.local, s.triggerKey(tenant, userID))
+		return nil
+	}
+	return s.keys.DeleteUserKeys(ctx, s.rdb, []CacheKey{s.keys.Key(tenant, userID, KeyKindTrigger)})
+}
+
+// triggerKey 辅助方法：用户的 key（按租户隔离）
+//
+// 新增的数据种类没有上一版格式的数据，只使用当前格式。
+func (s *TriggerRecommendationStore) triggerKey(tenant valueobject.TenantID, userID int64) string {
+	return s.keys.Key(tenant, userID, KeyKindTrigger).Current
+}
+
+// triggerField 辅助函数：Hash 中的 field（同一个被推荐用户的同一种触发只保留一条）
+func triggerField(entry service.TriggerEntry) string {
+	return fmt.Sprintf("%s:%d", entry.Trigger, entry.SubjectUserID)
+}
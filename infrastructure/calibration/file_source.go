@@ -0,0 +1,178 @@
+// Package calibration 分数校准参数的加载（application/service.ScoreCalibrationSource 的实现）
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// ErrMissingVersion 校准文件没有版本
+var ErrMissingVersion = errors.New("calibration: missing version")
+
+// fileFormat 校准文件的格式（YAML）
+//
+//	version: "2024-06-01"
+//	default:
+//	  platt: {a: -0.08, b: 4}
+//	reasons:
+//	  followed_by_following:
+//	    points:
+//	      - {raw: 0, calibrated: 0.01}
+//	      - {raw: 30, calibrated: 0.05}
+//	      - {raw: 100, calibrated: 0.2}
+type fileFormat struct {
+	Version string                     `yaml:"version"`
+	Default *calibrationSpec           `yaml:"default"` // 为空时没有单独配置的理由类型不校准
+	Reasons map[string]calibrationSpec `yaml:"reasons"` // 推荐理由类型 → 校准
+}
+
+// calibrationSpec 一个校准（platt 和 points 二选一）
+type calibrationSpec struct {
+	Platt *struct {
+		A float64 `yaml:"a"`
+		B float64 `yaml:"b"`
+	} `yaml:"platt"`
+	Points []struct {
+		Raw        int     `yaml:"raw"`
+		Calibrated float64 `yaml:"calibrated"`
+	} `yaml:"points"`
+}
+
+// FileSource 从本地文件加载分数校准参数，文件变化时热加载
+//
+// 为什么是文件？
+// 校准参数由离线任务按周期重新拟合，产出一个文件，由配置下发系统推到每台机器。
+// 和 mtls.Reloader 一样定期检查文件的修改时间，不需要重启服务。
+//
+// 加载失败（如文件写到一半、参数不合法）时保留旧的参数，下次检查再试：
+// 宁可多用一会儿旧版本，也不能让一部分请求没有校准分数。
+type FileSource struct {
+	path     string
+	interval time.Duration
+	current  atomic.Pointer[service.ScoreCalibrations]
+
+	mu      sync.Mutex
+	modTime time.Time
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewFileSource 构造函数：立即加载一次，并启动后台检查
+//
+// 参数：
+// - path: 校准文件
+// - interval: 检查文件变化的间隔（为 0 时 1 分钟）
+func NewFileSource(path string, interval time.Duration) (*FileSource, error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	s := &FileSource{path: path, interval: interval, stop: make(chan struct{})}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	go s.watch()
+	return s, nil
+}
+
+// ScoreCalibrations 实现 service.ScoreCalibrationSource：当前生效的校准参数
+func (s *FileSource) ScoreCalibrations() *service.ScoreCalibrations {
+	return s.current.Load()
+}
+
+// Reload 重新加载校准文件（文件没有变化时跳过）
+func (s *FileSource) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("calibration: %w", err)
+	}
+	if s.current.Load() != nil && !info.ModTime().After(s.modTime) {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("calibration: %w", err)
+	}
+	calibrations, err := parse(data)
+	if err != nil {
+		return err
+	}
+	s.current.Store(calibrations)
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// Close 停止后台检查
+func (s *FileSource) Close() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+// watch 辅助方法：定期检查校准文件
+func (s *FileSource) watch() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			_ = s.Reload() // 失败时保留旧参数
+		}
+	}
+}
+
+// parse 辅助函数：校准文件 → 校准参数（任何一个校准不合法都返回错误）
+func parse(data []byte) (*service.ScoreCalibrations, error) {
+	var file fileFormat
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("calibration: %w", err)
+	}
+	if file.Version == "" {
+		return nil, ErrMissingVersion
+	}
+	calibrations := &service.ScoreCalibrations{
+		Version:  file.Version,
+		ByReason: make(map[string]valueobject.ScoreCalibration, len(file.Reasons)),
+	}
+	if file.Default != nil {
+		calibration, err := file.Default.build()
+		if err != nil {
+			return nil, fmt.Errorf("calibration: default: %w", err)
+		}
+		calibrations.Default = calibration
+	}
+	for reasonType, spec := range file.Reasons {
+		calibration, err := spec.build()
+		if err != nil {
+			return nil, fmt.Errorf("calibration: %s: %w", reasonType, err)
+		}
+		calibrations.ByReason[reasonType] = calibration
+	}
+	return calibrations, nil
+}
+
+// build 辅助方法：配置 → 值对象
+func (spec calibrationSpec) build() (valueobject.ScoreCalibration, error) {
+	if (spec.Platt != nil) == (len(spec.Points) > 0) {
+		return valueobject.ScoreCalibration{}, fmt.Errorf("%w: exactly one of platt and points is required", valueobject.ErrInvalidCalibration)
+	}
+	if spec.Platt != nil {
+		return valueobject.NewPlattCalibration(spec.Platt.A, spec.Platt.B)
+	}
+	points := make([]valueobject.CalibrationPoint, 0, len(spec.Points))
+	for _, point := range spec.Points {
+		points = append(points, valueobject.CalibrationPoint{Raw: point.Raw, Calibrated: point.Calibrated})
+	}
+	return valueobject.NewPiecewiseCalibration(points)
+}
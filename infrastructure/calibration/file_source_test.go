@@ -0,0 +1,70 @@
+package calibration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSource_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibration.yaml")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write(`
+version: v1
+reasons:
+  followed_by_following:
+    points:
+      - {raw: 0, calibrated: 0}
+      - {raw: 100, calibrated: 0.5}
+`, now.Add(-time.Hour))
+
+	s, err := NewFileSource(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	first := s.ScoreCalibrations()
+	if first.Version != "v1" || first.ByReason["followed_by_following"].Calibrate(50) != 0.25 {
+		t.Fatalf("calibrations = %+v, want v1 with 50 → 0.25", first)
+	}
+	if !first.Default.IsZero() {
+		t.Errorf("default calibration should be zero when not configured")
+	}
+
+	// 不合法的参数（分数高的校准得更低）：保留旧版本
+	write(`
+version: v2
+default:
+  points:
+    - {raw: 0, calibrated: 0.5}
+    - {raw: 100, calibrated: 0.1}
+`, now.Add(-time.Minute))
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload with invalid calibration should fail")
+	}
+	if s.ScoreCalibrations().Version != "v1" {
+		t.Errorf("version = %s after failed reload, want v1", s.ScoreCalibrations().Version)
+	}
+
+	// 合法的新版本：替换
+	write(`
+version: v3
+default:
+  platt: {a: -0.1, b: 5}
+`, now)
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.ScoreCalibrations(); got.Version != "v3" || got.Default.Calibrate(50) != 0.5 {
+		t.Errorf("calibrations = %+v, want v3 with 50 → 0.5", got)
+	}
+}
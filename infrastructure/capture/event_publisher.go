@@ -0,0 +1,44 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"service/domain/event"
+)
+
+// RecordWriter 一条记录的去处（FileSink、TopicSink）
+type RecordWriter interface {
+	Write(ctx context.Context, record []byte) error
+}
+
+// EventPublisher 领域事件的发布者：每个事件写成一行 JSON
+//
+// 使用方式：
+//
+//	capture.NewEventPublisher(capture.NewTopicSink(kafkaProducer, "recommendation-events"))
+type EventPublisher struct {
+	sink RecordWriter
+}
+
+// NewEventPublisher 构造函数
+func NewEventPublisher(sink RecordWriter) *EventPublisher {
+	return &EventPublisher{sink: sink}
+}
+
+// eventRecord 一个事件的记录格式（订阅方按 name 区分事件类型）
+type eventRecord struct {
+	Name       string      `json:"name"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Event      event.Event `json:"event"`
+}
+
+// Publish 实现 event.Publisher
+func (p *EventPublisher) Publish(ctx context.Context, e event.Event) error {
+	record, err := json.Marshal(eventRecord{Name: e.Name(), OccurredAt: e.OccurredAt(), Event: e})
+	if err != nil {
+		return err
+	}
+	return p.sink.Write(ctx, record)
+}
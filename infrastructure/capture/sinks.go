@@ -0,0 +1,71 @@
+// Package capture 流量抓取结果的去处（本地文件、消息队列的 topic）
+package capture
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileSink 写入本地文件：每天一个文件（capture-2006-01-02.jsonl），每条记录一行
+//
+// 抓取只在排查问题时短时间开启、采样比例很低，
+// 每次写入都打开、追加、关闭文件，不需要管理文件句柄和轮转。
+type FileSink struct {
+	dir string
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewFileSink 构造函数（目录不存在时创建）
+func NewFileSink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileSink{dir: dir, now: time.Now}, nil
+}
+
+// Write 实现 handler.CaptureSink
+func (s *FileSink) Write(ctx context.Context, record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 抓取的数据包含用户ID：只有服务自己的账号可以读写
+	path := filepath.Join(s.dir, "capture-"+s.now().Format("2006-01-02")+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(record, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Publisher 消息队列生产者（只需要发送一条消息的能力）
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// TopicSink 发送到消息队列的 topic（多实例的抓取结果集中到一处）
+//
+// 使用方式：
+//
+//	capture.NewTopicSink(kafkaProducer, "recommendation-traffic-capture")
+type TopicSink struct {
+	publisher Publisher
+	topic     string
+}
+
+// NewTopicSink 构造函数
+func NewTopicSink(publisher Publisher, topic string) *TopicSink {
+	return &TopicSink{publisher: publisher, topic: topic}
+}
+
+// Write 实现 handler.CaptureSink
+func (s *TopicSink) Write(ctx context.Context, record []byte) error {
+	return s.publisher.Publish(ctx, s.topic, record)
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+
+	"service/application/service"
+	"service/infrastructure/cache"
+)
+
+// CachedReasonTextConfigClient 推荐理由配置服务客户端装饰器：缓存文案
+//
+// 为什么需要？
+// 每条展示的推荐都要同步调用一次配置服务（GetReasonText），一页 20 条就是 20 次 HTTP 调用；
+// 而文案只和（推荐理由类型、人数、语言）有关，组合很少，运营修改的频率以分钟计。
+//
+// 缓存策略（cache.ReadThrough）：
+//   - key：租户 + 推荐理由类型 + 人数 + 语言 + 文案版本
+//   - 超过 softTTL 的文案仍然直接返回，同时在后台刷新（stale-while-revalidate），
+//     运营修改的文案最多 softTTL 之后生效，请求不会等待配置服务
+//   - 超过 hardTTL 的文案删除，下一次查询同步调用配置服务
+//   - 配置服务返回空文案（没有配置）也缓存；调用失败不缓存，由上层降级到本地文案
+//
+// 监控（标签 cache 为构造时的 name）：
+// - MetricHotCacheRequests：hit / miss，计算命中率
+// - MetricReadThroughLoads：miss / refresh 的回源次数和结果
+//
+// 发现页的分栏（GetSectionLayout）不缓存，直接转发给被装饰的客户端。
+//
+// 使用示例：
+//
+//	texts, _ := cache.NewReadThrough[string]("reason_texts", 10_000, time.Minute, 30*time.Minute, metrics)
+//	reasonClient := NewCachedReasonTextConfigClient(httpClient, texts)
+type CachedReasonTextConfigClient struct {
+	next  service.ReasonTextConfigClient
+	texts *cache.ReadThrough[string]
+}
+
+// NewCachedReasonTextConfigClient 构造函数
+func NewCachedReasonTextConfigClient(next service.ReasonTextConfigClient, texts *cache.ReadThrough[string]) *CachedReasonTextConfigClient {
+	return &CachedReasonTextConfigClient{next: next, texts: texts}
+}
+
+// GetReasonText 实现 service.ReasonTextConfigClient：先查缓存，未命中时调用配置服务
+func (c *CachedReasonTextConfigClient) GetReasonText(ctx context.Context, reasonType string, count int, locale string, variant string) (string, error) {
+	key := c.texts.Key(ctx, "GetReasonText", reasonType, count, locale, variant)
+	return c.texts.Get(ctx, key, func(ctx context.Context) (string, error) {
+		return c.next.GetReasonText(ctx, reasonType, count, locale, variant)
+	})
+}
+
+// GetReasonTexts 实现 service.ReasonTextConfigClient：先逐个查缓存，未命中的请求一次批量获取
+//
+// 批量获取的结果回填缓存（没有配置文案的请求回填空字符串）；批量获取失败时不回填，返回错误。
+func (c *CachedReasonTextConfigClient) GetReasonTexts(
+	ctx context.Context,
+	requests []service.ReasonTextRequest,
+) (map[service.ReasonTextRequest]string, error) {
+	result := make(map[service.ReasonTextRequest]string, len(requests))
+	var misses []service.ReasonTextRequest
+	for _, request := range requests {
+		key := c.texts.Key(ctx, "GetReasonText", request.ReasonType, request.Count, request.Locale, request.Variant)
+		text, ok := c.texts.Lookup(ctx, key, func(ctx context.Context) (string, error) {
+			return c.next.GetReasonText(ctx, request.ReasonType, request.Count, request.Locale, request.Variant)
+		})
+		if ok {
+			result[request] = text
+		} else {
+			misses = append(misses, request)
+		}
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := c.next.GetReasonTexts(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range misses {
+		result[request] = loaded[request]
+		c.texts.Store(c.texts.Key(ctx, "GetReasonText", request.ReasonType, request.Count, request.Locale, request.Variant), loaded[request])
+	}
+	return result, nil
+}
+
+// GetSectionLayout 实现 service.SectionLayoutClient：被装饰的客户端不支持分栏配置时返回空（使用默认分栏）
+func (c *CachedReasonTextConfigClient) GetSectionLayout(ctx context.Context, surface string, locale string) ([]service.SectionLayout, error) {
+	layouts, ok := c.next.(service.SectionLayoutClient)
+	if !ok {
+		return nil, nil
+	}
+	return layouts.GetSectionLayout(ctx, surface, locale)
+}
+
+// Close 释放缓存的后台资源
+func (c *CachedReasonTextConfigClient) Close() {
+	c.texts.Close()
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"service/application/service"
+	"service/infrastructure/cache"
+)
+
+// countingReasonClient 测试用配置服务：文案为 text + 理由类型，统计单个、批量接口的调用次数
+type countingReasonClient struct {
+	mu          sync.Mutex
+	text        string
+	batchErr    error
+	singleCalls int
+	batchCalls  int
+}
+
+func (c *countingReasonClient) GetReasonText(ctx context.Context, reasonType string, count int, locale string, variant string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.singleCalls++
+	return c.text + reasonType, nil
+}
+
+func (c *countingReasonClient) GetReasonTexts(ctx context.Context, requests []service.ReasonTextRequest) (map[service.ReasonTextRequest]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batchCalls++
+	if c.batchErr != nil {
+		return nil, c.batchErr
+	}
+	result := make(map[service.ReasonTextRequest]string, len(requests))
+	for _, request := range requests {
+		result[request] = c.text + request.ReasonType
+	}
+	return result, nil
+}
+
+func (c *countingReasonClient) setText(text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text = text
+}
+
+func (c *countingReasonClient) calls() (single, batch int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.singleCalls, c.batchCalls
+}
+
+func newTestCachedReasonClient(t *testing.T, next service.ReasonTextConfigClient, softTTL time.Duration) *CachedReasonTextConfigClient {
+	t.Helper()
+	texts, err := cache.NewReadThrough[string]("reason_texts", 100, softTTL, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCachedReasonTextConfigClient(next, texts)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestCachedReasonTextConfigClient_Batch(t *testing.T) {
+	next := &countingReasonClient{text: "v1:"}
+	c := newTestCachedReasonClient(t, next, time.Minute)
+	ctx := context.Background()
+
+	if got, _ := c.GetReasonText(ctx, "mutual", 1, "zh-CN", ""); got != "v1:mutual" {
+		t.Fatalf("GetReasonText = %q, want v1:mutual", got)
+	}
+	waitForCachedText(t, c, service.ReasonTextRequest{ReasonType: "mutual", Count: 1, Locale: "zh-CN"}, "v1:mutual")
+
+	// 已缓存的请求不再回源，未命中的请求一次批量获取
+	requests := []service.ReasonTextRequest{
+		{ReasonType: "mutual", Count: 1, Locale: "zh-CN"},
+		{ReasonType: "popular", Count: 3, Locale: "zh-CN"},
+		{ReasonType: "popular", Count: 3, Locale: "en-US"},
+	}
+	texts, err := c.GetReasonTexts(ctx, requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(texts) != 3 || texts[requests[0]] != "v1:mutual" || texts[requests[2]] != "v1:popular" {
+		t.Errorf("GetReasonTexts = %v", texts)
+	}
+	if single, batch := next.calls(); single != 1 || batch != 1 {
+		t.Errorf("upstream calls = %d single / %d batch, want 1 / 1", single, batch)
+	}
+
+	// 批量获取的结果已经回填：再次请求不回源
+	waitForCachedText(t, c, requests[2], "v1:popular")
+	if _, err := c.GetReasonTexts(ctx, requests); err != nil {
+		t.Fatal(err)
+	}
+	if _, batch := next.calls(); batch != 1 {
+		t.Errorf("batch calls = %d after backfill, want 1", batch)
+	}
+}
+
+func TestCachedReasonTextConfigClient_BatchErrorNotCached(t *testing.T) {
+	next := &countingReasonClient{text: "v1:", batchErr: errors.New("config service down")}
+	c := newTestCachedReasonClient(t, next, time.Minute)
+	requests := []service.ReasonTextRequest{{ReasonType: "mutual", Count: 1}}
+
+	if _, err := c.GetReasonTexts(context.Background(), requests); err == nil {
+		t.Fatal("expected the batch error")
+	}
+	next.mu.Lock()
+	next.batchErr = nil
+	next.mu.Unlock()
+	if texts, err := c.GetReasonTexts(context.Background(), requests); err != nil || texts[requests[0]] != "v1:mutual" {
+		t.Errorf("GetReasonTexts after recovery = %v, %v, want a fresh load", texts, err)
+	}
+}
+
+func TestCachedReasonTextConfigClient_StaleWhileRevalidate(t *testing.T) {
+	next := &countingReasonClient{text: "v1:"}
+	c := newTestCachedReasonClient(t, next, 20*time.Millisecond)
+	ctx := context.Background()
+
+	if _, err := c.GetReasonText(ctx, "mutual", 1, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	cached := service.ReasonTextRequest{ReasonType: "mutual", Count: 1}
+	waitForCachedText(t, c, cached, "v1:mutual")
+
+	// 运营修改了文案；软过期之后先返回旧文案，同时在后台刷新
+	next.setText("v2:")
+	time.Sleep(30 * time.Millisecond)
+	if got, _ := c.GetReasonText(ctx, "mutual", 1, "", ""); got != "v1:mutual" {
+		t.Errorf("stale GetReasonText = %q, want the old text while refreshing", got)
+	}
+	waitForCachedText(t, c, cached, "v2:mutual")
+}
+
+// waitForCachedText 等待缓存写入（缓存异步写入）：直到缓存中的文案是 want（只查缓存，不触发刷新）
+func waitForCachedText(t *testing.T, c *CachedReasonTextConfigClient, request service.ReasonTextRequest, want string) {
+	t.Helper()
+	ctx := context.Background()
+	key := c.texts.Key(ctx, "GetReasonText", request.ReasonType, request.Count, request.Locale, request.Variant)
+	noRefresh := func(context.Context) (string, error) { return "", errors.New("lookup only") }
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, ok := c.texts.Lookup(ctx, key, noRefresh)
+		if ok && got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cached text for %+v = %q, want %q", request, got, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
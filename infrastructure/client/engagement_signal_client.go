@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// EngagementSignalHTTPClient HTTP 客户端：调用实时计数服务获取最近一小时的互动
+//
+// 这是领域层 EngagementSignalRepository 接口的实现（和 TrustSafetyHTTPClient 一样，
+// 领域服务在打分时就需要这份数据）。
+//
+// 容错设计：
+// - 超时比风控服务更短：互动加分只是锦上添花，不能拖慢推荐主流程
+// - 错误返回：由领域服务决定如何降级（当前策略：不加分）
+type EngagementSignalHTTPClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewEngagementSignalHTTPClient 构造函数
+func NewEngagementSignalHTTPClient(baseURL string, opts ...HTTPClientOption) repository.EngagementSignalRepository {
+	return &EngagementSignalHTTPClient{
+		baseURL: baseURL,
+		httpClient: ApplyHTTPClientOptions(&http.Client{
+			Timeout: 200 * time.Millisecond, // 计数服务是内存读取，慢了说明有问题，直接放弃
+		}, opts),
+	}
+}
+
+// GetRecentEngagement 实现接口：批量获取最近一小时的互动
+//
+// API 设计示例：
+// POST /api/v1/engagement/recent
+//
+// 请求示例：
+//
+//	{"user_ids": [1, 2, 3], "window": "1h"}
+//
+// 响应示例（没有互动的用户不返回）：
+//
+//	{
+//	  "code": 0,
+//	  "message": "success",
+//	  "data": {
+//	    "counts": {"1": {"likes": 12, "comments": 3}}
+//	  }
+//	}
+func (c *EngagementSignalHTTPClient) GetRecentEngagement(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID]valueobject.RecentEngagement, error) {
+	if len(userIDs) == 0 {
+		return map[valueobject.UserID]valueobject.RecentEngagement{}, nil
+	}
+
+	// 构造请求体
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.Value())
+	}
+	payload, err := json.Marshal(map[string]any{"user_ids": ids, "window": "1h"})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request failed: %w", err)
+	}
+
+	// 创建请求
+	url := fmt.Sprintf("%s/api/v1/engagement/recent", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// 发送请求
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 检查状态码
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// 解析响应
+	var response struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Counts map[string]struct {
+				Likes    int `json:"likes"`
+				Comments int `json:"comments"`
+			} `json:"counts"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	// 检查业务状态码
+	if response.Code != 0 {
+		return nil, fmt.Errorf("api error: code=%d, message=%s", response.Code, response.Message)
+	}
+
+	// 转换为领域对象
+	result := make(map[valueobject.UserID]valueobject.RecentEngagement, len(response.Data.Counts))
+	for rawID, counts := range response.Data.Counts {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			continue // 容错：跳过无法解析的ID
+		}
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		result[userID] = valueobject.RecentEngagement{Likes: counts.Likes, Comments: counts.Comments}
+	}
+
+	return result, nil
+}
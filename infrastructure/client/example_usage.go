@@ -1,9 +1,12 @@
 package client
 
 import (
+	"gorm.io/gorm"
+
 	"service/application/service"
 	domainService "service/domain/service"
-	"service/infrastructure/repository"
+	"service/domain/valueobject"
+	"service/infrastructure/persistence"
 )
 
 // ExampleWireRecommendationService 示例：如何组装推荐服务
@@ -11,15 +14,17 @@ import (
 // 这个示例展示了如何在实际项目中组装依赖。
 //
 // 在真实项目中，通常使用依赖注入框架（如 Wire、Dig）来自动化这个过程。
-func ExampleWireRecommendationService() *service.RecommendationService {
-	// 1. 创建仓储实现（假设已经实现）
-	socialGraphRepo := repository.NewMySQLSocialGraphRepository( /* db */ )
-	contentRepo := repository.NewMySQLContentRepository( /* db */ )
+func ExampleWireRecommendationService(db *gorm.DB) *service.RecommendationService {
+	// 1. 创建仓储实现
+	socialGraphRepo := persistence.NewSocialGraphRepository(db)
+	contentRepo := persistence.NewContentRepository(db)
 
-	// 2. 创建领域服务
+	// 2. 创建领域服务（开启风控过滤：排除中风险以上的账号）
+	trustRepo := NewTrustSafetyHTTPClient("http://trust-service:8080")
 	generator := domainService.NewRecommendationGenerator(
 		socialGraphRepo,
 		contentRepo,
+		domainService.WithTrustFilter(trustRepo, valueobject.RiskMedium),
 	)
 
 	// 3. 创建 RPC 客户端（假设已经实现）
@@ -37,6 +42,7 @@ func ExampleWireRecommendationService() *service.RecommendationService {
 		generator,
 		socialGraphRepo,
 		contentRepo,
+		nil, // contentClient：使用本地数据库
 		userRPCClient,
 		reasonConfigClient, // 可以传 nil
 	)
@@ -51,7 +57,7 @@ func ExampleGradualMigration() {
 	// 阶段1：不使用配置服务（当前状态）
 	// 所有文案使用本地逻辑生成
 	_ = service.NewRecommendationService(
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
 		nil, // reasonConfigClient = nil
 	)
 
@@ -65,7 +71,7 @@ func ExampleGradualMigration() {
 		reasonConfigClient = nil
 	}
 	_ = service.NewRecommendationService(
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
 		reasonConfigClient,
 	)
 
@@ -73,7 +79,7 @@ func ExampleGradualMigration() {
 	// 所有用户都使用配置服务，但保留降级逻辑
 	reasonConfigClient = NewReasonTextConfigHTTPClient("http://config-service:8080")
 	_ = service.NewRecommendationService(
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
 		reasonConfigClient,
 	)
 
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"service/infrastructure/resilience"
+)
+
+// RetryPolicy HTTP 调用的重试、对冲策略（实际能否重试、对冲还要看 Governor 的预算）
+type RetryPolicy struct {
+	MaxRetries int           // 最多重试次数
+	Backoff    time.Duration // 第 n 次重试前等待 n × Backoff
+	HedgeDelay time.Duration // 首次调用超过这个时间还没返回时发出对冲请求，为 0 时不对冲
+}
+
+// WithGovernor 由下游治理器管理这个 HTTP 客户端的调用
+//
+// - 首次调用前询问 Governor.Allow：依赖不健康时本地直接失败（resilience.ErrThrottled）
+// - 失败（网络错误、5xx、429）时按 RetryPolicy 重试，每次重试前询问重试预算
+// - 配置了 HedgeDelay 时，首次调用慢于这个时间且对冲预算允许，发出对冲请求，先成功的为准
+// - 每次调用的结果都记录到 Governor（调用方自己取消的不记录）
+//
+// 只有没有请求体的 GET、HEAD 会重试和对冲（幂等且可以重放）。
+// http.Client 的 Timeout 覆盖整个过程（包括重试），重试不会让调用方等得更久。
+//
+// 包装的是当前的 Transport，所以要放在 WithTransport 之后：
+//
+//	client.NewTrustSafetyHTTPClient(url,
+//		client.WithTransport(mtls.HTTPTransport(reloader, "trust.internal", "spiffe://example.org/trust-service")),
+//		client.WithGovernor(governor, "trust", policy),
+//	)
+func WithGovernor(governor *resilience.Governor, dependency string, policy RetryPolicy) HTTPClientOption {
+	return func(c *http.Client) {
+		next := c.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.Transport = &governedTransport{
+			next:       next,
+			governor:   governor,
+			dependency: dependency,
+			policy:     policy,
+		}
+	}
+}
+
+// governedTransport 由 Governor 管理的 http.RoundTripper
+type governedTransport struct {
+	next       http.RoundTripper
+	governor   *resilience.Governor
+	dependency string
+	policy     RetryPolicy
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *governedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.governor.Allow(t.dependency) {
+		return nil, resilience.ErrThrottled
+	}
+
+	replayable := req.Body == nil && (req.Method == http.MethodGet || req.Method == http.MethodHead)
+	resp, err := t.primary(req, replayable)
+
+	for retry := 1; replayable && retry <= t.policy.MaxRetries && failed(resp, err); retry++ {
+		if req.Context().Err() != nil || !t.governor.AllowRetry(t.dependency) {
+			break
+		}
+		select {
+		case <-time.After(time.Duration(retry) * t.policy.Backoff):
+		case <-req.Context().Done():
+			return resp, err
+		}
+		closeBody(resp)
+		resp, err = t.send(req, resilience.AttemptRetry)
+	}
+	return resp, err
+}
+
+// hedgeResult 一个并发调用的结果（index 对应 cancels 中的下标）
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// primary 首次调用：需要时发出对冲请求，返回先成功的那个（都失败时返回最后一个）
+func (t *governedTransport) primary(req *http.Request, replayable bool) (*http.Response, error) {
+	if t.policy.HedgeDelay <= 0 || !replayable {
+		return t.send(req, resilience.AttemptPrimary)
+	}
+
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	launch := func(attempt resilience.Attempt) {
+		ctx, cancel := context.WithCancel(req.Context())
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := t.send(req.Clone(ctx), attempt)
+			results <- hedgeResult{index: index, resp: resp, err: err}
+		}()
+	}
+
+	launch(resilience.AttemptPrimary)
+	inflight := 1
+	timer := time.NewTimer(t.policy.HedgeDelay)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		return t.win(result, cancels, 0, results)
+	case <-timer.C:
+	}
+	if t.governor.AllowHedge(t.dependency) {
+		launch(resilience.AttemptHedge)
+		inflight++
+	}
+
+	for {
+		result := <-results
+		inflight--
+		if !failed(result.resp, result.err) || inflight == 0 {
+			return t.win(result, cancels, inflight, results)
+		}
+		// 这个失败了，另一个还在进行：丢掉这个，继续等
+		closeBody(result.resp)
+		cancels[result.index]()
+	}
+}
+
+// win 辅助方法：采用这个结果，取消其他还在进行的调用
+//
+// 采用的响应体关闭时才取消它的 ctx（否则读响应体会失败）。
+func (t *governedTransport) win(
+	result hedgeResult,
+	cancels []context.CancelFunc,
+	inflight int,
+	results <-chan hedgeResult,
+) (*http.Response, error) {
+	for i, cancel := range cancels {
+		if i != result.index {
+			cancel()
+		}
+	}
+	if inflight > 0 {
+		go func() {
+			for ; inflight > 0; inflight-- {
+				closeBody((<-results).resp)
+			}
+		}()
+	}
+	if result.err != nil {
+		cancels[result.index]()
+		return nil, result.err
+	}
+	result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: cancels[result.index]}
+	return result.resp, nil
+}
+
+// send 辅助方法：发出一次调用并记录结果
+func (t *governedTransport) send(req *http.Request, attempt resilience.Attempt) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if req.Context().Err() == nil {
+		t.governor.Record(t.dependency, attempt, !failed(resp, err))
+	}
+	return resp, err
+}
+
+// failed 辅助函数：网络错误、5xx、429 视为失败（4xx 是调用方的问题，重试也没用）
+func failed(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// closeBody 辅助函数：丢弃响应
+func closeBody(resp *http.Response) {
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// cancelOnClose 关闭响应体时取消对应调用的 ctx
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
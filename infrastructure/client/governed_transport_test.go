@@ -0,0 +1,79 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service/infrastructure/resilience"
+)
+
+func TestWithGovernor_RetriesWithinBudget(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	governor := resilience.NewGovernor(resilience.DefaultGovernorConfig())
+	c := ApplyHTTPClientOptions(&http.Client{Timeout: time.Second}, []HTTPClientOption{
+		WithGovernor(governor, "trust", RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}),
+	})
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("status = %d, calls = %d, want 200 after one retry", resp.StatusCode, calls.Load())
+	}
+
+	// POST 不重试
+	calls.Store(0)
+	resp, err = c.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("POST: status = %d, calls = %d, want 503 without retry", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestWithGovernor_Hedge(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// 首次调用卡住（长尾），对冲请求立即返回
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	governor := resilience.NewGovernor(resilience.DefaultGovernorConfig())
+	c := ApplyHTTPClientOptions(&http.Client{Timeout: 2 * time.Second}, []HTTPClientOption{
+		WithGovernor(governor, "content", RetryPolicy{HedgeDelay: 20 * time.Millisecond}),
+	})
+
+	start := time.Now()
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("status = %d after %v, want hedged 200", resp.StatusCode, time.Since(start))
+	}
+}
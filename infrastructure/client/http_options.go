@@ -0,0 +1,25 @@
+package client
+
+import "net/http"
+
+// HTTPClientOption HTTP 客户端的可选配置（所有 HTTP 适配器共用）
+type HTTPClientOption func(*http.Client)
+
+// WithTransport 替换 HTTP 传输层
+//
+// 典型用法：遗留环境没有服务网格，出站请求需要应用层 mTLS：
+//
+//	client.NewTrustSafetyHTTPClient(url, client.WithTransport(mtls.HTTPTransport(reloader, "trust.internal", "spiffe://example.org/trust-service")))
+func WithTransport(transport http.RoundTripper) HTTPClientOption {
+	return func(c *http.Client) {
+		c.Transport = transport
+	}
+}
+
+// ApplyHTTPClientOptions 应用可选配置（其他上下文的 HTTP 适配器也使用，如 contentintegration）
+func ApplyHTTPClientOptions(c *http.Client, opts []HTTPClientOption) *http.Client {
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
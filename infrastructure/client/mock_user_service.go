@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"service/application/service"
+)
+
+// 用户服务的 Mock 实现（本地开发使用）
+//
+// 放在 client 包而不是 infrastructure/repository：
+// repository 只实现领域层的仓储接口，不依赖应用层；
+// 这两个 Mock 实现的是应用层端口（service.UserRPCClient）和 user 服务的原始接口，和真实实现放在一起。
+
+// MockUserRPCClient Mock 实现：用户 RPC 客户端
+type MockUserRPCClient struct{}
+
+func NewMockUserRPCClient() service.UserRPCClient {
+	return &MockUserRPCClient{}
+}
+
+func (c *MockUserRPCClient) GetUserInfo(
+	ctx context.Context,
+	userID int64,
+) (*service.UserInfo, error) {
+	// 返回模拟数据
+	return &service.UserInfo{
+		UserID:   userID,
+		Username: "user_" + string(rune(userID)),
+		Avatar:   "https://example.com/avatar.jpg",
+		Bio:      "这是用户简介",
+	}, nil
+}
+
+func (c *MockUserRPCClient) GetUserInfoBatch(
+	ctx context.Context,
+	userIDs []int64,
+) ([]*service.UserInfo, error) {
+	// 返回模拟数据
+	result := make([]*service.UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		result = append(result, &service.UserInfo{
+			UserID:   userID,
+			Username: "user_" + string(rune(userID)),
+			Avatar:   "https://example.com/avatar.jpg",
+			Bio:      "这是用户简介",
+		})
+	}
+	return result, nil
+}
+
+// MockUserServiceAPI Mock 实现：user 服务的原始接口（经过 client.UserServiceACL 使用）
+//
+// 模拟 user 服务的历史数据：一部分老账号只有废弃的 nickname、avatar_url 字段。
+type MockUserServiceAPI struct{}
+
+func NewMockUserServiceAPI() UserServiceAPI {
+	return &MockUserServiceAPI{}
+}
+
+func (a *MockUserServiceAPI) BatchGetUsers(
+	ctx context.Context,
+	userIDs []int64,
+) ([]*UserServiceUser, error) {
+	// 返回模拟数据：ID 为偶数的是老账号
+	result := make([]*UserServiceUser, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user := &UserServiceUser{
+			UserID: userID,
+			Bio:    "这是用户简介",
+			Status: UserServiceStatusActive,
+		}
+		if userID%2 == 0 {
+			user.Nickname = "user_" + strconv.FormatInt(userID, 10)
+			user.AvatarURL = "//example.com/avatar.jpg"
+		} else {
+			user.Username = "user_" + strconv.FormatInt(userID, 10)
+			user.Avatar = "https://example.com/avatar.jpg"
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
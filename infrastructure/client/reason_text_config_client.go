@@ -1,12 +1,17 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"sync/atomic"
 	"time"
+
+	"service/application/service"
 )
 
 // ReasonTextConfigHTTPClient HTTP 客户端：调用配置服务获取推荐理由文案
@@ -28,26 +33,31 @@ import (
 // 容错设计：
 // - 超时控制：避免配置服务慢影响主流程
 // - 错误返回：让上层决定如何降级
-// - 不缓存：保证文案实时性（可以在上层添加缓存）
+// - 不缓存：缓存由装饰器 CachedReasonTextConfigClient 负责
 type ReasonTextConfigHTTPClient struct {
 	baseURL    string
 	httpClient *http.Client
+
+	// batchUnavailable 配置服务没有批量接口（返回过 404），之后不再调用，直接返回 ErrBatchUnavailable
+	batchUnavailable atomic.Bool
 }
 
 // NewReasonTextConfigHTTPClient 构造函数
-func NewReasonTextConfigHTTPClient(baseURL string) *ReasonTextConfigHTTPClient {
+func NewReasonTextConfigHTTPClient(baseURL string, opts ...HTTPClientOption) *ReasonTextConfigHTTPClient {
 	return &ReasonTextConfigHTTPClient{
 		baseURL: baseURL,
-		httpClient: &http.Client{
+		httpClient: ApplyHTTPClientOptions(&http.Client{
 			Timeout: 2 * time.Second, // 2秒超时，避免影响主流程
-		},
+		}, opts),
 	}
 }
 
 // GetReasonText 实现接口：获取推荐理由文案
 //
 // API 设计示例：
-// GET /api/v1/recommendation/reason-text?type=followed_by_following&count=3
+// GET /api/v1/recommendation/reason-text?type=followed_by_following&count=3&locale=zh-CN&variant=short
+//
+// locale 为空时不传，由配置服务使用默认语言；variant（A/B 实验的文案版本）为空时不传，使用默认文案。
 //
 // 响应示例：
 //
@@ -67,6 +77,8 @@ func (c *ReasonTextConfigHTTPClient) GetReasonText(
 	ctx context.Context,
 	reasonType string,
 	count int,
+	locale string,
+	variant string,
 ) (string, error) {
 	// 构造请求 URL
 	url := fmt.Sprintf(
@@ -75,6 +87,12 @@ func (c *ReasonTextConfigHTTPClient) GetReasonText(
 		reasonType,
 		count,
 	)
+	if locale != "" {
+		url += "&locale=" + neturl.QueryEscape(locale)
+	}
+	if variant != "" {
+		url += "&variant=" + neturl.QueryEscape(variant)
+	}
 
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -120,3 +138,159 @@ func (c *ReasonTextConfigHTTPClient) GetReasonText(
 
 	return response.Data.Text, nil
 }
+
+// GetReasonTexts 实现接口：批量获取推荐理由文案
+//
+// API 设计示例（请求可能很多，用 POST 传参数）：
+// POST /api/v1/recommendation/reason-texts
+//
+//	{"items": [{"type": "followed_by_following", "count": 3, "locale": "zh-CN", "variant": "short"}]}
+//
+// 响应示例（没有配置文案的请求可以不出现）：
+//
+//	{
+//	  "code": 0,
+//	  "message": "success",
+//	  "data": {
+//	    "texts": [{"type": "followed_by_following", "count": 3, "locale": "zh-CN", "text": "你的 3 位好友也关注了TA"}]
+//	  }
+//	}
+//
+// 旧版本的配置服务没有这个接口（404）：返回 service.ErrBatchUnavailable，
+// 并记住这一点，进程重启之前不再调用（调用方逐个调用 GetReasonText）。
+func (c *ReasonTextConfigHTTPClient) GetReasonTexts(
+	ctx context.Context,
+	requests []service.ReasonTextRequest,
+) (map[service.ReasonTextRequest]string, error) {
+	if c.batchUnavailable.Load() {
+		return nil, service.ErrBatchUnavailable
+	}
+
+	type item struct {
+		Type    string `json:"type"`
+		Count   int    `json:"count"`
+		Locale  string `json:"locale,omitempty"`
+		Variant string `json:"variant,omitempty"`
+		Text    string `json:"text,omitempty"`
+	}
+	items := make([]item, 0, len(requests))
+	for _, request := range requests {
+		items = append(items, item{Type: request.ReasonType, Count: request.Count, Locale: request.Locale, Variant: request.Variant})
+	}
+	body, err := json.Marshal(map[string][]item{"items": items})
+	if err != nil {
+		return nil, fmt.Errorf("encode request failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/recommendation/reason-texts", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.batchUnavailable.Store(true)
+		return nil, service.ErrBatchUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Texts []item `json:"texts"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+	if response.Code != 0 {
+		return nil, fmt.Errorf("api error: code=%d, message=%s", response.Code, response.Message)
+	}
+
+	result := make(map[service.ReasonTextRequest]string, len(response.Data.Texts))
+	for _, text := range response.Data.Texts {
+		result[service.ReasonTextRequest{ReasonType: text.Type, Count: text.Count, Locale: text.Locale, Variant: text.Variant}] = text.Text
+	}
+	return result, nil
+}
+
+// GetSectionLayout 实现 service.SectionLayoutClient：获取某个展示场景的分栏配置
+//
+// API 设计示例：
+// GET /api/v1/recommendation/sections?surface=discovery&locale=zh-CN
+//
+// 响应示例：
+//
+//	{
+//	  "code": 0,
+//	  "message": "success",
+//	  "data": {
+//	    "sections": [
+//	      {"key": "people_you_may_know", "title": "你可能认识的人", "reason_types": ["followed_by_following"], "limit": 10}
+//	    ]
+//	  }
+//	}
+//
+// 分栏按展示顺序返回；失败时返回错误，上层使用默认分栏。
+func (c *ReasonTextConfigHTTPClient) GetSectionLayout(
+	ctx context.Context,
+	surface string,
+	locale string,
+) ([]service.SectionLayout, error) {
+	url := fmt.Sprintf("%s/api/v1/recommendation/sections?surface=%s", c.baseURL, neturl.QueryEscape(surface))
+	if locale != "" {
+		url += "&locale=" + neturl.QueryEscape(locale)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Sections []struct {
+				Key         string   `json:"key"`
+				Title       string   `json:"title"`
+				ReasonTypes []string `json:"reason_types"`
+				Limit       int      `json:"limit"`
+			} `json:"sections"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+	if response.Code != 0 {
+		return nil, fmt.Errorf("api error: code=%d, message=%s", response.Code, response.Message)
+	}
+
+	layout := make([]service.SectionLayout, 0, len(response.Data.Sections))
+	for _, section := range response.Data.Sections {
+		layout = append(layout, service.SectionLayout{
+			Key:         section.Key,
+			Title:       section.Title,
+			ReasonTypes: section.ReasonTypes,
+			Limit:       section.Limit,
+		})
+	}
+	return layout, nil
+}
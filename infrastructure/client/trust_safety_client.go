@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// TrustSafetyHTTPClient HTTP 客户端：调用信任与安全（风控）服务获取账号风险分级
+//
+// 这是领域层 TrustRepository 接口的实现。
+//
+// 为什么实现领域层的接口，而不是应用层的 Client 接口？
+// "不推荐高风险账号"是领域规则，领域服务（RecommendationGenerator）
+// 在生成过程中就需要这份数据，所以端口定义在领域层。
+// 数据来自 HTTP 服务还是数据库，只是基础设施的实现细节。
+//
+// 容错设计：
+// - 超时控制：风控服务慢不能拖垮推荐主流程
+// - 错误返回：由领域服务决定如何降级（当前策略：不过滤）
+type TrustSafetyHTTPClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewTrustSafetyHTTPClient 构造函数
+func NewTrustSafetyHTTPClient(baseURL string, opts ...HTTPClientOption) repository.TrustRepository {
+	return &TrustSafetyHTTPClient{
+		baseURL: baseURL,
+		httpClient: ApplyHTTPClientOptions(&http.Client{
+			Timeout: 500 * time.Millisecond, // 在推荐主流程中调用，超时要短
+		}, opts),
+	}
+}
+
+// GetRiskLevels 实现接口：批量获取风险分级
+//
+// API 设计示例：
+// POST /api/v1/trust/risk-levels
+//
+// 请求示例：
+//
+//	{"user_ids": [1, 2, 3]}
+//
+// 响应示例：
+//
+//	{
+//	  "code": 0,
+//	  "message": "success",
+//	  "data": {
+//	    "levels": {"1": "low", "2": "high"}
+//	  }
+//	}
+func (c *TrustSafetyHTTPClient) GetRiskLevels(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID]valueobject.RiskLevel, error) {
+	if len(userIDs) == 0 {
+		return map[valueobject.UserID]valueobject.RiskLevel{}, nil
+	}
+
+	// 构造请求体
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.Value())
+	}
+	payload, err := json.Marshal(map[string][]int64{"user_ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request failed: %w", err)
+	}
+
+	// 创建请求
+	url := fmt.Sprintf("%s/api/v1/trust/risk-levels", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// 发送请求
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 检查状态码
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// 解析响应
+	var response struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Levels map[string]string `json:"levels"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	// 检查业务状态码
+	if response.Code != 0 {
+		return nil, fmt.Errorf("api error: code=%d, message=%s", response.Code, response.Message)
+	}
+
+	// 转换为领域对象
+	result := make(map[valueobject.UserID]valueobject.RiskLevel, len(response.Data.Levels))
+	for rawID, rawLevel := range response.Data.Levels {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			continue // 容错：跳过无法解析的ID
+		}
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		result[userID] = valueobject.ParseRiskLevel(rawLevel)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"service/application/service"
+)
+
+// coalescedCallTimeout 合并后的批量调用的超时时间
+//
+// 合并后的调用服务于多个请求，不能沿用其中某一个请求的 ctx
+// （那个请求被取消时，其他请求也会跟着失败），所以使用独立的超时。
+const coalescedCallTimeout = 3 * time.Second
+
+// ErrUserInfoNotFound 批量结果中没有这个用户
+var ErrUserInfoNotFound = errors.New("user info not found")
+
+// CoalescingUserRPCClient 用户服务客户端装饰器：合并并发请求
+//
+// 为什么需要请求合并？
+// 流量高峰时，每个推荐请求都会单独调用一次 GetUserInfoBatch（每次十来个用户），
+// user 服务收到的 QPS 和推荐服务的 QPS 一样高。
+// 同一时刻在处理的推荐请求，往往查询的是同一批热门用户。
+//
+// 合并策略：
+// - 在一个很短的时间窗口（几毫秒）内收集所有 GetUserInfo / GetUserInfoBatch 请求
+// - 窗口结束（或攒够 maxBatch 个用户）时，去重后发起一次 GetUserInfoBatch
+// - 把结果分发给各个等待的请求
+//
+// 代价：
+// 每个请求最多多等一个窗口的时间，用几毫秒的延迟换下游 QPS 的大幅下降。
+//
+// 使用示例：
+//
+//	userClient := NewCoalescingUserRPCClient(rpcClient, 5*time.Millisecond, 100)
+type CoalescingUserRPCClient struct {
+	next     service.UserRPCClient
+	window   time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending *userInfoBatch // 正在收集中的批次
+}
+
+// userInfoBatch 一个合并批次
+type userInfoBatch struct {
+	userIDs []int64
+	seen    map[int64]bool
+
+	done   chan struct{} // 批量调用完成后关闭
+	result map[int64]*service.UserInfo
+	err    error
+}
+
+// NewCoalescingUserRPCClient 构造函数
+//
+// 参数：
+// - next: 真正发起调用的客户端
+// - window: 收集请求的时间窗口
+// - maxBatch: 一次批量调用最多包含多少个用户（攒够立即发出）
+func NewCoalescingUserRPCClient(next service.UserRPCClient, window time.Duration, maxBatch int) *CoalescingUserRPCClient {
+	return &CoalescingUserRPCClient{
+		next:     next,
+		window:   window,
+		maxBatch: maxBatch,
+	}
+}
+
+// GetUserInfo 实现接口：加入当前批次，等待批量结果
+func (c *CoalescingUserRPCClient) GetUserInfo(ctx context.Context, userID int64) (*service.UserInfo, error) {
+	batch := c.enqueue([]int64{userID})
+	result, err := c.wait(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := result[userID]
+	if !ok {
+		return nil, ErrUserInfoNotFound
+	}
+	return info, nil
+}
+
+// GetUserInfoBatch 实现接口：加入当前批次，等待批量结果
+//
+// 返回结果只包含查到的用户（和直接调用 GetUserInfoBatch 的语义一致）
+func (c *CoalescingUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*service.UserInfo, error) {
+	if len(userIDs) == 0 {
+		return []*service.UserInfo{}, nil
+	}
+
+	batch := c.enqueue(userIDs)
+	result, err := c.wait(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*service.UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if info, ok := result[userID]; ok {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// enqueue 把用户加入正在收集的批次，返回这个批次
+//
+// 第一个加入的请求负责启动窗口定时器；
+// 攒够 maxBatch 个用户时立即发出，后续请求进入新批次。
+func (c *CoalescingUserRPCClient) enqueue(userIDs []int64) *userInfoBatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	batch := c.pending
+	if batch == nil {
+		batch = &userInfoBatch{
+			seen: make(map[int64]bool),
+			done: make(chan struct{}),
+		}
+		c.pending = batch
+		time.AfterFunc(c.window, func() { c.flush(batch) })
+	}
+
+	for _, userID := range userIDs {
+		if !batch.seen[userID] {
+			batch.seen[userID] = true
+			batch.userIDs = append(batch.userIDs, userID)
+		}
+	}
+
+	if c.maxBatch > 0 && len(batch.userIDs) >= c.maxBatch {
+		c.pending = nil
+		go c.execute(batch)
+	}
+	return batch
+}
+
+// flush 窗口结束：发出批次（已经因为攒够而发出的批次不会重复发出）
+func (c *CoalescingUserRPCClient) flush(batch *userInfoBatch) {
+	c.mu.Lock()
+	if c.pending != batch {
+		c.mu.Unlock()
+		return
+	}
+	c.pending = nil
+	c.mu.Unlock()
+
+	c.execute(batch)
+}
+
+// execute 发起一次批量调用，并唤醒所有等待者
+func (c *CoalescingUserRPCClient) execute(batch *userInfoBatch) {
+	defer close(batch.done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), coalescedCallTimeout)
+	defer cancel()
+
+	infos, err := c.next.GetUserInfoBatch(ctx, batch.userIDs)
+	if err != nil {
+		batch.err = err
+		return
+	}
+
+	batch.result = make(map[int64]*service.UserInfo, len(infos))
+	for _, info := range infos {
+		batch.result[info.UserID] = info
+	}
+}
+
+// wait 等待批次完成；调用方的请求被取消时立即返回
+func (c *CoalescingUserRPCClient) wait(ctx context.Context, batch *userInfoBatch) (map[int64]*service.UserInfo, error) {
+	select {
+	case <-batch.done:
+		return batch.result, batch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service/application/service"
+)
+
+// countingUserRPCClient 统计批量调用次数的用户服务客户端
+type countingUserRPCClient struct {
+	batchCalls atomic.Int32
+}
+
+func (c *countingUserRPCClient) GetUserInfo(ctx context.Context, userID int64) (*service.UserInfo, error) {
+	return &service.UserInfo{UserID: userID}, nil
+}
+
+func (c *countingUserRPCClient) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*service.UserInfo, error) {
+	c.batchCalls.Add(1)
+	result := make([]*service.UserInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID < 0 {
+			continue // 模拟不存在的用户
+		}
+		result = append(result, &service.UserInfo{UserID: userID})
+	}
+	return result, nil
+}
+
+func TestCoalescingUserRPCClient_MergesConcurrentRequests(t *testing.T) {
+	next := &countingUserRPCClient{}
+	c := NewCoalescingUserRPCClient(next, 20*time.Millisecond, 1000)
+
+	var wg sync.WaitGroup
+	for i := int64(1); i <= 10; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			infos, err := c.GetUserInfoBatch(context.Background(), []int64{userID, userID + 100, -1})
+			if err != nil {
+				t.Errorf("GetUserInfoBatch(%d) failed: %v", userID, err)
+				return
+			}
+			if len(infos) != 2 || infos[0].UserID != userID || infos[1].UserID != userID+100 {
+				t.Errorf("GetUserInfoBatch(%d) = %+v, want users [%d %d]", userID, infos, userID, userID+100)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := next.batchCalls.Load(); got != 1 {
+		t.Errorf("downstream batch calls = %d, want 1", got)
+	}
+
+	if _, err := c.GetUserInfo(context.Background(), -1); err != ErrUserInfoNotFound {
+		t.Errorf("GetUserInfo(-1) err = %v, want %v", err, ErrUserInfoNotFound)
+	}
+}
+
+func TestCoalescingUserRPCClient_FlushesWhenBatchIsFull(t *testing.T) {
+	next := &countingUserRPCClient{}
+	c := NewCoalescingUserRPCClient(next, time.Hour, 3)
+
+	infos, err := c.GetUserInfoBatch(context.Background(), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetUserInfoBatch failed: %v", err)
+	}
+	if len(infos) != 3 {
+		t.Errorf("len(infos) = %d, want 3", len(infos))
+	}
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+)
+
+// user 服务的错误码（BizStatusError 的 code，定义在 user 服务的 IDL 中）
+const (
+	userServiceCodeInvalidArgument  int32 = 10001
+	userServiceCodePermissionDenied int32 = 10003
+	userServiceCodeUserNotFound     int32 = 10004
+	userServiceCodeUserDeactivated  int32 = 10005
+	userServiceCodeRateLimited      int32 = 10029
+	userServiceCodeInternal         int32 = 10500
+)
+
+// user 服务返回的账号状态
+const (
+	UserServiceStatusActive      = "active"
+	UserServiceStatusDeactivated = "deactivated"
+)
+
+// UserServiceUser user 服务返回的用户数据（上游模型，原样映射 user 服务的响应）
+//
+// 上游模型只在防腐层内部使用，不会进入应用层。
+type UserServiceUser struct {
+	UserID    int64
+	Username  string
+	Nickname  string // 已废弃：老账号只有这个字段，新账号使用 Username
+	Avatar    string
+	AvatarURL string // 已废弃：老版本客户端上传的头像写在这里
+	Bio       string
+	Status    string // active / deactivated，老数据可能为空（视为 active）
+}
+
+// UserServiceAPI user 服务的原始接口（生成 user 服务的 Kitex 客户端后，用它实现这个接口）
+type UserServiceAPI interface {
+	BatchGetUsers(ctx context.Context, userIDs []int64) ([]*UserServiceUser, error)
+}
+
+// UserServiceACL 防腐层（Anti-Corruption Layer）：user 服务 → 应用层
+//
+// 为什么需要？
+// user 服务是另一个团队维护的限界上下文，它的数据有很多历史包袱：
+// 用户名为空、头像地址格式不对、新老字段并存、错误码随版本变化。
+// 没有防腐层时，这些问题直接流进 DTO，每个使用方都要自己处理一遍。
+//
+// 防腐层负责：
+// 1. 模型翻译：上游模型（UserServiceUser）→ 应用层模型（service.UserInfo）
+// 2. 校验和规整：
+//   - 用户名：优先 Username，为空时使用废弃的 Nickname，都为空时使用"用户{ID}"
+//   - 头像：优先 Avatar，为空时使用废弃的 AvatarURL；协议相对地址、http 地址改为 https，格式不对时使用默认头像
+//   - 简介：去掉首尾空白和控制字符
+//   - 已注销的账号、请求之外的用户、ID 不合法的数据直接丢弃
+//
+// 3. 错误翻译：上游错误码 → 结构化错误（service.UserServiceError）
+//
+// 实现 service.UserRPCClient，位于请求合并、缓存之下（缓存的是规整之后的数据）。
+type UserServiceACL struct {
+	api           UserServiceAPI
+	defaultAvatar string
+}
+
+// NewUserServiceACL 构造函数
+//
+// 参数：
+// - api: user 服务的原始客户端
+// - defaultAvatar: 头像缺失或格式不对时使用的默认头像
+func NewUserServiceACL(api UserServiceAPI, defaultAvatar string) *UserServiceACL {
+	return &UserServiceACL{api: api, defaultAvatar: defaultAvatar}
+}
+
+// GetUserInfo 实现接口：获取单个用户（不存在或已注销时返回 ErrUserNotFound）
+func (a *UserServiceACL) GetUserInfo(ctx context.Context, userID int64) (*service.UserInfo, error) {
+	infos, err := a.GetUserInfoBatch(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, &service.UserServiceError{Kind: service.ErrUserNotFound, Message: "missing from batch result"}
+	}
+	return infos[0], nil
+}
+
+// GetUserInfoBatch 实现接口：批量获取用户
+//
+// 不存在、已注销的用户不在结果中（和上游的批量语义一致）。
+func (a *UserServiceACL) GetUserInfoBatch(ctx context.Context, userIDs []int64) ([]*service.UserInfo, error) {
+	users, err := a.api.BatchGetUsers(ctx, userIDs)
+	if err != nil {
+		return nil, translateUserServiceError(err)
+	}
+
+	requested := make(map[int64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		requested[userID] = true
+	}
+	result := make([]*service.UserInfo, 0, len(users))
+	for _, user := range users {
+		if user == nil || user.UserID <= 0 || !requested[user.UserID] || user.Status == UserServiceStatusDeactivated {
+			continue
+		}
+		delete(requested, user.UserID) // 上游重复返回同一个用户时只保留第一条
+		result = append(result, a.translate(user))
+	}
+	return result, nil
+}
+
+// translate 上游模型 → 应用层模型
+func (a *UserServiceACL) translate(user *UserServiceUser) *service.UserInfo {
+	username := strings.TrimSpace(user.Username)
+	if username == "" {
+		username = strings.TrimSpace(user.Nickname)
+	}
+	if username == "" {
+		username = "用户" + strconv.FormatInt(user.UserID, 10)
+	}
+
+	avatar := strings.TrimSpace(user.Avatar)
+	if avatar == "" {
+		avatar = strings.TrimSpace(user.AvatarURL)
+	}
+
+	return &service.UserInfo{
+		UserID:   user.UserID,
+		Username: username,
+		Avatar:   normalizeAvatarURL(avatar, a.defaultAvatar),
+		Bio:      strings.TrimSpace(stripControlChars(user.Bio)),
+	}
+}
+
+// normalizeAvatarURL 头像地址规整为 https 绝对地址，不合法时返回默认头像
+func normalizeAvatarURL(raw, defaultAvatar string) string {
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return defaultAvatar
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		u.Scheme = "https" // 客户端禁止加载 http 资源
+	default:
+		return defaultAvatar
+	}
+	return u.String()
+}
+
+// stripControlChars 去掉控制字符（保留换行）
+func stripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// translateUserServiceError 上游错误 → 结构化错误
+//
+// 没有错误码的错误（网络错误、超时）视为暂时不可用；
+// ctx 被取消时原样返回，调用方据此区分"自己放弃了"和"上游出错"。
+func translateUserServiceError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	var bizErr kerrors.BizStatusErrorIface
+	if !errors.As(err, &bizErr) {
+		return &service.UserServiceError{Kind: service.ErrUserServiceUnavailable, Message: err.Error()}
+	}
+
+	kind := service.ErrUserServiceUnavailable
+	switch bizErr.BizStatusCode() {
+	case userServiceCodeUserNotFound, userServiceCodeUserDeactivated:
+		kind = service.ErrUserNotFound
+	case userServiceCodeInvalidArgument, userServiceCodePermissionDenied:
+		kind = service.ErrUserServiceRejected
+	case userServiceCodeRateLimited, userServiceCodeInternal:
+		kind = service.ErrUserServiceUnavailable
+	}
+	return &service.UserServiceError{
+		Kind:         kind,
+		UpstreamCode: bizErr.BizStatusCode(),
+		Message:      bizErr.BizMessage(),
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+)
+
+// stubUserServiceAPI 返回固定结果的 user 服务
+type stubUserServiceAPI struct {
+	users []*UserServiceUser
+	err   error
+}
+
+func (s *stubUserServiceAPI) BatchGetUsers(context.Context, []int64) ([]*UserServiceUser, error) {
+	return s.users, s.err
+}
+
+func TestUserServiceACL_NormalizesUpstreamData(t *testing.T) {
+	const defaultAvatar = "https://cdn.example.com/default.png"
+	acl := NewUserServiceACL(&stubUserServiceAPI{users: []*UserServiceUser{
+		{UserID: 1, Username: "  alice ", Avatar: "http://cdn.example.com/a.png", Bio: " hi\x00 "},
+		{UserID: 2, Nickname: "bob", AvatarURL: "//cdn.example.com/b.png"},
+		{UserID: 3, Avatar: "not a url"},
+		{UserID: 4, Username: "gone", Status: UserServiceStatusDeactivated},
+		{UserID: 99, Username: "not requested"},
+		nil,
+	}}, defaultAvatar)
+
+	infos, err := acl.GetUserInfoBatch(context.Background(), []int64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []service.UserInfo{
+		{UserID: 1, Username: "alice", Avatar: "https://cdn.example.com/a.png", Bio: "hi"},
+		{UserID: 2, Username: "bob", Avatar: "https://cdn.example.com/b.png"},
+		{UserID: 3, Username: "用户3", Avatar: defaultAvatar},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("got %d users, want %d", len(infos), len(want))
+	}
+	for i, w := range want {
+		if *infos[i] != w {
+			t.Errorf("user %d = %+v, want %+v", w.UserID, *infos[i], w)
+		}
+	}
+}
+
+func TestUserServiceACL_TranslatesErrors(t *testing.T) {
+	cases := []struct {
+		upstream error
+		want     error
+	}{
+		{kerrors.NewBizStatusError(userServiceCodeUserNotFound, "no such user"), service.ErrUserNotFound},
+		{kerrors.NewBizStatusError(userServiceCodeInvalidArgument, "bad ids"), service.ErrUserServiceRejected},
+		{kerrors.NewBizStatusError(userServiceCodeRateLimited, "slow down"), service.ErrUserServiceUnavailable},
+		{errors.New("connection reset"), service.ErrUserServiceUnavailable},
+	}
+	for _, c := range cases {
+		acl := NewUserServiceACL(&stubUserServiceAPI{err: c.upstream}, "")
+		_, err := acl.GetUserInfoBatch(context.Background(), []int64{1})
+		if !errors.Is(err, c.want) {
+			t.Errorf("upstream %v: got %v, want %v", c.upstream, err, c.want)
+		}
+	}
+
+	acl := NewUserServiceACL(&stubUserServiceAPI{}, "")
+	if _, err := acl.GetUserInfo(context.Background(), 1); !errors.Is(err, service.ErrUserNotFound) {
+		t.Errorf("missing user: got %v, want ErrUserNotFound", err)
+	}
+}
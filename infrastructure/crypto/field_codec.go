@@ -0,0 +1,179 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ciphertextPrefix 密文前缀（带版本号，以后更换算法时可以区分）
+const ciphertextPrefix = "enc:v1:"
+
+var (
+	ErrMalformedCiphertext = errors.New("malformed ciphertext")
+	ErrNoBlindIndexKey     = errors.New("blind index key not configured")
+)
+
+// FieldCodec 字段级加密编解码器
+//
+// 由持久化层在写入 / 读出敏感字段时调用（PO 转换时），领域层和应用层感知不到加密。
+//
+// 算法：AES-256-GCM（带认证，密文被篡改时解密失败）
+//
+// 密文格式："enc:v1:{keyID}:{base64(nonce + ciphertext)}"
+// - keyID 写在密文里：密钥轮换后，旧数据仍然能用旧密钥解密
+// - 没有前缀的值当作未加密的历史数据原样返回（已有的表可以直接开启加密）
+//
+// 旧数据随着更新或重新加密（Reencrypt）逐步变成密文。
+//
+// 为什么是字段级而不是整库加密？
+// 磁盘 / 整库加密防不了数据库快照、备份、只读账号泄露；
+// 字段级加密后，拿到数据的人没有 KMS 里的密钥也看不到原文。
+type FieldCodec struct {
+	keys          KeyManager
+	blindIndexKey string // 盲索引使用的密钥ID，为空时不支持 BlindIndex
+}
+
+// FieldCodecOption 可选配置
+type FieldCodecOption func(*FieldCodec)
+
+// WithBlindIndexKey 盲索引使用的密钥ID（从同一个 KeyManager 读取）
+func WithBlindIndexKey(keyID string) FieldCodecOption {
+	return func(c *FieldCodec) {
+		c.blindIndexKey = keyID
+	}
+}
+
+// NewFieldCodec 构造函数
+func NewFieldCodec(keys KeyManager, opts ...FieldCodecOption) *FieldCodec {
+	c := &FieldCodec{keys: keys}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Encrypt 加密（使用当前密钥）
+func (c *FieldCodec) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	key, err := c.keys.CurrentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key.Material)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(key.ID))
+	return ciphertextPrefix + key.ID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密（按密文中的 keyID 找回密钥；没有加密前缀的值原样返回）
+func (c *FieldCodec) Decrypt(ctx context.Context, value string) (string, error) {
+	keyID, payload, encrypted, err := parseCiphertext(value)
+	if err != nil {
+		return "", err
+	}
+	if !encrypted {
+		return value, nil
+	}
+
+	key, err := c.keys.KeyByID(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key.Material)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) < gcm.NonceSize() {
+		return "", ErrMalformedCiphertext
+	}
+	nonce, sealed := payload[:gcm.NonceSize()], payload[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt 值是否需要重新加密（未加密，或者不是用当前密钥加密的）
+//
+// 密钥轮换时由后台任务扫描可修改的表，对返回 true 的值调用 Reencrypt 后写回。
+func (c *FieldCodec) NeedsReencrypt(ctx context.Context, value string) (bool, error) {
+	keyID, _, encrypted, err := parseCiphertext(value)
+	if err != nil {
+		return false, err
+	}
+	if !encrypted {
+		return true, nil
+	}
+	current, err := c.keys.CurrentKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	return keyID != current.ID, nil
+}
+
+// Reencrypt 用当前密钥重新加密（密钥轮换）
+func (c *FieldCodec) Reencrypt(ctx context.Context, value string) (string, error) {
+	plaintext, err := c.Decrypt(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	return c.Encrypt(ctx, plaintext)
+}
+
+// BlindIndex 盲索引：带密钥的 HMAC-SHA256（十六进制）
+//
+// 密文每次都不同，加密的列不能用 WHERE 查找；另存一列盲索引，查找时比较 BlindIndex(值)。
+// 盲索引密钥不随加密密钥轮换（换了之后已有的盲索引全部失效），单独配置。
+func (c *FieldCodec) BlindIndex(ctx context.Context, value string) (string, error) {
+	if c.blindIndexKey == "" {
+		return "", ErrNoBlindIndexKey
+	}
+	key, err := c.keys.KeyByID(ctx, c.blindIndexKey)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key.Material)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// newGCM 辅助方法：AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseCiphertext 辅助方法：拆分密文（encrypted 为 false 表示未加密的历史数据）
+func parseCiphertext(value string) (keyID string, payload []byte, encrypted bool, err error) {
+	rest, ok := strings.CutPrefix(value, ciphertextPrefix)
+	if !ok {
+		return "", nil, false, nil
+	}
+	keyID, encoded, ok := strings.Cut(rest, ":")
+	if !ok || keyID == "" {
+		return "", nil, false, ErrMalformedCiphertext
+	}
+	payload, err = base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, false, ErrMalformedCiphertext
+	}
+	return keyID, payload, true, nil
+}
@@ -0,0 +1,80 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFieldCodec_RoundTripAndRotation(t *testing.T) {
+	ctx := context.Background()
+	k1, k2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+
+	oldKeys, err := NewStaticKeyManager(map[string][]byte{"k1": k1}, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := NewFieldCodec(oldKeys).Encrypt(ctx, "contact:+86138xxxx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(ciphertext, "138") {
+		t.Fatalf("ciphertext %q leaks plaintext", ciphertext)
+	}
+
+	// 轮换：k2 成为当前密钥，k1 仍可解密
+	rotated, _ := NewStaticKeyManager(map[string][]byte{"k1": k1, "k2": k2}, "k2")
+	codec := NewFieldCodec(rotated)
+	if got, err := codec.Decrypt(ctx, ciphertext); err != nil || got != "contact:+86138xxxx" {
+		t.Fatalf("Decrypt = %q, %v", got, err)
+	}
+	if need, _ := codec.NeedsReencrypt(ctx, ciphertext); !need {
+		t.Errorf("value encrypted with retired key should need re-encryption")
+	}
+	reencrypted, err := codec.Reencrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if need, _ := codec.NeedsReencrypt(ctx, reencrypted); need {
+		t.Errorf("value encrypted with current key should not need re-encryption")
+	}
+
+	// 未加密的历史数据原样返回
+	if got, _ := codec.Decrypt(ctx, "legacy plaintext"); got != "legacy plaintext" {
+		t.Errorf("legacy value = %q", got)
+	}
+
+	// 旧密钥被删除后无法解密
+	k2Only, _ := NewStaticKeyManager(map[string][]byte{"k2": k2}, "k2")
+	if _, err := NewFieldCodec(k2Only).Decrypt(ctx, ciphertext); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Decrypt with missing key err = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestFieldCodec_BlindIndex(t *testing.T) {
+	ctx := context.Background()
+	keys, err := NewStaticKeyManager(map[string][]byte{
+		"k1":   bytes.Repeat([]byte{1}, 32),
+		"idx1": bytes.Repeat([]byte{9}, 32),
+	}, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFieldCodec(keys).BlindIndex(ctx, "42"); !errors.Is(err, ErrNoBlindIndexKey) {
+		t.Fatalf("without key: err = %v, want ErrNoBlindIndexKey", err)
+	}
+
+	codec := NewFieldCodec(keys, WithBlindIndexKey("idx1"))
+	a, err := codec.BlindIndex(ctx, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := codec.BlindIndex(ctx, "42")
+	c, _ := codec.BlindIndex(ctx, "43")
+	if a != b || a == c {
+		t.Fatalf("BlindIndex: 42 → %q, %q; 43 → %q", a, b, c)
+	}
+}
@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"service/application/service"
+)
+
+func TestIdentityTokens_Verify(t *testing.T) {
+	tokens, err := NewIdentityTokens(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	tokens.now = func() time.Time { return now }
+
+	token, err := tokens.Issue(service.Principal{UserID: 42, Service: "bff"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := tokens.Verify(token); err != nil || got != (service.Principal{UserID: 42, Service: "bff"}) {
+		t.Fatalf("Verify = %+v, %v", got, err)
+	}
+
+	// 其他密钥签发的令牌
+	other, _ := NewIdentityTokens(bytes.Repeat([]byte{2}, 32))
+	forged, _ := other.Issue(service.Principal{UserID: 42}, time.Minute)
+
+	// 改动声明（换成别的用户）但保留原签名
+	_, signature, _ := strings.Cut(token, ".")
+	tampered, _ := NewIdentityTokens(bytes.Repeat([]byte{1}, 32))
+	tampered.now = tokens.now
+	altered, _ := tampered.Issue(service.Principal{UserID: 7, Service: "bff"}, time.Minute)
+	payload, _, _ := strings.Cut(altered, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"other key", forged, ErrInvalidToken},
+		{"tampered claims", payload + "." + signature, ErrInvalidToken},
+		{"no signature", payload, ErrMalformedToken},
+		{"garbage", "not-a-token.!!", ErrMalformedToken},
+	}
+	for _, tt := range tests {
+		if _, err := tokens.Verify(tt.token); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired: err = %v, want %v", err, ErrTokenExpired)
+	}
+
+	if _, err := NewIdentityTokens([]byte("short")); err == nil {
+		t.Error("NewIdentityTokens accepted a short key")
+	}
+}
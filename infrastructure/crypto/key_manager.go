@@ -0,0 +1,168 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+
+	"service/infrastructure/secrets"
+)
+
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// Key 数据加密密钥（DEK）
+type Key struct {
+	ID       string // 密钥ID，写在密文里，解密时据此找回密钥
+	Material []byte // 密钥内容（AES-256：32 字节）
+}
+
+// KeyManager 密钥管理端口（KMS）
+//
+// 为什么抽象成接口？
+// 密钥不能和数据放在一起（否则数据库快照泄露时密钥也一起泄露），
+// 生产环境由 KMS（云厂商 KMS、Vault 等）保管，开发环境可以用静态密钥。
+// 加密编解码器只依赖这个接口，不关心密钥从哪里来。
+//
+// 密钥轮换：
+// - CurrentKey 返回当前用于加密的密钥，轮换后返回新密钥
+// - KeyByID 必须仍能返回旧密钥，直到用旧密钥加密的数据全部重新加密或过期
+type KeyManager interface {
+	// CurrentKey 当前用于加密的密钥
+	CurrentKey(ctx context.Context) (Key, error)
+	// KeyByID 按ID获取密钥（用于解密），找不到时返回 ErrKeyNotFound
+	KeyByID(ctx context.Context, id string) (Key, error)
+}
+
+// StaticKeyManager 静态密钥实现（开发、测试环境使用）
+type StaticKeyManager struct {
+	keys    map[string][]byte
+	current string
+}
+
+// NewStaticKeyManager 构造函数
+//
+// 参数：
+// - keys: 密钥ID → 密钥内容（每个 32 字节）
+// - current: 当前用于加密的密钥ID
+func NewStaticKeyManager(keys map[string][]byte, current string) (*StaticKeyManager, error) {
+	for id, material := range keys {
+		if len(material) != 32 {
+			return nil, fmt.Errorf("key %q: want 32 bytes, got %d", id, len(material))
+		}
+	}
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current key %q: %w", current, ErrKeyNotFound)
+	}
+	return &StaticKeyManager{keys: keys, current: current}, nil
+}
+
+// CurrentKey 实现接口
+func (m *StaticKeyManager) CurrentKey(ctx context.Context) (Key, error) {
+	return Key{ID: m.current, Material: m.keys[m.current]}, nil
+}
+
+// KeyByID 实现接口
+func (m *StaticKeyManager) KeyByID(ctx context.Context, id string) (Key, error) {
+	material, ok := m.keys[id]
+	if !ok {
+		return Key{}, fmt.Errorf("key %q: %w", id, ErrKeyNotFound)
+	}
+	return Key{ID: id, Material: material}, nil
+}
+
+// SecretsKeyManager 从 secrets（生产环境是 Vault）读取密钥环的实现
+//
+// 密钥环是 secrets 中的一个路径：每个键是密钥ID，值是 base64 编码的 32 字节密钥，如 k1、k2。
+//
+// 轮换步骤：
+// 1. 把新密钥加入密钥环（所有实例都能用它解密）
+// 2. 修改 encryption.current_key_id 并重新部署（新数据使用新密钥）
+// 3. 重新加密任务把可修改的表换成新密钥，旧密钥保留到只追加的表中旧数据过期
+//
+// 解密时遇到不认识的密钥ID（其他实例已经先换了密钥），重新读取一次密钥环。
+type SecretsKeyManager struct {
+	provider secrets.Provider
+	path     string
+	current  string
+
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+// NewSecretsKeyManager 构造函数（读取一次密钥环，当前密钥不在密钥环中时返回错误）
+//
+// 参数：
+// - provider: 密钥读取
+// - path: 密钥环在 secrets 中的路径
+// - current: 当前用于加密的密钥ID
+func NewSecretsKeyManager(ctx context.Context, provider secrets.Provider, path, current string) (*SecretsKeyManager, error) {
+	m := &SecretsKeyManager{provider: provider, path: path, current: current}
+	if err := m.reload(ctx); err != nil {
+		return nil, err
+	}
+	if _, ok := m.lookup(current); !ok {
+		return nil, fmt.Errorf("current key %q: %w", current, ErrKeyNotFound)
+	}
+	provider.Subscribe(path, func(values map[string]string) {
+		_ = m.load(values) // 格式错误时继续使用之前的密钥环
+	})
+	return m, nil
+}
+
+// CurrentKey 实现接口
+func (m *SecretsKeyManager) CurrentKey(ctx context.Context) (Key, error) {
+	return m.KeyByID(ctx, m.current)
+}
+
+// KeyByID 实现接口（密钥环中没有时重新读取一次）
+func (m *SecretsKeyManager) KeyByID(ctx context.Context, id string) (Key, error) {
+	if material, ok := m.lookup(id); ok {
+		return Key{ID: id, Material: material}, nil
+	}
+	if err := m.reload(ctx); err != nil {
+		return Key{}, err
+	}
+	material, ok := m.lookup(id)
+	if !ok {
+		return Key{}, fmt.Errorf("key %q: %w", id, ErrKeyNotFound)
+	}
+	return Key{ID: id, Material: material}, nil
+}
+
+// lookup 辅助方法：在已读取的密钥环中查找
+func (m *SecretsKeyManager) lookup(id string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	material, ok := m.keys[id]
+	return material, ok
+}
+
+// reload 辅助方法：重新读取密钥环
+func (m *SecretsKeyManager) reload(ctx context.Context) error {
+	values, err := m.provider.Get(ctx, m.path)
+	if err != nil {
+		return fmt.Errorf("key ring %q: %w", m.path, err)
+	}
+	return m.load(values)
+}
+
+// load 辅助方法：解码密钥环并替换（任何一个密钥格式错误时不替换）
+func (m *SecretsKeyManager) load(values map[string]string) error {
+	keys := make(map[string][]byte, len(values))
+	for id, encoded := range values {
+		material, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", id, err)
+		}
+		if len(material) != 32 {
+			return fmt.Errorf("key %q: want 32 bytes, got %d", id, len(material))
+		}
+		keys[id] = material
+	}
+	m.mu.Lock()
+	m.keys = keys
+	m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// keyRingProvider 测试用的 secrets.Provider：密钥环可以在测试中修改
+type keyRingProvider struct {
+	values map[string]string
+	reads  int
+}
+
+func (p *keyRingProvider) Get(ctx context.Context, name string) (map[string]string, error) {
+	p.reads++
+	return p.values, nil
+}
+
+func (p *keyRingProvider) Subscribe(name string, fn func(map[string]string)) {}
+
+func TestSecretsKeyManager_ReloadsUnknownKey(t *testing.T) {
+	ctx := context.Background()
+	k1, k2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	provider := &keyRingProvider{values: map[string]string{"k1": base64.StdEncoding.EncodeToString(k1)}}
+
+	if _, err := NewSecretsKeyManager(ctx, provider, "field-keys", "k2"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("current key missing: err = %v, want ErrKeyNotFound", err)
+	}
+	keys, err := NewSecretsKeyManager(ctx, provider, "field-keys", "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他实例已经开始使用 k2：第一次遇到 k2 时重新读取密钥环
+	provider.values = map[string]string{
+		"k1": base64.StdEncoding.EncodeToString(k1),
+		"k2": base64.StdEncoding.EncodeToString(k2),
+	}
+	key, err := keys.KeyByID(ctx, "k2")
+	if err != nil || !bytes.Equal(key.Material, k2) {
+		t.Fatalf("KeyByID(k2) = %v, %v", key, err)
+	}
+	reads := provider.reads
+	if _, err := keys.KeyByID(ctx, "k2"); err != nil || provider.reads != reads {
+		t.Fatalf("known key should not reload: reads %d → %d, err %v", reads, provider.reads, err)
+	}
+	if _, err := keys.KeyByID(ctx, "k3"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("KeyByID(k3) err = %v, want ErrKeyNotFound", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package discovery 服务注册与发现（etcd、Nacos）
+//
+// 服务启动后把自己的地址注册到注册中心，并定期续约（心跳）；
+// 调用方按服务名查询实例列表，不再在配置里写死 host:port。
+//
+// 和 secrets.VaultProvider 一样直接调用注册中心的 HTTP API，不引入 SDK：
+// - etcd：v3 的 JSON gateway（/v3/kv/*、/v3/lease/*），key 的格式和 Kitex 官方扩展 registry-etcd 相同
+// - Nacos：v1 OpenAPI（/nacos/v1/ns/instance），临时实例 + 心跳
+//
+// 这个包不依赖 Kitex；和 Kitex 的 registry.Registry、discovery.Resolver 的适配在组合根（main 包）中。
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 注册中心类型（config 中 registry 端口的实现）
+const (
+	TypeEtcd  = "etcd"
+	TypeNacos = "nacos"
+)
+
+// ErrNoEndpoint 所有注册中心节点都不可用
+var ErrNoEndpoint = errors.New("discovery: no registry endpoint available")
+
+// Instance 服务实例
+type Instance struct {
+	Service string            // 服务名（如 recommendation-service）
+	Addr    string            // host:port，必须是调用方能访问的地址（见 AdvertiseAddr）
+	Weight  int               // 负载均衡权重，为 0 时使用 DefaultWeight
+	Tags    map[string]string // 扩展信息（如集群、版本）
+}
+
+// DefaultWeight 没有指定权重时的默认值（和 Kitex 的 discovery.DefaultWeight 相同）
+const DefaultWeight = 10
+
+// Registry 注册中心
+//
+// Register 之后在后台续约，直到 Deregister 或 Close；
+// 续约失败（租约过期、注册中心重启丢失数据）时自动重新注册。
+type Registry interface {
+	Register(ctx context.Context, instance Instance) error
+	Deregister(ctx context.Context, instance Instance) error
+	// Resolve 查询服务当前的实例（只返回健康的实例）
+	Resolve(ctx context.Context, service string) ([]Instance, error)
+	// Name 注册中心类型（etcd、nacos）
+	Name() string
+	// Close 停止所有续约（不注销实例，实例在 TTL 之后过期）
+	Close()
+}
+
+// Config 注册中心配置
+type Config struct {
+	Type      string        // etcd、nacos
+	Endpoints []string      // 注册中心节点（host:port 或 URL），按顺序尝试
+	Namespace string        // Nacos 命名空间（etcd 不使用）
+	TTL       time.Duration // 实例的存活时间，每 TTL/3 续约一次；为 0 时 10 秒
+
+	// HTTPClient 可选，默认 5 秒超时
+	HTTPClient *http.Client
+}
+
+// New 按类型创建注册中心
+func New(cfg Config) (Registry, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("discovery: %s registry has no endpoints", cfg.Type)
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 10 * time.Second
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	switch cfg.Type {
+	case TypeEtcd:
+		return NewEtcdRegistry(cfg), nil
+	case TypeNacos:
+		return NewNacosRegistry(cfg), nil
+	default:
+		return nil, fmt.Errorf("discovery: unsupported registry type %q", cfg.Type)
+	}
+}
+
+// AdvertiseAddr 注册到注册中心的地址
+//
+// 服务通常监听 0.0.0.0，这个地址调用方访问不到：
+// 监听地址没有指定主机（或者是 0.0.0.0、::）时，换成本机第一个非回环的 IPv4 地址。
+func AdvertiseAddr(listenAddr string) (string, error) {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		return listenAddr, nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return net.JoinHostPort(ipNet.IP.String(), port), nil
+		}
+	}
+	return "", fmt.Errorf("discovery: no non-loopback IPv4 address to advertise for %s", listenAddr)
+}
+
+// keepalives 每个注册的实例一个续约协程（etcd、Nacos 共用）
+type keepalives struct {
+	mu      sync.Mutex
+	running map[string]chan struct{} // 服务名/地址 → 停止信号
+	closed  bool
+}
+
+func newKeepalives() *keepalives {
+	return &keepalives{running: make(map[string]chan struct{})}
+}
+
+// start 开始续约：每隔 interval 调用一次 beat（同一个实例重复注册时替换之前的协程）
+func (k *keepalives) start(key string, interval time.Duration, beat func(ctx context.Context)) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.closed {
+		return
+	}
+	if stop, ok := k.running[key]; ok {
+		close(stop)
+	}
+	stop := make(chan struct{})
+	k.running[key] = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				beat(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// stop 停止一个实例的续约
+func (k *keepalives) stop(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if stop, ok := k.running[key]; ok {
+		close(stop)
+		delete(k.running, key)
+	}
+}
+
+// close 停止所有续约
+func (k *keepalives) close() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for key, stop := range k.running {
+		close(stop)
+		delete(k.running, key)
+	}
+	k.closed = true
+}
+
+// instanceKey 辅助函数：续约协程的 key
+func instanceKey(instance Instance) string {
+	return instance.Service + "/" + instance.Addr
+}
+
+// endpointURL 辅助函数：注册中心节点的 URL（host:port 补上 http://）
+func endpointURL(endpoint string) string {
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	return "http://" + endpoint
+}
+
+// statusError 注册中心返回的错误状态码（4xx 不再尝试其他节点）
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("discovery: registry returned %d: %s", e.status, e.body)
+}
+
+// call 辅助函数：按顺序尝试每个注册中心节点，返回第一个成功的响应（解析到 out，out 为 nil 时忽略响应体）
+//
+// 网络错误、5xx 时尝试下一个节点；4xx 是请求本身的问题，直接返回。
+func call(ctx context.Context, client *http.Client, endpoints []string, build func(ctx context.Context, base string) (*http.Request, error), out any) error {
+	var errs []error
+	for _, endpoint := range endpoints {
+		req, err := build(ctx, endpointURL(endpoint))
+		if err != nil {
+			return err
+		}
+		err = send(client, req, out)
+		var statusErr *statusError
+		if err == nil || (errors.As(err, &statusErr) && statusErr.status < http.StatusInternalServerError) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+	}
+	return fmt.Errorf("%w: %w", ErrNoEndpoint, errors.Join(errs...))
+}
+
+// send 辅助函数：发送请求，非 2xx 返回 statusError
+func send(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return &statusError{status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,156 @@
+package discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEtcd etcd JSON gateway 的最小实现：kv 的 put / range / deleterange，租约只记录 ID
+type fakeEtcd struct {
+	mu   sync.Mutex
+	kv   map[string]string
+	next int64
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var req map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	decode := func(s string) string {
+		b, _ := base64.StdEncoding.DecodeString(s)
+		return string(b)
+	}
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		f.next++
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "7" + string(rune('0'+f.next)), "TTL": "10"})
+	case "/v3/kv/put":
+		if req["lease"] == "" || req["lease"] == "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.kv[decode(req["key"])] = req["value"]
+		_, _ = w.Write([]byte("{}"))
+	case "/v3/kv/range":
+		var kvs []map[string]string
+		for key, value := range f.kv {
+			if key >= decode(req["key"]) && key < decode(req["range_end"]) {
+				kvs = append(kvs, map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key)), "value": value})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"kvs": kvs})
+	case "/v3/kv/deleterange":
+		delete(f.kv, decode(req["key"]))
+		_, _ = w.Write([]byte("{}"))
+	default:
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+func TestEtcdRegistry_RegisterResolveDeregister(t *testing.T) {
+	etcd := httptest.NewServer(&fakeEtcd{kv: make(map[string]string)})
+	defer etcd.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	// 第一个节点不可用：换下一个节点
+	reg, err := New(Config{Type: TypeEtcd, Endpoints: []string{down.URL, strings.TrimPrefix(etcd.URL, "http://")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Close()
+
+	ctx := context.Background()
+	instance := Instance{Service: "recommendation-service", Addr: "10.0.0.8:8888", Tags: map[string]string{"cluster": "a"}}
+	if err := reg.Register(ctx, instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(ctx, Instance{Service: "recommendation-service-canary", Addr: "10.0.0.9:8888"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reg.Resolve(ctx, "recommendation-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Addr != "10.0.0.8:8888" || got[0].Weight != DefaultWeight || got[0].Tags["cluster"] != "a" {
+		t.Fatalf("Resolve() = %+v, want only 10.0.0.8:8888 (prefix must not match the canary service)", got)
+	}
+
+	if err := reg.Deregister(ctx, instance); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := reg.Resolve(ctx, "recommendation-service"); len(got) != 0 {
+		t.Errorf("Resolve() after Deregister = %+v, want none", got)
+	}
+}
+
+func TestNacosRegistry_HeartbeatReregistersMissingInstance(t *testing.T) {
+	var mu sync.Mutex
+	var registrations int
+	var lastParams string
+	nacos := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/nacos/v1/ns/instance":
+			registrations++
+			lastParams = r.URL.RawQuery
+			_, _ = w.Write([]byte("ok"))
+		case r.URL.Path == "/nacos/v1/ns/instance/beat":
+			_ = json.NewEncoder(w).Encode(map[string]int{"code": nacosInstanceNotFound}) // Nacos 重启过
+		case r.URL.Path == "/nacos/v1/ns/instance/list":
+			_ = json.NewEncoder(w).Encode(map[string]any{"hosts": []map[string]any{
+				{"ip": "10.0.0.8", "port": 8888, "weight": 10.0, "healthy": true, "enabled": true},
+				{"ip": "10.0.0.9", "port": 8888, "weight": 10.0, "healthy": true, "enabled": false}, // 下线中
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer nacos.Close()
+
+	reg, err := New(Config{Type: TypeNacos, Endpoints: []string{nacos.URL}, Namespace: "prod", TTL: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Close()
+
+	if err := reg.Register(context.Background(), Instance{Service: "recommendation-service", Addr: "10.0.0.8:8888"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n, params := registrations, lastParams
+		mu.Unlock()
+		if n >= 2 {
+			if !strings.Contains(params, "namespaceId=prod") || !strings.Contains(params, "ephemeral=true") {
+				t.Errorf("register params = %s, want namespace and ephemeral", params)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("registrations = %d, want a re-registration after the heartbeat reported the instance missing", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	got, err := reg.Resolve(context.Background(), "recommendation-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Addr != "10.0.0.8:8888" {
+		t.Errorf("Resolve() = %+v, want only the enabled instance", got)
+	}
+}
@@ -0,0 +1,208 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// etcdPrefix key 的前缀（和 Kitex 官方扩展 registry-etcd 相同）：kitex/registry-etcd/{服务名}/{地址}
+const etcdPrefix = "kitex/registry-etcd/"
+
+// EtcdRegistry etcd 实现
+//
+// 每个实例一个租约（lease）：key 绑定租约，每 TTL/3 续约一次；
+// 进程崩溃、没有注销时，key 在 TTL 之后随租约一起删除。
+// 续约时发现租约已经不存在（etcd 重启、网络分区超过 TTL），重新申请租约并写入 key。
+type EtcdRegistry struct {
+	cfg        Config
+	keepalives *keepalives
+
+	mu     sync.Mutex
+	leases map[string]int64 // 服务名/地址 → 租约ID
+}
+
+// NewEtcdRegistry 构造函数（cfg 的默认值见 New）
+func NewEtcdRegistry(cfg Config) *EtcdRegistry {
+	return &EtcdRegistry{
+		cfg:        cfg,
+		keepalives: newKeepalives(),
+		leases:     make(map[string]int64),
+	}
+}
+
+// etcdInstance key 对应的 value（JSON，字段和 registry-etcd 相同）
+type etcdInstance struct {
+	Network string            `json:"network"`
+	Address string            `json:"address"`
+	Weight  int               `json:"weight"`
+	Tags    map[string]string `json:"tags"`
+}
+
+// etcdLease /v3/lease/grant、/v3/lease/keepalive 的请求和响应（int64 在 JSON gateway 中是字符串）
+type etcdLease struct {
+	ID  int64 `json:"ID,string"`
+	TTL int64 `json:"TTL,string,omitempty"`
+}
+
+// etcdKeyValue /v3/kv/range 响应中的一个键值（只用到 value，base64）
+type etcdKeyValue struct {
+	Value string `json:"value"`
+}
+
+// Name 实现接口
+func (r *EtcdRegistry) Name() string {
+	return TypeEtcd
+}
+
+// Register 实现接口：申请租约、写入 key，然后在后台续约
+func (r *EtcdRegistry) Register(ctx context.Context, instance Instance) error {
+	if err := r.put(ctx, instance); err != nil {
+		return fmt.Errorf("etcd register %s: %w", instanceKey(instance), err)
+	}
+	r.keepalives.start(instanceKey(instance), r.cfg.TTL/3, func(ctx context.Context) {
+		r.keepalive(ctx, instance)
+	})
+	return nil
+}
+
+// Deregister 实现接口：停止续约，删除 key 并撤销租约
+func (r *EtcdRegistry) Deregister(ctx context.Context, instance Instance) error {
+	key := instanceKey(instance)
+	r.keepalives.stop(key)
+
+	r.mu.Lock()
+	leaseID, ok := r.leases[key]
+	delete(r.leases, key)
+	r.mu.Unlock()
+
+	if err := r.post(ctx, "/v3/kv/deleterange", map[string]string{"key": encodeKey(etcdKey(instance))}, nil); err != nil {
+		return fmt.Errorf("etcd deregister %s: %w", key, err)
+	}
+	if ok {
+		_ = r.post(ctx, "/v3/lease/revoke", etcdLease{ID: leaseID}, nil) // key 已经删除，撤销失败时租约自己过期
+	}
+	return nil
+}
+
+// Resolve 实现接口：按前缀查询服务的所有 key
+func (r *EtcdRegistry) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	prefix := etcdPrefix + service + "/"
+	var resp struct {
+		Kvs []etcdKeyValue `json:"kvs"`
+	}
+	err := r.post(ctx, "/v3/kv/range", map[string]string{
+		"key":       encodeKey(prefix),
+		"range_end": encodeKey(prefixEnd(prefix)),
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("etcd resolve %s: %w", service, err)
+	}
+
+	instances := make([]Instance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			continue
+		}
+		var info etcdInstance
+		if err := json.Unmarshal(value, &info); err != nil || info.Address == "" {
+			continue // 其他程序写入的、格式不对的 key
+		}
+		instances = append(instances, Instance{Service: service, Addr: info.Address, Weight: info.Weight, Tags: info.Tags})
+	}
+	return instances, nil
+}
+
+// Close 实现接口
+func (r *EtcdRegistry) Close() {
+	r.keepalives.close()
+}
+
+// put 辅助方法：申请租约，写入绑定租约的 key
+func (r *EtcdRegistry) put(ctx context.Context, instance Instance) error {
+	var lease etcdLease
+	if err := r.post(ctx, "/v3/lease/grant", map[string]int64{"TTL": int64(r.cfg.TTL / time.Second)}, &lease); err != nil {
+		return err
+	}
+	weight := instance.Weight
+	if weight <= 0 {
+		weight = DefaultWeight
+	}
+	value, err := json.Marshal(etcdInstance{Network: "tcp", Address: instance.Addr, Weight: weight, Tags: instance.Tags})
+	if err != nil {
+		return err
+	}
+	err = r.post(ctx, "/v3/kv/put", map[string]string{
+		"key":   encodeKey(etcdKey(instance)),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": fmt.Sprint(lease.ID),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.leases[instanceKey(instance)] = lease.ID
+	r.mu.Unlock()
+	return nil
+}
+
+// keepalive 辅助方法：续约一次；租约已经不存在时重新注册（失败时等下一次）
+func (r *EtcdRegistry) keepalive(ctx context.Context, instance Instance) {
+	r.mu.Lock()
+	leaseID := r.leases[instanceKey(instance)]
+	r.mu.Unlock()
+
+	var resp struct {
+		Result etcdLease `json:"result"`
+	}
+	err := r.post(ctx, "/v3/lease/keepalive", etcdLease{ID: leaseID}, &resp)
+	if err == nil && resp.Result.TTL > 0 {
+		return
+	}
+	_ = r.put(ctx, instance)
+}
+
+// post 辅助方法：调用 JSON gateway
+func (r *EtcdRegistry) post(ctx context.Context, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return call(ctx, r.cfg.HTTPClient, r.cfg.Endpoints, func(ctx context.Context, base string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, out)
+}
+
+// etcdKey 辅助函数：实例的 key
+func etcdKey(instance Instance) string {
+	return etcdPrefix + instance.Service + "/" + instance.Addr
+}
+
+// encodeKey 辅助函数：JSON gateway 中 key、value 是 base64
+func encodeKey(key string) string {
+	return base64.StdEncoding.EncodeToString([]byte(key))
+}
+
+// prefixEnd 辅助函数：前缀查询的 range_end（最后一个字节加一）
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return "\x00" // 前缀全是 0xff：查询到最后
+}
@@ -0,0 +1,185 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// nacosInstanceNotFound 心跳响应中的 code：实例不存在（Nacos 重启、心跳超时被删除）
+const nacosInstanceNotFound = 20404
+
+// NacosRegistry Nacos 实现
+//
+// 注册为临时实例（ephemeral），每 TTL/3 发送一次心跳；
+// 心跳超时（TTL）之后 Nacos 把实例标记为不健康，2×TTL 之后删除（通过 preserved.* 元数据设置）。
+// 心跳响应实例不存在时重新注册。
+type NacosRegistry struct {
+	cfg        Config
+	keepalives *keepalives
+}
+
+// NewNacosRegistry 构造函数（cfg 的默认值见 New）
+func NewNacosRegistry(cfg Config) *NacosRegistry {
+	return &NacosRegistry{cfg: cfg, keepalives: newKeepalives()}
+}
+
+// nacosHost /nacos/v1/ns/instance/list 响应中的一个实例
+type nacosHost struct {
+	IP       string            `json:"ip"`
+	Port     int               `json:"port"`
+	Weight   float64           `json:"weight"`
+	Healthy  bool              `json:"healthy"`
+	Enabled  bool              `json:"enabled"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// Name 实现接口
+func (r *NacosRegistry) Name() string {
+	return TypeNacos
+}
+
+// Register 实现接口：注册临时实例，然后在后台发送心跳
+func (r *NacosRegistry) Register(ctx context.Context, instance Instance) error {
+	params, err := r.instanceParams(instance)
+	if err != nil {
+		return fmt.Errorf("nacos register %s: %w", instanceKey(instance), err)
+	}
+	if err := r.do(ctx, http.MethodPost, "/nacos/v1/ns/instance", params, nil); err != nil {
+		return fmt.Errorf("nacos register %s: %w", instanceKey(instance), err)
+	}
+	r.keepalives.start(instanceKey(instance), r.cfg.TTL/3, func(ctx context.Context) {
+		r.beat(ctx, params)
+	})
+	return nil
+}
+
+// Deregister 实现接口：停止心跳，注销实例
+func (r *NacosRegistry) Deregister(ctx context.Context, instance Instance) error {
+	r.keepalives.stop(instanceKey(instance))
+	params, err := r.instanceParams(instance)
+	if err != nil {
+		return fmt.Errorf("nacos deregister %s: %w", instanceKey(instance), err)
+	}
+	if err := r.do(ctx, http.MethodDelete, "/nacos/v1/ns/instance", params, nil); err != nil {
+		return fmt.Errorf("nacos deregister %s: %w", instanceKey(instance), err)
+	}
+	return nil
+}
+
+// Resolve 实现接口：查询健康、启用的实例
+func (r *NacosRegistry) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	params := url.Values{
+		"serviceName": {service},
+		"healthyOnly": {"true"},
+	}
+	if r.cfg.Namespace != "" {
+		params.Set("namespaceId", r.cfg.Namespace)
+	}
+	var resp struct {
+		Hosts []nacosHost `json:"hosts"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/nacos/v1/ns/instance/list", params, &resp); err != nil {
+		return nil, fmt.Errorf("nacos resolve %s: %w", service, err)
+	}
+
+	instances := make([]Instance, 0, len(resp.Hosts))
+	for _, host := range resp.Hosts {
+		if !host.Healthy || !host.Enabled {
+			continue
+		}
+		weight := int(host.Weight)
+		if weight <= 0 {
+			weight = DefaultWeight
+		}
+		instances = append(instances, Instance{
+			Service: service,
+			Addr:    net.JoinHostPort(host.IP, strconv.Itoa(host.Port)),
+			Weight:  weight,
+			Tags:    host.Metadata,
+		})
+	}
+	return instances, nil
+}
+
+// Close 实现接口
+func (r *NacosRegistry) Close() {
+	r.keepalives.close()
+}
+
+// beat 辅助方法：发送一次心跳；实例不存在时重新注册（失败时等下一次）
+func (r *NacosRegistry) beat(ctx context.Context, params url.Values) {
+	port, _ := strconv.Atoi(params.Get("port"))
+	weight, _ := strconv.Atoi(params.Get("weight"))
+	beat, _ := json.Marshal(map[string]any{
+		"serviceName": params.Get("serviceName"),
+		"ip":          params.Get("ip"),
+		"port":        port,
+		"weight":      weight,
+		"metadata":    json.RawMessage(params.Get("metadata")),
+		"scheduled":   true,
+	})
+	beatParams := url.Values{
+		"serviceName": {params.Get("serviceName")},
+		"beat":        {string(beat)},
+	}
+	if r.cfg.Namespace != "" {
+		beatParams.Set("namespaceId", r.cfg.Namespace)
+	}
+	var resp struct {
+		Code int `json:"code"`
+	}
+	err := r.do(ctx, http.MethodPut, "/nacos/v1/ns/instance/beat", beatParams, &resp)
+	if err == nil && resp.Code != nacosInstanceNotFound {
+		return
+	}
+	_ = r.do(ctx, http.MethodPost, "/nacos/v1/ns/instance", params, nil)
+}
+
+// instanceParams 辅助方法：注册、注销接口的参数
+func (r *NacosRegistry) instanceParams(instance Instance) (url.Values, error) {
+	host, port, err := net.SplitHostPort(instance.Addr)
+	if err != nil {
+		return nil, err
+	}
+	weight := instance.Weight
+	if weight <= 0 {
+		weight = DefaultWeight
+	}
+	metadata := map[string]string{
+		"preserved.heart.beat.interval": strconv.FormatInt((r.cfg.TTL / 3).Milliseconds(), 10),
+		"preserved.heart.beat.timeout":  strconv.FormatInt(r.cfg.TTL.Milliseconds(), 10),
+		"preserved.ip.delete.timeout":   strconv.FormatInt((2 * r.cfg.TTL).Milliseconds(), 10),
+	}
+	for key, value := range instance.Tags {
+		metadata[key] = value
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"serviceName": {instance.Service},
+		"ip":          {host},
+		"port":        {port},
+		"weight":      {strconv.Itoa(weight)},
+		"ephemeral":   {"true"},
+		"metadata":    {string(encoded)},
+	}
+	if r.cfg.Namespace != "" {
+		params.Set("namespaceId", r.cfg.Namespace)
+	}
+	return params, nil
+}
+
+// do 辅助方法：调用 OpenAPI（参数放在 query 中，Nacos v1 的所有接口都支持）
+func (r *NacosRegistry) do(ctx context.Context, method, path string, params url.Values, out any) error {
+	return call(ctx, r.cfg.HTTPClient, r.cfg.Endpoints, func(ctx context.Context, base string) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, method, base+path+"?"+params.Encode(), nil)
+	}, out)
+}
@@ -0,0 +1,59 @@
+// Package health 依赖的健康检查（供诊断接口使用）
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"service/application/service"
+)
+
+// Database 数据库健康检查：Ping 连接池
+func Database(db *gorm.DB) service.HealthCheck {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Redis Redis 健康检查：PING
+func Redis(rdb redis.UniversalClient) service.HealthCheck {
+	return func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	}
+}
+
+// Reachable HTTP 服务健康检查：能否建立 TCP 连接
+//
+// 下游服务没有统一的健康检查接口，这里只检查网络是否可达，
+// 不发送业务请求（不会在下游产生访问记录或计入限流）。
+func Reachable(baseURL string) service.HealthCheck {
+	return func(ctx context.Context) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		host := u.Host
+		if u.Port() == "" {
+			port := "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+			host = net.JoinHostPort(u.Hostname(), port)
+		}
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", host)
+		if err != nil {
+			return fmt.Errorf("dial %s: %w", host, err)
+		}
+		return conn.Close()
+	}
+}
@@ -0,0 +1,87 @@
+// Package lifecycle 进程退出时释放依赖初始化过程中创建的资源
+//
+// Provider 创建连接池、后台协程时把释放函数登记到 Closers，
+// 进程收到退出信号、停止接收请求之后，由 main 统一调用 Close：
+//
+//	closers := lifecycle.NewClosers()
+//	srv := InitializeRecommendationServer(closers)  // Provider 登记数据库、Redis 等
+//	...
+//	closers.Close(ctx)                               // 逆序释放：后创建的先关闭
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Closer 释放一个资源（ctx 是整个退出过程的截止时间）
+type Closer func(ctx context.Context) error
+
+// namedCloser 登记的释放函数（name 用于错误信息）
+type namedCloser struct {
+	name  string
+	close Closer
+}
+
+// Closers 释放函数的登记表
+//
+// 按登记的逆序释放：后创建的对象依赖先创建的（bandit 依赖数据库连接），
+// 必须先停止后台写入，再关闭连接池。并发安全。
+type Closers struct {
+	mu      sync.Mutex
+	closers []namedCloser
+	closed  bool
+}
+
+// NewClosers 构造函数
+func NewClosers() *Closers {
+	return &Closers{}
+}
+
+// Add 登记释放函数
+//
+// 已经 Close 之后登记的资源（退出过程中才完成初始化）立即释放。
+func (c *Closers) Add(name string, closer Closer) {
+	c.mu.Lock()
+	if !c.closed {
+		c.closers = append(c.closers, namedCloser{name: name, close: closer})
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	_ = closer(context.Background())
+}
+
+// AddFunc 登记没有返回值的释放函数（如 StrategyBandit.Close）
+func (c *Closers) AddFunc(name string, closer func()) {
+	c.Add(name, func(context.Context) error {
+		closer()
+		return nil
+	})
+}
+
+// Close 逆序调用所有释放函数
+//
+// 某个资源释放失败不影响其他资源，返回合并之后的错误；
+// ctx 超时之后剩余的资源不再释放（进程即将退出，由操作系统回收）。
+// 只有第一次调用生效。
+func (c *Closers) Close(ctx context.Context) error {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers, c.closed = nil, true
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", closers[i].name, err))
+			continue
+		}
+		if err := closers[i].close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", closers[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
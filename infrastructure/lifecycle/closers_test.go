@@ -0,0 +1,40 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestClosers_CloseInReverseOrder(t *testing.T) {
+	closers := NewClosers()
+	var closed []string
+	for _, name := range []string{"database", "redis", "bandit"} {
+		closers.Add(name, func(context.Context) error {
+			closed = append(closed, name)
+			if name == "redis" {
+				return errors.New("connection reset")
+			}
+			return nil
+		})
+	}
+
+	err := closers.Close(context.Background())
+	if want := []string{"bandit", "redis", "database"}; !slices.Equal(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+	if err == nil || !strings.Contains(err.Error(), "close redis") {
+		t.Errorf("Close() error = %v, want the redis failure", err)
+	}
+
+	// 只有第一次 Close 生效；之后登记的资源立即释放
+	if err := closers.Close(context.Background()); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	closers.AddFunc("late", func() { closed = append(closed, "late") })
+	if closed[len(closed)-1] != "late" {
+		t.Errorf("closer added after Close was not called: %v", closed)
+	}
+}
@@ -0,0 +1,327 @@
+// Package messaging 消息队列的消费者（消费者组、重试、死信队列）
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"service/application/service"
+)
+
+// ErrTooManyDeliveries 消息的投递次数已经超过 MaxAttempts（多次让成员崩溃的毒消息），不再处理
+var ErrTooManyDeliveries = errors.New("messaging: message delivered too many times")
+
+// 一条消息的处理结果（MetricConsumedMessages 的 result 标签）
+const (
+	ResultProcessed  = "processed"   // 处理成功（可能经过重试）
+	ResultRetried    = "retried"     // 处理失败，等待之后重试
+	ResultDeadLetter = "dead_letter" // 转入死信队列
+	ResultDropped    = "dropped"     // 没有死信队列，丢弃（只记录日志）
+)
+
+// Message 一条消息
+type Message struct {
+	Topic      string
+	ID         string // 消息在 topic 中的位置（Kafka 为 partition/offset，Redis Stream 为 entry ID）
+	Payload    []byte
+	Deliveries int // 投递次数（从 1 开始；成员崩溃后消息被组内其他成员接管时增加）
+}
+
+// GroupReader 消费者组中的一个成员：读取分配给自己的消息，处理完之后确认
+//
+// 消费者组由消息队列实现：同一个组的多个实例分担 topic 的消息（Kafka 按 partition 分配，
+// Redis Stream 按消息分配），一条消息只交给组内的一个成员；
+// 成员没有确认就崩溃时，消息重新投递给组内的其他成员（至少一次投递）。
+//
+// 实现：KafkaGroupReader（Fetch → FetchMessage，Ack → CommitMessages）、RedisStreamReader（XREADGROUP / XACK）。
+type GroupReader interface {
+	// Fetch 读取下一批消息（没有消息时阻塞，直到有消息或者 ctx 取消）
+	Fetch(ctx context.Context) ([]Message, error)
+	// Ack 确认消息已经处理完（Kafka 提交位点，Redis Stream XACK），之后不再投递
+	Ack(ctx context.Context, msg Message) error
+}
+
+// Publisher 消息队列生产者（死信队列只需要发送一条消息的能力）
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// Handler 处理一条消息的消息体（如 handler.FollowEventHandler.HandleMessage）
+//
+// 返回错误时按 Retryable 决定重试还是直接转入死信队列；处理必须是幂等的。
+type Handler func(ctx context.Context, payload []byte) error
+
+// DeadLetter 死信队列中的一条记录：原始消息 + 失败原因
+//
+// 修复问题后，按 Payload 原样重新发送到 Topic 即可重放。
+type DeadLetter struct {
+	Consumer string    `json:"consumer"`
+	Topic    string    `json:"topic"`
+	ID       string    `json:"id"`
+	Payload  []byte    `json:"payload"` // 原始消息体（格式错误的消息也能完整保留）
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// ConsumerConfig 重试参数
+type ConsumerConfig struct {
+	// MaxAttempts 一条消息最多处理几次（包括第一次），默认 5
+	MaxAttempts int
+	// Backoff 第一次重试前的等待时间（之后每次翻倍），默认 100ms
+	Backoff time.Duration
+	// MaxBackoff 重试等待时间的上限，默认 5 秒
+	MaxBackoff time.Duration
+}
+
+// DefaultConsumerConfig 默认参数：最多 5 次，等待 100ms、200ms、400ms、800ms
+func DefaultConsumerConfig() ConsumerConfig {
+	return ConsumerConfig{
+		MaxAttempts: 5,
+		Backoff:     100 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	}
+}
+
+// Consumer 消息消费者：从消费者组读取消息，失败时重试，重试用完或者不值得重试时转入死信队列
+//
+// 处理顺序：
+// 同一个成员的消息逐条处理，前一条处理完（成功或者转入死信队列）才处理下一条：
+// 同一个 partition 内的消息（如同一个用户的关注、取关）按顺序生效。
+// 需要更高吞吐量时增加组内的成员（实例），而不是在一个成员内并发。
+//
+// 确认：
+// - 处理成功、转入死信队列之后确认
+// - 重试期间服务退出（ctx 取消）不确认：消息之后重新投递（处理是幂等的）
+// - 死信队列发送失败时一直重试发送，不确认也不跳过：跳过会丢消息，并且 Kafka 的位点提交会把它一起提交掉
+//
+// 消息的投递次数（Deliveries）已经超过 MaxAttempts 时直接转入死信队列：
+// 这条消息多次让成员崩溃（毒消息），不再处理。
+type Consumer struct {
+	name            string
+	reader          GroupReader
+	handle          Handler
+	cfg             ConsumerConfig
+	retryable       func(error) bool
+	deadLetter      Publisher
+	deadLetterTopic string
+	logger          service.Logger
+	metrics         service.Metrics
+	now             func() time.Time
+	sleep           func(ctx context.Context, d time.Duration) bool
+}
+
+// ConsumerOption 可选配置
+type ConsumerOption func(*Consumer)
+
+// WithRetryable 判断错误是否值得重试（默认所有错误都重试）
+//
+// 不值得重试的错误（如格式错误的消息）直接转入死信队列，不占用重试的时间。
+func WithRetryable(retryable func(error) bool) ConsumerOption {
+	return func(c *Consumer) {
+		c.retryable = retryable
+	}
+}
+
+// WithDeadLetter 处理失败的消息发送到死信队列（不设置时只记录日志后丢弃）
+func WithDeadLetter(publisher Publisher, topic string) ConsumerOption {
+	return func(c *Consumer) {
+		c.deadLetter = publisher
+		c.deadLetterTopic = topic
+	}
+}
+
+// WithConsumerObservability 注入日志和监控指标（可以为 nil）
+func WithConsumerObservability(logger service.Logger, metrics service.Metrics) ConsumerOption {
+	return func(c *Consumer) {
+		c.logger = logger
+		c.metrics = metrics
+	}
+}
+
+// NewConsumer 构造函数（没有设置的参数使用默认值）
+//
+// 参数：
+// - name: 消费者名（日志字段、监控标签，如 "follow_events"）
+// - reader: 消费者组中的这个成员
+// - handle: 消息处理函数
+func NewConsumer(name string, reader GroupReader, handle Handler, cfg ConsumerConfig, opts ...ConsumerOption) *Consumer {
+	defaults := DefaultConsumerConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaults.Backoff
+	}
+	if cfg.MaxBackoff < cfg.Backoff {
+		cfg.MaxBackoff = max(defaults.MaxBackoff, cfg.Backoff)
+	}
+	c := &Consumer{
+		name:      name,
+		reader:    reader,
+		handle:    handle,
+		cfg:       cfg,
+		retryable: func(error) bool { return true },
+		now:       time.Now,
+		sleep:     sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Run 持续消费，直到 ctx 取消
+//
+// 读取失败（如消息队列暂时不可用）时等待一段时间后重新读取，不退出。
+func (c *Consumer) Run(ctx context.Context) {
+	backoff := c.cfg.Backoff
+	for ctx.Err() == nil {
+		msgs, err := c.reader.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.log(ctx, service.LogLevelWarn, "fetch messages failed", map[string]any{
+				service.FieldError: err.Error(),
+			})
+			if !c.sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, c.cfg.MaxBackoff)
+			continue
+		}
+		backoff = c.cfg.Backoff
+		for _, msg := range msgs {
+			if !c.process(ctx, msg) {
+				return
+			}
+		}
+	}
+}
+
+// process 处理一条消息：成功或者转入死信队列之后确认；ctx 取消时返回 false（不确认）
+func (c *Consumer) process(ctx context.Context, msg Message) bool {
+	var err error
+	attempts := 0
+	if msg.Deliveries <= c.cfg.MaxAttempts {
+		backoff := c.cfg.Backoff
+		for {
+			attempts++
+			if err = c.handle(ctx, msg.Payload); err == nil {
+				c.report(ResultProcessed)
+				return c.ack(ctx, msg)
+			}
+			if ctx.Err() != nil {
+				return false
+			}
+			if !c.retryable(err) || attempts >= c.cfg.MaxAttempts {
+				break
+			}
+			c.report(ResultRetried)
+			if !c.sleep(ctx, backoff) {
+				return false
+			}
+			backoff = min(backoff*2, c.cfg.MaxBackoff)
+		}
+	} else {
+		err = ErrTooManyDeliveries
+	}
+
+	if !c.sendDeadLetter(ctx, msg, err, attempts) {
+		return false
+	}
+	return c.ack(ctx, msg)
+}
+
+// sendDeadLetter 辅助方法：把失败的消息发送到死信队列，发送失败时一直重试（ctx 取消时返回 false）
+func (c *Consumer) sendDeadLetter(ctx context.Context, msg Message, cause error, attempts int) bool {
+	fields := map[string]any{
+		"topic":            msg.Topic,
+		"message_id":       msg.ID,
+		"attempts":         attempts,
+		service.FieldError: cause.Error(),
+	}
+	if c.deadLetter == nil {
+		c.log(ctx, service.LogLevelError, "message dropped", fields)
+		c.report(ResultDropped)
+		return true
+	}
+
+	// 只有基本类型的字段，不会失败
+	record, _ := json.Marshal(DeadLetter{
+		Consumer: c.name,
+		Topic:    msg.Topic,
+		ID:       msg.ID,
+		Payload:  msg.Payload,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: c.now(),
+	})
+	backoff := c.cfg.Backoff
+	for {
+		err := c.deadLetter.Publish(ctx, c.deadLetterTopic, record)
+		if err == nil {
+			break
+		}
+		c.log(ctx, service.LogLevelError, "publish dead letter failed", map[string]any{
+			"message_id":       msg.ID,
+			service.FieldError: err.Error(),
+		})
+		if !c.sleep(ctx, backoff) {
+			return false
+		}
+		backoff = min(backoff*2, c.cfg.MaxBackoff)
+	}
+	c.log(ctx, service.LogLevelWarn, "message sent to dead letter topic", fields)
+	c.report(ResultDeadLetter)
+	return true
+}
+
+// ack 辅助方法：确认消息（失败只记日志：消息之后重新投递，处理是幂等的）
+func (c *Consumer) ack(ctx context.Context, msg Message) bool {
+	if err := c.reader.Ack(ctx, msg); err != nil {
+		c.log(ctx, service.LogLevelWarn, "ack message failed", map[string]any{
+			"message_id":       msg.ID,
+			service.FieldError: err.Error(),
+		})
+	}
+	return ctx.Err() == nil
+}
+
+// report 辅助方法：记录一条消息的处理结果
+func (c *Consumer) report(result string) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.IncCounter(service.MetricConsumedMessages, 1, map[string]string{
+		"consumer": c.name,
+		"result":   result,
+	})
+}
+
+// log 辅助方法：记录一条日志（带上消费者名）
+func (c *Consumer) log(ctx context.Context, level service.LogLevel, msg string, fields map[string]any) {
+	if c.logger == nil {
+		return
+	}
+	entry := map[string]any{"consumer": c.name}
+	for key, value := range fields {
+		entry[key] = value
+	}
+	c.logger.Log(ctx, level, msg, entry)
+}
+
+// sleep 等待 d，ctx 取消时提前返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
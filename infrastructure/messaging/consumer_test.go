@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeReader 测试用消费者组成员：返回一批消息之后取消 ctx
+type fakeReader struct {
+	msgs   []Message
+	cancel context.CancelFunc
+	acked  []string
+}
+
+func (r *fakeReader) Fetch(ctx context.Context) ([]Message, error) {
+	msgs := r.msgs
+	r.msgs = nil
+	if msgs == nil {
+		r.cancel()
+	}
+	return msgs, nil
+}
+
+func (r *fakeReader) Ack(ctx context.Context, msg Message) error {
+	r.acked = append(r.acked, msg.ID)
+	return nil
+}
+
+// fakePublisher 测试用死信队列：前 failures 次发送失败
+type fakePublisher struct {
+	failures int
+	sent     []DeadLetter
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	var record DeadLetter
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return err
+	}
+	p.sent = append(p.sent, record)
+	return nil
+}
+
+var errRejected = errors.New("rejected")
+
+func TestConsumer_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := &fakeReader{cancel: cancel, msgs: []Message{
+		{ID: "ok", Payload: []byte("ok"), Deliveries: 1},
+		{ID: "flaky", Payload: []byte("flaky"), Deliveries: 1},
+		{ID: "failing", Payload: []byte("failing"), Deliveries: 1},
+		{ID: "rejected", Payload: []byte("rejected"), Deliveries: 1},
+		{ID: "poison", Payload: []byte("ok"), Deliveries: 4},
+	}}
+	deadLetters := &fakePublisher{failures: 2}
+	calls := make(map[string]int)
+	handle := func(ctx context.Context, payload []byte) error {
+		calls[string(payload)]++
+		switch string(payload) {
+		case "flaky":
+			if calls["flaky"] < 3 {
+				return errors.New("database unavailable")
+			}
+		case "failing":
+			return errors.New("database unavailable")
+		case "rejected":
+			return errRejected
+		}
+		return nil
+	}
+	var waits []time.Duration
+	c := NewConsumer("test", reader, handle, ConsumerConfig{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: 3 * time.Millisecond},
+		WithRetryable(func(err error) bool { return !errors.Is(err, errRejected) }),
+		WithDeadLetter(deadLetters, "dead-letters"),
+	)
+	c.sleep = func(ctx context.Context, d time.Duration) bool {
+		waits = append(waits, d)
+		return true
+	}
+	c.Run(ctx)
+
+	// 成功、重试后成功、重试用完、不值得重试、毒消息都确认（后三条转入死信队列之后）
+	if len(reader.acked) != 5 {
+		t.Fatalf("acked = %v, want all 5 messages", reader.acked)
+	}
+	if calls["flaky"] != 3 || calls["failing"] != 3 || calls["rejected"] != 1 || calls["ok"] != 1 {
+		t.Errorf("calls = %v, want flaky 3, failing 3, rejected 1, ok 1 (poison not handled)", calls)
+	}
+	want := []struct {
+		id       string
+		attempts int
+	}{{"failing", 3}, {"rejected", 1}, {"poison", 0}}
+	if len(deadLetters.sent) != len(want) {
+		t.Fatalf("dead letters = %+v, want %d", deadLetters.sent, len(want))
+	}
+	for i, w := range want {
+		if got := deadLetters.sent[i]; got.ID != w.id || got.Attempts != w.attempts || got.Consumer != "test" {
+			t.Errorf("dead letter %d = %+v, want %s after %d attempts", i, got, w.id, w.attempts)
+		}
+	}
+	if string(deadLetters.sent[0].Payload) != "failing" {
+		t.Errorf("dead letter payload = %q, want the original message", deadLetters.sent[0].Payload)
+	}
+
+	// 退避翻倍，不超过 MaxBackoff；死信队列发送失败时同样退避重试
+	wantWaits := []time.Duration{1, 2, 1, 2, 1, 2}
+	for i := range wantWaits {
+		wantWaits[i] *= time.Millisecond
+	}
+	if len(waits) != len(wantWaits) {
+		t.Fatalf("waits = %v, want %v", waits, wantWaits)
+	}
+	for i := range waits {
+		if waits[i] != wantWaits[i] {
+			t.Errorf("waits = %v, want %v", waits, wantWaits)
+			break
+		}
+	}
+}
+
+func TestConsumer_StopsWithoutAck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{cancel: cancel, msgs: []Message{{ID: "failing", Deliveries: 1}}}
+	c := NewConsumer("test", reader, func(ctx context.Context, payload []byte) error {
+		return errors.New("database unavailable")
+	}, ConsumerConfig{})
+
+	// 重试期间服务退出：不确认，消息之后重新投递
+	c.sleep = func(ctx context.Context, d time.Duration) bool {
+		cancel()
+		return false
+	}
+	c.Run(ctx)
+	if len(reader.acked) != 0 {
+		t.Errorf("acked = %v, want none", reader.acked)
+	}
+}
@@ -0,0 +1,135 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaFetcher kafka-go Reader 中 KafkaGroupReader 用到的部分（测试时替换）
+type kafkaFetcher interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaGroupReader 消费者组中的一个成员：Kafka 实现（kafka-go Reader，设置 GroupID）
+//
+// 社交服务把关注、取关事件发到 Kafka；和 RedisStreamReader 实现同一个 GroupReader，
+// Consumer 的重试、死信逻辑不变。
+//
+// 消费者组：
+// - partition 由 Kafka 在组内的实例之间分配，实例增减时自动再平衡
+// - 新建的组从最新的消息开始消费（和 Redis Stream 的 "$" 一致），已有的组从提交的位点继续
+// - Ack 同步提交这条消息的位点；实例崩溃后，没有提交的消息在再平衡之后投递给组内其他实例
+//
+// 投递次数：
+// Kafka 不记录一条消息投递过几次，Deliveries 总是 1；
+// 毒消息由 Consumer 的 MaxAttempts 兜底（重试用完转入死信队列并提交位点）。
+type KafkaGroupReader struct {
+	reader kafkaFetcher
+	topic  string
+
+	mu      sync.Mutex
+	pending map[string]kafka.Message // 已经读取、还没有确认的消息（Ack 时提交它的位点）
+}
+
+// NewKafkaGroupReader 构造函数
+//
+// 参数：
+// - brokers: Kafka 集群地址
+// - topic、group: 消费的 topic 和消费者组名（同一个组的实例分担 partition）
+func NewKafkaGroupReader(brokers []string, topic, group string) *KafkaGroupReader {
+	return newKafkaGroupReader(kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     group,
+		StartOffset: kafka.LastOffset,
+		MaxWait:     2 * time.Second, // 不要太长：服务退出时要等这次读取返回
+	}), topic)
+}
+
+// newKafkaGroupReader 构造函数（注入 kafka-go Reader，测试用）
+func newKafkaGroupReader(reader kafkaFetcher, topic string) *KafkaGroupReader {
+	return &KafkaGroupReader{
+		reader:  reader,
+		topic:   topic,
+		pending: make(map[string]kafka.Message),
+	}
+}
+
+// Fetch 实现 GroupReader：读取分配给这个成员的下一条消息（没有消息时阻塞，直到有消息或者 ctx 取消）
+func (r *KafkaGroupReader) Fetch(ctx context.Context) ([]Message, error) {
+	m, err := r.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := kafkaMessageID(m)
+	r.mu.Lock()
+	r.pending[id] = m
+	r.mu.Unlock()
+	return []Message{{
+		Topic:      r.topic,
+		ID:         id,
+		Payload:    m.Value,
+		Deliveries: 1,
+	}}, nil
+}
+
+// Ack 实现 GroupReader：提交这条消息的位点（之后不再投递）
+//
+// Consumer 逐条处理、逐条确认，提交的位点不会越过还没有处理完的消息。
+func (r *KafkaGroupReader) Ack(ctx context.Context, msg Message) error {
+	r.mu.Lock()
+	m, ok := r.pending[msg.ID]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("messaging: kafka message %s was not fetched by this reader", msg.ID)
+	}
+	if err := r.reader.CommitMessages(ctx, m); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	delete(r.pending, msg.ID)
+	r.mu.Unlock()
+	return nil
+}
+
+// Close 离开消费者组（进程退出时调用，组内其他实例立即接管这个成员的 partition）
+func (r *KafkaGroupReader) Close() error {
+	return r.reader.Close()
+}
+
+// kafkaMessageID 消息在 topic 中的位置：partition/offset
+func kafkaMessageID(m kafka.Message) string {
+	return fmt.Sprintf("%d/%d", m.Partition, m.Offset)
+}
+
+// KafkaPublisher 消息队列生产者：Kafka 实现（用作死信队列）
+//
+// topic 由每次 Publish 指定，一个 Publisher 可以发送到多个 topic。
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher 构造函数
+func NewKafkaPublisher(brokers []string) *KafkaPublisher {
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: kafka.RequireAll, // 死信不能丢：所有副本写入才算成功
+	}}
+}
+
+// Publish 实现 Publisher
+func (p *KafkaPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{Topic: topic, Value: payload})
+}
+
+// Close 发送缓冲中的消息并关闭连接（进程退出时调用）
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
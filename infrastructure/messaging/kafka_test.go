@@ -0,0 +1,109 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeKafkaReader 测试用 kafka-go Reader：逐条返回消息，读完之后取消 ctx，记录提交的位点
+type fakeKafkaReader struct {
+	msgs      []kafka.Message
+	cancel    context.CancelFunc
+	committed []kafka.Message
+	closed    bool
+}
+
+func (r *fakeKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.msgs) == 0 {
+		r.cancel()
+		return kafka.Message{}, ctx.Err()
+	}
+	m := r.msgs[0]
+	r.msgs = r.msgs[1:]
+	return m, nil
+}
+
+func (r *fakeKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+func (r *fakeKafkaReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestKafkaGroupReader_WithConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := &fakeKafkaReader{cancel: cancel, msgs: []kafka.Message{
+		{Topic: "social.follow-events", Partition: 0, Offset: 10, Value: []byte("ok")},
+		{Topic: "social.follow-events", Partition: 2, Offset: 7, Value: []byte("rejected")},
+		{Topic: "social.follow-events", Partition: 0, Offset: 11, Value: []byte("flaky")},
+	}}
+	reader := newKafkaGroupReader(fake, "social.follow-events")
+
+	flakyCalls := 0
+	handle := func(ctx context.Context, payload []byte) error {
+		switch string(payload) {
+		case "rejected":
+			return errRejected
+		case "flaky":
+			if flakyCalls++; flakyCalls < 2 {
+				return errors.New("database unavailable")
+			}
+		}
+		return nil
+	}
+	deadLetters := &fakePublisher{}
+	c := NewConsumer("follow_events", reader, handle, ConsumerConfig{MaxAttempts: 3, Backoff: time.Millisecond},
+		WithRetryable(func(err error) bool { return !errors.Is(err, errRejected) }),
+		WithDeadLetter(deadLetters, "social.follow-events.dead-letter"),
+	)
+	c.sleep = func(ctx context.Context, d time.Duration) bool { return true }
+	c.Run(ctx)
+
+	// 成功、转入死信队列、重试后成功的消息都提交位点（按处理顺序）
+	wantCommitted := []struct {
+		partition int
+		offset    int64
+	}{{0, 10}, {2, 7}, {0, 11}}
+	if len(fake.committed) != len(wantCommitted) {
+		t.Fatalf("committed = %+v, want %d messages", fake.committed, len(wantCommitted))
+	}
+	for i, w := range wantCommitted {
+		if got := fake.committed[i]; got.Partition != w.partition || got.Offset != w.offset {
+			t.Errorf("commit %d = %d/%d, want %d/%d", i, got.Partition, got.Offset, w.partition, w.offset)
+		}
+	}
+	if len(reader.pending) != 0 {
+		t.Errorf("pending = %v, want none after ack", reader.pending)
+	}
+
+	// 死信记录 topic 和 partition/offset，可以定位、重放原始消息
+	if len(deadLetters.sent) != 1 {
+		t.Fatalf("dead letters = %+v, want 1", deadLetters.sent)
+	}
+	if got := deadLetters.sent[0]; got.Topic != "social.follow-events" || got.ID != "2/7" || string(got.Payload) != "rejected" {
+		t.Errorf("dead letter = %+v, want social.follow-events 2/7", got)
+	}
+}
+
+func TestKafkaGroupReader_AckUnknownMessage(t *testing.T) {
+	fake := &fakeKafkaReader{}
+	reader := newKafkaGroupReader(fake, "social.follow-events")
+
+	if err := reader.Ack(context.Background(), Message{ID: "0/1"}); err == nil {
+		t.Error("Ack() of a message that was never fetched should fail")
+	}
+	if len(fake.committed) != 0 {
+		t.Errorf("committed = %+v, want none", fake.committed)
+	}
+	if err := reader.Close(); err != nil || !fake.closed {
+		t.Errorf("Close() = %v, closed = %v, want the kafka reader closed", err, fake.closed)
+	}
+}
@@ -0,0 +1,202 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// payloadField Stream 消息中存放消息体的字段
+const payloadField = "payload"
+
+// RedisStreamReader 消费者组中的一个成员：Redis Stream 实现（XREADGROUP / XACK）
+//
+// 为什么有 Redis Stream 的实现？
+// 服务已经依赖 Redis，没有接入 Kafka 的环境（开发、测试、小规模部署）可以直接使用；
+// 和 KafkaGroupReader 实现同一个 GroupReader 接口，按 follow_events.backend 选择，Consumer 的重试、死信逻辑不变。
+//
+// 消费者组：
+// - 第一次读取时创建组（已经存在时忽略），新建的组从创建之后的消息开始消费
+// - 每个实例使用不同的 consumer 名（如主机名），组内的实例分担消息
+// - 实例崩溃后，它没有确认的消息在 claimIdle 之后被其他实例接管（XAUTOCLAIM）
+//
+// 接管时从 XPENDING 读取投递次数，超过 MaxAttempts 的毒消息由 Consumer 直接转入死信队列。
+type RedisStreamReader struct {
+	rdb       redis.UniversalClient
+	stream    string
+	group     string
+	consumer  string
+	batch     int64
+	block     time.Duration
+	claimIdle time.Duration
+
+	mu          sync.Mutex
+	created     bool      // 消费者组已经创建
+	claimCursor string    // XAUTOCLAIM 的游标（一轮扫描完回到 0-0）
+	lastClaim   time.Time // 上一次接管的时间（每 claimIdle 接管一次）
+	now         func() time.Time
+}
+
+// NewRedisStreamReader 构造函数
+//
+// 参数：
+// - stream、group: Stream 的 key 和消费者组名
+// - consumer: 组内这个成员的名字（每个实例不同，重启后保持不变时可以继续处理自己没有确认的消息）
+// - claimIdle: 其他成员超过这个时间没有确认的消息被接管，应该比一条消息最长的处理时间（含重试）长得多
+func NewRedisStreamReader(rdb redis.UniversalClient, stream, group, consumer string, claimIdle time.Duration) *RedisStreamReader {
+	return &RedisStreamReader{
+		rdb:         rdb,
+		stream:      stream,
+		group:       group,
+		consumer:    consumer,
+		batch:       100,
+		block:       2 * time.Second, // 不要太长：服务退出时要等这次读取返回
+		claimIdle:   claimIdle,
+		claimCursor: "0-0",
+		now:         time.Now,
+	}
+}
+
+// Fetch 实现 GroupReader：先接管其他成员超时没有确认的消息，再读取新消息
+//
+// block 时间内没有新消息时返回空的一批（Consumer 会再次调用）。
+func (r *RedisStreamReader) Fetch(ctx context.Context) ([]Message, error) {
+	if err := r.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+	if msgs, err := r.claim(ctx); err != nil || len(msgs) > 0 {
+		return msgs, err
+	}
+
+	streams, err := r.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    r.group,
+		Consumer: r.consumer,
+		Streams:  []string{r.stream, ">"},
+		Count:    r.batch,
+		Block:    r.block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	for _, stream := range streams {
+		for _, entry := range stream.Messages {
+			msgs = append(msgs, r.toMessage(entry, 1))
+		}
+	}
+	return msgs, nil
+}
+
+// Ack 实现 GroupReader
+func (r *RedisStreamReader) Ack(ctx context.Context, msg Message) error {
+	return r.rdb.XAck(ctx, r.stream, r.group, msg.ID).Err()
+}
+
+// ensureGroup 辅助方法：创建消费者组（已经存在时忽略；创建失败时下一次读取再试）
+func (r *RedisStreamReader) ensureGroup(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.created {
+		return nil
+	}
+	err := r.rdb.XGroupCreateMkStream(ctx, r.stream, r.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	r.created = true
+	return nil
+}
+
+// claim 辅助方法：接管超时没有确认的消息（每 claimIdle 扫描一次，一次最多 batch 条）
+func (r *RedisStreamReader) claim(ctx context.Context) ([]Message, error) {
+	r.mu.Lock()
+	if r.claimIdle <= 0 || (r.claimCursor == "0-0" && r.now().Sub(r.lastClaim) < r.claimIdle) {
+		r.mu.Unlock()
+		return nil, nil
+	}
+	if r.claimCursor == "0-0" {
+		r.lastClaim = r.now()
+	}
+	cursor := r.claimCursor
+	r.mu.Unlock()
+
+	entries, next, err := r.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   r.stream,
+		Group:    r.group,
+		Consumer: r.consumer,
+		MinIdle:  r.claimIdle,
+		Start:    cursor,
+		Count:    r.batch,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.claimCursor = next
+	r.mu.Unlock()
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	// 接管的消息至少投递过两次；投递次数以 XPENDING 为准（XAUTOCLAIM 已经把它加一）
+	deliveries := make(map[string]int, len(entries))
+	pending, err := r.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   r.stream,
+		Group:    r.group,
+		Start:    entries[0].ID,
+		End:      entries[len(entries)-1].ID,
+		Count:    int64(len(entries)),
+		Consumer: r.consumer,
+	}).Result()
+	if err == nil {
+		for _, p := range pending {
+			deliveries[p.ID] = int(p.RetryCount)
+		}
+	}
+	msgs := make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		msgs = append(msgs, r.toMessage(entry, max(deliveries[entry.ID], 2)))
+	}
+	return msgs, nil
+}
+
+// toMessage 辅助方法：Stream 消息 → Message（没有 payload 字段时消息体为空，由处理函数拒绝）
+func (r *RedisStreamReader) toMessage(entry redis.XMessage, deliveries int) Message {
+	payload, _ := entry.Values[payloadField].(string)
+	return Message{
+		Topic:      r.stream,
+		ID:         entry.ID,
+		Payload:    []byte(payload),
+		Deliveries: deliveries,
+	}
+}
+
+// RedisStreamPublisher 消息队列生产者：Redis Stream 实现（XADD）
+//
+// 用作死信队列时，死信 Stream 一般很少有消息；maxLen 限制长度，防止异常时无限增长。
+type RedisStreamPublisher struct {
+	rdb    redis.UniversalClient
+	maxLen int64
+}
+
+// NewRedisStreamPublisher 构造函数（maxLen 为 0 时不限制 Stream 的长度）
+func NewRedisStreamPublisher(rdb redis.UniversalClient, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{rdb: rdb, maxLen: maxLen}
+}
+
+// Publish 实现 Publisher（和 capture.Publisher 相同的接口）
+func (p *RedisStreamPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]any{payloadField: payload},
+	}).Err()
+}
@@ -0,0 +1,151 @@
+// Package migration 存储迁移的辅助工具（双读比较）
+package migration
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"service/application/service"
+)
+
+const (
+	// defaultShadowTimeout shadow 查询的超时时间（shadow 查询不跟随请求的 context）
+	defaultShadowTimeout = 2 * time.Second
+	// defaultMaxInFlight 同时进行的 shadow 查询上限（超过时跳过比较，不排队）
+	defaultMaxInFlight = 64
+)
+
+// 双读比较的结果（MetricDualReadComparisons 的 result 标签）
+const (
+	ResultMatch       = "match"        // 两个实现的结果相同
+	ResultMismatch    = "mismatch"     // 结果不同（同时记录一条 warn 日志）
+	ResultShadowError = "shadow_error" // shadow 查询失败
+	ResultSkipped     = "skipped"      // shadow 查询太多，跳过了这次比较
+)
+
+// DualReader 双读比较：存储迁移期间同时查询新旧两个实现，异步比较结果
+//
+// 为什么需要？
+// 把仓储从一个存储迁移到另一个（如关注关系从 MySQL 迁到图数据库）时，
+// 数据同步、查询语义（排序、去重、时间边界）上的差异很难靠测试发现完全。
+// 双读让线上真实流量同时查询两个实现：
+// - 调用方拿到的始终是主实现的结果，延迟不受影响（shadow 查询在后台进行）
+// - 两边结果不同时上报监控、记录日志，差异率降到可以接受之后再切换主实现
+//
+// 保护措施：
+// - 按 sampleRate 采样，只比较一部分请求
+// - 同时进行的 shadow 查询有上限，超过时跳过（不拖垮 shadow 存储，也不堆积 goroutine）
+// - shadow 查询使用独立的超时，请求结束后仍然可以完成
+//
+// 主实现查询失败时不比较（没有可以比较的结果）。
+type DualReader struct {
+	name       string
+	sampleRate float64
+	timeout    time.Duration
+	slots      chan struct{}
+	pending    sync.WaitGroup
+	logger     service.Logger
+	metrics    service.Metrics
+	random     func() float64
+}
+
+// NewDualReader 构造函数
+//
+// 参数：
+// - name: 仓储名（监控标签、日志字段，如 "social_graph"）
+// - sampleRate: 比较的请求比例（0~1），为 0 时全部比较
+// - logger、metrics: 差异的日志和监控（可以为 nil）
+func NewDualReader(name string, sampleRate float64, logger service.Logger, metrics service.Metrics) *DualReader {
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &DualReader{
+		name:       name,
+		sampleRate: sampleRate,
+		timeout:    defaultShadowTimeout,
+		slots:      make(chan struct{}, defaultMaxInFlight),
+		logger:     logger,
+		metrics:    metrics,
+		random:     rand.Float64,
+	}
+}
+
+// Wait 等待进行中的 shadow 查询全部完成（服务退出、测试时使用）
+func (r *DualReader) Wait() {
+	r.pending.Wait()
+}
+
+// compare 在后台查询 shadow 实现，和主实现的结果比较
+//
+// diff 返回两个结果的差异描述（相同时返回空字符串），写入日志。
+// fields 是附加的日志字段（如用户ID），可以为 nil。
+func compare[T any](
+	ctx context.Context,
+	r *DualReader,
+	method string,
+	primary T,
+	shadow func(ctx context.Context) (T, error),
+	diff func(primary, shadow T) string,
+	fields map[string]any,
+) {
+	if r.sampleRate < 1 && r.random() >= r.sampleRate {
+		return
+	}
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.report(method, ResultSkipped)
+		return
+	}
+
+	r.pending.Add(1)
+	go func() {
+		defer r.pending.Done()
+		defer func() { <-r.slots }()
+
+		shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+		defer cancel()
+		result, err := shadow(shadowCtx)
+		if err != nil {
+			r.report(method, ResultShadowError)
+			r.log(shadowCtx, method, "dual read shadow query failed", err.Error(), fields)
+			return
+		}
+		if d := diff(primary, result); d != "" {
+			r.report(method, ResultMismatch)
+			r.log(shadowCtx, method, "dual read mismatch", d, fields)
+			return
+		}
+		r.report(method, ResultMatch)
+	}()
+}
+
+// report 辅助方法：上报一次比较的结果
+func (r *DualReader) report(method, result string) {
+	if r.metrics == nil {
+		return
+	}
+	r.metrics.IncCounter(service.MetricDualReadComparisons, 1, map[string]string{
+		"repository": r.name,
+		"method":     method,
+		"result":     result,
+	})
+}
+
+// log 辅助方法：记录差异或 shadow 查询失败
+func (r *DualReader) log(ctx context.Context, method, msg, detail string, fields map[string]any) {
+	if r.logger == nil {
+		return
+	}
+	entry := map[string]any{
+		"repository": r.name,
+		"method":     method,
+		"detail":     detail,
+	}
+	for key, value := range fields {
+		entry[key] = value
+	}
+	r.logger.Log(ctx, service.LogLevelWarn, msg, entry)
+}
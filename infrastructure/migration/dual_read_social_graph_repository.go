@@ -0,0 +1,256 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"service/application/service"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// DualReadSocialGraphRepository 双读的社交图谱仓储（装饰器）
+//
+// 返回主实现的结果，后台查询 shadow 实现并比较：
+// - GetFollowings、GetRecentFollowings、GetMutualFollows：按集合比较（不同存储对同一时间关注的人排序可能不同，不算差异）
+// - GetRecentFollowingsBatch：每个用户按集合比较（没有出现在结果中的用户等同于空列表）
+// - CountFollowings、IsFollowing：直接比较
+// - ScanFollowings：只查询主实现（分页遍历的是大列表，逐页比较的代价太高）
+// - ExpandTwoHop：只查询主实现（主实现不支持时返回 repository.ErrTwoHopExpansionUnsupported，调用方回退到逐个查询）
+// - SaveFollow：同时写入两个实现（见 SaveFollow）
+type DualReadSocialGraphRepository struct {
+	primary repository.SocialGraphRepository
+	shadow  repository.SocialGraphRepository
+	reader  *DualReader
+}
+
+// NewDualReadSocialGraphRepository 构造函数
+func NewDualReadSocialGraphRepository(
+	primary repository.SocialGraphRepository,
+	shadow repository.SocialGraphRepository,
+	reader *DualReader,
+) repository.SocialGraphRepository {
+	return &DualReadSocialGraphRepository{
+		primary: primary,
+		shadow:  shadow,
+		reader:  reader,
+	}
+}
+
+// GetFollowings 实现接口
+func (r *DualReadSocialGraphRepository) GetFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.UserID, error) {
+	followings, err := r.primary.GetFollowings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	compare(ctx, r.reader, "GetFollowings", followings, func(ctx context.Context) ([]valueobject.UserID, error) {
+		return r.shadow.GetFollowings(ctx, userID)
+	}, diffUserSets, userFields(userID))
+	return followings, nil
+}
+
+// GetRecentFollowings 实现接口
+func (r *DualReadSocialGraphRepository) GetRecentFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]valueobject.UserID, error) {
+	followings, err := r.primary.GetRecentFollowings(ctx, userID, days)
+	if err != nil {
+		return nil, err
+	}
+	compare(ctx, r.reader, "GetRecentFollowings", followings, func(ctx context.Context) ([]valueobject.UserID, error) {
+		return r.shadow.GetRecentFollowings(ctx, userID, days)
+	}, diffUserSets, userFields(userID))
+	return followings, nil
+}
+
+// GetRecentFollowingsBatch 实现接口
+func (r *DualReadSocialGraphRepository) GetRecentFollowingsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID][]valueobject.UserID, error) {
+	followings, err := r.primary.GetRecentFollowingsBatch(ctx, userIDs, days)
+	if err != nil {
+		return nil, err
+	}
+	compare(ctx, r.reader, "GetRecentFollowingsBatch", followings, func(ctx context.Context) (map[valueobject.UserID][]valueobject.UserID, error) {
+		return r.shadow.GetRecentFollowingsBatch(ctx, userIDs, days)
+	}, diffUserSetMaps, map[string]any{"users": len(userIDs)})
+	return followings, nil
+}
+
+// CountFollowings 实现接口
+func (r *DualReadSocialGraphRepository) CountFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (int, error) {
+	count, err := r.primary.CountFollowings(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	compare(ctx, r.reader, "CountFollowings", count, func(ctx context.Context) (int, error) {
+		return r.shadow.CountFollowings(ctx, userID)
+	}, diffValues[int], userFields(userID))
+	return count, nil
+}
+
+// ScanFollowings 实现接口：只查询主实现
+func (r *DualReadSocialGraphRepository) ScanFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+	return r.primary.ScanFollowings(ctx, userID, pageSize, fn)
+}
+
+// IsFollowing 实现接口
+func (r *DualReadSocialGraphRepository) IsFollowing(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (bool, error) {
+	following, err := r.primary.IsFollowing(ctx, followerID, followingID)
+	if err != nil {
+		return false, err
+	}
+	compare(ctx, r.reader, "IsFollowing", following, func(ctx context.Context) (bool, error) {
+		return r.shadow.IsFollowing(ctx, followerID, followingID)
+	}, diffValues[bool], map[string]any{
+		service.FieldUserID:       followerID.Value(),
+		service.FieldTargetUserID: followingID.Value(),
+	})
+	return following, nil
+}
+
+// ExpandTwoHop 实现 repository.TwoHopExpander
+func (r *DualReadSocialGraphRepository) ExpandTwoHop(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]repository.TwoHopCandidate, error) {
+	expander, ok := r.primary.(repository.TwoHopExpander)
+	if !ok {
+		return nil, repository.ErrTwoHopExpansionUnsupported
+	}
+	return expander.ExpandTwoHop(ctx, userID, days)
+}
+
+// GetMutualFollows 实现接口
+func (r *DualReadSocialGraphRepository) GetMutualFollows(
+	ctx context.Context,
+	userID valueobject.UserID,
+	limit int,
+) ([]valueobject.UserID, error) {
+	mutuals, err := r.primary.GetMutualFollows(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	compare(ctx, r.reader, "GetMutualFollows", mutuals, func(ctx context.Context) ([]valueobject.UserID, error) {
+		return r.shadow.GetMutualFollows(ctx, userID, limit)
+	}, diffUserSets, userFields(userID))
+	return mutuals, nil
+}
+
+// FindFollow 实现接口：只查询主实现（写侧读取，不比较）
+func (r *DualReadSocialGraphRepository) FindFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (*entity.Follow, error) {
+	return r.primary.FindFollow(ctx, followerID, followingID)
+}
+
+// SaveFollow 实现接口：写入主实现，同时写入 shadow 实现（双写）
+//
+// shadow 的数据不跟着写入，后面的比较就全是差异；
+// shadow 写入失败只上报（shadow_error），不影响调用方。
+func (r *DualReadSocialGraphRepository) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	if err := r.primary.SaveFollow(ctx, follow); err != nil {
+		return err
+	}
+	if err := r.shadow.SaveFollow(ctx, follow); err != nil {
+		r.reader.report("SaveFollow", ResultShadowError)
+		r.reader.log(ctx, "SaveFollow", "dual write shadow failed", err.Error(), userFields(follow.FollowerID()))
+	}
+	return nil
+}
+
+// RemoveFollow 实现接口：和 SaveFollow 一样双写（shadow 失败只上报）
+func (r *DualReadSocialGraphRepository) RemoveFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+	removedAt time.Time,
+) error {
+	if err := r.primary.RemoveFollow(ctx, followerID, followingID, removedAt); err != nil {
+		return err
+	}
+	if err := r.shadow.RemoveFollow(ctx, followerID, followingID, removedAt); err != nil {
+		r.reader.report("RemoveFollow", ResultShadowError)
+		r.reader.log(ctx, "RemoveFollow", "dual write shadow failed", err.Error(), userFields(followerID))
+	}
+	return nil
+}
+
+// userFields 辅助函数：差异日志的用户字段
+func userFields(userID valueobject.UserID) map[string]any {
+	return map[string]any{service.FieldUserID: userID.Value()}
+}
+
+// diffValues 辅助函数：比较两个值
+func diffValues[T comparable](primary, shadow T) string {
+	if primary == shadow {
+		return ""
+	}
+	return fmt.Sprintf("primary=%v shadow=%v", primary, shadow)
+}
+
+// diffUserSets 辅助函数：按集合比较两个用户列表（只描述数量，不在日志中列出用户ID）
+func diffUserSets(primary, shadow []valueobject.UserID) string {
+	inPrimary := make(map[valueobject.UserID]bool, len(primary))
+	for _, userID := range primary {
+		inPrimary[userID] = true
+	}
+	missing := len(inPrimary) // 主实现有、shadow 没有
+	extra := 0                // shadow 有、主实现没有
+	seen := make(map[valueobject.UserID]bool, len(shadow))
+	for _, userID := range shadow {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if inPrimary[userID] {
+			missing--
+		} else {
+			extra++
+		}
+	}
+	if missing == 0 && extra == 0 {
+		return ""
+	}
+	return fmt.Sprintf("primary=%d shadow=%d missing_in_shadow=%d extra_in_shadow=%d", len(primary), len(shadow), missing, extra)
+}
+
+// diffUserSetMaps 辅助函数：按用户比较两个批量查询结果（只描述有差异的用户数）
+func diffUserSetMaps(primary, shadow map[valueobject.UserID][]valueobject.UserID) string {
+	differing := 0
+	for userID, followings := range primary {
+		if diffUserSets(followings, shadow[userID]) != "" {
+			differing++
+		}
+	}
+	for userID, followings := range shadow {
+		if _, ok := primary[userID]; !ok && len(followings) > 0 {
+			differing++
+		}
+	}
+	if differing == 0 {
+		return ""
+	}
+	return fmt.Sprintf("differing_users=%d", differing)
+}
@@ -0,0 +1,73 @@
+package migration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+	infraRepository "service/infrastructure/repository"
+)
+
+// recordingMetrics 测试用监控：按 method/result 记录计数
+type recordingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func (m *recordingMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[labels["method"]+"/"+labels["result"]] += delta
+}
+
+func (m *recordingMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
+
+// shadowSocialGraphRepo 测试用 shadow 实现：关注列表顺序不同（不算差异），关注数不同
+type shadowSocialGraphRepo struct {
+	repository.SocialGraphRepository
+}
+
+func (r shadowSocialGraphRepo) GetFollowings(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	followings, err := r.SocialGraphRepository.GetFollowings(ctx, userID)
+	reversed := make([]valueobject.UserID, 0, len(followings))
+	for i := len(followings) - 1; i >= 0; i-- {
+		reversed = append(reversed, followings[i])
+	}
+	return reversed, err
+}
+
+func (r shadowSocialGraphRepo) CountFollowings(ctx context.Context, userID valueobject.UserID) (int, error) {
+	return 0, nil
+}
+
+func TestDualReadSocialGraphRepository(t *testing.T) {
+	primary := infraRepository.NewMockSocialGraphRepository()
+	metrics := &recordingMetrics{counters: make(map[string]int)}
+	reader := NewDualReader("social_graph", 0, nil, metrics)
+	repo := NewDualReadSocialGraphRepository(primary, shadowSocialGraphRepo{primary}, reader)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	userID, _ := valueobject.NewUserID(1)
+	followings, err := repo.GetFollowings(ctx, userID)
+	if err != nil || len(followings) != 3 || followings[0].Value() != 2 {
+		t.Fatalf("GetFollowings = %v, %v; want primary result", followings, err)
+	}
+	count, err := repo.CountFollowings(ctx, userID)
+	if err != nil || count == 0 {
+		t.Fatalf("CountFollowings = %d, %v; want primary result", count, err)
+	}
+	cancel() // 请求结束不影响后台的比较
+	reader.Wait()
+
+	want := map[string]int{
+		"GetFollowings/" + ResultMatch:      1,
+		"CountFollowings/" + ResultMismatch: 1,
+	}
+	for key, n := range want {
+		if metrics.counters[key] != n {
+			t.Errorf("%s = %d, want %d (all: %v)", key, metrics.counters[key], n, metrics.counters)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Dialer 建立 TCP 连接后完成 TLS 握手（实现 Kitex 的 remote.Dialer 接口）
+//
+// 调用其他 Kitex 服务时使用（见 main 包的 rpcClientOptions）。Kitex 默认的 netpoll 传输层不支持 TLS，
+// 需要同时换成基于标准库 net 的 gonet 传输层：
+//
+//	contentservice.NewClient("content-service",
+//	    client.WithDialer(mtls.NewDialer(reloader, "content-service", "spiffe://example.org/content-service")),
+//	    client.WithTransHandlerFactory(gonet.NewCliTransHandlerFactory()),
+//	)
+type Dialer struct {
+	config *tls.Config
+}
+
+// NewDialer 构造函数
+//
+// serverName、serverID 见 Reloader.ClientTLSConfig（serverID 为空时按主机名校验）
+func NewDialer(r *Reloader, serverName, serverID string) *Dialer {
+	return &Dialer{config: r.ClientTLSConfig(serverName, serverID)}
+}
+
+// DialTimeout 实现接口
+func (d *Dialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: d.config}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// HTTPTransport HTTP 客户端的 mTLS 传输层（调用 content、风控等 HTTP 服务时使用）
+//
+// serverName、serverID 见 Reloader.ClientTLSConfig（serverID 为空时按主机名校验）。
+func HTTPTransport(r *Reloader, serverName, serverID string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = r.ClientTLSConfig(serverName, serverID)
+	return transport
+}
@@ -0,0 +1,304 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrPeerNotAllowed = errors.New("mtls: peer identity not allowed")
+
+// Config mTLS 配置
+//
+// 证书来源：
+// - 静态文件：由运维下发的证书、私钥、CA
+// - SPIFFE：由 spiffe-helper 从 SPIRE agent 拿到 SVID，写成同样的三个文件
+//
+// 使用 SPIFFE 时按证书中的 SPIFFE ID（spiffe://trust-domain/service）校验对端：
+// - 入站（本服务是服务端）：AllowedClientIDs 配置允许的调用方
+// - 出站（本服务是客户端）：每个下游各自的 SPIFFE ID，创建客户端配置时传入（ClientTLSConfig）
+//
+// 证书会定期轮换（SVID 通常一小时一换），所以不能只在启动时读一次，
+// 由 Reloader 定期检查文件是否变化并重新加载。
+type Config struct {
+	CertFile string // 本服务证书（PEM）
+	KeyFile  string // 本服务私钥（PEM）
+	CAFile   string // 用于校验对端证书的 CA（PEM）
+
+	// AllowedClientIDs 允许的调用方身份（客户端证书中的 URI SAN，如 SPIFFE ID），为空时只校验 CA
+	AllowedClientIDs []string
+
+	// ReloadInterval 检查证书文件变化的间隔（默认 1 分钟）
+	ReloadInterval time.Duration
+}
+
+// Reloader 证书热加载
+//
+// 为什么需要？
+// 遗留环境没有服务网格（sidecar 负责 mTLS），只能在应用层做双向认证。
+// 证书过期前会被替换，如果只在启动时加载，就必须在每次轮换后重启服务。
+//
+// 工作方式：
+// - 定期检查证书、私钥、CA 文件的修改时间，有变化时重新加载
+// - 新的握手使用新证书，已建立的连接不受影响
+// - 加载失败（如文件写到一半）时保留旧证书，下次检查再试
+type Reloader struct {
+	cfg     Config
+	current atomic.Pointer[material]
+
+	// peers 已经完成握手的入站连接：对端地址 → 对端身份（见 PeerID）
+	peers sync.Map
+
+	mu      sync.Mutex
+	modTime time.Time // 三个文件中最新的修改时间
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// material 一次加载的证书和 CA
+type material struct {
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// NewReloader 构造函数：立即加载一次证书，并启动后台检查
+func NewReloader(cfg Config) (*Reloader, error) {
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = time.Minute
+	}
+	r := &Reloader{cfg: cfg, stop: make(chan struct{})}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+	return r, nil
+}
+
+// Reload 重新加载证书（文件没有变化时跳过）
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, err := latestModTime(r.cfg.CertFile, r.cfg.KeyFile, r.cfg.CAFile)
+	if err != nil {
+		return err
+	}
+	if r.current.Load() != nil && !modTime.After(r.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("mtls: load key pair: %w", err)
+	}
+	caPEM, err := os.ReadFile(r.cfg.CAFile)
+	if err != nil {
+		return fmt.Errorf("mtls: read CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("mtls: no certificates in %s", r.cfg.CAFile)
+	}
+
+	r.current.Store(&material{cert: &cert, pool: pool})
+	r.modTime = modTime
+	return nil
+}
+
+// Close 停止后台检查
+func (r *Reloader) Close() {
+	r.once.Do(func() { close(r.stop) })
+}
+
+// ServerTLSConfig 服务端 TLS 配置：要求并校验客户端证书
+//
+// 协议本身能取到连接的 TLS 状态时使用（如 HTTP 服务的 http.Request.TLS，见 PeerIDFromState）；
+// 取不到时（Kitex）使用 NewListener，按对端地址查询身份（PeerID）。
+func (r *Reloader) ServerTLSConfig() *tls.Config {
+	return r.serverTLSConfig(false)
+}
+
+// serverTLSConfig 辅助方法：服务端 TLS 配置，record 为 true 时按对端地址记录校验通过的客户端身份
+func (r *Reloader) serverTLSConfig(record bool) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// 每次握手取当前的证书和 CA，证书轮换后新连接立即生效
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			m := r.current.Load()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*m.cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    m.pool,
+				VerifyConnection: func(state tls.ConnectionState) error {
+					id, err := peerID(state, r.cfg.AllowedClientIDs)
+					if err != nil {
+						return err
+					}
+					if record && hello.Conn != nil {
+						r.peers.Store(hello.Conn.RemoteAddr().String(), id)
+					}
+					return nil
+				},
+			}, nil
+		},
+	}
+}
+
+// NewListener 在 TCP 监听器上完成 mTLS 握手，记录每个连接的对端身份（PeerID），连接关闭时删除
+func (r *Reloader) NewListener(inner net.Listener) net.Listener {
+	return &peerListener{Listener: tls.NewListener(inner, r.serverTLSConfig(true)), reloader: r}
+}
+
+// PeerID 入站连接的对端身份（客户端证书中的 URI SAN，如 SPIFFE ID）
+//
+// remoteAddr 是连接的对端地址（net.Conn.RemoteAddr().String()）；
+// 连接还没有完成握手、或者已经关闭时返回 false。
+// 证书中有多个 URI SAN 时，返回 AllowedClientIDs 中的那一个（没有配置时返回第一个）。
+func (r *Reloader) PeerID(remoteAddr string) (string, bool) {
+	id, ok := r.peers.Load(remoteAddr)
+	if !ok {
+		return "", false
+	}
+	return id.(string), true
+}
+
+// forgetPeer 辅助方法：连接关闭时删除记录的对端身份
+func (r *Reloader) forgetPeer(remoteAddr string) {
+	r.peers.Delete(remoteAddr)
+}
+
+// ClientTLSConfig 客户端 TLS 配置：出示本服务证书并校验服务端证书
+//
+// 参数：
+// - serverName: 下游的主机名（SNI；没有 serverID 时按主机名校验服务端证书）
+// - serverID: 下游证书中的 SPIFFE ID（SVID 通常不带主机名，按身份校验），为空时按主机名校验
+//
+// 标准库的 RootCAs 在创建配置时固定，不能随 CA 轮换更新，
+// 所以关闭内置校验，改为在 VerifyConnection 中用当前的 CA 校验证书链、主机名或身份。
+func (r *Reloader) ClientTLSConfig(serverName, serverID string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.current.Load().cert, nil
+		},
+		InsecureSkipVerify: true, // 由 VerifyConnection 校验
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("mtls: server presented no certificate")
+			}
+			opts := x509.VerifyOptions{
+				Roots:         r.current.Load().pool,
+				Intermediates: x509.NewCertPool(),
+			}
+			if serverID == "" {
+				opts.DNSName = serverName
+			}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+				return err
+			}
+			if serverID == "" {
+				return nil
+			}
+			_, err := peerID(state, []string{serverID})
+			return err
+		},
+	}
+}
+
+// peerID 辅助函数：对端证书中的 URI SAN（如 SPIFFE ID）
+//
+// allowed 不为空时只接受其中的身份（都不在其中时返回 ErrPeerNotAllowed）；为空时返回第一个 URI SAN（没有时为空）。
+func peerID(state tls.ConnectionState, allowed []string) (string, error) {
+	if len(state.PeerCertificates) == 0 {
+		return "", ErrPeerNotAllowed
+	}
+	uris := state.PeerCertificates[0].URIs
+	if len(allowed) == 0 {
+		if len(uris) == 0 {
+			return "", nil
+		}
+		return uris[0].String(), nil
+	}
+	for _, uri := range uris {
+		for _, id := range allowed {
+			if uri.String() == id {
+				return id, nil
+			}
+		}
+	}
+	return "", ErrPeerNotAllowed
+}
+
+// PeerIDFromState 已经完成握手的连接中对端的身份（见 peerID，HTTP 服务从 http.Request.TLS 中取）
+func (r *Reloader) PeerIDFromState(state tls.ConnectionState) (string, bool) {
+	id, err := peerID(state, r.cfg.AllowedClientIDs)
+	return id, err == nil && id != ""
+}
+
+// peerListener NewListener 返回的监听器
+type peerListener struct {
+	net.Listener
+	reloader *Reloader
+}
+
+// Accept 实现 net.Listener
+func (l *peerListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &peerConn{Conn: conn.(*tls.Conn), reloader: l.reloader}, nil
+}
+
+// peerConn 关闭时删除记录的对端身份
+type peerConn struct {
+	*tls.Conn
+	reloader *Reloader
+	once     sync.Once
+}
+
+// Close 实现 net.Conn
+func (c *peerConn) Close() error {
+	c.once.Do(func() { c.reloader.forgetPeer(c.RemoteAddr().String()) })
+	return c.Conn.Close()
+}
+
+// watch 辅助方法：定期检查证书文件
+func (r *Reloader) watch() {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			_ = r.Reload() // 失败时保留旧证书
+		}
+	}
+}
+
+// latestModTime 辅助方法：多个文件中最新的修改时间
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("mtls: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
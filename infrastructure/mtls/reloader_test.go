@@ -0,0 +1,253 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCA 测试用 CA：签发带 SPIFFE ID 的证书
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+// writeIdentity 签发一张证书，写出证书、私钥、CA 三个文件
+func (ca *testCA) writeIdentity(t *testing.T, dir, spiffeID string, serial int64) Config {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	id, _ := url.Parse(spiffeID)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	cfg := Config{
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	writePEM(t, cfg.CertFile, "CERTIFICATE", der)
+	writePEM(t, cfg.KeyFile, "EC PRIVATE KEY", keyDER)
+	writePEM(t, cfg.CAFile, "CERTIFICATE", ca.cert.Raw)
+	return cfg
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake 在本地 TCP 连接上完成一次 mTLS 握手，返回服务端看到的客户端证书
+func handshake(t *testing.T, server, client *tls.Config) (*x509.Certificate, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	type result struct {
+		peer *x509.Certificate
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer conn.Close()
+		srv := tls.Server(conn, server)
+		if err := srv.Handshake(); err != nil {
+			results <- result{err: err}
+			return
+		}
+		results <- result{peer: srv.ConnectionState().PeerCertificates[0]}
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+	if err == nil {
+		// TLS 1.3 中服务端在客户端握手完成后才校验客户端证书，读一次才能拿到服务端的拒绝
+		_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		_, _ = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+	r := <-results
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.peer, err
+}
+
+func TestReloader_MutualAuthAndHotReload(t *testing.T) {
+	ca := newTestCA(t)
+	serverCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/recommendation", 2)
+	serverCfg.AllowedClientIDs = []string{"spiffe://example.org/bff"}
+	clientDir := t.TempDir()
+	clientCfg := ca.writeIdentity(t, clientDir, "spiffe://example.org/bff", 3)
+
+	serverReloader, err := NewReloader(serverCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverReloader.Close()
+	clientReloader, err := NewReloader(clientCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientReloader.Close()
+
+	peer, err := handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/recommendation"))
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if peer.SerialNumber.Int64() != 3 {
+		t.Errorf("client cert serial = %v, want 3", peer.SerialNumber)
+	}
+
+	// 证书轮换：客户端换成新证书（同一个身份），无需重建配置
+	ca.writeIdentity(t, clientDir, "spiffe://example.org/bff", 4)
+	future := time.Now().Add(time.Minute)
+	for _, path := range []string{clientCfg.CertFile, clientCfg.KeyFile, clientCfg.CAFile} {
+		_ = os.Chtimes(path, future, future)
+	}
+	if err := clientReloader.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	peer, err = handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/recommendation"))
+	if err != nil {
+		t.Fatalf("handshake after reload failed: %v", err)
+	}
+	if peer.SerialNumber.Int64() != 4 {
+		t.Errorf("client cert serial after reload = %v, want 4", peer.SerialNumber)
+	}
+}
+
+func TestReloader_RejectsUnknownPeerID(t *testing.T) {
+	ca := newTestCA(t)
+	serverCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/recommendation", 2)
+	serverCfg.AllowedClientIDs = []string{"spiffe://example.org/bff"}
+	clientCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/crawler", 3)
+
+	serverReloader, _ := NewReloader(serverCfg)
+	defer serverReloader.Close()
+	clientReloader, _ := NewReloader(clientCfg)
+	defer clientReloader.Close()
+
+	if _, err := handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/recommendation")); !errors.Is(err, ErrPeerNotAllowed) {
+		t.Errorf("handshake err = %v, want ErrPeerNotAllowed", err)
+	}
+}
+
+func TestReloader_ClientVerifiesServerIdentity(t *testing.T) {
+	ca := newTestCA(t)
+	serverCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/recommendation", 2)
+	clientCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/bff", 3)
+	// 调用方白名单只对入站连接生效：客户端自己的白名单不用来校验服务端
+	clientCfg.AllowedClientIDs = []string{"spiffe://example.org/push-pipeline"}
+
+	serverReloader, _ := NewReloader(serverCfg)
+	defer serverReloader.Close()
+	clientReloader, _ := NewReloader(clientCfg)
+	defer clientReloader.Close()
+
+	if _, err := handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/recommendation")); err != nil {
+		t.Fatalf("handshake with the expected server identity failed: %v", err)
+	}
+	if _, err := handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/content")); err == nil {
+		t.Errorf("handshake with another server identity succeeded, want the client to reject it")
+	}
+	// 没有配置下游身份时按主机名校验（测试证书没有主机名）
+	if _, err := handshake(t, serverReloader.ServerTLSConfig(), clientReloader.ClientTLSConfig("recommendation", "")); err == nil {
+		t.Errorf("handshake without a server identity succeeded, want hostname verification failure")
+	}
+}
+
+func TestReloader_PeerIDByRemoteAddr(t *testing.T) {
+	ca := newTestCA(t)
+	serverCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/recommendation", 2)
+	serverCfg.AllowedClientIDs = []string{"spiffe://example.org/bff"}
+	clientCfg := ca.writeIdentity(t, t.TempDir(), "spiffe://example.org/bff", 3)
+	serverReloader, _ := NewReloader(serverCfg)
+	defer serverReloader.Close()
+	clientReloader, _ := NewReloader(clientCfg)
+	defer clientReloader.Close()
+
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln := serverReloader.NewListener(inner)
+	defer ln.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		_, _ = conn.Read(make([]byte, 1)) // 完成握手
+		accepted <- conn
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientReloader.ClientTLSConfig("recommendation", "spiffe://example.org/recommendation"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	serverConn := <-accepted
+	if serverConn == nil {
+		t.Fatal("accept failed")
+	}
+	remoteAddr := serverConn.RemoteAddr().String()
+	if id, ok := serverReloader.PeerID(remoteAddr); !ok || id != "spiffe://example.org/bff" {
+		t.Errorf("PeerID = %q, %v, want the client's SPIFFE ID", id, ok)
+	}
+	serverConn.Close()
+	if _, ok := serverReloader.PeerID(remoteAddr); ok {
+		t.Errorf("PeerID still recorded after the connection closed")
+	}
+}
@@ -0,0 +1,254 @@
+package observability
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets 直方图的默认分桶（和 Prometheus 客户端库的 DefBuckets 相同，适合以秒为单位的耗时）
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics service.Metrics 的 Prometheus 实现
+//
+// 指标保存在进程内，Prometheus 定期抓取 ServeHTTP 输出的文本格式（text exposition format 0.0.4）。
+//
+// 为什么不用官方客户端库？
+// 和 secrets.VaultProvider、discovery 一样，协议很简单（一个纯文本的 HTTP 响应），
+// 应用层只用到计数器和直方图，自己实现避免引入一整套依赖。
+//
+// 指标不需要提前注册：第一次 IncCounter / ObserveHistogram 时创建。
+// 同一个指标名只能是一种类型，和第一次使用时的类型不同的调用被忽略。
+type PrometheusMetrics struct {
+	buckets map[string][]float64
+
+	mu       sync.RWMutex
+	families map[string]*metricFamily
+}
+
+// PrometheusOption 可选配置
+type PrometheusOption func(*PrometheusMetrics)
+
+// WithHistogramBuckets 指定直方图的分桶上界（升序；没有指定的直方图使用 DefaultBuckets）
+func WithHistogramBuckets(name string, buckets []float64) PrometheusOption {
+	return func(m *PrometheusMetrics) {
+		m.buckets[name] = buckets
+	}
+}
+
+// NewPrometheusMetrics 构造函数
+func NewPrometheusMetrics(opts ...PrometheusOption) *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		buckets:  make(map[string][]float64),
+		families: make(map[string]*metricFamily),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// metricType 指标类型（输出到 # TYPE 行）
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeHistogram metricType = "histogram"
+)
+
+// metricFamily 同一个指标名下、不同标签组合的时间序列
+type metricFamily struct {
+	name    string
+	typ     metricType
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series // 标签（排序后序列化）→ 时间序列
+}
+
+// series 一个时间序列：计数器只使用 sum；直方图的 counts[i] 是落在第 i 个桶（不累加）的观测数
+type series struct {
+	labels string // 已经格式化的标签，如 method="Get",result="ok"
+	sum    float64
+	count  uint64
+	counts []uint64
+}
+
+// IncCounter 实现 service.Metrics（计数器只增不减，delta 小于 0 时忽略）
+func (m *PrometheusMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	if delta < 0 {
+		return
+	}
+	family := m.family(name, typeCounter)
+	if family == nil {
+		return
+	}
+	family.mu.Lock()
+	defer family.mu.Unlock()
+	family.get(labels).sum += float64(delta)
+}
+
+// ObserveHistogram 实现 service.Metrics
+func (m *PrometheusMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	if math.IsNaN(value) {
+		return
+	}
+	family := m.family(name, typeHistogram)
+	if family == nil {
+		return
+	}
+	family.mu.Lock()
+	defer family.mu.Unlock()
+	s := family.get(labels)
+	s.sum += value
+	s.count++
+	// 大于所有上界的观测只计入 count（+Inf 桶）
+	if i := sort.SearchFloat64s(family.buckets, value); i < len(family.buckets) {
+		s.counts[i]++
+	}
+}
+
+// ServeHTTP 实现 http.Handler：输出所有指标（GET /metrics）
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	out := bufio.NewWriter(w)
+	m.write(out)
+	_ = out.Flush()
+}
+
+// family 辅助方法：指标名对应的 family（不存在时创建；类型不一致时返回 nil）
+func (m *PrometheusMetrics) family(name string, typ metricType) *metricFamily {
+	m.mu.RLock()
+	family, ok := m.families[name]
+	m.mu.RUnlock()
+	if !ok {
+		m.mu.Lock()
+		if family, ok = m.families[name]; !ok {
+			family = &metricFamily{name: name, typ: typ, series: make(map[string]*series)}
+			if typ == typeHistogram {
+				family.buckets = m.buckets[name]
+				if family.buckets == nil {
+					family.buckets = DefaultBuckets
+				}
+			}
+			m.families[name] = family
+		}
+		m.mu.Unlock()
+	}
+	if family.typ != typ {
+		return nil
+	}
+	return family
+}
+
+// write 辅助方法：按指标名排序输出（同一个指标的时间序列按标签排序，抓取结果稳定，方便比较）
+func (m *PrometheusMetrics) write(out *bufio.Writer) {
+	m.mu.RLock()
+	families := make([]*metricFamily, 0, len(m.families))
+	for _, family := range m.families {
+		families = append(families, family)
+	}
+	m.mu.RUnlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	for _, family := range families {
+		family.mu.Lock()
+		keys := make([]string, 0, len(family.series))
+		for key := range family.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(out, "# TYPE %s %s\n", family.name, family.typ)
+		for _, key := range keys {
+			s := family.series[key]
+			if family.typ == typeCounter {
+				fmt.Fprintf(out, "%s%s %s\n", family.name, braces(s.labels), formatFloat(s.sum))
+				continue
+			}
+			var cumulative uint64
+			for i, upper := range family.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(out, "%s_bucket%s %d\n", family.name, braces(joinLabels(s.labels, `le="`+formatFloat(upper)+`"`)), cumulative)
+			}
+			fmt.Fprintf(out, "%s_bucket%s %d\n", family.name, braces(joinLabels(s.labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(out, "%s_sum%s %s\n", family.name, braces(s.labels), formatFloat(s.sum))
+			fmt.Fprintf(out, "%s_count%s %d\n", family.name, braces(s.labels), s.count)
+		}
+		family.mu.Unlock()
+	}
+}
+
+// get 辅助方法：标签对应的时间序列（不存在时创建；调用方持有 family.mu）
+func (f *metricFamily) get(labels map[string]string) *series {
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// formatLabels 辅助函数：标签按名字排序、转义之后拼接（同时作为时间序列的 key）
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(labels[name]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// labelValueEscaper 标签值中的反斜杠、双引号、换行需要转义
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// joinLabels 辅助函数：在已经格式化的标签后面追加一个标签（直方图的 le）
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+// braces 辅助函数：有标签时加上花括号
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+// formatFloat 辅助函数：Prometheus 文本格式的数值（+Inf、-Inf、NaN 有固定写法）
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,39 @@
+package observability
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetrics_Exposition(t *testing.T) {
+	m := NewPrometheusMetrics(WithHistogramBuckets("recommendation_generated_candidates", []float64{10, 50}))
+	m.IncCounter("recommendation_hot_cache_requests_total", 3, map[string]string{"result": "hit", "cache": "user_info"})
+	m.IncCounter("recommendation_hot_cache_requests_total", 1, map[string]string{"cache": "user_info", "result": "miss"})
+	m.IncCounter("recommendation_hot_cache_requests_total", 2, map[string]string{"cache": "user_info", "result": "hit"})
+	m.ObserveHistogram("recommendation_generated_candidates", 5, map[string]string{"strategy": `a"b`})
+	m.ObserveHistogram("recommendation_generated_candidates", 50, map[string]string{"strategy": `a"b`})
+	m.ObserveHistogram("recommendation_generated_candidates", 80, map[string]string{"strategy": `a"b`})
+	// 类型和第一次使用时不同：忽略
+	m.ObserveHistogram("recommendation_hot_cache_requests_total", 1, nil)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	want := `# TYPE recommendation_generated_candidates histogram
+recommendation_generated_candidates_bucket{strategy="a\"b",le="10"} 1
+recommendation_generated_candidates_bucket{strategy="a\"b",le="50"} 2
+recommendation_generated_candidates_bucket{strategy="a\"b",le="+Inf"} 3
+recommendation_generated_candidates_sum{strategy="a\"b"} 135
+recommendation_generated_candidates_count{strategy="a\"b"} 3
+# TYPE recommendation_hot_cache_requests_total counter
+recommendation_hot_cache_requests_total{cache="user_info",result="hit"} 5
+recommendation_hot_cache_requests_total{cache="user_info",result="miss"} 1
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", got, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
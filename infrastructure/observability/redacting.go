@@ -0,0 +1,65 @@
+package observability
+
+import (
+	"context"
+
+	"service/application/service"
+)
+
+// RedactingLogger 带脱敏的日志（装饰器）
+//
+// 包装任意 Logger 实现，写出之前先对字段脱敏。
+// 应用层不需要关心脱敏，所有日志统一经过这一层。
+type RedactingLogger struct {
+	next     service.Logger
+	redactor *Redactor
+}
+
+// NewRedactingLogger 构造函数
+func NewRedactingLogger(next service.Logger, redactor *Redactor) service.Logger {
+	return &RedactingLogger{
+		next:     next,
+		redactor: redactor,
+	}
+}
+
+// Log 实现接口：脱敏后转发
+func (l *RedactingLogger) Log(ctx context.Context, level service.LogLevel, msg string, fields map[string]any) {
+	l.next.Log(ctx, level, msg, l.redactor.RedactFields(fields))
+}
+
+// RedactingTracer 带脱敏的链路追踪（装饰器）
+//
+// 包装任意 Tracer 实现，span 属性写入之前先脱敏。
+type RedactingTracer struct {
+	next     service.Tracer
+	redactor *Redactor
+}
+
+// NewRedactingTracer 构造函数
+func NewRedactingTracer(next service.Tracer, redactor *Redactor) service.Tracer {
+	return &RedactingTracer{
+		next:     next,
+		redactor: redactor,
+	}
+}
+
+// StartSpan 实现接口：返回带脱敏的 span
+func (t *RedactingTracer) StartSpan(ctx context.Context, name string) (context.Context, service.Span) {
+	ctx, span := t.next.StartSpan(ctx, name)
+	return ctx, &redactingSpan{next: span, redactor: t.redactor}
+}
+
+// redactingSpan 带脱敏的 span
+type redactingSpan struct {
+	next     service.Span
+	redactor *Redactor
+}
+
+func (s *redactingSpan) SetAttribute(key string, value any) {
+	s.next.SetAttribute(key, s.redactor.Redact(key, value))
+}
+
+func (s *redactingSpan) End() {
+	s.next.End()
+}
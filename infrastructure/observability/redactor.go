@@ -0,0 +1,123 @@
+package observability
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"service/application/service"
+)
+
+// UserIDMode 用户ID的脱敏方式
+type UserIDMode string
+
+const (
+	// UserIDModeHash 替换为带密钥的哈希（同一个用户哈希值相同，仍然可以关联同一用户的多条日志）
+	UserIDModeHash UserIDMode = "hash"
+	// UserIDModeMask 整体替换为掩码（完全无法关联）
+	UserIDModeMask UserIDMode = "mask"
+)
+
+// EnvironmentProduction 生产环境：白名单不生效
+const EnvironmentProduction = "production"
+
+// RedactionConfig 脱敏配置
+type RedactionConfig struct {
+	// Environment 运行环境；为 production 时忽略 Allowlist
+	Environment string
+	// UserIDMode 用户ID的脱敏方式（默认 hash）
+	UserIDMode UserIDMode
+	// HashKey 哈希密钥（UserIDModeHash 使用）；应从密钥管理系统读取，不同环境不同。
+	// 为空时用户ID完全掩码：没有密钥的哈希可以通过穷举用户ID反推
+	HashKey string
+	// Allowlist 调试环境中保留原文的字段名（如排查问题时需要看到真实用户ID）
+	Allowlist []string
+}
+
+// Redactor 脱敏器：按字段名识别日志字段、span 属性中的用户数据并脱敏
+//
+// 脱敏规则：
+// - 用户标识（user_id、viewer_id、target_user_id）：哈希或掩码
+// - 用户内容（nickname、bio、post_content）：替换为 "[redacted len=N]"，只保留长度
+// - 其他字段：原样保留
+//
+// 为什么按字段名而不是按内容识别？
+// 昵称、帖子内容是任意文本，无法靠正则可靠识别；
+// 应用层约定用户数据只能放在固定字段名下（见 service.Field*），脱敏规则就可以精确匹配。
+//
+// 白名单：
+// 调试环境可以通过 Allowlist 保留部分字段的原文，生产环境白名单不生效，
+// 避免调试配置被误带到生产。
+type Redactor struct {
+	mode      UserIDMode
+	hashKey   []byte
+	allowlist map[string]bool
+}
+
+// NewRedactor 构造函数
+func NewRedactor(cfg RedactionConfig) *Redactor {
+	r := &Redactor{
+		mode:      cfg.UserIDMode,
+		hashKey:   []byte(cfg.HashKey),
+		allowlist: make(map[string]bool),
+	}
+	if r.mode == "" {
+		r.mode = UserIDModeHash
+	}
+	if r.mode == UserIDModeHash && len(r.hashKey) == 0 {
+		r.mode = UserIDModeMask
+	}
+	if cfg.Environment != EnvironmentProduction {
+		for _, key := range cfg.Allowlist {
+			r.allowlist[key] = true
+		}
+	}
+	return r
+}
+
+// Redact 脱敏单个字段
+func (r *Redactor) Redact(key string, value any) any {
+	if r.allowlist[key] {
+		return value
+	}
+
+	switch key {
+	case service.FieldUserID, service.FieldViewerID, service.FieldTargetUserID:
+		return r.redactUserID(value)
+	case service.FieldNickname, service.FieldBio, service.FieldPostContent:
+		return redactText(value)
+	default:
+		return value
+	}
+}
+
+// RedactFields 脱敏一组字段（返回新的 map，不修改传入的 map）
+func (r *Redactor) RedactFields(fields map[string]any) map[string]any {
+	if len(fields) == 0 {
+		return fields
+	}
+	result := make(map[string]any, len(fields))
+	for key, value := range fields {
+		result[key] = r.Redact(key, value)
+	}
+	return result
+}
+
+// redactUserID 辅助方法：用户ID → 哈希或掩码
+//
+// 哈希使用 HMAC-SHA256（带密钥），不能通过穷举用户ID反推出原值
+func (r *Redactor) redactUserID(value any) any {
+	if r.mode == UserIDModeMask {
+		return "***"
+	}
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(fmt.Sprint(value)))
+	return "u_" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// redactText 辅助方法：用户内容 → 只保留长度
+func redactText(value any) any {
+	return fmt.Sprintf("[redacted len=%d]", utf8.RuneCountInString(fmt.Sprint(value)))
+}
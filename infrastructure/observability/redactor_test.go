@@ -0,0 +1,80 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"service/application/service"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := NewRedactor(RedactionConfig{Environment: EnvironmentProduction, HashKey: "k1"})
+
+	hashed := r.Redact(service.FieldUserID, int64(42))
+	if s, ok := hashed.(string); !ok || !strings.HasPrefix(s, "u_") || strings.Contains(s, "42") {
+		t.Errorf("user_id redacted to %v, want opaque hash", hashed)
+	}
+	if again := r.Redact(service.FieldViewerID, int64(42)); again != hashed {
+		t.Errorf("same user hashed differently: %v vs %v", again, hashed)
+	}
+	if other := NewRedactor(RedactionConfig{HashKey: "k2"}).Redact(service.FieldUserID, int64(42)); other == hashed {
+		t.Errorf("different hash keys should produce different hashes")
+	}
+
+	if got := r.Redact(service.FieldPostContent, "我的手机号是 138xxxx"); got != "[redacted len=14]" {
+		t.Errorf("post_content redacted to %v", got)
+	}
+	if got := r.Redact(service.FieldSurface, "home_feed"); got != "home_feed" {
+		t.Errorf("surface = %v, want unchanged", got)
+	}
+}
+
+func TestRedactor_Allowlist(t *testing.T) {
+	cfg := RedactionConfig{
+		Environment: "debug",
+		UserIDMode:  UserIDModeMask,
+		Allowlist:   []string{service.FieldUserID},
+	}
+
+	if got := NewRedactor(cfg).Redact(service.FieldUserID, int64(42)); got != int64(42) {
+		t.Errorf("allowlisted user_id = %v, want raw value in debug environment", got)
+	}
+	if got := NewRedactor(cfg).Redact(service.FieldNickname, "alice"); got == "alice" {
+		t.Errorf("nickname is not allowlisted and should be redacted")
+	}
+
+	cfg.Environment = EnvironmentProduction
+	if got := NewRedactor(cfg).Redact(service.FieldUserID, int64(42)); got != "***" {
+		t.Errorf("user_id in production = %v, want allowlist ignored", got)
+	}
+}
+
+func TestRedactor_EmptyHashKeyMasks(t *testing.T) {
+	r := NewRedactor(RedactionConfig{Environment: EnvironmentProduction, UserIDMode: UserIDModeHash})
+	if got := r.Redact(service.FieldUserID, int64(42)); got != "***" {
+		t.Errorf("user_id without hash key = %v, want fully masked", got)
+	}
+}
+
+func TestRedactingTracer_SlogSpan(t *testing.T) {
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tracer := NewRedactingTracer(NewSlogTracer(logger), NewRedactor(RedactionConfig{HashKey: "k1"}))
+
+	_, span := tracer.StartSpan(context.Background(), "RecommendationService.GetSuggestedPosts")
+	span.SetAttribute(service.FieldUserID, int64(42))
+	span.SetAttribute(service.FieldSurface, "home_feed")
+	span.End()
+
+	out := buf.String()
+	for _, want := range []string{"span=RecommendationService.GetSuggestedPosts", "surface=home_feed", "user_id=u_", "duration_ms="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("span log %q missing %q", out, want)
+		}
+	}
+	if strings.Contains(out, "user_id=42") {
+		t.Errorf("span log %q contains the raw user id", out)
+	}
+}
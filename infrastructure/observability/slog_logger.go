@@ -0,0 +1,44 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+
+	"service/application/service"
+)
+
+// SlogLogger 基于标准库 log/slog 的日志实现
+//
+// 输出格式（JSON / 文本）、输出位置由传入的 *slog.Logger 决定。
+// 本身不做脱敏，生产环境应使用 NewRedactingLogger 包装。
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger 构造函数
+func NewSlogLogger(logger *slog.Logger) service.Logger {
+	return &SlogLogger{logger: logger}
+}
+
+// Log 实现接口
+func (l *SlogLogger) Log(ctx context.Context, level service.LogLevel, msg string, fields map[string]any) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for key, value := range fields {
+		attrs = append(attrs, slog.Any(key, value))
+	}
+	l.logger.LogAttrs(ctx, slogLevel(level), msg, attrs...)
+}
+
+// slogLevel 辅助方法：日志级别转换
+func slogLevel(level service.LogLevel) slog.Level {
+	switch level {
+	case service.LogLevelDebug:
+		return slog.LevelDebug
+	case service.LogLevelWarn:
+		return slog.LevelWarn
+	case service.LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,57 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"service/application/service"
+)
+
+// SlogTracer 基于 log/slog 的链路追踪实现：span 结束时写一条 debug 日志
+//
+// 没有接入追踪系统的环境使用：每个 span 一条日志，包含名称、耗时（duration_ms）和属性，
+// 日志级别为 debug 时才会输出。接入追踪系统时换成对应的实现，应用层不需要修改。
+// 本身不做脱敏，应使用 NewRedactingTracer 包装。
+type SlogTracer struct {
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewSlogTracer 构造函数
+func NewSlogTracer(logger *slog.Logger) service.Tracer {
+	return &SlogTracer{logger: logger, now: time.Now}
+}
+
+// StartSpan 实现接口
+func (t *SlogTracer) StartSpan(ctx context.Context, name string) (context.Context, service.Span) {
+	return ctx, &slogSpan{tracer: t, ctx: ctx, name: name, start: t.now()}
+}
+
+// slogSpan 一个 span：属性在结束之前保存在内存中
+type slogSpan struct {
+	tracer *SlogTracer
+	ctx    context.Context
+	name   string
+	start  time.Time
+
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+func (s *slogSpan) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, slog.Any(key, value))
+}
+
+func (s *slogSpan) End() {
+	s.mu.Lock()
+	attrs := append([]slog.Attr{
+		slog.String("span", s.name),
+		slog.Int64(service.FieldDurationMS, s.tracer.now().Sub(s.start).Milliseconds()),
+	}, s.attrs...)
+	s.mu.Unlock()
+	s.tracer.logger.LogAttrs(s.ctx, slog.LevelDebug, "span", attrs...)
+}
@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// RedisActiveUsersRepository 最近活跃用户的 Redis 实现
+//
+// 每个租户一个有序集合：rec:{tenant}:active_users
+// - member: 用户ID
+// - score:  最近一次活跃的时间（Unix 秒）
+//
+// 每次写入顺带清理超过保留时间的用户，集合大小不超过保留时间内的活跃用户数。
+type RedisActiveUsersRepository struct {
+	rdb       redis.UniversalClient
+	retention time.Duration
+}
+
+// NewRedisActiveUsersRepository 构造函数
+//
+// 参数：
+// - rdb: Redis 客户端
+// - retention: 保留多久以内活跃过的用户（应不小于预计算使用的活跃窗口）
+func NewRedisActiveUsersRepository(rdb redis.UniversalClient, retention time.Duration) repository.ActiveUsersRepository {
+	return &RedisActiveUsersRepository{
+		rdb:       rdb,
+		retention: retention,
+	}
+}
+
+// TouchUser 实现 ActiveUsersRepository（ZADD GT：乱序到达的旧时间不覆盖新时间）
+func (r *RedisActiveUsersRepository) TouchUser(ctx context.Context, userID valueobject.UserID, at time.Time) error {
+	key := activeUsersKey(ctx)
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddGT(ctx, key, redis.Z{
+			Score:  float64(at.Unix()),
+			Member: strconv.FormatInt(userID.Value(), 10),
+		})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(at.Add(-r.retention).Unix(), 10))
+		pipe.Expire(ctx, key, r.retention)
+		return nil
+	})
+	return err
+}
+
+// ListActiveUsers 实现 ActiveUsersRepository
+func (r *RedisActiveUsersRepository) ListActiveUsers(ctx context.Context, since time.Time, limit int) ([]valueobject.UserID, error) {
+	members, err := r.rdb.ZRevRangeByScore(ctx, activeUsersKey(ctx), &redis.ZRangeBy{
+		Min:   strconv.FormatInt(since.Unix(), 10),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]valueobject.UserID, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		users = append(users, userID)
+	}
+	return users, nil
+}
+
+// DeleteUser 实现 ActiveUsersRepository（ZREM）
+func (r *RedisActiveUsersRepository) DeleteUser(ctx context.Context, userID valueobject.UserID) error {
+	return r.rdb.ZRem(ctx, activeUsersKey(ctx), strconv.FormatInt(userID.Value(), 10)).Err()
+}
+
+// activeUsersKey 辅助函数：租户的活跃用户集合
+func activeUsersKey(ctx context.Context) string {
+	return recentFollowIndexKeyPrefix + valueobject.TenantFromContext(ctx).String() + ":active_users"
+}
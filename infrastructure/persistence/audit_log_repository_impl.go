@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/infrastructure/crypto"
+)
+
+// AuditLogRepositoryImpl 审计日志仓储实现
+//
+// 表只追加：这里只有 INSERT 和 SELECT，
+// 生产环境的数据库账号也只授予这两个权限。
+//
+// 字段加密：
+// before / after 可能包含被操作用户的数据（如屏蔽覆盖的用户ID、原因），
+// 传入 codec 时这两列加密存储；表只追加不能原地重新加密，
+// 所以密钥轮换后旧密钥要保留在 KMS 中，直到旧记录超过保留期。
+type AuditLogRepositoryImpl struct {
+	db    *gorm.DB
+	codec *crypto.FieldCodec // 可以为 nil（明文存储）
+}
+
+// NewAuditLogRepository 构造函数（codec 可以为 nil）
+func NewAuditLogRepository(db *gorm.DB, codec *crypto.FieldCodec) repository.AuditLogRepository {
+	return &AuditLogRepositoryImpl{db: db, codec: codec}
+}
+
+// Append 实现接口：写入一条审计记录
+func (r *AuditLogRepositoryImpl) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	before, err := r.encrypt(ctx, entry.Before())
+	if err != nil {
+		return err
+	}
+	after, err := r.encrypt(ctx, entry.After())
+	if err != nil {
+		return err
+	}
+
+	po := AuditLogPO{
+		Actor:      entry.Actor(),
+		Action:     string(entry.Action()),
+		Target:     entry.Target(),
+		Before:     before,
+		After:      after,
+		RequestID:  entry.RequestID(),
+		OccurredAt: entry.OccurredAt(),
+	}
+	return conn(ctx, r.db).Create(&po).Error
+}
+
+// Query 实现接口：按条件查询审计记录
+func (r *AuditLogRepositoryImpl) Query(
+	ctx context.Context,
+	query repository.AuditLogQuery,
+) ([]*entity.AuditEntry, error) {
+	tx := conn(ctx, r.db).Model(&AuditLogPO{})
+	if query.Actor != "" {
+		tx = tx.Where("actor = ?", query.Actor)
+	}
+	if query.Action != "" {
+		tx = tx.Where("action = ?", string(query.Action))
+	}
+	if query.Target != "" {
+		tx = tx.Where("target = ?", query.Target)
+	}
+	if !query.Since.IsZero() {
+		tx = tx.Where("occurred_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		tx = tx.Where("occurred_at < ?", query.Until)
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var pos []AuditLogPO
+	if err := tx.Order("occurred_at DESC, id DESC").Find(&pos).Error; err != nil {
+		return nil, err
+	}
+
+	// 转换 PO -> 领域实体
+	result := make([]*entity.AuditEntry, 0, len(pos))
+	for _, po := range pos {
+		before, err := r.decrypt(ctx, po.Before)
+		if err != nil {
+			return nil, err
+		}
+		after, err := r.decrypt(ctx, po.After)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := entity.NewAuditEntry(
+			po.Actor,
+			entity.AuditAction(po.Action),
+			po.Target,
+			before,
+			after,
+			po.RequestID,
+			po.OccurredAt,
+		)
+		if err != nil {
+			continue // 容错：跳过不完整的历史数据
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// encrypt 辅助方法：加密敏感字段（没有 codec 或值为空时原样返回）
+func (r *AuditLogRepositoryImpl) encrypt(ctx context.Context, value string) (string, error) {
+	if r.codec == nil || value == "" {
+		return value, nil
+	}
+	return r.codec.Encrypt(ctx, value)
+}
+
+// decrypt 辅助方法：解密敏感字段（未加密的历史数据原样返回）
+func (r *AuditLogRepositoryImpl) decrypt(ctx context.Context, value string) (string, error) {
+	if r.codec == nil || value == "" {
+		return value, nil
+	}
+	return r.codec.Decrypt(ctx, value)
+}
+
+// AuditLogPO 审计日志持久化对象
+type AuditLogPO struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Actor      string    `gorm:"type:varchar(64);index:idx_actor_time,priority:1;not null"`
+	Action     string    `gorm:"type:varchar(64);not null"`
+	Target     string    `gorm:"type:varchar(128);index:idx_target"`
+	Before     string    `gorm:"type:text"`
+	After      string    `gorm:"type:text"`
+	RequestID  string    `gorm:"type:varchar(64)"`
+	OccurredAt time.Time `gorm:"index:idx_actor_time,priority:2;index:idx_occurred_at;not null"`
+}
+
+// TableName 指定表名
+func (AuditLogPO) TableName() string {
+	return "recommendation_admin_audit_log"
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// BlockRepositoryImpl 屏蔽关系仓储实现
+type BlockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository 构造函数
+func NewBlockRepository(db *gorm.DB) repository.BlockRepository {
+	return &BlockRepositoryImpl{db: db}
+}
+
+// GetBlockedUsers 实现接口：WHERE blocker_id = ?
+func (r *BlockRepositoryImpl) GetBlockedUsers(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.UserID, error) {
+	var ids []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&BlockPO{}).
+		Where("blocker_id = ?", userID.Value()).
+		Pluck("blocked_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return toUserIDs(ids), nil
+}
+
+// GetBlockers 实现接口：WHERE blocked_id = ?
+func (r *BlockRepositoryImpl) GetBlockers(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.UserID, error) {
+	var ids []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&BlockPO{}).
+		Where("blocked_id = ?", userID.Value()).
+		Pluck("blocker_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return toUserIDs(ids), nil
+}
+
+// IsBlocked 实现接口
+func (r *BlockRepositoryImpl) IsBlocked(
+	ctx context.Context,
+	blockerID, blockedID valueobject.UserID,
+) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&BlockPO{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID.Value(), blockedID.Value()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// toUserIDs 辅助函数：PO 中的用户ID → 领域对象（忽略非法的ID）
+func toUserIDs(ids []int64) []valueobject.UserID {
+	result := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := valueobject.NewUserID(id)
+		if err != nil {
+			continue
+		}
+		result = append(result, userID)
+	}
+	return result
+}
+
+// BlockPO 屏蔽关系的持久化对象（由社交服务写入，推荐服务只读）
+//
+// 主键 (tenant_id, blocker_id, blocked_id)；idx_blocked 用于反向查询（谁屏蔽了我）
+type BlockPO struct {
+	TenantID  string    `gorm:"primaryKey;type:varchar(32);default:'default';index:idx_blocked,priority:1"`
+	BlockerID int64     `gorm:"primaryKey;autoIncrement:false"`
+	BlockedID int64     `gorm:"primaryKey;autoIncrement:false;index:idx_blocked,priority:2"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (BlockPO) TableName() string {
+	return "user_blocks"
+}
@@ -31,7 +31,7 @@ func (r *ContentRepositoryImpl) CountRecentPosts(
 	since := time.Now().AddDate(0, 0, -days)
 
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
 		Model(&PostPO{}).
 		Where("author_id = ? AND created_at >= ? AND status = ?",
 			userID.Value(), since, "published").
@@ -44,6 +44,53 @@ func (r *ContentRepositoryImpl) CountRecentPosts(
 	return int(count), nil
 }
 
+// recentPostsBatchSize CountRecentPostsBatch 每条 SQL 最多查询的用户数（IN 列表太长时拆成多条）
+const recentPostsBatchSize = 1000
+
+// CountRecentPostsBatch 实现接口：批量统计最近帖子数（GROUP BY author_id）
+//
+// 和 CountRecentPosts 的条件相同，一条 SQL 统计一批用户（走 idx_tenant_author）；
+// 没有帖子的用户不出现在结果中。超过 recentPostsBatchSize 个用户时分批查询。
+func (r *ContentRepositoryImpl) CountRecentPostsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID]int, error) {
+	result := make(map[valueobject.UserID]int, len(userIDs))
+	since := time.Now().AddDate(0, 0, -days)
+
+	for start := 0; start < len(userIDs); start += recentPostsBatchSize {
+		batch := userIDs[start:min(start+recentPostsBatchSize, len(userIDs))]
+		ids := make([]int64, 0, len(batch))
+		for _, userID := range batch {
+			ids = append(ids, userID.Value())
+		}
+
+		var rows []struct {
+			AuthorID int64
+			Posts    int
+		}
+		err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Model(&PostPO{}).
+			Select("author_id, COUNT(*) AS posts").
+			Where("author_id IN ? AND created_at >= ? AND status = ?", ids, since, "published").
+			Group("author_id").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			authorID, err := valueobject.NewUserID(row.AuthorID)
+			if err != nil {
+				continue
+			}
+			result[authorID] = row.Posts
+		}
+	}
+	return result, nil
+}
+
 // GetRecentPosts 实现接口：获取最近帖子
 func (r *ContentRepositoryImpl) GetRecentPosts(
 	ctx context.Context,
@@ -52,7 +99,7 @@ func (r *ContentRepositoryImpl) GetRecentPosts(
 ) ([]*entity.Post, error) {
 
 	var posts []PostPO
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
 		Where("author_id = ? AND status = ?", userID.Value(), "published").
 		Order("created_at DESC").
 		Limit(limit).
@@ -65,27 +112,77 @@ func (r *ContentRepositoryImpl) GetRecentPosts(
 	// 转换 PO -> 领域实体
 	result := make([]*entity.Post, 0, len(posts))
 	for _, po := range posts {
-		postID, _ := valueobject.NewPostID(po.ID)
-		authorID, _ := valueobject.NewUserID(po.AuthorID)
-
-		post := entity.NewPost(postID, authorID, po.Content, po.CreatedAt)
-		result = append(result, post)
+		result = append(result, po.toEntity())
 	}
 
 	return result, nil
 }
 
+// GetRecentPostsForUsers 实现接口：批量获取最近帖子
+//
+// 每个作者取最近 limit 篇：窗口函数 ROW_NUMBER() 按作者分区、按发布时间倒序编号（MySQL 8.0 及以上），
+// 外层只保留编号不超过 limit 的行。超过 recentPostsBatchSize 个用户时分批查询。
+func (r *ContentRepositoryImpl) GetRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	limit int,
+) (map[valueobject.UserID][]*entity.Post, error) {
+	result := make(map[valueobject.UserID][]*entity.Post, len(userIDs))
+
+	for start := 0; start < len(userIDs); start += recentPostsBatchSize {
+		batch := userIDs[start:min(start+recentPostsBatchSize, len(userIDs))]
+		ids := make([]int64, 0, len(batch))
+		for _, userID := range batch {
+			ids = append(ids, userID.Value())
+		}
+
+		ranked := r.db.Scopes(tenantScope(ctx)).
+			Model(&PostPO{}).
+			Select("*, ROW_NUMBER() OVER (PARTITION BY author_id ORDER BY created_at DESC) AS rn").
+			Where("author_id IN ? AND status = ?", ids, "published")
+		var posts []PostPO
+		err := conn(ctx, r.db).
+			Table("(?) AS p", ranked).
+			Where("rn <= ?", limit).
+			Order("author_id, created_at DESC").
+			Find(&posts).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, po := range posts {
+			post := po.toEntity()
+			result[post.AuthorID()] = append(result[post.AuthorID()], post)
+		}
+	}
+	return result, nil
+}
+
 // PostPO 帖子持久化对象
 type PostPO struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement"`
-	AuthorID  int64     `gorm:"index:idx_author;not null"`
+	TenantID  string    `gorm:"type:varchar(32);default:'default';index:idx_tenant_author,priority:1;not null"`
+	AuthorID  int64     `gorm:"index:idx_author;index:idx_tenant_author,priority:2;not null"`
 	Content   string    `gorm:"type:text;not null"`
 	Status    string    `gorm:"type:varchar(20);default:'published'"`
 	CreatedAt time.Time `gorm:"index:idx_created_at;not null"`
 	UpdatedAt time.Time
+
+	// ModerationStatus 审核状态：unknown / approved / sensitive / nsfw
+	ModerationStatus string `gorm:"type:varchar(20);default:'unknown'"`
 }
 
 // TableName 指定表名
 func (PostPO) TableName() string {
 	return "posts"
 }
+
+// toEntity 转换 PO -> 领域实体
+func (po PostPO) toEntity() *entity.Post {
+	postID, _ := valueobject.NewPostID(po.ID)
+	authorID, _ := valueobject.NewUserID(po.AuthorID)
+
+	post := entity.NewPost(postID, authorID, po.Content, po.CreatedAt)
+	post.ApplyModeration(valueobject.ParseModerationStatus(po.ModerationStatus))
+	return post
+}
@@ -0,0 +1,108 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ConversionRepositoryImpl 归因转化记录仓储实现
+type ConversionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewConversionRepository 构造函数
+func NewConversionRepository(db *gorm.DB) repository.ConversionRepository {
+	return &ConversionRepositoryImpl{db: db}
+}
+
+// RecordConversion 实现接口：写入一次转化
+//
+// 使用 ON CONFLICT DO NOTHING（唯一索引 tenant_id + recommendation_id）：
+// 关注事件重复投递时保留第一次的归因结果
+func (r *ConversionRepositoryImpl) RecordConversion(
+	ctx context.Context,
+	conversion *entity.Conversion,
+) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ConversionPO{
+			TenantID:         valueobject.TenantFromContext(ctx).String(),
+			RecommendationID: conversion.RecommendationID().Value(),
+			ViewerID:         conversion.ViewerID().Value(),
+			TargetUserID:     conversion.TargetUserID().Value(),
+			ReasonType:       int(conversion.ReasonType()),
+			Surface:          conversion.Surface(),
+			ShownAt:          conversion.ShownAt(),
+			ConvertedAt:      conversion.ConvertedAt(),
+			Direct:           conversion.Direct(),
+		}).Error
+}
+
+// DeleteUserConversions 实现接口：删除用户作为 viewer 或 target 的所有转化记录
+func (r *ConversionRepositoryImpl) DeleteUserConversions(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ? OR target_user_id = ?", userID.Value(), userID.Value()).
+		Delete(&ConversionPO{}).Error
+}
+
+// ListUserConversions 实现接口：查询用户作为 viewer 的所有转化记录
+func (r *ConversionRepositoryImpl) ListUserConversions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.Conversion, error) {
+	var pos []ConversionPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ?", viewerID.Value()).
+		Order("converted_at ASC, id ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.Conversion, 0, len(pos))
+	for _, po := range pos {
+		recommendationID, err := valueobject.RecommendationIDFromString(po.RecommendationID)
+		if err != nil {
+			continue // 容错：跳过格式错误的历史数据
+		}
+		targetUserID, err := valueobject.NewUserID(po.TargetUserID)
+		if err != nil {
+			continue
+		}
+		// 转化冗余保存了曝光的字段，从它们还原曝光
+		impression := entity.NewImpression(recommendationID, viewerID, targetUserID, valueobject.ReasonType(po.ReasonType), po.Surface, po.ShownAt)
+		result = append(result, entity.NewConversion(impression, po.ConvertedAt, po.Direct))
+	}
+	return result, nil
+}
+
+// ConversionPO 归因转化记录持久化对象
+//
+// 冗余保存曝光的理由类型、场景和时间：统计时不需要再 JOIN 曝光表
+type ConversionPO struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID         string    `gorm:"type:varchar(32);default:'default';not null;uniqueIndex:uk_tenant_recommendation,priority:1"`
+	RecommendationID string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_tenant_recommendation,priority:2"`
+	ViewerID         int64     `gorm:"index:idx_viewer;not null"`
+	TargetUserID     int64     `gorm:"index:idx_target;not null"` // 用户数据删除时按 target 查找
+	ReasonType       int       `gorm:"type:smallint;default:0;not null"`
+	Surface          string    `gorm:"type:varchar(32)"`
+	ShownAt          time.Time `gorm:"index:idx_shown_at;not null"` // 统计按曝光时间查找
+	ConvertedAt      time.Time `gorm:"not null"`
+	Direct           bool      `gorm:"not null;default:false"`
+}
+
+// TableName 指定表名
+func (ConversionPO) TableName() string {
+	return "recommendation_conversions"
+}
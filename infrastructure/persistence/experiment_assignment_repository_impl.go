@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ExperimentAssignmentRepositoryImpl A/B 实验分组仓储实现
+type ExperimentAssignmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewExperimentAssignmentRepository 构造函数
+func NewExperimentAssignmentRepository(db *gorm.DB) repository.ExperimentAssignmentRepository {
+	return &ExperimentAssignmentRepositoryImpl{db: db}
+}
+
+// FindAssignment 实现接口：查询用户在实验中保存过的分组
+//
+// 分组名格式不对的记录（如手工写入时写错）按没有分组处理
+func (r *ExperimentAssignmentRepositoryImpl) FindAssignment(
+	ctx context.Context,
+	experiment string,
+	userID valueobject.UserID,
+) (valueobject.ExperimentAssignment, bool, error) {
+	var po ExperimentAssignmentPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("experiment = ? AND user_id = ?", experiment, userID.Value()).
+		Take(&po).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return valueobject.ExperimentAssignment{}, false, nil
+	}
+	if err != nil {
+		return valueobject.ExperimentAssignment{}, false, err
+	}
+	assignment, err := valueobject.NewExperimentAssignment(po.Experiment, po.Variant)
+	if err != nil {
+		return valueobject.ExperimentAssignment{}, false, nil
+	}
+	return assignment, true, nil
+}
+
+// SaveAssignment 实现接口：保存用户的分组
+//
+// 使用 ON CONFLICT DO NOTHING（唯一索引 tenant_id + experiment + user_id）：
+// 已经有分组时不覆盖
+func (r *ExperimentAssignmentRepositoryImpl) SaveAssignment(
+	ctx context.Context,
+	userID valueobject.UserID,
+	assignment valueobject.ExperimentAssignment,
+) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ExperimentAssignmentPO{
+			TenantID:   valueobject.TenantFromContext(ctx).String(),
+			Experiment: assignment.Experiment(),
+			UserID:     userID.Value(),
+			Variant:    assignment.Variant(),
+			CreatedAt:  time.Now(),
+		}).Error
+}
+
+// ListUserAssignments 实现接口：查询用户保存过的所有分组
+//
+// 和 FindAssignment 一样，跳过分组名格式不对的记录
+func (r *ExperimentAssignmentRepositoryImpl) ListUserAssignments(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.ExperimentAssignment, error) {
+	var pos []ExperimentAssignmentPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("user_id = ?", userID.Value()).
+		Order("experiment ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]valueobject.ExperimentAssignment, 0, len(pos))
+	for _, po := range pos {
+		assignment, err := valueobject.NewExperimentAssignment(po.Experiment, po.Variant)
+		if err != nil {
+			continue // 容错：跳过格式错误的历史数据
+		}
+		result = append(result, assignment)
+	}
+	return result, nil
+}
+
+// DeleteUserAssignments 实现接口：删除用户在所有实验中的分组
+func (r *ExperimentAssignmentRepositoryImpl) DeleteUserAssignments(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("user_id = ?", userID.Value()).
+		Delete(&ExperimentAssignmentPO{}).Error
+}
+
+// ExperimentAssignmentPO A/B 实验分组持久化对象
+type ExperimentAssignmentPO struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID   string    `gorm:"type:varchar(32);default:'default';not null;uniqueIndex:uk_tenant_experiment_user,priority:1"`
+	Experiment string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_tenant_experiment_user,priority:2"`
+	UserID     int64     `gorm:"not null;uniqueIndex:uk_tenant_experiment_user,priority:3"`
+	Variant    string    `gorm:"type:varchar(32);not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (ExperimentAssignmentPO) TableName() string {
+	return "experiment_assignments"
+}
@@ -0,0 +1,270 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+	"service/infrastructure/crypto"
+)
+
+// reencryptBatchSize 重新加密时每批读取的行数
+const reencryptBatchSize = 500
+
+// FeedbackRepositoryImpl 推荐反馈记录仓储实现
+//
+// 字段加密：
+// 一条反馈记录了"谁对谁做了什么"，传入 codec 时被推荐用户（target）加密存储在 target_user_cipher，
+// 同时写入盲索引 target_user_index，删除用户数据时按它查找，target_user_id 写 0。
+// viewer 保持明文：导出、删除都按 viewer 查找，单独的 viewer 不暴露关系。
+// 开启加密前写入的明文记录仍然可以读取，由重新加密任务（ReencryptFields）逐步加密。
+type FeedbackRepositoryImpl struct {
+	db    *gorm.DB
+	codec *crypto.FieldCodec // 可以为 nil（明文存储）
+}
+
+// NewFeedbackRepository 构造函数（codec 可以为 nil；不为 nil 时必须配置盲索引密钥）
+func NewFeedbackRepository(db *gorm.DB, codec *crypto.FieldCodec) repository.FeedbackRepository {
+	return &FeedbackRepositoryImpl{db: db, codec: codec}
+}
+
+// RecordFeedback 实现接口：写入一次反馈
+//
+// 使用 ON CONFLICT DO NOTHING（唯一索引 tenant_id + recommendation_id + action）：
+// 重复上报时没有写入任何行，recorded 为 false
+func (r *FeedbackRepositoryImpl) RecordFeedback(
+	ctx context.Context,
+	feedback *entity.RecommendationFeedback,
+) (bool, error) {
+	po := FeedbackPO{
+		TenantID:         valueobject.TenantFromContext(ctx).String(),
+		RecommendationID: feedback.RecommendationID().Value(),
+		Action:           feedback.Action().String(),
+		ViewerID:         feedback.ViewerID().Value(),
+		TargetUserID:     feedback.TargetUserID().Value(),
+		ReasonType:       int(feedback.ReasonType()),
+		Surface:          feedback.Surface(),
+		Variant:          feedback.Variant().String(),
+		ShownAt:          feedback.ShownAt(),
+		CreatedAt:        feedback.At(),
+	}
+	if err := r.sealTarget(ctx, &po); err != nil {
+		return false, err
+	}
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&po)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteUserFeedback 实现接口：删除用户作为 viewer 或 target 的所有反馈记录
+//
+// 加密的 target 按盲索引查找；还没有加密的历史记录按 target_user_id 查找。
+func (r *FeedbackRepositoryImpl) DeleteUserFeedback(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	tx := conn(ctx, r.db).Scopes(tenantScope(ctx))
+	if r.codec == nil {
+		tx = tx.Where("viewer_id = ? OR target_user_id = ?", userID.Value(), userID.Value())
+	} else {
+		index, err := r.codec.BlindIndex(ctx, strconv.FormatInt(userID.Value(), 10))
+		if err != nil {
+			return err
+		}
+		tx = tx.Where("viewer_id = ? OR target_user_id = ? OR target_user_index = ?", userID.Value(), userID.Value(), index)
+	}
+	return tx.Delete(&FeedbackPO{}).Error
+}
+
+// ListUserFeedback 实现接口：查询用户做出的所有反馈
+func (r *FeedbackRepositoryImpl) ListUserFeedback(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.RecommendationFeedback, error) {
+	var pos []FeedbackPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ?", viewerID.Value()).
+		Order("created_at ASC, id ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.RecommendationFeedback, 0, len(pos))
+	for _, po := range pos {
+		recommendationID, err := valueobject.RecommendationIDFromString(po.RecommendationID)
+		if err != nil {
+			continue // 容错：跳过格式错误的历史数据
+		}
+		target, err := r.openTarget(ctx, po)
+		if err != nil {
+			return nil, err
+		}
+		targetUserID, err := valueobject.NewUserID(target)
+		if err != nil {
+			continue
+		}
+		action, err := valueobject.ParseFeedbackAction(po.Action)
+		if err != nil {
+			continue
+		}
+		// 反馈冗余保存了曝光的字段，从它们还原曝光
+		impression := entity.NewImpression(recommendationID, viewerID, targetUserID, valueobject.ReasonType(po.ReasonType), po.Surface, po.ShownAt)
+		if variant, err := valueobject.ParseExperimentAssignment(po.Variant); err == nil {
+			impression.AssignVariant(variant)
+		}
+		result = append(result, entity.NewRecommendationFeedback(impression, action, po.CreatedAt))
+	}
+	return result, nil
+}
+
+// DeleteFeedbackBefore 实现接口：分批删除过期的反馈记录（和曝光记录一样每批 DELETE ... LIMIT）
+func (r *FeedbackRepositoryImpl) DeleteFeedbackBefore(
+	ctx context.Context,
+	cutoff time.Time,
+) (int64, error) {
+	var total int64
+	for {
+		result := conn(ctx, r.db).
+			Where("created_at < ?", cutoff).
+			Limit(purgeBatchSize).
+			Delete(&FeedbackPO{})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// OldestFeedbackTime 实现接口：查询最早的反馈时间
+func (r *FeedbackRepositoryImpl) OldestFeedbackTime(ctx context.Context) (time.Time, bool, error) {
+	var pos []FeedbackPO
+	err := conn(ctx, r.db).
+		Select("created_at").
+		Order("created_at ASC").
+		Limit(1).
+		Find(&pos).Error
+	if err != nil || len(pos) == 0 {
+		return time.Time{}, false, err
+	}
+	return pos[0].CreatedAt, true, nil
+}
+
+// ReencryptFields 重新加密：把明文的 target 和不是用当前密钥加密的 target 换成当前密钥的密文
+//
+// 由重新加密任务定期调用（不限租户），按主键分批扫描，返回更新的行数。没有 codec 时什么都不做。
+func (r *FeedbackRepositoryImpl) ReencryptFields(ctx context.Context) (int64, error) {
+	if r.codec == nil {
+		return 0, nil
+	}
+	var updated int64
+	var pos []FeedbackPO
+	err := conn(ctx, r.db).
+		Select("id", "target_user_id", "target_user_cipher", "target_user_index").
+		FindInBatches(&pos, reencryptBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range pos {
+				po := &pos[i]
+				if po.TargetUserCipher != "" {
+					stale, err := r.codec.NeedsReencrypt(ctx, po.TargetUserCipher)
+					if err != nil {
+						return err
+					}
+					if !stale {
+						continue
+					}
+				}
+				target, err := r.openTarget(ctx, *po)
+				if err != nil {
+					return err
+				}
+				po.TargetUserID = target
+				if err := r.sealTarget(ctx, po); err != nil {
+					return err
+				}
+				err = conn(ctx, r.db).Model(&FeedbackPO{}).
+					Where("id = ?", po.ID).
+					Updates(map[string]any{
+						"target_user_id":     po.TargetUserID,
+						"target_user_cipher": po.TargetUserCipher,
+						"target_user_index":  po.TargetUserIndex,
+					}).Error
+				if err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	return updated, err
+}
+
+// sealTarget 辅助方法：加密 target，写入密文和盲索引（没有 codec 时保持明文）
+func (r *FeedbackRepositoryImpl) sealTarget(ctx context.Context, po *FeedbackPO) error {
+	if r.codec == nil {
+		return nil
+	}
+	plaintext := strconv.FormatInt(po.TargetUserID, 10)
+	cipher, err := r.codec.Encrypt(ctx, plaintext)
+	if err != nil {
+		return err
+	}
+	index, err := r.codec.BlindIndex(ctx, plaintext)
+	if err != nil {
+		return err
+	}
+	po.TargetUserID, po.TargetUserCipher, po.TargetUserIndex = 0, cipher, index
+	return nil
+}
+
+// openTarget 辅助方法：读出 target（没有密文时是开启加密前的明文记录）
+func (r *FeedbackRepositoryImpl) openTarget(ctx context.Context, po FeedbackPO) (int64, error) {
+	if po.TargetUserCipher == "" {
+		return po.TargetUserID, nil
+	}
+	if r.codec == nil {
+		return 0, fmt.Errorf("persistence: feedback %d is encrypted but encryption is disabled", po.ID)
+	}
+	plaintext, err := r.codec.Decrypt(ctx, po.TargetUserCipher)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(plaintext, 10, 64)
+}
+
+// FeedbackPO 推荐反馈记录持久化对象
+//
+// 和转化记录一样冗余保存曝光的理由类型、场景和时间：统计时不需要再 JOIN 曝光表
+type FeedbackPO struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID         string    `gorm:"type:varchar(32);default:'default';not null;uniqueIndex:uk_tenant_recommendation_action,priority:1"`
+	RecommendationID string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_tenant_recommendation_action,priority:2"`
+	Action           string    `gorm:"type:varchar(16);not null;uniqueIndex:uk_tenant_recommendation_action,priority:3"`
+	ViewerID         int64     `gorm:"index:idx_viewer;not null"`
+	TargetUserID     int64     `gorm:"index:idx_target;not null"`             // 用户数据删除时按 target 查找；加密存储时为 0
+	TargetUserCipher string    `gorm:"type:varchar(128);default:'';not null"` // 加密的 target（enc:v1:...），为空时未加密
+	TargetUserIndex  string    `gorm:"type:char(64);index:idx_target_index"`  // target 的盲索引（HMAC），用户数据删除时按它查找
+	ReasonType       int       `gorm:"type:smallint;default:0;not null"`
+	Surface          string    `gorm:"type:varchar(32)"`
+	Variant          string    `gorm:"type:varchar(65);default:'';not null"` // 曝光时的 A/B 实验分组（实验名/分组名）
+	ShownAt          time.Time `gorm:"index:idx_shown_at;not null"`          // 统计按曝光时间查找
+	CreatedAt        time.Time `gorm:"index:idx_created_at;not null"`        // 按保留期删除时按 created_at 查找
+}
+
+// TableName 指定表名
+func (FeedbackPO) TableName() string {
+	return "recommendation_feedback"
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// GroupMembershipRepositoryImpl 小组成员关系仓储实现
+type GroupMembershipRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGroupMembershipRepository 构造函数
+func NewGroupMembershipRepository(db *gorm.DB) repository.GroupMembershipRepository {
+	return &GroupMembershipRepositoryImpl{db: db}
+}
+
+// GetUserGroups 实现接口：用户加入的小组
+func (r *GroupMembershipRepositoryImpl) GetUserGroups(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.GroupID, error) {
+	var groupIDs []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&GroupMemberPO{}).
+		Where("user_id = ?", userID.Value()).
+		Pluck("group_id", &groupIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]valueobject.GroupID, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		groupID, err := valueobject.NewGroupID(id)
+		if err != nil {
+			continue
+		}
+		result = append(result, groupID)
+	}
+	return result, nil
+}
+
+// GetGroupsOfUsers 实现接口：批量查询（WHERE user_id IN (...)）
+func (r *GroupMembershipRepositoryImpl) GetGroupsOfUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID][]valueobject.GroupID, error) {
+	result := make(map[valueobject.UserID][]valueobject.GroupID)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.Value())
+	}
+
+	var pos []GroupMemberPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("group_id", "user_id").
+		Where("user_id IN ?", ids).
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, po := range pos {
+		userID, err := valueobject.NewUserID(po.UserID)
+		if err != nil {
+			continue
+		}
+		groupID, err := valueobject.NewGroupID(po.GroupID)
+		if err != nil {
+			continue
+		}
+		result[userID] = append(result[userID], groupID)
+	}
+	return result, nil
+}
+
+// CountMembers 实现接口：按小组统计成员数（GROUP BY group_id）
+func (r *GroupMembershipRepositoryImpl) CountMembers(
+	ctx context.Context,
+	groupIDs []valueobject.GroupID,
+) (map[valueobject.GroupID]int, error) {
+	result := make(map[valueobject.GroupID]int, len(groupIDs))
+	if len(groupIDs) == 0 {
+		return result, nil
+	}
+	ids := make([]int64, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		ids = append(ids, groupID.Value())
+	}
+
+	var rows []struct {
+		GroupID int64
+		Members int
+	}
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&GroupMemberPO{}).
+		Select("group_id, COUNT(*) AS members").
+		Where("group_id IN ?", ids).
+		Group("group_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		groupID, err := valueobject.NewGroupID(row.GroupID)
+		if err != nil {
+			continue
+		}
+		result[groupID] = row.Members
+	}
+	return result, nil
+}
+
+// GroupMemberPO 小组成员持久化对象（由社区服务写入，推荐服务只读）
+//
+// 主键 (tenant_id, group_id, user_id)；idx_tenant_user 用于按用户查询加入的小组
+type GroupMemberPO struct {
+	TenantID string    `gorm:"primaryKey;type:varchar(32);default:'default';index:idx_tenant_user,priority:1"`
+	GroupID  int64     `gorm:"primaryKey;autoIncrement:false"`
+	UserID   int64     `gorm:"primaryKey;autoIncrement:false;index:idx_tenant_user,priority:2"`
+	JoinedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (GroupMemberPO) TableName() string {
+	return "group_members"
+}
@@ -0,0 +1,270 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// ImpressionRepositoryImpl 曝光记录仓储实现
+type ImpressionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewImpressionRepository 构造函数
+func NewImpressionRepository(db *gorm.DB) repository.ImpressionRepository {
+	return &ImpressionRepositoryImpl{db: db}
+}
+
+// RecordImpressions 实现接口：批量写入曝光记录
+//
+// 使用 CreateInBatches 一次写入多条，避免逐条 INSERT
+func (r *ImpressionRepositoryImpl) RecordImpressions(
+	ctx context.Context,
+	impressions []*entity.Impression,
+) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+
+	// 转换 领域实体 -> PO
+	tenant := valueobject.TenantFromContext(ctx).String()
+	pos := make([]ImpressionPO, 0, len(impressions))
+	for _, impression := range impressions {
+		pos = append(pos, ImpressionPO{
+			TenantID:         tenant,
+			RecommendationID: impression.RecommendationID().Value(),
+			ViewerID:         impression.ViewerID().Value(),
+			TargetUserID:     impression.TargetUserID().Value(),
+			ReasonType:       int(impression.ReasonType()),
+			Surface:          impression.Surface(),
+			ShownAt:          impression.ShownAt(),
+			Experiment:       impression.Experiment(),
+			Ranker:           impression.Ranker(),
+			Variant:          impression.Variant().String(),
+		})
+	}
+
+	return conn(ctx, r.db).CreateInBatches(pos, 100).Error
+}
+
+// GetImpressedTargets 实现接口：查询曝光过的推荐对象（去重）
+func (r *ImpressionRepositoryImpl) GetImpressedTargets(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	since time.Time,
+) ([]valueobject.UserID, error) {
+
+	var targetIDs []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&ImpressionPO{}).
+		Distinct("target_user_id").
+		Where("viewer_id = ? AND shown_at >= ?", viewerID.Value(), since).
+		Pluck("target_user_id", &targetIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换 PO -> 领域对象
+	result := make([]valueobject.UserID, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		domainID, _ := valueobject.NewUserID(id)
+		result = append(result, domainID)
+	}
+
+	return result, nil
+}
+
+// ListUserImpressions 实现接口：查询展示给用户的所有曝光记录
+func (r *ImpressionRepositoryImpl) ListUserImpressions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.Impression, error) {
+	var pos []ImpressionPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ?", viewerID.Value()).
+		Order("shown_at ASC, id ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toImpressions(viewerID, pos), nil
+}
+
+// ListImpressionHistory 实现接口：按 (shown_at, recommendation_id) 游标分页查询曝光记录
+//
+// 和 ScanFollowings 一样用游标而不是 OFFSET：翻到很多页之前的记录也走 idx_viewer_shown 直接定位。
+func (r *ImpressionRepositoryImpl) ListImpressionHistory(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	from, to time.Time,
+	after *repository.ImpressionCursor,
+	limit int,
+) ([]*entity.Impression, error) {
+	query := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ? AND shown_at < ?", viewerID.Value(), to)
+	if !from.IsZero() {
+		query = query.Where("shown_at >= ?", from)
+	}
+	if after != nil {
+		query = query.Where("(shown_at < ? OR (shown_at = ? AND recommendation_id < ?))",
+			after.ShownAt, after.ShownAt, after.RecommendationID)
+	}
+
+	var pos []ImpressionPO
+	err := query.Order("shown_at DESC, recommendation_id DESC").Limit(limit).Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return toImpressions(viewerID, pos), nil
+}
+
+// toImpressions 辅助函数：PO -> 领域实体
+func toImpressions(viewerID valueobject.UserID, pos []ImpressionPO) []*entity.Impression {
+	result := make([]*entity.Impression, 0, len(pos))
+	for _, po := range pos {
+		recommendationID, err := valueobject.RecommendationIDFromString(po.RecommendationID)
+		if err != nil {
+			continue // 容错：跳过格式错误的历史数据
+		}
+		targetUserID, err := valueobject.NewUserID(po.TargetUserID)
+		if err != nil {
+			continue
+		}
+		impression := entity.NewImpression(recommendationID, viewerID, targetUserID, valueobject.ReasonType(po.ReasonType), po.Surface, po.ShownAt)
+		if po.Experiment != "" {
+			impression.TagExperiment(po.Experiment, po.Ranker)
+		}
+		if variant, err := valueobject.ParseExperimentAssignment(po.Variant); err == nil {
+			impression.AssignVariant(variant)
+		}
+		result = append(result, impression)
+	}
+	return result
+}
+
+// FindLatestImpression 实现接口：查询 viewer 在时间范围内看到的 target 的最近一次曝光
+//
+// 走 idx_viewer_shown（viewer_id + shown_at），同一个 viewer 一段时间内的曝光不多，target 在索引之后过滤
+func (r *ImpressionRepositoryImpl) FindLatestImpression(
+	ctx context.Context,
+	viewerID, targetUserID valueobject.UserID,
+	from, to time.Time,
+) (*entity.Impression, bool, error) {
+	var pos []ImpressionPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ? AND target_user_id = ? AND shown_at >= ? AND shown_at <= ?",
+			viewerID.Value(), targetUserID.Value(), from, to).
+		Order("shown_at DESC, recommendation_id DESC").
+		Limit(1).
+		Find(&pos).Error
+	if err != nil {
+		return nil, false, err
+	}
+	impressions := toImpressions(viewerID, pos)
+	if len(impressions) == 0 {
+		return nil, false, nil
+	}
+	return impressions[0], true, nil
+}
+
+// FindImpression 实现接口：按推荐ID查询展示给 viewer 的曝光
+//
+// 走 idx_recommendation（recommendation_id），viewer 在索引之后过滤：推荐ID不属于这个 viewer 时当作没有曝光
+func (r *ImpressionRepositoryImpl) FindImpression(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	recommendationID valueobject.RecommendationID,
+) (*entity.Impression, bool, error) {
+	var pos []ImpressionPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("recommendation_id = ? AND viewer_id = ?", recommendationID.Value(), viewerID.Value()).
+		Order("shown_at DESC").
+		Limit(1).
+		Find(&pos).Error
+	if err != nil {
+		return nil, false, err
+	}
+	impressions := toImpressions(viewerID, pos)
+	if len(impressions) == 0 {
+		return nil, false, nil
+	}
+	return impressions[0], true, nil
+}
+
+// DeleteUserImpressions 实现接口：删除用户作为 viewer 或 target 的所有曝光记录
+func (r *ImpressionRepositoryImpl) DeleteUserImpressions(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("viewer_id = ? OR target_user_id = ?", userID.Value(), userID.Value()).
+		Delete(&ImpressionPO{}).Error
+}
+
+// purgeBatchSize 按保留期删除时每批删除的条数
+const purgeBatchSize = 10000
+
+// DeleteImpressionsBefore 实现接口：分批删除过期的曝光记录
+//
+// 每批 DELETE ... LIMIT 10000，单条语句持锁时间短，不影响线上写入
+func (r *ImpressionRepositoryImpl) DeleteImpressionsBefore(
+	ctx context.Context,
+	cutoff time.Time,
+) (int64, error) {
+	var total int64
+	for {
+		result := conn(ctx, r.db).
+			Where("shown_at < ?", cutoff).
+			Limit(purgeBatchSize).
+			Delete(&ImpressionPO{})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// OldestImpressionTime 实现接口：查询最早的曝光时间
+func (r *ImpressionRepositoryImpl) OldestImpressionTime(ctx context.Context) (time.Time, bool, error) {
+	var pos []ImpressionPO
+	err := conn(ctx, r.db).
+		Select("shown_at").
+		Order("shown_at ASC").
+		Limit(1).
+		Find(&pos).Error
+	if err != nil || len(pos) == 0 {
+		return time.Time{}, false, err
+	}
+	return pos[0].ShownAt, true, nil
+}
+
+// ImpressionPO 曝光记录持久化对象
+type ImpressionPO struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID         string    `gorm:"type:varchar(32);default:'default';not null"`
+	RecommendationID string    `gorm:"type:varchar(36);not null;index:idx_recommendation"` // 上报反馈时按推荐ID查找
+	ViewerID         int64     `gorm:"index:idx_viewer_shown,priority:1;not null"`
+	TargetUserID     int64     `gorm:"index:idx_target;not null"` // 用户数据删除时按 target 查找
+	ReasonType       int       `gorm:"type:smallint;default:0;not null"`
+	Surface          string    `gorm:"type:varchar(32)"`
+	ShownAt          time.Time `gorm:"index:idx_viewer_shown,priority:2;index:idx_shown_at;index:idx_experiment_shown,priority:2;not null"` // 按保留期删除时按 shown_at 查找
+	Experiment       string    `gorm:"type:varchar(32);default:'';not null;index:idx_experiment_shown,priority:1"`                          // 交错实验（统计胜率时按实验查找）
+	Ranker           string    `gorm:"type:varchar(8);default:'';not null"`
+	Variant          string    `gorm:"type:varchar(65);default:'';not null"` // A/B 实验的分组（实验名/分组名）
+}
+
+// TableName 指定表名
+func (ImpressionPO) TableName() string {
+	return "recommendation_impressions"
+}
@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// InteractionRepositoryImpl 互动记录仓储实现
+type InteractionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewInteractionRepository 构造函数
+func NewInteractionRepository(db *gorm.DB) repository.InteractionRepository {
+	return &InteractionRepositoryImpl{db: db}
+}
+
+// GetLastInteractions 实现接口：一次查询（WHERE user_id = ? AND target_user_id IN (...)）
+func (r *InteractionRepositoryImpl) GetLastInteractions(
+	ctx context.Context,
+	userID valueobject.UserID,
+	targets []valueobject.UserID,
+) (map[valueobject.UserID]time.Time, error) {
+	result := make(map[valueobject.UserID]time.Time)
+	if len(targets) == 0 {
+		return result, nil
+	}
+	ids := make([]int64, 0, len(targets))
+	for _, target := range targets {
+		ids = append(ids, target.Value())
+	}
+
+	var pos []InteractionPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("target_user_id", "last_interacted_at").
+		Where("user_id = ? AND target_user_id IN ?", userID.Value(), ids).
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, po := range pos {
+		targetID, err := valueobject.NewUserID(po.TargetUserID)
+		if err != nil {
+			continue
+		}
+		result[targetID] = po.LastInteractedAt
+	}
+	return result, nil
+}
+
+// receivedEngagementBatchSize CountReceivedEngagement 每条 SQL 最多查询的用户数（IN 列表太长时拆成多条）
+const receivedEngagementBatchSize = 1000
+
+// CountReceivedEngagement 实现接口：按天汇总表统计（SUM ... GROUP BY user_id）
+//
+// 一条 SQL 统计一批用户（走主键前缀 tenant_id, user_id）；没有收到互动的用户不出现在结果中。
+// 超过 receivedEngagementBatchSize 个用户时分批查询。
+func (r *InteractionRepositoryImpl) CountReceivedEngagement(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID]valueobject.CreatorEngagement, error) {
+	result := make(map[valueobject.UserID]valueobject.CreatorEngagement, len(userIDs))
+	since := time.Now().AddDate(0, 0, -days)
+
+	for start := 0; start < len(userIDs); start += receivedEngagementBatchSize {
+		batch := userIDs[start:min(start+receivedEngagementBatchSize, len(userIDs))]
+		ids := make([]int64, 0, len(batch))
+		for _, userID := range batch {
+			ids = append(ids, userID.Value())
+		}
+
+		var rows []struct {
+			UserID   int64
+			Likes    int
+			Comments int
+		}
+		err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Model(&ReceivedEngagementPO{}).
+			Select("user_id, SUM(likes) AS likes, SUM(comments) AS comments").
+			Where("user_id IN ? AND day >= ?", ids, since.Format(time.DateOnly)).
+			Group("user_id").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			userID, err := valueobject.NewUserID(row.UserID)
+			if err != nil {
+				continue
+			}
+			result[userID] = valueobject.CreatorEngagement{Likes: row.Likes, Comments: row.Comments}
+		}
+	}
+	return result, nil
+}
+
+// InteractionPO 最近一次互动的持久化对象（由互动服务写入，推荐服务只读）
+//
+// 主键 (tenant_id, user_id, target_user_id)：每对用户只保存最近一次互动的时间
+type InteractionPO struct {
+	TenantID         string    `gorm:"primaryKey;type:varchar(32);default:'default'"`
+	UserID           int64     `gorm:"primaryKey;autoIncrement:false"`
+	TargetUserID     int64     `gorm:"primaryKey;autoIncrement:false"`
+	LastInteractedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (InteractionPO) TableName() string {
+	return "user_interactions"
+}
+
+// ReceivedEngagementPO 每个人每天收到的点赞、评论数（由互动服务按天汇总写入，推荐服务只读）
+//
+// 主键 (tenant_id, user_id, day)：按天汇总，统计最近 N 天只需要读 N 行
+type ReceivedEngagementPO struct {
+	TenantID string `gorm:"primaryKey;type:varchar(32);default:'default'"`
+	UserID   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Day      string `gorm:"primaryKey;type:date"` // YYYY-MM-DD
+	Likes    int    `gorm:"not null;default:0"`
+	Comments int    `gorm:"not null;default:0"`
+}
+
+// TableName 指定表名
+func (ReceivedEngagementPO) TableName() string {
+	return "user_received_engagement_daily"
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// 冷启动名单（popular_users.list）
+const (
+	popularListPopular = "popular" // 全站热门（离线任务每天按新增粉丝数计算）
+	popularListCurated = "curated" // 编辑精选（运营后台维护）
+)
+
+// PopularUsersRepositoryImpl 冷启动名单仓储实现
+type PopularUsersRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPopularUsersRepository 构造函数
+func NewPopularUsersRepository(db *gorm.DB) repository.PopularUsersRepository {
+	return &PopularUsersRepositoryImpl{db: db}
+}
+
+// GetPopularUsers 实现接口：WHERE list = 'popular' ORDER BY position
+func (r *PopularUsersRepositoryImpl) GetPopularUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	return r.getList(ctx, popularListPopular, limit)
+}
+
+// GetCuratedUsers 实现接口：WHERE list = 'curated' ORDER BY position
+func (r *PopularUsersRepositoryImpl) GetCuratedUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	return r.getList(ctx, popularListCurated, limit)
+}
+
+// getList 辅助方法：一份名单的前 limit 个用户
+func (r *PopularUsersRepositoryImpl) getList(ctx context.Context, list string, limit int) ([]valueobject.UserID, error) {
+	var ids []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&PopularUserPO{}).
+		Where("list = ?", list).
+		Order("position").
+		Limit(limit).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return toUserIDs(ids), nil
+}
+
+// PopularUserPO 冷启动名单持久化对象（由离线任务、运营后台写入，推荐服务只读）
+//
+// 主键 (tenant_id, list, user_id)；position 是名单中的顺序（从 0 开始，越小越靠前）
+type PopularUserPO struct {
+	TenantID  string    `gorm:"primaryKey;type:varchar(32);default:'default'"`
+	List      string    `gorm:"primaryKey;type:varchar(16)"`
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Position  int       `gorm:"not null;index:idx_list_position"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (PopularUserPO) TableName() string {
+	return "popular_users"
+}
@@ -0,0 +1,350 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// recentFollowIndexKeyPrefix 反向关注索引的 Redis key 前缀
+//
+// 每个租户的每个用户一个有序集合：rec:{tenant}:rfi:{userID}
+// - member: "{candidateID}:{referrerID}"（referrer 最近关注了 candidate）
+// - score:  关注发生的时间（Unix 秒）
+const recentFollowIndexKeyPrefix = "rec:"
+
+// followerBatchSize 写扩散时每批处理的粉丝数（一批一个 pipeline）
+const followerBatchSize = 1000
+
+// FollowAction 关注事件类型
+type FollowAction string
+
+const (
+	FollowActionFollow   FollowAction = "follow"
+	FollowActionUnfollow FollowAction = "unfollow"
+)
+
+// FollowEvent 关注事件（由社交服务通过消息队列发布）
+type FollowEvent struct {
+	FollowerID  int64        `json:"follower_id"`  // 谁发起了关注/取关
+	FollowingID int64        `json:"following_id"` // 被关注/取关的人
+	Action      FollowAction `json:"action"`
+	OccurredAt  time.Time    `json:"occurred_at"`
+	TenantID    string       `json:"tenant_id,omitempty"` // 为空表示默认租户（多租户之前的事件没有这个字段）
+}
+
+// RedisRecentFollowIndex 反向关注索引的 Redis 实现
+//
+// 索引内容：
+// 用户 A 的索引保存"A 关注的人最近关注了谁"，
+// 生成推荐时一次 ZRANGEBYSCORE 就能拿到全部推荐信号。
+//
+// 索引维护（写扩散）：
+// - B 关注了 E：把 "E:B" 写入 B 所有粉丝的索引
+// - A 关注了 B：把 B 保留时间内的关注回填到 A 的索引（A 的索引不存在时不回填）
+// - B 取关了 E：把 "E:B" 从 B 所有粉丝的索引中删除
+// - A 取关了 B：A 的索引中删除所有来自 B 的记录
+// 每次写入顺带清理超过保留时间的记录，并刷新 key 的过期时间。
+//
+// 已知限制：
+// - 粉丝很多的账号写扩散成本高，粉丝按批写入（每批一个 pipeline）
+//
+// 索引没有建立（key 不存在）或查询窗口超过保留时间时，
+// 返回 ErrRecentFollowIndexMiss，由领域服务回退到逐个查询。
+type RedisRecentFollowIndex struct {
+	rdb       redis.UniversalClient
+	db        *gorm.DB // 查询粉丝列表（写扩散时使用）
+	retention time.Duration
+}
+
+// NewRedisRecentFollowIndex 构造函数
+//
+// 参数：
+// - rdb: Redis 客户端
+// - db: 关注关系数据库（follows 表）
+// - retention: 索引保留多久的关注记录（应不小于推荐使用的"最近N天"）
+func NewRedisRecentFollowIndex(rdb redis.UniversalClient, db *gorm.DB, retention time.Duration) *RedisRecentFollowIndex {
+	return &RedisRecentFollowIndex{
+		rdb:       rdb,
+		db:        db,
+		retention: retention,
+	}
+}
+
+// GetRecentFollowsOfFollowings 实现接口：一次读取用户的反向关注索引
+func (r *RedisRecentFollowIndex) GetRecentFollowsOfFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]repository.RecentFollow, error) {
+
+	window := time.Duration(days) * 24 * time.Hour
+	if window > r.retention {
+		// 索引没有保存这么久的记录，无法给出完整结果
+		return nil, repository.ErrRecentFollowIndexMiss
+	}
+
+	key := recentFollowIndexKey(ctx, userID.Value())
+	since := time.Now().Add(-window).Unix()
+
+	var exists *redis.IntCmd
+	var members *redis.StringSliceCmd
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = pipe.Exists(ctx, key)
+		members = pipe.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+			Min: strconv.FormatInt(since, 10),
+			Max: "+inf",
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if exists.Val() == 0 {
+		return nil, repository.ErrRecentFollowIndexMiss
+	}
+
+	// 转换 member -> 领域对象
+	result := make([]repository.RecentFollow, 0, len(members.Val()))
+	for _, member := range members.Val() {
+		candidateID, referrerID, ok := parseRecentFollowMember(member)
+		if !ok {
+			continue
+		}
+		candidate, err := valueobject.NewUserID(candidateID)
+		if err != nil {
+			continue
+		}
+		referrer, err := valueobject.NewUserID(referrerID)
+		if err != nil {
+			continue
+		}
+		result = append(result, repository.RecentFollow{
+			Referrer:  referrer,
+			Candidate: candidate,
+		})
+	}
+	return result, nil
+}
+
+// HandleFollowEvent 根据关注事件更新索引
+//
+// 由消息队列消费者调用。返回错误时消费者应该重试（写入是幂等的）。
+func (r *RedisRecentFollowIndex) HandleFollowEvent(ctx context.Context, event FollowEvent) error {
+	if event.TenantID != "" {
+		tenant, err := valueobject.NewTenantID(event.TenantID)
+		if err != nil {
+			return err
+		}
+		ctx = valueobject.WithTenant(ctx, tenant)
+	}
+	switch event.Action {
+	case FollowActionFollow:
+		return r.onFollow(ctx, event)
+	case FollowActionUnfollow:
+		return r.onUnfollow(ctx, event)
+	default:
+		return fmt.Errorf("unknown follow action: %q", event.Action)
+	}
+}
+
+// onFollow A 关注了 B：
+// 1. 把 "B:A" 写入 A 所有粉丝的索引
+// 2. 把 B 最近的关注回填到 A 自己的索引（B 成为 A 的推荐信号来源）
+func (r *RedisRecentFollowIndex) onFollow(ctx context.Context, event FollowEvent) error {
+	member := recentFollowMember(event.FollowingID, event.FollowerID)
+	score := float64(event.OccurredAt.Unix())
+	expiredBefore := strconv.FormatInt(time.Now().Add(-r.retention).Unix(), 10)
+
+	err := r.forEachFollowerBatch(ctx, event.FollowerID, func(followerIDs []int64) error {
+		_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, followerID := range followerIDs {
+				key := recentFollowIndexKey(ctx, followerID)
+				pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: member})
+				pipe.ZRemRangeByScore(ctx, key, "-inf", "("+expiredBefore)
+				pipe.Expire(ctx, key, r.retention)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return r.backfill(ctx, event.FollowerID, event.FollowingID)
+}
+
+// backfill 把 referrer 保留时间内的关注写入 userID 的索引
+//
+// 索引不存在时不回填：回填之后 key 存在，但只有 referrer 一个人的记录，
+// 查询会把不完整的索引当作命中；不存在时查询返回 ErrRecentFollowIndexMiss，回退到逐个查询。
+// 用 WATCH 保证检查和写入之间索引没有过期或被删除；被并发修改时删除索引，下一次查询回退。
+func (r *RedisRecentFollowIndex) backfill(ctx context.Context, userID, referrerID int64) error {
+	var follows []FollowPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("following_id", "created_at").
+		Where("follower_id = ? AND status = ? AND created_at >= ?", referrerID, "active", time.Now().Add(-r.retention)).
+		Find(&follows).Error
+	if err != nil || len(follows) == 0 {
+		return err
+	}
+
+	key := recentFollowIndexKey(ctx, userID)
+	members := make([]redis.Z, 0, len(follows))
+	for _, follow := range follows {
+		members = append(members, redis.Z{
+			Score:  float64(follow.CreatedAt.Unix()),
+			Member: recentFollowMember(follow.FollowingID, referrerID),
+		})
+	}
+	err = r.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil || exists == 0 {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZAdd(ctx, key, members...)
+			pipe.Expire(ctx, key, r.retention)
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return r.rdb.Del(ctx, key).Err()
+	}
+	return err
+}
+
+// onUnfollow A 取关了 B：
+// 1. 从 A 所有粉丝的索引中删除 "B:A"
+// 2. 从 A 自己的索引中删除所有来自 B 的记录（B 不再是 A 的推荐信号来源）
+func (r *RedisRecentFollowIndex) onUnfollow(ctx context.Context, event FollowEvent) error {
+	member := recentFollowMember(event.FollowingID, event.FollowerID)
+	err := r.forEachFollowerBatch(ctx, event.FollowerID, func(followerIDs []int64) error {
+		_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, followerID := range followerIDs {
+				pipe.ZRem(ctx, recentFollowIndexKey(ctx, followerID), member)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 索引只保留最近的记录，数据量小，直接读出来过滤
+	key := recentFollowIndexKey(ctx, event.FollowerID)
+	members, err := r.rdb.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	stale := make([]interface{}, 0)
+	for _, m := range members {
+		_, referrerID, ok := parseRecentFollowMember(m)
+		if ok && referrerID == event.FollowingID {
+			stale = append(stale, m)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return r.rdb.ZRem(ctx, key, stale...).Err()
+}
+
+// DeleteUserIndex 实现接口：删除与用户有关的索引数据
+//
+// 1. 删除用户自己的索引
+// 2. 用户最近关注过的每个人 E：从用户所有粉丝的索引中删除 "E:用户"
+//
+// 已知限制：
+// 用户作为被推荐对象（candidate）出现在别人索引里的记录无法高效定位，
+// 这些记录只有用户ID，并且会在保留时间（retention）后自然过期；
+// 在此之前，推荐生成会通过删除标记（UserErasureRepository）跳过该用户。
+func (r *RedisRecentFollowIndex) DeleteUserIndex(ctx context.Context, userID valueobject.UserID) error {
+	if err := r.rdb.Del(ctx, recentFollowIndexKey(ctx, userID.Value())).Err(); err != nil {
+		return err
+	}
+
+	// 超过保留时间的关注不会出现在任何索引里，只查保留时间内的
+	var followingIDs []int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&FollowPO{}).
+		Where("follower_id = ? AND created_at >= ?", userID.Value(), time.Now().Add(-r.retention)).
+		Pluck("following_id", &followingIDs).Error
+	if err != nil {
+		return err
+	}
+	if len(followingIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(followingIDs))
+	for _, followingID := range followingIDs {
+		members = append(members, recentFollowMember(followingID, userID.Value()))
+	}
+	return r.forEachFollowerBatch(ctx, userID.Value(), func(followerIDs []int64) error {
+		_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, followerID := range followerIDs {
+				pipe.ZRem(ctx, recentFollowIndexKey(ctx, followerID), members...)
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+// forEachFollowerBatch 分批遍历用户的粉丝
+func (r *RedisRecentFollowIndex) forEachFollowerBatch(
+	ctx context.Context,
+	userID int64,
+	fn func(followerIDs []int64) error,
+) error {
+	var batch []FollowPO
+	return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("id", "follower_id").
+		Where("following_id = ? AND status = ?", userID, "active").
+		FindInBatches(&batch, followerBatchSize, func(tx *gorm.DB, _ int) error {
+			followerIDs := make([]int64, 0, len(batch))
+			for _, follow := range batch {
+				followerIDs = append(followerIDs, follow.FollowerID)
+			}
+			return fn(followerIDs)
+		}).Error
+}
+
+// recentFollowIndexKey 用户的索引 key（租户来自 ctx）
+func recentFollowIndexKey(ctx context.Context, userID int64) string {
+	return recentFollowIndexKeyPrefix + valueobject.TenantFromContext(ctx).String() + ":rfi:" + strconv.FormatInt(userID, 10)
+}
+
+// recentFollowMember 索引 member："{candidateID}:{referrerID}"
+func recentFollowMember(candidateID, referrerID int64) string {
+	return strconv.FormatInt(candidateID, 10) + ":" + strconv.FormatInt(referrerID, 10)
+}
+
+// parseRecentFollowMember 解析索引 member
+func parseRecentFollowMember(member string) (candidateID, referrerID int64, ok bool) {
+	candidatePart, referrerPart, found := strings.Cut(member, ":")
+	if !found {
+		return 0, 0, false
+	}
+	candidateID, err := strconv.ParseInt(candidatePart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	referrerID, err = strconv.ParseInt(referrerPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return candidateID, referrerID, true
+}
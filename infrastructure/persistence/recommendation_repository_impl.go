@@ -0,0 +1,261 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// RecommendationRepositoryImpl 推荐列表仓储实现
+//
+// 一份列表写两张表：recommendation_lists（每次生成一行）+ recommendation_list_items（每条推荐一行），
+// 在同一个事务中写入，读取时不会看到只写了一半的列表。
+type RecommendationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecommendationRepository 构造函数
+func NewRecommendationRepository(db *gorm.DB) repository.RecommendationRepository {
+	return &RecommendationRepositoryImpl{db: db}
+}
+
+// SaveList 实现接口：在一个事务中写入列表和其中的推荐
+func (r *RecommendationRepositoryImpl) SaveList(ctx context.Context, list *aggregate.RecommendationList) error {
+	sampling := list.FollowingSampling()
+	listPO := &RecommendationListPO{
+		TenantID:        valueobject.TenantFromContext(ctx).String(),
+		UserID:          list.ForUserID().Value(),
+		GeneratedAt:     list.GeneratedAt(),
+		FollowingsTotal: sampling.Total,
+		FollowingsUsed:  sampling.Used,
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(listPO).Error; err != nil {
+			return err
+		}
+		recommendations := list.All()
+		if len(recommendations) == 0 {
+			return nil
+		}
+		items := make([]RecommendationListItemPO, 0, len(recommendations))
+		for i, rec := range recommendations {
+			items = append(items, RecommendationListItemPO{
+				ListID:          listPO.ID,
+				Position:        i,
+				TargetUserID:    rec.TargetUserID().Value(),
+				ReasonType:      int(rec.Reason().Type()),
+				RelatedCount:    rec.Reason().RelatedCount(),
+				Score:           rec.Score(),
+				RecentPostCount: rec.RecentPostCount(),
+				CreatedAt:       rec.CreatedAt(),
+				ExpiresAt:       rec.ExpiresAt(),
+			})
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+}
+
+// GetLatestListForUser 实现接口：最新的一行 recommendation_lists + 它的推荐（按保存时的顺序）
+func (r *RecommendationRepositoryImpl) GetLatestListForUser(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (*aggregate.RecommendationList, error) {
+	var listPO RecommendationListPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("user_id = ?", userID.Value()).
+		Order("generated_at DESC").
+		First(&listPO).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var itemPOs []RecommendationListItemPO
+	err = conn(ctx, r.db).
+		Where("list_id = ?", listPO.ID).
+		Order("position").
+		Find(&itemPOs).Error
+	if err != nil {
+		return nil, err
+	}
+	return toRecommendationList(userID, listPO, itemPOs), nil
+}
+
+// ListUserLists 实现接口：用户的所有列表（当前租户，按生成时间倒序）和它们的推荐
+func (r *RecommendationRepositoryImpl) ListUserLists(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]*aggregate.RecommendationList, error) {
+	var listPOs []RecommendationListPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("user_id = ?", userID.Value()).
+		Order("generated_at DESC").
+		Find(&listPOs).Error
+	if err != nil || len(listPOs) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(listPOs))
+	for _, po := range listPOs {
+		ids = append(ids, po.ID)
+	}
+	var itemPOs []RecommendationListItemPO
+	err = conn(ctx, r.db).
+		Where("list_id IN ?", ids).
+		Order("list_id, position").
+		Find(&itemPOs).Error
+	if err != nil {
+		return nil, err
+	}
+	itemsByList := make(map[int64][]RecommendationListItemPO, len(listPOs))
+	for _, po := range itemPOs {
+		itemsByList[po.ListID] = append(itemsByList[po.ListID], po)
+	}
+
+	lists := make([]*aggregate.RecommendationList, 0, len(listPOs))
+	for _, po := range listPOs {
+		lists = append(lists, toRecommendationList(userID, po, itemsByList[po.ID]))
+	}
+	return lists, nil
+}
+
+// DeleteUserLists 实现接口：在一个事务中删除用户（当前租户）所有列表的推荐和列表本身
+func (r *RecommendationRepositoryImpl) DeleteUserLists(ctx context.Context, userID valueobject.UserID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var ids []int64
+		err := tx.Model(&RecommendationListPO{}).Scopes(tenantScope(ctx)).
+			Where("user_id = ?", userID.Value()).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := tx.Where("list_id IN ?", ids).Delete(&RecommendationListItemPO{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&RecommendationListPO{}).Error
+	})
+}
+
+// toRecommendationList 辅助函数：一行 recommendation_lists + 它的推荐（已按 position 排序）→ 聚合
+func toRecommendationList(userID valueobject.UserID, listPO RecommendationListPO, itemPOs []RecommendationListItemPO) *aggregate.RecommendationList {
+	recommendations := make([]*aggregate.UserRecommendation, 0, len(itemPOs))
+	for _, po := range itemPOs {
+		targetUserID, err := valueobject.NewUserID(po.TargetUserID)
+		if err != nil {
+			continue // 忽略非法的ID
+		}
+		reason := valueobject.RestoreRecommendationReason(valueobject.ReasonType(po.ReasonType), po.RelatedCount)
+		recommendations = append(recommendations, aggregate.RestoreUserRecommendation(
+			targetUserID, reason, po.Score, po.RecentPostCount, po.CreatedAt, po.ExpiresAt,
+		))
+	}
+	return aggregate.RestoreRecommendationList(userID, recommendations, listPO.GeneratedAt, aggregate.FollowingSampling{
+		Total: listPO.FollowingsTotal,
+		Used:  listPO.FollowingsUsed,
+	})
+}
+
+// RemoveTarget 实现接口：删除用户所有列表中这个人的推荐
+//
+// 其他推荐的 position 不重新编号：读取时只按 position 排序，中间有空位不影响顺序。
+func (r *RecommendationRepositoryImpl) RemoveTarget(ctx context.Context, userID, targetUserID valueobject.UserID) error {
+	lists := conn(ctx, r.db).Model(&RecommendationListPO{}).Scopes(tenantScope(ctx)).
+		Select("id").
+		Where("user_id = ?", userID.Value())
+	return conn(ctx, r.db).
+		Where("list_id IN (?) AND target_user_id = ?", lists, targetUserID.Value()).
+		Delete(&RecommendationListItemPO{}).Error
+}
+
+// DeleteExpired 实现接口：分批删除过期的列表（所有租户）
+//
+// 每批先查出一批列表ID，再在一个事务中删除它们的推荐和列表本身
+func (r *RecommendationRepositoryImpl) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		var ids []int64
+		err := conn(ctx, r.db).
+			Model(&RecommendationListPO{}).
+			Where("generated_at < ?", cutoff).
+			Limit(purgeBatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		var deleted int64
+		err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("list_id IN ?", ids).Delete(&RecommendationListItemPO{}).Error; err != nil {
+				return err
+			}
+			result := tx.Where("id IN ?", ids).Delete(&RecommendationListPO{})
+			deleted = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if len(ids) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// OldestListTime 实现接口：查询最早的生成时间
+func (r *RecommendationRepositoryImpl) OldestListTime(ctx context.Context) (time.Time, bool, error) {
+	var pos []RecommendationListPO
+	err := conn(ctx, r.db).
+		Select("generated_at").
+		Order("generated_at ASC").
+		Limit(1).
+		Find(&pos).Error
+	if err != nil || len(pos) == 0 {
+		return time.Time{}, false, err
+	}
+	return pos[0].GeneratedAt, true, nil
+}
+
+// RecommendationListPO 推荐列表持久化对象（每次生成一行）
+type RecommendationListPO struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID        string    `gorm:"type:varchar(32);default:'default';not null;index:idx_user_generated,priority:1"`
+	UserID          int64     `gorm:"not null;index:idx_user_generated,priority:2"`
+	GeneratedAt     time.Time `gorm:"not null;index:idx_user_generated,priority:3;index:idx_generated_at"` // 按保留期删除时按 generated_at 查找
+	FollowingsTotal int       `gorm:"default:0;not null"`
+	FollowingsUsed  int       `gorm:"default:0;not null"`
+}
+
+// TableName 指定表名
+func (RecommendationListPO) TableName() string {
+	return "recommendation_lists"
+}
+
+// RecommendationListItemPO 推荐列表中的一条推荐
+type RecommendationListItemPO struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	ListID          int64     `gorm:"not null;index:idx_list_position,priority:1"`
+	Position        int       `gorm:"not null;index:idx_list_position,priority:2"` // 保存时的顺序
+	TargetUserID    int64     `gorm:"not null"`
+	ReasonType      int       `gorm:"type:smallint;default:0;not null"`
+	RelatedCount    int       `gorm:"default:0;not null"`
+	Score           int       `gorm:"not null"`
+	RecentPostCount int       `gorm:"default:0;not null"`
+	CreatedAt       time.Time `gorm:"not null"`
+	ExpiresAt       time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (RecommendationListItemPO) TableName() string {
+	return "recommendation_list_items"
+}
@@ -0,0 +1,166 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// RecommendationStatsRepositoryImpl 推荐效果统计仓储实现（曝光表 LEFT JOIN 关注表）
+type RecommendationStatsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecommendationStatsRepository 构造函数
+//
+// 聚合查询会扫描一段时间内的全部曝光，生产环境应该传入只读副本的连接。
+func NewRecommendationStatsRepository(db *gorm.DB) repository.RecommendationStatsRepository {
+	return &RecommendationStatsRepositoryImpl{db: db}
+}
+
+// dailyStatsRow 聚合查询的一行结果
+type dailyStatsRow struct {
+	Day         time.Time
+	Surface     string
+	ReasonType  int
+	Impressions int64
+	Conversions int64
+}
+
+// DailyStats 实现接口：按天、场景、理由类型聚合曝光和转化
+//
+// 转化：同一个 viewer 在曝光之后的归因窗口内关注了被推荐的用户（关注仍然有效）。
+// 一次曝光之后有多条关注记录时只算一次（COUNT DISTINCT 曝光 id）。
+func (r *RecommendationStatsRepositoryImpl) DailyStats(
+	ctx context.Context,
+	from, to time.Time,
+	conversionWindow time.Duration,
+) ([]*repository.DailyRecommendationStats, error) {
+	tenant := valueobject.TenantFromContext(ctx).String()
+
+	var rows []dailyStatsRow
+	err := conn(ctx, r.db).
+		Table("recommendation_impressions AS i").
+		Select(`DATE(i.shown_at) AS day, i.surface, i.reason_type,
+			COUNT(DISTINCT i.id) AS impressions,
+			COUNT(DISTINCT CASE WHEN f.id IS NOT NULL THEN i.id END) AS conversions`).
+		Joins(`LEFT JOIN follows AS f ON f.tenant_id = i.tenant_id
+			AND f.follower_id = i.viewer_id AND f.following_id = i.target_user_id
+			AND f.status = 'active'
+			AND f.created_at >= i.shown_at AND f.created_at < i.shown_at + INTERVAL ? SECOND`,
+			int64(conversionWindow.Seconds())).
+		Where("i.tenant_id = ? AND i.shown_at >= ? AND i.shown_at < ?", tenant, from, to).
+		Group("day, i.surface, i.reason_type").
+		Order("day ASC, i.surface ASC, i.reason_type ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*repository.DailyRecommendationStats, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &repository.DailyRecommendationStats{
+			Day:         row.Day,
+			Surface:     row.Surface,
+			ReasonType:  valueobject.ReasonType(row.ReasonType),
+			Impressions: row.Impressions,
+			Conversions: row.Conversions,
+		})
+	}
+	return result, nil
+}
+
+// reasonCountRow 按理由类型聚合的一行结果
+type reasonCountRow struct {
+	ReasonType int
+	Total      int64
+	Direct     int64
+}
+
+// ReasonStats 实现接口：曝光表、转化表各聚合一次，按理由类型合并
+//
+// 两张表都按 shown_at 过滤（转化表冗余了曝光时间），不需要 JOIN。
+func (r *RecommendationStatsRepositoryImpl) ReasonStats(
+	ctx context.Context,
+	from, to time.Time,
+) ([]*repository.ReasonConversionStats, error) {
+	var impressions []reasonCountRow
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Table("recommendation_impressions").
+		Select("reason_type, COUNT(*) AS total").
+		Where("shown_at >= ? AND shown_at < ?", from, to).
+		Group("reason_type").
+		Order("reason_type ASC").
+		Scan(&impressions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var conversions []reasonCountRow
+	err = conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Table("recommendation_conversions").
+		Select("reason_type, COUNT(*) AS total, SUM(CASE WHEN direct THEN 1 ELSE 0 END) AS direct").
+		Where("shown_at >= ? AND shown_at < ?", from, to).
+		Group("reason_type").
+		Scan(&conversions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*repository.ReasonConversionStats, 0, len(impressions))
+	byReason := make(map[int]*repository.ReasonConversionStats, len(impressions))
+	for _, row := range impressions {
+		stats := &repository.ReasonConversionStats{
+			ReasonType:  valueobject.ReasonType(row.ReasonType),
+			Impressions: row.Total,
+		}
+		byReason[row.ReasonType] = stats
+		result = append(result, stats)
+	}
+	for _, row := range conversions {
+		stats, ok := byReason[row.ReasonType]
+		if !ok {
+			continue // 曝光已经按保留期删除，转化没有分母，不计入
+		}
+		stats.Conversions = row.Total
+		stats.DirectConversions = row.Direct
+	}
+	return result, nil
+}
+
+// InterleavingOutcome 实现接口：先按交错列表（viewer + 曝光时间）统计两边的转化，再汇总胜负
+//
+// 曝光表 LEFT JOIN 转化表（按推荐ID），走 idx_experiment_shown。
+func (r *RecommendationStatsRepositoryImpl) InterleavingOutcome(
+	ctx context.Context,
+	experiment string,
+	from, to time.Time,
+) (*repository.InterleavingOutcome, error) {
+	sessions := conn(ctx, r.db).
+		Table("recommendation_impressions AS i").
+		Select(`i.viewer_id, i.shown_at,
+			SUM(CASE WHEN i.ranker = 'a' AND c.id IS NOT NULL THEN 1 ELSE 0 END) AS conversions_a,
+			SUM(CASE WHEN i.ranker = 'b' AND c.id IS NOT NULL THEN 1 ELSE 0 END) AS conversions_b`).
+		Joins(`LEFT JOIN recommendation_conversions AS c ON c.tenant_id = i.tenant_id
+			AND c.recommendation_id = i.recommendation_id`).
+		Where("i.tenant_id = ? AND i.experiment = ? AND i.shown_at >= ? AND i.shown_at < ?",
+			valueobject.TenantFromContext(ctx).String(), experiment, from, to).
+		Group("i.viewer_id, i.shown_at")
+
+	var outcome repository.InterleavingOutcome
+	err := conn(ctx, r.db).
+		Table("(?) AS s", sessions).
+		Select(`COUNT(*) AS sessions,
+			COALESCE(SUM(CASE WHEN s.conversions_a > s.conversions_b THEN 1 ELSE 0 END), 0) AS wins_a,
+			COALESCE(SUM(CASE WHEN s.conversions_b > s.conversions_a THEN 1 ELSE 0 END), 0) AS wins_b,
+			COALESCE(SUM(CASE WHEN s.conversions_a = s.conversions_b AND s.conversions_a > 0 THEN 1 ELSE 0 END), 0) AS ties`).
+		Scan(&outcome).Error
+	if err != nil {
+		return nil, err
+	}
+	return &outcome, nil
+}
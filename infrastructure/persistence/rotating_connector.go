@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync/atomic"
+)
+
+// RotatingConnector 可以换用新账号的数据库连接器
+//
+// 为什么需要？
+// Vault 的动态数据库账号有租约，续租失败后会换一份新账号，旧账号在租约到期后被吊销。
+// sql.Open 的 DSN 创建之后不能修改，连接池之后新建的连接仍然使用旧账号，租约到期后全部失败。
+//
+// 用法：sql.OpenDB(connector) 创建连接池；凭据轮换时调用 Rotate，
+// 之后新建的连接使用新账号（已经建立的连接不受影响，随 ConnMaxLifetime 逐渐替换）。
+type RotatingConnector struct {
+	driver  driver.Driver
+	opener  driver.DriverContext
+	current atomic.Pointer[driver.Connector]
+}
+
+// NewRotatingConnector 构造函数（driverName 是已经注册的驱动名，如 "mysql"）
+func NewRotatingConnector(driverName, dsn string) (*RotatingConnector, error) {
+	// sql.Open 不建立连接，只用来取得注册的驱动
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	_ = db.Close()
+	opener, ok := drv.(driver.DriverContext)
+	if !ok {
+		return nil, fmt.Errorf("persistence: driver %q does not support connectors", driverName)
+	}
+	c := &RotatingConnector{driver: drv, opener: opener}
+	if err := c.Rotate(dsn); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Rotate 换用新的 DSN（新账号），之后新建的连接使用它
+func (c *RotatingConnector) Rotate(dsn string) error {
+	connector, err := c.opener.OpenConnector(dsn)
+	if err != nil {
+		return err
+	}
+	c.current.Store(&connector)
+	return nil
+}
+
+// Connect 实现 driver.Connector：使用当前的 DSN 建立连接
+func (c *RotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return (*c.current.Load()).Connect(ctx)
+}
+
+// Driver 实现 driver.Connector
+func (c *RotatingConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// dsnDriver 测试用驱动：记录每个新连接使用的 DSN，不连接真正的数据库
+type dsnDriver struct {
+	mu   sync.Mutex
+	dsns []string
+}
+
+func (d *dsnDriver) Open(dsn string) (driver.Conn, error) {
+	return nil, errors.New("use OpenConnector")
+}
+
+func (d *dsnDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	return dsnConnector{driver: d, dsn: dsn}, nil
+}
+
+type dsnConnector struct {
+	driver *dsnDriver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.dsns = append(c.driver.dsns, c.dsn)
+	return dsnConn{}, nil
+}
+
+func (c dsnConnector) Driver() driver.Driver { return c.driver }
+
+type dsnConn struct{}
+
+func (dsnConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (dsnConn) Close() error                              { return nil }
+func (dsnConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func TestRotatingConnector_NewConnectionsUseRotatedDSN(t *testing.T) {
+	drv := &dsnDriver{}
+	sql.Register("rotating-connector-test", drv)
+
+	connector, err := NewRotatingConnector("rotating-connector-test", "v-rec-1:p1@tcp(db)/rec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxIdleConns(0) // 每次都新建连接
+
+	ctx := context.Background()
+	connect := func() {
+		t.Helper()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.Close()
+	}
+	connect()
+	if err := connector.Rotate("v-rec-2:p2@tcp(db)/rec"); err != nil {
+		t.Fatal(err)
+	}
+	connect()
+
+	want := []string{"v-rec-1:p1@tcp(db)/rec", "v-rec-2:p2@tcp(db)/rec"}
+	if len(drv.dsns) != len(want) || drv.dsns[0] != want[0] || drv.dsns[1] != want[1] {
+		t.Fatalf("dsns = %v, want %v", drv.dsns, want)
+	}
+}
@@ -2,10 +2,14 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"service/domain/entity"
 	"service/domain/repository"
 	"service/domain/valueobject"
 )
@@ -74,8 +78,9 @@ func (r *SocialGraphRepositoryImpl) GetFollowings(
 ) ([]valueobject.UserID, error) {
 
 	var follows []FollowPO
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
 		Where("follower_id = ? AND status = ?", userID.Value(), "active").
+		Order("created_at DESC"). // 最近关注的在前（关注列表采样依赖这个顺序）
 		Find(&follows).Error
 
 	if err != nil {
@@ -103,7 +108,7 @@ func (r *SocialGraphRepositoryImpl) GetRecentFollowings(
 	since := time.Now().AddDate(0, 0, -days)
 
 	var follows []FollowPO
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
 		Where("follower_id = ? AND status = ? AND created_at >= ?",
 			userID.Value(), "active", since).
 		Find(&follows).Error
@@ -122,6 +127,166 @@ func (r *SocialGraphRepositoryImpl) GetRecentFollowings(
 	return result, nil
 }
 
+// recentFollowingsBatchSize GetRecentFollowingsBatch 每条 SQL 最多查询的用户数（IN 列表太长时拆成多条）
+const recentFollowingsBatchSize = 1000
+
+// GetRecentFollowingsBatch 实现接口：批量查询（WHERE follower_id IN (...)）
+//
+// 和 GetRecentFollowings 的条件相同，一条 SQL 查询一批用户（走 idx_tenant_follower），
+// 再按 follower_id 分组；超过 recentFollowingsBatchSize 个用户时分批查询。
+func (r *SocialGraphRepositoryImpl) GetRecentFollowingsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID][]valueobject.UserID, error) {
+	result := make(map[valueobject.UserID][]valueobject.UserID)
+	since := time.Now().AddDate(0, 0, -days)
+
+	for start := 0; start < len(userIDs); start += recentFollowingsBatchSize {
+		batch := userIDs[start:min(start+recentFollowingsBatchSize, len(userIDs))]
+		ids := make([]int64, 0, len(batch))
+		for _, userID := range batch {
+			ids = append(ids, userID.Value())
+		}
+
+		var follows []FollowPO
+		err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Select("follower_id", "following_id").
+			Where("follower_id IN ? AND status = ? AND created_at >= ?", ids, "active", since).
+			Find(&follows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, follow := range follows {
+			followerID, err := valueobject.NewUserID(follow.FollowerID)
+			if err != nil {
+				continue
+			}
+			followingID, err := valueobject.NewUserID(follow.FollowingID)
+			if err != nil {
+				continue
+			}
+			result[followerID] = append(result[followerID], followingID)
+		}
+	}
+	return result, nil
+}
+
+// CountFollowings 实现接口：获取用户关注的人数
+func (r *SocialGraphRepositoryImpl) CountFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (int, error) {
+
+	var count int64
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Model(&FollowPO{}).
+		Where("follower_id = ? AND status = ?", userID.Value(), "active").
+		Count(&count).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// ScanFollowings 实现接口：分页遍历用户关注的人
+//
+// 为什么用游标（id < lastID）而不是 OFFSET？
+// OFFSET 分页越往后越慢（数据库要先跳过前面所有行），
+// 游标分页每一页都走索引直接定位，几万条关注也能稳定遍历。
+//
+// 顺序：按 id 倒序（自增 id 与关注时间一致，最近关注的在前）
+func (r *SocialGraphRepositoryImpl) ScanFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+
+	var lastID int64
+	for {
+		query := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Where("follower_id = ? AND status = ?", userID.Value(), "active")
+		if lastID > 0 {
+			query = query.Where("id < ?", lastID)
+		}
+
+		var follows []FollowPO
+		err := query.Order("id DESC").Limit(pageSize).Find(&follows).Error
+		if err != nil {
+			return err
+		}
+		if len(follows) == 0 {
+			return nil
+		}
+
+		// 转换 PO -> 领域对象
+		page := make([]valueobject.UserID, 0, len(follows))
+		for _, follow := range follows {
+			domainID, _ := valueobject.NewUserID(follow.FollowingID)
+			page = append(page, domainID)
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if len(follows) < pageSize {
+			return nil
+		}
+		lastID = follows[len(follows)-1].ID
+	}
+}
+
+// NewFollowerRepository 构造函数：反向关注关系仓储（和 SocialGraphRepositoryImpl 共用 follows 表）
+func NewFollowerRepository(db *gorm.DB) repository.FollowerRepository {
+	return &SocialGraphRepositoryImpl{db: db}
+}
+
+// ScanFollowers 实现 repository.FollowerRepository：分页遍历关注了 userID 的人
+//
+// 和 ScanFollowings 相同的游标分页，走 following_id 索引。
+func (r *SocialGraphRepositoryImpl) ScanFollowers(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+
+	var lastID int64
+	for {
+		query := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Where("following_id = ? AND status = ?", userID.Value(), "active")
+		if lastID > 0 {
+			query = query.Where("id < ?", lastID)
+		}
+
+		var follows []FollowPO
+		if err := query.Order("id DESC").Limit(pageSize).Find(&follows).Error; err != nil {
+			return err
+		}
+		if len(follows) == 0 {
+			return nil
+		}
+
+		page := make([]valueobject.UserID, 0, len(follows))
+		for _, follow := range follows {
+			domainID, _ := valueobject.NewUserID(follow.FollowerID)
+			page = append(page, domainID)
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if len(follows) < pageSize {
+			return nil
+		}
+		lastID = follows[len(follows)-1].ID
+	}
+}
+
 // IsFollowing 实现接口：检查关注关系
 func (r *SocialGraphRepositoryImpl) IsFollowing(
 	ctx context.Context,
@@ -129,7 +294,7 @@ func (r *SocialGraphRepositoryImpl) IsFollowing(
 ) (bool, error) {
 
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
 		Model(&FollowPO{}).
 		Where("follower_id = ? AND following_id = ? AND status = ?",
 			followerID.Value(), followingID.Value(), "active").
@@ -142,6 +307,154 @@ func (r *SocialGraphRepositoryImpl) IsFollowing(
 	return count > 0, nil
 }
 
+// GetMutualFollows 实现接口：获取互相关注的人
+//
+// follows 表自连接：我关注了 TA（f），TA 也关注了我（b）。
+// 自连接时 tenantScope 的 tenant_id 有歧义，两边的租户条件直接写在查询里。
+func (r *SocialGraphRepositoryImpl) GetMutualFollows(
+	ctx context.Context,
+	userID valueobject.UserID,
+	limit int,
+) ([]valueobject.UserID, error) {
+
+	tenant := valueobject.TenantFromContext(ctx).String()
+	var ids []int64
+	err := conn(ctx, r.db).
+		Table("follows AS f").
+		Joins("JOIN follows AS b ON b.tenant_id = f.tenant_id AND b.follower_id = f.following_id AND b.following_id = f.follower_id AND b.status = ?", "active").
+		Where("f.tenant_id = ? AND f.follower_id = ? AND f.status = ?", tenant, userID.Value(), "active").
+		Order("f.id DESC"). // 最近关注的在前
+		Limit(limit).
+		Pluck("f.following_id", &ids).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		domainID, _ := valueobject.NewUserID(id)
+		result = append(result, domainID)
+	}
+	return result, nil
+}
+
+// twoHopRow ExpandTwoHop 查询结果的一行（一个候选）
+type twoHopRow struct {
+	CandidateID int64
+	ReferrerIDs string // JSON 数组，如 [2,3]
+	Referrers   int
+}
+
+// ExpandTwoHop 实现 repository.TwoHopExpander：一条 SQL 完成二度扩展
+//
+// follows 表自连接：我关注了 TA（f），TA 最近关注了候选（r）；
+// 按候选分组，JSON_ARRAYAGG 收集信号来源，COUNT 统计信号来源数量（MySQL 5.7.22 及以上）。
+// 为什么不用 GROUP_CONCAT？结果受 group_concat_max_len（默认 1024 字节）限制，热门候选的信号来源会被截断。
+//
+// 关注的人多的用户连接的行数也多，推荐生成器只对不需要采样、分页遍历的关注列表使用这个查询。
+func (r *SocialGraphRepositoryImpl) ExpandTwoHop(
+	ctx context.Context,
+	userID valueobject.UserID,
+	days int,
+) ([]repository.TwoHopCandidate, error) {
+
+	tenant := valueobject.TenantFromContext(ctx).String()
+	since := time.Now().AddDate(0, 0, -days)
+	var rows []twoHopRow
+	err := conn(ctx, r.db).
+		Table("follows AS f").
+		Select("r.following_id AS candidate_id, JSON_ARRAYAGG(r.follower_id) AS referrer_ids, COUNT(*) AS referrers").
+		Joins("JOIN follows AS r ON r.tenant_id = f.tenant_id AND r.follower_id = f.following_id AND r.status = ? AND r.created_at >= ?", "active", since).
+		Where("f.tenant_id = ? AND f.follower_id = ? AND f.status = ?", tenant, userID.Value(), "active").
+		Group("r.following_id").
+		Order("referrers DESC, candidate_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]repository.TwoHopCandidate, 0, len(rows))
+	for _, row := range rows {
+		candidate, err := valueobject.NewUserID(row.CandidateID)
+		if err != nil {
+			continue
+		}
+		var ids []int64
+		if err := json.Unmarshal([]byte(row.ReferrerIDs), &ids); err != nil {
+			return nil, err
+		}
+		referrers := make([]valueobject.UserID, 0, len(ids))
+		for _, id := range ids {
+			referrer, err := valueobject.NewUserID(id)
+			if err != nil {
+				continue
+			}
+			referrers = append(referrers, referrer)
+		}
+		result = append(result, repository.TwoHopCandidate{Candidate: candidate, Referrers: referrers})
+	}
+	return result, nil
+}
+
+// FindFollow 实现接口：查询一条关注关系（包括已静音、已取关的；在事务中时锁住这一行，见 forUpdate）
+func (r *SocialGraphRepositoryImpl) FindFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (*entity.Follow, error) {
+
+	var po FollowPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx), forUpdate(ctx)).
+		Where("follower_id = ? AND following_id = ?", followerID.Value(), followingID.Value()).
+		Take(&po).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换 PO -> 领域对象（created_at 是最近一次关注的时间）
+	return entity.RestoreFollow(followerID, followingID, entity.FollowStatus(po.Status), po.CreatedAt, po.UpdatedAt), nil
+}
+
+// SaveFollow 实现接口：保存关注关系（不存在时创建，存在时更新状态和时间）
+//
+// 重新关注时 created_at 更新为新的关注时间：GetRecentFollowings 按 created_at 判断"最近关注"。
+// 一条 upsert 完成（唯一索引 uk_tenant_follower_following）：
+// 两个请求同时关注同一个人时，不会插入两条关注关系。
+func (r *SocialGraphRepositoryImpl) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	po := FollowPO{
+		TenantID:    valueobject.TenantFromContext(ctx).String(),
+		FollowerID:  follow.FollowerID().Value(),
+		FollowingID: follow.FollowingID().Value(),
+		Status:      string(follow.Status()),
+		CreatedAt:   follow.FollowedAt(),
+		UpdatedAt:   follow.UpdatedAt(),
+	}
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "follower_id"}, {Name: "following_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "created_at", "updated_at"}),
+		}).
+		Create(&po).Error
+}
+
+// RemoveFollow 实现接口：取关（软删除，只更新状态和时间）
+//
+// 一条 UPDATE 完成，不需要先查询：
+// 已经取关的记录不更新（取关时间保持第一次取关），晚于 removedAt 的变化（乱序）也不覆盖。
+func (r *SocialGraphRepositoryImpl) RemoveFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+	removedAt time.Time,
+) error {
+	return conn(ctx, r.db).Model(&FollowPO{}).Scopes(tenantScope(ctx)).
+		Where("follower_id = ? AND following_id = ? AND status <> ? AND updated_at <= ?",
+			followerID.Value(), followingID.Value(), string(entity.FollowRemoved), removedAt).
+		Updates(map[string]any{"status": string(entity.FollowRemoved), "updated_at": removedAt}).Error
+}
+
 // FollowPO 持久化对象（PO - Persistent Object）
 //
 // 为什么需要 PO？为什么不直接用领域对象？
@@ -190,9 +503,10 @@ func (r *SocialGraphRepositoryImpl) IsFollowing(
 // 但领域层只关心核心的关注关系，不需要这些技术字段。
 type FollowPO struct {
 	ID          int64     `gorm:"primaryKey;autoIncrement"`
-	FollowerID  int64     `gorm:"index:idx_follower;not null"`
-	FollowingID int64     `gorm:"index:idx_following;not null"`
-	Status      string    `gorm:"type:varchar(20);default:'active'"`
+	TenantID    string    `gorm:"type:varchar(32);default:'default';index:idx_tenant_follower,priority:1;uniqueIndex:uk_tenant_follower_following,priority:1;not null"`
+	FollowerID  int64     `gorm:"index:idx_follower;index:idx_tenant_follower,priority:2;uniqueIndex:uk_tenant_follower_following,priority:2;not null"`
+	FollowingID int64     `gorm:"index:idx_following;uniqueIndex:uk_tenant_follower_following,priority:3;not null"`
+	Status      string    `gorm:"type:varchar(20);default:'active'"` // entity.FollowStatus：active / muted / removed
 	CreatedAt   time.Time `gorm:"index:idx_created_at;not null"`
 	UpdatedAt   time.Time
 }
@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// StrategyBanditRepositoryImpl 策略权重 bandit 仓储实现
+type StrategyBanditRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStrategyBanditRepository 构造函数
+func NewStrategyBanditRepository(db *gorm.DB) repository.StrategyBanditRepository {
+	return &StrategyBanditRepositoryImpl{db: db}
+}
+
+// AddArmStats 实现接口：累加统计
+//
+// 使用 INSERT ... ON DUPLICATE KEY UPDATE pulls = pulls + ?（唯一索引 tenant_id + surface + arm）：
+// 多个实例同时写入时也不会丢失增量
+func (r *StrategyBanditRepositoryImpl) AddArmStats(
+	ctx context.Context,
+	surface string,
+	deltas []*repository.StrategyArmStats,
+) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	tenant := valueobject.TenantFromContext(ctx).String()
+	now := time.Now()
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, delta := range deltas {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tenant_id"}, {Name: "surface"}, {Name: "arm"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"pulls":      gorm.Expr("pulls + ?", delta.Pulls),
+					"rewards":    gorm.Expr("rewards + ?", delta.Rewards),
+					"updated_at": now,
+				}),
+			}).Create(&StrategyArmPO{
+				TenantID:  tenant,
+				Surface:   surface,
+				Arm:       delta.Arm,
+				Pulls:     delta.Pulls,
+				Rewards:   delta.Rewards,
+				UpdatedAt: now,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListArmStats 实现接口：一个展示场景上所有策略的累计统计
+func (r *StrategyBanditRepositoryImpl) ListArmStats(
+	ctx context.Context,
+	surface string,
+) ([]*repository.StrategyArmStats, error) {
+	var pos []StrategyArmPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("surface = ?", surface).
+		Order("arm").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]*repository.StrategyArmStats, 0, len(pos))
+	for _, po := range pos {
+		stats = append(stats, &repository.StrategyArmStats{Arm: po.Arm, Pulls: po.Pulls, Rewards: po.Rewards})
+	}
+	return stats, nil
+}
+
+// SaveOverride 实现接口：保存人工覆盖（为 nil 时删除）
+func (r *StrategyBanditRepositoryImpl) SaveOverride(
+	ctx context.Context,
+	surface string,
+	override *repository.StrategyWeightOverride,
+) error {
+	if override == nil {
+		return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+			Where("surface = ?", surface).
+			Delete(&StrategyWeightOverridePO{}).Error
+	}
+	weights, err := json.Marshal(override.Weights)
+	if err != nil {
+		return err
+	}
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "surface"}},
+			DoUpdates: clause.AssignmentColumns([]string{"disabled", "weights", "updated_by", "updated_at"}),
+		}).
+		Create(&StrategyWeightOverridePO{
+			TenantID:  valueobject.TenantFromContext(ctx).String(),
+			Surface:   surface,
+			Disabled:  override.Disabled,
+			Weights:   string(weights),
+			UpdatedBy: override.UpdatedBy,
+			UpdatedAt: override.UpdatedAt,
+		}).Error
+}
+
+// FindOverride 实现接口：展示场景的人工覆盖
+func (r *StrategyBanditRepositoryImpl) FindOverride(
+	ctx context.Context,
+	surface string,
+) (*repository.StrategyWeightOverride, error) {
+	var po StrategyWeightOverridePO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("surface = ?", surface).
+		Take(&po).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	override := &repository.StrategyWeightOverride{
+		Disabled:  po.Disabled,
+		UpdatedBy: po.UpdatedBy,
+		UpdatedAt: po.UpdatedAt,
+	}
+	if po.Weights != "" {
+		if err := json.Unmarshal([]byte(po.Weights), &override.Weights); err != nil {
+			return nil, err
+		}
+	}
+	return override, nil
+}
+
+// StrategyArmPO 策略臂统计持久化对象（每个租户、场景、策略一行）
+type StrategyArmPO struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID  string    `gorm:"type:varchar(32);default:'default';not null;uniqueIndex:uk_tenant_surface_arm,priority:1"`
+	Surface   string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_tenant_surface_arm,priority:2"`
+	Arm       string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_tenant_surface_arm,priority:3"`
+	Pulls     int64     `gorm:"not null;default:0"`
+	Rewards   int64     `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (StrategyArmPO) TableName() string {
+	return "recommendation_strategy_arms"
+}
+
+// StrategyWeightOverridePO 策略权重人工覆盖持久化对象（每个租户、场景一行）
+type StrategyWeightOverridePO struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	TenantID  string    `gorm:"type:varchar(32);default:'default';not null;uniqueIndex:uk_tenant_surface,priority:1"`
+	Surface   string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_tenant_surface,priority:2"`
+	Disabled  bool      `gorm:"not null;default:false"`
+	Weights   string    `gorm:"type:text"` // JSON：推荐理由类型 → 权重
+	UpdatedBy string    `gorm:"type:varchar(64)"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (StrategyWeightOverridePO) TableName() string {
+	return "recommendation_strategy_weight_overrides"
+}
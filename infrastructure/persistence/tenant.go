@@ -0,0 +1,24 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"service/domain/valueobject"
+)
+
+// tenantScope 查询条件：只访问 ctx 中租户的数据
+//
+// 用法：conn(ctx, r.db).Scopes(tenantScope(ctx)).Where(...)
+//
+// 各表的 tenant_id 列默认值为 'default'：
+// 多租户之前写入的数据不需要迁移，自动属于默认租户。
+// 按保留期清理（DeleteImpressionsBefore 等）是全局的运维任务，不加租户条件。
+// 审计日志属于管理后台（跨租户的运维操作），也不区分租户。
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	tenant := valueobject.TenantFromContext(ctx).String()
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id = ?", tenant)
+	}
+}
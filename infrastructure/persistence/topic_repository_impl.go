@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// TopicRepositoryImpl 话题关注仓储实现
+type TopicRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTopicRepository 构造函数
+func NewTopicRepository(db *gorm.DB) repository.TopicRepository {
+	return &TopicRepositoryImpl{db: db}
+}
+
+// GetFollowedTopics 实现接口：用户关注的话题
+func (r *TopicRepositoryImpl) GetFollowedTopics(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.Topic, error) {
+	var pos []TopicFollowPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("user_id", "topic").
+		Where("user_id = ?", userID.Value()).
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return toTopics(pos)[userID.Value()], nil
+}
+
+// GetFollowedTopicsBatch 实现接口：批量查询（WHERE user_id IN (...)）
+func (r *TopicRepositoryImpl) GetFollowedTopicsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID][]valueobject.Topic, error) {
+	if len(userIDs) == 0 {
+		return map[valueobject.UserID][]valueobject.Topic{}, nil
+	}
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.Value())
+	}
+
+	var pos []TopicFollowPO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("user_id", "topic").
+		Where("user_id IN ?", ids).
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[valueobject.UserID][]valueobject.Topic)
+	for id, topics := range toTopics(pos) {
+		userID, _ := valueobject.NewUserID(id)
+		result[userID] = topics
+	}
+	return result, nil
+}
+
+// toTopics 辅助函数：转换 PO -> 值对象，按用户分组（跳过不合法的旧数据）
+func toTopics(pos []TopicFollowPO) map[int64][]valueobject.Topic {
+	result := make(map[int64][]valueobject.Topic)
+	for _, po := range pos {
+		topic, err := valueobject.NewTopic(po.Topic)
+		if err != nil {
+			continue
+		}
+		result[po.UserID] = append(result[po.UserID], topic)
+	}
+	return result
+}
+
+// TopicFollowPO 话题关注持久化对象（由内容服务写入，推荐服务只读）
+//
+// 主键 (tenant_id, user_id, topic)；topic 保存规范化后的名称（小写、不含 #）
+type TopicFollowPO struct {
+	TenantID  string    `gorm:"primaryKey;type:varchar(32);default:'default'"`
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Topic     string    `gorm:"primaryKey;type:varchar(64)"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (TopicFollowPO) TableName() string {
+	return "topic_follows"
+}
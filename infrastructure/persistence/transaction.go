@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// txKey ctx 中保存当前事务的 key
+type txKey struct{}
+
+// GormTransactionManager 事务边界的 GORM 实现（实现 service.TransactionManager）
+//
+// 事务保存在 ctx 中：仓储通过 conn(ctx, r.db) 取得连接，
+// ctx 中有事务时使用事务，没有时使用连接池（和以前一样，每条语句各自提交）。
+//
+// 只有连接同一个数据库的仓储能在一个事务中：
+// Redis、内存实现不受事务控制，事务回滚时它们的写入不会撤销。
+type GormTransactionManager struct {
+	db *gorm.DB
+}
+
+// NewGormTransactionManager 构造函数
+func NewGormTransactionManager(db *gorm.DB) *GormTransactionManager {
+	return &GormTransactionManager{db: db}
+}
+
+// WithinTransaction 实现 TransactionManager（ctx 中已经有事务时直接执行 fn，不开启嵌套事务）
+func (m *GormTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn 仓储使用的连接：ctx 中有事务（WithinTransaction）时使用事务，否则使用 db
+//
+// 用法：conn(ctx, r.db).Scopes(tenantScope(ctx)).Where(...)
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// forUpdate 查询作用域：ctx 中有事务时加行锁（SELECT ... FOR UPDATE），没有事务时不加
+//
+// 用于"先查询、再按状态修改"的读改写：锁住的行在事务提交前不会被并发的事务修改。
+// 行不存在时锁不住，并发插入由唯一索引兜底。
+func forUpdate(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if _, ok := ctx.Value(txKey{}).(*gorm.DB); !ok {
+			return db
+		}
+		return db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+}
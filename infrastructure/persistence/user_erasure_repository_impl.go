@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// UserErasureRepositoryImpl 用户数据删除记录仓储实现
+type UserErasureRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUserErasureRepository 构造函数
+func NewUserErasureRepository(db *gorm.DB) repository.UserErasureRepository {
+	return &UserErasureRepositoryImpl{db: db}
+}
+
+// MarkErased 实现接口：写入删除标记
+//
+// 使用 ON CONFLICT DO NOTHING：删除事件重复投递时保留第一次的删除时间
+func (r *UserErasureRepositoryImpl) MarkErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+	erasedAt time.Time,
+) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&UserErasurePO{TenantID: valueobject.TenantFromContext(ctx).String(), UserID: userID.Value(), ErasedAt: erasedAt}).Error
+}
+
+// IsErased 实现接口：查询删除标记
+func (r *UserErasureRepositoryImpl) IsErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (bool, error) {
+	var po UserErasurePO
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Select("user_id").
+		Where("user_id = ?", userID.Value()).
+		Take(&po).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ClearErased 实现接口：删除删除标记
+func (r *UserErasureRepositoryImpl) ClearErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	return conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("user_id = ?", userID.Value()).
+		Delete(&UserErasurePO{}).Error
+}
+
+// UserErasurePO 用户数据删除记录持久化对象
+//
+// 主键 (tenant_id, user_id)：不同租户的同一个用户ID 是不同的用户
+type UserErasurePO struct {
+	TenantID string    `gorm:"primaryKey;type:varchar(32);default:'default'"`
+	UserID   int64     `gorm:"primaryKey;autoIncrement:false"`
+	ErasedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (UserErasurePO) TableName() string {
+	return "recommendation_user_erasures"
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// MemoryActiveUsersRepository 内存实现：最近活跃的用户
+//
+// 每个用户只记录最近一次活跃的时间，预计算任务按它挑选最近活跃的用户。
+// 不区分租户（本地开发只有默认租户），租户隔离见 Redis 实现。
+type MemoryActiveUsersRepository struct {
+	mu         sync.RWMutex
+	lastActive map[valueobject.UserID]time.Time
+}
+
+func NewMemoryActiveUsersRepository() repository.ActiveUsersRepository {
+	return &MemoryActiveUsersRepository{
+		lastActive: make(map[valueobject.UserID]time.Time),
+	}
+}
+
+func (r *MemoryActiveUsersRepository) TouchUser(ctx context.Context, userID valueobject.UserID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if at.After(r.lastActive[userID]) {
+		r.lastActive[userID] = at
+	}
+	return nil
+}
+
+func (r *MemoryActiveUsersRepository) ListActiveUsers(ctx context.Context, since time.Time, limit int) ([]valueobject.UserID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]valueobject.UserID, 0, len(r.lastActive))
+	for userID, at := range r.lastActive {
+		if !at.Before(since) {
+			users = append(users, userID)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return r.lastActive[users[i]].After(r.lastActive[users[j]])
+	})
+	if limit >= 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *MemoryActiveUsersRepository) DeleteUser(ctx context.Context, userID valueobject.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.lastActive, userID)
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"service/domain/entity"
+	"service/domain/repository"
+)
+
+// MemoryAuditLogRepository 内存实现：审计日志仓储
+//
+// 管理操作的审计条目（修改权重、编辑精选推荐包等）按追加顺序保存，只追加不修改。
+type MemoryAuditLogRepository struct {
+	mu      sync.RWMutex
+	entries []*entity.AuditEntry // 按追加顺序
+}
+
+func NewMemoryAuditLogRepository() repository.AuditLogRepository {
+	return &MemoryAuditLogRepository{}
+}
+
+func (r *MemoryAuditLogRepository) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *MemoryAuditLogRepository) Query(
+	ctx context.Context,
+	query repository.AuditLogQuery,
+) ([]*entity.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.AuditEntry, 0)
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		switch {
+		case query.Actor != "" && entry.Actor() != query.Actor,
+			query.Action != "" && entry.Action() != query.Action,
+			query.Target != "" && entry.Target() != query.Target,
+			!query.Since.IsZero() && entry.OccurredAt().Before(query.Since),
+			!query.Until.IsZero() && !entry.OccurredAt().Before(query.Until):
+			continue
+		}
+		result = append(result, entry)
+		if query.Limit > 0 && len(result) == query.Limit {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// MemoryConversionRepository 内存实现：归因转化记录仓储
+//
+// 每次曝光只保存第一条归因到它的转化，之后的转化被忽略。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryConversionRepository struct {
+	mu          sync.RWMutex
+	conversions map[valueobject.RecommendationID]*entity.Conversion // 曝光 → 转化
+}
+
+func NewMemoryConversionRepository() repository.ConversionRepository {
+	return &MemoryConversionRepository{
+		conversions: make(map[valueobject.RecommendationID]*entity.Conversion),
+	}
+}
+
+func (r *MemoryConversionRepository) RecordConversion(
+	ctx context.Context,
+	conversion *entity.Conversion,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.conversions[conversion.RecommendationID()]; !ok {
+		r.conversions[conversion.RecommendationID()] = conversion
+	}
+	return nil
+}
+
+func (r *MemoryConversionRepository) DeleteUserConversions(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for recommendationID, conversion := range r.conversions {
+		if conversion.ViewerID() == userID || conversion.TargetUserID() == userID {
+			delete(r.conversions, recommendationID)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryConversionRepository) ListUserConversions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.Conversion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.Conversion, 0)
+	for _, conversion := range r.conversions {
+		if conversion.ViewerID() == viewerID {
+			result = append(result, conversion)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ConvertedAt().Before(result[j].ConvertedAt())
+	})
+	return result, nil
+}
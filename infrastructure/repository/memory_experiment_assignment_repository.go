@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// assignmentKey 一个用户在一个实验中的分组
+type assignmentKey struct {
+	experiment string
+	userID     valueobject.UserID
+}
+
+// MemoryExperimentAssignmentRepository 内存实现：A/B 实验分组仓储
+//
+// 每个（实验, 用户）只保存第一次分配到的分组，进程重启后用户会被重新分组。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryExperimentAssignmentRepository struct {
+	mu          sync.RWMutex
+	assignments map[assignmentKey]valueobject.ExperimentAssignment
+}
+
+func NewMemoryExperimentAssignmentRepository() repository.ExperimentAssignmentRepository {
+	return &MemoryExperimentAssignmentRepository{
+		assignments: make(map[assignmentKey]valueobject.ExperimentAssignment),
+	}
+}
+
+func (r *MemoryExperimentAssignmentRepository) FindAssignment(
+	ctx context.Context,
+	experiment string,
+	userID valueobject.UserID,
+) (valueobject.ExperimentAssignment, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignment, ok := r.assignments[assignmentKey{experiment: experiment, userID: userID}]
+	return assignment, ok, nil
+}
+
+func (r *MemoryExperimentAssignmentRepository) SaveAssignment(
+	ctx context.Context,
+	userID valueobject.UserID,
+	assignment valueobject.ExperimentAssignment,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := assignmentKey{experiment: assignment.Experiment(), userID: userID}
+	if _, ok := r.assignments[key]; !ok {
+		r.assignments[key] = assignment
+	}
+	return nil
+}
+
+func (r *MemoryExperimentAssignmentRepository) ListUserAssignments(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.ExperimentAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]valueobject.ExperimentAssignment, 0)
+	for key, assignment := range r.assignments {
+		if key.userID == userID {
+			result = append(result, assignment)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Experiment() < result[j].Experiment()
+	})
+	return result, nil
+}
+
+func (r *MemoryExperimentAssignmentRepository) DeleteUserAssignments(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.assignments {
+		if key.userID == userID {
+			delete(r.assignments, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// feedbackKey 一次曝光的一种反馈
+type feedbackKey struct {
+	recommendationID valueobject.RecommendationID
+	action           valueobject.FeedbackAction
+}
+
+// MemoryFeedbackRepository 内存实现：推荐反馈记录仓储
+//
+// 每次曝光的每种反馈只保存第一次上报的那一条，重复上报被忽略（RecordFeedback 返回 false）。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryFeedbackRepository struct {
+	mu       sync.RWMutex
+	feedback map[feedbackKey]*entity.RecommendationFeedback
+}
+
+func NewMemoryFeedbackRepository() repository.FeedbackRepository {
+	return &MemoryFeedbackRepository{
+		feedback: make(map[feedbackKey]*entity.RecommendationFeedback),
+	}
+}
+
+func (r *MemoryFeedbackRepository) RecordFeedback(
+	ctx context.Context,
+	feedback *entity.RecommendationFeedback,
+) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := feedbackKey{recommendationID: feedback.RecommendationID(), action: feedback.Action()}
+	if _, ok := r.feedback[key]; ok {
+		return false, nil
+	}
+	r.feedback[key] = feedback
+	return true, nil
+}
+
+func (r *MemoryFeedbackRepository) DeleteUserFeedback(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, feedback := range r.feedback {
+		if feedback.ViewerID() == userID || feedback.TargetUserID() == userID {
+			delete(r.feedback, key)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryFeedbackRepository) ListUserFeedback(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.RecommendationFeedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.RecommendationFeedback, 0)
+	for _, feedback := range r.feedback {
+		if feedback.ViewerID() == viewerID {
+			result = append(result, feedback)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].At().Before(result[j].At())
+	})
+	return result, nil
+}
+
+func (r *MemoryFeedbackRepository) DeleteFeedbackBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, feedback := range r.feedback {
+		if feedback.At().Before(cutoff) {
+			delete(r.feedback, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *MemoryFeedbackRepository) OldestFeedbackTime(ctx context.Context) (time.Time, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var oldest time.Time
+	for _, feedback := range r.feedback {
+		if oldest.IsZero() || feedback.At().Before(oldest) {
+			oldest = feedback.At()
+		}
+	}
+	return oldest, !oldest.IsZero(), nil
+}
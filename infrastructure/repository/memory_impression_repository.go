@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// MemoryImpressionRepository 内存实现：曝光记录仓储
+//
+// 按浏览者保存曝光记录（推荐了谁、理由、场景、展示时间），只追加不合并。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+// 和其他 Mock 不同，它会真实记录写入的数据，
+// 这样曝光配额等依赖曝光历史的功能在本地也能看到效果。
+type MemoryImpressionRepository struct {
+	mu          sync.RWMutex
+	impressions map[valueobject.UserID][]*entity.Impression // viewerID → 曝光记录
+}
+
+func NewMemoryImpressionRepository() repository.ImpressionRepository {
+	return &MemoryImpressionRepository{
+		impressions: make(map[valueobject.UserID][]*entity.Impression),
+	}
+}
+
+func (r *MemoryImpressionRepository) RecordImpressions(
+	ctx context.Context,
+	impressions []*entity.Impression,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, impression := range impressions {
+		viewerID := impression.ViewerID()
+		r.impressions[viewerID] = append(r.impressions[viewerID], impression)
+	}
+	return nil
+}
+
+func (r *MemoryImpressionRepository) GetImpressedTargets(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	since time.Time,
+) ([]valueobject.UserID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[valueobject.UserID]bool)
+	result := make([]valueobject.UserID, 0)
+	for _, impression := range r.impressions[viewerID] {
+		if impression.ShownAt().Before(since) || seen[impression.TargetUserID()] {
+			continue
+		}
+		seen[impression.TargetUserID()] = true
+		result = append(result, impression.TargetUserID())
+	}
+	return result, nil
+}
+
+func (r *MemoryImpressionRepository) ListUserImpressions(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+) ([]*entity.Impression, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.Impression, len(r.impressions[viewerID]))
+	copy(result, r.impressions[viewerID])
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ShownAt().Before(result[j].ShownAt())
+	})
+	return result, nil
+}
+
+func (r *MemoryImpressionRepository) ListImpressionHistory(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	from, to time.Time,
+	after *repository.ImpressionCursor,
+	limit int,
+) ([]*entity.Impression, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.Impression, 0)
+	for _, impression := range r.impressions[viewerID] {
+		if impression.ShownAt().Before(from) || !impression.ShownAt().Before(to) {
+			continue
+		}
+		if after != nil && !after.Precedes(impression) {
+			continue
+		}
+		result = append(result, impression)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return repository.CursorOf(result[i]).Precedes(result[j])
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *MemoryImpressionRepository) FindLatestImpression(
+	ctx context.Context,
+	viewerID, targetUserID valueobject.UserID,
+	from, to time.Time,
+) (*entity.Impression, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entity.Impression
+	for _, impression := range r.impressions[viewerID] {
+		if impression.TargetUserID() != targetUserID ||
+			impression.ShownAt().Before(from) || impression.ShownAt().After(to) {
+			continue
+		}
+		if latest == nil || !impression.ShownAt().Before(latest.ShownAt()) {
+			latest = impression
+		}
+	}
+	return latest, latest != nil, nil
+}
+
+func (r *MemoryImpressionRepository) FindImpression(
+	ctx context.Context,
+	viewerID valueobject.UserID,
+	recommendationID valueobject.RecommendationID,
+) (*entity.Impression, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, impression := range r.impressions[viewerID] {
+		if impression.RecommendationID() == recommendationID {
+			return impression, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (r *MemoryImpressionRepository) DeleteUserImpressions(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.impressions, userID)
+	for viewerID, impressions := range r.impressions {
+		kept := impressions[:0]
+		for _, impression := range impressions {
+			if impression.TargetUserID() != userID {
+				kept = append(kept, impression)
+			}
+		}
+		r.impressions[viewerID] = kept
+	}
+	return nil
+}
+
+func (r *MemoryImpressionRepository) DeleteImpressionsBefore(
+	ctx context.Context,
+	cutoff time.Time,
+) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for viewerID, impressions := range r.impressions {
+		kept := impressions[:0]
+		for _, impression := range impressions {
+			if impression.ShownAt().Before(cutoff) {
+				deleted++
+				continue
+			}
+			kept = append(kept, impression)
+		}
+		r.impressions[viewerID] = kept
+	}
+	return deleted, nil
+}
+
+func (r *MemoryImpressionRepository) OldestImpressionTime(ctx context.Context) (time.Time, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var oldest time.Time
+	found := false
+	for _, impressions := range r.impressions {
+		for _, impression := range impressions {
+			if !found || impression.ShownAt().Before(oldest) {
+				oldest = impression.ShownAt()
+				found = true
+			}
+		}
+	}
+	return oldest, found, nil
+}
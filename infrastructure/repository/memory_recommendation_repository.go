@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"service/domain/aggregate"
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// MemoryRecommendationRepository 内存实现：推荐列表仓储
+//
+// 按用户保存推荐列表的副本：存取都会复制，调用方修改拿到的列表不影响保存的那一份。
+// 每个用户只保留最新的一份列表（GetLatestListForUser 也只需要最新的一份）。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryRecommendationRepository struct {
+	mu    sync.RWMutex
+	lists map[valueobject.UserID]*aggregate.RecommendationList
+}
+
+func NewMemoryRecommendationRepository() repository.RecommendationRepository {
+	return &MemoryRecommendationRepository{
+		lists: make(map[valueobject.UserID]*aggregate.RecommendationList),
+	}
+}
+
+func (r *MemoryRecommendationRepository) SaveList(ctx context.Context, list *aggregate.RecommendationList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.lists[list.ForUserID()]; ok && existing.GeneratedAt().After(list.GeneratedAt()) {
+		return nil // 已经有更新的一份
+	}
+	r.lists[list.ForUserID()] = cloneRecommendationList(list)
+	return nil
+}
+
+func (r *MemoryRecommendationRepository) GetLatestListForUser(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (*aggregate.RecommendationList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[userID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRecommendationList(list), nil
+}
+
+func (r *MemoryRecommendationRepository) RemoveTarget(ctx context.Context, userID, targetUserID valueobject.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list, ok := r.lists[userID]
+	if !ok {
+		return nil
+	}
+	kept := make([]*aggregate.UserRecommendation, 0, list.Count())
+	for _, rec := range list.All() {
+		if !rec.TargetUserID().Equals(targetUserID) {
+			kept = append(kept, rec)
+		}
+	}
+	r.lists[userID] = aggregate.RestoreRecommendationList(userID, kept, list.GeneratedAt(), list.FollowingSampling())
+	return nil
+}
+
+func (r *MemoryRecommendationRepository) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for userID, list := range r.lists {
+		if list.GeneratedAt().Before(cutoff) {
+			delete(r.lists, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *MemoryRecommendationRepository) ListUserLists(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]*aggregate.RecommendationList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[userID]
+	if !ok {
+		return nil, nil
+	}
+	return []*aggregate.RecommendationList{cloneRecommendationList(list)}, nil
+}
+
+func (r *MemoryRecommendationRepository) DeleteUserLists(ctx context.Context, userID valueobject.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.lists, userID)
+	return nil
+}
+
+func (r *MemoryRecommendationRepository) OldestListTime(ctx context.Context) (time.Time, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var oldest time.Time
+	for _, list := range r.lists {
+		if oldest.IsZero() || list.GeneratedAt().Before(oldest) {
+			oldest = list.GeneratedAt()
+		}
+	}
+	return oldest, !oldest.IsZero(), nil
+}
+
+// cloneRecommendationList 辅助函数：复制推荐列表和其中的推荐
+//
+// 和 MySQL 实现一样，保存的和读出的是两份独立的数据：
+// 调用方会在列表上执行过滤、调整有效期（ExpireIn），不能影响保存的列表。
+func cloneRecommendationList(list *aggregate.RecommendationList) *aggregate.RecommendationList {
+	recommendations := make([]*aggregate.UserRecommendation, 0, list.Count())
+	for _, rec := range list.All() {
+		recommendations = append(recommendations, aggregate.RestoreUserRecommendation(
+			rec.TargetUserID(), rec.Reason(), rec.Score(), rec.RecentPostCount(), rec.CreatedAt(), rec.ExpiresAt(),
+		))
+	}
+	return aggregate.RestoreRecommendationList(list.ForUserID(), recommendations, list.GeneratedAt(), list.FollowingSampling())
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"service/domain/repository"
+)
+
+// MemoryStrategyBanditRepository 内存实现：策略权重 bandit 仓储
+//
+// 按场景保存每种推荐理由的曝光、关注次数（bandit 统计），以及管理后台人工覆盖的权重。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryStrategyBanditRepository struct {
+	mu        sync.RWMutex
+	arms      map[string]map[string]*repository.StrategyArmStats // 场景 → 策略 → 统计
+	overrides map[string]*repository.StrategyWeightOverride
+}
+
+func NewMemoryStrategyBanditRepository() repository.StrategyBanditRepository {
+	return &MemoryStrategyBanditRepository{
+		arms:      make(map[string]map[string]*repository.StrategyArmStats),
+		overrides: make(map[string]*repository.StrategyWeightOverride),
+	}
+}
+
+func (r *MemoryStrategyBanditRepository) AddArmStats(
+	ctx context.Context,
+	surface string,
+	deltas []*repository.StrategyArmStats,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.arms[surface] == nil {
+		r.arms[surface] = make(map[string]*repository.StrategyArmStats)
+	}
+	for _, delta := range deltas {
+		arm, ok := r.arms[surface][delta.Arm]
+		if !ok {
+			arm = &repository.StrategyArmStats{Arm: delta.Arm}
+			r.arms[surface][delta.Arm] = arm
+		}
+		arm.Pulls += delta.Pulls
+		arm.Rewards += delta.Rewards
+	}
+	return nil
+}
+
+func (r *MemoryStrategyBanditRepository) ListArmStats(
+	ctx context.Context,
+	surface string,
+) ([]*repository.StrategyArmStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := make([]*repository.StrategyArmStats, 0, len(r.arms[surface]))
+	for _, arm := range r.arms[surface] {
+		copied := *arm
+		stats = append(stats, &copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Arm < stats[j].Arm })
+	return stats, nil
+}
+
+func (r *MemoryStrategyBanditRepository) SaveOverride(
+	ctx context.Context,
+	surface string,
+	override *repository.StrategyWeightOverride,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if override == nil {
+		delete(r.overrides, surface)
+		return nil
+	}
+	r.overrides[surface] = override
+	return nil
+}
+
+func (r *MemoryStrategyBanditRepository) FindOverride(
+	ctx context.Context,
+	surface string,
+) (*repository.StrategyWeightOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.overrides[surface], nil
+}
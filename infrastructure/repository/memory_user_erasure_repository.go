@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"service/domain/repository"
+	"service/domain/valueobject"
+)
+
+// MemoryUserErasureRepository 内存实现：用户数据删除记录仓储
+//
+// 记录删除了数据的用户和第一次删除的时间，这些用户请求推荐时直接返回空列表。
+// 不区分租户（本地开发只有默认租户），租户隔离见 MySQL 实现。
+type MemoryUserErasureRepository struct {
+	mu     sync.RWMutex
+	erased map[valueobject.UserID]time.Time // userID → 删除时间
+}
+
+func NewMemoryUserErasureRepository() repository.UserErasureRepository {
+	return &MemoryUserErasureRepository{
+		erased: make(map[valueobject.UserID]time.Time),
+	}
+}
+
+func (r *MemoryUserErasureRepository) MarkErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+	erasedAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.erased[userID]; !ok {
+		r.erased[userID] = erasedAt
+	}
+	return nil
+}
+
+func (r *MemoryUserErasureRepository) IsErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.erased[userID]
+	return ok, nil
+}
+
+func (r *MemoryUserErasureRepository) ClearErased(
+	ctx context.Context,
+	userID valueobject.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.erased, userID)
+	return nil
+}
@@ -2,9 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
-	"service/application/service"
 	"service/domain/entity"
 	"service/domain/repository"
 	"service/domain/valueobject"
@@ -14,7 +15,11 @@ import (
 //
 // 用于演示和测试，返回模拟数据。
 // 在实际项目中，这里会是真实的数据库实现。
-type MockSocialGraphRepository struct{}
+//
+// 写侧（SaveFollow）保存在内存中，只影响 FindFollow；关注列表等查询仍然返回模拟数据。
+type MockSocialGraphRepository struct {
+	follows sync.Map // "followerID:followingID" → *entity.Follow
+}
 
 func NewMockSocialGraphRepository() repository.SocialGraphRepository {
 	return &MockSocialGraphRepository{}
@@ -42,6 +47,37 @@ func (r *MockSocialGraphRepository) GetRecentFollowings(
 	return []valueobject.UserID{user5, user6}, nil
 }
 
+func (r *MockSocialGraphRepository) GetRecentFollowingsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID][]valueobject.UserID, error) {
+	result := make(map[valueobject.UserID][]valueobject.UserID, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID], _ = r.GetRecentFollowings(ctx, userID, days)
+	}
+	return result, nil
+}
+
+func (r *MockSocialGraphRepository) CountFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+) (int, error) {
+	followings, _ := r.GetFollowings(ctx, userID)
+	return len(followings), nil
+}
+
+func (r *MockSocialGraphRepository) ScanFollowings(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+	// 模拟数据量很小，一页返回全部
+	followings, _ := r.GetFollowings(ctx, userID)
+	return fn(followings)
+}
+
 func (r *MockSocialGraphRepository) IsFollowing(
 	ctx context.Context,
 	followerID, followingID valueobject.UserID,
@@ -50,6 +86,124 @@ func (r *MockSocialGraphRepository) IsFollowing(
 	return true, nil
 }
 
+func (r *MockSocialGraphRepository) GetMutualFollows(
+	ctx context.Context,
+	userID valueobject.UserID,
+	limit int,
+) ([]valueobject.UserID, error) {
+	// 返回模拟数据：和 user2, user3 互相关注
+	user2, _ := valueobject.NewUserID(2)
+	user3, _ := valueobject.NewUserID(3)
+	mutuals := []valueobject.UserID{user2, user3}
+	if limit >= 0 && len(mutuals) > limit {
+		mutuals = mutuals[:limit]
+	}
+	return mutuals, nil
+}
+
+func (r *MockSocialGraphRepository) FindFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+) (*entity.Follow, error) {
+	follow, ok := r.follows.Load(mockFollowKey(followerID, followingID))
+	if !ok {
+		return nil, nil
+	}
+	return follow.(*entity.Follow), nil
+}
+
+func (r *MockSocialGraphRepository) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	r.follows.Store(mockFollowKey(follow.FollowerID(), follow.FollowingID()), follow)
+	return nil
+}
+
+func (r *MockSocialGraphRepository) RemoveFollow(
+	ctx context.Context,
+	followerID, followingID valueobject.UserID,
+	removedAt time.Time,
+) error {
+	if follow, ok := r.follows.Load(mockFollowKey(followerID, followingID)); ok {
+		_, _ = follow.(*entity.Follow).Remove(removedAt)
+	}
+	return nil
+}
+
+// mockFollowKey 辅助函数：关注关系的标识
+func mockFollowKey(followerID, followingID valueobject.UserID) string {
+	return fmt.Sprintf("%d:%d", followerID.Value(), followingID.Value())
+}
+
+// MockFollowerRepository Mock 实现：反向关注关系仓储
+type MockFollowerRepository struct{}
+
+func NewMockFollowerRepository() repository.FollowerRepository {
+	return &MockFollowerRepository{}
+}
+
+func (r *MockFollowerRepository) ScanFollowers(
+	ctx context.Context,
+	userID valueobject.UserID,
+	pageSize int,
+	fn func(page []valueobject.UserID) error,
+) error {
+	// 返回模拟数据：user1, user7 关注了这个用户（一页返回全部）
+	user1, _ := valueobject.NewUserID(1)
+	user7, _ := valueobject.NewUserID(7)
+	return fn([]valueobject.UserID{user1, user7})
+}
+
+// MockInteractionRepository Mock 实现：互动记录仓储
+type MockInteractionRepository struct{}
+
+func NewMockInteractionRepository() repository.InteractionRepository {
+	return &MockInteractionRepository{}
+}
+
+func (r *MockInteractionRepository) GetLastInteractions(
+	ctx context.Context,
+	userID valueobject.UserID,
+	targets []valueobject.UserID,
+) (map[valueobject.UserID]time.Time, error) {
+	// 返回模拟数据：昨天和第一个人互动过，和其他人都没有互动过
+	result := make(map[valueobject.UserID]time.Time)
+	if len(targets) > 0 {
+		result[targets[0]] = time.Now().Add(-24 * time.Hour)
+	}
+	return result, nil
+}
+
+func (r *MockInteractionRepository) CountReceivedEngagement(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID]valueobject.CreatorEngagement, error) {
+	// 返回模拟数据：每个人每天收到 20 个点赞、2 条评论
+	result := make(map[valueobject.UserID]valueobject.CreatorEngagement, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = valueobject.CreatorEngagement{Likes: 20 * days, Comments: 2 * days}
+	}
+	return result, nil
+}
+
+// MockBlockRepository Mock 实现：屏蔽关系仓储（没有任何屏蔽关系）
+type MockBlockRepository struct{}
+
+func NewMockBlockRepository() repository.BlockRepository {
+	return &MockBlockRepository{}
+}
+
+func (r *MockBlockRepository) GetBlockedUsers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	return nil, nil
+}
+
+func (r *MockBlockRepository) GetBlockers(ctx context.Context, userID valueobject.UserID) ([]valueobject.UserID, error) {
+	return nil, nil
+}
+
+func (r *MockBlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID valueobject.UserID) (bool, error) {
+	return false, nil
+}
+
 // MockContentRepository Mock 实现：内容仓储
 type MockContentRepository struct{}
 
@@ -66,6 +220,18 @@ func (r *MockContentRepository) CountRecentPosts(
 	return 5, nil
 }
 
+func (r *MockContentRepository) CountRecentPostsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	days int,
+) (map[valueobject.UserID]int, error) {
+	result := make(map[valueobject.UserID]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID], _ = r.CountRecentPosts(ctx, userID, days)
+	}
+	return result, nil
+}
+
 func (r *MockContentRepository) GetRecentPosts(
 	ctx context.Context,
 	userID valueobject.UserID,
@@ -82,43 +248,152 @@ func (r *MockContentRepository) GetRecentPosts(
 		entity.NewPost(postID2, userID, "这是第二篇帖子", now.Add(-2*time.Hour)),
 		entity.NewPost(postID3, userID, "这是第三篇帖子", now.Add(-3*time.Hour)),
 	}
+	for _, post := range posts {
+		post.ApplyModeration(valueobject.ModerationApproved)
+	}
 
 	return posts, nil
 }
 
-// MockUserRPCClient Mock 实现：用户 RPC 客户端
-type MockUserRPCClient struct{}
+func (r *MockContentRepository) GetRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+	limit int,
+) (map[valueobject.UserID][]*entity.Post, error) {
+	result := make(map[valueobject.UserID][]*entity.Post, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID], _ = r.GetRecentPosts(ctx, userID, limit)
+	}
+	return result, nil
+}
 
-func NewMockUserRPCClient() service.UserRPCClient {
-	return &MockUserRPCClient{}
+// MockTrustRepository Mock 实现：风控分级仓储
+type MockTrustRepository struct{}
+
+func NewMockTrustRepository() repository.TrustRepository {
+	return &MockTrustRepository{}
 }
 
-func (c *MockUserRPCClient) GetUserInfo(
+func (r *MockTrustRepository) GetRiskLevels(
 	ctx context.Context,
-	userID int64,
-) (*service.UserInfo, error) {
-	// 返回模拟数据
-	return &service.UserInfo{
-		UserID:   userID,
-		Username: "user_" + string(rune(userID)),
-		Avatar:   "https://example.com/avatar.jpg",
-		Bio:      "这是用户简介",
-	}, nil
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID]valueobject.RiskLevel, error) {
+	// 返回模拟数据：所有用户都是低风险
+	result := make(map[valueobject.UserID]valueobject.RiskLevel, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = valueobject.RiskLow
+	}
+	return result, nil
 }
 
-func (c *MockUserRPCClient) GetUserInfoBatch(
+// MockTopicRepository Mock 实现：话题关注仓储
+type MockTopicRepository struct{}
+
+func NewMockTopicRepository() repository.TopicRepository {
+	return &MockTopicRepository{}
+}
+
+func (r *MockTopicRepository) GetFollowedTopics(
 	ctx context.Context,
-	userIDs []int64,
-) ([]*service.UserInfo, error) {
-	// 返回模拟数据
-	result := make([]*service.UserInfo, 0, len(userIDs))
+	userID valueobject.UserID,
+) ([]valueobject.Topic, error) {
+	// 返回模拟数据：ID 为偶数的用户关注 #golang，所有用户都关注 #ddd
+	names := []string{"ddd"}
+	if userID.Value()%2 == 0 {
+		names = append(names, "golang")
+	}
+	topics := make([]valueobject.Topic, 0, len(names))
+	for _, name := range names {
+		topic, _ := valueobject.NewTopic(name)
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+func (r *MockTopicRepository) GetFollowedTopicsBatch(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID][]valueobject.Topic, error) {
+	result := make(map[valueobject.UserID][]valueobject.Topic, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID], _ = r.GetFollowedTopics(ctx, userID)
+	}
+	return result, nil
+}
+
+// MockGroupMembershipRepository Mock 实现：小组成员关系仓储
+type MockGroupMembershipRepository struct{}
+
+func NewMockGroupMembershipRepository() repository.GroupMembershipRepository {
+	return &MockGroupMembershipRepository{}
+}
+
+func (r *MockGroupMembershipRepository) GetUserGroups(
+	ctx context.Context,
+	userID valueobject.UserID,
+) ([]valueobject.GroupID, error) {
+	// 返回模拟数据：每个用户加入小组 (ID % 3) + 1，ID 为偶数的用户还加入了小组 10
+	ids := []int64{userID.Value()%3 + 1}
+	if userID.Value()%2 == 0 {
+		ids = append(ids, 10)
+	}
+	groups := make([]valueobject.GroupID, 0, len(ids))
+	for _, id := range ids {
+		groupID, _ := valueobject.NewGroupID(id)
+		groups = append(groups, groupID)
+	}
+	return groups, nil
+}
+
+func (r *MockGroupMembershipRepository) GetGroupsOfUsers(
+	ctx context.Context,
+	userIDs []valueobject.UserID,
+) (map[valueobject.UserID][]valueobject.GroupID, error) {
+	result := make(map[valueobject.UserID][]valueobject.GroupID, len(userIDs))
 	for _, userID := range userIDs {
-		result = append(result, &service.UserInfo{
-			UserID:   userID,
-			Username: "user_" + string(rune(userID)),
-			Avatar:   "https://example.com/avatar.jpg",
-			Bio:      "这是用户简介",
-		})
+		result[userID], _ = r.GetUserGroups(ctx, userID)
 	}
 	return result, nil
 }
+
+func (r *MockGroupMembershipRepository) CountMembers(
+	ctx context.Context,
+	groupIDs []valueobject.GroupID,
+) (map[valueobject.GroupID]int, error) {
+	// 返回模拟数据：每个小组 50 个成员
+	result := make(map[valueobject.GroupID]int, len(groupIDs))
+	for _, groupID := range groupIDs {
+		result[groupID] = 50
+	}
+	return result, nil
+}
+
+// MockPopularUsersRepository Mock 实现：冷启动名单
+type MockPopularUsersRepository struct{}
+
+func NewMockPopularUsersRepository() repository.PopularUsersRepository {
+	return &MockPopularUsersRepository{}
+}
+
+func (r *MockPopularUsersRepository) GetPopularUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	// 返回模拟数据：全站热门 user100 ~ user104
+	return mockUserIDs(limit, 100, 101, 102, 103, 104), nil
+}
+
+func (r *MockPopularUsersRepository) GetCuratedUsers(ctx context.Context, limit int) ([]valueobject.UserID, error) {
+	// 返回模拟数据：编辑精选 user200、user201
+	return mockUserIDs(limit, 200, 201), nil
+}
+
+// mockUserIDs 辅助函数：前 limit 个模拟用户
+func mockUserIDs(limit int, ids ...int64) []valueobject.UserID {
+	if limit >= 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	users := make([]valueobject.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, _ := valueobject.NewUserID(id)
+		users = append(users, userID)
+	}
+	return users
+}
@@ -0,0 +1,358 @@
+// Package resilience 下游调用的治理（重试预算、对冲、自适应限流）
+package resilience
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"service/application/service"
+)
+
+// ErrThrottled 依赖不健康，本地直接拒绝了这次调用（没有发给下游）
+var ErrThrottled = errors.New("resilience: request throttled by downstream governor")
+
+// 熔断器状态（和依赖状态报告中的取值一致）
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// Attempt 一次调用的类型
+type Attempt int
+
+const (
+	AttemptPrimary Attempt = iota // 首次调用
+	AttemptRetry                  // 重试
+	AttemptHedge                  // 对冲（首次调用还没返回时发出的第二个请求）
+)
+
+// String 监控指标的标签值
+func (a Attempt) String() string {
+	switch a {
+	case AttemptRetry:
+		return "retry"
+	case AttemptHedge:
+		return "hedge"
+	default:
+		return "primary"
+	}
+}
+
+// GovernorConfig 治理参数
+type GovernorConfig struct {
+	// Window 统计窗口（按秒分桶滑动），默认 10 秒
+	Window time.Duration
+	// MinRequests 窗口内调用少于这个数时不做判断（样本太少，成功率没有意义），默认 20
+	MinRequests int
+	// RetryRatio 重试预算：窗口内重试次数最多为首次调用的这个比例，默认 0.1
+	RetryRatio float64
+	// HedgeRatio 对冲预算：窗口内对冲次数最多为首次调用的这个比例，默认 0.05
+	HedgeRatio float64
+	// HealthySuccessRate 成功率低于这个值时不再重试、对冲，默认 0.9
+	HealthySuccessRate float64
+	// ThrottleK 自适应限流系数（Google SRE 的客户端限流）：
+	// 拒绝概率 = max(0, (调用数 - K × 成功数) / (调用数 + 1))；为 0 时不限流
+	ThrottleK float64
+}
+
+// DefaultGovernorConfig 默认参数（开启自适应限流，K = 2：成功率低于 50% 才开始拒绝）
+func DefaultGovernorConfig() GovernorConfig {
+	return GovernorConfig{
+		Window:             10 * time.Second,
+		MinRequests:        20,
+		RetryRatio:         0.1,
+		HedgeRatio:         0.05,
+		HealthySuccessRate: 0.9,
+		ThrottleK:          2,
+	}
+}
+
+// Governor 下游调用治理器（所有下游共用一个，按依赖名分别统计）
+//
+// 为什么需要？
+// 重试、对冲本来是为了应对偶发的失败和长尾延迟，
+// 但下游整体出问题时，每个调用方都在重试、对冲，发给下游的请求量成倍放大，
+// 把一个"变慢"的下游彻底压垮，故障持续得更久（重试风暴）。
+//
+// Governor 按依赖统计最近一段时间的调用结果，据此决定：
+// - 重试预算：重试次数不超过首次调用的 10%，下游不健康（成功率 < 90%）时不重试
+// - 对冲预算：同上，比例更低（5%）
+// - 自适应限流：成功率继续下降时，按概率在本地直接拒绝一部分调用（ErrThrottled），给下游恢复的空间
+//
+// 和熔断器配合：
+// - 熔断器 open：直接拒绝，不发请求
+// - 熔断器 half_open：只放行首次调用（探测），不重试、不对冲
+// - 熔断器 closed：按上面的统计决定
+// 熔断器通过 WithBreakerState 接入（目前下游还没有熔断器，不接入时视为 closed）。
+type Governor struct {
+	cfg      GovernorConfig
+	mu       sync.Mutex
+	deps     map[string]*window
+	breakers map[string]func() string
+	metrics  service.Metrics
+	now      func() time.Time
+	random   func() float64
+}
+
+// GovernorOption 可选配置
+type GovernorOption func(*Governor)
+
+// WithBreakerState 接入依赖的熔断器状态（closed、open、half_open）
+func WithBreakerState(dependency string, state func() string) GovernorOption {
+	return func(g *Governor) {
+		g.breakers[dependency] = state
+	}
+}
+
+// WithGovernorMetrics 注入监控指标（记录每次调用的结果，以及被治理器拒绝的调用、重试、对冲）
+func WithGovernorMetrics(metrics service.Metrics) GovernorOption {
+	return func(g *Governor) {
+		g.metrics = metrics
+	}
+}
+
+// NewGovernor 构造函数（没有设置的参数使用默认值）
+func NewGovernor(cfg GovernorConfig, opts ...GovernorOption) *Governor {
+	defaults := DefaultGovernorConfig()
+	if cfg.Window <= 0 {
+		cfg.Window = defaults.Window
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = defaults.MinRequests
+	}
+	if cfg.RetryRatio <= 0 {
+		cfg.RetryRatio = defaults.RetryRatio
+	}
+	if cfg.HedgeRatio <= 0 {
+		cfg.HedgeRatio = defaults.HedgeRatio
+	}
+	if cfg.HealthySuccessRate <= 0 {
+		cfg.HealthySuccessRate = defaults.HealthySuccessRate
+	}
+	g := &Governor{
+		cfg:      cfg,
+		deps:     make(map[string]*window),
+		breakers: make(map[string]func() string),
+		now:      time.Now,
+		random:   rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Allow 是否放行一次首次调用（熔断器 open 或自适应限流时拒绝）
+//
+// 被拒绝的调用也计入统计（自适应限流的公式要求这样：拒绝越多，下游恢复后放行得越快）。
+func (g *Governor) Allow(dependency string) bool {
+	if g.breakerState(dependency) == BreakerOpen {
+		g.count(dependency, "breaker_open")
+		return false
+	}
+
+	g.mu.Lock()
+	stats := g.sum(dependency)
+	throttle := g.cfg.ThrottleK > 0 && stats.attempts >= g.cfg.MinRequests &&
+		g.random() < (float64(stats.attempts)-g.cfg.ThrottleK*float64(stats.successes))/float64(stats.attempts+1)
+	if throttle {
+		g.bucket(dependency).attempts++
+	}
+	g.mu.Unlock()
+
+	if throttle {
+		g.count(dependency, "throttled")
+	}
+	return !throttle
+}
+
+// AllowRetry 是否允许一次重试（不允许时调用方直接返回上一次的失败）
+func (g *Governor) AllowRetry(dependency string) bool {
+	return g.allowExtra(dependency, AttemptRetry)
+}
+
+// AllowHedge 是否允许一次对冲（不允许时只等首次调用）
+func (g *Governor) AllowHedge(dependency string) bool {
+	return g.allowExtra(dependency, AttemptHedge)
+}
+
+// Record 记录一次调用的结果（首次调用、重试、对冲都要记录）
+//
+// 同时上报到监控（MetricDownstreamRequests），按依赖计算错误率。
+func (g *Governor) Record(dependency string, attempt Attempt, success bool) {
+	if g.metrics != nil {
+		result := "success"
+		if !success {
+			result = "failure"
+		}
+		g.metrics.IncCounter(service.MetricDownstreamRequests, 1, map[string]string{
+			"dependency": dependency,
+			"attempt":    attempt.String(),
+			"result":     result,
+		})
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b := g.bucket(dependency)
+	b.attempts++
+	if success {
+		b.successes++
+	}
+	switch attempt {
+	case AttemptPrimary:
+		b.primaries++
+	case AttemptRetry:
+		b.retries++
+	case AttemptHedge:
+		b.hedges++
+	}
+}
+
+// SuccessRate 依赖最近的成功率（样本不足时返回 1）
+func (g *Governor) SuccessRate(dependency string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.successRate(g.sum(dependency))
+}
+
+// Healthy 依赖是否健康（调用方据此决定是否跳过这个依赖，直接使用备用来源）
+//
+// 不健康：熔断器 open，或者成功率已经低到自适应限流开始拒绝调用（成功率 < 1/K）。
+// 跳过期间依赖没有新的调用，窗口滑过之后样本不足，视为健康，调用方会再试一次。
+func (g *Governor) Healthy(dependency string) bool {
+	if g.breakerState(dependency) == BreakerOpen {
+		return false
+	}
+	if g.cfg.ThrottleK <= 0 {
+		return true
+	}
+	return g.SuccessRate(dependency) >= 1/g.cfg.ThrottleK
+}
+
+// allowExtra 辅助方法：重试、对冲共用的判断
+//
+// 条件：熔断器 closed、依赖健康、预算没有用完
+func (g *Governor) allowExtra(dependency string, attempt Attempt) bool {
+	if g.breakerState(dependency) != BreakerClosed {
+		g.count(dependency, "breaker_not_closed")
+		return false
+	}
+
+	g.mu.Lock()
+	stats := g.sum(dependency)
+	ratio, used := g.cfg.RetryRatio, stats.retries
+	if attempt == AttemptHedge {
+		ratio, used = g.cfg.HedgeRatio, stats.hedges
+	}
+	healthy := g.successRate(stats) >= g.cfg.HealthySuccessRate
+	// 预算至少为 1：调用量很小时也允许偶尔重试
+	withinBudget := float64(used) < max(1, ratio*float64(stats.primaries))
+	g.mu.Unlock()
+
+	switch {
+	case !healthy:
+		g.count(dependency, "unhealthy")
+		return false
+	case !withinBudget:
+		g.count(dependency, "budget_exhausted")
+		return false
+	}
+	return true
+}
+
+// successRate 辅助方法（调用方持有锁）
+func (g *Governor) successRate(stats bucket) float64 {
+	if stats.attempts < g.cfg.MinRequests {
+		return 1
+	}
+	return float64(stats.successes) / float64(stats.attempts)
+}
+
+// breakerState 辅助方法：没有接入熔断器时视为 closed
+func (g *Governor) breakerState(dependency string) string {
+	if state, ok := g.breakers[dependency]; ok {
+		return state()
+	}
+	return BreakerClosed
+}
+
+// count 辅助方法：上报被治理器拒绝的调用
+func (g *Governor) count(dependency, reason string) {
+	if g.metrics == nil {
+		return
+	}
+	g.metrics.IncCounter(service.MetricDownstreamGoverned, 1, map[string]string{
+		"dependency": dependency,
+		"reason":     reason,
+	})
+}
+
+// bucket 辅助方法：当前这一秒的桶（调用方持有锁）
+func (g *Governor) bucket(dependency string) *bucket {
+	w, ok := g.deps[dependency]
+	if !ok {
+		w = newWindow(int(g.cfg.Window / time.Second))
+		g.deps[dependency] = w
+	}
+	return w.current(g.now().Unix())
+}
+
+// sum 辅助方法：窗口内的合计（调用方持有锁）
+func (g *Governor) sum(dependency string) bucket {
+	w, ok := g.deps[dependency]
+	if !ok {
+		return bucket{}
+	}
+	return w.sum(g.now().Unix())
+}
+
+// bucket 一秒内的统计
+type bucket struct {
+	second    int64
+	attempts  int // 所有调用（包括被本地拒绝的）
+	successes int
+	primaries int
+	retries   int
+	hedges    int
+}
+
+// window 按秒分桶的滑动窗口
+type window struct {
+	buckets []bucket
+}
+
+func newWindow(seconds int) *window {
+	if seconds <= 0 {
+		seconds = 1
+	}
+	return &window{buckets: make([]bucket, seconds)}
+}
+
+// current 当前这一秒的桶（过期的桶先清零）
+func (w *window) current(now int64) *bucket {
+	b := &w.buckets[now%int64(len(w.buckets))]
+	if b.second != now {
+		*b = bucket{second: now}
+	}
+	return b
+}
+
+// sum 窗口内所有没有过期的桶的合计
+func (w *window) sum(now int64) bucket {
+	var total bucket
+	for _, b := range w.buckets {
+		if now-b.second >= int64(len(w.buckets)) {
+			continue
+		}
+		total.attempts += b.attempts
+		total.successes += b.successes
+		total.primaries += b.primaries
+		total.retries += b.retries
+		total.hedges += b.hedges
+	}
+	return total
+}
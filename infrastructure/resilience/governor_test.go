@@ -0,0 +1,69 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGovernor_RetryBudget(t *testing.T) {
+	g := NewGovernor(GovernorConfig{MinRequests: 10, RetryRatio: 0.1})
+	now := time.Unix(1000, 0)
+	g.now = func() time.Time { return now }
+
+	// 健康：100 次首次调用，重试预算为 10 次
+	for i := 0; i < 100; i++ {
+		g.Record("trust", AttemptPrimary, true)
+	}
+	for i := 0; i < 10; i++ {
+		if !g.AllowRetry("trust") {
+			t.Fatalf("retry %d denied, want allowed within budget", i)
+		}
+		g.Record("trust", AttemptRetry, true)
+	}
+	if g.AllowRetry("trust") {
+		t.Error("retry allowed after budget exhausted")
+	}
+
+	// 窗口滑过之后预算恢复
+	now = now.Add(20 * time.Second)
+	if !g.AllowRetry("trust") {
+		t.Error("retry denied after window passed")
+	}
+
+	// 不健康：成功率低于 90% 时不重试、不对冲
+	for i := 0; i < 100; i++ {
+		g.Record("trust", AttemptPrimary, i%2 == 0)
+	}
+	if g.AllowRetry("trust") || g.AllowHedge("trust") {
+		t.Error("retry/hedge allowed for unhealthy dependency")
+	}
+}
+
+func TestGovernor_Throttle(t *testing.T) {
+	state := BreakerClosed
+	g := NewGovernor(GovernorConfig{MinRequests: 10, ThrottleK: 2},
+		WithBreakerState("trust", func() string { return state }))
+	g.random = func() float64 { return 0.5 }
+
+	// 全部失败：拒绝概率接近 1
+	for i := 0; i < 100; i++ {
+		g.Record("content", AttemptPrimary, false)
+	}
+	if g.Allow("content") {
+		t.Error("Allow() = true, want throttled")
+	}
+	// 其他依赖不受影响
+	if !g.Allow("trust") {
+		t.Error("Allow(trust) = false, want true")
+	}
+
+	// 熔断器 half_open：只放行首次调用，不重试
+	state = BreakerHalfOpen
+	if !g.Allow("trust") || g.AllowRetry("trust") {
+		t.Error("half_open: want primary allowed and retry denied")
+	}
+	state = BreakerOpen
+	if g.Allow("trust") {
+		t.Error("open: Allow() = true, want false")
+	}
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Provider 密钥读取接口
+//
+// 在依赖初始化时（wire.go 的 Provider 中）读取数据库账号、Redis 密码、签名密钥等，
+// 不再把明文密码直接放在环境变量或配置文件里。
+//
+// name 是密钥的逻辑路径（如 "database/creds/recommendation"），返回键值对（如 username、password）。
+//
+// 动态凭据会轮换：使用方通过 Subscribe 换用新凭据（如数据库连接池换用新账号）。
+//
+// 实现：
+// - VaultProvider：生产环境，从 Vault 读取，动态凭据自动续租
+// - EnvProvider：本地开发，从环境变量读取
+type Provider interface {
+	Get(ctx context.Context, name string) (map[string]string, error)
+
+	// Subscribe 订阅凭据变化：凭据轮换（重新读取到新凭据）时回调，在后台协程中调用
+	Subscribe(name string, fn func(map[string]string))
+}
+
+// EnvProvider 环境变量实现（本地开发使用）
+//
+// 路径 → 环境变量前缀：大写，"/" 和 "-" 换成 "_"。
+// 例如 Get("database/creds/recommendation") 读取所有以
+// DATABASE_CREDS_RECOMMENDATION_ 开头的环境变量，
+// DATABASE_CREDS_RECOMMENDATION_PASSWORD 对应键 "password"。
+type EnvProvider struct {
+	environ func() []string
+}
+
+// NewEnvProvider 构造函数
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{environ: os.Environ}
+}
+
+// Get 实现接口
+func (p *EnvProvider) Get(ctx context.Context, name string) (map[string]string, error) {
+	prefix := envPrefix(name)
+	values := make(map[string]string)
+	for _, kv := range p.environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		values[strings.ToLower(strings.TrimPrefix(key, prefix))] = value
+	}
+	if len(values) == 0 {
+		return nil, ErrSecretNotFound
+	}
+	return values, nil
+}
+
+// Subscribe 实现接口：环境变量在进程运行期间不变，不会回调
+func (p *EnvProvider) Subscribe(name string, fn func(map[string]string)) {}
+
+// NewProvider 按配置选择实现：vaultAddress 不为空时使用 Vault（令牌来自环境变量 VAULT_TOKEN），否则使用环境变量
+func NewProvider(vaultAddress string) Provider {
+	if vaultAddress != "" {
+		return NewVaultProvider(VaultConfig{
+			Address: vaultAddress,
+			Token:   os.Getenv("VAULT_TOKEN"),
+		})
+	}
+	return NewEnvProvider()
+}
+
+// Value 辅助函数：读取密钥中的一个键
+func Value(ctx context.Context, p Provider, name, key string) (string, error) {
+	values, err := p.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// envPrefix 辅助方法：路径 → 环境变量前缀
+func envPrefix(name string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "_", "-", "_").Replace(name)) + "_"
+}
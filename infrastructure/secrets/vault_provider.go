@@ -0,0 +1,217 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig Vault 配置
+type VaultConfig struct {
+	Address string // 如 https://vault.internal:8200
+	Token   string // 访问令牌
+
+	// HTTPClient 可选（如开启 mTLS 的客户端），默认 10 秒超时
+	HTTPClient *http.Client
+}
+
+// VaultProvider Vault 实现
+//
+// 支持两类密钥：
+// - 静态密钥（KV v2 引擎，如签名密钥）：读取一次
+// - 动态凭据（database 引擎，如数据库账号）：带租约（lease），到期前自动续租
+//
+// 续租：
+// 在租约过期前（剩余 1/3 时）续租；续租失败（超过最长租期、租约被吊销）时
+// 重新读取一份新凭据，并通知订阅者（Subscribe）换用新凭据，例如重建数据库连接池。
+//
+// 直接调用 Vault HTTP API，不引入 Vault SDK：只用到读取和续租两个接口。
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	subscribers map[string][]func(map[string]string)
+	renewing    map[string]bool
+	stop        chan struct{}
+	once        sync.Once
+}
+
+// NewVaultProvider 构造函数
+func NewVaultProvider(cfg VaultConfig) *VaultProvider {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &VaultProvider{
+		cfg:         cfg,
+		client:      client,
+		subscribers: make(map[string][]func(map[string]string)),
+		renewing:    make(map[string]bool),
+		stop:        make(chan struct{}),
+	}
+}
+
+// vaultResponse Vault 读取接口的响应
+type vaultResponse struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int            `json:"lease_duration"` // 秒
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+}
+
+// Get 实现接口：读取密钥；带可续租租约的凭据会在后台自动续租
+func (p *VaultProvider) Get(ctx context.Context, name string) (map[string]string, error) {
+	resp, err := p.read(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Renewable && resp.LeaseID != "" {
+		p.startRenewal(name, resp)
+	}
+	return flatten(resp.Data), nil
+}
+
+// Subscribe 实现接口：续租失败、重新读取到新凭据时回调
+func (p *VaultProvider) Subscribe(name string, fn func(map[string]string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers[name] = append(p.subscribers[name], fn)
+}
+
+// Close 停止所有续租
+func (p *VaultProvider) Close() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+// read 辅助方法：GET /v1/{name}
+func (p *VaultProvider) read(ctx context.Context, name string) (*vaultResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp vaultResponse
+	if err := p.do(req, &resp); err != nil {
+		return nil, fmt.Errorf("vault read %s: %w", name, err)
+	}
+	return &resp, nil
+}
+
+// renew 辅助方法：PUT /v1/sys/leases/renew，返回新的租期
+func (p *VaultProvider) renew(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	body, _ := json.Marshal(map[string]any{
+		"lease_id":  leaseID,
+		"increment": int(increment.Seconds()),
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.url("sys/leases/renew"), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	var resp vaultResponse
+	if err := p.do(req, &resp); err != nil {
+		return 0, err
+	}
+	return time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+// startRenewal 辅助方法：每个密钥只启动一个续租循环
+func (p *VaultProvider) startRenewal(name string, resp *vaultResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.renewing[name] {
+		return
+	}
+	p.renewing[name] = true
+	go p.renewLoop(name, resp.LeaseID, time.Duration(resp.LeaseDuration)*time.Second)
+}
+
+// renewLoop 辅助方法：在租约剩余 1/3 时续租，续租失败时重新读取
+func (p *VaultProvider) renewLoop(name, leaseID string, ttl time.Duration) {
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(ttl * 2 / 3):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		newTTL, err := p.renew(ctx, leaseID, ttl)
+		if err == nil && newTTL > 0 {
+			cancel()
+			ttl = newTTL
+			continue
+		}
+
+		// 续租失败或者租期已经不能延长：换一份新凭据
+		resp, err := p.read(ctx, name)
+		cancel()
+		if err != nil {
+			ttl = time.Minute // Vault 暂时不可用，稍后再试（旧凭据在过期前仍可用）
+			continue
+		}
+		leaseID, ttl = resp.LeaseID, time.Duration(resp.LeaseDuration)*time.Second
+		p.notify(name, flatten(resp.Data))
+		if !resp.Renewable || ttl <= 0 {
+			p.mu.Lock()
+			p.renewing[name] = false
+			p.mu.Unlock()
+			return
+		}
+	}
+}
+
+// notify 辅助方法：通知订阅者
+func (p *VaultProvider) notify(name string, values map[string]string) {
+	p.mu.Lock()
+	subscribers := append([]func(map[string]string){}, p.subscribers[name]...)
+	p.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(values)
+	}
+}
+
+// do 辅助方法：发送请求并解析响应
+func (p *VaultProvider) do(req *http.Request, out any) error {
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrSecretNotFound
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// url 辅助方法
+func (p *VaultProvider) url(path string) string {
+	return strings.TrimRight(p.cfg.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+}
+
+// flatten 辅助方法：KV v2 的数据在 data.data 里，其他引擎直接在 data 里
+func flatten(data map[string]any) map[string]string {
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = inner
+		}
+	}
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		} else {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values
+}
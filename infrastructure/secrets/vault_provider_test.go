@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVaultProvider_ReadsKVAndRotatesExpiredLease(t *testing.T) {
+	var reads atomic.Int32
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "t0k3n" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/webhook":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"data": map[string]any{"signing_key": "s3cr3t"}, "metadata": map[string]any{}},
+			})
+		case "/v1/database/creds/recommendation":
+			n := reads.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"lease_id": "lease-" + string(rune('0'+n)), "lease_duration": 1, "renewable": true,
+				"data": map[string]any{"username": "v-rec", "password": "p" + string(rune('0'+n))},
+			})
+		case "/v1/sys/leases/renew":
+			w.WriteHeader(http.StatusBadRequest) // 超过最长租期，不能再续
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+
+	provider := NewVaultProvider(VaultConfig{Address: vault.URL, Token: "t0k3n"})
+	defer provider.Close()
+	ctx := context.Background()
+
+	if key, err := Value(ctx, provider, "secret/data/webhook", "signing_key"); err != nil || key != "s3cr3t" {
+		t.Fatalf("signing_key = %q, %v", key, err)
+	}
+	if _, err := provider.Get(ctx, "secret/data/missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing secret err = %v, want ErrSecretNotFound", err)
+	}
+
+	rotated := make(chan map[string]string, 1)
+	provider.Subscribe("database/creds/recommendation", func(values map[string]string) { rotated <- values })
+	creds, err := provider.Get(ctx, "database/creds/recommendation")
+	if err != nil || creds["password"] != "p1" {
+		t.Fatalf("creds = %v, %v", creds, err)
+	}
+
+	select {
+	case values := <-rotated:
+		if values["password"] != "p2" {
+			t.Errorf("rotated password = %q, want p2", values["password"])
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("credentials were not re-read after renewal failed")
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	p := &EnvProvider{environ: func() []string {
+		return []string{"LOG_REDACTION_HASH_KEY=abc", "REDIS_MAIN_PASSWORD=pw", "PATH=/bin"}
+	}}
+	if v, err := Value(context.Background(), p, "log/redaction", "hash_key"); err != nil || v != "abc" {
+		t.Errorf("hash_key = %q, %v", v, err)
+	}
+	if v, err := Value(context.Background(), p, "redis-main", "password"); err != nil || v != "pw" {
+		t.Errorf("password = %q, %v", v, err)
+	}
+	if _, err := p.Get(context.Background(), "database/creds/recommendation"); err != ErrSecretNotFound {
+		t.Errorf("missing secret err = %v, want ErrSecretNotFound", err)
+	}
+}
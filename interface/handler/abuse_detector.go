@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+)
+
+// ErrCodeRateLimited 限流错误码：调用方因为异常请求模式被暂时封禁
+const ErrCodeRateLimited int32 = 429
+
+// ErrRateLimited 限流错误（和 ErrOverloaded 一样通过 BizStatusError 返回）
+var ErrRateLimited = kerrors.NewBizStatusError(ErrCodeRateLimited, "too many requests")
+
+// 异常请求模式（监控标签 pattern 的取值）
+const (
+	AbusePatternCallerRate  = "caller_rate" // 单个调用方请求过多
+	AbusePatternEnumeration = "enumeration" // 单个调用方查询了过多不同的用户（遍历用户ID）
+	AbusePatternTargetRate  = "target_rate" // 单个用户被查询过多
+	AbusePatternBatchSize   = "batch_size"  // 单次请求的数量异常大
+)
+
+// AbuseDetectorConfig 异常检测配置（阈值为 0 表示不检查）
+type AbuseDetectorConfig struct {
+	Window             time.Duration // 统计窗口
+	MaxCallerRequests  int           // 每个调用方每个窗口最多请求数
+	MaxDistinctTargets int           // 每个调用方每个窗口最多查询多少个不同用户
+	MaxTargetRequests  int           // 每个用户每个窗口最多被查询多少次
+	MaxBatchSize       int           // 单次请求最多要多少条推荐
+	BlockDuration      time.Duration // 发现调用方异常后封禁多久（0：只告警，不封禁）
+}
+
+// AbuseDetector 异常请求检测：发现爬取式的请求模式
+//
+// 为什么需要？
+// 推荐结果（带推荐理由）间接反映了用户的关注关系。
+// 授权（Authorizer）限制了"谁能查谁"，但白名单中的内部服务可以查询任意用户：
+// 如果它被攻破或者有 bug，逐个遍历用户ID 就能拉取整个社交图谱。
+//
+// 检测的模式：
+// - caller_rate：单个调用方请求过多
+// - enumeration：单个调用方在窗口内查询了过多不同的用户
+// - target_rate：单个用户被查询过多（同一个目标被反复拉取）
+// - batch_size：单次请求的数量异常大
+//
+// 发现异常时：
+// - 上报监控 MetricAbuseFlagged（标签：pattern），写一条告警日志
+// - 配置了 BlockDuration 时，封禁调用方一段时间，期间的请求返回 ErrRateLimited
+// - target_rate 不封禁调用方（热门用户被很多人查询是正常的），只告警
+//
+// 实现：
+// 固定窗口计数，窗口结束时清空，内存占用和窗口内的调用方、用户数成正比。
+// 每种异常在一个窗口内只上报一次，避免刷屏。
+// 计数只在单个实例内，多实例部署时阈值按单实例流量配置。
+type AbuseDetector struct {
+	cfg     AbuseDetectorConfig
+	metrics service.Metrics // 可以为 nil
+	logger  service.Logger  // 可以为 nil
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	callers      map[string]*callerStats
+	targets      map[int64]int
+	flagged      map[string]bool      // 本窗口已上报的异常（pattern + key）
+	blockedUntil map[string]time.Time // 调用方 → 封禁截止时间
+	now          func() time.Time
+}
+
+// callerStats 一个调用方在当前窗口内的统计
+type callerStats struct {
+	requests int
+	targets  map[int64]struct{}
+}
+
+// NewAbuseDetector 构造函数
+func NewAbuseDetector(cfg AbuseDetectorConfig, metrics service.Metrics, logger service.Logger) *AbuseDetector {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	return &AbuseDetector{
+		cfg:          cfg,
+		metrics:      metrics,
+		logger:       logger,
+		callers:      make(map[string]*callerStats),
+		targets:      make(map[int64]int),
+		flagged:      make(map[string]bool),
+		blockedUntil: make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+// Check 记录一次请求并检查请求模式
+//
+// 返回：
+// - nil：放行
+// - ErrRateLimited：调用方处于封禁期
+func (d *AbuseDetector) Check(ctx context.Context, principal service.Principal, targetUserID int64, batchSize int) error {
+	caller := callerKey(principal)
+	now := d.now()
+
+	d.mu.Lock()
+	if until, ok := d.blockedUntil[caller]; ok {
+		if now.Before(until) {
+			d.mu.Unlock()
+			return ErrRateLimited
+		}
+		delete(d.blockedUntil, caller)
+	}
+	if now.Sub(d.windowStart) >= d.cfg.Window {
+		d.resetWindow(now)
+	}
+
+	stats, ok := d.callers[caller]
+	if !ok {
+		stats = &callerStats{targets: make(map[int64]struct{})}
+		d.callers[caller] = stats
+	}
+	stats.requests++
+	stats.targets[targetUserID] = struct{}{}
+	d.targets[targetUserID]++
+
+	var patterns []string
+	if exceeds(stats.requests, d.cfg.MaxCallerRequests) {
+		patterns = append(patterns, AbusePatternCallerRate)
+	}
+	if exceeds(len(stats.targets), d.cfg.MaxDistinctTargets) {
+		patterns = append(patterns, AbusePatternEnumeration)
+	}
+	if exceeds(batchSize, d.cfg.MaxBatchSize) {
+		patterns = append(patterns, AbusePatternBatchSize)
+	}
+	targetFlagged := exceeds(d.targets[targetUserID], d.cfg.MaxTargetRequests) &&
+		d.markFlagged(AbusePatternTargetRate+":"+strconv.FormatInt(targetUserID, 10))
+
+	var newlyFlagged []string
+	for _, pattern := range patterns {
+		if d.markFlagged(pattern + ":" + caller) {
+			newlyFlagged = append(newlyFlagged, pattern)
+		}
+	}
+	block := len(patterns) > 0 && d.cfg.BlockDuration > 0
+	if block {
+		d.blockedUntil[caller] = now.Add(d.cfg.BlockDuration)
+	}
+	d.mu.Unlock()
+
+	// 上报在锁外进行
+	for _, pattern := range newlyFlagged {
+		d.report(ctx, pattern, principal, targetUserID, block)
+	}
+	if targetFlagged {
+		d.report(ctx, AbusePatternTargetRate, principal, targetUserID, false)
+	}
+
+	if block {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// resetWindow 辅助方法：开始新的统计窗口（调用方持有锁）
+func (d *AbuseDetector) resetWindow(now time.Time) {
+	d.windowStart = now
+	clear(d.callers)
+	clear(d.targets)
+	clear(d.flagged)
+}
+
+// markFlagged 辅助方法：记录本窗口已上报的异常，返回是否第一次上报（调用方持有锁）
+func (d *AbuseDetector) markFlagged(key string) bool {
+	if d.flagged[key] {
+		return false
+	}
+	d.flagged[key] = true
+	return true
+}
+
+// report 辅助方法：上报监控和告警日志
+func (d *AbuseDetector) report(ctx context.Context, pattern string, principal service.Principal, targetUserID int64, blocked bool) {
+	if d.metrics != nil {
+		d.metrics.IncCounter(service.MetricAbuseFlagged, 1, map[string]string{"pattern": pattern})
+	}
+	if d.logger != nil {
+		d.logger.Log(ctx, service.LogLevelWarn, "abnormal request pattern", map[string]any{
+			"pattern":                 pattern,
+			"caller_service":          principal.Service,
+			service.FieldUserID:       principal.UserID,
+			service.FieldTargetUserID: targetUserID,
+			"blocked":                 blocked,
+		})
+	}
+}
+
+// callerKey 辅助方法：调用方标识（服务名 + 用户ID）
+func callerKey(principal service.Principal) string {
+	return principal.Service + "/" + strconv.FormatInt(principal.UserID, 10)
+}
+
+// exceeds 辅助方法：阈值为 0 表示不检查
+func exceeds(value, limit int) bool {
+	return limit > 0 && value > limit
+}
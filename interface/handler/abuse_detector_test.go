@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/service"
+)
+
+// recordingMetrics 测试用监控：记录计数
+type recordingMetrics struct {
+	counters map[string]int
+}
+
+func (m *recordingMetrics) IncCounter(name string, delta int, labels map[string]string) {
+	m.counters[name+"/"+labels["pattern"]] += delta
+}
+
+func (m *recordingMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {}
+
+func TestAbuseDetector_BlocksEnumeratingCaller(t *testing.T) {
+	metrics := &recordingMetrics{counters: make(map[string]int)}
+	detector := NewAbuseDetector(AbuseDetectorConfig{
+		Window:             time.Minute,
+		MaxDistinctTargets: 3,
+		BlockDuration:      time.Minute,
+	}, metrics, nil)
+	now := time.Unix(1000, 0)
+	detector.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	scraper := service.Principal{Service: "push-pipeline"}
+	for target := int64(1); target <= 3; target++ {
+		if err := detector.Check(ctx, scraper, target, 10); err != nil {
+			t.Fatalf("request %d err = %v, want nil", target, err)
+		}
+	}
+	if err := detector.Check(ctx, scraper, 4, 10); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("4th distinct target err = %v, want ErrRateLimited", err)
+	}
+	if got := metrics.counters[service.MetricAbuseFlagged+"/"+AbusePatternEnumeration]; got != 1 {
+		t.Errorf("enumeration flagged %d times, want 1", got)
+	}
+
+	// 其他调用方不受影响
+	if err := detector.Check(ctx, service.Principal{UserID: 7}, 7, 10); err != nil {
+		t.Errorf("other caller err = %v, want nil", err)
+	}
+
+	// 封禁期结束、进入新窗口后恢复
+	now = now.Add(2 * time.Minute)
+	if err := detector.Check(ctx, scraper, 5, 10); err != nil {
+		t.Errorf("after block err = %v, want nil", err)
+	}
+}
+
+func TestAbuseDetector_HotTargetOnlyAlerts(t *testing.T) {
+	metrics := &recordingMetrics{counters: make(map[string]int)}
+	detector := NewAbuseDetector(AbuseDetectorConfig{
+		MaxTargetRequests: 2,
+		BlockDuration:     time.Minute,
+	}, metrics, nil)
+
+	for userID := int64(1); userID <= 5; userID++ {
+		if err := detector.Check(context.Background(), service.Principal{Service: "bff", UserID: userID}, 42, 10); err != nil {
+			t.Fatalf("hot target request err = %v, want nil", err)
+		}
+	}
+	if got := metrics.counters[service.MetricAbuseFlagged+"/"+AbusePatternTargetRate]; got != 1 {
+		t.Errorf("target_rate flagged %d times, want 1", got)
+	}
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/application/service"
+
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+// AdminHandler 接口层：管理接口 RPC 处理器
+//
+// 和 RecommendationHandler 一样只负责协议适配，
+// 审计记录的写入和查询都在应用层（AuditLogService）。
+//
+// 每个方法都先做授权检查：只允许管理白名单（authorization.admin_services）中的内部服务调用。
+type AdminHandler struct {
+	authorizer               *service.Authorizer
+	auditLogService          *service.AuditLogService
+	dependencyStatusService  *service.DependencyStatusService
+	feedbackAnalyticsService *service.FeedbackAnalyticsService
+	strategyBandit           *service.StrategyBandit // 没有开启时为 nil
+}
+
+// NewAdminHandler 构造函数
+func NewAdminHandler(
+	authorizer *service.Authorizer,
+	auditLogService *service.AuditLogService,
+	dependencyStatusService *service.DependencyStatusService,
+	feedbackAnalyticsService *service.FeedbackAnalyticsService,
+	strategyBandit *service.StrategyBandit,
+) *AdminHandler {
+	return &AdminHandler{
+		authorizer:               authorizer,
+		auditLogService:          auditLogService,
+		dependencyStatusService:  dependencyStatusService,
+		feedbackAnalyticsService: feedbackAnalyticsService,
+		strategyBandit:           strategyBandit,
+	}
+}
+
+// QueryAuditLog RPC 方法实现：查询管理操作审计日志
+func (h *AdminHandler) QueryAuditLog(
+	ctx context.Context,
+	req *recommendation.QueryAuditLogRequest,
+) (*recommendation.QueryAuditLogResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	query := &dto.AuditLogQuery{
+		Actor:  req.Actor,
+		Action: req.Action,
+		Target: req.Target,
+		Limit:  int(req.GetLimit()),
+	}
+	if req.Since > 0 {
+		query.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until > 0 {
+		query.Until = time.Unix(req.Until, 0)
+	}
+
+	entries, err := h.auditLogService.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.QueryAuditLogResponse{
+		Entries: make([]*recommendation.AuditEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, &recommendation.AuditEntry{
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			Target:     entry.Target,
+			Before:     entry.Before,
+			After:      entry.After,
+			RequestId:  entry.RequestID,
+			OccurredAt: entry.OccurredAt,
+		})
+	}
+	return resp, nil
+}
+
+// GetDependencyStatus RPC 方法实现：查询本实例每个依赖的状态
+func (h *AdminHandler) GetDependencyStatus(
+	ctx context.Context,
+	req *recommendation.GetDependencyStatusRequest,
+) (*recommendation.GetDependencyStatusResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	statuses := h.dependencyStatusService.Status(ctx)
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetDependencyStatusResponse{
+		Dependencies: make([]*recommendation.DependencyStatus, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		dependency := &recommendation.DependencyStatus{
+			Name:           status.Name,
+			Type:           status.Type,
+			Implementation: status.Implementation,
+			Target:         status.Target,
+			Health:         status.Health,
+			LastError:      status.LastError,
+			LatencyMs:      status.Latency.Milliseconds(),
+			CircuitBreaker: status.CircuitBreaker,
+		}
+		if !status.CheckedAt.IsZero() {
+			dependency.CheckedAt = status.CheckedAt.Format(time.RFC3339)
+		}
+		resp.Dependencies = append(resp.Dependencies, dependency)
+	}
+	return resp, nil
+}
+
+// GetFeedbackStats RPC 方法实现：查询推荐效果统计（运营看板）
+func (h *AdminHandler) GetFeedbackStats(
+	ctx context.Context,
+	req *recommendation.GetFeedbackStatsRequest,
+) (*recommendation.GetFeedbackStatsResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	query := &dto.FeedbackStatsQuery{}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+
+	stats, err := h.feedbackAnalyticsService.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetFeedbackStatsResponse{
+		Stats: make([]*recommendation.FeedbackStats, 0, len(stats)),
+	}
+	for _, row := range stats {
+		resp.Stats = append(resp.Stats, &recommendation.FeedbackStats{
+			Day:            row.Day,
+			Strategy:       row.Strategy,
+			ReasonType:     row.ReasonType,
+			Surface:        row.Surface,
+			Impressions:    row.Impressions,
+			Conversions:    row.Conversions,
+			ConversionRate: row.ConversionRate,
+		})
+	}
+	return resp, nil
+}
+
+// GetStrategyStats RPC 方法实现：查询按策略汇总的推荐效果（运营看板）
+func (h *AdminHandler) GetStrategyStats(
+	ctx context.Context,
+	req *recommendation.GetStrategyStatsRequest,
+) (*recommendation.GetStrategyStatsResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	query := &dto.FeedbackStatsQuery{}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+
+	stats, err := h.feedbackAnalyticsService.QueryStrategies(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetStrategyStatsResponse{
+		Stats: make([]*recommendation.StrategyStats, 0, len(stats)),
+	}
+	for _, row := range stats {
+		resp.Stats = append(resp.Stats, &recommendation.StrategyStats{
+			Strategy:         row.Strategy,
+			Impressions:      row.Impressions,
+			Clicks:           row.Clicks,
+			Conversions:      row.Conversions,
+			ClickThroughRate: row.ClickThroughRate,
+			ConversionRate:   row.ConversionRate,
+		})
+	}
+	return resp, nil
+}
+
+// GetInterleavingStats RPC 方法实现：查询交错实验的胜率
+func (h *AdminHandler) GetInterleavingStats(
+	ctx context.Context,
+	req *recommendation.GetInterleavingStatsRequest,
+) (*recommendation.GetInterleavingStatsResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	if req.Experiment == "" {
+		return nil, ErrInvalidExperiment
+	}
+
+	query := &dto.FeedbackStatsQuery{}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+
+	stats, err := h.feedbackAnalyticsService.QueryInterleaving(ctx, req.Experiment, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	return &recommendation.GetInterleavingStatsResponse{
+		Experiment: stats.Experiment,
+		Sessions:   stats.Sessions,
+		WinsA:      stats.WinsA,
+		WinsB:      stats.WinsB,
+		Ties:       stats.Ties,
+		WinRateA:   stats.WinRateA,
+		WinRateB:   stats.WinRateB,
+	}, nil
+}
+
+// GetStrategyWeights RPC 方法实现：查询各策略的 bandit 统计和当前权重
+func (h *AdminHandler) GetStrategyWeights(
+	ctx context.Context,
+	req *recommendation.GetStrategyWeightsRequest,
+) (*recommendation.GetStrategyWeightsResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	status, err := h.strategyBandit.Status(ctx, req.Surface)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetStrategyWeightsResponse{
+		Surface: status.Surface,
+		Arms:    make([]*recommendation.StrategyArm, 0, len(status.Arms)),
+	}
+	for _, arm := range status.Arms {
+		resp.Arms = append(resp.Arms, &recommendation.StrategyArm{
+			Arm:            arm.Arm,
+			Pulls:          arm.Pulls,
+			Rewards:        arm.Rewards,
+			ConversionRate: arm.ConversionRate,
+			BanditWeight:   arm.BanditWeight,
+			Weight:         arm.Weight,
+		})
+	}
+	if status.Override != nil {
+		resp.Override = &recommendation.StrategyWeightOverride{
+			Disabled:  status.Override.Disabled,
+			Weights:   status.Override.Weights,
+			UpdatedBy: status.Override.UpdatedBy,
+			UpdatedAt: status.Override.UpdatedAt.Unix(),
+		}
+	}
+	return resp, nil
+}
+
+// SetStrategyWeightOverride RPC 方法实现：固定某个展示场景的策略权重，或关闭 bandit
+func (h *AdminHandler) SetStrategyWeightOverride(
+	ctx context.Context,
+	req *recommendation.SetStrategyWeightOverrideRequest,
+) (*recommendation.SetStrategyWeightOverrideResponse, error) {
+	// 授权：只允许管理白名单中的内部服务调用
+	if err := h.authorizer.AuthorizeAdmin(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 操作人取认证后的身份，不使用请求中的 operator 字段（调用方可以随便填）
+	principal, _ := service.PrincipalFromContext(ctx)
+	err := h.strategyBandit.SetOverride(ctx, &dto.StrategyWeightOverrideRequest{
+		Surface:   req.Surface,
+		Operator:  principal.Actor(),
+		RequestID: req.RequestId,
+		Clear:     req.Clear,
+		Disabled:  req.Disabled,
+		Weights:   req.Weights,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &recommendation.SetStrategyWeightOverrideResponse{}, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+
+	"service/application/service"
+)
+
+// MetaKeyAuthToken 网关 / BFF 认证用户登录态之后，透传签名身份令牌的 metainfo key（见 service.IdentityVerifier）
+const MetaKeyAuthToken = "auth-token"
+
+// 授权失败的错误码（和 ErrCodeOverloaded 一样通过 BizStatusError 返回）
+const (
+	ErrCodeUnauthenticated int32 = 401
+	ErrCodeForbidden       int32 = 403
+)
+
+var (
+	ErrUnauthenticated = kerrors.NewBizStatusError(ErrCodeUnauthenticated, "unauthenticated")
+	ErrForbidden       = kerrors.NewBizStatusError(ErrCodeForbidden, "forbidden")
+)
+
+// PeerIdentities 入站连接的对端身份（mTLS 客户端证书中的 SPIFFE ID，由 mtls.Reloader 实现）
+type PeerIdentities interface {
+	PeerID(remoteAddr string) (string, bool)
+}
+
+// Authenticator Kitex 服务端认证：识别调用方身份，放进 ctx
+//
+// 认证（你是谁）在接口层，授权（你能做什么）在应用层（service.Authorizer）：
+// 中间件只负责从协议里取出经过校验的身份，构造 service.Principal。
+//
+// 身份来源（只信任能校验的来源，调用方自己声明的服务名、用户ID一律不用）：
+// - 内部服务（开启 mTLS）：握手时校验过的客户端证书中的 SPIFFE ID，按 mtls.clients 映射为服务名
+// - 内部服务（没有开启 mTLS）：签名身份令牌中的服务名
+// - 终端用户：网关 / BFF 签发的签名身份令牌中的用户ID（metainfo: auth-token）
+//
+// 两种来源都没有、或令牌校验失败时直接拒绝请求（ErrUnauthenticated）。
+type Authenticator struct {
+	peers    PeerIdentities           // 为 nil 时没有开启 mTLS
+	services map[string]string        // SPIFFE ID → 服务名（授权白名单中使用的名字）
+	tokens   service.IdentityVerifier // 为 nil 时没有配置签名密钥，不接受令牌
+}
+
+// NewAuthenticator 构造函数
+//
+// services 中没有的 SPIFFE ID 直接作为服务名（只校验 CA 时），不会匹配任何授权白名单。
+func NewAuthenticator(peers PeerIdentities, services map[string]string, tokens service.IdentityVerifier) *Authenticator {
+	return &Authenticator{peers: peers, services: services, tokens: tokens}
+}
+
+// Middleware Kitex 服务端中间件
+//
+// 使用方式：
+//
+//	recommendationservice.NewServer(h, server.WithMiddleware(authenticator.Middleware))
+func (a *Authenticator) Middleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		principal, ok := a.principalFromRPC(ctx)
+		if !ok {
+			return ErrUnauthenticated
+		}
+		return next(service.WithPrincipal(ctx, principal), req, resp)
+	}
+}
+
+// principalFromRPC 辅助方法：从 RPC 上下文中取出调用方身份，没有可校验的身份时返回 false
+func (a *Authenticator) principalFromRPC(ctx context.Context) (service.Principal, bool) {
+	var principal service.Principal
+	if token, _ := metainfo.GetPersistentValue(ctx, MetaKeyAuthToken); token != "" {
+		if a.tokens == nil {
+			return service.Principal{}, false
+		}
+		claims, err := a.tokens.Verify(token)
+		if err != nil {
+			return service.Principal{}, false
+		}
+		principal = claims
+	}
+	if a.peers != nil {
+		// 开启 mTLS 时服务名只来自证书，令牌中的服务名不使用
+		principal.Service = ""
+		if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.From() != nil {
+			principal.Service = a.peerService(ri.From())
+		}
+	}
+	return principal, principal.Service != "" || principal.UserID > 0
+}
+
+// peerService 辅助方法：客户端证书中的身份对应的服务名，没有校验过的证书时为空
+func (a *Authenticator) peerService(from rpcinfo.EndpointInfo) string {
+	if from.Address() == nil {
+		return ""
+	}
+	id, ok := a.peers.PeerID(from.Address().String())
+	if !ok || id == "" {
+		return ""
+	}
+	if name, ok := a.services[id]; ok {
+		return name
+	}
+	return id
+}
+
+// toAuthError 辅助方法：应用层的授权错误 → 带错误码的 RPC 错误
+func toAuthError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		return ErrUnauthenticated
+	case errors.Is(err, service.ErrForbidden):
+		return ErrForbidden
+	default:
+		return err
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/utils"
+
+	"service/application/service"
+)
+
+// staticPeers 测试用对端身份：远端地址 → SPIFFE ID
+type staticPeers map[string]string
+
+func (p staticPeers) PeerID(remoteAddr string) (string, bool) {
+	id, ok := p[remoteAddr]
+	return id, ok
+}
+
+// staticTokens 测试用令牌校验：令牌 → 身份，其他令牌校验失败
+type staticTokens map[string]service.Principal
+
+func (t staticTokens) Verify(token string) (service.Principal, error) {
+	principal, ok := t[token]
+	if !ok {
+		return service.Principal{}, errors.New("invalid token")
+	}
+	return principal, nil
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	peers := staticPeers{
+		"10.0.0.1:5000": "spiffe://example.org/ns/prod/sa/bff",
+		"10.0.0.2:5000": "spiffe://example.org/ns/prod/sa/unknown",
+	}
+	services := map[string]string{"spiffe://example.org/ns/prod/sa/bff": "bff"}
+	tokens := staticTokens{
+		"user-1":  {UserID: 1},
+		"svc-ops": {Service: "ops-dashboard"},
+	}
+
+	tests := []struct {
+		name     string
+		peers    PeerIdentities
+		tokens   service.IdentityVerifier
+		declared string // 调用方自己声明的服务名，任何情况下都不使用
+		addr     string
+		token    string
+		want     service.Principal
+		wantErr  bool
+	}{
+		{name: "mapped SPIFFE ID", peers: peers, declared: "admin-console", addr: "10.0.0.1:5000", want: service.Principal{Service: "bff"}},
+		{name: "unmapped SPIFFE ID", peers: peers, addr: "10.0.0.2:5000", want: service.Principal{Service: "spiffe://example.org/ns/prod/sa/unknown"}},
+		{name: "SPIFFE ID with user token", peers: peers, tokens: tokens, addr: "10.0.0.1:5000", token: "user-1", want: service.Principal{Service: "bff", UserID: 1}},
+		{name: "token service ignored under mTLS", peers: peers, tokens: tokens, addr: "10.0.0.3:5000", token: "svc-ops", wantErr: true},
+		{name: "no verified peer", peers: peers, declared: "bff", addr: "10.0.0.3:5000", wantErr: true},
+		{name: "service token", tokens: tokens, declared: "crawler", addr: "10.0.0.3:5000", token: "svc-ops", want: service.Principal{Service: "ops-dashboard"}},
+		{name: "invalid token", peers: peers, tokens: tokens, addr: "10.0.0.1:5000", token: "forged", wantErr: true},
+		{name: "token without verifier", addr: "10.0.0.3:5000", token: "user-1", wantErr: true},
+		{name: "declared name only", tokens: tokens, declared: "bff", addr: "10.0.0.3:5000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := rpcinfo.NewEndpointInfo(tt.declared, "", utils.NewNetAddr("tcp", tt.addr), nil)
+			ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), rpcinfo.NewRPCInfo(from, nil, nil, nil, nil))
+			if tt.token != "" {
+				ctx = metainfo.WithPersistentValue(ctx, MetaKeyAuthToken, tt.token)
+			}
+
+			var got service.Principal
+			err := NewAuthenticator(tt.peers, services, tt.tokens).Middleware(func(ctx context.Context, req, resp interface{}) error {
+				got, _ = service.PrincipalFromContext(ctx)
+				return nil
+			})(ctx, nil, nil)
+			if tt.wantErr {
+				if err != ErrUnauthenticated {
+					t.Fatalf("err = %v, want ErrUnauthenticated", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("principal = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+const (
+	// maxBatchUsers 批量接口一次最多多少个用户
+	maxBatchUsers = 100
+	// batchConcurrency 批量接口同时为几个用户生成推荐（每个用户仍然单独经过负载保护）
+	batchConcurrency = 8
+)
+
+// 批量结果中单个用户的错误码（其余的错误码和单个请求相同：401、403、429、503）
+const (
+	ErrCodeInvalidArgument int32 = 400
+	ErrCodeInternal        int32 = 500
+	ErrCodeTimeout         int32 = 504
+)
+
+// ErrInvalidBatch 批量请求的用户数为 0 或超过 maxBatchUsers
+var ErrInvalidBatch = errors.New("invalid batch size")
+
+// BatchGetRecommendations RPC 方法实现：批量获取推荐（推送管道）
+//
+// 为什么需要？
+// 推送管道以前逐个用户调用，或者把一批用户当作一个整体：
+// 一个用户失败（被限流、下游超时）整批重试，已经成功的用户也要重新生成一次。
+// 这里每个用户单独返回状态：
+// - ok：完整的推荐
+// - degraded：可以使用，但超时预算用完、或部分推荐的资料/帖子没有取到
+// - error：带错误码（和单个请求的错误码相同），调用方只重试这些用户
+//
+// 每个用户走和单个请求完全相同的流程（授权、异常请求检测、负载保护），
+// 过载时只有部分用户得到 503，而不是整批被拒绝。
+func (h *RecommendationHandler) BatchGetRecommendations(
+	ctx context.Context,
+	req *recommendation.BatchGetRecommendationsRequest,
+) (*recommendation.BatchGetRecommendationsResponse, error) {
+
+	// 参数验证
+	if len(req.UserIds) == 0 || len(req.UserIds) > maxBatchUsers {
+		return nil, ErrInvalidBatch
+	}
+	// 过滤条件对每个用户相同：不合法时整批失败
+	if _, err := convertFilter(req.GetFilter()); err != nil {
+		return nil, err
+	}
+
+	// 认证：没有身份时整批失败（每个用户都会得到同样的错误）
+	if _, ok := service.PrincipalFromContext(ctx); !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	results := make([]*recommendation.BatchRecommendationResult, len(req.UserIds))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, userID := range req.UserIds {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, userID int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resp, err := h.getFollowingBasedRecommendations(ctx, &recommendation.GetRecommendationsRequest{
+				UserId:  userID,
+				Limit:   req.GetLimit(),
+				Surface: req.GetSurface(),
+				Filter:  req.GetFilter(),
+			})
+			results[i] = batchResult(userID, resp, err)
+		}(i, userID)
+	}
+	wg.Wait()
+
+	return &recommendation.BatchGetRecommendationsResponse{Results: results}, nil
+}
+
+// batchResult 辅助函数：一个用户的响应或错误 → 批量结果
+func batchResult(userID int64, resp *recommendation.GetRecommendationsResponse, err error) *recommendation.BatchRecommendationResult {
+	if err != nil {
+		return &recommendation.BatchRecommendationResult{
+			UserId:       userID,
+			Status:       string(dto.ItemStatusError),
+			ErrorCode:    itemErrorCode(err),
+			ErrorMessage: err.Error(),
+		}
+	}
+	status := dto.ItemStatusOK
+	if resp.Degraded {
+		status = dto.ItemStatusDegraded
+	}
+	for _, rec := range resp.Recommendations {
+		if rec.Status == string(dto.ItemStatusDegraded) {
+			status = dto.ItemStatusDegraded
+		}
+	}
+	return &recommendation.BatchRecommendationResult{
+		UserId:          userID,
+		Status:          string(status),
+		Recommendations: resp.Recommendations,
+		Version:         resp.Version,
+	}
+}
+
+// itemErrorCode 辅助函数：单个用户的错误 → 错误码
+//
+// 带错误码的错误（授权、限流、过载）使用原来的错误码；
+// 参数错误 400、超时 504，其余的都是 500（调用方可以重试）。
+func itemErrorCode(err error) int32 {
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		return bizErr.BizStatusCode()
+	}
+	switch {
+	case errors.Is(err, ErrInvalidUserID), errors.Is(err, ErrInvalidSessionID), errors.Is(err, ErrInvalidTimezone),
+		errors.Is(err, service.ErrInvalidRecencyDays), errors.Is(err, ErrInvalidFilter), errors.Is(err, service.ErrUnknownReasonType):
+		return ErrCodeInvalidArgument
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeTimeout
+	default:
+		return ErrCodeInternal
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+func TestBatchResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       *recommendation.GetRecommendationsResponse
+		err        error
+		wantStatus string
+		wantCode   int32
+	}{
+		{"ok", &recommendation.GetRecommendationsResponse{
+			Recommendations: []*recommendation.UserRecommendation{{UserId: 10, Status: "ok"}},
+		}, nil, "ok", 0},
+		{"item degraded", &recommendation.GetRecommendationsResponse{
+			Recommendations: []*recommendation.UserRecommendation{{UserId: 10, Status: "degraded", Degraded: []string{"posts"}}},
+		}, nil, "degraded", 0},
+		{"response degraded", &recommendation.GetRecommendationsResponse{Degraded: true}, nil, "degraded", 0},
+		{"overloaded", nil, ErrOverloaded, "error", ErrCodeOverloaded},
+		{"forbidden", nil, ErrForbidden, "error", ErrCodeForbidden},
+		{"invalid user", nil, ErrInvalidUserID, "error", ErrCodeInvalidArgument},
+		{"timeout", nil, fmt.Errorf("hydrate: %w", context.DeadlineExceeded), "error", ErrCodeTimeout},
+		{"internal", nil, errors.New("database unavailable"), "error", ErrCodeInternal},
+	}
+	for _, tt := range tests {
+		got := batchResult(7, tt.resp, tt.err)
+		if got.UserId != 7 || got.Status != tt.wantStatus || got.ErrorCode != tt.wantCode {
+			t.Errorf("%s: result = %+v, want status %s, code %d", tt.name, got, tt.wantStatus, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/entity"
+	"service/domain/valueobject"
+)
+
+// ErrRejectedFollowEvent 事件本身有问题，重试也不会成功（消费者直接转入死信队列）
+//
+// 格式错误、用户ID非法、未知的租户、未知的事件类型、不允许的状态转换、关注自己。
+// 返回的错误同时包装了具体原因（errors.Is 仍然可以判断 ErrInvalidUserID 等）。
+var ErrRejectedFollowEvent = errors.New("rejected follow event")
+
+// FollowEvent 关注事件（由社交服务通过消息队列发布）
+//
+// 和反向关注索引消费的是同一个 topic（persistence.FollowEvent），这里只关心归因、本地关注关系需要的字段。
+type FollowEvent struct {
+	FollowerID  int64     `json:"follower_id"`
+	FollowingID int64     `json:"following_id"`
+	Action      string    `json:"action"`           // follow、unfollow、mute、unmute
+	Source      string    `json:"source,omitempty"` // 关注入口；推荐卡片上的关注为 recommendation，旧事件没有这个字段
+	OccurredAt  time.Time `json:"occurred_at"`
+	TenantID    string    `json:"tenant_id,omitempty"` // 为空表示默认租户
+}
+
+// FollowEventHandler 消息处理器：消费关注事件，维护本地关注关系，把关注归因到推荐曝光
+//
+// 和 UserDeletionEventHandler 一样只负责把消息转换为应用服务调用：
+// - FollowService.HandleFollowEvent：所有事件类型（followService 为 nil 时跳过）
+// - 关注列表缓存的删除：所有事件类型（开启 WithFollowGraphInvalidation、followService 为 nil 时）
+// - RecommendationService.HandleFollowChange：所有事件类型（开启 WithFollowInvalidation 时）
+// - AttributionService.HandleFollow：只处理关注事件（取关不撤销转化）
+type FollowEventHandler struct {
+	attributionService    *service.AttributionService
+	followService         *service.FollowService
+	recommendationService *service.RecommendationService // 清理推荐列表（为 nil 时不清理）
+	graphCache            service.UserCacheInvalidator   // 清理关注列表缓存（为 nil 时不清理）
+}
+
+// FollowEventHandlerOption 可选配置
+type FollowEventHandlerOption func(*FollowEventHandler)
+
+// WithFollowInvalidation 关注关系变化后清理关注者的推荐列表（缓存的列表、保存的列表）
+//
+// followService 同样会删除缓存的列表；这里还从保存的列表中移除刚关注的人，
+// 并且在关注关系仍由社交服务写入（followService 为 nil）时同样生效。
+func WithFollowInvalidation(recommendationService *service.RecommendationService) FollowEventHandlerOption {
+	return func(h *FollowEventHandler) {
+		h.recommendationService = recommendationService
+	}
+}
+
+// WithFollowGraphInvalidation 关注关系由社交服务写入（followService 为 nil）时，删除关注者的关注列表缓存
+//
+// 推荐服务不写入关注关系，带缓存的社交图谱仓储不知道关注关系变了，只能等 TTL 过期；
+// 关注事件就是失效消息。followService 不为 nil 时由它在保存之后删除（见 service.WithFollowGraphCache）。
+func WithFollowGraphInvalidation(graphCache service.UserCacheInvalidator) FollowEventHandlerOption {
+	return func(h *FollowEventHandler) {
+		h.graphCache = graphCache
+	}
+}
+
+// NewFollowEventHandler 构造函数
+//
+// followService 可以为 nil（关注关系仍由社交服务写入时，只做归因）。
+func NewFollowEventHandler(
+	attributionService *service.AttributionService,
+	followService *service.FollowService,
+	opts ...FollowEventHandlerOption,
+) *FollowEventHandler {
+	h := &FollowEventHandler{
+		attributionService: attributionService,
+		followService:      followService,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// HandleMessage 处理一条消息（消息体为 JSON 格式的 FollowEvent）
+//
+// 返回错误时消费者应该重试（同一次曝光只记录一次转化）；
+// ErrRejectedFollowEvent（如格式错误的消息）重试也不会成功，应由消费者转入死信队列。
+func (h *FollowEventHandler) HandleMessage(ctx context.Context, payload []byte) error {
+	var event FollowEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("%w: decode follow event: %w", ErrRejectedFollowEvent, err)
+	}
+	return h.HandleEvent(ctx, event)
+}
+
+// HandleEvent 处理一个关注事件
+//
+// 依次更新关注关系（或者清理关注列表缓存）、清理推荐列表、归因：后面的步骤失败重试时，前面的步骤是空操作。
+func (h *FollowEventHandler) HandleEvent(ctx context.Context, event FollowEvent) error {
+	action := dto.FollowAction(event.Action)
+	if h.followService == nil && h.recommendationService == nil && h.graphCache == nil && action != dto.FollowActionFollow {
+		return nil
+	}
+	if event.FollowerID <= 0 || event.FollowingID <= 0 {
+		return fmt.Errorf("%w: %w", ErrRejectedFollowEvent, ErrInvalidUserID)
+	}
+	if event.TenantID != "" {
+		tenant, err := valueobject.NewTenantID(event.TenantID)
+		if err != nil {
+			return fmt.Errorf("%w: handle follow event %d→%d: %w", ErrRejectedFollowEvent, event.FollowerID, event.FollowingID, err)
+		}
+		ctx = valueobject.WithTenant(ctx, tenant)
+	}
+	req := &dto.FollowEventRequest{
+		FollowerID:  event.FollowerID,
+		FollowingID: event.FollowingID,
+		Action:      action,
+		Source:      event.Source,
+		OccurredAt:  event.OccurredAt,
+	}
+	if h.followService != nil {
+		if _, err := h.followService.HandleFollowEvent(ctx, req); err != nil {
+			if rejected(err) {
+				err = fmt.Errorf("%w: %w", ErrRejectedFollowEvent, err)
+			}
+			return fmt.Errorf("handle follow event %d→%d: %w", event.FollowerID, event.FollowingID, err)
+		}
+	} else if h.graphCache != nil {
+		if err := h.graphCache.InvalidateUser(ctx, event.FollowerID); err != nil {
+			return fmt.Errorf("handle follow event %d→%d: %w", event.FollowerID, event.FollowingID, err)
+		}
+	}
+	if h.recommendationService != nil {
+		if err := h.recommendationService.HandleFollowChange(ctx, req); err != nil {
+			return fmt.Errorf("handle follow event %d→%d: %w", event.FollowerID, event.FollowingID, err)
+		}
+	}
+	if action != dto.FollowActionFollow {
+		return nil
+	}
+	if _, err := h.attributionService.HandleFollow(ctx, req); err != nil {
+		return fmt.Errorf("handle follow event %d→%d: %w", event.FollowerID, event.FollowingID, err)
+	}
+	return nil
+}
+
+// rejected 辅助函数：关注关系的错误是否是事件本身的问题（而不是仓储失败）
+func rejected(err error) bool {
+	return errors.Is(err, service.ErrUnknownFollowAction) ||
+		errors.Is(err, entity.ErrInvalidFollowTransition) ||
+		errors.Is(err, entity.ErrCannotFollowSelf)
+}
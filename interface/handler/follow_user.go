@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/entity"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+// ErrFollowUnavailable 没有开启关注、取关接口（没有注入 FollowService）
+var ErrFollowUnavailable = errors.New("follow writes unavailable")
+
+// 关注、取关的业务错误码（关注自己使用 ErrCodeInvalidArgument）
+const (
+	ErrCodeNotFollowing     int32 = 404
+	ErrCodeAlreadyFollowing int32 = 409
+)
+
+// 关注、取关的业务错误：客户端按错误码区分，不需要匹配错误信息
+var (
+	ErrCannotFollowSelf = kerrors.NewBizStatusError(ErrCodeInvalidArgument, "cannot follow self")
+	ErrAlreadyFollowing = kerrors.NewBizStatusError(ErrCodeAlreadyFollowing, "already following")
+	ErrNotFollowing     = kerrors.NewBizStatusError(ErrCodeNotFollowing, "not following")
+)
+
+// WithFollowService 开启关注、取关接口（FollowUser、UnfollowUser）
+//
+// 没有开启时两个接口返回 ErrFollowUnavailable（关注关系仍由社交服务写入）。
+func WithFollowService(followService *service.FollowService) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.followService = followService
+	}
+}
+
+// FollowUser RPC 方法实现：用户主动关注
+//
+// 授权见 Authorizer.AuthorizeFollowWrite：只能为自己关注，代为查询推荐的服务不能代为关注。
+// 关注自己返回 ErrCannotFollowSelf，已经在关注返回 ErrAlreadyFollowing。
+func (h *RecommendationHandler) FollowUser(
+	ctx context.Context,
+	req *recommendation.FollowUserRequest,
+) (*recommendation.FollowUserResponse, error) {
+
+	// 参数验证
+	if req.FollowerId <= 0 || req.FollowingId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	if h.followService == nil {
+		return nil, ErrFollowUnavailable
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeFollowWrite(ctx, req.FollowerId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	if err := h.followService.FollowUser(ctx, &dto.FollowUserRequest{
+		FollowerID:  req.FollowerId,
+		FollowingID: req.FollowingId,
+		Source:      req.Source,
+	}); err != nil {
+		return nil, toFollowError(err)
+	}
+	return &recommendation.FollowUserResponse{}, nil
+}
+
+// UnfollowUser RPC 方法实现：用户主动取关（授权规则同 FollowUser，没有在关注时返回 ErrNotFollowing）
+func (h *RecommendationHandler) UnfollowUser(
+	ctx context.Context,
+	req *recommendation.UnfollowUserRequest,
+) (*recommendation.UnfollowUserResponse, error) {
+
+	// 参数验证
+	if req.FollowerId <= 0 || req.FollowingId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	if h.followService == nil {
+		return nil, ErrFollowUnavailable
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeFollowWrite(ctx, req.FollowerId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	if err := h.followService.UnfollowUser(ctx, &dto.UnfollowUserRequest{
+		FollowerID:  req.FollowerId,
+		FollowingID: req.FollowingId,
+	}); err != nil {
+		return nil, toFollowError(err)
+	}
+	return &recommendation.UnfollowUserResponse{}, nil
+}
+
+// toFollowError 辅助函数：关注关系的领域错误 → 业务错误（其他错误原样返回，如仓储失败）
+func toFollowError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrCannotFollowSelf):
+		return ErrCannotFollowSelf
+	case errors.Is(err, entity.ErrAlreadyFollowing):
+		return ErrAlreadyFollowing
+	case errors.Is(err, entity.ErrNotFollowing):
+		return ErrNotFollowing
+	default:
+		return err
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+	"service/domain/entity"
+	"service/domain/repository"
+	"service/domain/valueobject"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+func TestToFollowError(t *testing.T) {
+	storeErr := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int32 // 0 表示原样返回
+	}{
+		{"follow self", entity.ErrCannotFollowSelf, ErrCodeInvalidArgument},
+		{"already following", fmt.Errorf("follow: %w", entity.ErrAlreadyFollowing), ErrCodeAlreadyFollowing},
+		{"not following", entity.ErrNotFollowing, ErrCodeNotFollowing},
+		{"store failure", storeErr, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toFollowError(tt.err)
+			bizErr, ok := kerrors.FromBizStatusError(got)
+			if tt.wantCode == 0 {
+				if ok || got != tt.err {
+					t.Fatalf("toFollowError(%v) = %v, want the error unchanged", tt.err, got)
+				}
+				return
+			}
+			if !ok || bizErr.BizStatusCode() != tt.wantCode {
+				t.Fatalf("toFollowError(%v) = %v, want code %d", tt.err, got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// followWrites 测试用社交图谱：只实现关注、取关用到的方法，记录写入次数
+type followWrites struct {
+	repository.SocialGraphRepository
+	follows map[[2]int64]*entity.Follow
+	writes  int
+}
+
+func (r *followWrites) FindFollow(ctx context.Context, followerID, followingID valueobject.UserID) (*entity.Follow, error) {
+	return r.follows[[2]int64{followerID.Value(), followingID.Value()}], nil
+}
+
+func (r *followWrites) SaveFollow(ctx context.Context, follow *entity.Follow) error {
+	r.follows[[2]int64{follow.FollowerID().Value(), follow.FollowingID().Value()}] = follow
+	r.writes++
+	return nil
+}
+
+func (r *followWrites) RemoveFollow(ctx context.Context, followerID, followingID valueobject.UserID, removedAt time.Time) error {
+	r.writes++
+	return nil
+}
+
+func TestFollowUser_Authorization(t *testing.T) {
+	authorizer := service.NewAuthorizer(service.AuthorizationPolicy{
+		OnBehalfServices:    []string{"push-pipeline", "bff", "ops-dashboard"},
+		FollowWriteServices: []string{"onboarding-importer"},
+	})
+
+	tests := []struct {
+		name      string
+		principal *service.Principal
+		wantCode  int32 // 0 表示允许
+	}{
+		{"user follows for themselves", &service.Principal{UserID: 1}, 0},
+		{"bff forwarding the logged-in user", &service.Principal{Service: "bff", UserID: 1}, 0},
+		{"bff forwarding another user", &service.Principal{Service: "bff", UserID: 2}, ErrCodeForbidden},
+		{"on-behalf read service", &service.Principal{Service: "push-pipeline"}, ErrCodeForbidden},
+		{"on-behalf read service with admin rights", &service.Principal{Service: "ops-dashboard"}, ErrCodeForbidden},
+		{"follow write allowlist", &service.Principal{Service: "onboarding-importer"}, 0},
+		{"no principal", nil, ErrCodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := &followWrites{follows: make(map[[2]int64]*entity.Follow)}
+			h := NewRecommendationHandler(nil, nil, nil, nil, nil, authorizer,
+				WithFollowService(service.NewFollowService(graph)))
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = service.WithPrincipal(ctx, *tt.principal)
+			}
+
+			_, followErr := h.FollowUser(ctx, &recommendation.FollowUserRequest{FollowerId: 1, FollowingId: 2})
+			_, unfollowErr := h.UnfollowUser(ctx, &recommendation.UnfollowUserRequest{FollowerId: 1, FollowingId: 2})
+			if tt.wantCode == 0 {
+				if followErr != nil || unfollowErr != nil {
+					t.Fatalf("follow = %v, unfollow = %v, want both allowed", followErr, unfollowErr)
+				}
+				return
+			}
+			for _, err := range []error{followErr, unfollowErr} {
+				if bizErr, ok := kerrors.FromBizStatusError(err); !ok || bizErr.BizStatusCode() != tt.wantCode {
+					t.Errorf("error = %v, want code %d", err, tt.wantCode)
+				}
+			}
+			if graph.writes != 0 {
+				t.Errorf("rejected request wrote %d follows", graph.writes)
+			}
+		})
+	}
+}
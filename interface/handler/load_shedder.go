@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+)
+
+// ErrCodeOverloaded 过载错误码：请求在入口处被限流拒绝
+//
+// 客户端看到这个错误码时应该退避重试（或直接展示兜底内容），
+// 而不是立即重试——立即重试只会让过载更严重。
+const ErrCodeOverloaded int32 = 503
+
+// ErrOverloaded 过载错误：在途请求和排队请求都已满，或排队超时
+//
+// 使用 Kitex 的业务状态错误（BizStatusError）返回，
+// 错误码会原样传给调用方，调用方可以和普通的失败区分开。
+var ErrOverloaded = kerrors.NewBizStatusError(ErrCodeOverloaded, "recommendation service overloaded")
+
+// LoadShedder 负载保护：限制同时生成推荐的请求数，超出的请求尽早拒绝
+//
+// 为什么需要？
+// 推荐生成会访问数据库、user 服务、content 服务。
+// 流量突增时如果来者不拒，所有请求一起变慢，最后一起超时：
+// 数据库和下游服务被拖垮，用户也一个结果都拿不到。
+// 尽早拒绝一部分请求，剩下的请求才能在超时前正常完成。
+//
+// 工作方式：
+// - 最多 maxInFlight 个请求同时生成推荐
+// - 超出的请求最多 maxQueue 个排队等待，等待时间不超过 queueTimeout
+// - 排队已满：立即拒绝
+// - 排队超时：拒绝
+//
+// 自适应排队时间：
+// 排队时间还受请求自身的截止时间限制，
+// 剩余时间不足 minExecution（留给推荐生成的最少时间）的请求不再排队，
+// 因为即使拿到名额也来不及完成，不如把名额留给别的请求。
+//
+// 按用户优先级（AcquireTier）：
+// - high：不受排队数限制（仍然受排队时间限制）
+// - standard：按上面的规则
+// - low：不排队；在途请求达到 lowPriorityLimit 时直接拒绝，把剩下的名额留给其他用户
+//
+// 监控：
+// - MetricLoadShed：被拒绝的请求数（标签：reason=queue_full/queue_timeout/no_budget/low_priority）
+type LoadShedder struct {
+	slots            chan struct{} // 在途请求的名额（信号量）
+	maxQueue         int64
+	queueTimeout     time.Duration
+	minExecution     time.Duration
+	lowPriorityLimit int          // 低优先级请求最多使用的名额
+	queued           atomic.Int64 // 正在排队的请求数
+	metrics          service.Metrics
+}
+
+// LoadShedderOption 可选配置
+type LoadShedderOption func(*LoadShedder)
+
+// WithLowPriorityLimit 低优先级请求最多使用 n 个在途名额（默认可以使用全部名额，但不排队）
+func WithLowPriorityLimit(n int) LoadShedderOption {
+	return func(l *LoadShedder) {
+		l.lowPriorityLimit = min(max(n, 0), cap(l.slots))
+	}
+}
+
+// NewLoadShedder 构造函数
+//
+// 参数：
+// - maxInFlight: 最多同时生成推荐的请求数
+// - maxQueue: 最多排队等待的请求数
+// - queueTimeout: 最长排队时间
+// - minExecution: 拿到名额后，推荐生成至少需要的时间
+// - metrics: 监控指标（可以为 nil）
+func NewLoadShedder(
+	maxInFlight int,
+	maxQueue int,
+	queueTimeout time.Duration,
+	minExecution time.Duration,
+	metrics service.Metrics,
+	opts ...LoadShedderOption,
+) *LoadShedder {
+	l := &LoadShedder{
+		slots:        make(chan struct{}, max(maxInFlight, 1)),
+		maxQueue:     int64(max(maxQueue, 0)),
+		queueTimeout: queueTimeout,
+		minExecution: minExecution,
+		metrics:      metrics,
+	}
+	l.lowPriorityLimit = cap(l.slots)
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Acquire 申请一个在途名额（standard 优先级）
+//
+// 成功时返回 release 函数，请求处理完后必须调用；
+// 被拒绝时返回 ErrOverloaded。
+// 请求在排队期间被取消时，返回 ctx 的错误。
+func (l *LoadShedder) Acquire(ctx context.Context) (release func(), err error) {
+	return l.AcquireTier(ctx, service.PriorityStandard)
+}
+
+// AcquireTier 按用户优先级申请一个在途名额（返回值和 Acquire 相同）
+func (l *LoadShedder) AcquireTier(ctx context.Context, tier service.PriorityTier) (release func(), err error) {
+	release = func() { <-l.slots }
+
+	// 低优先级：名额快用完时直接拒绝，有名额也不排队
+	// （在途数是近似值：并发申请时可能略微超过 lowPriorityLimit）
+	if tier == service.PriorityLow {
+		if len(l.slots) < l.lowPriorityLimit {
+			select {
+			case l.slots <- struct{}{}:
+				return release, nil
+			default:
+			}
+		}
+		l.reject("low_priority")
+		return nil, ErrOverloaded
+	}
+
+	// 快速路径：有空闲名额，不排队
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueue && tier != service.PriorityHigh {
+		l.queued.Add(-1)
+		l.reject("queue_full")
+		return nil, ErrOverloaded
+	}
+	defer l.queued.Add(-1)
+
+	wait, ok := l.queueBudget(ctx)
+	if !ok {
+		l.reject("no_budget")
+		return nil, ErrOverloaded
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		l.reject("queue_timeout")
+		return nil, ErrOverloaded
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// queueBudget 辅助方法：计算这个请求最多能排队多久
+//
+// 请求没有截止时间时，使用 queueTimeout；
+// 有截止时间时，还要给推荐生成留出 minExecution。
+func (l *LoadShedder) queueBudget(ctx context.Context) (time.Duration, bool) {
+	wait := l.queueTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline)-l.minExecution)
+	}
+	return wait, wait > 0
+}
+
+// reject 辅助方法：上报被拒绝的请求
+func (l *LoadShedder) reject(reason string) {
+	if l.metrics == nil {
+		return
+	}
+	l.metrics.IncCounter(service.MetricLoadShed, 1, map[string]string{
+		"reason": reason,
+	})
+}
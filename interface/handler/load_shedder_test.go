@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"service/application/service"
+)
+
+func TestLoadShedder_RejectsWhenQueueFull(t *testing.T) {
+	shedder := NewLoadShedder(1, 0, 50*time.Millisecond, 0, nil)
+
+	release, err := shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+
+	if _, err := shedder.Acquire(context.Background()); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("second Acquire err = %v, want ErrOverloaded", err)
+	}
+
+	release()
+	release, err = shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	release()
+}
+
+func TestLoadShedder_QueuedRequestGetsReleasedSlot(t *testing.T) {
+	shedder := NewLoadShedder(1, 1, time.Second, 0, nil)
+
+	release, err := shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+	time.AfterFunc(10*time.Millisecond, release)
+
+	release, err = shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("queued Acquire failed: %v", err)
+	}
+	release()
+}
+
+func TestLoadShedder_QueueTimeout(t *testing.T) {
+	shedder := NewLoadShedder(1, 1, 10*time.Millisecond, 0, nil)
+
+	release, err := shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+	defer release()
+
+	if _, err := shedder.Acquire(context.Background()); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("queued Acquire err = %v, want ErrOverloaded", err)
+	}
+}
+
+func TestLoadShedder_NoBudgetLeftForExecution(t *testing.T) {
+	shedder := NewLoadShedder(1, 1, time.Second, 100*time.Millisecond, nil)
+
+	release, err := shedder.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+	defer release()
+
+	// 剩余 50ms，不够留给推荐生成的 100ms：不排队，直接拒绝
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := shedder.Acquire(ctx); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("Acquire err = %v, want ErrOverloaded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Acquire waited %v, want immediate rejection", elapsed)
+	}
+}
+
+func TestLoadShedder_PriorityTiers(t *testing.T) {
+	shedder := NewLoadShedder(2, 0, 50*time.Millisecond, 0, nil, WithLowPriorityLimit(1))
+
+	release, err := shedder.AcquireTier(context.Background(), service.PriorityStandard)
+	if err != nil {
+		t.Fatalf("standard Acquire failed: %v", err)
+	}
+	defer release()
+
+	// 一个名额已经在用：低优先级请求不能再使用
+	if _, err := shedder.AcquireTier(context.Background(), service.PriorityLow); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("low Acquire err = %v, want ErrOverloaded", err)
+	}
+	release2, err := shedder.AcquireTier(context.Background(), service.PriorityStandard)
+	if err != nil {
+		t.Fatalf("second standard Acquire failed: %v", err)
+	}
+	time.AfterFunc(10*time.Millisecond, release2)
+
+	// 名额用完、排队数为 0：高优先级请求仍然可以排队
+	release3, err := shedder.AcquireTier(context.Background(), service.PriorityHigh)
+	if err != nil {
+		t.Fatalf("high Acquire err = %v, want queued slot", err)
+	}
+	release3()
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+
+	"service/domain/valueobject"
+)
+
+// MetaKeyLocale 网关 / BFF 透传客户端语言（Accept-Language 请求头）的 metainfo key
+const MetaKeyLocale = "locale"
+
+// LocaleMiddleware Kitex 服务端中间件：识别请求的语言，放进 ctx
+//
+// 和租户一样只在接口层识别一次：应用层获取推荐理由文案、分栏标题时按这个语言
+// （见 service.Tenants.Locale，没有时使用租户配置的语言）。
+// 语言不合法时按没有传处理，不拒绝请求。
+//
+// 使用方式：
+//
+//	recommendationservice.NewServer(h, server.WithMiddleware(handler.LocaleMiddleware))
+func LocaleMiddleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		raw, _ := metainfo.GetPersistentValue(ctx, MetaKeyLocale)
+		return next(valueobject.WithLocale(ctx, valueobject.ParseLocale(raw)), req, resp)
+	}
+}
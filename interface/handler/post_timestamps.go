@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// legacyTimestampLayout 旧版本协议的帖子时间格式（没有时区，服务端所在时区）
+	legacyTimestampLayout = "2006-01-02 15:04:05"
+	// displayTimestampLayout 按客户端时区格式化的展示时间
+	displayTimestampLayout = "2006-01-02 15:04"
+	// maxTimezoneLength 时区名的最大长度（IANA 名称最长的也只有 30 多个字符）
+	maxTimezoneLength = 64
+)
+
+// ErrInvalidTimezone 客户端传入的时区不是有效的 IANA 时区名
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// WithLegacyPostTimestamps 是否继续填充旧格式的 created_at（默认开启）
+//
+// 为什么需要？
+// 旧格式没有时区：服务端和客户端不在同一个时区时，客户端显示的时间是错的。
+// 新协议改为 created_at_rfc3339（UTC）加上按客户端时区格式化的 created_at_display，
+// 但已经发布的客户端还在解析 created_at，所以旧字段先保留；
+// 所有客户端都升级之后关闭（post_timestamps.omit_legacy: true），created_at 返回空字符串。
+func WithLegacyPostTimestamps(enabled bool) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.legacyPostTimestamps = enabled
+	}
+}
+
+// postTimestamps 一条帖子发布时间的各种格式（对应 RPC Post 的三个字段）
+type postTimestamps struct {
+	legacy  string // created_at（关闭兼容字段后为空）
+	rfc3339 string // created_at_rfc3339
+	display string // created_at_display（客户端没有传时区时为空）
+}
+
+// formatPostTime 辅助方法：格式化帖子的发布时间
+//
+// 发布时间未知（内容服务返回的格式无法解析）时全部为空。
+func (h *RecommendationHandler) formatPostTime(createdAt time.Time, loc *time.Location) postTimestamps {
+	if createdAt.IsZero() {
+		return postTimestamps{}
+	}
+	ts := postTimestamps{rfc3339: createdAt.UTC().Format(time.RFC3339)}
+	if h.legacyPostTimestamps {
+		ts.legacy = createdAt.Local().Format(legacyTimestampLayout)
+	}
+	if loc != nil {
+		ts.display = createdAt.In(loc).Format(displayTimestampLayout)
+	}
+	return ts
+}
+
+// parseTimezone 辅助函数：解析客户端时区（为空时返回 nil，不生成展示时间）
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if len(name) > maxTimezoneLength {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
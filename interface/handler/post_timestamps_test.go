@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatPostTime(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC)
+	loc, err := parseTimezone("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &RecommendationHandler{legacyPostTimestamps: true}
+	ts := h.formatPostTime(createdAt, loc)
+	if ts.rfc3339 != "2024-06-01T23:30:00Z" || ts.display != "2024-06-02 07:30" {
+		t.Errorf("formatPostTime = %+v, want rfc3339 2024-06-01T23:30:00Z, display 2024-06-02 07:30", ts)
+	}
+	if ts.legacy != createdAt.Local().Format(legacyTimestampLayout) {
+		t.Errorf("legacy = %q, want server local time", ts.legacy)
+	}
+
+	// 关闭兼容字段、没有传时区：只有 RFC3339
+	h.legacyPostTimestamps = false
+	if ts := h.formatPostTime(createdAt, nil); ts.legacy != "" || ts.display != "" || ts.rfc3339 == "" {
+		t.Errorf("without legacy and timezone: %+v", ts)
+	}
+
+	// 发布时间未知时全部为空
+	if ts := h.formatPostTime(time.Time{}, loc); ts != (postTimestamps{}) {
+		t.Errorf("zero time: %+v, want empty", ts)
+	}
+
+	if _, err := parseTimezone("Mars/Olympus"); err != ErrInvalidTimezone {
+		t.Errorf("parseTimezone(Mars/Olympus) = %v, want ErrInvalidTimezone", err)
+	}
+}
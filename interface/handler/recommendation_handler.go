@@ -2,7 +2,12 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
 
 	"service/application/service"
 
@@ -57,22 +62,97 @@ import (
 // DDD 方式：Handler 只负责协议适配，业务逻辑在内层
 type RecommendationHandler struct {
 	recommendationService *service.RecommendationService
+	userDataService       *service.UserDataService
+	topicService          *service.TopicRecommendationService
+	groupService          *service.GroupRecommendationService
+	triggerService        *service.TriggerRecommendationService
+	authorizer            *service.Authorizer
+
+	// 可选依赖（通过 HandlerOption 注入）
+	loadShedder    *LoadShedder           // 负载保护（为 nil 时不限制）
+	abuseDetector  *AbuseDetector         // 异常请求检测（为 nil 时不检测）
+	priorityTiers  *service.PriorityTiers // 用户优先级（为 nil 时都是 standard）
+	trafficCapture *TrafficCapture        // 流量抓取（为 nil 时不抓取）
+	followService  *service.FollowService // 关注、取关（为 nil 时接口返回 ErrFollowUnavailable）
+
+	legacyPostTimestamps bool // 是否填充旧格式的帖子时间（兼容旧客户端，默认开启）
+}
+
+// HandlerOption 可选配置：为 Handler 注入可选能力
+//
+// 与应用层的 RecommendationServiceOption 一样：
+// 必需依赖放在构造函数参数里，可选能力通过 Option 开启。
+type HandlerOption func(*RecommendationHandler)
+
+// WithLoadShedder 开启负载保护：超出处理能力的请求在入口处直接拒绝（ErrOverloaded）
+func WithLoadShedder(shedder *LoadShedder) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.loadShedder = shedder
+	}
+}
+
+// WithAbuseDetector 开启异常请求检测：发现爬取式的请求模式时告警，可选地封禁调用方（ErrRateLimited）
+func WithAbuseDetector(detector *AbuseDetector) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.abuseDetector = detector
+	}
+}
+
+// WithPriorityTiers 按用户优先级区分新鲜度和负载保护
+//
+// - high：总是重新生成推荐；过载时可以排队，不会被降级为快照
+// - standard、low：过载被拒绝时，改为只使用最近的快照（没有快照时才返回 ErrOverloaded）
+func WithPriorityTiers(tiers *service.PriorityTiers) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.priorityTiers = tiers
+	}
 }
 
 // NewRecommendationHandler 构造函数
 func NewRecommendationHandler(
 	recommendationService *service.RecommendationService,
+	userDataService *service.UserDataService,
+	topicService *service.TopicRecommendationService,
+	groupService *service.GroupRecommendationService,
+	triggerService *service.TriggerRecommendationService,
+	authorizer *service.Authorizer,
+	opts ...HandlerOption,
 ) *RecommendationHandler {
-	return &RecommendationHandler{
+	h := &RecommendationHandler{
 		recommendationService: recommendationService,
+		userDataService:       userDataService,
+		topicService:          topicService,
+		groupService:          groupService,
+		triggerService:        triggerService,
+		authorizer:            authorizer,
+		legacyPostTimestamps:  true,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // GetFollowingBasedRecommendations RPC 方法实现
+//
+// 开启流量抓取时，成功的请求按比例采样记录（见 TrafficCapture）。
 func (h *RecommendationHandler) GetFollowingBasedRecommendations(
 	ctx context.Context,
 	req *recommendation.GetRecommendationsRequest,
 ) (*recommendation.GetRecommendationsResponse, error) {
+	start := time.Now()
+	resp, err := h.getFollowingBasedRecommendations(ctx, req)
+	if err == nil && h.trafficCapture != nil {
+		h.trafficCapture.Record(ctx, req, resp, time.Since(start))
+	}
+	return resp, err
+}
+
+// getFollowingBasedRecommendations GetFollowingBasedRecommendations 的实现
+func (h *RecommendationHandler) getFollowingBasedRecommendations(
+	ctx context.Context,
+	req *recommendation.GetRecommendationsRequest,
+) (*recommendation.GetRecommendationsResponse, error) {
 
 	// 参数验证
 	if req.UserId <= 0 {
@@ -81,61 +161,739 @@ func (h *RecommendationHandler) GetFollowingBasedRecommendations(
 	if req.Limit <= 0 {
 		req.Limit = 10 // 默认值
 	}
+	if len(req.GetSessionId()) > maxSessionIDLength {
+		return nil, ErrInvalidSessionID
+	}
+	loc, err := parseTimezone(req.GetTimezone())
+	if err != nil {
+		return nil, err
+	}
+	filter, err := convertFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	// 授权：只能查询自己的推荐（白名单中的内部服务除外）
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调试请求带打分细节：和推荐解释接口一样，只允许质量评审工具调用
+	if req.GetDebug() {
+		if err := h.authorizer.AuthorizeQualityReview(ctx); err != nil {
+			return nil, toAuthError(err)
+		}
+	}
+
+	// 异常请求检测：在授权之后（此时调用方身份已确认）
+	if h.abuseDetector != nil {
+		principal, _ := service.PrincipalFromContext(ctx)
+		size := int(req.Limit)
+		if req.GetPageSize() > 0 {
+			size = int(req.GetPageSize()) // 分页请求的 page_size 代替 limit
+		}
+		if err := h.abuseDetector.Check(ctx, principal, req.UserId, size); err != nil {
+			return nil, err
+		}
+	}
+
+	// 用户优先级：决定新鲜度要求和负载保护的先后
+	tier := service.PriorityStandard
+	if h.priorityTiers != nil {
+		tier = h.priorityTiers.Resolve(ctx, req.UserId)
+	}
+	freshness := tier.Freshness()
+
+	// 负载保护：在访问数据库和下游服务之前拒绝超出处理能力的请求
+	// 开启优先级时，被拒绝的非高优先级请求改为只使用快照（不占用名额：不召回、不排序）
+	if h.loadShedder != nil {
+		release, err := h.loadShedder.AcquireTier(ctx, tier)
+		switch {
+		case err == nil:
+			defer release()
+		case errors.Is(err, ErrOverloaded) && h.priorityTiers != nil && tier != service.PriorityHigh:
+			freshness = dto.FreshnessCachedOnly
+		default:
+			return nil, err
+		}
+	}
 
 	// 调用应用服务
 	result, err := h.recommendationService.GetFollowingBasedRecommendations(
 		ctx,
-		req.UserId,
-		int(req.Limit),
+		&dto.RecommendationRequest{
+			UserID:         req.UserId,
+			Limit:          int(req.Limit),
+			Surface:        dto.Surface(req.GetSurface()),
+			KnownVersion:   knownVersion(ctx, req),
+			SinceVersion:   req.GetSinceVersion(),
+			SinceTimestamp: sinceTimestamp(req),
+			Freshness:      freshness,
+			SessionID:      req.GetSessionId(),
+			Days:           int(req.GetDay()),
+			Filter:         filter,
+			PageToken:      req.GetPageToken(),
+			PageSize:       int(req.GetPageSize()),
+			Debug:          req.GetDebug(),
+		},
 	)
+	if errors.Is(err, service.ErrNoPrecomputedRecommendations) {
+		return nil, ErrOverloaded
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// 转换为 RPC 响应
-	res := h.convertToRPCResponse(result)
+	res := h.convertToRPCResponse(result, loc)
 	return res, nil
 }
 
-// convertToRPCResponse 辅助方法：DTO -> RPC 响应转换
+// DeleteUserData RPC 方法实现：删除用户数据（内部接口）
+//
+// 不经过负载保护：删除请求量很小，而且必须完成（有合规时限）。
+// 返回错误时调用方重试，删除是幂等的。
+func (h *RecommendationHandler) DeleteUserData(
+	ctx context.Context,
+	req *recommendation.DeleteUserDataRequest,
+) (*recommendation.DeleteUserDataResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权：只允许隐私团队的工具调用
+	if err := h.authorizer.AuthorizePrivacyOperation(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	if err := h.userDataService.DeleteUserData(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	return &recommendation.DeleteUserDataResponse{
+		UserId:    req.UserId,
+		RequestId: req.GetRequestId(),
+	}, nil
+}
+
+// ExportUserData RPC 方法实现：导出用户数据（内部接口）
+//
+// 和 DeleteUserData 一样不经过负载保护，只允许隐私团队的工具调用。
+func (h *RecommendationHandler) ExportUserData(
+	ctx context.Context,
+	req *recommendation.ExportUserDataRequest,
+) (*recommendation.ExportUserDataResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权：只允许隐私团队的工具调用
+	if err := h.authorizer.AuthorizePrivacyOperation(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	export, err := h.userDataService.ExportUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// DTO -> JSON
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recommendation.ExportUserDataResponse{
+		UserId:    req.UserId,
+		RequestId: req.GetRequestId(),
+		Data:      string(data),
+	}, nil
+}
+
+// GetRecommendationHistory RPC 方法实现：查询展示过的推荐
+//
+// 授权规则和推荐请求相同：只能查询自己的历史（白名单中的内部服务除外，如客服工具）。
+// 只读曝光记录，不召回、不排序，不经过负载保护。
+func (h *RecommendationHandler) GetRecommendationHistory(
+	ctx context.Context,
+	req *recommendation.GetRecommendationHistoryRequest,
+) (*recommendation.GetRecommendationHistoryResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	query := &dto.RecommendationHistoryRequest{
+		UserID:    req.UserId,
+		PageToken: req.GetPageToken(),
+		Limit:     int(req.GetLimit()),
+	}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+	history, err := h.recommendationService.GetRecommendationHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetRecommendationHistoryResponse{
+		Items:         make([]*recommendation.RecommendationHistoryItem, 0, len(history.Items)),
+		NextPageToken: history.NextPageToken,
+	}
+	for _, item := range history.Items {
+		resp.Items = append(resp.Items, &recommendation.RecommendationHistoryItem{
+			RecommendationId: item.RecommendationID,
+			UserId:           item.UserID,
+			Username:         item.Username,
+			Avatar:           item.Avatar,
+			Surface:          item.Surface,
+			ShownAt:          item.ShownAt,
+		})
+	}
+	return resp, nil
+}
+
+// GetSuggestedPosts RPC 方法实现：探索页的帖子推荐
+//
+// 授权规则和推荐请求相同。帖子推荐同样要召回候选，经过负载保护
+// （没有快照可以降级，过载时直接拒绝）。
+func (h *RecommendationHandler) GetSuggestedPosts(
+	ctx context.Context,
+	req *recommendation.GetSuggestedPostsRequest,
+) (*recommendation.GetSuggestedPostsResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	loc, err := parseTimezone(req.GetTimezone())
+	if err != nil {
+		return nil, err
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 负载保护
+	if h.loadShedder != nil {
+		tier := service.PriorityStandard
+		if h.priorityTiers != nil {
+			tier = h.priorityTiers.Resolve(ctx, req.UserId)
+		}
+		release, err := h.loadShedder.AcquireTier(ctx, tier)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 调用应用服务
+	result, err := h.recommendationService.GetSuggestedPosts(ctx, &dto.PostRecommendationRequest{
+		UserID:  req.UserId,
+		Limit:   int(req.GetLimit()),
+		Surface: dto.Surface(req.GetSurface()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetSuggestedPostsResponse{
+		Posts: make([]*recommendation.SuggestedPost, 0, len(result.Posts)),
+	}
+	for _, post := range result.Posts {
+		createdAt := h.formatPostTime(post.CreatedAt, loc)
+		resp.Posts = append(resp.Posts, &recommendation.SuggestedPost{
+			RecommendationId: post.RecommendationID,
+			PostId:           post.PostID,
+			AuthorId:         post.AuthorID,
+			AuthorUsername:   post.AuthorUsername,
+			AuthorAvatar:     post.AuthorAvatar,
+			Content:          post.Content,
+			CreatedAt:        createdAt.legacy,
+			Masked:           post.Masked,
+			Reason:           post.Reason,
+			Score:            int32(post.Score),
+			CreatedAtRfc3339: createdAt.rfc3339,
+			CreatedAtDisplay: createdAt.display,
+		})
+	}
+	return resp, nil
+}
+
+// GetTopicRecommendations RPC 方法实现：话题推荐
+//
+// 授权规则和推荐请求相同；话题推荐只查询关注关系和话题，不经过负载保护。
+func (h *RecommendationHandler) GetTopicRecommendations(
+	ctx context.Context,
+	req *recommendation.GetTopicRecommendationsRequest,
+) (*recommendation.GetTopicRecommendationsResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	result, err := h.topicService.GetTopicRecommendations(ctx, &dto.TopicRecommendationRequest{
+		UserID: req.UserId,
+		Limit:  int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetTopicRecommendationsResponse{
+		Topics: make([]*recommendation.TopicRecommendation, 0, len(result.Topics)),
+	}
+	for _, topic := range result.Topics {
+		resp.Topics = append(resp.Topics, &recommendation.TopicRecommendation{
+			RecommendationId: topic.RecommendationID,
+			Topic:            topic.Topic,
+			Hashtag:          topic.Hashtag,
+			Reason:           topic.Reason,
+			Score:            int32(topic.Score),
+		})
+	}
+	return resp, nil
+}
+
+// GetGroupRecommendations RPC 方法实现：小组推荐
+//
+// 授权规则和推荐请求相同；和话题推荐一样不经过负载保护。
+func (h *RecommendationHandler) GetGroupRecommendations(
+	ctx context.Context,
+	req *recommendation.GetGroupRecommendationsRequest,
+) (*recommendation.GetGroupRecommendationsResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	result, err := h.groupService.GetGroupRecommendations(ctx, &dto.GroupRecommendationRequest{
+		UserID: req.UserId,
+		Limit:  int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetGroupRecommendationsResponse{
+		Groups: make([]*recommendation.GroupRecommendation, 0, len(result.Groups)),
+	}
+	for _, group := range result.Groups {
+		resp.Groups = append(resp.Groups, &recommendation.GroupRecommendation{
+			RecommendationId: group.RecommendationID,
+			GroupId:          group.GroupID,
+			MemberCount:      int32(group.MemberCount),
+			Reason:           group.Reason,
+			Score:            int32(group.Score),
+		})
+	}
+	return resp, nil
+}
+
+// MetaKeyIfNoneMatch HTTP 网关透传 If-None-Match 请求头的 metainfo key
+const MetaKeyIfNoneMatch = "if-none-match"
+
+// knownVersion 辅助方法：客户端缓存的列表版本
+//
+// 优先使用请求中的 known_version；没有时使用网关透传的 If-None-Match
+// （HTTP 客户端把上次响应的 ETag 原样带回，可能是 W/"v1-..." 的形式）。
+// 网关把响应的 version 作为 ETag 返回，not_modified 时返回 304。
+func knownVersion(ctx context.Context, req *recommendation.GetRecommendationsRequest) string {
+	if v := req.GetKnownVersion(); v != "" {
+		return v
+	}
+	etag, _ := metainfo.GetPersistentValue(ctx, MetaKeyIfNoneMatch)
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), `"`)
+}
+
+// sinceTimestamp 辅助方法：上次同步的时间（没有时为零值）
+func sinceTimestamp(req *recommendation.GetRecommendationsRequest) time.Time {
+	if ts := req.GetSinceTimestamp(); ts > 0 {
+		return time.Unix(ts, 0)
+	}
+	return time.Time{}
+}
+
+// GetTriggerRecommendations RPC 方法实现：推送管道拉取触发式推荐
+//
+// 授权规则和推荐请求相同（推送管道在代查白名单中）；只读取存储，不经过负载保护。
+func (h *RecommendationHandler) GetTriggerRecommendations(
+	ctx context.Context,
+	req *recommendation.GetTriggerRecommendationsRequest,
+) (*recommendation.GetTriggerRecommendationsResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	result, err := h.triggerService.GetTriggerRecommendations(ctx, &dto.TriggerRecommendationRequest{
+		UserID: req.UserId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetTriggerRecommendationsResponse{
+		Recommendations: make([]*recommendation.TriggerRecommendation, 0, len(result.Recommendations)),
+	}
+	for _, rec := range result.Recommendations {
+		resp.Recommendations = append(resp.Recommendations, &recommendation.TriggerRecommendation{
+			UserId:    rec.UserID,
+			Trigger:   rec.Trigger,
+			Reason:    rec.Reason,
+			Score:     int32(rec.Score),
+			ExpiresAt: rec.ExpiresAt,
+		})
+	}
+	return resp, nil
+}
+
+// ExplainRecommendation RPC 方法实现：解释一条推荐的打分（内部接口）
+//
+// 只允许质量评审工具调用；要重新召回候选，和推荐请求一样经过负载保护（低优先级）。
+func (h *RecommendationHandler) ExplainRecommendation(
+	ctx context.Context,
+	req *recommendation.ExplainRecommendationRequest,
+) (*recommendation.ExplainRecommendationResponse, error) {
+
+	// 参数验证
+	if req.ViewerId <= 0 || req.TargetUserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权：只允许质量评审工具调用
+	if err := h.authorizer.AuthorizeQualityReview(ctx); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 负载保护：评审工具可以等，过载时先拒绝它
+	if h.loadShedder != nil {
+		release, err := h.loadShedder.AcquireTier(ctx, service.PriorityLow)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 调用应用服务
+	result, err := h.recommendationService.ExplainRecommendation(ctx, &dto.ExplainRecommendationRequest{
+		ViewerID:     req.ViewerId,
+		TargetUserID: req.TargetUserId,
+		Surface:      dto.Surface(req.GetSurface()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.ExplainRecommendationResponse{
+		Found:              result.Found,
+		ReasonType:         result.ReasonType,
+		Position:           int32(result.Position),
+		Candidates:         int32(result.Candidates),
+		Score:              int32(result.Score),
+		Factors:            convertScoreFactorsToRPC(result.Factors),
+		StrategyWeight:     result.StrategyWeight,
+		CalibratedScore:    result.CalibratedScore,
+		RulesVersion:       result.RulesVersion,
+		PerRelatedUser:     int32(result.PerRelatedUser),
+		PerRecentPost:      int32(result.PerRecentPost),
+		CalibrationVersion: result.CalibrationVersion,
+		FiltersPassed:      result.FiltersPassed,
+	}
+	return resp, nil
+}
+
+// GetHybridRecommendations RPC 方法实现：混合推荐（关注推荐 + 热度推荐）
+//
+// 授权规则和推荐请求相同；两个策略都要召回候选，经过负载保护（没有快照可以降级，过载时直接拒绝）。
+func (h *RecommendationHandler) GetHybridRecommendations(
+	ctx context.Context,
+	req *recommendation.GetHybridRecommendationsRequest,
+) (*recommendation.GetRecommendationsResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	loc, err := parseTimezone(req.GetTimezone())
+	if err != nil {
+		return nil, err
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 负载保护
+	if h.loadShedder != nil {
+		tier := service.PriorityStandard
+		if h.priorityTiers != nil {
+			tier = h.priorityTiers.Resolve(ctx, req.UserId)
+		}
+		release, err := h.loadShedder.AcquireTier(ctx, tier)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 调用应用服务
+	result, err := h.recommendationService.GetHybridRecommendations(ctx, &dto.RecommendationRequest{
+		UserID:  req.UserId,
+		Limit:   int(req.GetLimit()),
+		Surface: dto.Surface(req.GetSurface()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	return h.convertToRPCResponse(result, loc), nil
+}
+
+// RecordRecommendationFeedback RPC 方法实现：记录推荐反馈（点开、在卡片上关注）
+//
+// 授权规则和推荐请求相同：只能为自己（或者有权限查询推荐的调用方代为）上报反馈。
+// 只是写入一条记录，不召回候选，不经过负载保护。
+func (h *RecommendationHandler) RecordRecommendationFeedback(
+	ctx context.Context,
+	req *recommendation.RecordRecommendationFeedbackRequest,
+) (*recommendation.RecordRecommendationFeedbackResponse, error) {
+
+	// 参数验证（推荐ID、反馈类型由应用服务验证）
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 调用应用服务
+	if err := h.recommendationService.RecordRecommendationFeedback(ctx, req.UserId, req.RecommendationId, req.Action); err != nil {
+		return nil, err
+	}
+	return &recommendation.RecordRecommendationFeedbackResponse{}, nil
+}
+
+// GetRecommendationBundle RPC 方法实现：发现页的分栏推荐
+//
+// 授权规则和推荐请求相同；分栏推荐同样要召回候选，经过负载保护。
+func (h *RecommendationHandler) GetRecommendationBundle(
+	ctx context.Context,
+	req *recommendation.GetRecommendationBundleRequest,
+) (*recommendation.GetRecommendationBundleResponse, error) {
+
+	// 参数验证
+	if req.UserId <= 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// 授权
+	if err := h.authorizer.AuthorizeRecommendations(ctx, req.UserId); err != nil {
+		return nil, toAuthError(err)
+	}
+
+	// 负载保护
+	if h.loadShedder != nil {
+		tier := service.PriorityStandard
+		if h.priorityTiers != nil {
+			tier = h.priorityTiers.Resolve(ctx, req.UserId)
+		}
+		release, err := h.loadShedder.AcquireTier(ctx, tier)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 调用应用服务
+	result, err := h.recommendationService.GetRecommendationBundle(ctx, &dto.RecommendationBundleRequest{
+		UserID: req.UserId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为 RPC 响应
+	resp := &recommendation.GetRecommendationBundleResponse{
+		Sections: make([]*recommendation.RecommendationSection, 0, len(result.Sections)),
+		Degraded: result.Degraded,
+	}
+	for _, section := range result.Sections {
+		recs := make([]*recommendation.UserRecommendation, 0, len(section.Recommendations))
+		for _, rec := range section.Recommendations {
+			recs = append(recs, h.convertRecommendationToRPC(rec, nil))
+		}
+		resp.Sections = append(resp.Sections, &recommendation.RecommendationSection{
+			Key:             section.Key,
+			Title:           section.Title,
+			Recommendations: recs,
+		})
+	}
+	return resp, nil
+}
+
+// convertToRPCResponse 辅助方法：DTO -> RPC 响应转换（loc 为客户端时区，可以为 nil）
 func (h *RecommendationHandler) convertToRPCResponse(
 	dto *dto.RecommendationResponse,
+	loc *time.Location,
 ) *recommendation.GetRecommendationsResponse {
 	resp := &recommendation.GetRecommendationsResponse{
 		Recommendations: make([]*recommendation.UserRecommendation, 0, len(dto.Recommendations)),
+		Degraded:        dto.Degraded,
+		Version:         dto.Version,
+		NotModified:     dto.NotModified,
+		Delta:           dto.Delta,
+		Precomputed:     dto.Precomputed,
+		NextCursor:      dto.NextCursor,
+		HasMore:         dto.HasMore,
+	}
+	for _, tombstone := range dto.Tombstones {
+		resp.Tombstones = append(resp.Tombstones, &recommendation.Tombstone{
+			UserId: tombstone.UserID,
+			Reason: tombstone.Reason,
+		})
+	}
+	if dto.Metadata != nil {
+		resp.Metadata = &recommendation.ResponseMetadata{
+			FollowingsSampled:  dto.Metadata.FollowingsSampled,
+			FollowingsTotal:    int32(dto.Metadata.FollowingsTotal),
+			FollowingsUsed:     int32(dto.Metadata.FollowingsUsed),
+			CalibrationVersion: dto.Metadata.CalibrationVersion,
+			Experiment:         dto.Metadata.Experiment,
+			Variant:            dto.Metadata.Variant,
+		}
 	}
 
 	for _, rec := range dto.Recommendations {
-		rpcRec := &recommendation.UserRecommendation{
-			UserId:      rec.UserID,
-			Username:    rec.Username,
-			Avatar:      rec.Avatar,
-			Bio:         rec.Bio,
-			Reason:      rec.Reason,
-			Score:       int32(rec.Score),
-			RecentPosts: h.convertPostsToRPC(rec.RecentPosts),
-		}
-		resp.Recommendations = append(resp.Recommendations, rpcRec)
+		resp.Recommendations = append(resp.Recommendations, h.convertRecommendationToRPC(rec, loc))
 	}
 
 	return resp
 }
 
+// convertRecommendationToRPC 辅助方法：UserRecommendationDTO -> RPC UserRecommendation 转换
+func (h *RecommendationHandler) convertRecommendationToRPC(
+	rec *dto.UserRecommendationDTO,
+	loc *time.Location,
+) *recommendation.UserRecommendation {
+	return &recommendation.UserRecommendation{
+		UserId:          rec.UserID,
+		Username:        rec.Username,
+		Avatar:          rec.Avatar,
+		Bio:             rec.Bio,
+		Reason:          rec.Reason,
+		Score:           int32(rec.Score),
+		RecentPosts:     h.convertPostsToRPC(rec.RecentPosts, loc),
+		CalibratedScore: rec.CalibratedScore,
+		ProfilePending:  rec.ProfilePending,
+		Status:          string(rec.Status()),
+		Degraded:        rec.Degraded,
+		ScoreDetails:    convertScoreDetailsToRPC(rec.ScoreDetails),
+		ReasonType:      rec.ReasonType,
+	}
+}
+
+// convertScoreDetailsToRPC 辅助函数：ScoreDetailsDTO -> RPC ScoreDetails 转换（nil 表示没有明细）
+func convertScoreDetailsToRPC(details *dto.ScoreDetailsDTO) *recommendation.ScoreDetails {
+	if details == nil {
+		return nil
+	}
+	return &recommendation.ScoreDetails{
+		Factors:              convertScoreFactorsToRPC(details.Factors),
+		RulesVersion:         details.RulesVersion,
+		PerRelatedUser:       int32(details.PerRelatedUser),
+		PerRecentPost:        int32(details.PerRecentPost),
+		PerHundredEngagement: int32(details.PerHundredEngagement),
+	}
+}
+
+// convertScoreFactorsToRPC 辅助函数：ScoreFactorDTO -> RPC ScoreFactor 转换
+func convertScoreFactorsToRPC(factors []*dto.ScoreFactorDTO) []*recommendation.ScoreFactor {
+	result := make([]*recommendation.ScoreFactor, 0, len(factors))
+	for _, factor := range factors {
+		result = append(result, &recommendation.ScoreFactor{
+			Name:         factor.Name,
+			Value:        int32(factor.Value),
+			Contribution: int32(factor.Contribution),
+		})
+	}
+	return result
+}
+
 // convertPostsToRPC 辅助方法：PostDTO -> RPC Post 转换
 func (h *RecommendationHandler) convertPostsToRPC(
 	posts []*dto.PostDTO,
+	loc *time.Location,
 ) []*recommendation.Post {
 	result := make([]*recommendation.Post, 0, len(posts))
 	for _, post := range posts {
+		createdAt := h.formatPostTime(post.CreatedAt, loc)
 		result = append(result, &recommendation.Post{
-			PostId:    post.PostID,
-			Content:   post.Content,
-			CreatedAt: post.CreatedAt,
+			PostId:           post.PostID,
+			Content:          post.Content,
+			CreatedAt:        createdAt.legacy,
+			Masked:           post.Masked,
+			CreatedAtRfc3339: createdAt.rfc3339,
+			CreatedAtDisplay: createdAt.display,
 		})
 	}
 	return result
 }
 
+// maxSessionIDLength 会话ID的最大长度（会话ID是缓存 key 的一部分）
+const maxSessionIDLength = 64
+
 var (
-	ErrInvalidUserID = errors.New("invalid user id")
+	ErrInvalidUserID     = errors.New("invalid user id")
+	ErrInvalidExperiment = errors.New("invalid experiment")
+	ErrInvalidSessionID  = errors.New("invalid session id")
 )
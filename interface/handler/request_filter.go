@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+
+	"service/application/dto"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+const (
+	// maxFilterReasonTypes 过滤条件最多指定多少种理由类型
+	maxFilterReasonTypes = 10
+	// maxFilterExcludedUsers 过滤条件最多排除多少个用户（更长的名单应该做成服务端的过滤器）
+	maxFilterExcludedUsers = 500
+)
+
+// ErrInvalidFilter 过滤条件不合法（分数为负、数量超过上限、用户ID无效）
+var ErrInvalidFilter = errors.New("invalid recommendation filter")
+
+// convertFilter 辅助函数：验证并转换请求的过滤条件（没有时返回 nil）
+//
+// 理由类型是否存在由应用服务判断（service.ErrUnknownReasonType）。
+func convertFilter(filter *recommendation.RecommendationFilter) (*dto.RecommendationFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	if filter.MinScore < 0 ||
+		len(filter.ReasonTypes) > maxFilterReasonTypes ||
+		len(filter.ExcludeUserIds) > maxFilterExcludedUsers {
+		return nil, ErrInvalidFilter
+	}
+	for _, reasonType := range filter.ReasonTypes {
+		if reasonType == "" {
+			return nil, ErrInvalidFilter
+		}
+	}
+	for _, userID := range filter.ExcludeUserIds {
+		if userID <= 0 {
+			return nil, ErrInvalidFilter
+		}
+	}
+	return &dto.RecommendationFilter{
+		MinScore:       int(filter.MinScore),
+		ReasonTypes:    filter.ReasonTypes,
+		ExcludeUserIDs: filter.ExcludeUserIds,
+	}, nil
+}
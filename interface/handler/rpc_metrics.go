@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+
+	"service/application/service"
+)
+
+// RPCMetrics Kitex 服务端中间件：按 RPC 方法记录请求耗时（MetricRPCDuration）
+//
+// 放在最外层（第一个 WithMiddleware），认证、租户识别、负载保护拒绝的请求也会被统计。
+//
+// 结果分三类：
+// - ok：正常返回
+// - biz_error：业务错误（BizStatusError，如未授权、过载），Kitex 把它放在 rpcinfo 中，中间件看到的 err 为 nil
+// - error：其他错误（超时、panic、编解码失败等）
+//
+// 使用方式：
+//
+//	recommendationservice.NewServer(h, server.WithMiddleware(rpcMetrics.Middleware))
+type RPCMetrics struct {
+	metrics service.Metrics
+	now     func() time.Time
+}
+
+// NewRPCMetrics 构造函数
+func NewRPCMetrics(metrics service.Metrics) *RPCMetrics {
+	return &RPCMetrics{metrics: metrics, now: time.Now}
+}
+
+// Middleware Kitex 中间件
+func (m *RPCMetrics) Middleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		start := m.now()
+		err := next(ctx, req, resp)
+		method, result := "unknown", "ok"
+		if ri := rpcinfo.GetRPCInfo(ctx); ri != nil {
+			if ri.To() != nil {
+				method = ri.To().Method()
+			}
+			if ri.Invocation() != nil && ri.Invocation().BizStatusErr() != nil {
+				result = "biz_error"
+			}
+		}
+		if _, ok := kerrors.FromBizStatusError(err); ok {
+			result = "biz_error" // 中间件（认证、租户识别）直接返回的业务错误
+		} else if err != nil {
+			result = "error"
+		}
+		m.metrics.ObserveHistogram(service.MetricRPCDuration, m.now().Sub(start).Seconds(), map[string]string{
+			"method": method,
+			"result": result,
+		})
+		return err
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// MetaKeyTenantID 网关 / BFF 透传租户（App 品牌）标识的 metainfo key
+const MetaKeyTenantID = "tenant-id"
+
+// ErrCodeUnknownTenant 租户没有配置的错误码
+const ErrCodeUnknownTenant int32 = 400
+
+// ErrUnknownTenant 租户没有配置（和 ErrUnauthenticated 一样通过 BizStatusError 返回）
+var ErrUnknownTenant = kerrors.NewBizStatusError(ErrCodeUnknownTenant, "unknown tenant")
+
+// TenantResolver Kitex 服务端中间件：识别请求的租户，放进 ctx
+//
+// 租户只在接口层识别一次，之后随 ctx 传到每一层（缓存 key、数据库查询、打分权重、文案语言）。
+// 没有带租户标识的请求属于默认租户；带了但没有配置的租户直接拒绝，
+// 避免写错的租户标识悄悄产生一份独立的数据。
+//
+// 使用方式：
+//
+//	recommendationservice.NewServer(h, server.WithMiddleware(resolver.Middleware))
+type TenantResolver struct {
+	tenants *service.Tenants
+}
+
+// NewTenantResolver 构造函数
+func NewTenantResolver(tenants *service.Tenants) *TenantResolver {
+	return &TenantResolver{tenants: tenants}
+}
+
+// Middleware Kitex 中间件
+func (r *TenantResolver) Middleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		raw, _ := metainfo.GetPersistentValue(ctx, MetaKeyTenantID)
+		tenant, err := r.tenants.Resolve(raw)
+		if err != nil {
+			return ErrUnknownTenant
+		}
+		return next(valueobject.WithTenant(ctx, tenant), req, resp)
+	}
+}
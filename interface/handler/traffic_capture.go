@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"time"
+
+	"service/domain/valueobject"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+// CapturedExchange 抓取的一次推荐请求和响应（已脱敏）
+//
+// 回放时用 Request 重新请求候选版本，和 Response 比较。
+type CapturedExchange struct {
+	CapturedAt time.Time                                  `json:"captured_at"`
+	Tenant     string                                     `json:"tenant"`
+	LatencyMS  int64                                      `json:"latency_ms"`
+	Request    *recommendation.GetRecommendationsRequest  `json:"request"`
+	Response   *recommendation.GetRecommendationsResponse `json:"response"`
+}
+
+// CaptureSink 抓取结果的去处（本地文件、消息队列的 topic，实现在基础设施层）
+type CaptureSink interface {
+	// Write 写入一条记录（JSON 格式的 CapturedExchange）
+	Write(ctx context.Context, record []byte) error
+}
+
+// TrafficCapture 流量抓取：按比例采样推荐请求，脱敏后写入 CaptureSink
+//
+// 为什么需要？
+// 线上反馈的排序问题几乎无法复现：本地没有那个用户的关注关系，
+// 也不知道请求带了什么参数、当时返回了什么。
+// 抓取一部分真实的请求和响应，就可以用回放工具（interface/replay）
+// 对候选版本重新请求，逐条比较排序的差异。
+//
+// 脱敏：
+// 推荐对象的昵称、头像、简介和帖子内容不写入（回放只比较推荐了谁、排第几、分数和理由）；
+// 用户ID保留（回放需要用它重新生成推荐），抓取文件按用户数据的权限管理。
+//
+// 写入在后台进行，不占用请求时间；缓冲区满时丢弃（抓取是调试手段，不能影响线上请求）。
+type TrafficCapture struct {
+	sink       CaptureSink
+	sampleRate float64
+	random     func() float64
+	queue      chan *CapturedExchange
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewTrafficCapture 构造函数（启动后台写入）
+//
+// 参数：
+// - sink: 抓取结果的去处
+// - sampleRate: 采样比例（0~1）
+// - bufferSize: 等待写入的最大条数
+func NewTrafficCapture(sink CaptureSink, sampleRate float64, bufferSize int) *TrafficCapture {
+	c := &TrafficCapture{
+		sink:       sink,
+		sampleRate: sampleRate,
+		random:     rand.Float64,
+		queue:      make(chan *CapturedExchange, max(bufferSize, 1)),
+		done:       make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// WithTrafficCapture 开启流量抓取（为 nil 时不抓取）
+func WithTrafficCapture(capture *TrafficCapture) HandlerOption {
+	return func(h *RecommendationHandler) {
+		h.trafficCapture = capture
+	}
+}
+
+// Record 采样记录一次成功的请求（没有被采样时什么都不做）
+//
+// 失败的请求（参数错误、授权失败、过载）不记录：回放比较的是推荐结果。
+func (c *TrafficCapture) Record(
+	ctx context.Context,
+	req *recommendation.GetRecommendationsRequest,
+	resp *recommendation.GetRecommendationsResponse,
+	latency time.Duration,
+) {
+	if c.random() >= c.sampleRate {
+		return
+	}
+	request := *req
+	exchange := &CapturedExchange{
+		CapturedAt: time.Now(),
+		Tenant:     valueobject.TenantFromContext(ctx).String(),
+		LatencyMS:  latency.Milliseconds(),
+		Request:    &request,
+		Response:   sanitizeResponse(resp),
+	}
+	select {
+	case c.queue <- exchange:
+	default:
+	}
+}
+
+// Close 停止抓取，等待缓冲区中的记录写完
+func (c *TrafficCapture) Close() {
+	c.closeOnce.Do(func() { close(c.queue) })
+	<-c.done
+}
+
+// run 后台写入（写入失败的记录直接丢弃）
+func (c *TrafficCapture) run() {
+	defer close(c.done)
+	for exchange := range c.queue {
+		record, err := json.Marshal(exchange)
+		if err != nil {
+			continue
+		}
+		_ = c.sink.Write(context.Background(), record)
+	}
+}
+
+// sanitizeResponse 辅助函数：复制响应，去掉推荐对象的个人资料和帖子内容
+func sanitizeResponse(resp *recommendation.GetRecommendationsResponse) *recommendation.GetRecommendationsResponse {
+	if resp == nil {
+		return nil
+	}
+	sanitized := *resp
+	sanitized.Recommendations = make([]*recommendation.UserRecommendation, 0, len(resp.Recommendations))
+	for _, rec := range resp.Recommendations {
+		posts := make([]*recommendation.Post, 0, len(rec.RecentPosts))
+		for _, post := range rec.RecentPosts {
+			posts = append(posts, &recommendation.Post{
+				PostId:           post.PostId,
+				CreatedAt:        post.CreatedAt,
+				Masked:           post.Masked,
+				CreatedAtRfc3339: post.CreatedAtRfc3339,
+				CreatedAtDisplay: post.CreatedAtDisplay,
+			})
+		}
+		sanitized.Recommendations = append(sanitized.Recommendations, &recommendation.UserRecommendation{
+			UserId:      rec.UserId,
+			Reason:      rec.Reason,
+			Score:       rec.Score,
+			RecentPosts: posts,
+		})
+	}
+	return &sanitized
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// 用户活动事件的类型（只列出触发式推荐关心的类型）
+const (
+	ActivityMilestoneReached        = "milestone_reached"
+	ActivityReturnedAfterInactivity = "returned_after_inactivity"
+)
+
+// UserActivityEvent 用户活动事件（由用户增长团队通过消息队列发布）
+type UserActivityEvent struct {
+	EventID      string    `json:"event_id"`
+	Type         string    `json:"type"`
+	UserID       int64     `json:"user_id"`
+	Milestone    int       `json:"milestone,omitempty"`     // milestone_reached：达到的粉丝数
+	InactiveDays int       `json:"inactive_days,omitempty"` // returned_after_inactivity：不活跃的天数
+	OccurredAt   time.Time `json:"occurred_at"`
+	TenantID     string    `json:"tenant_id,omitempty"` // 为空表示默认租户
+}
+
+// UserActivityEventHandler 消息处理器：消费用户活动事件，生成触发式推荐
+//
+// 和 UserDeletionEventHandler 一样只负责把消息转换为应用服务调用
+// （TriggerRecommendationService.HandleTrigger）。
+//
+// 同一个 topic 上还有其他类型的活动事件，不关心的类型直接确认（不重试、不进死信队列）。
+type UserActivityEventHandler struct {
+	triggerService *service.TriggerRecommendationService
+}
+
+// NewUserActivityEventHandler 构造函数
+func NewUserActivityEventHandler(triggerService *service.TriggerRecommendationService) *UserActivityEventHandler {
+	return &UserActivityEventHandler{
+		triggerService: triggerService,
+	}
+}
+
+// HandleMessage 处理一条消息（消息体为 JSON 格式的 UserActivityEvent）
+//
+// 返回错误时消费者应该重试（同一条推荐重复写入只是覆盖）；
+// 格式错误的消息重试也不会成功，应由消费者转入死信队列。
+func (h *UserActivityEventHandler) HandleMessage(ctx context.Context, payload []byte) error {
+	var event UserActivityEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("decode user activity event: %w", err)
+	}
+	return h.HandleEvent(ctx, event)
+}
+
+// HandleEvent 处理一个活动事件
+func (h *UserActivityEventHandler) HandleEvent(ctx context.Context, event UserActivityEvent) error {
+	var trigger dto.TriggerType
+	switch event.Type {
+	case ActivityMilestoneReached:
+		trigger = dto.TriggerMilestone
+	case ActivityReturnedAfterInactivity:
+		trigger = dto.TriggerReturned
+	default:
+		return nil
+	}
+	if event.UserID <= 0 {
+		return ErrInvalidUserID
+	}
+	if event.TenantID != "" {
+		tenant, err := valueobject.NewTenantID(event.TenantID)
+		if err != nil {
+			return fmt.Errorf("handle user activity event %s: %w", event.EventID, err)
+		}
+		ctx = valueobject.WithTenant(ctx, tenant)
+	}
+	_, err := h.triggerService.HandleTrigger(ctx, &dto.TriggerEventRequest{
+		Type:          trigger,
+		SubjectUserID: event.UserID,
+		Milestone:     event.Milestone,
+		InactiveDays:  event.InactiveDays,
+		OccurredAt:    event.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("handle user activity event %s: %w", event.EventID, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// UserDeletionEvent 用户数据删除事件（由隐私团队通过消息队列发布）
+type UserDeletionEvent struct {
+	UserID      int64     `json:"user_id"`
+	RequestID   string    `json:"request_id"` // 删除工单号
+	RequestedAt time.Time `json:"requested_at"`
+	TenantID    string    `json:"tenant_id,omitempty"` // 为空表示默认租户
+}
+
+// UserDeletionEventHandler 消息处理器：消费用户数据删除事件
+//
+// 和 RecommendationHandler 一样属于接口层：
+// 负责把消息队列的消息转换为应用服务调用，本身不包含删除逻辑。
+//
+// 与 DeleteUserData RPC 的关系：
+// 两个入口调用同一个用例（UserDataService.DeleteUserData）。
+// 隐私团队正常情况下发布事件，RPC 用于补偿和人工处理。
+type UserDeletionEventHandler struct {
+	userDataService *service.UserDataService
+}
+
+// NewUserDeletionEventHandler 构造函数
+func NewUserDeletionEventHandler(userDataService *service.UserDataService) *UserDeletionEventHandler {
+	return &UserDeletionEventHandler{
+		userDataService: userDataService,
+	}
+}
+
+// HandleMessage 处理一条消息（消息体为 JSON 格式的 UserDeletionEvent）
+//
+// 返回错误时消费者应该重试（删除是幂等的）；
+// 格式错误的消息重试也不会成功，应由消费者转入死信队列人工处理。
+func (h *UserDeletionEventHandler) HandleMessage(ctx context.Context, payload []byte) error {
+	var event UserDeletionEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("decode user deletion event: %w", err)
+	}
+	return h.HandleEvent(ctx, event)
+}
+
+// HandleEvent 处理一个删除事件
+func (h *UserDeletionEventHandler) HandleEvent(ctx context.Context, event UserDeletionEvent) error {
+	if event.UserID <= 0 {
+		return ErrInvalidUserID
+	}
+	if event.TenantID != "" {
+		tenant, err := valueobject.NewTenantID(event.TenantID)
+		if err != nil {
+			return fmt.Errorf("handle user deletion event %s: %w", event.RequestID, err)
+		}
+		ctx = valueobject.WithTenant(ctx, tenant)
+	}
+	if err := h.userDataService.DeleteUserData(ctx, event.UserID); err != nil {
+		return fmt.Errorf("handle user deletion event %s: %w", event.RequestID, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"service/application/service"
+)
+
+// 参数验证错误（返回 400）
+var (
+	ErrInvalidUserID    = errors.New("invalid user id")
+	ErrInvalidParameter = errors.New("invalid query parameter")
+)
+
+// 异常请求检测、负载保护拒绝的请求（和 RPC 的 handler.ErrRateLimited、handler.ErrOverloaded 对应）
+var (
+	ErrRateLimited = errors.New("too many requests")                 // 429
+	ErrOverloaded  = errors.New("recommendation service overloaded") // 503
+)
+
+// errorResponse 错误响应体
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// statusCode 辅助函数：应用层、参数验证的错误 → HTTP 状态码
+//
+// 和 RPC 的错误码（handler.itemErrorCode）一致：
+// 参数错误 400、未认证 401、无权限 403、限流 429、过载 503、超时 504，其余的都是 500（调用方可以重试）。
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidUserID), errors.Is(err, ErrInvalidParameter),
+		errors.Is(err, service.ErrInvalidRecencyDays), errors.Is(err, service.ErrUnknownReasonType),
+		errors.Is(err, service.ErrInvalidPageToken):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrOverloaded), errors.Is(err, service.ErrNoPrecomputedRecommendations):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// handleError 辅助方法：写入错误响应
+//
+// 500 不返回内部错误的细节（可能包含 SQL、下游地址），只记日志。
+func (s *Server) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusCode(err)
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		s.logger.Log(r.Context(), service.LogLevelError, "http request failed", map[string]any{
+			"path":             r.URL.Path,
+			service.FieldError: err.Error(),
+		})
+		message = http.StatusText(status)
+	}
+	writeError(w, status, message)
+}
+
+// writeError 辅助函数：写入 JSON 错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeJSON 辅助函数：写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"strings"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// 身份、租户、语言的请求头（和 RPC 的 metainfo key 对应，见 handler.MetaKeyAuthToken、handler.MetaKeyTenantID、handler.MetaKeyLocale）
+const (
+	HeaderAuthorization = "Authorization"   // "Bearer {签名身份令牌}"，由网关 / BFF 认证登录态之后签发
+	HeaderTenantID      = "X-Tenant-Id"     // 租户（App 品牌）标识，没有时为默认租户
+	HeaderLocale        = "Accept-Language" // 客户端语言，没有时使用租户配置的语言
+)
+
+// authenticate 中间件：识别调用方身份，放进 ctx（授权在应用层，见 service.Authorizer）
+//
+// 和 handler.Authenticator 相同，只信任能校验的身份：
+// - 内部服务（WithMutualTLS）：客户端证书中的 SPIFFE ID，按 mtls.clients 映射为服务名
+// - 内部服务（没有开启 mTLS）：签名身份令牌中的服务名
+// - 终端用户：签名身份令牌中的用户ID
+//
+// 没有可校验的身份、或令牌校验失败时返回 401，不进入后面的中间件。
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := s.principalFromRequest(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, service.ErrUnauthenticated.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(service.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// principalFromRequest 辅助方法：从请求中取出调用方身份，没有可校验的身份时返回 false
+func (s *Server) principalFromRequest(r *http.Request) (service.Principal, bool) {
+	var principal service.Principal
+	if header := r.Header.Get(HeaderAuthorization); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || s.tokens == nil {
+			return service.Principal{}, false
+		}
+		claims, err := s.tokens.Verify(token)
+		if err != nil {
+			return service.Principal{}, false
+		}
+		principal = claims
+	}
+	if s.peers != nil {
+		// 开启 mTLS 时服务名只来自证书，令牌中的服务名不使用
+		principal.Service = ""
+		if r.TLS != nil {
+			if id, ok := s.peers.PeerIDFromState(*r.TLS); ok {
+				principal.Service = cmp.Or(s.services[id], id)
+			}
+		}
+	}
+	return principal, principal.Service != "" || principal.UserID > 0
+}
+
+// resolveTenant 中间件：识别请求的租户，放进 ctx（没有配置的租户返回 400，和 handler.TenantResolver 相同）
+func (s *Server) resolveTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := s.tenants.Resolve(r.Header.Get(HeaderTenantID))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unknown tenant")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(valueobject.WithTenant(r.Context(), tenant)))
+	})
+}
+
+// resolveLocale 中间件：识别请求的语言，放进 ctx（和 handler.LocaleMiddleware 相同）
+func (s *Server) resolveLocale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := valueobject.ParseLocale(r.Header.Get(HeaderLocale))
+		next.ServeHTTP(w, r.WithContext(valueobject.WithLocale(r.Context(), locale)))
+	})
+}
+
+// protect 辅助方法：推荐列表请求的异常请求检测、负载保护（和 handler.RecommendationHandler 相同，在授权之后调用）
+//
+// 通过时返回 release（请求处理完后调用）和这个用户的新鲜度要求；
+// 开启用户优先级时，过载被拒绝的非高优先级请求不占用名额，改为只使用快照（dto.FreshnessCachedOnly）。
+//
+// 错误：调用方处于封禁期 ErrRateLimited；过载 ErrOverloaded；排队期间请求取消时返回 ctx 的错误。
+func (s *Server) protect(ctx context.Context, userID int64, batchSize int) (release func(), freshness dto.Freshness, err error) {
+	if s.abuse != nil {
+		principal, _ := service.PrincipalFromContext(ctx)
+		if err := s.abuse.Check(ctx, principal, userID, batchSize); err != nil {
+			return nil, "", ErrRateLimited
+		}
+	}
+
+	tier := service.PriorityStandard
+	if s.tiers != nil {
+		tier = s.tiers.Resolve(ctx, userID)
+	}
+	freshness = tier.Freshness()
+	if s.shedder == nil {
+		return func() {}, freshness, nil
+	}
+
+	release, err = s.shedder.AcquireTier(ctx, tier)
+	switch {
+	case err == nil:
+		return release, freshness, nil
+	case ctx.Err() != nil:
+		return nil, "", ctx.Err()
+	case s.tiers != nil && tier != service.PriorityHigh:
+		return func() {}, dto.FreshnessCachedOnly, nil
+	default:
+		return nil, "", ErrOverloaded
+	}
+}
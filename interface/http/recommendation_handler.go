@@ -0,0 +1,224 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"service/application/dto"
+)
+
+const (
+	defaultLimit         = 10  // 没有指定 limit 时返回多少条（和 RPC 接口相同）
+	maxLimit             = 100 // limit、page_size 的上限
+	maxSessionIDLength   = 64  // 会话ID是缓存 key 的一部分
+	maxFilterReasonTypes = 10  // 最多指定多少种理由类型
+)
+
+// getRecommendations GET /api/v1/users/{id}/recommendations
+//
+// 查询参数（都是可选的）：
+//   - limit: 返回数量（1~100，默认 10）
+//   - surface: 展示场景
+//   - days: 推荐信号的时间窗口（最近几天）
+//   - session_id: 客户端会话ID（同一个会话内不再返回已经返回过的推荐）
+//   - page_size、page_token: 分页（page_token 是上一页响应的 next_cursor）
+//   - min_score、reason_type（可以重复）: 过滤条件
+//   - debug: 为 true 时每条推荐带上分数明细（需要质量评审权限）
+//
+// 条件请求：响应的 ETag 是列表版本；请求带上 If-None-Match 时，列表没有变化返回 304（没有响应体）。
+//
+// 和 RPC 接口一样，授权之后经过异常请求检测（被封禁时 429）、负载保护（过载时 503，见 protect）。
+//
+// 响应体是应用层的 dto.RecommendationResponse。
+func (s *Server) getRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUserID(r)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	req, err := parseRecommendationRequest(userID, r.URL.Query())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	req.KnownVersion = ifNoneMatchVersion(r.Header.Get("If-None-Match"))
+	if err := s.authorizer.AuthorizeRecommendations(r.Context(), userID); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	if req.Debug {
+		if err := s.authorizer.AuthorizeQualityReview(r.Context()); err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+	}
+	size := req.Limit
+	if req.PageSize > 0 {
+		size = req.PageSize // 分页请求的 page_size 代替 limit
+	}
+	release, freshness, err := s.protect(r.Context(), userID, size)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	defer release()
+	req.Freshness = freshness
+
+	resp, err := s.recommendations.GetFollowingBasedRecommendations(r.Context(), req)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	writeRecommendations(w, resp)
+}
+
+// writeRecommendations 辅助函数：推荐列表的响应（带 ETag，没有变化时 304）
+//
+// 降级的响应没有版本，也就没有 ETag：客户端下次请求会拿到完整列表。
+func writeRecommendations(w http.ResponseWriter, resp *dto.RecommendationResponse) {
+	if resp.Version != "" {
+		w.Header().Set("ETag", `"`+resp.Version+`"`)
+	}
+	if resp.NotModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ifNoneMatchVersion 辅助函数：If-None-Match 中客户端缓存的列表版本（没有时为空）
+//
+// 客户端把上次响应的 ETag 原样带回，可能是弱校验（W/"v1-..."）；
+// 带了多个 ETag 时使用第一个，"*" 不对应任何版本。
+func ifNoneMatchVersion(header string) string {
+	etag, _, _ := strings.Cut(header, ",")
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if etag == "*" {
+		return ""
+	}
+	return strings.Trim(etag, `"`)
+}
+
+// getRecommendationHistory GET /api/v1/users/{id}/recommendations/history
+//
+// 查询参数（都是可选的）：
+//   - from、to: 时间范围（Unix 秒，from 包含、to 不包含）
+//   - limit: 每页条数（默认 20，最多 100）
+//   - page_token: 上一页响应的 next_page_token
+func (s *Server) getRecommendationHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUserID(r)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	req := &dto.RecommendationHistoryRequest{UserID: userID, PageToken: query.Get("page_token")}
+	from, err := intParam(query, "from", 0, -1)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	to, err := intParam(query, "to", 0, -1)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	if req.Limit, err = intParam(query, "limit", 0, maxLimit); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	if from > 0 {
+		req.From = time.Unix(int64(from), 0)
+	}
+	if to > 0 {
+		req.To = time.Unix(int64(to), 0)
+	}
+	if err := s.authorizer.AuthorizeRecommendations(r.Context(), userID); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	resp, err := s.recommendations.GetRecommendationHistory(r.Context(), req)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseRecommendationRequest 辅助函数：验证并转换推荐请求的查询参数
+//
+// 时间窗口的范围、理由类型是否存在由应用服务判断（和 RPC 接口相同）。
+func parseRecommendationRequest(userID int64, query url.Values) (*dto.RecommendationRequest, error) {
+	req := &dto.RecommendationRequest{
+		UserID:    userID,
+		Surface:   dto.Surface(query.Get("surface")),
+		SessionID: query.Get("session_id"),
+		PageToken: query.Get("page_token"),
+	}
+	var err error
+	if req.Limit, err = intParam(query, "limit", defaultLimit, maxLimit); err != nil {
+		return nil, err
+	}
+	if req.PageSize, err = intParam(query, "page_size", 0, maxLimit); err != nil {
+		return nil, err
+	}
+	if req.Days, err = intParam(query, "days", 0, -1); err != nil {
+		return nil, err
+	}
+	if len(req.SessionID) > maxSessionIDLength {
+		return nil, fmt.Errorf("%w: session_id", ErrInvalidParameter)
+	}
+	if raw := query.Get("debug"); raw != "" {
+		if req.Debug, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("%w: debug", ErrInvalidParameter)
+		}
+	}
+
+	minScore, err := intParam(query, "min_score", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	reasonTypes := query["reason_type"]
+	if len(reasonTypes) > maxFilterReasonTypes {
+		return nil, fmt.Errorf("%w: reason_type", ErrInvalidParameter)
+	}
+	for _, reasonType := range reasonTypes {
+		if reasonType == "" {
+			return nil, fmt.Errorf("%w: reason_type", ErrInvalidParameter)
+		}
+	}
+	if minScore > 0 || len(reasonTypes) > 0 {
+		req.Filter = &dto.RecommendationFilter{MinScore: minScore, ReasonTypes: reasonTypes}
+	}
+	return req, nil
+}
+
+// pathUserID 辅助函数：路径中的用户ID（必须是正整数）
+func pathUserID(r *http.Request) (int64, error) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidUserID
+	}
+	return userID, nil
+}
+
+// intParam 辅助函数：非负整数的查询参数（没有或者为 0 时返回 fallback，upper 为 -1 时不限制上限）
+func intParam(query url.Values, name string, fallback, upper int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 || (upper >= 0 && value > upper) {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidParameter, name)
+	}
+	if value == 0 {
+		return fallback, nil
+	}
+	return value, nil
+}
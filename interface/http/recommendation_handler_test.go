@@ -0,0 +1,266 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/domain/valueobject"
+)
+
+// staticTokens 测试用令牌校验：令牌 → 身份，其他令牌校验失败
+type staticTokens map[string]service.Principal
+
+func (t staticTokens) Verify(token string) (service.Principal, error) {
+	principal, ok := t[token]
+	if !ok {
+		return service.Principal{}, errors.New("invalid token")
+	}
+	return principal, nil
+}
+
+// staticPeers 测试用 mTLS 证书：ServerName → SPIFFE ID（用 ServerName 区分测试中的连接）
+type staticPeers map[string]string
+
+func (p staticPeers) ServerTLSConfig() *tls.Config { return &tls.Config{} }
+
+func (p staticPeers) PeerIDFromState(state tls.ConnectionState) (string, bool) {
+	id, ok := p[state.ServerName]
+	return id, ok
+}
+
+// 验证、认证、授权都在调用应用服务之前完成：这些请求不会用到推荐服务（为 nil）
+func TestGetRecommendations_RejectedBeforeService(t *testing.T) {
+	tenants, err := service.NewTenants(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer := service.NewAuthorizer(service.AuthorizationPolicy{OnBehalfServices: []string{"ops-dashboard"}})
+	tokens := staticTokens{
+		"user-1":      {UserID: 1},
+		"svc-crawler": {Service: "crawler"},
+	}
+	handler := NewServer(":0", nil, authorizer, tenants, nil, WithIdentityVerifier(tokens)).Handler()
+	user := map[string]string{HeaderAuthorization: "Bearer user-1"}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{"invalid user id", http.MethodGet, "/api/v1/users/abc/recommendations", user, http.StatusBadRequest},
+		{"limit too large", http.MethodGet, "/api/v1/users/1/recommendations?limit=1000", user, http.StatusBadRequest},
+		{"negative page size", http.MethodGet, "/api/v1/users/1/recommendations?page_size=-1", user, http.StatusBadRequest},
+		{"unknown tenant", http.MethodGet, "/api/v1/users/1/recommendations", map[string]string{HeaderAuthorization: "Bearer user-1", HeaderTenantID: "nope"}, http.StatusBadRequest},
+		{"unauthenticated", http.MethodGet, "/api/v1/users/1/recommendations", nil, http.StatusUnauthorized},
+		{"forged token", http.MethodGet, "/api/v1/users/1/recommendations", map[string]string{HeaderAuthorization: "Bearer forged"}, http.StatusUnauthorized},
+		{"not a bearer token", http.MethodGet, "/api/v1/users/1/recommendations", map[string]string{HeaderAuthorization: "user-1"}, http.StatusUnauthorized},
+		{"declared identity headers", http.MethodGet, "/api/v1/users/1/recommendations", map[string]string{"X-Auth-User-Id": "1", "X-Caller-Service": "ops-dashboard"}, http.StatusUnauthorized},
+		{"other user", http.MethodGet, "/api/v1/users/2/recommendations", user, http.StatusForbidden},
+		{"invalid debug", http.MethodGet, "/api/v1/users/1/recommendations?debug=maybe", user, http.StatusBadRequest},
+		{"debug by end user", http.MethodGet, "/api/v1/users/1/recommendations?debug=true", user, http.StatusForbidden},
+		{"unknown service", http.MethodGet, "/api/v1/users/2/recommendations/history", map[string]string{HeaderAuthorization: "Bearer svc-crawler"}, http.StatusForbidden},
+		{"wrong method", http.MethodPost, "/api/v1/users/1/recommendations", user, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		for key, value := range tt.headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d (body %s)", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+}
+
+// 开启 mTLS 时服务名只来自客户端证书，令牌中的服务名不使用
+func TestAuthenticate_MutualTLS(t *testing.T) {
+	tokens := staticTokens{"svc-ops": {Service: "ops-dashboard"}}
+	peers := staticPeers{"bff": "spiffe://example.org/ns/prod/sa/bff", "unknown": "spiffe://example.org/ns/prod/sa/unknown"}
+	services := map[string]string{"spiffe://example.org/ns/prod/sa/bff": "bff"}
+	s := NewServer(":0", nil, nil, nil, nil, WithIdentityVerifier(tokens), WithMutualTLS(peers, services))
+
+	tests := []struct {
+		name  string
+		peer  string // 为空时不是 TLS 连接
+		token string
+		want  string
+		ok    bool
+	}{
+		{name: "mapped SPIFFE ID", peer: "bff", want: "bff", ok: true},
+		{name: "unmapped SPIFFE ID", peer: "unknown", want: "spiffe://example.org/ns/prod/sa/unknown", ok: true},
+		{name: "token service without certificate", token: "svc-ops", ok: false},
+		{name: "unverified certificate", peer: "other", ok: false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.peer != "" {
+			req.TLS = &tls.ConnectionState{ServerName: tt.peer}
+		}
+		if tt.token != "" {
+			req.Header.Set(HeaderAuthorization, "Bearer "+tt.token)
+		}
+		got, ok := s.principalFromRequest(req)
+		if ok != tt.ok || got.Service != tt.want {
+			t.Errorf("%s: principal = %+v, %v; want service %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWriteRecommendations_ETag(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *dto.RecommendationResponse
+		wantCode int
+		wantETag string
+	}{
+		{"full list", &dto.RecommendationResponse{Version: "v1-abc"}, http.StatusOK, `"v1-abc"`},
+		{"not modified", &dto.RecommendationResponse{Version: "v1-abc", NotModified: true}, http.StatusNotModified, `"v1-abc"`},
+		{"degraded without version", &dto.RecommendationResponse{Degraded: true}, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeRecommendations(rec, tt.resp)
+		if rec.Code != tt.wantCode || rec.Header().Get("ETag") != tt.wantETag {
+			t.Errorf("%s: status = %d, ETag = %q, want %d, %q", tt.name, rec.Code, rec.Header().Get("ETag"), tt.wantCode, tt.wantETag)
+		}
+		if tt.resp.NotModified && rec.Body.Len() != 0 {
+			t.Errorf("%s: 304 with body %q", tt.name, rec.Body.String())
+		}
+	}
+}
+
+func TestIfNoneMatchVersion(t *testing.T) {
+	tests := map[string]string{
+		"":                      "",
+		`"v1-abc"`:              "v1-abc",
+		`W/"v1-abc"`:            "v1-abc",
+		` "v1-abc", "v1-older"`: "v1-abc",
+		"*":                     "",
+	}
+	for header, want := range tests {
+		if got := ifNoneMatchVersion(header); got != want {
+			t.Errorf("ifNoneMatchVersion(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+// blockingDetector 测试用异常请求检测：封禁指定的调用方，记录检查过的请求
+type blockingDetector struct {
+	blocked string
+	checked []int
+}
+
+func (d *blockingDetector) Check(ctx context.Context, principal service.Principal, targetUserID int64, batchSize int) error {
+	d.checked = append(d.checked, batchSize)
+	if principal.Service == d.blocked {
+		return errors.New("too many requests")
+	}
+	return nil
+}
+
+// fullShedder 测试用负载保护：只有 high 优先级能拿到名额，记录释放的次数
+type fullShedder struct {
+	released int
+}
+
+func (l *fullShedder) AcquireTier(ctx context.Context, tier service.PriorityTier) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if tier != service.PriorityHigh {
+		return nil, errors.New("recommendation service overloaded")
+	}
+	return func() { l.released++ }, nil
+}
+
+// 异常请求检测、负载保护在授权之后、调用应用服务之前：被拒绝的请求不会用到推荐服务（为 nil）
+func TestGetRecommendations_Protected(t *testing.T) {
+	tenants, err := service.NewTenants(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer := service.NewAuthorizer(service.AuthorizationPolicy{OnBehalfServices: []string{"ops-dashboard", "crawler"}})
+	tokens := staticTokens{
+		"svc-ops":     {Service: "ops-dashboard"},
+		"svc-crawler": {Service: "crawler"},
+	}
+	detector := &blockingDetector{blocked: "crawler"}
+	handler := NewServer(":0", nil, authorizer, tenants, nil,
+		WithIdentityVerifier(tokens), WithAbuseDetector(detector), WithLoadShedder(&fullShedder{}),
+	).Handler()
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		want  int
+	}{
+		{"blocked caller", "svc-crawler", "/api/v1/users/1/recommendations?page_size=50", http.StatusTooManyRequests},
+		{"overloaded", "svc-ops", "/api/v1/users/1/recommendations", http.StatusServiceUnavailable},
+		{"unauthenticated before detection", "svc-unknown", "/api/v1/users/1/recommendations", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(HeaderAuthorization, "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d (body %s)", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+	// 分页请求按 page_size 计算单次请求的数量
+	if want := []int{50, defaultLimit}; !slices.Equal(detector.checked, want) {
+		t.Errorf("abuse detector checked batch sizes %v, want %v", detector.checked, want)
+	}
+}
+
+// 开启用户优先级时，过载被拒绝的非高优先级请求改为只使用快照；高优先级请求占用名额、总是重新生成
+func TestProtect_PriorityTiers(t *testing.T) {
+	tiers := service.NewPriorityTiers(map[valueobject.TenantID]map[int64]service.PriorityTier{
+		valueobject.DefaultTenantID: {1: service.PriorityHigh},
+	}, nil, service.PriorityStandard)
+	shedder := &fullShedder{}
+	s := NewServer(":0", nil, nil, nil, nil, WithLoadShedder(shedder), WithPriorityTiers(tiers))
+	ctx := context.Background()
+
+	release, freshness, err := s.protect(ctx, 1, 10)
+	if err != nil || freshness != dto.FreshnessRealtime {
+		t.Fatalf("high priority: freshness = %q, err = %v; want realtime", freshness, err)
+	}
+	release()
+	if shedder.released != 1 {
+		t.Errorf("released %d slots, want 1", shedder.released)
+	}
+
+	release, freshness, err = s.protect(ctx, 2, 10)
+	if err != nil || freshness != dto.FreshnessCachedOnly {
+		t.Fatalf("standard priority when overloaded: freshness = %q, err = %v; want cached only", freshness, err)
+	}
+	release()
+	if shedder.released != 1 {
+		t.Errorf("released %d slots, want 1 (the cached-only request holds no slot)", shedder.released)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := s.protect(canceled, 2, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled request: err = %v, want context.Canceled", err)
+	}
+
+	// 没有开启用户优先级时，过载直接拒绝
+	s = NewServer(":0", nil, nil, nil, nil, WithLoadShedder(shedder))
+	if _, _, err := s.protect(ctx, 2, 10); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("without priority tiers: err = %v, want ErrOverloaded", err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package http 接口层：JSON REST 接口（和 Kitex 服务并列）
+//
+// 很多内部管理后台只能发 HTTP 请求，这里用 REST 暴露和 RPC 接口相同的用例：
+//
+//	GET /api/v1/users/{id}/recommendations          推荐列表（支持分页）
+//	GET /api/v1/users/{id}/recommendations/history  展示过的推荐
+//
+// 和 interface/handler 一样只做协议适配：参数验证、调用应用服务、错误 → 状态码，
+// 和 Kitex 服务共用同一个 RecommendationService（同一份缓存、连接池），授权规则也相同（service.Authorizer）。
+// 推荐列表接口和 RPC 接口一样经过异常请求检测、负载保护（共用同一个检测窗口、同一组在途名额）。
+//
+// 监控指标（GET /metrics）在单独的端口上输出（WithMetricsHandler），不暴露在业务端口上。
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"service/application/service"
+)
+
+// Server HTTP 服务器
+//
+// 生命周期：ListenAndServe 阻塞到服务停止；Shutdown 停止接收新连接，
+// 等待处理中的请求完成（最多 shutdownTimeout），超时后强制关闭剩余的连接。
+type Server struct {
+	server          *http.Server
+	recommendations *service.RecommendationService
+	authorizer      *service.Authorizer
+	tenants         *service.Tenants
+	logger          service.Logger
+	metricsServer   *http.Server // 监控指标的监听（为 nil 时不输出）
+
+	abuse   AbuseDetector          // 为 nil 时不检测
+	shedder LoadShedder            // 为 nil 时不限制
+	tiers   *service.PriorityTiers // 为 nil 时都是 standard
+
+	tokens   service.IdentityVerifier // 为 nil 时不接受身份令牌
+	peers    PeerCertificates         // 为 nil 时没有开启 mTLS
+	services map[string]string        // SPIFFE ID → 服务名
+
+	shutdownTimeout time.Duration
+}
+
+// PeerCertificates 应用层 mTLS 的证书（由 mtls.Reloader 实现）
+type PeerCertificates interface {
+	// ServerTLSConfig 服务端 TLS 配置（要求并校验客户端证书）
+	ServerTLSConfig() *tls.Config
+	// PeerIDFromState 握手时校验过的客户端证书中的 SPIFFE ID
+	PeerIDFromState(state tls.ConnectionState) (string, bool)
+}
+
+// AbuseDetector 异常请求检测（由 handler.AbuseDetector 实现）
+//
+// Check 返回错误时拒绝这个请求（429）。
+type AbuseDetector interface {
+	Check(ctx context.Context, principal service.Principal, targetUserID int64, batchSize int) error
+}
+
+// LoadShedder 负载保护（由 handler.LoadShedder 实现）
+//
+// AcquireTier 成功时返回 release，请求处理完后调用；被拒绝时返回错误（503），排队期间请求取消时返回 ctx 的错误。
+type LoadShedder interface {
+	AcquireTier(ctx context.Context, tier service.PriorityTier) (release func(), err error)
+}
+
+// Option 可选配置
+type Option func(*Server)
+
+// WithShutdownTimeout 优雅停止时最多等待处理中的请求多久（默认 10 秒）
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithMetricsHandler 在单独的端口 addr 上输出监控指标：GET /metrics（Prometheus 抓取）
+//
+// 抓取方是监控系统而不是业务调用方，不经过认证、租户识别中间件，也不使用 mTLS；
+// 所以不和业务接口共用端口，只对监控网段开放 addr。
+// 和业务端口一起启动、停止（ListenAndServe、Shutdown）。
+func WithMetricsHandler(addr string, metrics http.Handler) Option {
+	return func(s *Server) {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metrics)
+		s.metricsServer = &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+		}
+	}
+}
+
+// WithAbuseDetector 推荐列表接口开启异常请求检测（和 Kitex 服务共用同一个 handler.AbuseDetector）
+func WithAbuseDetector(detector AbuseDetector) Option {
+	return func(s *Server) {
+		s.abuse = detector
+	}
+}
+
+// WithLoadShedder 推荐列表接口开启负载保护（和 Kitex 服务共用同一个 handler.LoadShedder，即同一组在途名额）
+func WithLoadShedder(shedder LoadShedder) Option {
+	return func(s *Server) {
+		s.shedder = shedder
+	}
+}
+
+// WithPriorityTiers 按用户优先级区分新鲜度和负载保护（和 handler.WithPriorityTiers 相同）
+//
+// - high：总是重新生成推荐；过载时可以排队，不会被降级为快照
+// - standard、low：过载被拒绝时，改为只使用最近的快照（没有快照时才返回 503）
+func WithPriorityTiers(tiers *service.PriorityTiers) Option {
+	return func(s *Server) {
+		s.tiers = tiers
+	}
+}
+
+// WithIdentityVerifier 接受签名身份令牌（Authorization: Bearer），见 service.IdentityVerifier
+func WithIdentityVerifier(tokens service.IdentityVerifier) Option {
+	return func(s *Server) {
+		s.tokens = tokens
+	}
+}
+
+// WithMutualTLS 开启 mTLS：只接受带有效客户端证书的连接，调用方的服务名来自证书
+//
+// services 把证书中的 SPIFFE ID 映射为服务名（和 Kitex 服务共用 mtls.clients），没有映射的 SPIFFE ID 直接作为服务名。
+func WithMutualTLS(peers PeerCertificates, services map[string]string) Option {
+	return func(s *Server) {
+		s.peers = peers
+		s.services = services
+	}
+}
+
+// NewServer 构造函数
+//
+// 参数：
+// - addr: 监听地址（如 ":8080"）
+// - recommendationService: 和 Kitex Handler 共用的推荐应用服务
+// - authorizer: 授权检查（和 RPC 接口相同的规则）
+// - tenants: 租户配置（识别 X-Tenant-Id）
+// - logger: 记录内部错误
+func NewServer(
+	addr string,
+	recommendationService *service.RecommendationService,
+	authorizer *service.Authorizer,
+	tenants *service.Tenants,
+	logger service.Logger,
+	opts ...Option,
+) *Server {
+	s := &Server{
+		recommendations: recommendationService,
+		authorizer:      authorizer,
+		tenants:         tenants,
+		logger:          logger,
+		shutdownTimeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if s.peers != nil {
+		s.server.TLSConfig = s.peers.ServerTLSConfig()
+	}
+	return s
+}
+
+// Handler 路由（所有接口都带认证、租户识别、语言识别中间件），测试中可以直接使用
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/users/{id}/recommendations", s.getRecommendations)
+	mux.HandleFunc("GET /api/v1/users/{id}/recommendations/history", s.getRecommendationHistory)
+	return s.authenticate(s.resolveTenant(s.resolveLocale(mux)))
+}
+
+// Addr 监听地址
+func (s *Server) Addr() string {
+	return s.server.Addr
+}
+
+// ListenAndServe 开始监听业务端口和监控指标端口，阻塞到服务停止（Shutdown 之后返回 nil）
+//
+// 开启 mTLS 时业务端口监听 HTTPS（每次握手取当前的证书，证书轮换后新连接立即生效）。
+// 任何一个端口监听失败时关闭另一个，返回这个错误。
+func (s *Server) ListenAndServe() error {
+	if s.metricsServer == nil {
+		return s.serveAPI()
+	}
+	errs := make(chan error, 2)
+	go func() { errs <- s.serveAPI() }()
+	go func() { errs <- serve(s.metricsServer.ListenAndServe) }()
+	err := <-errs
+	if err != nil {
+		_ = s.server.Close()
+		_ = s.metricsServer.Close()
+	}
+	return errors.Join(err, <-errs)
+}
+
+// serveAPI 辅助方法：监听业务端口
+func (s *Server) serveAPI() error {
+	listen := s.server.ListenAndServe
+	if s.server.TLSConfig != nil {
+		// 证书在 GetConfigForClient 中提供，不能用 ListenAndServeTLS（要求证书文件或 GetCertificate）
+		listen = func() error {
+			ln, err := net.Listen("tcp", s.server.Addr)
+			if err != nil {
+				return err
+			}
+			return s.server.Serve(tls.NewListener(ln, s.server.TLSConfig))
+		}
+	}
+	return serve(listen)
+}
+
+// serve 辅助函数：阻塞到服务停止，Shutdown、Close 之后返回 nil
+func serve(listen func() error) error {
+	if err := listen(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown 优雅停止：不再接收新请求，等待处理中的请求完成（监控指标端口一起停止）
+//
+// 等待时间取 ctx 和 shutdownTimeout 中先到的；超时后强制关闭剩余的连接并返回超时错误。
+func (s *Server) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
+	defer cancel()
+	var errs []error
+	for _, server := range []*http.Server{s.server, s.metricsServer} {
+		if server == nil {
+			continue
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, err, server.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
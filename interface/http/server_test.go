@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 监控指标只在单独的端口上输出：业务端口上的 /metrics 和其他路径一样要求认证
+func TestWithMetricsHandler_SeparateListener(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("rpc_requests_total 1\n"))
+	})
+	s := NewServer(":0", nil, nil, nil, nil, WithMetricsHandler(":0", metrics))
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("API port /metrics: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	rec = httptest.NewRecorder()
+	s.metricsServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "rpc_requests_total 1\n" {
+		t.Errorf("metrics port /metrics: status = %d, body %q", rec.Code, rec.Body.String())
+	}
+}
+
+// 监控指标端口监听失败时业务端口一起停止，ListenAndServe 返回这个错误
+func TestListenAndServe_MetricsListenFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	s := NewServer("127.0.0.1:0", nil, nil, nil, nil, WithMetricsHandler(ln.Addr().String(), http.NotFoundHandler()))
+
+	done := make(chan error, 1)
+	go func() { done <- s.ListenAndServe() }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("ListenAndServe() = nil, want the metrics listen error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe() still running after the metrics listener failed")
+	}
+}
+
+// Shutdown 同时停止业务端口和监控指标端口，ListenAndServe 返回 nil
+func TestShutdown_StopsBothListeners(t *testing.T) {
+	s := NewServer("127.0.0.1:0", nil, nil, nil, nil, WithMetricsHandler("127.0.0.1:0", http.NotFoundHandler()))
+
+	done := make(chan error, 1)
+	go func() { done <- s.ListenAndServe() }()
+	time.Sleep(50 * time.Millisecond) // 等两个端口开始监听
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ListenAndServe() = %v, want nil after Shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe() still running after Shutdown")
+	}
+}
@@ -0,0 +1,89 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/application/service"
+)
+
+// PrecomputeJob 后台任务：定期为最近活跃的用户预计算推荐列表
+//
+// 和 RetentionJob 一样只负责"什么时候调用应用服务"，
+// 为谁生成、什么时候跳过由应用层（ListPrecomputer）决定。
+//
+// 必须和 RPC Handler 在同一个依赖图中：预计算的列表同时写入 Handler 使用的推荐列表缓存
+// （dev 环境的内存仓储也只有同一个依赖图里的 Handler 能读到）。
+type PrecomputeJob struct {
+	precomputer *service.ListPrecomputer
+	interval    time.Duration
+	logger      service.Logger
+}
+
+// NewPrecomputeJob 构造函数
+//
+// 参数：
+// - precomputer: 预计算
+// - interval: 执行间隔（应小于在线请求使用持久化列表的 listMaxAge，活跃用户的列表才不会过期）
+// - logger: 日志（记录每轮每个租户的报告）
+func NewPrecomputeJob(precomputer *service.ListPrecomputer, interval time.Duration, logger service.Logger) *PrecomputeJob {
+	return &PrecomputeJob{
+		precomputer: precomputer,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Run 启动后立即执行一轮，之后每隔 interval 执行一轮，直到 ctx 取消
+//
+// 一轮没有在 interval 内完成时，下一轮在它完成之后立即开始（不会并发执行）。
+func (j *PrecomputeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 执行一轮预计算，并把报告写入日志
+func (j *PrecomputeJob) RunOnce(ctx context.Context) *dto.PrecomputeReport {
+	report := j.precomputer.RunOnce(ctx)
+	for _, tenant := range report.Tenants {
+		j.logTenant(ctx, tenant)
+	}
+	return report
+}
+
+// logTenant 辅助方法：按结果选择日志级别
+//
+// - 查询活跃用户失败：error
+// - 有用户生成失败：warn
+// - 其他：info
+func (j *PrecomputeJob) logTenant(ctx context.Context, tenant *dto.PrecomputeTenantReport) {
+	fields := map[string]any{
+		"tenant":       tenant.Tenant,
+		"active_users": tenant.ActiveUsers,
+		"generated":    tenant.Generated,
+		"fresh":        tenant.Fresh,
+		"erased":       tenant.Erased,
+		"failed":       tenant.Failed,
+		"duration":     tenant.Duration.String(),
+	}
+
+	switch {
+	case tenant.Error != "":
+		fields[service.FieldError] = tenant.Error
+		j.logger.Log(ctx, service.LogLevelError, "precompute failed", fields)
+	case tenant.Failed > 0:
+		j.logger.Log(ctx, service.LogLevelWarn, "precompute completed with failures", fields)
+	default:
+		j.logger.Log(ctx, service.LogLevelInfo, "precompute completed", fields)
+	}
+}
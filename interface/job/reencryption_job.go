@@ -0,0 +1,73 @@
+package job
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"service/application/service"
+)
+
+// ReencryptionJob 定时任务：加密字段的重新加密（密钥轮换）
+//
+// encryption.current_key_id 换成新密钥之后，新数据使用新密钥，
+// 这个任务把旧密钥加密的数据（以及开启加密前写入的明文数据）逐步换成新密钥，
+// 全部完成后旧密钥才可以从密钥环中删除。
+//
+// 多实例部署时每个实例都会运行：已经是当前密钥的行会被跳过，重复执行只是多一次扫描。
+type ReencryptionJob struct {
+	tables   map[string]service.FieldReencryptor
+	interval time.Duration
+	logger   service.Logger
+}
+
+// NewReencryptionJob 构造函数
+//
+// 参数：
+// - tables: 表名 → 重新加密（表名只用于日志）
+// - interval: 执行间隔
+// - logger: 日志（记录每张表更新的行数）
+func NewReencryptionJob(tables map[string]service.FieldReencryptor, interval time.Duration, logger service.Logger) *ReencryptionJob {
+	return &ReencryptionJob{
+		tables:   tables,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run 启动后立即执行一次，之后每隔 interval 执行一次，直到 ctx 取消
+func (j *ReencryptionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 逐张表执行一次重新加密，并把结果写入日志（一张表失败不影响其他表）
+func (j *ReencryptionJob) RunOnce(ctx context.Context) {
+	tables := make([]string, 0, len(j.tables))
+	for table := range j.tables {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+	for _, table := range tables {
+		updated, err := j.tables[table].ReencryptFields(ctx)
+		fields := map[string]any{
+			"table":        table,
+			"updated_rows": updated,
+		}
+		if err != nil {
+			fields[service.FieldError] = err.Error()
+			j.logger.Log(ctx, service.LogLevelError, "reencryption failed", fields)
+			continue
+		}
+		j.logger.Log(ctx, service.LogLevelInfo, "reencryption completed", fields)
+	}
+}
@@ -0,0 +1,88 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"service/application/dto"
+	"service/application/service"
+)
+
+// RetentionJob 定时任务：数据保留期清理
+//
+// 和 Handler 一样属于接口层：
+// Handler 由 RPC 请求触发，定时任务由时间触发，
+// 都只负责"什么时候调用应用服务"，清理规则在应用层（RetentionEnforcer）。
+//
+// 多实例部署时：
+// 每个实例都会运行这个任务，删除是幂等的（删除 cutoff 之前的数据），
+// 重复执行不影响正确性，只是多一次查询。
+type RetentionJob struct {
+	enforcer *service.RetentionEnforcer
+	interval time.Duration
+	logger   service.Logger
+}
+
+// NewRetentionJob 构造函数
+//
+// 参数：
+// - enforcer: 数据保留期执行器
+// - interval: 执行间隔（如每天一次）
+// - logger: 日志（记录每次执行的报告）
+func NewRetentionJob(enforcer *service.RetentionEnforcer, interval time.Duration, logger service.Logger) *RetentionJob {
+	return &RetentionJob{
+		enforcer: enforcer,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run 启动后立即执行一次，之后每隔 interval 执行一次，直到 ctx 取消
+func (j *RetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 执行一次清理，并把报告写入日志
+func (j *RetentionJob) RunOnce(ctx context.Context) *dto.RetentionReport {
+	report := j.enforcer.Enforce(ctx)
+	for _, class := range report.Classes {
+		j.logClass(ctx, class)
+	}
+	return report
+}
+
+// logClass 辅助方法：按结果选择日志级别
+//
+// - 执行失败：error
+// - 仍有超过保留期的数据：warn（需要人工排查）
+// - 没有对应的存储：info
+func (j *RetentionJob) logClass(ctx context.Context, class *dto.RetentionClassReport) {
+	fields := map[string]any{
+		"class":       class.Class,
+		"max_age":     class.MaxAge.String(),
+		"purged_rows": class.PurgedRows,
+	}
+
+	switch {
+	case class.Error != "":
+		fields[service.FieldError] = class.Error
+		j.logger.Log(ctx, service.LogLevelError, "retention purge failed", fields)
+	case class.ExceedsLimit:
+		fields["oldest_record"] = class.OldestRecord.Format(time.RFC3339)
+		j.logger.Log(ctx, service.LogLevelWarn, "data still exceeds retention target after purge", fields)
+	case class.Unmanaged:
+		j.logger.Log(ctx, service.LogLevelInfo, "retention configured but no store registered", fields)
+	default:
+		j.logger.Log(ctx, service.LogLevelInfo, "retention purge completed", fields)
+	}
+}
@@ -0,0 +1,164 @@
+// Package replay 流量回放：把抓取的请求重新发给候选版本，比较推荐结果
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"service/application/service"
+	"service/domain/valueobject"
+	"service/interface/handler"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+// ReadExchanges 读取抓取文件（每行一条 JSON 格式的 handler.CapturedExchange）
+func ReadExchanges(r io.Reader) ([]*handler.CapturedExchange, error) {
+	var exchanges []*handler.CapturedExchange
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var exchange handler.CapturedExchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		exchanges = append(exchanges, &exchange)
+	}
+	return exchanges, scanner.Err()
+}
+
+// Result 一条请求的回放结果
+type Result struct {
+	Exchange *handler.CapturedExchange
+	Response *recommendation.GetRecommendationsResponse // 候选版本的响应（失败时为 nil）
+	Err      error
+	Diffs    []string // 和抓取的响应的差异（为空表示一致）
+}
+
+// Report 回放报告
+type Report struct {
+	Total    int
+	Matched  int
+	Failures int
+	Results  []*Result // 不一致或失败的请求
+}
+
+// Replayer 回放器
+//
+// 为什么需要？
+// 线上反馈的排序问题，拿着抓取的请求在候选版本上重放，
+// 就能知道修复是否生效、改动还影响了哪些用户的排序。
+//
+// 回放方式：
+// - 按抓取时的租户、以被推荐用户本人的身份请求（授权检查和线上一致地通过）
+// - 去掉条件请求、增量同步和会话去重的参数（known_version、since_*、session_id），总是比较完整列表
+// - 先比较列表版本（由推荐对象、分数、理由算出，相同即排序结果相同），不同时逐条比较
+//
+// 候选版本需要能读到和线上一致的数据（如只读副本），否则差异来自数据而不是代码。
+type Replayer struct {
+	target Target
+}
+
+// Target 回放的目标：进程内的 Handler，或者候选版本的 RPC 客户端
+type Target interface {
+	GetFollowingBasedRecommendations(
+		ctx context.Context,
+		req *recommendation.GetRecommendationsRequest,
+	) (*recommendation.GetRecommendationsResponse, error)
+}
+
+// NewReplayer 构造函数
+func NewReplayer(target Target) *Replayer {
+	return &Replayer{target: target}
+}
+
+// Replay 逐条回放
+func (r *Replayer) Replay(ctx context.Context, exchanges []*handler.CapturedExchange) *Report {
+	report := &Report{Total: len(exchanges)}
+	for _, exchange := range exchanges {
+		result := r.replayOne(ctx, exchange)
+		switch {
+		case result.Err != nil:
+			report.Failures++
+			report.Results = append(report.Results, result)
+		case len(result.Diffs) > 0:
+			report.Results = append(report.Results, result)
+		default:
+			report.Matched++
+		}
+	}
+	return report
+}
+
+// replayOne 辅助方法：回放一条请求
+func (r *Replayer) replayOne(ctx context.Context, exchange *handler.CapturedExchange) *Result {
+	result := &Result{Exchange: exchange}
+	tenant, err := valueobject.NewTenantID(exchange.Tenant)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	req := *exchange.Request
+	req.KnownVersion, req.SinceVersion, req.SinceTimestamp, req.SessionId = "", "", 0, ""
+	ctx = valueobject.WithTenant(ctx, tenant)
+	ctx = service.WithPrincipal(ctx, service.Principal{UserID: req.UserId})
+
+	result.Response, result.Err = r.target.GetFollowingBasedRecommendations(ctx, &req)
+	if result.Err == nil {
+		result.Diffs = Diff(exchange.Response, result.Response)
+	}
+	return result
+}
+
+// Diff 比较抓取的响应和回放的响应，返回可读的差异描述
+//
+// 比较推荐了谁、排第几、分数和理由文案；个人资料和帖子在抓取时已经去掉，不比较。
+func Diff(captured, replayed *recommendation.GetRecommendationsResponse) []string {
+	if captured.Version != "" && captured.Version == replayed.Version {
+		return nil
+	}
+	if replayed.Degraded {
+		return []string{"replayed response is degraded (partial list), compare again with a longer timeout"}
+	}
+	// 抓取的不是完整列表（not_modified、增量、降级）：只能比较版本
+	if captured.NotModified || captured.Delta || captured.Degraded {
+		return []string{fmt.Sprintf("version %q -> %q (captured response is not a full list)", captured.Version, replayed.Version)}
+	}
+
+	var diffs []string
+	replayedRank := make(map[int64]int, len(replayed.Recommendations))
+	for i, rec := range replayed.Recommendations {
+		replayedRank[rec.UserId] = i
+	}
+	seen := make(map[int64]bool, len(captured.Recommendations))
+	for i, before := range captured.Recommendations {
+		seen[before.UserId] = true
+		j, ok := replayedRank[before.UserId]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("user %d: removed (was #%d, score %d)", before.UserId, i+1, before.Score))
+			continue
+		}
+		after := replayed.Recommendations[j]
+		if i != j {
+			diffs = append(diffs, fmt.Sprintf("user %d: rank #%d -> #%d", before.UserId, i+1, j+1))
+		}
+		if before.Score != after.Score {
+			diffs = append(diffs, fmt.Sprintf("user %d: score %d -> %d", before.UserId, before.Score, after.Score))
+		}
+		if before.Reason != after.Reason {
+			diffs = append(diffs, fmt.Sprintf("user %d: reason %q -> %q", before.UserId, before.Reason, after.Reason))
+		}
+	}
+	for j, after := range replayed.Recommendations {
+		if !seen[after.UserId] {
+			diffs = append(diffs, fmt.Sprintf("user %d: added (#%d, score %d)", after.UserId, j+1, after.Score))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"service/interface/handler"
+	"service/rpc_gen/kitex_gen/recommendation"
+)
+
+// memorySink 测试用抓取去处
+type memorySink struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *memorySink) Write(ctx context.Context, record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Write(record)
+	s.buf.WriteByte('\n')
+	return nil
+}
+
+// fakeTarget 测试用回放目标：返回固定的响应
+type fakeTarget struct {
+	resp *recommendation.GetRecommendationsResponse
+}
+
+func (t fakeTarget) GetFollowingBasedRecommendations(
+	ctx context.Context,
+	req *recommendation.GetRecommendationsRequest,
+) (*recommendation.GetRecommendationsResponse, error) {
+	return t.resp, nil
+}
+
+func rec(userID int64, score int32) *recommendation.UserRecommendation {
+	return &recommendation.UserRecommendation{UserId: userID, Username: "nick", Bio: "bio", Score: score, Reason: "r"}
+}
+
+func TestCaptureAndReplay(t *testing.T) {
+	sink := &memorySink{}
+	capture := handler.NewTrafficCapture(sink, 1, 10)
+	captured := &recommendation.GetRecommendationsResponse{
+		Recommendations: []*recommendation.UserRecommendation{rec(2, 40), rec(3, 30)},
+		Version:         "v1-a",
+	}
+	capture.Record(context.Background(), &recommendation.GetRecommendationsRequest{UserId: 1, Limit: 2}, captured, time.Millisecond)
+	capture.Close()
+
+	// 抓取结果已经脱敏
+	if strings.Contains(sink.buf.String(), "nick") || strings.Contains(sink.buf.String(), "bio") {
+		t.Fatalf("capture not sanitized: %s", sink.buf.String())
+	}
+	exchanges, err := ReadExchanges(&sink.buf)
+	if err != nil || len(exchanges) != 1 {
+		t.Fatalf("ReadExchanges = %d, %v", len(exchanges), err)
+	}
+
+	// 版本相同：一致
+	report := NewReplayer(fakeTarget{resp: captured}).Replay(context.Background(), exchanges)
+	if report.Matched != 1 {
+		t.Fatalf("same version: matched = %d, results = %+v", report.Matched, report.Results)
+	}
+
+	// 排序变化：3 升到第一，2 的分数下降，新增 4
+	replayed := &recommendation.GetRecommendationsResponse{
+		Recommendations: []*recommendation.UserRecommendation{rec(3, 30), rec(2, 20), rec(4, 10)},
+		Version:         "v1-b",
+	}
+	report = NewReplayer(fakeTarget{resp: replayed}).Replay(context.Background(), exchanges)
+	if report.Matched != 0 || len(report.Results) != 1 {
+		t.Fatalf("changed ranking: matched = %d, results = %d", report.Matched, len(report.Results))
+	}
+	want := []string{
+		"user 2: rank #1 -> #2",
+		"user 2: score 40 -> 20",
+		"user 3: rank #2 -> #1",
+		"user 4: added (#3, score 10)",
+	}
+	if got := report.Results[0].Diffs; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("diffs = %q, want %q", got, want)
+	}
+}
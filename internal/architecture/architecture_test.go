@@ -0,0 +1,102 @@
+package architecture
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const module = "service/"
+
+// rule 一个包（目录前缀）允许导入的本模块的包（前缀）
+type rule struct {
+	from    string
+	allowed []string
+}
+
+// rules 分层和上下文的依赖规则（没有列出的目录不检查）
+//
+// 规则按顺序匹配，第一条匹配的规则生效（更具体的目录写在前面）。
+var rules = []rule{
+	// 共享内核不依赖任何其他包
+	{"internal/sharedkernel", nil},
+	// 领域层只依赖自己和共享内核
+	{"domain", []string{"domain", "internal/sharedkernel"}},
+	// 应用层不依赖基础设施层、接口层
+	{"application", []string{"application", "domain", "internal/sharedkernel"}},
+	// 仓储实现（包括 Mock）只实现领域层的端口，不依赖应用层
+	{"infrastructure/repository", []string{"domain", "internal/sharedkernel"}},
+	{"infrastructure/persistence", []string{"domain", "internal/sharedkernel", "infrastructure/crypto"}},
+	{"infrastructure", []string{"application", "domain", "infrastructure", "internal/sharedkernel"}},
+	// 内容集成上下文：只通过应用层的端口对接推荐上下文，不依赖推荐领域
+	{"internal/contentintegration", []string{"application/service", "infrastructure/client", "internal/contentintegration", "internal/sharedkernel"}},
+	// 接口层不直接依赖基础设施层（由组合根注入）
+	{"interface", []string{"application", "domain", "internal/sharedkernel", "interface", "rpc_gen"}},
+}
+
+func TestImportRules(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, filepath.Dir(path))
+		r, ok := matchRule(filepath.ToSlash(rel))
+		if !ok {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, imp := range file.Imports {
+			importPath, _ := strconv.Unquote(imp.Path.Value)
+			if !strings.HasPrefix(importPath, module) {
+				continue
+			}
+			if !hasPrefix(strings.TrimPrefix(importPath, module), r.allowed) {
+				t.Errorf("%s imports %s (rule %q allows %v)", filepath.ToSlash(mustRel(root, path)), importPath, r.from, r.allowed)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// matchRule 目录对应的规则
+func matchRule(dir string) (rule, bool) {
+	for _, r := range rules {
+		if hasPrefix(dir, []string{r.from}) {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+// hasPrefix 路径是否在某个目录下（按路径段匹配，"domain" 不匹配 "domainx"）
+func hasPrefix(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func mustRel(root, path string) string {
+	rel, _ := filepath.Rel(root, path)
+	return rel
+}
@@ -0,0 +1,6 @@
+// Package architecture 架构约束测试：检查各层、各上下文之间的导入关系
+//
+// Go 的 internal 目录只能限制"模块外"的导入，限制不了模块内各层之间的依赖方向；
+// 这里的测试（architecture_test.go）把分层规则写成代码，违反时 go test 失败，
+// 例如基础设施层的 Mock 不能再导入应用层的类型。
+package architecture
@@ -0,0 +1,8 @@
+// Package contentintegration 内容集成上下文：调用内容服务获取帖子
+//
+// 内容服务是另一个团队的限界上下文，这个包是推荐服务和它之间唯一的适配层。
+//
+// 对外只暴露构造函数（NewHTTPClient、NewRPCClient），返回应用层的端口 service.ContentServiceClient：
+// - 具体实现类型不导出，调用方无法依赖实现细节
+// - 内容服务的响应格式在 internal/contentapi 中，编译器保证只有本包能使用
+package contentintegration
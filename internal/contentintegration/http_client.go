@@ -0,0 +1,200 @@
+package contentintegration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"service/application/service"
+	"service/infrastructure/client"
+	"service/internal/contentintegration/internal/contentapi"
+)
+
+// httpClient 内容服务HTTP客户端实现
+//
+// 这是 ContentServiceClient 接口的具体实现，负责通过 HTTP 调用内容服务。
+//
+// 为什么在基础设施层？
+// - HTTP 调用是技术细节
+// - 序列化/反序列化是实现细节
+// - 错误处理、超时、重试等都是基础设施关注点
+//
+// 实际场景：
+// 推荐服务需要获取用户帖子 →
+//
+//	应用层调用 ContentServiceClient 接口 →
+//	  基础设施层通过 HTTP 调用内容服务 →
+//	    内容服务返回帖子数据 →
+//	  转换为应用层的 PostInfo →
+//	返回给应用层
+//
+// 对比：
+// - ContentRepository：查询本地数据库（SQL）
+// - ContentServiceClient：调用远程服务（HTTP/RPC）
+type httpClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// batchUnavailable 内容服务没有批量接口（返回过 404），之后不再调用，直接返回 ErrBatchUnavailable
+	batchUnavailable atomic.Bool
+}
+
+// NewHTTPClient 构造函数：通过 HTTP 调用内容服务
+func NewHTTPClient(baseURL string, opts ...client.HTTPClientOption) service.ContentServiceClient {
+	return &httpClient{
+		baseURL: baseURL,
+		httpClient: client.ApplyHTTPClientOptions(&http.Client{
+			Timeout: 3 * time.Second, // 3秒超时
+		}, opts),
+	}
+}
+
+// GetRecentPosts 获取用户最近的帖子
+//
+// HTTP 调用示例：
+// GET /api/v1/users/{userID}/posts?limit=3
+//
+// 响应示例：
+//
+//	{
+//	  "posts": [
+//	    {
+//	      "post_id": 123,
+//	      "content": "Hello World",
+//	      "created_at": "2024-01-01 12:00:00",
+//	      "moderation_status": "approved"
+//	    }
+//	  ]
+//	}
+//
+// 错误处理：
+// - 网络错误：返回错误
+// - 超时：返回错误
+// - 4xx/5xx：返回错误
+// - 解析失败：返回错误
+func (c *httpClient) GetRecentPosts(
+	ctx context.Context,
+	userID int64,
+	limit int,
+) ([]*service.PostInfo, error) {
+	// 构造请求 URL
+	url := fmt.Sprintf("%s/api/v1/users/%d/posts?limit=%d", c.baseURL, userID, limit)
+
+	// 创建 HTTP 请求
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+
+	// 发送请求
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 检查状态码
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// 解析响应（内容服务的响应格式只在本上下文内部可见）
+	var response contentapi.PostsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	// 转换为应用层的 PostInfo
+	result := make([]*service.PostInfo, 0, len(response.Posts))
+	for _, post := range response.Posts {
+		result = append(result, &service.PostInfo{
+			PostID:           post.PostID,
+			Content:          post.Content,
+			CreatedAt:        post.CreatedTime(),
+			ModerationStatus: post.ModerationStatus,
+		})
+	}
+
+	return result, nil
+}
+
+// GetRecentPostsForUsers 批量获取多个用户最近的帖子
+//
+// HTTP 调用示例：
+// GET /api/v1/posts/recent?user_ids=1,2,3&limit=3
+//
+// 响应示例（没有帖子的用户可以不出现）：
+//
+//	{
+//	  "users": {
+//	    "1": [{"post_id": 123, "content": "Hello World", "created_at": "2024-01-01T12:00:00+08:00"}]
+//	  }
+//	}
+//
+// 旧版本的内容服务没有这个接口（404）：返回 service.ErrBatchUnavailable，
+// 并记住这一点，进程重启之前不再调用（调用方逐个调用 GetRecentPosts）。
+func (c *httpClient) GetRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []int64,
+	limit int,
+) (map[int64][]*service.PostInfo, error) {
+	if c.batchUnavailable.Load() {
+		return nil, service.ErrBatchUnavailable
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, strconv.FormatInt(userID, 10))
+	}
+	query := url.Values{
+		"user_ids": {strings.Join(ids, ",")},
+		"limit":    {strconv.Itoa(limit)},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/posts/recent?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.batchUnavailable.Store(true)
+		return nil, service.ErrBatchUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response contentapi.RecentPostsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	result := make(map[int64][]*service.PostInfo, len(response.Users))
+	for userID, posts := range response.Users {
+		infos := make([]*service.PostInfo, 0, len(posts))
+		for _, post := range posts {
+			infos = append(infos, &service.PostInfo{
+				PostID:           post.PostID,
+				Content:          post.Content,
+				CreatedAt:        post.CreatedTime(),
+				ModerationStatus: post.ModerationStatus,
+			})
+		}
+		result[userID] = infos
+	}
+	return result, nil
+}
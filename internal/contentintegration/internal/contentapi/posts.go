@@ -0,0 +1,39 @@
+// Package contentapi 内容服务的 HTTP 响应格式
+//
+// 放在 internal 下：只有 contentintegration 能导入，
+// 内容服务的字段命名、时间格式不会泄漏到推荐上下文（推荐上下文只看到 service.PostInfo）。
+package contentapi
+
+import "time"
+
+// legacyTimeLayout 旧版本内容服务的时间格式（没有时区，内容服务所在时区，和本服务相同）
+const legacyTimeLayout = "2006-01-02 15:04:05"
+
+// PostsResponse GET /api/v1/users/{userID}/posts 的响应
+type PostsResponse struct {
+	Posts []Post `json:"posts"`
+}
+
+// RecentPostsBatchResponse GET /api/v1/posts/recent 的响应：用户ID → 帖子
+type RecentPostsBatchResponse struct {
+	Users map[int64][]Post `json:"users"`
+}
+
+// Post 内容服务返回的帖子
+type Post struct {
+	PostID           int64  `json:"post_id"`
+	Content          string `json:"content"`
+	CreatedAt        string `json:"created_at"` // RFC3339；旧版本是 legacyTimeLayout
+	ModerationStatus string `json:"moderation_status"`
+}
+
+// CreatedTime 解析发布时间（两种格式都不是时返回零值，展示时间为空，不影响帖子本身）
+func (p Post) CreatedTime() time.Time {
+	if t, err := time.Parse(time.RFC3339, p.CreatedAt); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation(legacyTimeLayout, p.CreatedAt, time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
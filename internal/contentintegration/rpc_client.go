@@ -1,4 +1,4 @@
-package client
+package contentintegration
 
 import (
 	"context"
@@ -10,7 +10,7 @@ import (
 	// "service/rpc_gen/kitex_gen/content/contentservice"
 )
 
-// ContentServiceRPCClient 内容服务RPC客户端实现（使用 Kitex）
+// rpcClient 内容服务RPC客户端实现（使用 Kitex）
 //
 // 这是使用 Kitex RPC 框架的实现版本。
 //
@@ -26,24 +26,24 @@ import (
 // 1. 定义 content.thrift（IDL）
 // 2. 使用 Kitex 生成客户端代码
 // 3. 实现这个适配器（将 RPC 响应转换为应用层的 PostInfo）
-type ContentServiceRPCClient struct {
+type rpcClient struct {
 	// client contentservice.Client // Kitex 生成的客户端
 }
 
-// NewContentServiceRPCClient 构造函数
+// NewRPCClient 构造函数：通过 Kitex 调用内容服务
 //
-// 实际使用示例：
+// 实际使用示例（在组合根中创建，实例地址来自注册中心，见 main 包的 rpcClientOptions）：
 //
 //	client, err := contentservice.NewClient(
 //	    "content-service",
-//	    client.WithHostPorts("127.0.0.1:8889"),
+//	    rpcClientOptions(cfg, registry, "content-service")...,
 //	)
 //	if err != nil {
 //	    panic(err)
 //	}
-//	return &ContentServiceRPCClient{client: client}
-func NewContentServiceRPCClient( /* client contentservice.Client */ ) *ContentServiceRPCClient {
-	return &ContentServiceRPCClient{
+//	return &rpcClient{client: client}
+func NewRPCClient( /* client contentservice.Client */ ) service.ContentServiceClient {
+	return &rpcClient{
 		// client: client,
 	}
 }
@@ -62,7 +62,7 @@ func NewContentServiceRPCClient( /* client contentservice.Client */ ) *ContentSe
 // - 类型安全：编译时检查
 // - 高性能：二进制序列化
 // - 代码生成：自动生成客户端代码
-func (c *ContentServiceRPCClient) GetRecentPosts(
+func (c *rpcClient) GetRecentPosts(
 	ctx context.Context,
 	userID int64,
 	limit int,
@@ -83,9 +83,10 @@ func (c *ContentServiceRPCClient) GetRecentPosts(
 	// result := make([]*service.PostInfo, 0, len(resp.Posts))
 	// for _, post := range resp.Posts {
 	//     result = append(result, &service.PostInfo{
-	//         PostID:    post.PostId,
-	//         Content:   post.Content,
-	//         CreatedAt: post.CreatedAt,
+	//         PostID:           post.PostId,
+	//         Content:          post.Content,
+	//         CreatedAt:        time.UnixMilli(post.CreatedAtMs),
+	//         ModerationStatus: post.ModerationStatus,
 	//     })
 	// }
 	//
@@ -94,3 +95,15 @@ func (c *ContentServiceRPCClient) GetRecentPosts(
 	// 占位实现
 	return nil, fmt.Errorf("not implemented: need Kitex generated code")
 }
+
+// GetRecentPostsForUsers 批量获取多个用户最近的帖子（RPC 版本）
+//
+// content.thrift 还没有批量方法：返回 service.ErrBatchUnavailable，调用方逐个调用 GetRecentPosts。
+// 加上批量方法（如 GetRecentPostsForUsers(1: list<i64> user_ids, 2: i32 limit)）之后按 GetRecentPosts 的方式转换响应。
+func (c *rpcClient) GetRecentPostsForUsers(
+	ctx context.Context,
+	userIDs []int64,
+	limit int,
+) (map[int64][]*service.PostInfo, error) {
+	return nil, service.ErrBatchUnavailable
+}
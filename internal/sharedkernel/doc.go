@@ -0,0 +1,13 @@
+// Package sharedkernel 共享内核：多个限界上下文共用的值对象
+//
+// 什么是共享内核？
+// 推荐上下文、内容集成、用户服务防腐层都要表达"用户ID""帖子ID""租户"，
+// 这些概念在各个上下文中含义完全相同，由共享内核统一定义，而不是每个上下文各写一份。
+//
+// 约束：
+// - 只放含义稳定、没有业务行为分歧的值对象（ID、租户）
+// - 不依赖本仓库的任何其他包
+// - 修改需要所有使用方的上下文同意（共享内核的代价）
+//
+// 推荐领域通过 domain/valueobject 的类型别名使用这些值对象，领域代码不需要改动。
+package sharedkernel
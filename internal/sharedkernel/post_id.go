@@ -1,4 +1,4 @@
-package valueobject
+package sharedkernel
 
 import "errors"
 
@@ -28,7 +28,3 @@ func (p PostID) Value() int64 {
 func (p PostID) Equals(other PostID) bool {
 	return p.value == other.value
 }
-
-type D struct {
-	value int64
-}
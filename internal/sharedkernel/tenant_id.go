@@ -0,0 +1,60 @@
+package sharedkernel
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrInvalidTenantID = errors.New("invalid tenant id: must be 1-32 characters of [a-z0-9_-]")
+
+// DefaultTenantID 默认租户：多租户之前的全部数据（第一个 App 品牌）都属于它
+const DefaultTenantID TenantID = "default"
+
+// TenantID 值对象：租户（App 品牌）标识
+//
+// 为什么需要？
+// 同一套推荐服务要同时服务多个 App 品牌，它们的用户、关注关系、曝光记录不能混在一起。
+// 租户在接口层识别，随 ctx 传递到每一层：
+// - 缓存 key、Redis key 带上租户前缀
+// - 数据库查询带上 tenant_id 条件
+// - 打分权重、文案语言按租户配置
+//
+// 格式：小写字母、数字、下划线、短横线，最长 32 个字符（会拼进缓存 key）。
+type TenantID string
+
+// NewTenantID 工厂方法：创建并验证租户标识
+func NewTenantID(value string) (TenantID, error) {
+	if len(value) == 0 || len(value) > 32 {
+		return "", ErrInvalidTenantID
+	}
+	for _, c := range value {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return "", ErrInvalidTenantID
+		}
+	}
+	return TenantID(value), nil
+}
+
+// String 实现 fmt.Stringer
+func (t TenantID) String() string {
+	return string(t)
+}
+
+// tenantKey context key（不导出，避免和其他包冲突）
+type tenantKey struct{}
+
+// WithTenant 把租户放进 ctx（由接口层在识别租户之后调用）
+func WithTenant(ctx context.Context, tenant TenantID) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext 从 ctx 取出租户
+//
+// ctx 中没有租户时返回 DefaultTenantID：
+// 多租户之前的调用方（以及后台任务）都属于默认租户。
+func TenantFromContext(ctx context.Context) TenantID {
+	if tenant, ok := ctx.Value(tenantKey{}).(TenantID); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenantID
+}
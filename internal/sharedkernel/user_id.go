@@ -1,4 +1,4 @@
-package valueobject
+package sharedkernel
 
 import (
 	"errors"
@@ -30,7 +30,7 @@ var (
 //
 // 使用示例：
 //
-//	userID, err := valueobject.NewUserID(123)
+//	userID, err := sharedkernel.NewUserID(123)
 //	if err != nil {
 //	    // 处理无效ID
 //	}
@@ -1,9 +1,19 @@
+//go:build !fx
+
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
-	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"service/infrastructure/lifecycle"
+	"service/interface/handler"
+	"service/rpc_gen/kitex_gen/recommendation/recommendationadminservice"
 	"service/rpc_gen/kitex_gen/recommendation/recommendationservice"
 
 	"github.com/cloudwego/kitex/server"
@@ -11,10 +21,14 @@ import (
 
 // main 服务启动入口（使用 Wire 依赖注入）
 //
+// 使用 Fx 组装时（go build -tags fx），入口在 main_fx.go。
+//
 // Kitex 微服务的标准启动流程：
 // 1. 初始化依赖（使用 Wire 自动生成）
-// 2. 创建 Kitex Server
-// 3. 启动服务监听
+// 2. 启动预热（失败时退出）；-replay 模式下回放抓取文件后退出，不启动服务
+// 3. 创建 Kitex Server（配置了 http_server.addr 时同时启动 HTTP 接口，配置了 server.admin_port 时同时启动管理接口）
+// 4. 启动服务监听；收到 SIGINT、SIGTERM 后停止接收新请求，等待处理中的请求完成
+// 5. 逆序释放数据库、Redis、HTTP 客户端等资源（lifecycle.Closers）
 //
 // 依赖注入方式：
 // - 旧方式：手动在 initDependencies() 中创建所有对象（已移除）
@@ -51,7 +65,91 @@ func main() {
 	// - 创建所有依赖对象
 	// - 按正确顺序注入依赖
 	// - 返回最终的 Handler
-	recommendationHandler := InitializeRecommendationHandler()
+	replayFile := flag.String("replay", "", "replay captured traffic from this file against this build, then exit")
+	flag.Parse()
+
+	// Provider 把连接池、后台协程登记到 closers，退出时统一释放
+	closers := lifecycle.NewClosers()
+	recommendationServer := InitializeRecommendationServer(closers)
+	settings := recommendationServer.Server
+
+	// 回放模式：对当前版本回放抓取的流量，输出排序差异
+	if *replayFile != "" {
+		err := runReplay(context.Background(), recommendationServer.Handler, *replayFile, os.Stdout)
+		closeResources(closers, settings.ShutdownTimeout)
+		if err != nil {
+			log.Fatal("Replay failed:", err)
+		}
+		return
+	}
+
+	tenantResolver := InitializeTenantResolver()
+
+	// 退出信号（SIGINT、SIGTERM）：取消 ctx，Kitex、HTTP 接口、后台任务一起开始停止
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 启动预热：建立连接、预加载文案、预生成，完成之后才开始监听端口
+	if err := recommendationServer.Warmup.Run(ctx); err != nil {
+		closeResources(closers, settings.ShutdownTimeout)
+		log.Fatal("Warmup failed:", err)
+	}
+
+	// 后台任务：数据保留期清理（间隔见 retention.interval），退出时等它结束再关闭数据库连接
+	retentionDone := make(chan struct{})
+	go func() {
+		defer close(retentionDone)
+		InitializeRetentionJob(closers).Run(ctx)
+	}()
+
+	// 加密字段的重新加密（开启了 encryption 时）：密钥轮换后把旧数据换成当前密钥
+	reencryptionDone := make(chan struct{})
+	go func() {
+		defer close(reencryptionDone)
+		if reencryptionJob := InitializeReencryptionJob(closers); reencryptionJob != nil {
+			reencryptionJob.Run(ctx)
+		}
+	}()
+
+	// 关注事件的消费者（配置了 follow_events.topic 或 stream 时）：退出时等正在处理的事件结束
+	followEventsDone := make(chan struct{})
+	go func() {
+		defer close(followEventsDone)
+		if consumer := recommendationServer.FollowEvents; consumer != nil {
+			consumer.Run(ctx)
+		}
+	}()
+
+	// 推荐列表预计算（precompute.enabled 时）：为最近活跃的用户提前生成，在线请求直接使用
+	precomputeDone := make(chan struct{})
+	go func() {
+		defer close(precomputeDone)
+		if precomputeJob := recommendationServer.Precompute; precomputeJob != nil {
+			precomputeJob.Run(ctx)
+		}
+	}()
+
+	// HTTP 接口（内部管理后台）：和 Kitex 服务共用推荐服务，监听失败时整个服务退出
+	httpDone := make(chan struct{})
+	go func() {
+		defer close(httpDone)
+		httpServer := recommendationServer.HTTP
+		if httpServer == nil {
+			return
+		}
+		go func() {
+			log.Println("HTTP API starting on", httpServer.Addr())
+			if err := httpServer.ListenAndServe(); err != nil {
+				log.Println("HTTP API run failed:", err)
+				stop()
+			}
+		}()
+		<-ctx.Done()
+		// 优雅停止：不再接收新请求，等待处理中的请求完成
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			log.Println("HTTP API shutdown failed:", err)
+		}
+	}()
 
 	// 2. 创建 Kitex Server
 	// 配置服务选项：
@@ -59,24 +157,92 @@ func main() {
 	// - 中间件（日志、监控、限流等）
 	// - 服务注册与发现
 	// - 链路追踪
-	svr := recommendationservice.NewServer(
-		recommendationHandler,
-		server.WithServiceAddr(&net.TCPAddr{
-			IP:   net.IPv4(0, 0, 0, 0),
-			Port: 8888,
-		}),
+	addr := settings.Addr // server.port
+	transportOpts, err := serverTransportOptions(settings, addr)
+	if err != nil {
+		log.Fatal("mTLS setup failed:", err)
+	}
+	opts := append(transportOpts,
+		// 监控：按方法记录 RPC 耗时（最外层，被认证、租户识别拒绝的请求也统计）
+		server.WithMiddleware(recommendationServer.Metrics.Middleware),
+		// 认证：识别调用方身份（开启 mTLS 时按客户端证书识别；授权检查在 Handler 中）
+		server.WithMiddleware(recommendationServer.Authenticator.Middleware),
+		// 租户：识别请求的 App 品牌（缓存、数据库查询、打分按租户隔离）
+		server.WithMiddleware(tenantResolver.Middleware),
+		// 语言：识别客户端语言（推荐理由文案、分栏标题，没有时使用租户配置的语言）
+		server.WithMiddleware(handler.LocaleMiddleware),
+		// 优雅停止：ctx 取消时停止接收新请求，处理中的请求最多等待 shutdown_timeout_seconds
+		server.WithExitSignal(exitSignal(ctx)),
+		server.WithExitWaitTime(settings.ShutdownTimeout),
 		// 在实际项目中，还会添加：
 		// server.WithMiddleware(...),      // 中间件
-		// server.WithRegistry(...),        // 服务注册
 		// server.WithSuite(...),           // 链路追踪
 		// server.WithLimit(...),           // 限流配置
 	)
+	// 服务注册：profile 中的 registry 端口为 etcd / nacos 时注册到 server.registry
+	opts = append(opts, serverRegistryOptions(settings)...)
+	svr := recommendationservice.NewServer(recommendationServer.Handler, opts...)
 
-	// 3. 启动服务
-	log.Println("Recommendation Service starting on :8888 (using Wire)")
-	err := svr.Run()
-	if err != nil {
-		log.Fatal("Server run failed:", err)
+	// 管理接口（server.admin_port）：单独的 Kitex Server，只对内部管理后台开放；和推荐接口一起停止
+	adminDone := make(chan struct{})
+	if settings.AdminAddr == nil {
+		close(adminDone)
+	} else {
+		adminOpts, err := adminServerOptions(settings, recommendationServer.Metrics, recommendationServer.Authenticator, tenantResolver)
+		if err != nil {
+			log.Fatal("Admin server setup failed:", err)
+		}
+		adminSvr := recommendationadminservice.NewServer(recommendationServer.Admin, append(adminOpts, server.WithExitSignal(exitSignal(ctx)))...)
+		go func() {
+			defer close(adminDone)
+			log.Printf("Recommendation Admin Service starting on %s", settings.AdminAddr)
+			if err := adminSvr.Run(); err != nil {
+				log.Println("Admin server run failed:", err)
+				stop()
+			}
+		}()
+	}
+
+	// 3. 启动服务（收到退出信号、或者 HTTP 接口监听失败时 Run 返回）
+	log.Printf("Recommendation Service starting on %s (using Wire)", addr)
+	runErr := svr.Run()
+
+	// 4. 等待 HTTP 接口、管理接口、后台任务停止，再释放连接（顺序不能反：处理中的请求还在使用连接池）
+	stop()
+	<-httpDone
+	<-adminDone
+	<-retentionDone
+	<-reencryptionDone
+	<-followEventsDone
+	<-precomputeDone
+	closeResources(closers, settings.ShutdownTimeout)
+	if runErr != nil {
+		log.Fatal("Server run failed:", runErr)
+	}
+	log.Println("Recommendation Service stopped")
+}
+
+// exitSignal Kitex 的退出信号：ctx 取消时 Server.Run 开始优雅停止
+//
+// Kitex 默认自己监听 SIGINT、SIGTERM；这里换成同一个 ctx，
+// HTTP 接口监听失败时也能让 Kitex 停止，整个进程只有一处处理退出信号。
+func exitSignal(ctx context.Context) func() <-chan error {
+	return func() <-chan error {
+		ch := make(chan error, 1)
+		go func() {
+			<-ctx.Done()
+			ch <- nil
+		}()
+		return ch
+	}
+}
+
+// closeResources 释放依赖初始化时登记的资源（数据库、Redis、HTTP 客户端的空闲连接等），最多等待 timeout
+func closeResources(closers *lifecycle.Closers, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := closers.Close(ctx); err != nil {
+		log.Println("Closing resources failed:", err)
 	}
 }
 
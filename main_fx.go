@@ -0,0 +1,140 @@
+//go:build fx
+
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/cloudwego/kitex/server"
+	"go.uber.org/fx"
+
+	"service/interface/handler"
+	httpapi "service/interface/http"
+	"service/rpc_gen/kitex_gen/recommendation/recommendationadminservice"
+	"service/rpc_gen/kitex_gen/recommendation/recommendationservice"
+)
+
+// main 服务启动入口（使用 Fx 组装，go build -tags fx）
+//
+// 启动顺序由 Fx 管理：构造依赖 → 按注册顺序执行 OnStart → 等待退出信号（SIGINT、SIGTERM）→ 逆序执行 OnStop。
+// OnStop 的顺序：HTTP 接口、Kitex 服务停止接收请求 → 后台任务结束 → 释放连接（registerClosers）。
+func main() {
+	fx.New(
+		infrastructureModule,
+		repositoryModule,
+		domainModule,
+		applicationModule,
+		handlerModule,
+		jobModule,
+		fx.Invoke(registerServer, registerAdminServer, registerHTTPServer),
+		// 启动预热有自己的超时（warmup.timeout_seconds），这里放宽 Fx 默认的 15 秒
+		fx.StartTimeout(time.Minute),
+		// 停止时等待处理中的请求有自己的超时（server.shutdown_timeout_seconds），同样放宽
+		fx.StopTimeout(time.Minute),
+	).Run()
+}
+
+// registerServer 创建 Kitex Server，挂到生命周期上
+func registerServer(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	recommendationHandler *handler.RecommendationHandler,
+	tenantResolver *handler.TenantResolver,
+	rpcMetrics *handler.RPCMetrics,
+	authenticator *handler.Authenticator,
+	settings *serverSettings,
+) error {
+	addr := settings.Addr
+	transportOpts, err := serverTransportOptions(settings, addr)
+	if err != nil {
+		return err
+	}
+	opts := append(transportOpts,
+		server.WithMiddleware(rpcMetrics.Middleware),
+		server.WithMiddleware(authenticator.Middleware),
+		server.WithMiddleware(tenantResolver.Middleware),
+		// 停止时处理中的请求最多等待 shutdown_timeout_seconds
+		server.WithExitWaitTime(settings.ShutdownTimeout),
+	)
+	// 服务注册：profile 中的 registry 端口为 etcd / nacos 时注册到 server.registry
+	svr := recommendationservice.NewServer(recommendationHandler, append(opts, serverRegistryOptions(settings)...)...)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			// svr.Run 会阻塞到服务停止，放到后台执行；启动失败时让整个应用退出
+			go func() {
+				if err := svr.Run(); err != nil {
+					log.Println("Server run failed:", err)
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			log.Printf("Recommendation Service starting on %s (using Fx)", addr)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			return svr.Stop()
+		},
+	})
+	return nil
+}
+
+// registerAdminServer 管理接口的 Kitex Server 挂到生命周期上（没有配置 server.admin_port 时不启动）
+func registerAdminServer(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	adminHandler *handler.AdminHandler,
+	tenantResolver *handler.TenantResolver,
+	rpcMetrics *handler.RPCMetrics,
+	authenticator *handler.Authenticator,
+	settings *serverSettings,
+) error {
+	if settings.AdminAddr == nil {
+		return nil
+	}
+	opts, err := adminServerOptions(settings, rpcMetrics, authenticator, tenantResolver)
+	if err != nil {
+		return err
+	}
+	svr := recommendationadminservice.NewServer(adminHandler, opts...)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				if err := svr.Run(); err != nil {
+					log.Println("Admin server run failed:", err)
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			log.Printf("Recommendation Admin Service starting on %s (using Fx)", settings.AdminAddr)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			return svr.Stop()
+		},
+	})
+	return nil
+}
+
+// registerHTTPServer HTTP 接口挂到生命周期上（没有配置 http_server.addr 时为 nil）
+//
+// 停止时优雅停止：不再接收新请求，等待处理中的请求完成。
+func registerHTTPServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, httpServer *httpapi.Server) {
+	if httpServer == nil {
+		return
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				if err := httpServer.ListenAndServe(); err != nil {
+					log.Println("HTTP API run failed:", err)
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			log.Println("HTTP API starting on", httpServer.Addr())
+			return nil
+		},
+		OnStop: httpServer.Shutdown,
+	})
+}
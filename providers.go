@@ -0,0 +1,1927 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"service/application/dto"
+	"service/application/service"
+	"service/config"
+	"service/domain/event"
+	domainRepository "service/domain/repository"
+	domainService "service/domain/service"
+	"service/domain/valueobject"
+	"service/infrastructure/cache"
+	"service/infrastructure/calibration"
+	"service/infrastructure/capture"
+	"service/infrastructure/client"
+	"service/infrastructure/crypto"
+	"service/infrastructure/discovery"
+	"service/infrastructure/health"
+	"service/infrastructure/lifecycle"
+	"service/infrastructure/messaging"
+	"service/infrastructure/migration"
+	"service/infrastructure/mtls"
+	"service/infrastructure/observability"
+	"service/infrastructure/persistence"
+	"service/infrastructure/repository"
+	"service/infrastructure/resilience"
+	"service/infrastructure/secrets"
+	"service/interface/handler"
+	httpapi "service/interface/http"
+	"service/interface/job"
+	"service/internal/contentintegration"
+)
+
+// Provider 函数定义
+//
+// 这些函数告诉 Wire 如何构造每个对象。
+// Wire 会分析这些函数的参数和返回值，自动解决依赖关系。
+//
+// 为什么放在单独的文件（不带构建标签）？
+// wire.go 只在 wire 工具分析时参与编译（wireinject），
+// 生成的 wire_gen.go 和 Fx 组装（fx.go）都要调用这些函数，所以它们必须在普通构建中可见。
+
+// provideConfig 提供服务配置
+//
+// 配置文件路径来自环境变量 CONFIG_FILE（默认 config/config.yaml），
+// 环境来自 APP_PROFILE（dev、staging、prod），决定下面每个 Provider 使用哪种实现：
+// 从 mock 切换到真实依赖只需要换环境，不需要修改代码重新编译。
+//
+// 配置文件不存在时使用 dev 的默认实现（mock、内存），本地可以直接运行。
+// 启动时把生效的装配（端口 → 实现 → 地址）写入日志。
+func provideConfig() *config.Config {
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = "config/config.yaml"
+	}
+	cfg, err := config.Load(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		slog.Warn("config file not found, using dev defaults", "path", path)
+		cfg = config.Default()
+	case err != nil:
+		panic(err)
+	}
+	for _, entry := range cfg.WiringReport() {
+		slog.Info("effective wiring",
+			"profile", cfg.Profile,
+			"port", entry.Port,
+			"implementation", entry.Implementation,
+			"target", entry.Target,
+		)
+	}
+	return cfg
+}
+
+// provideTenants 提供租户配置（config.yaml 的 tenants，配置错误时启动失败）
+func provideTenants(cfg *config.Config) *service.Tenants {
+	configs := make([]service.TenantConfig, 0, len(cfg.Tenants))
+	for _, t := range cfg.Tenants {
+		configs = append(configs, service.TenantConfig{
+			ID:     valueobject.TenantID(t.ID),
+			Locale: t.Locale,
+			ScoringWeights: valueobject.ScoringWeights{
+				PerRelatedUser:       t.Scoring.PerRelatedUser,
+				PerRecentPost:        t.Scoring.PerRecentPost,
+				PerHundredEngagement: t.Scoring.PerHundredEngagement,
+			},
+		})
+	}
+	tenants, err := service.NewTenants(configs)
+	if err != nil {
+		panic(err)
+	}
+	return tenants
+}
+
+// provideDatabase 提供数据库连接（当前环境没有端口使用 MySQL 时为 nil）
+//
+// 账号密码优先从 secrets 读取（secrets.paths.database，Vault 动态账号），
+// 读取不到时使用配置文件中的账号（本地开发）。
+//
+// 订阅动态账号的轮换：连接池通过 RotatingConnector 建立连接，轮换后新建的连接使用新账号。
+//
+// 连接池登记到 closers，进程退出时关闭。
+func provideDatabase(cfg *config.Config, secretsProvider secrets.Provider, closers *lifecycle.Closers) *gorm.DB {
+	if !cfg.NeedsDatabase() {
+		return nil
+	}
+	mysqlCfg := cfg.Database.MySQL
+	user, password := mysqlCfg.User, mysqlCfg.Password
+	credsPath := cmp.Or(cfg.Secrets.Paths.Database, "database/creds/recommendation")
+	creds, err := secretsProvider.Get(context.Background(), credsPath)
+	switch {
+	case err == nil:
+		user, password = creds["username"], creds["password"]
+	case !errors.Is(err, secrets.ErrSecretNotFound):
+		panic(err)
+	}
+
+	connector, err := persistence.NewRotatingConnector("mysql", mysqlCfg.DSN(user, password))
+	if err != nil {
+		panic(err)
+	}
+	sqlDB := sql.OpenDB(connector)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	// 动态账号轮换：之后新建的连接使用新账号，关闭空闲连接（它们使用旧账号，租约到期后被吊销）
+	secretsProvider.Subscribe(credsPath, func(creds map[string]string) {
+		if err := connector.Rotate(mysqlCfg.DSN(creds["username"], creds["password"])); err != nil {
+			return // DSN 和启动时的格式相同，不会解析失败
+		}
+		sqlDB.SetMaxIdleConns(0)
+		sqlDB.SetMaxIdleConns(mysqlCfg.MaxIdleConns)
+	})
+	sqlDB.SetMaxIdleConns(mysqlCfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(mysqlCfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(mysqlCfg.ConnMaxLifetime) * time.Second)
+	closers.Add("database", func(context.Context) error { return sqlDB.Close() })
+	return db
+}
+
+// provideTransactionManager 提供事务边界（没有连接数据库时为 nil：每个仓储的写入各自提交）
+//
+// 只有 MySQL 实现的仓储参与事务；内存、Redis 实现的写入不受事务控制。
+func provideTransactionManager(db *gorm.DB) service.TransactionManager {
+	if db == nil {
+		return nil
+	}
+	return persistence.NewGormTransactionManager(db)
+}
+
+// provideRedis 提供 Redis 连接（当前环境没有端口使用 Redis、也不消费关注事件时为 nil）
+//
+// 密码优先从 secrets 读取（secret/data/redis 的 password），读取不到时使用配置文件中的密码。
+// 连接池登记到 closers，进程退出时关闭。
+func provideRedis(cfg *config.Config, secretsProvider secrets.Provider, closers *lifecycle.Closers) redis.UniversalClient {
+	if !cfg.Uses(config.ImplRedis) && cfg.FollowEvents.Stream == "" {
+		return nil
+	}
+	password, err := secrets.Value(context.Background(), secretsProvider, cmp.Or(cfg.Secrets.Paths.Redis, "secret/data/redis"), "password")
+	switch {
+	case errors.Is(err, secrets.ErrSecretNotFound):
+		password = cfg.Redis.Password
+	case err != nil:
+		panic(err)
+	}
+	rdb := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:        []string{cfg.Redis.Addr()},
+		Password:     password,
+		DB:           cfg.Redis.DB,
+		PoolSize:     cfg.Redis.PoolSize,
+		MinIdleConns: cfg.Redis.MinIdleConns,
+	})
+	closers.Add("redis", func(context.Context) error { return rdb.Close() })
+	return rdb
+}
+
+// provideMTLS 提供应用层 mTLS 的证书（mtls.cert_file 为空时为 nil）
+//
+// Kitex 服务的监听（serverTransportOptions）、出站 HTTP（mtlsHTTPOptions）、Kitex 客户端（rpcClientOptions）共用。
+// 入站只接受 mtls.clients 中的调用方；出站按 mtls.servers 校验下游身份。
+// 后台检查证书文件的协程登记到 closers，进程退出时停止。
+func provideMTLS(cfg *config.Config, closers *lifecycle.Closers) *mtls.Reloader {
+	mtlsCfg := cfg.MTLS
+	if !mtlsCfg.Enabled() {
+		return nil
+	}
+	clientIDs := make([]string, 0, len(mtlsCfg.Clients))
+	for id := range mtlsCfg.Clients {
+		clientIDs = append(clientIDs, id)
+	}
+	sort.Strings(clientIDs)
+	reloader, err := mtls.NewReloader(mtls.Config{
+		CertFile:         mtlsCfg.CertFile,
+		KeyFile:          mtlsCfg.KeyFile,
+		CAFile:           mtlsCfg.CAFile,
+		AllowedClientIDs: clientIDs,
+		ReloadInterval:   mtlsCfg.ReloadInterval,
+	})
+	if err != nil {
+		panic(err)
+	}
+	closers.AddFunc("mtls reloader", reloader.Close)
+	return reloader
+}
+
+// mtlsHTTPOptions 辅助函数：开启 mTLS 时出站 HTTP 使用 mTLS 传输层（没有开启时为空）
+//
+// 按 http_clients 中的地址取主机名，按 mtls.servers 中的 SPIFFE ID 校验下游。
+// 返回的选项替换传输层，必须放在 client.WithGovernor 之前。
+func mtlsHTTPOptions(cfg *config.Config, reloader *mtls.Reloader, port config.Port) []client.HTTPClientOption {
+	if reloader == nil {
+		return nil
+	}
+	target, err := url.Parse(cfg.HTTPClients[port])
+	if err != nil {
+		panic(fmt.Errorf("http_clients.%s: %w", port, err))
+	}
+	return []client.HTTPClientOption{
+		client.WithTransport(mtls.HTTPTransport(reloader, target.Hostname(), cfg.MTLS.Servers[string(port)])),
+	}
+}
+
+// defaultAvatarURL 用户没有头像（或头像地址格式不对）时展示的默认头像
+const defaultAvatarURL = "https://example.com/avatar/default.png"
+
+// newHotCache 辅助方法：按 hot_cache 中的配置创建进程内缓存（为 0 的值使用默认值，创建失败时启动失败）
+func newHotCache[V any](name string, size config.HotCacheSizeConfig, defaultMaxCost int64, defaultTTL time.Duration, metrics service.Metrics) *cache.HotCache[V] {
+	ttl := defaultTTL
+	if size.TTLSeconds > 0 {
+		ttl = time.Duration(size.TTLSeconds) * time.Second
+	}
+	hot, err := cache.NewHotCache[V](name, cmp.Or(size.MaxCost, defaultMaxCost), ttl, metrics)
+	if err != nil {
+		panic(fmt.Sprintf("hot_cache.%s: %v", name, err))
+	}
+	return hot
+}
+
+// provideUserRPCClient 提供 User RPC 客户端
+//
+// 目前只有 mock 实现（user 服务的 Kitex 客户端还没有生成）。
+// 接入后在 config 中为 user_rpc 增加 rpc 实现，在这里按环境选择：
+//
+//	case config.ImplRPC:
+//	    api = newKitexUserServiceAPI(userservice.MustNewClient("user-service", rpcClientOptions(cfg, registry, reloader, "user-service")...)) // 实现 client.UserServiceAPI
+//
+// 实例地址来自注册中心（provideServiceRegistry），没有使用注册中心的环境读取 rpc_clients。
+//
+// 请求合并：
+// 用 CoalescingUserRPCClient 包装真实客户端，
+// 把 5ms 内并发的用户信息查询合并成一次批量调用，降低 user 服务的 QPS。
+//
+// 热点缓存：
+// 最外层用 CachedUserRPCClient 缓存热门用户的资料（进程内 → Redis），
+// 只有缓存都没命中的用户才会进入请求合并、访问 user 服务。
+// 当前环境的 cache 端口不是 redis 时只使用进程内缓存。
+//
+// 防腐层：
+// 最内层用 UserServiceACL 翻译 user 服务的数据和错误码，
+// 缓存、请求合并、应用层看到的都是规整之后的 UserInfo。
+func provideUserRPCClient(cfg *config.Config, rdb redis.UniversalClient, metrics service.Metrics) service.UserRPCClient {
+	hot := newHotCache[*service.UserInfo]("user_info", cfg.HotCache.UserInfo, 100000, time.Minute, metrics)
+	acl := client.NewUserServiceACL(client.NewMockUserServiceAPI(), defaultAvatarURL)
+	coalescing := client.NewCoalescingUserRPCClient(acl, 5*time.Millisecond, 100)
+	return cache.NewCachedUserRPCClient(coalescing, hot, cacheRedis(cfg, rdb), 10*time.Minute, cache.DefaultKeyStrategy())
+}
+
+// provideContentServiceClient 提供 Content 服务客户端
+//
+// 按环境选择实现：
+// - none：不使用内容服务，从本地数据库读取帖子（ContentRepository）
+// - http：调用 http_clients.content_service
+//
+// RPC 版本（contentintegration.NewRPCClient）还只是示例骨架，生成 content 服务的 Kitex 代码后再开放。
+func provideContentServiceClient(cfg *config.Config, governor *resilience.Governor, reloader *mtls.Reloader) service.ContentServiceClient {
+	switch cfg.Implementation(config.PortContentService) {
+	case config.ImplHTTP:
+		// 帖子查询有长尾：200ms 没有返回时对冲
+		return contentintegration.NewHTTPClient(cfg.HTTPClients[config.PortContentService],
+			append(mtlsHTTPOptions(cfg, reloader, config.PortContentService),
+				client.WithGovernor(governor, string(config.PortContentService), client.RetryPolicy{
+					MaxRetries: 1,
+					Backoff:    50 * time.Millisecond,
+					HedgeDelay: 200 * time.Millisecond,
+				}))...)
+	default:
+		return nil
+	}
+}
+
+// provideReasonConfigClient 提供推荐理由配置服务客户端
+//
+// 这是一个可选的依赖（可以为 nil）：
+// - none：使用 RecommendationReason 中的默认文案
+// - http：调用 http_clients.reason_config
+//
+// 文案在进程内缓存：1 分钟后在后台刷新（期间仍然返回旧文案），30 分钟没有刷新成功时删除。
+func provideReasonConfigClient(
+	cfg *config.Config,
+	governor *resilience.Governor,
+	reloader *mtls.Reloader,
+	metrics service.Metrics,
+	closers *lifecycle.Closers,
+) service.ReasonTextConfigClient {
+	switch cfg.Implementation(config.PortReasonConfig) {
+	case config.ImplHTTP:
+		httpClient := client.NewReasonTextConfigHTTPClient(cfg.HTTPClients[config.PortReasonConfig],
+			append(mtlsHTTPOptions(cfg, reloader, config.PortReasonConfig),
+				client.WithGovernor(governor, string(config.PortReasonConfig), client.RetryPolicy{
+					MaxRetries: 1,
+					Backoff:    50 * time.Millisecond,
+				}))...)
+		texts, err := cache.NewReadThrough[string]("reason_texts", 10_000, time.Minute, 30*time.Minute, metrics)
+		if err != nil {
+			panic(err)
+		}
+		cached := client.NewCachedReasonTextConfigClient(httpClient, texts)
+		closers.AddFunc("reason text cache", cached.Close)
+		return cached
+	default:
+		return nil
+	}
+}
+
+// provideDownstreamGovernor 提供下游调用治理器（所有 HTTP 下游共用，按依赖名分别统计）
+//
+// 重试、对冲有预算，下游不健康时自动收紧；成功率低于 50% 时开始在本地拒绝一部分调用。
+// 目前下游还没有熔断器，接入后通过 resilience.WithBreakerState 配合。
+//
+// 每次调用的结果按依赖上报到监控（MetricDownstreamRequests），用于计算下游错误率。
+//
+// HTTP 下游客户端都使用默认的 Transport（外面包一层治理），
+// 进程退出时关闭其中的空闲连接（登记到 closers）。
+func provideDownstreamGovernor(closers *lifecycle.Closers, metrics service.Metrics) *resilience.Governor {
+	closers.AddFunc("http clients", http.DefaultClient.CloseIdleConnections)
+	return resilience.NewGovernor(resilience.DefaultGovernorConfig(), resilience.WithGovernorMetrics(metrics))
+}
+
+// provideSecretsProvider 提供密钥读取
+//
+// 配置了 secrets.vault_address 时从 Vault 读取（动态凭据自动续租），
+// 否则从环境变量读取（本地开发）。
+//
+// 其他 Provider 通过它读取密码，而不是直接读取明文环境变量（见 provideDatabase、provideRedis），
+// 各类密钥的路径来自 secrets.paths。
+//
+// 使用 Vault 时，进程退出时结束租约续期（登记到 closers）。
+func provideSecretsProvider(cfg *config.Config, closers *lifecycle.Closers) secrets.Provider {
+	provider := secrets.NewProvider(cfg.Secrets.VaultAddress)
+	if vault, ok := provider.(*secrets.VaultProvider); ok {
+		closers.AddFunc("vault", vault.Close)
+	}
+	return provider
+}
+
+// provideFieldCodec 提供敏感字段的加密编解码器（config.yaml 的 encryption）
+//
+// encryption.enabled 为 false、或者当前环境没有端口使用 MySQL 时为 nil（明文存储）。
+//
+// 密钥不和数据放在一起：密钥环从 secrets 读取（kms_key_ring 路径，生产环境是 Vault），
+// 当前密钥、盲索引密钥不在密钥环中时启动失败。
+func provideFieldCodec(cfg *config.Config, secretsProvider secrets.Provider) *crypto.FieldCodec {
+	encryption := cfg.Encryption
+	if !encryption.Enabled || !cfg.NeedsDatabase() {
+		return nil
+	}
+	if encryption.KMSKeyRing == "" || encryption.CurrentKeyID == "" || encryption.BlindIndexKeyID == "" {
+		panic("config: encryption.kms_key_ring, current_key_id and blind_index_key_id are required when encryption is enabled")
+	}
+	ctx := context.Background()
+	keys, err := crypto.NewSecretsKeyManager(ctx, secretsProvider, encryption.KMSKeyRing, encryption.CurrentKeyID)
+	if err != nil {
+		panic(fmt.Sprintf("encryption: %v", err))
+	}
+	if _, err := keys.KeyByID(ctx, encryption.BlindIndexKeyID); err != nil {
+		panic(fmt.Sprintf("encryption: blind index %v", err))
+	}
+	return crypto.NewFieldCodec(keys, crypto.WithBlindIndexKey(encryption.BlindIndexKeyID))
+}
+
+// provideRedactor 提供日志和链路追踪共用的脱敏器（config.yaml 的 log.redaction）
+//
+// 用户ID 替换为带密钥的哈希，昵称、简介、帖子内容只保留长度；调试环境可以配置白名单。
+//
+// 哈希密钥优先从 secrets 读取（log/redaction 的 hash_key；本地开发时是环境变量 LOG_REDACTION_HASH_KEY），
+// 读取不到时使用 log.redaction.hash_key。都没有时用户ID完全掩码，不使用没有密钥的哈希。
+func provideRedactor(cfg *config.Config, secretsProvider secrets.Provider) *observability.Redactor {
+	redaction := cfg.Log.Redaction
+	mode := observability.UserIDMode(cmp.Or(redaction.UserIDMode, string(observability.UserIDModeHash)))
+	if mode != observability.UserIDModeHash && mode != observability.UserIDModeMask {
+		panic(fmt.Sprintf("config: unknown log.redaction.user_id_mode %q", redaction.UserIDMode))
+	}
+	hashKey, err := secrets.Value(context.Background(), secretsProvider, cmp.Or(cfg.Secrets.Paths.LogRedaction, "log/redaction"), "hash_key")
+	switch {
+	case errors.Is(err, secrets.ErrSecretNotFound):
+		hashKey = redaction.HashKey
+	case err != nil:
+		panic(err)
+	}
+	return observability.NewRedactor(observability.RedactionConfig{
+		Environment: redaction.Environment,
+		UserIDMode:  mode,
+		HashKey:     hashKey,
+		Allowlist:   redaction.Allowlist,
+	})
+}
+
+// provideLogger 提供日志实现（所有日志都经过 RedactingLogger 脱敏）
+func provideLogger(redactor *observability.Redactor) service.Logger {
+	return observability.NewRedactingLogger(observability.NewSlogLogger(slog.Default()), redactor)
+}
+
+// provideTracer 提供链路追踪实现（span 属性经过 RedactingTracer 脱敏）
+//
+// 没有接入追踪系统：span 结束时写一条 debug 日志（SlogTracer）。
+func provideTracer(redactor *observability.Redactor) service.Tracer {
+	return observability.NewRedactingTracer(observability.NewSlogTracer(slog.Default()), redactor)
+}
+
+// prometheusMetrics 进程内唯一的监控指标
+//
+// Wire 的每个 Injector 各自构造一份对象（推荐服务、管理接口、数据保留期任务），
+// 监控指标必须共用一份，GET /metrics 才能输出所有 Injector 中记录的指标。
+var prometheusMetrics = observability.NewPrometheusMetrics(
+	// 生成的推荐数：默认分桶是按秒设计的，换成按数量的分桶
+	observability.WithHistogramBuckets(service.MetricGeneratedCandidates, []float64{0, 5, 10, 20, 50, 100, 200, 500}),
+)
+
+// providePrometheusMetrics 提供 Prometheus 监控指标（http_server.metrics_addr 上的 GET /metrics 输出）
+func providePrometheusMetrics() *observability.PrometheusMetrics {
+	return prometheusMetrics
+}
+
+// provideMetrics 提供监控指标接口（应用层、基础设施层、接口层记录指标使用）
+//
+// 各组件记录的指标见 service.Metric* 常量：
+// - RPC 每个方法的耗时（handler.RPCMetrics）
+// - 每次生成的推荐数（RecommendationService）
+// - 热点缓存、推荐列表缓存的命中次数（HotCache、ReadThrough、RecommendationService）
+// - 下游 HTTP 调用的结果（resilience.Governor），按依赖计算错误率
+// - 依赖降级次数（DegradationPolicy），如内容服务 → 本地数据库、配置服务 → 本地文案
+func provideMetrics(prometheus *observability.PrometheusMetrics) service.Metrics {
+	return prometheus
+}
+
+// provideSocialGraphRepository 提供社交图谱仓储
+//
+// 按环境选择实现：mock（示例数据）或 mysql（follows 表）。
+//
+// 存储迁移：
+// dual_read 中配置了 social_graph 时，同时查询 shadow 实现并比较（DualReadSocialGraphRepository），
+// 返回的仍然是 profile 中选择的实现的结果。双读在缓存之下：只有回源的查询参与比较。
+//
+// 读穿透缓存（social_graph_cache，disabled 时直接返回上面的实现）：
+// 用 CachedSocialGraphRepository 缓存关注列表、最近关注（进程内 → Redis → 数据库），
+// 当前环境的 cache 端口不是 redis 时只使用进程内缓存。
+// 配置了 invalidation_channel 时订阅失效消息，进程退出时停止订阅（登记到 closers）。
+func provideSocialGraphRepository(
+	cfg *config.Config,
+	db *gorm.DB,
+	rdb redis.UniversalClient,
+	logger service.Logger,
+	metrics service.Metrics,
+	closers *lifecycle.Closers,
+) domainRepository.SocialGraphRepository {
+	next := socialGraphRepository(cfg.Implementation(config.PortSocialGraph), db)
+	if dualRead, ok := cfg.DualRead[config.PortSocialGraph]; ok {
+		reader := migration.NewDualReader(string(config.PortSocialGraph), dualRead.SampleRate, logger, metrics)
+		next = migration.NewDualReadSocialGraphRepository(next, socialGraphRepository(dualRead.Shadow, db), reader)
+	}
+	cacheCfg := cfg.SocialGraphCache
+	if cacheCfg.Disabled {
+		return next
+	}
+
+	hot := newHotCache[[]valueobject.UserID]("followings", cfg.HotCache.Followings, 1_000_000, 30*time.Second, metrics)
+
+	cacheRDB := cacheRedis(cfg, rdb)
+	var opts []cache.CachedSocialGraphOption
+	var bus *cache.InvalidationBus
+	if cacheRDB != nil && cacheCfg.InvalidationChannel != "" {
+		bus = cache.NewInvalidationBus(cacheRDB, cacheCfg.InvalidationChannel)
+		opts = append(opts, cache.WithSocialGraphInvalidation(bus))
+	}
+	ttl := time.Duration(cmp.Or(cacheCfg.TTLSeconds, 120)) * time.Second
+	cached := cache.NewCachedSocialGraphRepository(next, hot, cacheRDB, ttl, cache.DefaultKeyStrategy(), opts...)
+	if bus != nil {
+		closers.AddFunc("social graph invalidations", bus.Subscribe(context.Background(), cached.HandleInvalidation))
+	}
+	return cached
+}
+
+// socialGraphCacheInvalidator 辅助函数：带缓存的社交图谱仓储（social_graph_cache 没有关闭时），否则为 nil
+func socialGraphCacheInvalidator(socialGraphRepo domainRepository.SocialGraphRepository) service.UserCacheInvalidator {
+	if invalidator, ok := socialGraphRepo.(service.UserCacheInvalidator); ok {
+		return invalidator
+	}
+	return nil
+}
+
+// socialGraphRepository 辅助函数：按实现创建社交图谱仓储
+func socialGraphRepository(impl config.Implementation, db *gorm.DB) domainRepository.SocialGraphRepository {
+	switch impl {
+	case config.ImplMySQL:
+		return persistence.NewSocialGraphRepository(db)
+	default:
+		return repository.NewMockSocialGraphRepository()
+	}
+}
+
+// provideContentRepository 提供内容仓储（mock 或 mysql）
+//
+// 用 CachedContentRepository 缓存每个候选的最近帖子数（进程内读穿透缓存）：
+// 超过 1 分钟的条目在后台刷新，超过 5 分钟没有刷新的条目被删除（下一次查询同步回源）。
+func provideContentRepository(cfg *config.Config, db *gorm.DB, metrics service.Metrics) domainRepository.ContentRepository {
+	var next domainRepository.ContentRepository
+	switch cfg.Implementation(config.PortContentRepository) {
+	case config.ImplMySQL:
+		next = persistence.NewContentRepository(db)
+	default:
+		next = repository.NewMockContentRepository()
+	}
+	recentPosts, err := cache.NewReadThrough[int]("recent_post_counts", 1_000_000, time.Minute, 5*time.Minute, metrics)
+	if err != nil {
+		panic(err)
+	}
+	return cache.NewCachedContentRepository(next, recentPosts)
+}
+
+// provideFollowerRepository 提供反向关注关系仓储（和社交图谱使用同一个端口：mock 或 mysql）
+//
+// 只有触发式推荐的扇出使用，不经过关注列表缓存。
+func provideFollowerRepository(cfg *config.Config, db *gorm.DB) domainRepository.FollowerRepository {
+	switch cfg.Implementation(config.PortSocialGraph) {
+	case config.ImplMySQL:
+		return persistence.NewFollowerRepository(db)
+	default:
+		return repository.NewMockFollowerRepository()
+	}
+}
+
+// provideTopicRepository 提供话题关注仓储（mock 或 mysql）
+func provideTopicRepository(cfg *config.Config, db *gorm.DB) domainRepository.TopicRepository {
+	switch cfg.Implementation(config.PortTopics) {
+	case config.ImplMySQL:
+		return persistence.NewTopicRepository(db)
+	default:
+		return repository.NewMockTopicRepository()
+	}
+}
+
+// provideGroupMembershipRepository 提供小组成员关系仓储（mock 或 mysql）
+func provideGroupMembershipRepository(cfg *config.Config, db *gorm.DB) domainRepository.GroupMembershipRepository {
+	switch cfg.Implementation(config.PortGroups) {
+	case config.ImplMySQL:
+		return persistence.NewGroupMembershipRepository(db)
+	default:
+		return repository.NewMockGroupMembershipRepository()
+	}
+}
+
+// providePopularUsersRepository 提供冷启动名单仓储（mock 或 mysql；none 时为 nil，不做冷启动推荐）
+func providePopularUsersRepository(cfg *config.Config, db *gorm.DB) domainRepository.PopularUsersRepository {
+	switch cfg.Implementation(config.PortPopularUsers) {
+	case config.ImplMySQL:
+		return persistence.NewPopularUsersRepository(db)
+	case config.ImplNone:
+		return nil
+	default:
+		return repository.NewMockPopularUsersRepository()
+	}
+}
+
+// provideActiveUsersRepository 提供最近活跃用户仓储（memory 或 redis；none 时为 nil，不记录）
+//
+// Redis 中保留 precompute.active_window_seconds 以内活跃过的用户。
+func provideActiveUsersRepository(cfg *config.Config, rdb redis.UniversalClient) domainRepository.ActiveUsersRepository {
+	switch cfg.Implementation(config.PortActiveUsers) {
+	case config.ImplRedis:
+		return persistence.NewRedisActiveUsersRepository(rdb, precomputeActiveWindow(cfg))
+	case config.ImplMemory:
+		return repository.NewMemoryActiveUsersRepository()
+	default:
+		return nil
+	}
+}
+
+// provideInteractionRepository 提供互动记录仓储（mock 或 mysql）
+func provideInteractionRepository(cfg *config.Config, db *gorm.DB) domainRepository.InteractionRepository {
+	switch cfg.Implementation(config.PortInteractions) {
+	case config.ImplMySQL:
+		return persistence.NewInteractionRepository(db)
+	default:
+		return repository.NewMockInteractionRepository()
+	}
+}
+
+// provideBlockRepository 提供屏蔽关系仓储（mock 或 mysql）
+func provideBlockRepository(cfg *config.Config, db *gorm.DB) domainRepository.BlockRepository {
+	switch cfg.Implementation(config.PortBlocks) {
+	case config.ImplMySQL:
+		return persistence.NewBlockRepository(db)
+	default:
+		return repository.NewMockBlockRepository()
+	}
+}
+
+// provideRecommendationRepository 提供推荐列表仓储（none、memory 或 mysql）
+//
+// none 时返回 nil：不保存生成的推荐列表，每次请求都重新生成。
+func provideRecommendationRepository(cfg *config.Config, db *gorm.DB) domainRepository.RecommendationRepository {
+	switch cfg.Implementation(config.PortRecommendations) {
+	case config.ImplMySQL:
+		return persistence.NewRecommendationRepository(db)
+	case config.ImplMemory:
+		return repository.NewMemoryRecommendationRepository()
+	default:
+		return nil
+	}
+}
+
+// provideTrustRepository 提供风控分级仓储（mock 或 http：http_clients.trust）
+func provideTrustRepository(cfg *config.Config, governor *resilience.Governor, reloader *mtls.Reloader) domainRepository.TrustRepository {
+	switch cfg.Implementation(config.PortTrust) {
+	case config.ImplHTTP:
+		// 超时只有 500ms：只重试一次，间隔很短
+		return client.NewTrustSafetyHTTPClient(cfg.HTTPClients[config.PortTrust],
+			append(mtlsHTTPOptions(cfg, reloader, config.PortTrust),
+				client.WithGovernor(governor, string(config.PortTrust), client.RetryPolicy{
+					MaxRetries: 1,
+					Backoff:    20 * time.Millisecond,
+				}))...)
+	default:
+		return repository.NewMockTrustRepository()
+	}
+}
+
+// provideEngagementSignalRepository 提供实时互动信号（none 时为 nil，不做互动加分）
+func provideEngagementSignalRepository(cfg *config.Config, governor *resilience.Governor, reloader *mtls.Reloader) domainRepository.EngagementSignalRepository {
+	switch cfg.Implementation(config.PortEngagement) {
+	case config.ImplHTTP:
+		// 超时只有 200ms：不重试，失败时这次请求不加分
+		return client.NewEngagementSignalHTTPClient(cfg.HTTPClients[config.PortEngagement],
+			append(mtlsHTTPOptions(cfg, reloader, config.PortEngagement),
+				client.WithGovernor(governor, string(config.PortEngagement), client.RetryPolicy{}))...)
+	default:
+		return nil
+	}
+}
+
+// provideImpressionRepository 提供曝光记录仓储（memory 或 mysql）
+func provideImpressionRepository(cfg *config.Config, db *gorm.DB) domainRepository.ImpressionRepository {
+	switch cfg.Implementation(config.PortImpressions) {
+	case config.ImplMySQL:
+		return persistence.NewImpressionRepository(db)
+	default:
+		return repository.NewMemoryImpressionRepository()
+	}
+}
+
+// provideConversionRepository 提供归因转化记录仓储（跟随曝光记录：memory 或 mysql）
+//
+// 转化记录引用曝光记录，两者总是存放在一起，不单独配置端口。
+func provideConversionRepository(cfg *config.Config, db *gorm.DB) domainRepository.ConversionRepository {
+	switch cfg.Implementation(config.PortImpressions) {
+	case config.ImplMySQL:
+		return persistence.NewConversionRepository(db)
+	default:
+		return repository.NewMemoryConversionRepository()
+	}
+}
+
+// provideFeedbackRepository 提供推荐反馈记录仓储（跟随曝光记录：memory 或 mysql）
+//
+// 反馈引用曝光记录，两者总是存放在一起，不单独配置端口。
+// mysql 实现在开启加密时（codec 不为 nil）加密被推荐用户。
+func provideFeedbackRepository(cfg *config.Config, db *gorm.DB, codec *crypto.FieldCodec) domainRepository.FeedbackRepository {
+	switch cfg.Implementation(config.PortImpressions) {
+	case config.ImplMySQL:
+		return persistence.NewFeedbackRepository(db, codec)
+	default:
+		return repository.NewMemoryFeedbackRepository()
+	}
+}
+
+// provideExperimentAssignmentRepository 提供 A/B 实验分组仓储（跟随曝光记录：memory 或 mysql）
+func provideExperimentAssignmentRepository(cfg *config.Config, db *gorm.DB) domainRepository.ExperimentAssignmentRepository {
+	switch cfg.Implementation(config.PortImpressions) {
+	case config.ImplMySQL:
+		return persistence.NewExperimentAssignmentRepository(db)
+	default:
+		return repository.NewMemoryExperimentAssignmentRepository()
+	}
+}
+
+// provideEventPublisher 提供领域事件的发布者（domain_events.dir 为空时为 nil，不发布）
+//
+// 写入本地目录，由日志采集转发到消息队列；也可以直接发送到消息队列：
+//
+//	capture.NewEventPublisher(capture.NewTopicSink(producer, "recommendation-events"))
+func provideEventPublisher(cfg *config.Config) event.Publisher {
+	if cfg.DomainEvents.Dir == "" {
+		return nil
+	}
+	sink, err := capture.NewFileSink(cfg.DomainEvents.Dir)
+	if err != nil {
+		panic(err)
+	}
+	return capture.NewEventPublisher(sink)
+}
+
+// provideStrategyBanditRepository 提供策略权重 bandit 的统计仓储（跟随曝光记录：memory 或 mysql）
+//
+// 臂统计来自曝光和归因转化，和它们存放在一起，不单独配置端口。
+func provideStrategyBanditRepository(cfg *config.Config, db *gorm.DB) domainRepository.StrategyBanditRepository {
+	switch cfg.Implementation(config.PortImpressions) {
+	case config.ImplMySQL:
+		return persistence.NewStrategyBanditRepository(db)
+	default:
+		return repository.NewMemoryStrategyBanditRepository()
+	}
+}
+
+// provideUserErasureRepository 提供用户数据删除记录仓储（memory 或 mysql）
+func provideUserErasureRepository(cfg *config.Config, db *gorm.DB) domainRepository.UserErasureRepository {
+	switch cfg.Implementation(config.PortUserErasures) {
+	case config.ImplMySQL:
+		return persistence.NewUserErasureRepository(db)
+	default:
+		return repository.NewMemoryUserErasureRepository()
+	}
+}
+
+// provideRecentFollowIndex 提供反向关注索引（none 时为 nil）
+//
+// 索引存在 Redis 中，写扩散时从数据库查询粉丝列表，所以同时需要 Redis 和数据库。
+func provideRecentFollowIndex(cfg *config.Config, rdb redis.UniversalClient, db *gorm.DB) domainRepository.RecentFollowIndexRepository {
+	switch cfg.Implementation(config.PortRecentFollowIndex) {
+	case config.ImplRedis:
+		return persistence.NewRedisRecentFollowIndex(rdb, db, 7*24*time.Hour)
+	default:
+		return nil
+	}
+}
+
+// provideAuditLogRepository 提供审计日志仓储（memory 或 mysql）
+//
+// 数据库账号只授予 INSERT、SELECT。开启加密时（codec 不为 nil）before / after 加密存储。
+func provideAuditLogRepository(cfg *config.Config, db *gorm.DB, codec *crypto.FieldCodec) domainRepository.AuditLogRepository {
+	switch cfg.Implementation(config.PortAuditLog) {
+	case config.ImplMySQL:
+		return persistence.NewAuditLogRepository(db, codec)
+	default:
+		return repository.NewMemoryAuditLogRepository()
+	}
+}
+
+// provideSnapshotStore 提供推荐列表快照存储（增量同步的基准）
+//
+// 保留 24 小时、每个场景最近 5 份；cache 端口为 redis 时多实例共享，否则只在进程内。
+func provideSnapshotStore(cfg *config.Config, rdb redis.UniversalClient, metrics service.Metrics) service.RecommendationSnapshotStore {
+	hot := newHotCache[[]*service.RecommendationSnapshot]("recommendation_snapshots", cfg.HotCache.RecommendationSnapshots, 500000, 10*time.Minute, metrics)
+	return cache.NewRecommendationSnapshotStore(hot, cacheRedis(cfg, rdb), 24*time.Hour, 5, cache.DefaultKeyStrategy())
+}
+
+// provideTriggerRecommendationStore 提供触发式推荐存储
+//
+// cache 端口为 redis 时保存在 Redis（推送管道的请求可能落到任何实例），否则保存在进程内。
+// 用户 72 小时没有新的触发式推荐时整个列表过期（不短于最长的触发有效期 48 小时）。
+func provideTriggerRecommendationStore(cfg *config.Config, rdb redis.UniversalClient) service.TriggerRecommendationStore {
+	return cache.NewTriggerRecommendationStore(cacheRedis(cfg, rdb), 72*time.Hour, cache.DefaultKeyStrategy())
+}
+
+// provideSessionStore 提供会话去重存储
+//
+// cache 端口为 redis 时保存在 Redis（同一个会话的请求可能落到任何实例），否则保存在进程内。
+// 会话 30 分钟没有请求后过期。
+func provideSessionStore(cfg *config.Config, rdb redis.UniversalClient) service.SessionStore {
+	return cache.NewSessionStore(cacheRedis(cfg, rdb), 30*time.Minute, cache.DefaultKeyStrategy())
+}
+
+// provideRecommendationListCache 提供推荐列表缓存（recommendation_cache.ttl_seconds 为 0 时返回 nil，不缓存）
+//
+// 进程内最多缓存 20 万个用户；cache 端口为 redis 时多实例共享。
+func provideRecommendationListCache(cfg *config.Config, rdb redis.UniversalClient) service.RecommendationListCache {
+	if cfg.RecommendationCache.TTLSeconds <= 0 {
+		return nil
+	}
+	ttl := time.Duration(cfg.RecommendationCache.TTLSeconds) * time.Second
+	listCache, err := cache.NewRecommendationListCache(cacheRedis(cfg, rdb), ttl, 200000, cache.DefaultKeyStrategy())
+	if err != nil {
+		panic(err)
+	}
+	return listCache
+}
+
+// cacheRedis 辅助函数：cache 端口为 redis 时热点缓存使用 Redis 作为第二级，否则为 nil
+func cacheRedis(cfg *config.Config, rdb redis.UniversalClient) redis.UniversalClient {
+	if cfg.Implementation(config.PortCache) != config.ImplRedis {
+		return nil
+	}
+	return rdb
+}
+
+// provideCandidateRegistry 提供候选来源、过滤器注册表
+//
+// 内置的 following 来源、trust 过滤器已经注册；
+// 新的来源或过滤器在这里注册，由配置（candidate_pipeline）按名称启用：
+//
+//	registry.RegisterSource("geo", client.NewGeoCandidateSource(cfg.HTTPClients["geo"]))
+func provideCandidateRegistry(
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	contentRepo domainRepository.ContentRepository,
+	interactionRepo domainRepository.InteractionRepository,
+) *domainService.CandidateRegistry {
+	registry := domainService.NewCandidateRegistry()
+	// 重新互动："你可能错过了TA的新帖子"
+	registry.RegisterRecommendationSource(domainService.SourceDormant,
+		domainService.NewDormantConnectionSource(socialGraphRepo, contentRepo, interactionRepo))
+	// 共同好友："你们有 N 位共同好友"
+	registry.RegisterRecommendationSource(domainService.SourceMutual,
+		domainService.NewMutualFollowSource(socialGraphRepo))
+	return registry
+}
+
+// provideGeneratorOptions 提供推荐生成器的可选配置
+//
+// NewRecommendationGenerator 的可变参数（...GeneratorOption）
+// 在 Wire 中被当作 []GeneratorOption 类型的依赖，由这个 Provider 提供。
+//
+// 候选来源和过滤器按配置（candidate_pipeline）从注册表中选择，名称写错时启动失败。
+//
+// 实际项目中还会开启反向关注索引：
+//
+//	index := persistence.NewRedisRecentFollowIndex(rdb, db, 7*24*time.Hour)
+//	domainService.WithRecentFollowIndex(index)
+func provideGeneratorOptions(
+	cfg *config.Config,
+	trustRepo domainRepository.TrustRepository,
+	engagementRepo domainRepository.EngagementSignalRepository,
+	interactionRepo domainRepository.InteractionRepository,
+	blockRepo domainRepository.BlockRepository,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	popularRepo domainRepository.PopularUsersRepository,
+	registry *domainService.CandidateRegistry,
+	tenants *service.Tenants,
+) []domainService.GeneratorOption {
+	opts := []domainService.GeneratorOption{
+		// 打分权重按租户配置
+		domainService.WithTenantScoringWeights(tenants.ScoringWeights()),
+		// 排除风险等级高于 medium 的账号（high、banned）
+		domainService.WithTrustFilter(trustRepo, valueobject.RiskMedium),
+		// 排除有屏蔽关系的人（任一方向；查询失败时不生成推荐）
+		domainService.WithBlockRepository(blockRepo),
+		// 关注超过 5000 人时，只使用最近关注的 1000 人 + 随机 1000 人
+		domainService.WithFollowingSampling(domainService.DefaultFollowingSamplingPolicy()),
+		// 关注超过 10000 人时分页遍历关注列表（每页 1000 人），不一次加载到内存
+		domainService.WithFollowingScan(10000, 1000),
+	}
+	// 扇出查询的并发度和单次查询的超时（fanout）
+	if concurrency := cfg.Fanout.Concurrency; concurrency > 0 {
+		opts = append(opts, domainService.WithConcurrency(concurrency))
+	}
+	if timeoutMS := cfg.Fanout.CallTimeoutMS; timeoutMS > 0 {
+		opts = append(opts, domainService.WithCallTimeout(time.Duration(timeoutMS)*time.Millisecond))
+	}
+	// 最近一小时正在产生互动的候选加分（engagement 端口为 none 时不加分）
+	if engagementRepo != nil {
+		opts = append(opts, domainService.WithEngagementSignals(engagementRepo))
+	}
+	// 最近 N 天收到的点赞、评论多的创作者加分（权重为 0 的租户不加分）
+	opts = append(opts, domainService.WithCreatorEngagement(interactionRepo))
+	// 没有任何推荐时（还没有关注任何人的新用户）推荐编辑精选、全站热门（popular_users 端口为 none 时不推荐）
+	if popularRepo != nil {
+		opts = append(opts, domainService.WithColdStart(domainService.NewColdStartSource(popularRepo, socialGraphRepo)))
+	}
+	if pipeline := cfg.CandidatePipeline; len(pipeline.Sources) > 0 {
+		opt, err := registry.Pipeline(pipeline.Sources, pipeline.Filters)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+// provideRecommendationServiceOptions 提供应用服务的可选配置
+//
+// 与 provideGeneratorOptions 相同，对应 NewRecommendationService 的可变参数。
+func provideRecommendationServiceOptions(
+	cfg *config.Config,
+	impressionRepo domainRepository.ImpressionRepository,
+	erasureRepo domainRepository.UserErasureRepository,
+	listRepo domainRepository.RecommendationRepository,
+	popularRepo domainRepository.PopularUsersRepository,
+	activeUsers domainRepository.ActiveUsersRepository,
+	feedbackRepo domainRepository.FeedbackRepository,
+	eventPublisher event.Publisher,
+	experiments *service.ExperimentAssigner,
+	logger service.Logger,
+	tracer service.Tracer,
+	tenants *service.Tenants,
+	snapshotStore service.RecommendationSnapshotStore,
+	listCache service.RecommendationListCache,
+	surfaces *service.SurfacePolicies,
+	reasonConfigClient service.ReasonTextConfigClient,
+	bandit *service.StrategyBandit,
+	sessions service.SessionStore,
+	metrics service.Metrics,
+	contentClient service.ContentServiceClient,
+	contentRepo domainRepository.ContentRepository,
+	governor *resilience.Governor,
+	transactions service.TransactionManager,
+) []service.RecommendationServiceOption {
+	profileMiss, err := service.ParseProfileMissPolicy(cfg.Hydration.ProfileMiss)
+	if err != nil {
+		panic(err)
+	}
+	degradation, err := degradationPolicy(cfg, logger, metrics)
+	if err != nil {
+		panic(err)
+	}
+	sources, err := contentSources(cfg, degradation, contentClient, contentRepo, governor)
+	if err != nil {
+		panic(err)
+	}
+	opts := []service.RecommendationServiceOption{
+		service.WithLogger(logger),
+		service.WithTracer(tracer),
+		// 监控指标：生成的推荐数、缓存命中、降级次数等（GET /metrics）
+		service.WithMetrics(metrics),
+		// 推荐理由文案使用租户配置的语言
+		service.WithTenants(tenants),
+		// 记录曝光，并限制每个用户每天最多看到 50 个不同的推荐对象
+		service.WithImpressionRepository(impressionRepo),
+		service.WithExposureQuota(service.NewExposureQuota(50, nil)),
+		// 已删除数据的用户不再生成推荐
+		service.WithUserErasureRepository(erasureRepo),
+		// 增量同步：保存每次完整返回的列表快照
+		service.WithSnapshotStore(snapshotStore),
+		// 快照在 priority.max_staleness_seconds 内直接使用（高优先级用户除外）
+		service.WithPrecomputedServing(time.Duration(cfg.Priority.MaxStalenessSeconds) * time.Second),
+		// 各展示场景的数量上限、有效期、多样性和推荐策略（config.yaml 的 surfaces）
+		service.WithSurfacePolicies(surfaces),
+		// 请求带会话ID时，同一个会话内不重复返回
+		service.WithSessionStore(sessions),
+		// 推荐对象的资料缺失时跳过、补位或返回占位资料（hydration.profile_miss）
+		service.WithProfileMissPolicy(profileMiss),
+		// 帖子预览、推荐理由文案、召回排序失败时的降级顺序和超时（degradation）
+		service.WithDegradationPolicy(degradation),
+		// 帖子预览的来源链：content 服务 → 本地数据库（degradation.content 的顺序、超时、skip_unhealthy）
+		service.WithContentSources(sources),
+		// 多个仓储一起写入的用例（如反馈）在一个数据库事务中执行（没有连接数据库时为 nil，不开启事务）
+		service.WithTransactionManager(transactions),
+	}
+	// 保存生成的推荐列表，priority.list_max_age_seconds 内直接使用（高优先级用户除外）
+	if listRepo != nil {
+		opts = append(opts, service.WithRecommendationRepository(listRepo, time.Duration(cfg.Priority.ListMaxAgeSeconds)*time.Second))
+	}
+	// 生成推荐之前先查缓存（recommendation_cache.ttl_seconds）
+	if listCache != nil {
+		opts = append(opts, service.WithRecommendationListCache(listCache))
+	}
+	// 个性化推荐不够一页时用编辑精选、全站热门补齐（popular_users 端口为 none 时不补位）
+	if popularRepo != nil {
+		opts = append(opts, service.WithBackfill(popularRepo))
+	}
+	// 记录请求推荐的用户，预计算只为最近活跃的用户生成（active_users 端口为 none 时不记录）
+	if activeUsers != nil {
+		opts = append(opts, service.WithActiveUsersRepository(activeUsers))
+	}
+	// 客户端上报的推荐反馈（点开、关注）写入反馈记录，并发布领域事件（domain_events.dir 为空时不发布）
+	opts = append(opts, service.WithFeedback(feedbackRepo, eventPublisher))
+	// A/B 实验：按分组选择推荐策略、打分权重、理由文案（experiments 为空时不做实验）
+	if experiments != nil {
+		opts = append(opts, service.WithExperiments(experiments))
+	}
+	// 发现页的分栏由推荐理由配置服务提供（没有配置服务时使用默认分栏）
+	if layouts, ok := reasonConfigClient.(service.SectionLayoutClient); ok {
+		opts = append(opts, service.WithSectionLayoutClient(layouts))
+	}
+	// 特征快照日志（feature_log.sample_rate 为 0 时不记录）
+	// 写入本地目录，由日志采集转发到分析平台；也可以直接发送到消息队列：
+	//
+	//	capture.NewTopicSink(producer, "recommendation-feature-snapshots")
+	if cfg.FeatureLog.SampleRate > 0 {
+		sink, err := capture.NewFileSink(cfg.FeatureLog.Dir)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, service.WithFeatureLogger(service.NewFeatureLogger(sink, cfg.FeatureLog.SampleRate, 1000)))
+	}
+	// 分数校准（score_calibration.file 为空时不校准）
+	// 启动时文件不存在或参数不合法直接失败；运行中加载失败时保留旧参数
+	if cfg.ScoreCalibration.File != "" {
+		source, err := calibration.NewFileSource(
+			cfg.ScoreCalibration.File,
+			time.Duration(cfg.ScoreCalibration.ReloadSeconds)*time.Second,
+		)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, service.WithScoreCalibration(source))
+	}
+	// 策略权重 bandit（strategy_bandit.enabled 为 false 时 bandit 为 nil，不调整）
+	if bandit != nil {
+		opts = append(opts, service.WithStrategyBandit(bandit))
+	}
+	// 混合推荐的策略权重（hybrid.weights，策略名写错时启动失败）
+	hybridWeights, err := service.ParseHybridWeights(cfg.Hybrid.Weights)
+	if err != nil {
+		panic(err)
+	}
+	// 融合方式（hybrid.fusion，写错时启动失败）
+	hybridFusion, err := service.ParseHybridFusion(cfg.Hybrid.Fusion, hybridWeights, cfg.Hybrid.RRFK)
+	if err != nil {
+		panic(err)
+	}
+	opts = append(opts, service.WithHybridWeights(hybridWeights), service.WithHybridFusion(hybridFusion))
+	return opts
+}
+
+// contentSources 辅助函数：帖子预览的来源链（顺序、超时来自 degradation.content）
+//
+// degradation.content.skip_unhealthy 为 true 时，content 服务的健康信号来自下游调用治理器：
+// 熔断器打开或者成功率过低时直接查本地数据库，不再等远程调用超时。
+func contentSources(
+	cfg *config.Config,
+	degradation *service.DegradationPolicy,
+	contentClient service.ContentServiceClient,
+	contentRepo domainRepository.ContentRepository,
+	governor *resilience.Governor,
+) (*service.ContentSourceChain, error) {
+	policy := degradation.For(service.FallbackContent)
+	var sources []service.ContentSourceConfig
+	for _, name := range policy.Order {
+		source := service.ContentSourceConfig{Name: name, Timeout: policy.TimeoutFor(name)}
+		switch {
+		case name == service.SourceRemote && contentClient != nil:
+			source.Source = contentClient
+			if cfg.Degradation[string(service.FallbackContent)].SkipUnhealthy {
+				source.Healthy = func() bool { return governor.Healthy(string(config.PortContentService)) }
+			}
+		case name == service.SourceLocal && contentRepo != nil:
+			source.Source = service.NewLocalContentSource(contentRepo)
+		default:
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return service.NewContentSourceChain(sources...)
+}
+
+// degradationPolicy 辅助函数：config.yaml 的 degradation → 降级策略
+func degradationPolicy(cfg *config.Config, logger service.Logger, metrics service.Metrics) (*service.DegradationPolicy, error) {
+	policies := make(map[service.FallbackDependency]service.DependencyPolicy, len(cfg.Degradation))
+	for name, dc := range cfg.Degradation {
+		dependency, err := service.ParseFallbackDependency(name)
+		if err != nil {
+			return nil, fmt.Errorf("degradation: %w", err)
+		}
+		policy := service.DependencyPolicy{
+			Timeout:    time.Duration(dc.TimeoutMS) * time.Millisecond,
+			ServeStale: dc.ServeStale,
+		}
+		for _, source := range dc.Order {
+			policy.Order = append(policy.Order, service.FallbackSource(source))
+		}
+		for source, ms := range dc.SourceTimeoutsMS {
+			if policy.SourceTimeouts == nil {
+				policy.SourceTimeouts = make(map[service.FallbackSource]time.Duration, len(dc.SourceTimeoutsMS))
+			}
+			policy.SourceTimeouts[service.FallbackSource(source)] = time.Duration(ms) * time.Millisecond
+		}
+		if dc.SkipUnhealthy && dependency != service.FallbackContent {
+			return nil, fmt.Errorf("degradation %s: skip_unhealthy is not supported", name)
+		}
+		policies[dependency] = policy
+	}
+	return service.NewDegradationPolicy(policies, logger, metrics)
+}
+
+// provideSurfacePolicies 提供各展示场景的推荐行为（config.yaml 的 surfaces，配置错误时启动失败）
+//
+// 场景单独配置了 candidate_pipeline 时，为它构造一个独立的生成器：
+// 其余生成器选项（打分权重、风控、采样）和默认生成器相同，只替换候选来源和过滤器。
+// 场景配置了 interleaving 时，两个排序器按各自的打分权重排序（权重都为 0 时使用默认权重）。
+func provideSurfacePolicies(
+	cfg *config.Config,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	contentRepo domainRepository.ContentRepository,
+	registry *domainService.CandidateRegistry,
+	generatorOpts []domainService.GeneratorOption,
+) *service.SurfacePolicies {
+	policies := make(map[dto.Surface]service.SurfacePolicy, len(cfg.Surfaces))
+	for name, surfaceCfg := range cfg.Surfaces {
+		surface, err := service.ParseSurface(name)
+		if err != nil {
+			panic(err)
+		}
+		if surfaceCfg.RepeatWindowDays < 0 {
+			panic(fmt.Errorf("surface %s: invalid repeat_window_days %d", name, surfaceCfg.RepeatWindowDays))
+		}
+		if days := surfaceCfg.RecencyDays; days != 0 && (days < service.MinRecencyDays || days > service.MaxRecencyDays) {
+			panic(fmt.Errorf("surface %s: recency_days %d out of [%d, %d]", name, days, service.MinRecencyDays, service.MaxRecencyDays))
+		}
+		if surfaceCfg.PreviewPosts < 0 || surfaceCfg.PreviewLength < 0 {
+			panic(fmt.Errorf("surface %s: invalid preview_posts %d / preview_length %d",
+				name, surfaceCfg.PreviewPosts, surfaceCfg.PreviewLength))
+		}
+		policy := service.SurfacePolicy{
+			MaxLimit:         surfaceCfg.MaxLimit,
+			Expiry:           time.Duration(surfaceCfg.ExpiryHours) * time.Hour,
+			MaxPerReasonType: surfaceCfg.MaxPerReasonType,
+			MaxPerReferrer:   surfaceCfg.MaxPerReferrer,
+			RepeatWindow:     time.Duration(surfaceCfg.RepeatWindowDays) * 24 * time.Hour,
+			PreviewPosts:     surfaceCfg.PreviewPosts,
+			PreviewLength:    surfaceCfg.PreviewLength,
+			RecencyDays:      surfaceCfg.RecencyDays,
+		}
+		if pipeline := surfaceCfg.CandidatePipeline; len(pipeline.Sources) > 0 {
+			opt, err := registry.Pipeline(pipeline.Sources, pipeline.Filters)
+			if err != nil {
+				panic(fmt.Errorf("surface %s: %w", name, err))
+			}
+			opts := append(slices.Clone(generatorOpts), opt)
+			policy.Generator = domainService.NewRecommendationGenerator(socialGraphRepo, contentRepo, opts...)
+		}
+		if interleaving := surfaceCfg.Interleaving; interleaving.Experiment != "" {
+			if len(interleaving.Experiment) > 32 || interleaving.TrafficPercent < 0 || interleaving.TrafficPercent > 100 {
+				panic(fmt.Errorf("surface %s: invalid interleaving experiment %q (traffic %d%%)",
+					name, interleaving.Experiment, interleaving.TrafficPercent))
+			}
+			policy.Interleaving = &service.InterleavingExperiment{
+				Name:           interleaving.Experiment,
+				TrafficPercent: interleaving.TrafficPercent,
+				RankerA:        domainService.NewWeightsRanker(scoringWeights(interleaving.RankerA)),
+				RankerB:        domainService.NewWeightsRanker(scoringWeights(interleaving.RankerB)),
+			}
+		}
+		policies[surface] = policy
+	}
+	return service.NewSurfacePolicies(policies)
+}
+
+// provideExperimentAssigner 提供 A/B 实验的分组（config.yaml 的 experiments，没有实验时为 nil，配置错误时启动失败）
+//
+// 分组配置了 candidate_pipeline 时，和展示场景一样为它构造一个独立的生成器；
+// 配置了 scoring 时按分组的打分权重排序（都为 0 时使用场景的排序）。
+func provideExperimentAssigner(
+	cfg *config.Config,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	contentRepo domainRepository.ContentRepository,
+	registry *domainService.CandidateRegistry,
+	generatorOpts []domainService.GeneratorOption,
+	assignmentRepo domainRepository.ExperimentAssignmentRepository,
+) *service.ExperimentAssigner {
+	if len(cfg.Experiments) == 0 {
+		return nil
+	}
+	experiments := make([]*service.Experiment, 0, len(cfg.Experiments))
+	for _, experimentCfg := range cfg.Experiments {
+		experiment := &service.Experiment{Name: experimentCfg.Name, Sticky: experimentCfg.Sticky}
+		for _, name := range experimentCfg.Surfaces {
+			surface, err := service.ParseSurface(name)
+			if err != nil {
+				panic(fmt.Errorf("experiment %s: %w", experimentCfg.Name, err))
+			}
+			experiment.Surfaces = append(experiment.Surfaces, surface)
+		}
+		for _, variantCfg := range experimentCfg.Variants {
+			variant := service.ExperimentVariant{
+				Name:              variantCfg.Name,
+				Percent:           variantCfg.Percent,
+				ReasonTextVariant: variantCfg.ReasonTextVariant,
+			}
+			if pipeline := variantCfg.CandidatePipeline; len(pipeline.Sources) > 0 {
+				opt, err := registry.Pipeline(pipeline.Sources, pipeline.Filters)
+				if err != nil {
+					panic(fmt.Errorf("experiment %s/%s: %w", experimentCfg.Name, variantCfg.Name, err))
+				}
+				opts := append(slices.Clone(generatorOpts), opt)
+				variant.Generator = domainService.NewRecommendationGenerator(socialGraphRepo, contentRepo, opts...)
+			}
+			if variantCfg.Scoring != (config.TenantScoringConfig{}) {
+				variant.Ranker = domainService.NewWeightsRanker(scoringWeights(variantCfg.Scoring))
+			}
+			experiment.Variants = append(experiment.Variants, variant)
+		}
+		experiments = append(experiments, experiment)
+	}
+	assigner, err := service.NewExperimentAssigner(experiments, assignmentRepo)
+	if err != nil {
+		panic(err)
+	}
+	return assigner
+}
+
+// scoringWeights 辅助函数：配置 → 打分权重（都为 0 时使用默认权重）
+func scoringWeights(cfg config.TenantScoringConfig) valueobject.ScoringWeights {
+	weights := valueobject.ScoringWeights{
+		PerRelatedUser:       cfg.PerRelatedUser,
+		PerRecentPost:        cfg.PerRecentPost,
+		PerHundredEngagement: cfg.PerHundredEngagement,
+	}
+	if weights == (valueobject.ScoringWeights{}) {
+		return valueobject.DefaultScoringWeights()
+	}
+	return weights
+}
+
+// providePriorityTiers 提供用户优先级（config.yaml 的 priority，配置错误时启动失败）
+//
+// 实际项目中还会接入会员系统的信号：
+//
+//	service.NewPriorityTiers(users, membership.NewTierSource(...), defaultTier)
+func providePriorityTiers(cfg *config.Config, tenants *service.Tenants) *service.PriorityTiers {
+	defaultTier := service.PriorityStandard
+	if raw := cfg.Priority.DefaultTier; raw != "" {
+		tier, err := service.ParsePriorityTier(raw)
+		if err != nil {
+			panic(err)
+		}
+		defaultTier = tier
+	}
+	users := make(map[valueobject.TenantID]map[int64]service.PriorityTier, len(cfg.Priority.Users))
+	for rawTenant, byTier := range cfg.Priority.Users {
+		tenant, err := tenants.Resolve(rawTenant)
+		if err != nil {
+			panic(err)
+		}
+		users[tenant] = make(map[int64]service.PriorityTier)
+		for rawTier, userIDs := range byTier {
+			tier, err := service.ParsePriorityTier(rawTier)
+			if err != nil {
+				panic(err)
+			}
+			for _, userID := range userIDs {
+				users[tenant][userID] = tier
+			}
+		}
+	}
+	return service.NewPriorityTiers(users, nil, defaultTier)
+}
+
+// provideUserCacheInvalidators 提供删除用户数据时需要清理的缓存
+//
+// 带缓存的用户服务客户端、社交图谱仓储、推荐列表快照、推荐列表缓存、触发式推荐存储都实现了 UserCacheInvalidator，
+// 没有开启缓存时（直接使用原始实现，或者为 nil）自动跳过。
+func provideUserCacheInvalidators(
+	userRPCClient service.UserRPCClient,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	snapshotStore service.RecommendationSnapshotStore,
+	listCache service.RecommendationListCache,
+	triggerStore service.TriggerRecommendationStore,
+) []service.UserCacheInvalidator {
+	var invalidators []service.UserCacheInvalidator
+	for _, dependency := range []any{userRPCClient, socialGraphRepo, snapshotStore, listCache, triggerStore} {
+		if invalidator, ok := dependency.(service.UserCacheInvalidator); ok {
+			invalidators = append(invalidators, invalidator)
+		}
+	}
+	return invalidators
+}
+
+// provideAuthorizer 提供授权检查（白名单来自 authorization）
+//
+// 终端用户只能查询自己的推荐；
+// 推送管道、BFF、管理后台可以代表任意用户查询；
+// 用户数据删除只允许隐私团队的工具调用；
+// 推荐解释只允许质量评审工具调用；
+// 管理接口只允许运营后台调用。
+func provideAuthorizer(cfg *config.Config) *service.Authorizer {
+	return service.NewAuthorizer(service.AuthorizationPolicy{
+		OnBehalfServices:      cfg.Authorization.OnBehalfServices,
+		PrivacyServices:       cfg.Authorization.PrivacyServices,
+		QualityReviewServices: cfg.Authorization.QualityReviewServices,
+		AdminServices:         cfg.Authorization.AdminServices,
+		FollowWriteServices:   cfg.Authorization.FollowWriteServices,
+	})
+}
+
+// provideHandlerOptions 提供 Handler 的可选配置
+//
+// 负载保护、异常请求检测：见 provideLoadShedder、provideAbuseDetector（没有开启时为 nil）。
+//
+// 用户优先级：过载时非高优先级请求改为只使用快照。
+//
+// 流量抓取：见 provideTrafficCapture（没有开启时为 nil）。
+//
+// 帖子时间：post_timestamps.omit_legacy 为 true 时不再填充旧格式的 created_at。
+//
+// 关注、取关：见 provideFollowService。
+func provideHandlerOptions(
+	cfg *config.Config,
+	tiers *service.PriorityTiers,
+	capture *handler.TrafficCapture,
+	followService *service.FollowService,
+	abuseDetector *handler.AbuseDetector,
+	loadShedder *handler.LoadShedder,
+) []handler.HandlerOption {
+	opts := []handler.HandlerOption{
+		handler.WithPriorityTiers(tiers),
+		handler.WithTrafficCapture(capture),
+		handler.WithLegacyPostTimestamps(!cfg.PostTimestamps.OmitLegacy),
+		handler.WithFollowService(followService),
+	}
+	if abuseDetector != nil {
+		opts = append(opts, handler.WithAbuseDetector(abuseDetector))
+	}
+	if loadShedder != nil {
+		opts = append(opts, handler.WithLoadShedder(loadShedder))
+	}
+	return opts
+}
+
+// provideAbuseDetector 提供异常请求检测（abuse_detection.enabled 为 false 时为 nil，不检测）
+//
+// 阈值来自 config.yaml：每分钟单个调用方超过 6000 次请求或查询超过 3000 个不同用户、
+// 单个用户被查询超过 600 次、单次请求超过 100 条时告警；
+// 调用方异常时封禁 5 分钟（返回 ErrRateLimited，错误码 ErrCodeRateLimited；HTTP 接口返回 429）。
+//
+// Kitex Handler 和 HTTP 接口共用同一个实例：同一个调用方在两个接口上的请求一起计数。
+func provideAbuseDetector(cfg *config.Config, logger service.Logger, metrics service.Metrics) *handler.AbuseDetector {
+	abuse := cfg.AbuseDetection
+	if !abuse.Enabled {
+		return nil
+	}
+	window := abuse.Window
+	if window <= 0 {
+		window = time.Minute
+	}
+	return handler.NewAbuseDetector(handler.AbuseDetectorConfig{
+		Window:             window,
+		MaxCallerRequests:  abuse.MaxCallerRequests,
+		MaxDistinctTargets: abuse.MaxDistinctTargets,
+		MaxTargetRequests:  abuse.MaxTargetRequests,
+		MaxBatchSize:       abuse.MaxBatchSize,
+		BlockDuration:      abuse.BlockDuration,
+	}, metrics, logger)
+}
+
+// provideLoadShedder 提供负载保护（load_shedding.enabled 为 false 时为 nil，不限制）
+//
+// 默认参数：最多 100 个请求同时生成推荐，超出的最多 200 个排队 50ms，
+// 再多的请求直接返回 ErrOverloaded（错误码 ErrCodeOverloaded；HTTP 接口返回 503），
+// 保护数据库和下游服务，不让所有请求一起超时。
+// 低优先级请求最多使用 80 个名额、不排队。
+//
+// Kitex Handler 和 HTTP 接口共用同一个实例（同一组在途名额）：两个接口加起来不超过处理能力。
+func provideLoadShedder(cfg *config.Config, metrics service.Metrics) *handler.LoadShedder {
+	shedding := cfg.LoadShedding
+	if !shedding.Enabled {
+		return nil
+	}
+	maxInFlight := cmp.Or(shedding.MaxInFlight, 100)
+	return handler.NewLoadShedder(
+		maxInFlight,
+		cmp.Or(shedding.MaxQueue, 200),
+		cmp.Or(shedding.QueueTimeout, 50*time.Millisecond),
+		cmp.Or(shedding.MinExecution, 100*time.Millisecond),
+		metrics,
+		handler.WithLowPriorityLimit(cmp.Or(shedding.LowPriorityLimit, maxInFlight*4/5)),
+	)
+}
+
+// provideTrafficCapture 提供流量抓取（traffic_capture.sample_rate 为 0 时不抓取，返回 nil）
+//
+// 写入本地目录；多实例部署时可以改为发送到消息队列：
+//
+//	capture.NewTopicSink(producer, "recommendation-traffic-capture")
+func provideTrafficCapture(cfg *config.Config) *handler.TrafficCapture {
+	if cfg.TrafficCapture.SampleRate <= 0 {
+		return nil
+	}
+	sink, err := capture.NewFileSink(cfg.TrafficCapture.Dir)
+	if err != nil {
+		panic(err)
+	}
+	return handler.NewTrafficCapture(sink, cfg.TrafficCapture.SampleRate, 1000)
+}
+
+// dependencyTypes 端口对应的接口类型（诊断接口中的 type）
+var dependencyTypes = map[config.Port]string{
+	config.PortUserRPC:           "UserRPCClient",
+	config.PortContentService:    "ContentServiceClient",
+	config.PortReasonConfig:      "ReasonTextConfigClient",
+	config.PortTrust:             "TrustRepository",
+	config.PortSocialGraph:       "SocialGraphRepository",
+	config.PortContentRepository: "ContentRepository",
+	config.PortImpressions:       "ImpressionRepository",
+	config.PortUserErasures:      "UserErasureRepository",
+	config.PortAuditLog:          "AuditLogRepository",
+	config.PortRecentFollowIndex: "RecentFollowIndexRepository",
+	config.PortCache:             "HotCache",
+	config.PortTopics:            "TopicRepository",
+	config.PortGroups:            "GroupMembershipRepository",
+	config.PortInteractions:      "InteractionRepository",
+	config.PortEngagement:        "EngagementSignalRepository",
+	config.PortBlocks:            "BlockRepository",
+	config.PortRecommendations:   "RecommendationRepository",
+}
+
+// provideDependencyStatusService 提供依赖状态报告（管理接口 GetDependencyStatus）
+//
+// 按生效的装配（WiringReport）登记每个端口：
+// - mysql：Ping 数据库
+// - redis：PING
+// - http：检查下游地址是否可达
+// - mock、memory、none：不检查
+//
+// 健康检查最多每 10 秒执行一次，每个检查 2 秒超时。
+// 目前下游调用都没有熔断器，熔断器状态报告为 none。
+func provideDependencyStatusService(cfg *config.Config, db *gorm.DB, rdb redis.UniversalClient) *service.DependencyStatusService {
+	var dependencies []service.Dependency
+	for _, entry := range cfg.WiringReport() {
+		dependency := service.Dependency{
+			Name:           string(entry.Port),
+			Type:           dependencyTypes[entry.Port],
+			Implementation: string(entry.Implementation),
+			Target:         entry.Target,
+		}
+		switch entry.Implementation {
+		case config.ImplMySQL:
+			dependency.Check = health.Database(db)
+		case config.ImplRedis:
+			dependency.Check = health.Redis(rdb)
+		case config.ImplHTTP:
+			dependency.Check = health.Reachable(entry.Target)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return service.NewDependencyStatusService(dependencies, 10*time.Second, 2*time.Second)
+}
+
+// conversionWindow 归因窗口：曝光后一天内的关注算作这次曝光的转化
+//
+// 归因（AttributionService）和统计（FeedbackAnalyticsService）必须使用同一个窗口。
+const conversionWindow = 24 * time.Hour
+
+// provideFeedbackAnalyticsService 提供推荐效果统计（管理接口 GetFeedbackStats、GetStrategyStats）
+//
+// 统计在曝光记录上聚合：曝光记录为 mysql 时查询数据库，内存实现时统计为空。
+func provideFeedbackAnalyticsService(cfg *config.Config, db *gorm.DB) *service.FeedbackAnalyticsService {
+	var repo domainRepository.RecommendationStatsRepository
+	if cfg.Implementation(config.PortImpressions) == config.ImplMySQL {
+		repo = persistence.NewRecommendationStatsRepository(db)
+	}
+	return service.NewFeedbackAnalyticsService(repo, conversionWindow)
+}
+
+// provideAttributionService 提供曝光 → 关注的归因（社交服务关注事件的消费者使用）
+func provideAttributionService(
+	impressionRepo domainRepository.ImpressionRepository,
+	conversionRepo domainRepository.ConversionRepository,
+	bandit *service.StrategyBandit,
+) *service.AttributionService {
+	return service.NewAttributionService(impressionRepo, conversionRepo, bandit, conversionWindow)
+}
+
+// provideFollowService 提供关注关系的写侧（RPC FollowUser、UnfollowUser，社交服务关注事件的消费者）
+//
+// 关注、取关之后立即删除关注者的关注列表缓存、推荐列表缓存；推荐卡片上的关注立即归因。
+func provideFollowService(
+	socialGraphRepo domainRepository.SocialGraphRepository,
+	listCache service.RecommendationListCache,
+	attribution *service.AttributionService,
+	transactions service.TransactionManager,
+) *service.FollowService {
+	return service.NewFollowService(socialGraphRepo,
+		service.WithFollowGraphCache(socialGraphCacheInvalidator(socialGraphRepo)),
+		service.WithFollowListCache(listCache),
+		service.WithFollowAttribution(attribution),
+		service.WithFollowTransactions(transactions),
+	)
+}
+
+// provideFollowEventHandler 提供社交服务关注事件的处理（维护关注关系、清理关注列表缓存和推荐列表、归因）
+func provideFollowEventHandler(
+	attribution *service.AttributionService,
+	followService *service.FollowService,
+	recommendationService *service.RecommendationService,
+	socialGraphRepo domainRepository.SocialGraphRepository,
+) *handler.FollowEventHandler {
+	return handler.NewFollowEventHandler(attribution, followService,
+		handler.WithFollowGraphInvalidation(socialGraphCacheInvalidator(socialGraphRepo)),
+		handler.WithFollowInvalidation(recommendationService),
+	)
+}
+
+// provideFollowEventConsumer 提供关注事件的消费者（没有配置要消费的 topic / Stream 时为 nil，配置错误时启动失败）
+//
+// follow_events.backend 选择消息队列：kafka 消费社交服务的 topic（生产环境），redis 读取 Redis Stream（开发、测试）；
+// 死信发送到同一种消息队列。Kafka 的 Reader、Writer 登记到 closers，进程退出时离开消费者组。
+//
+// 格式错误、用户ID不合法等事件（ErrRejectedFollowEvent）重试也不会成功，直接转入死信队列；
+// 其他错误（数据库暂时不可用等）按 max_attempts 重试。
+// 必须和 Handler 在同一个依赖图中：删除的是 Handler 使用的那份推荐列表缓存。
+func provideFollowEventConsumer(
+	cfg *config.Config,
+	rdb redis.UniversalClient,
+	followEvents *handler.FollowEventHandler,
+	logger service.Logger,
+	metrics service.Metrics,
+	closers *lifecycle.Closers,
+) *messaging.Consumer {
+	eventsCfg := cfg.FollowEvents
+	group := cmp.Or(eventsCfg.Group, "recommendation-service")
+
+	var reader messaging.GroupReader
+	var deadLetters messaging.Publisher
+	switch eventsCfg.Backend {
+	case "kafka":
+		if eventsCfg.Topic == "" {
+			return nil
+		}
+		if len(eventsCfg.Brokers) == 0 {
+			panic("follow_events: backend kafka requires brokers")
+		}
+		kafkaReader := messaging.NewKafkaGroupReader(eventsCfg.Brokers, eventsCfg.Topic, group)
+		closers.Add("follow events kafka reader", func(context.Context) error { return kafkaReader.Close() })
+		reader = kafkaReader
+		if eventsCfg.DeadLetter != "" {
+			publisher := messaging.NewKafkaPublisher(eventsCfg.Brokers)
+			closers.Add("follow events kafka dead letters", func(context.Context) error { return publisher.Close() })
+			deadLetters = publisher
+		}
+	case "", "redis":
+		if eventsCfg.Stream == "" {
+			return nil
+		}
+		consumer := eventsCfg.Consumer
+		if consumer == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				panic(fmt.Sprintf("follow_events: consumer is empty and hostname is unavailable: %v", err))
+			}
+			consumer = hostname
+		}
+		reader = messaging.NewRedisStreamReader(rdb, eventsCfg.Stream, group, consumer,
+			time.Duration(cmp.Or(eventsCfg.ClaimIdleSeconds, 300))*time.Second)
+		if eventsCfg.DeadLetter != "" {
+			deadLetters = messaging.NewRedisStreamPublisher(rdb, 100000)
+		}
+	default:
+		panic(fmt.Sprintf("follow_events: unknown backend %q (want kafka or redis)", eventsCfg.Backend))
+	}
+
+	opts := []messaging.ConsumerOption{
+		messaging.WithRetryable(func(err error) bool {
+			return !errors.Is(err, handler.ErrRejectedFollowEvent)
+		}),
+		messaging.WithConsumerObservability(logger, metrics),
+	}
+	if deadLetters != nil {
+		opts = append(opts, messaging.WithDeadLetter(deadLetters, eventsCfg.DeadLetter))
+	}
+	return messaging.NewConsumer("follow_events", reader, followEvents.HandleMessage, messaging.ConsumerConfig{
+		MaxAttempts: eventsCfg.MaxAttempts,
+		Backoff:     time.Duration(eventsCfg.BackoffMillis) * time.Millisecond,
+	}, opts...)
+}
+
+// providePrecomputeJob 提供推荐列表预计算任务（precompute.enabled 为 false 时为 nil，配置错误时启动失败）
+//
+// 必须和 Handler 在同一个依赖图中：预计算的列表同时写入 Handler 使用的推荐列表缓存。
+func providePrecomputeJob(
+	cfg *config.Config,
+	recommendationService *service.RecommendationService,
+	activeUsers domainRepository.ActiveUsersRepository,
+	tenants *service.Tenants,
+	logger service.Logger,
+) *job.PrecomputeJob {
+	precomputeCfg := cfg.Precompute
+	if !precomputeCfg.Enabled {
+		return nil
+	}
+	if activeUsers == nil {
+		panic("precompute: enabled but the active_users port is none")
+	}
+	interval := time.Duration(cmp.Or(precomputeCfg.IntervalSeconds, 300)) * time.Second
+	listMaxAge := time.Duration(cfg.Priority.ListMaxAgeSeconds) * time.Second
+	if interval >= listMaxAge {
+		panic(fmt.Sprintf("precompute: interval_seconds (%v) must be shorter than priority.list_max_age_seconds (%v), or precomputed lists expire before they are refreshed", interval, listMaxAge))
+	}
+	precomputer, err := service.NewListPrecomputer(recommendationService, activeUsers, tenants, service.PrecomputeConfig{
+		ActiveWindow: precomputeActiveWindow(cfg),
+		MaxUsers:     precomputeCfg.MaxUsers,
+		// 下一轮之前就会超过 listMaxAge 的列表这一轮重新生成
+		RefreshAfter: listMaxAge - interval,
+		Concurrency:  precomputeCfg.Concurrency,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("precompute: %v", err))
+	}
+	return job.NewPrecomputeJob(precomputer, interval, logger)
+}
+
+// precomputeActiveWindow 辅助函数：多久以内请求过推荐的用户算作活跃（precompute.active_window_seconds，默认 24 小时）
+func precomputeActiveWindow(cfg *config.Config) time.Duration {
+	return time.Duration(cmp.Or(cfg.Precompute.ActiveWindowSeconds, 86400)) * time.Second
+}
+
+// provideStrategyBandit 提供策略权重 bandit（strategy_bandit.enabled 为 false 时为 nil，配置错误时启动失败）
+//
+// 排序（RecommendationService）、归因（AttributionService）和管理接口使用同一个对象：
+// 曝光和转化在进程内累加，定期写入仓储。
+// 进程退出时写入还没有写入的统计（登记到 closers，在关闭数据库连接之前）。
+func provideStrategyBandit(
+	cfg *config.Config,
+	repo domainRepository.StrategyBanditRepository,
+	audit *service.AuditLogService,
+	logger service.Logger,
+	closers *lifecycle.Closers,
+) *service.StrategyBandit {
+	banditCfg := cfg.StrategyBandit
+	if !banditCfg.Enabled {
+		return nil
+	}
+	policy := domainService.DefaultStrategyBanditPolicy()
+	if banditCfg.MinWeight > 0 {
+		policy.MinWeight = banditCfg.MinWeight
+	}
+	if banditCfg.MaxWeight > 0 {
+		policy.MaxWeight = banditCfg.MaxWeight
+	}
+	if banditCfg.Exploration > 0 {
+		policy.Exploration = banditCfg.Exploration
+	}
+	if banditCfg.MinPulls > 0 {
+		policy.MinPulls = banditCfg.MinPulls
+	}
+	if policy.MinWeight > 1 || policy.MaxWeight < 1 {
+		panic(fmt.Sprintf("strategy_bandit: weights must satisfy min_weight <= 1 <= max_weight, got %v and %v", policy.MinWeight, policy.MaxWeight))
+	}
+	refresh := time.Minute
+	if banditCfg.RefreshSeconds > 0 {
+		refresh = time.Duration(banditCfg.RefreshSeconds) * time.Second
+	}
+	bandit := service.NewStrategyBandit(repo, audit, policy, refresh, logger)
+	closers.AddFunc("strategy bandit", bandit.Close)
+	return bandit
+}
+
+// provideRetentionEnforcer 提供数据保留期执行器
+//
+// 保留期来自配置（retention.max_age），没有列出的类别使用默认值：曝光 90 天、反馈 1 年、推荐结果 30 天。
+// 列出了不认识的类别、或者保留期不是正数时启动失败（写错的配置不能悄悄变成默认值）。
+// 曝光记录、反馈、推荐列表（没有关闭 recommendations 端口时）按保留期清理。
+// 归因转化记录还没有对应的数据类别，暂不按保留期清理（用户数据删除时会删除）。
+func provideRetentionEnforcer(
+	cfg *config.Config,
+	impressionRepo domainRepository.ImpressionRepository,
+	feedbackRepo domainRepository.FeedbackRepository,
+	listRepo domainRepository.RecommendationRepository,
+	metrics service.Metrics,
+) *service.RetentionEnforcer {
+	stores := map[service.DataClass]service.RetentionStore{
+		service.DataClassImpressions: service.NewImpressionRetentionStore(impressionRepo),
+		service.DataClassFeedback:    service.NewFeedbackRetentionStore(feedbackRepo),
+	}
+	if listRepo != nil {
+		stores[service.DataClassRecommendations] = service.NewRecommendationListRetentionStore(listRepo)
+	}
+	return service.NewRetentionEnforcer(retentionPolicies(cfg), stores, metrics)
+}
+
+// retentionPolicies 辅助方法：默认保留期按 retention.max_age 覆盖
+func retentionPolicies(cfg *config.Config) []service.RetentionPolicy {
+	policies := service.DefaultRetentionPolicies()
+	known := make(map[string]bool, len(policies))
+	for i := range policies {
+		class := string(policies[i].Class)
+		known[class] = true
+		if maxAge, ok := cfg.Retention.MaxAge[class]; ok {
+			policies[i].MaxAge = maxAge
+		}
+	}
+	for class, maxAge := range cfg.Retention.MaxAge {
+		if !known[class] {
+			panic(fmt.Sprintf("config: unknown retention.max_age class %q", class))
+		}
+		if maxAge <= 0 {
+			panic(fmt.Sprintf("config: retention.max_age.%s must be positive, got %v", class, maxAge))
+		}
+	}
+	return policies
+}
+
+// provideRetentionJob 提供数据保留期清理任务（间隔来自 retention.interval，为 0 时每天执行一次）
+func provideRetentionJob(cfg *config.Config, enforcer *service.RetentionEnforcer, logger service.Logger) *job.RetentionJob {
+	return job.NewRetentionJob(enforcer, cmp.Or(cfg.Retention.Interval, 24*time.Hour), logger)
+}
+
+// provideReencryptionJob 提供加密字段的重新加密任务（没有开启加密时为 nil）
+//
+// 只有可修改的表重新加密：反馈记录的 mysql 实现。审计日志只追加，旧密钥保留到旧记录过期。
+func provideReencryptionJob(
+	cfg *config.Config,
+	codec *crypto.FieldCodec,
+	feedbackRepo domainRepository.FeedbackRepository,
+	logger service.Logger,
+) *job.ReencryptionJob {
+	if codec == nil {
+		return nil
+	}
+	tables := make(map[string]service.FieldReencryptor)
+	if reencryptor, ok := feedbackRepo.(service.FieldReencryptor); ok {
+		tables["recommendation_feedback"] = reencryptor
+	}
+	return job.NewReencryptionJob(tables, cmp.Or(cfg.Encryption.ReencryptInterval, time.Hour), logger)
+}
+
+// provideWarmup 提供启动预热（配置 warmup）
+//
+// 步骤：
+// 1. 数据库、Redis：当前环境用到时 Ping（必需）
+// 2. 推荐理由文案：每个租户的语言各取一次（可选）
+// 3. 预生成：为 warmup.cohort 中的用户按各自租户生成一次推荐（可选）
+//
+// cohort 中的租户没有配置时启动失败（配置写错应该在启动时发现）。
+func provideWarmup(
+	cfg *config.Config,
+	db *gorm.DB,
+	rdb redis.UniversalClient,
+	tenants *service.Tenants,
+	recommendationService *service.RecommendationService,
+	logger service.Logger,
+) *service.Warmup {
+	var steps []service.WarmupStep
+	if db != nil {
+		steps = append(steps, service.WarmupStep{Name: "database", Run: health.Database(db), Required: true})
+	}
+	if rdb != nil {
+		steps = append(steps, service.WarmupStep{Name: "redis", Run: health.Redis(rdb), Required: true})
+	}
+	steps = append(steps, service.WarmupStep{Name: "reason_texts", Run: recommendationService.PreloadReasonTexts})
+
+	rawTenants := make([]string, 0, len(cfg.Warmup.Cohort))
+	for raw := range cfg.Warmup.Cohort {
+		rawTenants = append(rawTenants, raw)
+	}
+	sort.Strings(rawTenants)
+	for _, raw := range rawTenants {
+		userIDs := cfg.Warmup.Cohort[raw]
+		tenant, err := tenants.Resolve(raw)
+		if err != nil {
+			panic(err)
+		}
+		steps = append(steps, service.WarmupStep{
+			Name: "cohort:" + tenant.String(),
+			Run: func(ctx context.Context) error {
+				return recommendationService.Prewarm(ctx, tenant, userIDs)
+			},
+		})
+	}
+
+	timeout := time.Duration(cfg.Warmup.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return service.NewWarmup(steps, timeout, logger)
+}
+
+// provideHTTPServer 提供 JSON REST 接口（http_server.addr 为空时返回 nil，不启动）
+//
+// 和 Kitex Handler 共用同一个 RecommendationService、授权规则、用户优先级、异常请求检测和负载保护。
+// 监控指标（GET /metrics，Prometheus 抓取）在 http_server.metrics_addr 上单独监听，不经过认证，不暴露在业务端口上。
+// 认证和 Kitex 服务相同：开启 mTLS 时监听 HTTPS、服务名来自客户端证书，终端用户来自签名身份令牌（Authorization: Bearer）。
+func provideHTTPServer(
+	cfg *config.Config,
+	recommendationService *service.RecommendationService,
+	authorizer *service.Authorizer,
+	tenants *service.Tenants,
+	logger service.Logger,
+	metrics *observability.PrometheusMetrics,
+	reloader *mtls.Reloader,
+	tokens service.IdentityVerifier,
+	tiers *service.PriorityTiers,
+	abuseDetector *handler.AbuseDetector,
+	loadShedder *handler.LoadShedder,
+) *httpapi.Server {
+	if cfg.HTTPServer.Addr == "" {
+		return nil
+	}
+	opts := []httpapi.Option{httpapi.WithIdentityVerifier(tokens), httpapi.WithPriorityTiers(tiers)}
+	if addr := cfg.HTTPServer.MetricsAddr; addr != "" {
+		opts = append(opts, httpapi.WithMetricsHandler(addr, metrics))
+	}
+	// 没有开启时是 nil 指针：不能直接传给接口类型的参数
+	if abuseDetector != nil {
+		opts = append(opts, httpapi.WithAbuseDetector(abuseDetector))
+	}
+	if loadShedder != nil {
+		opts = append(opts, httpapi.WithLoadShedder(loadShedder))
+	}
+	if reloader != nil {
+		opts = append(opts, httpapi.WithMutualTLS(reloader, cfg.MTLS.Clients))
+	}
+	if seconds := cfg.HTTPServer.ShutdownTimeoutSeconds; seconds > 0 {
+		opts = append(opts, httpapi.WithShutdownTimeout(time.Duration(seconds)*time.Second))
+	}
+	return httpapi.NewServer(cfg.HTTPServer.Addr, recommendationService, authorizer, tenants, logger, opts...)
+}
+
+// provideServiceRegistry 提供注册中心（profile 中的 registry 端口：none 时为 nil，etcd / nacos 时连接 server.registry）
+//
+// Kitex 服务启动后注册自己（serverRegistryOptions），下游 Kitex 客户端按服务名发现实例（rpcClientOptions）。
+// 进程退出时停止续约（登记到 closers；Kitex 停止时已经注销了实例）。
+func provideServiceRegistry(cfg *config.Config, closers *lifecycle.Closers) discovery.Registry {
+	impl := cfg.Implementation(config.PortRegistry)
+	if impl == config.ImplNone {
+		return nil
+	}
+	registryCfg := cfg.Server.Registry
+	reg, err := discovery.New(discovery.Config{
+		Type:      string(impl),
+		Endpoints: registryCfg.Endpoints,
+		Namespace: registryCfg.Namespace,
+		TTL:       time.Duration(registryCfg.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		panic(err)
+	}
+	closers.AddFunc("service registry", reg.Close)
+	return reg
+}
+
+// serverSettings Kitex 服务的监听地址、服务注册和优雅停止
+type serverSettings struct {
+	Addr            *net.TCPAddr
+	AdminAddr       *net.TCPAddr       // 管理接口的监听地址，server.admin_port 为 0 时为 nil
+	ServiceName     string             // 注册到注册中心的服务名（server.name）
+	Registry        discovery.Registry // 当前环境没有使用注册中心时为 nil
+	ShutdownTimeout time.Duration      // 收到退出信号之后最多等待多久（处理中的请求、释放连接）
+	TLS             *mtls.Reloader     // 应用层 mTLS 的证书，没有开启时为 nil
+}
+
+// provideServerSettings 提供 Kitex 服务的监听地址（server.port，为 0 时 8888）、服务名和优雅停止的超时（为 0 时 10 秒）
+//
+// 管理接口（server.admin_port）单独监听：和推荐接口分开，可以在网络层只对内部管理后台开放。
+func provideServerSettings(cfg *config.Config, reg discovery.Registry, reloader *mtls.Reloader) *serverSettings {
+	var adminAddr *net.TCPAddr
+	if cfg.Server.AdminPort > 0 {
+		adminAddr = &net.TCPAddr{IP: net.IPv4(0, 0, 0, 0), Port: cfg.Server.AdminPort}
+	}
+	return &serverSettings{
+		Addr: &net.TCPAddr{
+			IP:   net.IPv4(0, 0, 0, 0),
+			Port: cmp.Or(cfg.Server.Port, 8888),
+		},
+		AdminAddr:       adminAddr,
+		ServiceName:     cmp.Or(cfg.Server.Name, "recommendation-service"),
+		Registry:        reg,
+		ShutdownTimeout: time.Duration(cmp.Or(cfg.Server.ShutdownTimeoutSeconds, 10)) * time.Second,
+		TLS:             reloader,
+	}
+}
+
+// provideIdentityVerifier 提供身份令牌校验（网关 / BFF 签发的签名令牌）
+//
+// 签名密钥从 secrets 读取（auth/identity-token 的 signing_key；本地开发时是环境变量 AUTH_IDENTITY_TOKEN_SIGNING_KEY）。
+// 没有配置时返回 nil：不接受令牌，只能通过 mTLS 客户端证书认证。
+func provideIdentityVerifier(cfg *config.Config, secretsProvider secrets.Provider) service.IdentityVerifier {
+	key, err := secrets.Value(context.Background(), secretsProvider, cmp.Or(cfg.Secrets.Paths.IdentityToken, "auth/identity-token"), "signing_key")
+	if errors.Is(err, secrets.ErrSecretNotFound) {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	tokens, err := crypto.NewIdentityTokens([]byte(key))
+	if err != nil {
+		panic(err)
+	}
+	return tokens
+}
+
+// provideAuthenticator 提供 Kitex 服务端认证
+//
+// 开启 mTLS 时按客户端证书中校验过的 SPIFFE ID 识别调用方，再按 mtls.clients 映射为服务名；
+// 终端用户来自签名身份令牌。没有可校验身份的请求直接拒绝。
+func provideAuthenticator(cfg *config.Config, reloader *mtls.Reloader, tokens service.IdentityVerifier) *handler.Authenticator {
+	if reloader == nil {
+		return handler.NewAuthenticator(nil, nil, tokens)
+	}
+	return handler.NewAuthenticator(reloader, cfg.MTLS.Clients, tokens)
+}
+
+// recommendationServer 推荐服务启动需要的对象
+//
+// 预热、HTTP 接口必须和 Handler 使用同一个依赖图（同一个连接池、同一份进程内缓存），
+// 否则预热的是另一套对象，对真正接收流量的 Handler 没有帮助。
+// 管理接口同理：修改的策略权重要作用在 Handler 使用的那个 StrategyBandit 上。
+type recommendationServer struct {
+	Server  *serverSettings
+	Handler *handler.RecommendationHandler
+	Warmup  *service.Warmup
+	HTTP    *httpapi.Server     // 没有配置 http_server.addr 时为 nil
+	Metrics *handler.RPCMetrics // Kitex 中间件：按方法记录 RPC 耗时
+
+	Authenticator *handler.Authenticator // Kitex 中间件：识别调用方身份
+	Admin         *handler.AdminHandler  // 管理接口（server.admin_port 单独监听）
+
+	FollowEvents *messaging.Consumer // 关注事件的消费者，没有配置要消费的 topic / Stream 时为 nil
+	Precompute   *job.PrecomputeJob  // 推荐列表预计算，precompute.enabled 为 false 时为 nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"service/interface/replay"
+)
+
+// runReplay 回放模式：把抓取文件中的请求发给当前版本（进程内的 Handler），输出差异报告
+//
+// 当前版本就是候选版本：构建候选版本，指向和线上一致的数据（如只读副本），执行
+//
+//	service -replay capture-2024-05-01.jsonl
+//
+// 所有请求都一致时返回 nil；有差异或失败时返回错误（退出码非 0，便于在 CI 中使用）。
+func runReplay(ctx context.Context, target replay.Target, path string, out io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	exchanges, err := replay.ReadExchanges(f)
+	if err != nil {
+		return err
+	}
+
+	report := replay.NewReplayer(target).Replay(ctx, exchanges)
+	for _, result := range report.Results {
+		request := result.Exchange.Request
+		fmt.Fprintf(out, "user %d (tenant %s, surface %q, captured %s):\n",
+			request.UserId, result.Exchange.Tenant, request.Surface, result.Exchange.CapturedAt.Format("2006-01-02T15:04:05Z07:00"))
+		if result.Err != nil {
+			fmt.Fprintf(out, "  error: %v\n", result.Err)
+			continue
+		}
+		fmt.Fprintf(out, "  %s\n", strings.Join(result.Diffs, "\n  "))
+	}
+	fmt.Fprintf(out, "replayed %d, matched %d, differed %d, failed %d\n",
+		report.Total, report.Matched, len(report.Results)-report.Failures, report.Failures)
+	if report.Matched != report.Total {
+		return fmt.Errorf("%d of %d replayed requests did not match", report.Total-report.Matched, report.Total)
+	}
+	return nil
+}
@@ -19,13 +19,56 @@ package recommendation
 // 2. 版本管理：RPC 接口可以独立演进
 // 3. 类型转换：RPC 的 int64 转换为领域的 UserID
 type GetRecommendationsRequest struct {
-	UserId int64 `thrift:"user_id,1,required" json:"user_id"`
-	Limit  int32 `thrift:"limit,2,optional" json:"limit,omitempty"`
+	UserId         int64                 `thrift:"user_id,1,required" json:"user_id"`
+	Limit          int32                 `thrift:"limit,2,optional" json:"limit,omitempty"`
+	Day            int32                 `thrift:"day,3,optional" json:"day,omitempty"` // 时间窗口（天），为 0 时使用展示场景的默认值
+	Surface        string                `thrift:"surface,4,optional" json:"surface,omitempty"`
+	KnownVersion   string                `thrift:"known_version,5,optional" json:"known_version,omitempty"`
+	SinceVersion   string                `thrift:"since_version,6,optional" json:"since_version,omitempty"`
+	SinceTimestamp int64                 `thrift:"since_timestamp,7,optional" json:"since_timestamp,omitempty"`
+	SessionId      string                `thrift:"session_id,8,optional" json:"session_id,omitempty"`
+	Timezone       string                `thrift:"timezone,9,optional" json:"timezone,omitempty"`
+	Filter         *RecommendationFilter `thrift:"filter,10,optional" json:"filter,omitempty"`
+	PageToken      string                `thrift:"page_token,11,optional" json:"page_token,omitempty"` // 上一页响应的 next_cursor
+	PageSize       int32                 `thrift:"page_size,12,optional" json:"page_size,omitempty"`   // 每页数量，大于 0 时按页返回
+	Debug          bool                  `thrift:"debug,13,optional" json:"debug,omitempty"`           // 调试请求：每条推荐带上分数明细
+}
+
+// RecommendationFilter 请求级别的过滤条件
+type RecommendationFilter struct {
+	MinScore       int32    `thrift:"min_score,1,optional" json:"min_score,omitempty"`
+	ReasonTypes    []string `thrift:"reason_types,2,optional" json:"reason_types,omitempty"`
+	ExcludeUserIds []int64  `thrift:"exclude_user_ids,3,optional" json:"exclude_user_ids,omitempty"`
 }
 
 // GetRecommendationsResponse 推荐响应
 type GetRecommendationsResponse struct {
 	Recommendations []*UserRecommendation `thrift:"recommendations,1,required" json:"recommendations"`
+	Degraded        bool                  `thrift:"degraded,2,optional" json:"degraded,omitempty"`
+	Metadata        *ResponseMetadata     `thrift:"metadata,3,optional" json:"metadata,omitempty"`
+	Version         string                `thrift:"version,4,optional" json:"version,omitempty"`
+	NotModified     bool                  `thrift:"not_modified,5,optional" json:"not_modified,omitempty"`
+	Delta           bool                  `thrift:"delta,6,optional" json:"delta,omitempty"`
+	Tombstones      []*Tombstone          `thrift:"tombstones,7,optional" json:"tombstones,omitempty"`
+	Precomputed     bool                  `thrift:"precomputed,8,optional" json:"precomputed,omitempty"`
+	NextCursor      string                `thrift:"next_cursor,9,optional" json:"next_cursor,omitempty"`
+	HasMore         bool                  `thrift:"has_more,10,optional" json:"has_more,omitempty"`
+}
+
+// Tombstone 墓碑：客户端缓存中需要删除的推荐
+type Tombstone struct {
+	UserId int64  `thrift:"user_id,1,required" json:"user_id"`
+	Reason string `thrift:"reason,2,required" json:"reason"`
+}
+
+// ResponseMetadata 推荐生成过程的元数据
+type ResponseMetadata struct {
+	FollowingsSampled  bool   `thrift:"followings_sampled,1,optional" json:"followings_sampled,omitempty"`
+	FollowingsTotal    int32  `thrift:"followings_total,2,optional" json:"followings_total,omitempty"`
+	FollowingsUsed     int32  `thrift:"followings_used,3,optional" json:"followings_used,omitempty"`
+	CalibrationVersion string `thrift:"calibration_version,4,optional" json:"calibration_version,omitempty"`
+	Experiment         string `thrift:"experiment,5,optional" json:"experiment,omitempty"`
+	Variant            string `thrift:"variant,6,optional" json:"variant,omitempty"`
 }
 
 // UserRecommendation 用户推荐
@@ -42,6 +85,26 @@ type UserRecommendation struct {
 	Reason      string  `thrift:"reason,5,required" json:"reason"`
 	Score       int32   `thrift:"score,6,required" json:"score"`
 	RecentPosts []*Post `thrift:"recent_posts,7,required" json:"recent_posts"`
+	// CalibratedScore 校准后的分数（没有校准时为 nil）
+	CalibratedScore *float64 `thrift:"calibrated_score,8,optional" json:"calibrated_score,omitempty"`
+	// ProfilePending 资料没有取到，客户端应按 user_id 刷新资料
+	ProfilePending bool `thrift:"profile_pending,9,optional" json:"profile_pending,omitempty"`
+	// Status ok / degraded；Degraded 降级的部分（profile、posts）
+	Status   string   `thrift:"status,10,optional" json:"status,omitempty"`
+	Degraded []string `thrift:"degraded,11,optional" json:"degraded,omitempty"`
+	// ScoreDetails 分数明细（只有调试请求才有）
+	ScoreDetails *ScoreDetails `thrift:"score_details,12,optional" json:"score_details,omitempty"`
+	// ReasonType 推荐理由类型（backfill 表示补足一页的热门用户）
+	ReasonType string `thrift:"reason_type,13,optional" json:"reason_type,omitempty"`
+}
+
+// ScoreDetails 一条推荐的分数明细：各因素的贡献之和等于 score
+type ScoreDetails struct {
+	Factors              []*ScoreFactor `thrift:"factors,1,required" json:"factors"`
+	RulesVersion         string         `thrift:"rules_version,2,required" json:"rules_version"`
+	PerRelatedUser       int32          `thrift:"per_related_user,3,required" json:"per_related_user"`
+	PerRecentPost        int32          `thrift:"per_recent_post,4,required" json:"per_recent_post"`
+	PerHundredEngagement int32          `thrift:"per_hundred_engagement,5,required" json:"per_hundred_engagement"`
 }
 
 // Post 帖子
@@ -50,11 +113,413 @@ type UserRecommendation struct {
 // - RPC Post：简单的数据结构，用于传输
 // - 领域 Post：实体对象，有 ID、行为方法
 type Post struct {
-	PostId    int64  `thrift:"post_id,1,required" json:"post_id"`
-	Content   string `thrift:"content,2,required" json:"content"`
-	CreatedAt string `thrift:"created_at,3,required" json:"created_at"`
+	PostId           int64  `thrift:"post_id,1,required" json:"post_id"`
+	Content          string `thrift:"content,2,required" json:"content"`
+	CreatedAt        string `thrift:"created_at,3,required" json:"created_at"` // 兼容字段：旧格式，配置 post_timestamps.omit_legacy 后为空
+	Masked           bool   `thrift:"masked,4,optional" json:"masked,omitempty"`
+	CreatedAtRfc3339 string `thrift:"created_at_rfc3339,5,optional" json:"created_at_rfc3339,omitempty"`
+	CreatedAtDisplay string `thrift:"created_at_display,6,optional" json:"created_at_display,omitempty"`
+}
+
+// DeleteUserDataRequest 用户数据删除请求（内部接口）
+type DeleteUserDataRequest struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	RequestId string `thrift:"request_id,2,optional" json:"request_id,omitempty"`
+}
+
+// DeleteUserDataResponse 用户数据删除响应
+type DeleteUserDataResponse struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	RequestId string `thrift:"request_id,2,optional" json:"request_id,omitempty"`
+}
+
+// ExportUserDataRequest 用户数据导出请求（内部接口）
+type ExportUserDataRequest struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	RequestId string `thrift:"request_id,2,optional" json:"request_id,omitempty"`
+}
+
+// ExportUserDataResponse 用户数据导出响应
+type ExportUserDataResponse struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	RequestId string `thrift:"request_id,2,optional" json:"request_id,omitempty"`
+	Data      string `thrift:"data,3,required" json:"data"` // JSON 格式的导出数据
+}
+
+// GetRecommendationHistoryRequest 推荐历史查询请求
+type GetRecommendationHistoryRequest struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	From      int64  `thrift:"from,2,optional" json:"from,omitempty"`
+	To        int64  `thrift:"to,3,optional" json:"to,omitempty"`
+	Limit     int32  `thrift:"limit,4,optional" json:"limit,omitempty"`
+	PageToken string `thrift:"page_token,5,optional" json:"page_token,omitempty"`
+}
+
+// GetRecommendationHistoryResponse 推荐历史查询响应
+type GetRecommendationHistoryResponse struct {
+	Items         []*RecommendationHistoryItem `thrift:"items,1,required" json:"items"`
+	NextPageToken string                       `thrift:"next_page_token,2,optional" json:"next_page_token,omitempty"`
+}
+
+// RecommendationHistoryItem 一次展示过的推荐
+type RecommendationHistoryItem struct {
+	RecommendationId string `thrift:"recommendation_id,1,required" json:"recommendation_id"`
+	UserId           int64  `thrift:"user_id,2,required" json:"user_id"`
+	Username         string `thrift:"username,3,optional" json:"username,omitempty"`
+	Avatar           string `thrift:"avatar,4,optional" json:"avatar,omitempty"`
+	Surface          string `thrift:"surface,5,optional" json:"surface,omitempty"`
+	ShownAt          string `thrift:"shown_at,6,required" json:"shown_at"`
+}
+
+// GetSuggestedPostsRequest 帖子推荐请求（探索页）
+type GetSuggestedPostsRequest struct {
+	UserId   int64  `thrift:"user_id,1,required" json:"user_id"`
+	Limit    int32  `thrift:"limit,2,optional" json:"limit,omitempty"`
+	Surface  string `thrift:"surface,3,optional" json:"surface,omitempty"`
+	Timezone string `thrift:"timezone,4,optional" json:"timezone,omitempty"`
+}
+
+// GetSuggestedPostsResponse 帖子推荐响应
+type GetSuggestedPostsResponse struct {
+	Posts []*SuggestedPost `thrift:"posts,1,required" json:"posts"`
+}
+
+// SuggestedPost 推荐的帖子
+type SuggestedPost struct {
+	RecommendationId string `thrift:"recommendation_id,1,required" json:"recommendation_id"`
+	PostId           int64  `thrift:"post_id,2,required" json:"post_id"`
+	AuthorId         int64  `thrift:"author_id,3,required" json:"author_id"`
+	AuthorUsername   string `thrift:"author_username,4,optional" json:"author_username,omitempty"`
+	AuthorAvatar     string `thrift:"author_avatar,5,optional" json:"author_avatar,omitempty"`
+	Content          string `thrift:"content,6,required" json:"content"`
+	CreatedAt        string `thrift:"created_at,7,required" json:"created_at"`
+	Masked           bool   `thrift:"masked,8,optional" json:"masked,omitempty"`
+	Reason           string `thrift:"reason,9,required" json:"reason"`
+	Score            int32  `thrift:"score,10,required" json:"score"`
+	CreatedAtRfc3339 string `thrift:"created_at_rfc3339,11,optional" json:"created_at_rfc3339,omitempty"`
+	CreatedAtDisplay string `thrift:"created_at_display,12,optional" json:"created_at_display,omitempty"`
+}
+
+// GetTopicRecommendationsRequest 话题推荐请求
+type GetTopicRecommendationsRequest struct {
+	UserId int64 `thrift:"user_id,1,required" json:"user_id"`
+	Limit  int32 `thrift:"limit,2,optional" json:"limit,omitempty"`
+}
+
+// GetTopicRecommendationsResponse 话题推荐响应
+type GetTopicRecommendationsResponse struct {
+	Topics []*TopicRecommendation `thrift:"topics,1,required" json:"topics"`
+}
+
+// TopicRecommendation 推荐的话题
+type TopicRecommendation struct {
+	RecommendationId string `thrift:"recommendation_id,1,required" json:"recommendation_id"`
+	Topic            string `thrift:"topic,2,required" json:"topic"`
+	Hashtag          string `thrift:"hashtag,3,required" json:"hashtag"`
+	Reason           string `thrift:"reason,4,required" json:"reason"`
+	Score            int32  `thrift:"score,5,required" json:"score"`
+}
+
+// GetGroupRecommendationsRequest 小组推荐请求
+type GetGroupRecommendationsRequest struct {
+	UserId int64 `thrift:"user_id,1,required" json:"user_id"`
+	Limit  int32 `thrift:"limit,2,optional" json:"limit,omitempty"`
+}
+
+// GetGroupRecommendationsResponse 小组推荐响应
+type GetGroupRecommendationsResponse struct {
+	Groups []*GroupRecommendation `thrift:"groups,1,required" json:"groups"`
+}
+
+// GroupRecommendation 推荐的小组
+type GroupRecommendation struct {
+	RecommendationId string `thrift:"recommendation_id,1,required" json:"recommendation_id"`
+	GroupId          int64  `thrift:"group_id,2,required" json:"group_id"`
+	MemberCount      int32  `thrift:"member_count,3,optional" json:"member_count,omitempty"`
+	Reason           string `thrift:"reason,4,required" json:"reason"`
+	Score            int32  `thrift:"score,5,required" json:"score"`
+}
+
+// GetRecommendationBundleRequest 分栏推荐请求（发现页）
+type GetRecommendationBundleRequest struct {
+	UserId int64 `thrift:"user_id,1,required" json:"user_id"`
+}
+
+// GetRecommendationBundleResponse 分栏推荐响应
+type GetRecommendationBundleResponse struct {
+	Sections []*RecommendationSection `thrift:"sections,1,required" json:"sections"`
+	Degraded bool                     `thrift:"degraded,2,optional" json:"degraded,omitempty"`
+}
+
+// RecommendationSection 发现页的一个分栏
+type RecommendationSection struct {
+	Key             string                `thrift:"key,1,required" json:"key"`
+	Title           string                `thrift:"title,2,required" json:"title"`
+	Recommendations []*UserRecommendation `thrift:"recommendations,3,required" json:"recommendations"`
+}
+
+// GetTriggerRecommendationsRequest 触发式推荐请求（推送管道）
+type GetTriggerRecommendationsRequest struct {
+	UserId int64 `thrift:"user_id,1,required" json:"user_id"`
+}
+
+// GetTriggerRecommendationsResponse 触发式推荐响应
+type GetTriggerRecommendationsResponse struct {
+	Recommendations []*TriggerRecommendation `thrift:"recommendations,1,required" json:"recommendations"`
+}
+
+// TriggerRecommendation 一条触发式推荐
+type TriggerRecommendation struct {
+	UserId    int64  `thrift:"user_id,1,required" json:"user_id"`
+	Trigger   string `thrift:"trigger,2,required" json:"trigger"`
+	Reason    string `thrift:"reason,3,required" json:"reason"`
+	Score     int32  `thrift:"score,4,required" json:"score"`
+	ExpiresAt int64  `thrift:"expires_at,5,required" json:"expires_at"`
+}
+
+// ExplainRecommendationRequest 推荐解释请求（内部接口，质量评审工具）
+type ExplainRecommendationRequest struct {
+	ViewerId     int64  `thrift:"viewer_id,1,required" json:"viewer_id"`
+	TargetUserId int64  `thrift:"target_user_id,2,required" json:"target_user_id"`
+	Surface      string `thrift:"surface,3,optional" json:"surface,omitempty"`
+}
+
+// ExplainRecommendationResponse 推荐解释响应
+type ExplainRecommendationResponse struct {
+	Found              bool           `thrift:"found,1,required" json:"found"`
+	ReasonType         string         `thrift:"reason_type,2,optional" json:"reason_type,omitempty"`
+	Position           int32          `thrift:"position,3,required" json:"position"` // 没有找到时为 -1
+	Candidates         int32          `thrift:"candidates,4,required" json:"candidates"`
+	Score              int32          `thrift:"score,5,required" json:"score"`
+	Factors            []*ScoreFactor `thrift:"factors,6,required" json:"factors"`
+	StrategyWeight     float64        `thrift:"strategy_weight,7,required" json:"strategy_weight"`
+	CalibratedScore    *float64       `thrift:"calibrated_score,8,optional" json:"calibrated_score,omitempty"`
+	RulesVersion       string         `thrift:"rules_version,9,required" json:"rules_version"`
+	PerRelatedUser     int32          `thrift:"per_related_user,10,required" json:"per_related_user"`
+	PerRecentPost      int32          `thrift:"per_recent_post,11,required" json:"per_recent_post"`
+	CalibrationVersion string         `thrift:"calibration_version,12,optional" json:"calibration_version,omitempty"`
+	FiltersPassed      []string       `thrift:"filters_passed,13,required" json:"filters_passed"`
+}
+
+// ScoreFactor 一个打分因素对分数的贡献
+type ScoreFactor struct {
+	Name         string `thrift:"name,1,required" json:"name"`
+	Value        int32  `thrift:"value,2,required" json:"value"`
+	Contribution int32  `thrift:"contribution,3,required" json:"contribution"`
+}
+
+// GetHybridRecommendationsRequest 混合推荐请求（关注推荐 + 热度推荐）
+type GetHybridRecommendationsRequest struct {
+	UserId   int64  `thrift:"user_id,1,required" json:"user_id"`
+	Limit    int32  `thrift:"limit,2,optional" json:"limit,omitempty"`
+	Surface  string `thrift:"surface,3,optional" json:"surface,omitempty"`
+	Timezone string `thrift:"timezone,4,optional" json:"timezone,omitempty"`
+}
+
+// RecordRecommendationFeedbackRequest 推荐反馈请求（点开、在卡片上关注）
+type RecordRecommendationFeedbackRequest struct {
+	UserId           int64  `thrift:"user_id,1,required" json:"user_id"`
+	RecommendationId string `thrift:"recommendation_id,2,required" json:"recommendation_id"`
+	Action           string `thrift:"action,3,required" json:"action"`
+}
+
+// RecordRecommendationFeedbackResponse 推荐反馈响应
+type RecordRecommendationFeedbackResponse struct{}
+
+// FollowUserRequest 关注请求（用户主动关注）
+type FollowUserRequest struct {
+	FollowerId  int64  `thrift:"follower_id,1,required" json:"follower_id"`
+	FollowingId int64  `thrift:"following_id,2,required" json:"following_id"`
+	Source      string `thrift:"source,3,optional" json:"source,omitempty"`
+}
+
+// FollowUserResponse 关注响应
+type FollowUserResponse struct{}
+
+// UnfollowUserRequest 取关请求（用户主动取关）
+type UnfollowUserRequest struct {
+	FollowerId  int64 `thrift:"follower_id,1,required" json:"follower_id"`
+	FollowingId int64 `thrift:"following_id,2,required" json:"following_id"`
+}
+
+// UnfollowUserResponse 取关响应
+type UnfollowUserResponse struct{}
+
+// BatchGetRecommendationsRequest 批量推荐请求（推送管道）
+type BatchGetRecommendationsRequest struct {
+	UserIds []int64               `thrift:"user_ids,1,required" json:"user_ids"`
+	Limit   int32                 `thrift:"limit,2,optional" json:"limit,omitempty"`
+	Surface string                `thrift:"surface,3,optional" json:"surface,omitempty"`
+	Filter  *RecommendationFilter `thrift:"filter,4,optional" json:"filter,omitempty"`
+}
+
+// BatchGetRecommendationsResponse 批量推荐响应（每个用户一个结果，顺序和 UserIds 相同）
+type BatchGetRecommendationsResponse struct {
+	Results []*BatchRecommendationResult `thrift:"results,1,required" json:"results"`
+}
+
+// BatchRecommendationResult 一个用户的推荐结果
+type BatchRecommendationResult struct {
+	UserId          int64                 `thrift:"user_id,1,required" json:"user_id"`
+	Status          string                `thrift:"status,2,required" json:"status"` // ok / degraded / error
+	ErrorCode       int32                 `thrift:"error_code,3,optional" json:"error_code,omitempty"`
+	ErrorMessage    string                `thrift:"error_message,4,optional" json:"error_message,omitempty"`
+	Recommendations []*UserRecommendation `thrift:"recommendations,5,optional" json:"recommendations,omitempty"`
+	Version         string                `thrift:"version,6,optional" json:"version,omitempty"`
+}
+
+// QueryAuditLogRequest 审计日志查询请求（管理接口）
+type QueryAuditLogRequest struct {
+	Actor  string `thrift:"actor,1,optional" json:"actor,omitempty"`
+	Action string `thrift:"action,2,optional" json:"action,omitempty"`
+	Target string `thrift:"target,3,optional" json:"target,omitempty"`
+	Since  int64  `thrift:"since,4,optional" json:"since,omitempty"`
+	Until  int64  `thrift:"until,5,optional" json:"until,omitempty"`
+	Limit  int32  `thrift:"limit,6,optional" json:"limit,omitempty"`
+}
+
+// QueryAuditLogResponse 审计日志查询响应
+type QueryAuditLogResponse struct {
+	Entries []*AuditEntry `thrift:"entries,1,required" json:"entries"`
+}
+
+// AuditEntry 审计记录
+type AuditEntry struct {
+	Actor      string `thrift:"actor,1,required" json:"actor"`
+	Action     string `thrift:"action,2,required" json:"action"`
+	Target     string `thrift:"target,3,optional" json:"target,omitempty"`
+	Before     string `thrift:"before,4,optional" json:"before,omitempty"`
+	After      string `thrift:"after,5,optional" json:"after,omitempty"`
+	RequestId  string `thrift:"request_id,6,optional" json:"request_id,omitempty"`
+	OccurredAt string `thrift:"occurred_at,7,required" json:"occurred_at"`
 }
 
+// GetDependencyStatusRequest 依赖状态查询请求（管理接口）
+type GetDependencyStatusRequest struct {
+}
+
+// GetDependencyStatusResponse 依赖状态查询响应
+type GetDependencyStatusResponse struct {
+	Dependencies []*DependencyStatus `thrift:"dependencies,1,required" json:"dependencies"`
+}
+
+// DependencyStatus 一个已装配依赖的状态
+type DependencyStatus struct {
+	Name           string `thrift:"name,1,required" json:"name"`
+	Type           string `thrift:"type,2,required" json:"type"`
+	Implementation string `thrift:"implementation,3,required" json:"implementation"`
+	Target         string `thrift:"target,4,optional" json:"target,omitempty"`
+	Health         string `thrift:"health,5,required" json:"health"`
+	LastError      string `thrift:"last_error,6,optional" json:"last_error,omitempty"`
+	CheckedAt      string `thrift:"checked_at,7,optional" json:"checked_at,omitempty"`
+	LatencyMs      int64  `thrift:"latency_ms,8,optional" json:"latency_ms,omitempty"`
+	CircuitBreaker string `thrift:"circuit_breaker,9,required" json:"circuit_breaker"`
+}
+
+// GetFeedbackStatsRequest 推荐效果统计查询请求（管理接口）
+type GetFeedbackStatsRequest struct {
+	From int64 `thrift:"from,1,optional" json:"from,omitempty"`
+	To   int64 `thrift:"to,2,optional" json:"to,omitempty"`
+}
+
+// GetFeedbackStatsResponse 推荐效果统计查询响应
+type GetFeedbackStatsResponse struct {
+	Stats []*FeedbackStats `thrift:"stats,1,required" json:"stats"`
+}
+
+// FeedbackStats 一天、一种策略、一种推荐理由、一个展示场景的推荐效果
+type FeedbackStats struct {
+	Day            string  `thrift:"day,1,required" json:"day"`
+	Strategy       string  `thrift:"strategy,2,required" json:"strategy"`
+	ReasonType     string  `thrift:"reason_type,3,required" json:"reason_type"`
+	Surface        string  `thrift:"surface,4,optional" json:"surface,omitempty"`
+	Impressions    int64   `thrift:"impressions,5,required" json:"impressions"`
+	Conversions    int64   `thrift:"conversions,6,required" json:"conversions"`
+	ConversionRate float64 `thrift:"conversion_rate,7,required" json:"conversion_rate"`
+}
+
+// GetStrategyStatsRequest 按策略汇总的推荐效果查询请求（管理接口）
+type GetStrategyStatsRequest struct {
+	From int64 `thrift:"from,1,optional" json:"from,omitempty"`
+	To   int64 `thrift:"to,2,optional" json:"to,omitempty"`
+}
+
+// GetStrategyStatsResponse 按策略汇总的推荐效果查询响应
+type GetStrategyStatsResponse struct {
+	Stats []*StrategyStats `thrift:"stats,1,required" json:"stats"`
+}
+
+// StrategyStats 一种策略的推荐效果（基于归因转化记录）
+type StrategyStats struct {
+	Strategy         string  `thrift:"strategy,1,required" json:"strategy"`
+	Impressions      int64   `thrift:"impressions,2,required" json:"impressions"`
+	Clicks           int64   `thrift:"clicks,3,required" json:"clicks"`
+	Conversions      int64   `thrift:"conversions,4,required" json:"conversions"`
+	ClickThroughRate float64 `thrift:"click_through_rate,5,required" json:"click_through_rate"`
+	ConversionRate   float64 `thrift:"conversion_rate,6,required" json:"conversion_rate"`
+}
+
+// GetInterleavingStatsRequest 交错实验结果查询请求（管理接口）
+type GetInterleavingStatsRequest struct {
+	Experiment string `thrift:"experiment,1,required" json:"experiment"`
+	From       int64  `thrift:"from,2,optional" json:"from,omitempty"`
+	To         int64  `thrift:"to,3,optional" json:"to,omitempty"`
+}
+
+// GetInterleavingStatsResponse 交错实验结果查询响应
+type GetInterleavingStatsResponse struct {
+	Experiment string  `thrift:"experiment,1,required" json:"experiment"`
+	Sessions   int64   `thrift:"sessions,2,required" json:"sessions"`
+	WinsA      int64   `thrift:"wins_a,3,required" json:"wins_a"`
+	WinsB      int64   `thrift:"wins_b,4,required" json:"wins_b"`
+	Ties       int64   `thrift:"ties,5,required" json:"ties"`
+	WinRateA   float64 `thrift:"win_rate_a,6,required" json:"win_rate_a"`
+	WinRateB   float64 `thrift:"win_rate_b,7,required" json:"win_rate_b"`
+}
+
+// GetStrategyWeightsRequest 策略权重查询请求（管理接口）
+type GetStrategyWeightsRequest struct {
+	Surface string `thrift:"surface,1,optional" json:"surface,omitempty"`
+}
+
+// StrategyArm 一种策略（推荐理由类型）的 bandit 统计和权重
+type StrategyArm struct {
+	Arm            string  `thrift:"arm,1,required" json:"arm"`
+	Pulls          int64   `thrift:"pulls,2,required" json:"pulls"`
+	Rewards        int64   `thrift:"rewards,3,required" json:"rewards"`
+	ConversionRate float64 `thrift:"conversion_rate,4,required" json:"conversion_rate"`
+	BanditWeight   float64 `thrift:"bandit_weight,5,required" json:"bandit_weight"`
+	Weight         float64 `thrift:"weight,6,required" json:"weight"`
+}
+
+// StrategyWeightOverride 策略权重的人工覆盖
+type StrategyWeightOverride struct {
+	Disabled  bool               `thrift:"disabled,1,required" json:"disabled"`
+	Weights   map[string]float64 `thrift:"weights,2,optional" json:"weights,omitempty"`
+	UpdatedBy string             `thrift:"updated_by,3,required" json:"updated_by"`
+	UpdatedAt int64              `thrift:"updated_at,4,required" json:"updated_at"`
+}
+
+// GetStrategyWeightsResponse 策略权重查询响应
+type GetStrategyWeightsResponse struct {
+	Surface  string                  `thrift:"surface,1,required" json:"surface"`
+	Arms     []*StrategyArm          `thrift:"arms,2,required" json:"arms"`
+	Override *StrategyWeightOverride `thrift:"override,3,optional" json:"override,omitempty"`
+}
+
+// SetStrategyWeightOverrideRequest 设置策略权重的人工覆盖（管理接口）
+type SetStrategyWeightOverrideRequest struct {
+	Surface   string             `thrift:"surface,1,optional" json:"surface,omitempty"`
+	Operator  string             `thrift:"operator,2,optional" json:"operator,omitempty"` // 已废弃：操作人取调用方认证后的身份
+	RequestId string             `thrift:"request_id,3,optional" json:"request_id,omitempty"`
+	Clear     bool               `thrift:"clear,4,optional" json:"clear,omitempty"`
+	Disabled  bool               `thrift:"disabled,5,optional" json:"disabled,omitempty"`
+	Weights   map[string]float64 `thrift:"weights,6,optional" json:"weights,omitempty"`
+}
+
+// SetStrategyWeightOverrideResponse 设置策略权重的人工覆盖响应
+type SetStrategyWeightOverrideResponse struct{}
+
 // NewGetRecommendationsRequest 创建请求对象
 func NewGetRecommendationsRequest() *GetRecommendationsRequest {
 	return &GetRecommendationsRequest{
@@ -82,7 +547,236 @@ func (p *GetRecommendationsRequest) GetLimit() int32 {
 	return p.Limit
 }
 
+// GetDay 获取时间窗口（为 0 时使用展示场景的默认值）
+func (p *GetRecommendationsRequest) GetDay() int32 {
+	return p.Day
+}
+
+// GetSurface 获取展示场景
+func (p *GetRecommendationsRequest) GetSurface() string {
+	return p.Surface
+}
+
+// GetKnownVersion 获取客户端缓存的列表版本
+func (p *GetRecommendationsRequest) GetKnownVersion() string {
+	return p.KnownVersion
+}
+
+// GetSinceVersion 获取上次同步的列表版本
+func (p *GetRecommendationsRequest) GetSinceVersion() string {
+	return p.SinceVersion
+}
+
+// GetSinceTimestamp 获取上次同步的时间（Unix 秒）
+func (p *GetRecommendationsRequest) GetSinceTimestamp() int64 {
+	return p.SinceTimestamp
+}
+
+// GetSessionId 获取客户端会话ID
+func (p *GetRecommendationsRequest) GetSessionId() string {
+	return p.SessionId
+}
+
+// GetTimezone 获取客户端时区
+func (p *GetRecommendationsRequest) GetTimezone() string {
+	return p.Timezone
+}
+
+// GetFilter 获取过滤条件（没有时为 nil）
+func (p *GetRecommendationsRequest) GetFilter() *RecommendationFilter {
+	return p.Filter
+}
+
+// GetPageToken 获取分页位置
+func (p *GetRecommendationsRequest) GetPageToken() string {
+	return p.PageToken
+}
+
+// GetPageSize 获取每页数量
+func (p *GetRecommendationsRequest) GetPageSize() int32 {
+	return p.PageSize
+}
+
+// GetDebug 是否为调试请求
+func (p *GetRecommendationsRequest) GetDebug() bool {
+	return p.Debug
+}
+
 // GetRecommendations 获取推荐列表
 func (p *GetRecommendationsResponse) GetRecommendations() []*UserRecommendation {
 	return p.Recommendations
 }
+
+// GetDegraded 是否为降级响应
+func (p *GetRecommendationsResponse) GetDegraded() bool {
+	return p.Degraded
+}
+
+// GetMetadata 获取生成过程的元数据
+func (p *GetRecommendationsResponse) GetMetadata() *ResponseMetadata {
+	return p.Metadata
+}
+
+// GetVersion 获取列表版本
+func (p *GetRecommendationsResponse) GetVersion() string {
+	return p.Version
+}
+
+// GetNotModified 列表是否没有变化
+func (p *GetRecommendationsResponse) GetNotModified() bool {
+	return p.NotModified
+}
+
+// GetDelta 是否为增量响应
+func (p *GetRecommendationsResponse) GetDelta() bool {
+	return p.Delta
+}
+
+// GetTombstones 获取需要删除的推荐
+func (p *GetRecommendationsResponse) GetTombstones() []*Tombstone {
+	return p.Tombstones
+}
+
+// GetPrecomputed 列表是否来自快照
+func (p *GetRecommendationsResponse) GetPrecomputed() bool {
+	return p.Precomputed
+}
+
+// GetNextCursor 获取下一页的分页位置
+func (p *GetRecommendationsResponse) GetNextCursor() string {
+	return p.NextCursor
+}
+
+// GetHasMore 是否还有下一页
+func (p *GetRecommendationsResponse) GetHasMore() bool {
+	return p.HasMore
+}
+
+// GetUserId 获取用户ID
+func (p *DeleteUserDataRequest) GetUserId() int64 {
+	return p.UserId
+}
+
+// GetRequestId 获取删除工单号
+func (p *DeleteUserDataRequest) GetRequestId() string {
+	return p.RequestId
+}
+
+// GetUserId 获取用户ID
+func (p *ExportUserDataRequest) GetUserId() int64 {
+	return p.UserId
+}
+
+// GetRequestId 获取访问请求工单号
+func (p *ExportUserDataRequest) GetRequestId() string {
+	return p.RequestId
+}
+
+// GetLimit 获取每页条数
+func (p *GetRecommendationHistoryRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 20
+	}
+	return p.Limit
+}
+
+// GetPageToken 获取分页位置
+func (p *GetRecommendationHistoryRequest) GetPageToken() string {
+	return p.PageToken
+}
+
+// GetNextPageToken 获取下一页的分页位置
+func (p *GetRecommendationHistoryResponse) GetNextPageToken() string {
+	return p.NextPageToken
+}
+
+// GetLimit 获取返回数量
+func (p *GetSuggestedPostsRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 20
+	}
+	return p.Limit
+}
+
+// GetSurface 获取展示场景
+func (p *GetSuggestedPostsRequest) GetSurface() string {
+	return p.Surface
+}
+
+// GetTimezone 获取客户端时区
+func (p *GetSuggestedPostsRequest) GetTimezone() string {
+	return p.Timezone
+}
+
+// GetLimit 获取每个用户的返回数量
+func (p *BatchGetRecommendationsRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 10
+	}
+	return p.Limit
+}
+
+// GetSurface 获取展示场景
+func (p *BatchGetRecommendationsRequest) GetSurface() string {
+	return p.Surface
+}
+
+// GetFilter 获取过滤条件（没有时为 nil）
+func (p *BatchGetRecommendationsRequest) GetFilter() *RecommendationFilter {
+	return p.Filter
+}
+
+// GetSurface 获取展示场景
+func (p *ExplainRecommendationRequest) GetSurface() string {
+	return p.Surface
+}
+
+// GetCalibratedScore 获取校准后的分数（没有校准参数时为 0）
+func (p *ExplainRecommendationResponse) GetCalibratedScore() float64 {
+	if p.CalibratedScore == nil {
+		return 0
+	}
+	return *p.CalibratedScore
+}
+
+// GetLimit 获取返回数量
+func (p *GetTopicRecommendationsRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 10
+	}
+	return p.Limit
+}
+
+// GetLimit 获取返回数量
+func (p *GetGroupRecommendationsRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 10
+	}
+	return p.Limit
+}
+
+// GetLimit 获取限制数量
+func (p *QueryAuditLogRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 100
+	}
+	return p.Limit
+}
+
+// GetLimit 获取返回数量
+func (p *GetHybridRecommendationsRequest) GetLimit() int32 {
+	if p.Limit == 0 {
+		return 10
+	}
+	return p.Limit
+}
+
+// GetSurface 获取展示场景
+func (p *GetHybridRecommendationsRequest) GetSurface() string {
+	return p.Surface
+}
+
+// GetTimezone 获取客户端时区
+func (p *GetHybridRecommendationsRequest) GetTimezone() string {
+	return p.Timezone
+}
@@ -0,0 +1,37 @@
+// Code generated by Kitex. DO NOT EDIT.
+//
+// 这是 Kitex 生成的服务接口定义
+// 实际项目中由 kitex 工具自动生成
+
+package recommendation
+
+import (
+	"context"
+)
+
+// RecommendationAdminService 推荐管理服务接口
+//
+// 和 RecommendationService 分开定义：
+// 管理接口只对内部管理后台开放，可以单独部署、单独配置访问控制。
+type RecommendationAdminService interface {
+	// QueryAuditLog 查询管理操作审计日志（最新的在前）
+	QueryAuditLog(ctx context.Context, req *QueryAuditLogRequest) (*QueryAuditLogResponse, error)
+
+	// GetDependencyStatus 查询本实例每个依赖的实现、地址、健康状况
+	GetDependencyStatus(ctx context.Context, req *GetDependencyStatusRequest) (*GetDependencyStatusResponse, error)
+
+	// GetFeedbackStats 查询推荐效果统计（按天、策略、推荐理由、展示场景汇总的曝光和转化）
+	GetFeedbackStats(ctx context.Context, req *GetFeedbackStatsRequest) (*GetFeedbackStatsResponse, error)
+
+	// GetStrategyStats 查询按策略汇总的推荐效果（曝光、点击、归因转化）
+	GetStrategyStats(ctx context.Context, req *GetStrategyStatsRequest) (*GetStrategyStatsResponse, error)
+
+	// GetInterleavingStats 查询交错实验的胜率（比较两个排序器）
+	GetInterleavingStats(ctx context.Context, req *GetInterleavingStatsRequest) (*GetInterleavingStatsResponse, error)
+
+	// GetStrategyWeights 查询各策略的 bandit 统计和当前权重
+	GetStrategyWeights(ctx context.Context, req *GetStrategyWeightsRequest) (*GetStrategyWeightsResponse, error)
+
+	// SetStrategyWeightOverride 固定某个展示场景的策略权重，或关闭 bandit
+	SetStrategyWeightOverride(ctx context.Context, req *SetStrategyWeightOverrideRequest) (*SetStrategyWeightOverrideResponse, error)
+}
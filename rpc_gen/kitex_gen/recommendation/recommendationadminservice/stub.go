@@ -0,0 +1,12 @@
+package recommendationadminservice
+
+import (
+	"service/rpc_gen/kitex_gen/recommendation"
+
+	"github.com/cloudwego/kitex/server"
+)
+
+func NewServer(handler recommendation.RecommendationAdminService, opts ...server.Option) server.Server {
+	_ = handler
+	return server.NewServer(opts...)
+}
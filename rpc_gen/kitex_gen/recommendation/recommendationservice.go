@@ -57,5 +57,74 @@ type RecommendationService interface {
 	//       Limit:  10,
 	//   }
 	//   resp, err := client.GetFollowingBasedRecommendations(ctx, req)
+	//
+	// 分页：设置 page_size，之后把响应的 next_cursor 作为 page_token 带回，直到 has_more 为 false；
+	// 分页位置过期（列表重新生成了）时返回参数错误，客户端从第一页重新开始。
 	GetFollowingBasedRecommendations(ctx context.Context, req *GetRecommendationsRequest) (*GetRecommendationsResponse, error)
+
+	// DeleteUserData 删除用户数据（内部接口，只供隐私团队调用）
+	//
+	// 删除推荐服务保存的用户数据，并停止为该用户生成推荐。
+	// 幂等：同一个用户可以重复调用。
+	DeleteUserData(ctx context.Context, req *DeleteUserDataRequest) (*DeleteUserDataResponse, error)
+
+	// ExportUserData 导出用户数据（内部接口，只供隐私团队调用）
+	//
+	// 返回推荐服务保存的用户数据（JSON），用于处理用户的数据访问请求。
+	ExportUserData(ctx context.Context, req *ExportUserDataRequest) (*ExportUserDataResponse, error)
+
+	// GetRecommendationHistory 查询一段时间内展示给用户的推荐（按展示时间倒序，分页）
+	//
+	// 翻页时把上一页的 next_page_token 原样带回；next_page_token 为空表示没有更多。
+	GetRecommendationHistory(ctx context.Context, req *GetRecommendationHistoryRequest) (*GetRecommendationHistoryResponse, error)
+
+	// GetSuggestedPosts 推荐帖子：二度关系中的作者最近发的帖子（探索页）
+	//
+	// 推荐理由和用户推荐一样是关于作者的（"你关注的 3 个人也关注了作者"）。
+	GetSuggestedPosts(ctx context.Context, req *GetSuggestedPostsRequest) (*GetSuggestedPostsResponse, error)
+
+	// GetTopicRecommendations 推荐话题：我关注的人关注的话题（不包括已经关注的）
+	GetTopicRecommendations(ctx context.Context, req *GetTopicRecommendationsRequest) (*GetTopicRecommendationsResponse, error)
+
+	// GetGroupRecommendations 推荐小组：我关注的人参与的小组（不包括已经加入的）
+	GetGroupRecommendations(ctx context.Context, req *GetGroupRecommendationsRequest) (*GetGroupRecommendationsResponse, error)
+
+	// GetRecommendationBundle 发现页的分栏推荐（分栏的顺序、标题由推荐理由配置服务提供）
+	//
+	// 每个推荐最多出现在一个分栏，没有推荐的分栏不返回。
+	GetRecommendationBundle(ctx context.Context, req *GetRecommendationBundleRequest) (*GetRecommendationBundleResponse, error)
+
+	// GetTriggerRecommendations 触发式推荐（推送管道拉取）：你关注的人达到里程碑、很久之后回来了
+	//
+	// 最多 3 条，按分数降序；过了 expires_at 的推荐不应再推送。
+	GetTriggerRecommendations(ctx context.Context, req *GetTriggerRecommendationsRequest) (*GetTriggerRecommendationsResponse, error)
+
+	// ExplainRecommendation 解释一条推荐的打分（内部接口，质量评审工具）
+	//
+	// 按当前数据重新生成推荐，返回目标用户的位置、各打分因素的贡献、策略权重、校准分数和版本信息。
+	ExplainRecommendation(ctx context.Context, req *ExplainRecommendationRequest) (*ExplainRecommendationResponse, error)
+
+	// BatchGetRecommendations 批量获取推荐（推送管道）
+	//
+	// 每个用户的结果单独带状态（ok / degraded / error）和错误码：
+	// 部分用户失败时整个请求仍然成功，调用方只重试失败的用户。
+	BatchGetRecommendations(ctx context.Context, req *BatchGetRecommendationsRequest) (*BatchGetRecommendationsResponse, error)
+
+	// GetHybridRecommendations 混合推荐：关注推荐和热度推荐并发生成，按策略权重混合后重新排序
+	//
+	// 同一个人只出现一次；不使用快照，响应不带 version，不支持增量同步和分页。
+	GetHybridRecommendations(ctx context.Context, req *GetHybridRecommendationsRequest) (*GetRecommendationsResponse, error)
+
+	// RecordRecommendationFeedback 记录推荐反馈：点开、在推荐卡片上关注
+	//
+	// 只能对展示给自己的推荐反馈；同一条推荐的同一种反馈重复上报只记录一次。
+	RecordRecommendationFeedback(ctx context.Context, req *RecordRecommendationFeedbackRequest) (*RecordRecommendationFeedbackResponse, error)
+
+	// FollowUser 关注：不能关注自己，已经在关注（包括静音）时返回错误
+	//
+	// 关注之后立即删除关注者的推荐列表缓存；在推荐卡片上关注（source = recommendation）立即归因到推荐曝光。
+	FollowUser(ctx context.Context, req *FollowUserRequest) (*FollowUserResponse, error)
+
+	// UnfollowUser 取关（软删除，重新关注时复用关注关系）：没有在关注时返回错误
+	UnfollowUser(ctx context.Context, req *UnfollowUserRequest) (*UnfollowUserResponse, error)
 }
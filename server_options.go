@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net"
+
+	"github.com/cloudwego/kitex/pkg/remote/trans/gonet"
+	"github.com/cloudwego/kitex/server"
+
+	"service/interface/handler"
+)
+
+// serverTransportOptions 服务监听选项：开启了 mTLS（mtls.cert_file）时使用 TLS 监听
+//
+// 遗留环境没有服务网格，服务间的双向认证在应用层完成。
+// 证书由 provideMTLS 加载，只接受 mtls.clients 中的调用方；
+// 每个连接的对端身份记录在 Reloader 中，认证中间件（handler.Authenticator）按对端地址取出。
+// 证书文件定期检查，轮换后新连接自动使用新证书，不需要重启。
+// Kitex 默认的 netpoll 传输层不支持 TLS，开启 mTLS 时换成 gonet 传输层。
+//
+// 推荐接口（settings.Addr）和管理接口（settings.AdminAddr）各自监听，使用同一份证书。
+func serverTransportOptions(settings *serverSettings, addr *net.TCPAddr) ([]server.Option, error) {
+	if settings.TLS == nil {
+		return []server.Option{server.WithServiceAddr(addr)}, nil
+	}
+
+	ln, err := net.ListenTCP("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return []server.Option{
+		server.WithServiceAddr(addr),
+		server.WithListener(settings.TLS.NewListener(ln)),
+		server.WithTransServerFactory(gonet.NewTransServerFactory()),
+		server.WithTransHandlerFactory(gonet.NewSvrTransHandlerFactory()),
+	}, nil
+}
+
+// adminServerOptions 管理接口（RecommendationAdminService）的服务选项
+//
+// 和推荐接口相同的传输层（mTLS）、耗时统计、认证、租户识别；授权检查在 AdminHandler 中（authorization.admin_services）。
+// 不注册到注册中心：管理后台按地址直连。
+func adminServerOptions(
+	settings *serverSettings,
+	rpcMetrics *handler.RPCMetrics,
+	authenticator *handler.Authenticator,
+	tenantResolver *handler.TenantResolver,
+) ([]server.Option, error) {
+	opts, err := serverTransportOptions(settings, settings.AdminAddr)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts,
+		server.WithMiddleware(rpcMetrics.Middleware),
+		server.WithMiddleware(authenticator.Middleware),
+		server.WithMiddleware(tenantResolver.Middleware),
+		server.WithExitWaitTime(settings.ShutdownTimeout),
+	), nil
+}
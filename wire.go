@@ -6,8 +6,9 @@ package main
 import (
 	"service/application/service"
 	domainService "service/domain/service"
-	"service/infrastructure/repository"
+	"service/infrastructure/lifecycle"
 	"service/interface/handler"
+	"service/interface/job"
 
 	"github.com/google/wire"
 )
@@ -58,22 +59,70 @@ import (
 // - 易于管理：每层的依赖清晰
 // - 易于复用：可以在不同的 Injector 中复用
 
+// configSet 配置和连接 Provider（所有 Injector 共用）
+//
+// 连接池、后台协程登记到 Injector 参数中的 lifecycle.Closers，进程退出时由 main 统一释放。
+//
+// 包含：
+// - 服务配置（环境 profile 决定每个端口使用哪种实现）
+// - 密钥（Vault / 环境变量）
+// - 敏感字段的加密编解码器（没有开启加密时为 nil）
+// - 数据库连接、Redis 连接（当前环境用不到时为 nil）
+// - 事务边界（多个仓储一起写入，没有数据库连接时为 nil）
+// - 租户配置
+// - 下游调用治理器（HTTP 客户端的重试预算、对冲、自适应限流）
+// - 应用层 mTLS 的证书（入站监听、出站 HTTP 和 Kitex 调用共用，没有开启时为 nil）
+// - 注册中心（当前环境不使用时为 nil）
+// - 监控指标（进程内唯一，所有 Injector 记录到同一份）
+var configSet = wire.NewSet(
+	provideConfig,
+	providePrometheusMetrics,
+	provideMetrics,
+	provideTenants,
+	provideDownstreamGovernor,
+	provideMTLS,
+	provideSecretsProvider,
+	provideFieldCodec,
+	provideDatabase,
+	provideTransactionManager,
+	provideRedis,
+	provideServiceRegistry,
+)
+
 // infrastructureSet 基础设施层 Provider
 //
 // 包含：
 // - RPC 客户端（User 服务、Content 服务、配置服务）
-// - 数据库连接（实际项目中）
-// - Redis 连接（实际项目中）
+// - 推荐列表快照存储
+// - 推荐列表缓存
+// - 触发式推荐存储（推送）
+// - 领域事件的发布者
+// - 日志（带脱敏）
 var infrastructureSet = wire.NewSet(
 	// RPC 客户端
 	provideUserRPCClient,
 	provideContentServiceClient,
 	provideReasonConfigClient,
 
-	// 实际项目中还会有：
-	// provideDatabase,
-	// provideRedis,
-	// provideKafka,
+	// 推荐列表快照（增量同步）
+	provideSnapshotStore,
+
+	// 推荐列表缓存（生成器的结果）
+	provideRecommendationListCache,
+
+	// 触发式推荐（推送管道读取）
+	provideTriggerRecommendationStore,
+
+	// 会话内去重
+	provideSessionStore,
+
+	// 领域事件（推荐反馈）
+	provideEventPublisher,
+
+	// 日志、链路追踪（带脱敏）
+	provideRedactor,
+	provideLogger,
+	provideTracer,
 )
 
 // repositorySet 仓储层 Provider
@@ -81,132 +130,167 @@ var infrastructureSet = wire.NewSet(
 // 包含：
 // - SocialGraphRepository
 // - ContentRepository
+// - TrustRepository（风控分级）
+// - EngagementSignalRepository（实时互动信号，可选）
+// - ImpressionRepository（曝光记录）
+// - ConversionRepository（归因转化记录）
+// - FeedbackRepository（推荐反馈记录）
+// - StrategyBanditRepository（策略权重 bandit 的臂统计）
+// - UserErasureRepository（用户数据删除记录）
+// - RecentFollowIndexRepository（反向关注索引，可选）
+// - AuditLogRepository（管理操作审计日志）
+// - RecommendationRepository（生成过的推荐列表，可选）
+// - ActiveUsersRepository（最近活跃的用户，可选）
 var repositorySet = wire.NewSet(
 	provideSocialGraphRepository,
 	provideContentRepository,
+	provideTrustRepository,
+	provideEngagementSignalRepository,
+	provideImpressionRepository,
+	provideConversionRepository,
+	provideFeedbackRepository,
+	provideExperimentAssignmentRepository,
+	provideStrategyBanditRepository,
+	provideUserErasureRepository,
+	provideRecentFollowIndex,
+	provideAuditLogRepository,
+	provideTopicRepository,
+	provideGroupMembershipRepository,
+	provideFollowerRepository,
+	provideInteractionRepository,
+	providePopularUsersRepository,
+	provideBlockRepository,
+	provideRecommendationRepository,
+	provideActiveUsersRepository,
 )
 
 // domainServiceSet 领域服务层 Provider
 //
 // 包含：
 // - RecommendationGenerator（推荐生成器）
+// - GeneratorOption（推荐生成器的可选能力）
+// - CandidateRegistry（候选来源、过滤器注册表，按配置启用）
 var domainServiceSet = wire.NewSet(
+	provideCandidateRegistry,
+	provideGeneratorOptions,
 	domainService.NewRecommendationGenerator,
+	domainService.NewTopicRecommender,
+	domainService.NewGroupRecommender,
 )
 
 // applicationServiceSet 应用服务层 Provider
 //
 // 包含：
 // - RecommendationService（推荐应用服务）
+// - RecommendationServiceOption（应用服务的可选能力）
+// - SurfacePolicies（各展示场景的推荐行为）
+// - UserDataService（用户数据删除）
+// - AttributionService（曝光 → 关注的归因）
+// - FollowService（关注、取关）
+// - AuditLogService（管理操作审计）
+// - StrategyBandit（策略权重 bandit，排序、归因和管理接口共用）
 var applicationServiceSet = wire.NewSet(
+	provideSurfacePolicies,
+	provideExperimentAssigner,
+	provideRecommendationServiceOptions,
 	service.NewRecommendationService,
+	provideUserCacheInvalidators,
+	service.NewUserDataService,
+	provideAttributionService,
+	provideFollowService,
+	service.NewTopicRecommendationService,
+	service.NewGroupRecommendationService,
+	service.NewTriggerRecommendationService,
+	service.NewAuditLogService,
+	provideStrategyBandit,
 )
 
 // handlerSet 接口层 Provider
 //
 // 包含：
 // - RecommendationHandler（RPC Handler）
+// - Authorizer（授权检查）
+// - PriorityTiers（用户优先级）
+// - TrafficCapture（流量抓取）
+// - AbuseDetector、LoadShedder（异常请求检测、负载保护，和 HTTP 接口共用）
+// - HandlerOption（Handler 的可选能力，如负载保护）
 var handlerSet = wire.NewSet(
+	provideAuthorizer,
+	providePriorityTiers,
+	provideTrafficCapture,
+	provideAbuseDetector,
+	provideLoadShedder,
+	provideHandlerOptions,
 	handler.NewRecommendationHandler,
 )
 
-// Provider 函数定义
-//
-// 这些函数告诉 Wire 如何构造每个对象。
-// Wire 会分析这些函数的参数和返回值，自动解决依赖关系。
-
-// provideUserRPCClient 提供 User RPC 客户端
-//
-// 实际项目中，这里会：
-// - 读取配置文件
-// - 创建真实的 RPC 客户端
-// - 配置超时、重试等
-//
-// 示例：
-//
-//	func provideUserRPCClient(cfg *Config) service.UserRPCClient {
-//	    client, err := userservice.NewClient(
-//	        cfg.UserService.Name,
-//	        client.WithHostPorts(cfg.UserService.Addr),
-//	    )
-//	    if err != nil {
-//	        panic(err)
-//	    }
-//	    return client
-//	}
-func provideUserRPCClient() service.UserRPCClient {
-	// 示例：使用 mock 实现
-	return repository.NewMockUserRPCClient()
-}
+// adminHandlerSet 管理接口 Provider
+//
+// 包含：
+// - DependencyStatusService（依赖状态报告）
+// - FeedbackAnalyticsService（推荐效果统计）
+// - AdminHandler（管理接口 RPC Handler，只对内部管理后台开放）
+var adminHandlerSet = wire.NewSet(
+	provideDependencyStatusService,
+	provideFeedbackAnalyticsService,
+	handler.NewAdminHandler,
+)
 
-// provideContentServiceClient 提供 Content 服务客户端
-//
-// 这里展示了如何在不同环境使用不同实现：
-// - 开发环境：使用 mock
-// - 测试环境：使用 HTTP 客户端
-// - 生产环境：使用 RPC 客户端
-//
-// 实际项目中，通过配置文件控制：
-//
-//	func provideContentServiceClient(cfg *Config) service.ContentServiceClient {
-//	    switch cfg.ContentService.Type {
-//	    case "rpc":
-//	        return client.NewContentServiceRPCClient(...)
-//	    case "http":
-//	        return client.NewContentServiceHTTPClient(cfg.ContentService.URL)
-//	    default:
-//	        return nil // 使用本地数据库
-//	    }
-//	}
-func provideContentServiceClient() service.ContentServiceClient {
-	// 示例：返回 nil，使用本地数据库
-	// 如果需要使用远程服务，可以改为：
-	// return client.NewContentServiceHTTPClient("http://content-service:8080")
-	// 或：
-	// return client.NewContentServiceRPCClient()
-	return nil
-}
+// warmupSet 启动预热 Provider
+var warmupSet = wire.NewSet(
+	provideWarmup,
+)
 
-// provideReasonConfigClient 提供推荐理由配置服务客户端
+// serverSet 服务监听 Provider
 //
-// 这是一个可选的依赖（可以为 nil）。
-//
-// 实际项目中：
-//
-//	func provideReasonConfigClient(cfg *Config) service.ReasonTextConfigClient {
-//	    if !cfg.Features.UseReasonConfig {
-//	        return nil // 不使用配置服务
-//	    }
-//	    return client.NewReasonTextConfigHTTPClient(cfg.ReasonConfigService.URL)
-//	}
-func provideReasonConfigClient() service.ReasonTextConfigClient {
-	// 示例：不使用配置服务
-	return nil
-}
+// 包含：
+// - Kitex 服务的监听地址、服务注册、优雅停止的超时（server）
+// - HTTP 接口（JSON REST，和 RPC Handler 共用推荐服务、负载保护；监控指标在单独的端口上输出）
+// - RPC 耗时统计中间件
+// - 调用方认证中间件（开启 mTLS 时按客户端证书识别，终端用户按签名身份令牌识别）
+var serverSet = wire.NewSet(
+	provideServerSettings,
+	provideHTTPServer,
+	handler.NewRPCMetrics,
+	provideIdentityVerifier,
+	provideAuthenticator,
+)
 
-// provideSocialGraphRepository 提供社交图谱仓储
+// followEventSet 关注事件消费 Provider
 //
-// 实际项目中：
+// 包含：
+// - FollowEventHandler（关注事件的处理：维护关注关系、清理推荐列表、归因）
+// - Consumer（消费者组读取关注事件，重试、死信）
+var followEventSet = wire.NewSet(
+	provideFollowEventHandler,
+	provideFollowEventConsumer,
+)
+
+// precomputeSet 推荐列表预计算 Provider
 //
-//	func provideSocialGraphRepository(db *gorm.DB) repository.SocialGraphRepository {
-//	    return persistence.NewMySQLSocialGraphRepository(db)
-//	}
-func provideSocialGraphRepository() repository.SocialGraphRepository {
-	// 示例：使用 mock 实现
-	return repository.NewMockSocialGraphRepository()
-}
+// 包含：
+// - PrecomputeJob（定期为最近活跃的用户生成推荐列表）
+var precomputeSet = wire.NewSet(
+	providePrecomputeJob,
+)
 
-// provideContentRepository 提供内容仓储
+// retentionJobSet 定时任务 Provider
 //
-// 实际项目中：
+// 包含：
+// - RetentionEnforcer（数据保留期执行器）
+// - RetentionJob（定时清理任务）
+var retentionJobSet = wire.NewSet(
+	provideRetentionEnforcer,
+	provideRetentionJob,
+)
+
+// reencryptionJobSet 重新加密任务 Provider
 //
-//	func provideContentRepository(db *gorm.DB) repository.ContentRepository {
-//	    return persistence.NewMySQLContentRepository(db)
-//	}
-func provideContentRepository() repository.ContentRepository {
-	// 示例：使用 mock 实现
-	return repository.NewMockContentRepository()
-}
+// 包含：
+// - ReencryptionJob（密钥轮换后把加密字段换成当前密钥，没有开启加密时为 nil）
+var reencryptionJobSet = wire.NewSet(
+	provideReencryptionJob,
+)
 
 // Injector 函数定义
 //
@@ -244,15 +328,77 @@ func provideContentRepository() repository.ContentRepository {
 // 基础设施（RPC 客户端、数据库等）
 //
 // Wire 会自动解决这个依赖链！
-func InitializeRecommendationHandler() *handler.RecommendationHandler {
+func InitializeRecommendationHandler(closers *lifecycle.Closers) *handler.RecommendationHandler {
 	// 这个函数体会被 Wire 忽略
 	// Wire 会生成真实的实现到 wire_gen.go
 	wire.Build(
+		configSet,
+		infrastructureSet,
+		repositorySet,
+		domainServiceSet,
+		applicationServiceSet,
+		handlerSet,
+	)
+	return nil // 占位返回
+}
+
+// InitializeRecommendationServer 初始化推荐 Handler、启动预热、HTTP 接口、管理接口和后台任务（关注事件的消费者、预计算）
+//
+// 和 InitializeRecommendationHandler 的依赖图相同，多了预热、HTTP 接口、管理接口和后台任务：
+// Wire 的每个 Injector 各自构造一份对象，它们必须和 Handler 在同一个 Injector 里。
+func InitializeRecommendationServer(closers *lifecycle.Closers) *recommendationServer {
+	wire.Build(
+		configSet,
 		infrastructureSet,
 		repositorySet,
 		domainServiceSet,
 		applicationServiceSet,
 		handlerSet,
+		adminHandlerSet,
+		warmupSet,
+		serverSet,
+		followEventSet,
+		precomputeSet,
+		wire.Struct(new(recommendationServer), "*"),
+	)
+	return nil // 占位返回
+}
+
+// InitializeTenantResolver 初始化租户识别中间件
+//
+// 中间件在创建 Kitex Server 时使用，不属于 Handler 的依赖，单独一个 Injector。
+func InitializeTenantResolver() *handler.TenantResolver {
+	wire.Build(
+		provideConfig,
+		provideTenants,
+		handler.NewTenantResolver,
+	)
+	return nil // 占位返回
+}
+
+// InitializeRetentionJob 初始化数据保留期清理任务
+//
+// 注意：dev 环境的仓储是内存实现，每个 Injector 各自持有一份数据；
+// staging、prod 环境的仓储连接同一个数据库，清理任务删除的就是线上写入的数据。
+func InitializeRetentionJob(closers *lifecycle.Closers) *job.RetentionJob {
+	wire.Build(
+		configSet,
+		provideRedactor,
+		provideLogger,
+		repositorySet,
+		retentionJobSet,
+	)
+	return nil // 占位返回
+}
+
+// InitializeReencryptionJob 初始化重新加密任务（没有开启加密时返回 nil）
+func InitializeReencryptionJob(closers *lifecycle.Closers) *job.ReencryptionJob {
+	wire.Build(
+		configSet,
+		provideRedactor,
+		provideLogger,
+		repositorySet,
+		reencryptionJobSet,
 	)
 	return nil // 占位返回
 }